@@ -0,0 +1,78 @@
+package apperrors
+
+import "errors"
+
+// Kind classifies an application error so transports can map it consistently
+type Kind string
+
+const (
+	KindValidation  Kind = "validation"
+	KindNotFound    Kind = "not_found"
+	KindForbidden   Kind = "forbidden"
+	KindConflict    Kind = "conflict"
+	KindQuota       Kind = "quota_exceeded"
+	KindUnavailable Kind = "unavailable"
+)
+
+// Error is a classified application error wrapping its underlying cause
+type Error struct {
+	Kind   Kind
+	Reason string // specific machine-readable identifier, e.g. task_not_found
+	Err    error
+}
+
+// Error returns the underlying error message
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validation wraps an error caused by invalid input
+func Validation(err error) error {
+	return &Error{Kind: KindValidation, Reason: "validation_error", Err: err}
+}
+
+// NotFound wraps an error for a resource that does not exist
+func NotFound(reason string, err error) error {
+	return &Error{Kind: KindNotFound, Reason: reason, Err: err}
+}
+
+// Forbidden wraps an error for an operation the caller may not perform
+func Forbidden(reason string, err error) error {
+	return &Error{Kind: KindForbidden, Reason: reason, Err: err}
+}
+
+// Conflict wraps an error for a request that conflicts with existing state
+func Conflict(reason string, err error) error {
+	return &Error{Kind: KindConflict, Reason: reason, Err: err}
+}
+
+// QuotaExceeded wraps an error for a request that would exceed a usage limit
+func QuotaExceeded(reason string, err error) error {
+	return &Error{Kind: KindQuota, Reason: reason, Err: err}
+}
+
+// Unavailable wraps an error for an operation this deployment cannot perform,
+// such as one that needs a service that is not configured
+func Unavailable(reason string, err error) error {
+	return &Error{Kind: KindUnavailable, Reason: reason, Err: err}
+}
+
+// As returns the classified error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// IsKind checks if err carries a classified error of the given kind
+func IsKind(err error, kind Kind) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == kind
+}
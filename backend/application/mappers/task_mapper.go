@@ -16,7 +16,7 @@ type TaskMapper struct{}
 func (m *TaskMapper) ToEntity(dto *dtos.Task) (*entities.Task, error) {
 	// Validate and create TaskID
 	taskID := valueobjects.NewTaskID(dto.ID)
-	if taskID.IsZero() && dto.ID != 0 {
+	if taskID.IsZero() {
 		return nil, fmt.Errorf("task ID cannot be zero")
 	}
 
@@ -26,22 +26,32 @@ func (m *TaskMapper) ToEntity(dto *dtos.Task) (*entities.Task, error) {
 		return nil, fmt.Errorf("invalid title: %w", err)
 	}
 
-	// Create empty TaskDescription (not in DTO)
-	description, err := valueobjects.NewTaskDescription("")
+	// Validate and create TaskDescription
+	description, err := valueobjects.NewTaskDescription(dto.Description)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create description: %w", err)
+		return nil, fmt.Errorf("invalid description: %w", err)
 	}
 
-	// Convert completed boolean to TaskStatus
+	// Archived is only recorded in the status column; otherwise the completed
+	// flag wins so rows written before the column existed map correctly
 	var status valueobjects.TaskStatus
-	if dto.Completed {
+	switch {
+	case dto.Status == valueobjects.NewArchivedStatus().Value():
+		status = valueobjects.NewArchivedStatus()
+	case dto.Completed:
 		status = valueobjects.NewCompletedStatus()
-	} else {
+	default:
 		status = valueobjects.NewPendingStatus()
 	}
 
-	// Create default TaskPriority (medium)
+	// Validate and create TaskPriority, defaulting to medium for legacy rows
 	priority := valueobjects.NewMediumPriority()
+	if dto.Priority != "" {
+		priority, err = valueobjects.NewTaskPriority(dto.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority: %w", err)
+		}
+	}
 
 	// Create UserID value object from DTO
 	ownerID := uservo.NewUserID(dto.UserID)
@@ -55,17 +65,81 @@ func (m *TaskMapper) ToEntity(dto *dtos.Task) (*entities.Task, error) {
 		return nil, fmt.Errorf("failed to create task entity: %w", err)
 	}
 
+	// Restore tags when they were preloaded
+	if len(dto.Tags) > 0 {
+		tags := make([]valueobjects.TagName, 0, len(dto.Tags))
+		for _, tagDTO := range dto.Tags {
+			tag, err := valueobjects.NewTagName(tagDTO.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag: %w", err)
+			}
+			tags = append(tags, tag)
+		}
+		task.LoadTags(tags)
+	}
+	task.LoadDueDate(dto.DueDate)
+	task.LoadRemindAt(dto.RemindAt)
+	if dto.DeletedAt.Valid {
+		deletedAt := dto.DeletedAt.Time
+		task.LoadDeletedAt(&deletedAt)
+	}
+	// DTOs that were never saved have no timestamps; keep the entity's
+	if !dto.CreatedAt.IsZero() && !dto.UpdatedAt.IsZero() {
+		task.LoadTimestamps(dto.CreatedAt, dto.UpdatedAt)
+	}
+	if dto.Version > 0 {
+		task.LoadVersion(dto.Version)
+	}
+
 	return task, nil
 }
 
 // ToDTO converts a Task entity to a TaskDTO
 func (m *TaskMapper) ToDTO(entity *entities.Task) *dtos.Task {
 	return &dtos.Task{
-		ID:        entity.ID().Value(),
-		Title:     entity.Title().Value(),
-		Completed: entity.Status().IsCompleted(), // Convert TaskStatus to boolean
-		UserID:    entity.UserID().Value(),       // Include UserID for database
+		ID:          entity.ID().Value(),
+		Title:       entity.Title().Value(),
+		Description: entity.Description().Value(),
+		Priority:    entity.Priority().Value(),
+		Status:      entity.Status().Value(),
+		Completed:   entity.Status().IsCompleted(), // Convert TaskStatus to boolean
+		DueDate:     entity.DueDate(),
+		RemindAt:    entity.RemindAt(),
+		UserID:      entity.UserID().Value(), // Include UserID for database
+		CreatedAt:   entity.CreatedAt(),
+		UpdatedAt:   entity.UpdatedAt(),
+		Version:     entity.Version(),
+	}
+}
+
+// ActivityToEntity converts a TaskActivity DTO to an entity
+func (m *TaskMapper) ActivityToEntity(dto *dtos.TaskActivity) (*entities.TaskActivity, error) {
+	activity, err := entities.NewTaskActivity(
+		valueobjects.NewTaskID(dto.TaskID),
+		uservo.NewUserID(dto.UserID),
+		entities.TaskActivityAction(dto.Action),
+		dto.Field,
+		dto.OldValue,
+		dto.NewValue,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task activity: %w", err)
+	}
+
+	activity.LoadPersisted(dto.ID, dto.CreatedAt)
+	return activity, nil
+}
+
+// ActivityToDTO converts a TaskActivity entity to a DTO
+func (m *TaskMapper) ActivityToDTO(entity *entities.TaskActivity) *dtos.TaskActivity {
+	return &dtos.TaskActivity{
+		ID:        entity.ID(),
+		TaskID:    entity.TaskID().Value(),
+		UserID:    entity.UserID().Value(),
+		Action:    string(entity.Action()),
+		Field:     entity.Field(),
+		OldValue:  entity.OldValue(),
+		NewValue:  entity.NewValue(),
 		CreatedAt: entity.CreatedAt(),
-		UpdatedAt: entity.UpdatedAt(),
 	}
 }
@@ -33,6 +33,18 @@ func TestTaskMapper_ToEntity_ValidDTO(t *testing.T) {
 	assert.Equal(t, uint(1), entity.UserID().Value())
 }
 
+func TestTaskMapper_ToEntity_RestoresTimestamps(t *testing.T) {
+	mapper := &TaskMapper{}
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(2 * time.Hour)
+
+	entity, err := mapper.ToEntity(&dtos.Task{ID: 1, Title: "Old task", UserID: 1, CreatedAt: createdAt, UpdatedAt: updatedAt})
+
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(entity.CreatedAt()))
+	assert.True(t, updatedAt.Equal(entity.UpdatedAt()))
+}
+
 func TestTaskMapper_ToEntity_CompletedTask(t *testing.T) {
 	mapper := &TaskMapper{}
 
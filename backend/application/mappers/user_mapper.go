@@ -28,8 +28,8 @@ func (m *UserMapper) ToEntity(dto *dtos.User) (*entities.User, error) {
 
 	// Create UserProfile from Name field
 	// The DTO has a single Name field, but UserProfile expects firstName, lastName, timezone
-	// We'll split the name and use a default timezone
-	profile, err := m.createUserProfileFromName(dto.Name)
+	// We'll split the name
+	profile, err := m.createUserProfileFromName(dto.Name, dto.Timezone)
 	if err != nil {
 		return nil, fmt.Errorf("invalid profile: %w", err)
 	}
@@ -43,26 +43,43 @@ func (m *UserMapper) ToEntity(dto *dtos.User) (*entities.User, error) {
 		return nil, fmt.Errorf("failed to create user entity: %w", err)
 	}
 
+	var token valueobjects.VerificationToken
+	if dto.VerificationToken != nil && dto.VerificationTokenExpiresAt != nil {
+		token = valueobjects.RestoreVerificationToken(*dto.VerificationToken, *dto.VerificationTokenExpiresAt)
+	}
+	user.LoadCredentials(dto.PasswordHash, dto.HasVerifiedEmail(), token)
+
 	return user, nil
 }
 
 // ToDTO converts a User entity to a UserDTO
 func (m *UserMapper) ToDTO(entity *entities.User) *dtos.User {
-	return &dtos.User{
-		ID:         entity.ID().Value(),
-		Email:      entity.Email().Value(),
-		Name:       entity.Profile().DisplayName(), // Get full name from profile
-		AuthMethod: "password",                      // Default value (auth-related fields managed by Auth domain)
-		IsActive:   true,                            // Default value
-		CreatedAt:  entity.CreatedAt(),
-		UpdatedAt:  entity.UpdatedAt(),
+	dto := &dtos.User{
+		ID:            entity.ID().Value(),
+		Email:         entity.Email().Value(),
+		Name:          entity.Profile().DisplayName(), // Get full name from profile
+		Timezone:      entity.Profile().Timezone(),
+		PasswordHash:  entity.PasswordHash(),
+		AuthMethod:    "password", // Default value (auth-related fields managed by Auth domain)
+		IsActive:      true,       // Default value
+		EmailVerified: entity.IsEmailVerified(),
+		CreatedAt:     entity.CreatedAt(),
+		UpdatedAt:     entity.UpdatedAt(),
+	}
+
+	if token := entity.VerificationToken(); !token.IsZero() {
+		value, expiresAt := token.Value(), token.ExpiresAt()
+		dto.VerificationToken = &value
+		dto.VerificationTokenExpiresAt = &expiresAt
 	}
+
+	return dto
 }
 
 // createUserProfileFromName creates a UserProfile from a single name string
 // This is a helper method to handle the mismatch between DTO (single Name field)
 // and UserProfile (firstName, lastName, timezone)
-func (m *UserMapper) createUserProfileFromName(name string) (valueobjects.UserProfile, error) {
+func (m *UserMapper) createUserProfileFromName(name, timezone string) (valueobjects.UserProfile, error) {
 	if name == "" {
 		return valueobjects.UserProfile{}, fmt.Errorf("name cannot be empty")
 	}
@@ -84,8 +101,11 @@ func (m *UserMapper) createUserProfileFromName(name string) (valueobjects.UserPr
 		lastName = strings.Join(parts[1:], " ")
 	}
 
-	// Use UTC as default timezone
-	timezone := "UTC"
+	// Rows saved before timezones were stored, or with a zone the timezone
+	// database no longer knows, fall back to UTC
+	if _, err := valueobjects.LoadTimezone(timezone); err != nil {
+		timezone = valueobjects.DefaultTimezone
+	}
 
 	return valueobjects.NewUserProfile(firstName, lastName, timezone)
 }
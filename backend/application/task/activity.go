@@ -0,0 +1,126 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"domain/task/entities"
+	uservo "domain/user/valueobjects"
+)
+
+// taskActivityFields are the task fields whose changes are recorded, in the
+// order their entries are written. Values are nil when a field is empty.
+var taskActivityFields = []struct {
+	name  string
+	value func(task *entities.Task) *string
+}{
+	{"title", func(task *entities.Task) *string {
+		return optionalString(task.Title().Value())
+	}},
+	{"description", func(task *entities.Task) *string {
+		return optionalString(task.Description().Value())
+	}},
+	{"status", func(task *entities.Task) *string {
+		return optionalString(task.Status().Value())
+	}},
+	{"priority", func(task *entities.Task) *string {
+		return optionalString(task.Priority().Value())
+	}},
+	{"tags", func(task *entities.Task) *string {
+		if len(task.Tags()) == 0 {
+			return nil
+		}
+		names := make([]string, 0, len(task.Tags()))
+		for _, tag := range task.Tags() {
+			names = append(names, tag.Value())
+		}
+		sort.Strings(names)
+		encoded, _ := json.Marshal(names)
+		return optionalString(string(encoded))
+	}},
+	{"due_date", func(task *entities.Task) *string {
+		if task.DueDate() == nil {
+			return nil
+		}
+		return optionalString(task.DueDate().UTC().Format(time.RFC3339))
+	}},
+	{"remind_at", func(task *entities.Task) *string {
+		if task.RemindAt() == nil {
+			return nil
+		}
+		return optionalString(task.RemindAt().UTC().Format(time.RFC3339))
+	}},
+}
+
+// taskSnapshot holds a task's recorded field values, taken before a change so
+// the change can be diffed
+type taskSnapshot map[string]*string
+
+// snapshotTask records the current values of a task's recorded fields
+func snapshotTask(task *entities.Task) taskSnapshot {
+	snapshot := make(taskSnapshot, len(taskActivityFields))
+	for _, field := range taskActivityFields {
+		snapshot[field.name] = field.value(task)
+	}
+	return snapshot
+}
+
+// diffTaskActivity returns an entry for each recorded field whose value
+// differs between before and task. Status changes are recorded as completing,
+// archiving or reopening the task; other changes as updates.
+func diffTaskActivity(before taskSnapshot, task *entities.Task, userID uservo.UserID) ([]*entities.TaskActivity, error) {
+	var activities []*entities.TaskActivity
+	for _, field := range taskActivityFields {
+		oldValue, newValue := before[field.name], field.value(task)
+		if equalOptionalStrings(oldValue, newValue) {
+			continue
+		}
+
+		action := entities.TaskActivityUpdated
+		if field.name == "status" {
+			switch {
+			case task.Status().IsCompleted():
+				action = entities.TaskActivityCompleted
+			case task.Status().IsArchived():
+				action = entities.TaskActivityArchived
+			default:
+				action = entities.TaskActivityReopened
+			}
+		}
+
+		activity, err := entities.NewTaskActivity(task.ID(), userID, action, field.name, oldValue, newValue)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, nil
+}
+
+// recordTaskAction stores a history entry for an action on a whole task, such
+// as creating or deleting it
+func (s *taskApplicationService) recordTaskAction(ctx context.Context, task *entities.Task, userID uservo.UserID, action entities.TaskActivityAction) error {
+	activity, err := entities.NewTaskActivity(task.ID(), userID, action, "", nil, nil)
+	if err != nil {
+		return err
+	}
+	return s.taskRepo.RecordActivity(ctx, []*entities.TaskActivity{activity})
+}
+
+// optionalString returns a pointer to value, or nil when it is empty
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// equalOptionalStrings reports whether a and b are both nil or hold the same value
+func equalOptionalStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
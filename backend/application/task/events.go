@@ -0,0 +1,42 @@
+package task
+
+import "domain/task/entities"
+
+// EventPublisher dispatches domain events once the changes that recorded them
+// have been saved
+type EventPublisher interface {
+	Publish(events []entities.DomainEvent) error
+}
+
+// NoopEventPublisher discards every event
+type NoopEventPublisher struct{}
+
+// Publish ignores events
+func (NoopEventPublisher) Publish(events []entities.DomainEvent) error {
+	return nil
+}
+
+// Kinds of TaskChange published on the EventBus
+const (
+	TaskCreated = "task.created"
+	TaskUpdated = "task.updated"
+	TaskDeleted = "task.deleted"
+)
+
+// TaskChange reports a task that was created, updated or deleted
+type TaskChange struct {
+	Type string // TaskCreated, TaskUpdated or TaskDeleted
+	Task *entities.Task
+}
+
+// EventBus tells listeners, such as the owner's open websocket connections,
+// about task changes once they are saved. Publishing must not block.
+type EventBus interface {
+	PublishTaskChange(change TaskChange)
+}
+
+// NoopEventBus discards every task change
+type NoopEventBus struct{}
+
+// PublishTaskChange ignores change
+func (NoopEventBus) PublishTaskChange(change TaskChange) {}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"domain/task/entities"
+)
+
+// EventSubscriber reacts to published domain events, e.g. by writing the audit
+// log or notifying the owner's open connections. It runs on the publishing
+// request's goroutine, so it must not block for long.
+type EventSubscriber interface {
+	HandleDomainEvent(event entities.DomainEvent)
+}
+
+// EventSubscriberFunc adapts a function to an EventSubscriber
+type EventSubscriberFunc func(event entities.DomainEvent)
+
+// HandleDomainEvent calls f(event)
+func (f EventSubscriberFunc) HandleDomainEvent(event entities.DomainEvent) {
+	f(event)
+}
+
+// SyncEventPublisher is an in-process EventPublisher that hands each event to
+// every subscriber in turn, in the order the events were recorded and the
+// subscribers were added. A subscriber that panics is skipped for that event
+// without affecting the others.
+type SyncEventPublisher struct {
+	subscribers []EventSubscriber
+}
+
+// NewSyncEventPublisher creates a publisher with the given subscribers. It is
+// not safe to add subscribers once events are being published.
+func NewSyncEventPublisher(subscribers ...EventSubscriber) *SyncEventPublisher {
+	return &SyncEventPublisher{subscribers: subscribers}
+}
+
+// Subscribe adds a subscriber after the existing ones
+func (p *SyncEventPublisher) Subscribe(subscriber EventSubscriber) {
+	p.subscribers = append(p.subscribers, subscriber)
+}
+
+// Publish delivers events to every subscriber, returning the panics recovered
+// from subscribers as errors
+func (p *SyncEventPublisher) Publish(events []entities.DomainEvent) error {
+	var errs []error
+	for _, event := range events {
+		for _, subscriber := range p.subscribers {
+			if err := deliverEvent(subscriber, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverEvent hands event to subscriber, turning a panic into an error
+func deliverEvent(subscriber EventSubscriber, event entities.DomainEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Event subscriber %T panicked on %s: %v", subscriber, event.EventName(), recovered)
+			err = fmt.Errorf("subscriber %T panicked on %s: %v", subscriber, event.EventName(), recovered)
+		}
+	}()
+
+	subscriber.HandleDomainEvent(event)
+	return nil
+}
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"domain/task/entities"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncEventPublisher_DeliversInOrder(t *testing.T) {
+	var delivered []string
+	subscriber := func(name string) EventSubscriber {
+		return EventSubscriberFunc(func(event entities.DomainEvent) {
+			delivered = append(delivered, name+" "+event.EventName())
+		})
+	}
+	publisher := NewSyncEventPublisher(subscriber("first"))
+	publisher.Subscribe(subscriber("second"))
+
+	now := time.Now()
+	require.NoError(t, publisher.Publish([]entities.DomainEvent{
+		entities.TaskCompleted{TaskID: valueobjects.NewTaskID(1), UserID: uservo.NewUserID(1), CompletedAt: now},
+		entities.TaskArchived{TaskID: valueobjects.NewTaskID(1), UserID: uservo.NewUserID(1), ArchivedAt: now},
+	}))
+
+	assert.Equal(t, []string{
+		"first task.completed",
+		"second task.completed",
+		"first task.archived",
+		"second task.archived",
+	}, delivered)
+}
+
+func TestSyncEventPublisher_IsolatesPanickingSubscribers(t *testing.T) {
+	var delivered []string
+	publisher := NewSyncEventPublisher(
+		EventSubscriberFunc(func(event entities.DomainEvent) {
+			if _, ok := event.(entities.TaskCompleted); ok {
+				panic("subscriber bug")
+			}
+		}),
+		EventSubscriberFunc(func(event entities.DomainEvent) {
+			delivered = append(delivered, event.EventName())
+		}),
+	)
+
+	now := time.Now()
+	err := publisher.Publish([]entities.DomainEvent{
+		entities.TaskCompleted{TaskID: valueobjects.NewTaskID(1), UserID: uservo.NewUserID(1), CompletedAt: now},
+		entities.TaskReopened{TaskID: valueobjects.NewTaskID(1), UserID: uservo.NewUserID(1), ReopenedAt: now},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriber bug")
+	assert.Equal(t, []string{"task.completed", "task.reopened"}, delivered)
+}
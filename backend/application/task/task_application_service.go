@@ -1,13 +1,21 @@
 package task
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
 
 	"domain/task/entities"
 	"domain/task/repositories"
 	"domain/task/services"
 	"domain/task/valueobjects"
 	uservo "domain/user/valueobjects"
+	"todo-app/application/apperrors"
+	"todo-app/application/unitofwork"
+	"todo-app/internal/logging"
 )
 
 // CreateTaskCommand represents a command to create a new task
@@ -15,6 +23,9 @@ type CreateTaskCommand struct {
 	Title       string
 	Description string
 	Priority    string
+	Tags        []string
+	DueDate     *time.Time
+	RemindAt    *time.Time // must be before DueDate when both are set
 	UserID      uint
 }
 
@@ -25,7 +36,48 @@ type UpdateTaskCommand struct {
 	Description *string
 	Status      *string
 	Priority    *string
+	Tags        *[]string // replaces the task's tags when set
+	DueDate     *time.Time
+	RemindAt    *time.Time
 	UserID      uint
+
+	// ClearDueDate removes the task's due date; it is ignored when DueDate is set
+	ClearDueDate bool
+
+	// ClearRemindAt removes the task's reminder; it is ignored when RemindAt is set
+	ClearRemindAt bool
+
+	// Restore allows an archived task to move back to pending
+	Restore bool
+
+	// Version, when set, is the version of the task the client last read; the
+	// update fails with a conflict if the task has been saved since
+	Version *int
+}
+
+// ImportTasksCommand represents a command to create many tasks at once
+type ImportTasksCommand struct {
+	UserID uint
+	Tasks  []CreateTaskCommand // the UserID of each is ignored in favour of the command's
+
+	// Atomic imports nothing unless every task is valid and fits the quota
+	Atomic bool
+}
+
+// TaskImportError describes why the task at Index of an import was not created
+type TaskImportError struct {
+	Index int
+	Err   error
+}
+
+// TaskImportResult reports the outcome of ImportTasks
+type TaskImportResult struct {
+	Imported []*entities.Task
+	Errors   []TaskImportError // ordered by Index
+
+	// LimitReached is set when the import stopped at the user's task quota;
+	// the tasks past it are reported in Errors
+	LimitReached bool
 }
 
 // TaskQuery represents a query for tasks
@@ -33,126 +85,466 @@ type TaskQuery struct {
 	UserID   uint
 	Status   *string
 	Priority *string
+	Tag      *string
+	Sort     string // created_at, updated_at, priority or title; empty keeps default order
+	Order    string // asc or desc; defaults to asc
+
+	// UpdatedSince limits results to tasks changed after it, including trashed ones
+	UpdatedSince *time.Time
+
+	// Limit and Offset select a page of the results; a zero Limit returns all
+	Limit  int
+	Offset int
+}
+
+// ErrTaskQuotaExceeded is returned by CreateTask when the user already has the
+// maximum number of tasks allowed by the TaskQuota
+var ErrTaskQuotaExceeded = errors.New("task quota exceeded")
+
+// TaskQuotaError reports that a user has no room left in the task quota;
+// errors.Is matches it against ErrTaskQuotaExceeded
+type TaskQuotaError struct {
+	Count int64 // tasks counted towards the quota
+	Limit int64
+
+	excludeFinished bool
+}
+
+func (e *TaskQuotaError) Error() string {
+	hint := "delete"
+	if e.excludeFinished {
+		hint = "complete, archive or delete"
+	}
+	return fmt.Sprintf("%s: you have reached the limit of %d tasks; %s existing tasks to create more",
+		ErrTaskQuotaExceeded, e.Limit, hint)
+}
+
+// Is makes errors.Is(err, ErrTaskQuotaExceeded) match any TaskQuotaError
+func (e *TaskQuotaError) Is(target error) bool {
+	return target == ErrTaskQuotaExceeded
+}
+
+// MaxDueSoonWindow is the furthest ahead GetTasksDueSoon may look
+const MaxDueSoonWindow = 30 * 24 * time.Hour
+
+// MaxImportTasks is the most tasks a single ImportTasks call may create
+const MaxImportTasks = 1000
+
+// TaskQuota limits how many tasks each user may have
+type TaskQuota struct {
+	// MaxTasks is the most tasks a user may have; 0 means unlimited
+	MaxTasks int64
+
+	// ExcludeFinished counts only pending tasks, so completed and archived
+	// tasks do not use up the quota. Trashed tasks are never counted.
+	ExcludeFinished bool
+
+	// Limits, when set, lets individual users have a MaxTasks of their own
+	Limits TaskLimits
+}
+
+// counts reports whether a task in status uses up the quota
+func (q TaskQuota) counts(status valueobjects.TaskStatus) bool {
+	return !q.ExcludeFinished || status.IsPending()
+}
+
+// TaskLimits supplies per-user overrides of TaskQuota.MaxTasks
+type TaskLimits interface {
+	// TaskLimit returns the user's own limit, 0 meaning unlimited; ok is false
+	// when the user has none and the quota's MaxTasks applies
+	TaskLimit(ctx context.Context, userID uservo.UserID) (limit int64, ok bool, err error)
 }
 
 // TaskApplicationService orchestrates task-related use cases
 type TaskApplicationService interface {
 	// CreateTask creates a new task
-	CreateTask(cmd CreateTaskCommand) (*entities.Task, error)
+	CreateTask(ctx context.Context, cmd CreateTaskCommand) (*entities.Task, error)
+
+	// ImportTasks creates several tasks in one transaction, reporting the
+	// tasks that are invalid or over the quota instead of failing the batch
+	ImportTasks(ctx context.Context, cmd ImportTasksCommand) (*TaskImportResult, error)
 
 	// UpdateTask updates an existing task
-	UpdateTask(cmd UpdateTaskCommand) (*entities.Task, error)
+	UpdateTask(ctx context.Context, cmd UpdateTaskCommand) (*entities.Task, error)
 
 	// GetTask retrieves a specific task
-	GetTask(taskID uint, userID uint) (*entities.Task, error)
+	GetTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error)
+
+	// GetUserTasks retrieves a page of a user's tasks with optional filtering,
+	// along with the number of tasks that match
+	GetUserTasks(ctx context.Context, query TaskQuery) ([]*entities.Task, int64, error)
 
-	// GetUserTasks retrieves tasks for a user with optional filtering
-	GetUserTasks(query TaskQuery) ([]*entities.Task, error)
+	// CountUserTasks counts a user's tasks, excluding trashed ones
+	CountUserTasks(ctx context.Context, userID uint) (int64, error)
 
-	// DeleteTask deletes a task
-	DeleteTask(taskID uint, userID uint) error
+	// GetTaskListVersion summarises a user's tasks cheaply, to tell whether
+	// their task list has changed
+	GetTaskListVersion(ctx context.Context, userID uint) (repositories.TaskListVersion, error)
+
+	// SearchTasks retrieves a user's tasks matching a text query
+	SearchTasks(ctx context.Context, userID uint, query string) ([]*entities.Task, error)
+
+	// GetTaskStats summarises a user's tasks by status and priority
+	GetTaskStats(ctx context.Context, userID uint) (repositories.TaskStats, error)
+
+	// GetTaskPage retrieves up to limit of a user's tasks with IDs above
+	// afterID, in ID order, for reading all of them a page at a time
+	GetTaskPage(ctx context.Context, userID uint, afterID uint, limit int) ([]*entities.Task, error)
+
+	// GetOverdueTasks retrieves a user's pending tasks whose due date has passed
+	GetOverdueTasks(ctx context.Context, userID uint) ([]*entities.Task, error)
+
+	// GetTasksDueSoon retrieves a user's pending tasks due within the given
+	// window from now, which must be positive and at most MaxDueSoonWindow
+	GetTasksDueSoon(ctx context.Context, userID uint, within time.Duration) ([]*entities.Task, error)
+
+	// DeleteTask moves a task to the trash
+	DeleteTask(ctx context.Context, taskID uint, userID uint) error
+
+	// GetTrashedTasks retrieves a user's trashed tasks
+	GetTrashedTasks(ctx context.Context, userID uint) ([]*entities.Task, error)
+
+	// RestoreTask moves a task out of the trash
+	RestoreTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error)
+
+	// PermanentlyDeleteTask removes a task for good, whether or not it is trashed
+	PermanentlyDeleteTask(ctx context.Context, taskID uint, userID uint) error
 
 	// CompleteTask marks a task as completed
-	CompleteTask(taskID uint, userID uint) (*entities.Task, error)
+	CompleteTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error)
 
 	// ArchiveTask archives a task
-	ArchiveTask(taskID uint, userID uint) (*entities.Task, error)
+	ArchiveTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error)
+
+	// BulkUpdateStatus sets the status of several tasks atomically
+	BulkUpdateStatus(ctx context.Context, userID uint, taskIDs []uint, status string) ([]*entities.Task, error)
+
+	// GetTaskActivity retrieves a page of the history of a live or trashed
+	// task, newest first, along with the total number of entries
+	GetTaskActivity(ctx context.Context, taskID uint, userID uint, limit, offset int) ([]*entities.TaskActivity, int64, error)
 }
 
 // taskApplicationService implements TaskApplicationService
 type taskApplicationService struct {
 	taskRepo           repositories.TaskRepository
+	uow                unitofwork.UnitOfWork
 	validationService  services.TaskValidationService
 	searchService      services.TaskSearchService
+	dueDateBounds      valueobjects.DueDateBounds
+	quota              TaskQuota
+	eventPublisher     EventPublisher
+	eventBus           EventBus
 }
 
 // NewTaskApplicationService creates a new task application service
 func NewTaskApplicationService(
 	taskRepo repositories.TaskRepository,
+	uow unitofwork.UnitOfWork,
 	validationService services.TaskValidationService,
 	searchService services.TaskSearchService,
+	dueDateBounds valueobjects.DueDateBounds,
+	quota TaskQuota,
+	eventPublisher EventPublisher,
+	eventBus EventBus,
 ) TaskApplicationService {
 	return &taskApplicationService{
 		taskRepo:          taskRepo,
+		uow:               uow,
 		validationService: validationService,
 		searchService:     searchService,
+		dueDateBounds:     dueDateBounds,
+		quota:             quota,
+		eventPublisher:    eventPublisher,
+		eventBus:          eventBus,
 	}
 }
 
 // CreateTask creates a new task with validation
-func (s *taskApplicationService) CreateTask(cmd CreateTaskCommand) (*entities.Task, error) {
+func (s *taskApplicationService) CreateTask(ctx context.Context, cmd CreateTaskCommand) (*entities.Task, error) {
+	task, tags, err := s.newTask(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the task and its tags in the transaction that checked the quota,
+	// which holds the user's quota lock until the task is saved
+	err = s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		if err := tx.checkQuota(ctx, task.UserID()); err != nil {
+			return err
+		}
+
+		if err := tx.taskRepo.Save(ctx, task); err != nil {
+			return err
+		}
+
+		if len(tags) > 0 {
+			if err := tx.attachTags(ctx, task, tags); err != nil {
+				return err
+			}
+		}
+		return tx.recordTaskAction(ctx, task, task.UserID(), entities.TaskActivityCreated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishChange(TaskCreated, task)
+	return task, nil
+}
+
+// newTask validates a create command and builds the pending task it describes,
+// along with the tags to attach once it is saved
+func (s *taskApplicationService) newTask(cmd CreateTaskCommand) (*entities.Task, []valueobjects.TagName, error) {
 	// Create value objects
 	title, err := valueobjects.NewTaskTitle(cmd.Title)
 	if err != nil {
-		return nil, err
+		return nil, nil, apperrors.Validation(err)
 	}
 
 	description, err := valueobjects.NewTaskDescription(cmd.Description)
 	if err != nil {
-		return nil, err
+		return nil, nil, apperrors.Validation(err)
 	}
 
 	priority, err := valueobjects.NewTaskPriority(cmd.Priority)
 	if err != nil {
-		return nil, err
+		return nil, nil, apperrors.Validation(err)
 	}
 
 	userID := uservo.NewUserID(cmd.UserID)
 
+	tags, err := valueobjects.NewTagNames(cmd.Tags)
+	if err != nil {
+		return nil, nil, apperrors.Validation(err)
+	}
+
 	// Validate task creation
 	if err := s.validationService.ValidateTaskCreation(title, userID); err != nil {
-		return nil, err
+		return nil, nil, apperrors.Validation(err)
+	}
+
+	var dueDate *valueobjects.DueDate
+	if cmd.DueDate != nil {
+		parsed, err := s.newDueDate(*cmd.DueDate)
+		if err != nil {
+			return nil, nil, err
+		}
+		dueDate = &parsed
 	}
 
 	// Create pending status for new tasks
 	status := valueobjects.NewPendingStatus()
 
-	// Generate new task ID (in real implementation, this would come from repository)
-	taskID := valueobjects.NewTaskID(0) // Repository will assign actual ID
+	// New tasks have no ID until the repository assigns one on Save
+	taskID := valueobjects.NewTaskID(0)
 
 	// Create the task entity
 	task, err := entities.NewTask(taskID, title, description, status, priority, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dueDate != nil {
+		if err := task.SetDueDate(*dueDate); err != nil {
+			return nil, nil, apperrors.Validation(err)
+		}
+	}
+
+	if cmd.RemindAt != nil {
+		if err := task.SetRemindAt(*cmd.RemindAt); err != nil {
+			return nil, nil, apperrors.Validation(err)
+		}
+	}
+	if err := checkReminder(task); err != nil {
+		return nil, nil, err
+	}
+
+	// Setting up a new task is not an update of it; its creation is published
+	// as a TaskCreated change once it is saved
+	task.PullEvents()
+
+	return task, tags, nil
+}
+
+// ImportTasks validates every task first, then saves the valid ones and their
+// tags in one transaction. Tasks beyond the user's quota are reported like
+// invalid ones; in atomic mode any such failure means nothing is saved.
+func (s *taskApplicationService) ImportTasks(ctx context.Context, cmd ImportTasksCommand) (*TaskImportResult, error) {
+	if len(cmd.Tasks) > MaxImportTasks {
+		return nil, apperrors.Validation(fmt.Errorf("an import may contain at most %d tasks", MaxImportTasks))
+	}
+
+	type importedTask struct {
+		index int
+		task  *entities.Task
+		tags  []valueobjects.TagName
+	}
+
+	result := &TaskImportResult{Imported: []*entities.Task{}, Errors: []TaskImportError{}}
+	valid := make([]importedTask, 0, len(cmd.Tasks))
+	for i, row := range cmd.Tasks {
+		row.UserID = cmd.UserID
+		task, tags, err := s.newTask(row)
+		if err != nil {
+			result.Errors = append(result.Errors, TaskImportError{Index: i, Err: err})
+			continue
+		}
+		valid = append(valid, importedTask{index: i, task: task, tags: tags})
+	}
+	if cmd.Atomic && len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	userID := uservo.NewUserID(cmd.UserID)
+	err := s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		// The import stops at the quota; the tasks past it are reported
+		count, limit, err := tx.quotaUsage(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && count+int64(len(valid)) > limit {
+			remaining := max(limit-count, 0)
+			result.LimitReached = true
+			for _, rejected := range valid[remaining:] {
+				result.Errors = append(result.Errors, TaskImportError{Index: rejected.index, Err: tx.errQuotaExceeded(count, limit)})
+			}
+			if cmd.Atomic {
+				return nil
+			}
+			valid = valid[:remaining]
+		}
+
+		for _, row := range valid {
+			if err := tx.taskRepo.Save(ctx, row.task); err != nil {
+				return err
+			}
+			if len(row.tags) > 0 {
+				if err := tx.attachTags(ctx, row.task, row.tags); err != nil {
+					return err
+				}
+			}
+			if err := tx.recordTaskAction(ctx, row.task, userID, entities.TaskActivityCreated); err != nil {
+				return err
+			}
+			result.Imported = append(result.Imported, row.task)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Save the task
-	if err := s.taskRepo.Save(task); err != nil {
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Index < result.Errors[j].Index
+	})
+	s.publishChange(TaskCreated, result.Imported...)
+	return result, nil
+}
+
+// checkQuota rejects giving the user one more counted task once they have
+// reached the task quota
+func (s *taskApplicationService) checkQuota(ctx context.Context, userID uservo.UserID) error {
+	count, limit, err := s.quotaUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && count >= limit {
+		return s.errQuotaExceeded(count, limit)
+	}
+	return nil
+}
+
+// quotaUsage returns how many of the user's tasks count towards the quota and
+// the user's limit; limit is 0 when there is no quota. It takes the user's
+// quota lock, so it belongs inside inTransaction.
+func (s *taskApplicationService) quotaUsage(ctx context.Context, userID uservo.UserID) (count, limit int64, err error) {
+	limit = s.quota.MaxTasks
+	if s.quota.Limits != nil {
+		own, ok, err := s.quota.Limits.TaskLimit(ctx, userID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if ok {
+			limit = own
+		}
+	}
+	if limit <= 0 {
+		return 0, 0, nil
+	}
+
+	// Concurrent requests for the user wait here until this transaction ends,
+	// so each one counts the tasks the others added
+	if err := s.taskRepo.LockUserTasks(ctx, userID); err != nil {
+		return 0, 0, err
+	}
+	if s.quota.ExcludeFinished {
+		count, err = s.taskRepo.CountByUserIDAndStatus(ctx, userID, valueobjects.NewPendingStatus())
+	} else {
+		count, err = s.taskRepo.CountByUserID(ctx, userID)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, limit, nil
+}
+
+// errQuotaExceeded reports a task that would take the user past the quota
+func (s *taskApplicationService) errQuotaExceeded(count, limit int64) error {
+	return apperrors.QuotaExceeded("task_quota_exceeded", &TaskQuotaError{
+		Count:           count,
+		Limit:           limit,
+		excludeFinished: s.quota.ExcludeFinished,
+	})
+}
+
+// UpdateTask updates an existing task with validation; the task and its tag
+// changes are persisted in one transaction
+func (s *taskApplicationService) UpdateTask(ctx context.Context, cmd UpdateTaskCommand) (*entities.Task, error) {
+	var task *entities.Task
+	err := s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		var err error
+		task, err = tx.updateTask(ctx, cmd)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	// The task itself reaches clients before what happened to it
+	s.publishChange(TaskUpdated, task)
+	s.publishEvents(ctx, task)
 	return task, nil
 }
 
-// UpdateTask updates an existing task with validation
-func (s *taskApplicationService) UpdateTask(cmd UpdateTaskCommand) (*entities.Task, error) {
+// updateTask applies an update using the service's repositories
+func (s *taskApplicationService) updateTask(ctx context.Context, cmd UpdateTaskCommand) (*entities.Task, error) {
 	// Create task ID value object
 	taskID := valueobjects.NewTaskID(cmd.TaskID)
 
 	userID := uservo.NewUserID(cmd.UserID)
 
 	// Retrieve the existing task
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	if task == nil {
-		return nil, errors.New("task not found")
+	// Tasks owned by someone else are reported as missing so their existence is not revealed
+	if task == nil || !task.IsOwnedBy(userID) {
+		return nil, errTaskNotFound()
 	}
-
-	// Check ownership
-	if !task.IsOwnedBy(userID) {
-		return nil, errors.New("access denied: task does not belong to user")
+	if cmd.Version != nil && *cmd.Version != task.Version() {
+		return nil, errTaskVersionConflict(repositories.ErrTaskVersionConflict)
 	}
+	before := snapshotTask(task)
 
 	// Build updates for validation
-	updates := services.TaskUpdates{}
+	updates := services.TaskUpdates{Restore: cmd.Restore}
 
 	if cmd.Title != nil {
 		title, err := valueobjects.NewTaskTitle(*cmd.Title)
 		if err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 		updates.Title = &title
 	}
@@ -160,7 +552,7 @@ func (s *taskApplicationService) UpdateTask(cmd UpdateTaskCommand) (*entities.Ta
 	if cmd.Description != nil {
 		description, err := valueobjects.NewTaskDescription(*cmd.Description)
 		if err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 		updates.Description = &description
 	}
@@ -168,7 +560,7 @@ func (s *taskApplicationService) UpdateTask(cmd UpdateTaskCommand) (*entities.Ta
 	if cmd.Status != nil {
 		status, err := valueobjects.NewTaskStatus(*cmd.Status)
 		if err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 		updates.Status = &status
 	}
@@ -176,49 +568,113 @@ func (s *taskApplicationService) UpdateTask(cmd UpdateTaskCommand) (*entities.Ta
 	if cmd.Priority != nil {
 		priority, err := valueobjects.NewTaskPriority(*cmd.Priority)
 		if err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 		updates.Priority = &priority
 	}
 
+	var tags []valueobjects.TagName
+	if cmd.Tags != nil {
+		tags, err = valueobjects.NewTagNames(*cmd.Tags)
+		if err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	}
+
+	var dueDate *valueobjects.DueDate
+	if cmd.DueDate != nil {
+		parsed, err := s.newDueDate(*cmd.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		dueDate = &parsed
+	}
+
 	// Validate the updates
 	if err := s.validationService.ValidateTaskUpdate(task.Status(), updates); err != nil {
-		return nil, err
+		return nil, errInvalidTaskUpdate(err)
+	}
+
+	// Reopening a finished task uses up the quota again when those are left out
+	if updates.Status != nil && !s.quota.counts(task.Status()) && s.quota.counts(*updates.Status) {
+		if err := s.checkQuota(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Work out tag changes before the task's tags are replaced
+	previousTags := task.Tags()
+	if cmd.Tags != nil {
+		if err := task.SetTags(tags); err != nil {
+			return nil, apperrors.Validation(err)
+		}
 	}
 
 	// Apply the updates
 	if updates.Title != nil {
 		if err := task.UpdateTitle(*updates.Title); err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 	}
 
 	if updates.Description != nil {
 		if err := task.UpdateDescription(*updates.Description); err != nil {
-			return nil, err
+			return nil, apperrors.Validation(err)
 		}
 	}
 
 	if updates.Status != nil {
-		if updates.Status.IsCompleted() {
-			if err := task.MarkAsCompleted(); err != nil {
-				return nil, err
-			}
-		} else if updates.Status.IsArchived() {
-			if err := task.Archive(); err != nil {
-				return nil, err
-			}
+		if err := applyStatus(task, *updates.Status); err != nil {
+			return nil, apperrors.Validation(err)
 		}
 	}
 
 	if updates.Priority != nil {
 		if err := task.ChangePriority(*updates.Priority); err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	}
+
+	if dueDate != nil {
+		if err := task.SetDueDate(*dueDate); err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	} else if cmd.ClearDueDate {
+		if err := task.ClearDueDate(); err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	}
+
+	if cmd.RemindAt != nil {
+		if err := task.SetRemindAt(*cmd.RemindAt); err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	} else if cmd.ClearRemindAt {
+		if err := task.ClearRemindAt(); err != nil {
+			return nil, apperrors.Validation(err)
+		}
+	}
+	if err := checkReminder(task); err != nil {
+		return nil, err
+	}
+
+	// Save the updated task; it fails if another request saved it first
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, errTaskSave(err)
+	}
+
+	if cmd.Tags != nil {
+		if err := s.replaceTags(ctx, task, previousTags); err != nil {
 			return nil, err
 		}
 	}
 
-	// Save the updated task
-	if err := s.taskRepo.Update(task); err != nil {
+	// Record what changed, so reads and no-op updates leave no history
+	activities, err := diffTaskActivity(before, task, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.RecordActivity(ctx, activities); err != nil {
 		return nil, err
 	}
 
@@ -226,95 +682,527 @@ func (s *taskApplicationService) UpdateTask(cmd UpdateTaskCommand) (*entities.Ta
 }
 
 // GetTask retrieves a specific task with ownership validation
-func (s *taskApplicationService) GetTask(taskID uint, userID uint) (*entities.Task, error) {
+func (s *taskApplicationService) GetTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error) {
 	taskIDVO := valueobjects.NewTaskID(taskID)
 
 	userIDVO := uservo.NewUserID(userID)
 
-	task, err := s.taskRepo.FindByID(taskIDVO)
+	task, err := s.taskRepo.FindByID(ctx, taskIDVO)
 	if err != nil {
 		return nil, err
 	}
 
-	if task == nil {
-		return nil, errors.New("task not found")
-	}
-
-	// Check ownership
-	if !task.IsOwnedBy(userIDVO) {
-		return nil, errors.New("access denied: task does not belong to user")
+	// Tasks owned by someone else are reported as missing so their existence is not revealed
+	if task == nil || !task.IsOwnedBy(userIDVO) {
+		return nil, errTaskNotFound()
 	}
 
 	return task, nil
 }
 
-// GetUserTasks retrieves tasks for a user with optional filtering
-func (s *taskApplicationService) GetUserTasks(query TaskQuery) ([]*entities.Task, error) {
+// GetUserTasks retrieves a page of a user's tasks with optional filtering and
+// sorting; the filters, order and page are all applied by the repository
+func (s *taskApplicationService) GetUserTasks(ctx context.Context, query TaskQuery) ([]*entities.Task, int64, error) {
 	userID := uservo.NewUserID(query.UserID)
 
-	// If status filter is provided
+	sort, err := repositories.NewTaskSort(query.Sort, query.Order)
+	if err != nil {
+		return nil, 0, apperrors.Validation(err)
+	}
+
+	// Validate optional filters before querying
+	filter := repositories.TaskFilter{UpdatedSince: query.UpdatedSince}
 	if query.Status != nil {
-		status, err := valueobjects.NewTaskStatus(*query.Status)
+		parsed, err := valueobjects.NewTaskStatus(*query.Status)
 		if err != nil {
-			return nil, err
+			return nil, 0, apperrors.Validation(err)
 		}
-		return s.searchService.FindTasksByStatus(userID, status)
+		filter.Status = &parsed
 	}
 
-	// If priority filter is provided
 	if query.Priority != nil {
-		priority, err := valueobjects.NewTaskPriority(*query.Priority)
+		parsed, err := valueobjects.NewTaskPriority(*query.Priority)
+		if err != nil {
+			return nil, 0, apperrors.Validation(err)
+		}
+		filter.Priority = &parsed
+	}
+
+	if query.Tag != nil {
+		parsed, err := valueobjects.NewTagName(*query.Tag)
+		if err != nil {
+			return nil, 0, apperrors.Validation(err)
+		}
+		filter.Tag = &parsed
+	}
+
+	return s.taskRepo.FindFilteredByUserID(ctx, userID, filter, sort, query.Limit, query.Offset)
+}
+
+// inTransaction runs fn with a copy of the service whose repositories share one
+// transaction; transactions started through the copy join the outer one
+func (s *taskApplicationService) inTransaction(ctx context.Context, fn func(tx *taskApplicationService) error) error {
+	return s.uow.WithTransaction(ctx, func(repos unitofwork.Repositories) error {
+		return fn(&taskApplicationService{
+			taskRepo:          repos.Tasks,
+			uow:               unitofwork.Join(repos),
+			validationService: s.validationService,
+			searchService:     services.NewTaskSearchService(repos.Tasks),
+			dueDateBounds:     s.dueDateBounds,
+			quota:             s.quota,
+			eventPublisher:    s.eventPublisher,
+			eventBus:          s.eventBus,
+		})
+	})
+}
+
+// publishEvents dispatches the events recorded by saved tasks. The changes are
+// already committed, so a failed publish is logged rather than returned.
+func (s *taskApplicationService) publishEvents(ctx context.Context, tasks ...*entities.Task) {
+	var events []entities.DomainEvent
+	for _, task := range tasks {
+		events = append(events, task.PullEvents()...)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.eventPublisher.Publish(events); err != nil {
+		slog.ErrorContext(ctx, "task events not published",
+			"request_id", logging.RequestID(ctx),
+			"events", len(events),
+			"error", err,
+		)
+	}
+}
+
+// publishChange tells the event bus about saved task changes
+func (s *taskApplicationService) publishChange(changeType string, tasks ...*entities.Task) {
+	for _, task := range tasks {
+		s.eventBus.PublishTaskChange(TaskChange{Type: changeType, Task: task})
+	}
+}
+
+// attachTags persists tags on a newly saved task
+func (s *taskApplicationService) attachTags(ctx context.Context, task *entities.Task, tags []valueobjects.TagName) error {
+	toAttach, err := newTags(tags, task.UserID())
+	if err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.AttachTags(ctx, task.ID(), toAttach); err != nil {
+		return err
+	}
+
+	task.LoadTags(tags)
+	return nil
+}
+
+// replaceTags persists the difference between a task's previous and current tags
+func (s *taskApplicationService) replaceTags(ctx context.Context, task *entities.Task, previous []valueobjects.TagName) error {
+	var added, removed []valueobjects.TagName
+	for _, tag := range task.Tags() {
+		if !containsTag(previous, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range previous {
+		if !task.HasTag(tag) {
+			removed = append(removed, tag)
+		}
+	}
+
+	toDetach, err := newTags(removed, task.UserID())
+	if err != nil {
+		return err
+	}
+	if err := s.taskRepo.DetachTags(ctx, task.ID(), toDetach); err != nil {
+		return err
+	}
+
+	toAttach, err := newTags(added, task.UserID())
+	if err != nil {
+		return err
+	}
+	return s.taskRepo.AttachTags(ctx, task.ID(), toAttach)
+}
+
+// newTags builds tag entities owned by the given user
+func newTags(names []valueobjects.TagName, ownerID uservo.UserID) ([]*entities.Tag, error) {
+	tags := make([]*entities.Tag, 0, len(names))
+	for _, name := range names {
+		tag, err := entities.NewTag(name, ownerID)
 		if err != nil {
 			return nil, err
 		}
-		return s.searchService.FindTasksByPriority(userID, priority)
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// containsTag checks if a tag name is in the list
+func containsTag(tags []valueobjects.TagName, tag valueobjects.TagName) bool {
+	for _, existing := range tags {
+		if existing.Equals(tag) {
+			return true
+		}
 	}
+	return false
+}
 
-	// No filters, return all tasks for user
-	return s.taskRepo.FindByUserID(userID)
+// CountUserTasks counts a user's tasks, excluding trashed ones
+func (s *taskApplicationService) CountUserTasks(ctx context.Context, userID uint) (int64, error) {
+	return s.taskRepo.CountByUserID(ctx, uservo.NewUserID(userID))
 }
 
-// DeleteTask deletes a task with ownership validation
-func (s *taskApplicationService) DeleteTask(taskID uint, userID uint) error {
+// GetTaskListVersion summarises a user's tasks without loading them
+func (s *taskApplicationService) GetTaskListVersion(ctx context.Context, userID uint) (repositories.TaskListVersion, error) {
+	return s.taskRepo.GetListVersionByUserID(ctx, uservo.NewUserID(userID))
+}
+
+// SearchTasks retrieves a user's tasks whose title or description matches the query
+func (s *taskApplicationService) SearchTasks(ctx context.Context, userID uint, query string) ([]*entities.Task, error) {
+	tasks, err := s.searchService.SearchByText(ctx, uservo.NewUserID(userID), query)
+	if errors.Is(err, services.ErrEmptySearchQuery) {
+		return nil, apperrors.Validation(err)
+	}
+	return tasks, err
+}
+
+// GetTaskStats counts a user's tasks, including pending tasks that are overdue
+func (s *taskApplicationService) GetTaskStats(ctx context.Context, userID uint) (repositories.TaskStats, error) {
+	return s.taskRepo.GetStatsByUserID(ctx, uservo.NewUserID(userID), time.Now())
+}
+
+// GetTaskPage retrieves up to limit of a user's tasks after afterID, in ID order
+func (s *taskApplicationService) GetTaskPage(ctx context.Context, userID uint, afterID uint, limit int) ([]*entities.Task, error) {
+	return s.taskRepo.FindPageByUserID(ctx, uservo.NewUserID(userID), valueobjects.NewTaskID(afterID), limit)
+}
+
+// GetOverdueTasks retrieves a user's pending tasks whose due date has passed, earliest first
+func (s *taskApplicationService) GetOverdueTasks(ctx context.Context, userID uint) ([]*entities.Task, error) {
+	return s.taskRepo.FindOverdueByUserID(ctx, uservo.NewUserID(userID), time.Now())
+}
+
+// GetTasksDueSoon retrieves a user's pending tasks due between now and now
+// plus within, earliest first
+func (s *taskApplicationService) GetTasksDueSoon(ctx context.Context, userID uint, within time.Duration) ([]*entities.Task, error) {
+	if within <= 0 || within > MaxDueSoonWindow {
+		return nil, apperrors.Validation(fmt.Errorf("within must be positive and at most %s", MaxDueSoonWindow))
+	}
+
+	now := time.Now()
+	return s.taskRepo.FindDueSoonByUserID(ctx, uservo.NewUserID(userID), now, now.Add(within))
+}
+
+// DeleteTask moves a task to the trash with ownership validation
+func (s *taskApplicationService) DeleteTask(ctx context.Context, taskID uint, userID uint) error {
 	taskIDVO := valueobjects.NewTaskID(taskID)
 
 	userIDVO := uservo.NewUserID(userID)
 
 	// Retrieve task to check ownership
-	task, err := s.taskRepo.FindByID(taskIDVO)
+	task, err := s.taskRepo.FindByID(ctx, taskIDVO)
 	if err != nil {
 		return err
 	}
 
+	// Tasks owned by someone else are reported as missing so their existence is not revealed
+	if task == nil || !task.IsOwnedBy(userIDVO) {
+		return errTaskNotFound()
+	}
+
+	// Move the task to the trash, recording it in the task's history
+	err = s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		if err := tx.taskRepo.Delete(ctx, taskIDVO); err != nil {
+			return err
+		}
+		return tx.recordTaskAction(ctx, task, userIDVO, entities.TaskActivityDeleted)
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	task.LoadDeletedAt(&now)
+	s.publishChange(TaskDeleted, task)
+	return nil
+}
+
+// GetTrashedTasks retrieves a user's trashed tasks, most recently deleted first
+func (s *taskApplicationService) GetTrashedTasks(ctx context.Context, userID uint) ([]*entities.Task, error) {
+	return s.taskRepo.FindDeletedByUserID(ctx, uservo.NewUserID(userID))
+}
+
+// GetTaskActivity retrieves a page of a task's history with ownership
+// validation; trashed tasks keep their history visible
+func (s *taskApplicationService) GetTaskActivity(ctx context.Context, taskID uint, userID uint, limit, offset int) ([]*entities.TaskActivity, int64, error) {
+	taskIDVO := valueobjects.NewTaskID(taskID)
+	userIDVO := uservo.NewUserID(userID)
+
+	// Only ownership matters, so live tasks are not loaded
+	owned, err := s.taskRepo.ExistsByIDAndUser(ctx, taskIDVO, userIDVO)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !owned {
+		trashed, err := s.taskRepo.FindDeletedByID(ctx, taskIDVO)
+		if err != nil {
+			return nil, 0, err
+		}
+		owned = trashed != nil && trashed.IsOwnedBy(userIDVO)
+	}
+
+	// Tasks owned by someone else are reported as missing so their existence is not revealed
+	if !owned {
+		return nil, 0, errTaskNotFound()
+	}
+
+	return s.taskRepo.FindActivityByTaskID(ctx, taskIDVO, limit, offset)
+}
+
+// RestoreTask moves a trashed task back to the user's task list
+func (s *taskApplicationService) RestoreTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error) {
+	taskIDVO := valueobjects.NewTaskID(taskID)
+
+	userIDVO := uservo.NewUserID(userID)
+
+	task, err := s.taskRepo.FindDeletedByID(ctx, taskIDVO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only trashed tasks owned by the user can be restored
+	if task == nil || !task.IsOwnedBy(userIDVO) {
+		return nil, errTaskNotFound()
+	}
+
+	err = s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		// The task keeps its status, so it may count towards the quota again
+		if tx.quota.counts(task.Status()) {
+			if err := tx.checkQuota(ctx, userIDVO); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.taskRepo.Restore(ctx, taskIDVO); err != nil {
+			return err
+		}
+		return tx.recordTaskAction(ctx, task, userIDVO, entities.TaskActivityRestored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	task.LoadDeletedAt(nil)
+	s.publishChange(TaskUpdated, task)
+	return task, nil
+}
+
+// PermanentlyDeleteTask removes a task and its tag links with ownership validation
+func (s *taskApplicationService) PermanentlyDeleteTask(ctx context.Context, taskID uint, userID uint) error {
+	taskIDVO := valueobjects.NewTaskID(taskID)
+
+	userIDVO := uservo.NewUserID(userID)
+
+	// The task may be live or already in the trash
+	task, err := s.taskRepo.FindByID(ctx, taskIDVO)
+	if err != nil {
+		return err
+	}
 	if task == nil {
-		return errors.New("task not found")
+		task, err = s.taskRepo.FindDeletedByID(ctx, taskIDVO)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tasks owned by someone else are reported as missing so their existence is not revealed
+	if task == nil || !task.IsOwnedBy(userIDVO) {
+		return errTaskNotFound()
 	}
 
-	// Check ownership
-	if !task.IsOwnedBy(userIDVO) {
-		return errors.New("access denied: task does not belong to user")
+	// The task's history is kept for audit
+	err = s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		if err := tx.taskRepo.DeletePermanently(ctx, taskIDVO); err != nil {
+			return err
+		}
+		return tx.recordTaskAction(ctx, task, userIDVO, entities.TaskActivityPurged)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Delete the task
-	return s.taskRepo.Delete(taskIDVO)
+	if task.DeletedAt() == nil {
+		now := time.Now()
+		task.LoadDeletedAt(&now)
+	}
+	s.publishChange(TaskDeleted, task)
+	return nil
 }
 
 // CompleteTask marks a task as completed
-func (s *taskApplicationService) CompleteTask(taskID uint, userID uint) (*entities.Task, error) {
+func (s *taskApplicationService) CompleteTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error) {
 	cmd := UpdateTaskCommand{
 		TaskID: taskID,
 		Status: func() *string { s := "completed"; return &s }(),
 		UserID: userID,
 	}
-	return s.UpdateTask(cmd)
+	return s.UpdateTask(ctx, cmd)
 }
 
 // ArchiveTask archives a task
-func (s *taskApplicationService) ArchiveTask(taskID uint, userID uint) (*entities.Task, error) {
+func (s *taskApplicationService) ArchiveTask(ctx context.Context, taskID uint, userID uint) (*entities.Task, error) {
 	cmd := UpdateTaskCommand{
 		TaskID: taskID,
 		Status: func() *string { s := "archived"; return &s }(),
 		UserID: userID,
 	}
-	return s.UpdateTask(cmd)
-}
\ No newline at end of file
+	return s.UpdateTask(ctx, cmd)
+}
+
+// BulkUpdateStatus sets the status of all given tasks. Every task must exist and
+// belong to the user; otherwise nothing is updated.
+func (s *taskApplicationService) BulkUpdateStatus(ctx context.Context, userID uint, taskIDs []uint, status string) ([]*entities.Task, error) {
+	newStatus, err := valueobjects.NewTaskStatus(status)
+	if err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	userIDVO := uservo.NewUserID(userID)
+	var tasks []*entities.Task
+
+	// Load, validate and persist every task in a single transaction
+	err = s.inTransaction(ctx, func(tx *taskApplicationService) error {
+		// Reopening finished tasks uses up the quota again when those are left
+		// out; the quota lock is taken before the tasks are loaded so
+		// concurrent changes are counted
+		var count, limit int64
+		if tx.quota.ExcludeFinished && tx.quota.counts(newStatus) {
+			var err error
+			count, limit, err = tx.quotaUsage(ctx, userIDVO)
+			if err != nil {
+				return err
+			}
+		}
+
+		seen := make(map[uint]bool, len(taskIDs))
+		tasks = make([]*entities.Task, 0, len(taskIDs))
+		var activities []*entities.TaskActivity
+		var reopened int64
+
+		// Validate ownership and transitions for every task before changing any
+		for _, id := range taskIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			task, err := tx.taskRepo.FindByID(ctx, valueobjects.NewTaskID(id))
+			if err != nil {
+				return err
+			}
+
+			if task == nil || !task.IsOwnedBy(userIDVO) {
+				return apperrors.NotFound("task_not_found", fmt.Errorf("task %d not found", id))
+			}
+
+			if err := tx.validationService.ValidateTaskUpdate(task.Status(), services.TaskUpdates{Status: &newStatus}); err != nil {
+				return errInvalidTaskUpdate(fmt.Errorf("invalid status change for task %d: %w", id, err))
+			}
+
+			if !tx.quota.counts(task.Status()) && tx.quota.counts(newStatus) {
+				reopened++
+			}
+
+			before := snapshotTask(task)
+			if err := applyStatus(task, newStatus); err != nil {
+				return apperrors.Validation(fmt.Errorf("invalid status change for task %d: %w", id, err))
+			}
+
+			changes, err := diffTaskActivity(before, task, userIDVO)
+			if err != nil {
+				return err
+			}
+			activities = append(activities, changes...)
+			tasks = append(tasks, task)
+		}
+
+		if limit > 0 && count+reopened > limit {
+			return tx.errQuotaExceeded(count, limit)
+		}
+
+		if err := tx.taskRepo.UpdateBatch(ctx, tasks); err != nil {
+			return errTaskSave(err)
+		}
+		return tx.taskRepo.RecordActivity(ctx, activities)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishChange(TaskUpdated, tasks...)
+	s.publishEvents(ctx, tasks...)
+	return tasks, nil
+}
+
+// newDueDate validates a due date against the configured bounds
+func (s *taskApplicationService) newDueDate(value time.Time) (valueobjects.DueDate, error) {
+	dueDate, err := valueobjects.NewDueDate(value, s.dueDateBounds)
+	if errors.Is(err, valueobjects.ErrDueDateOutOfRange) {
+		return valueobjects.DueDate{}, &apperrors.Error{Kind: apperrors.KindValidation, Reason: "invalid_due_date", Err: err}
+	}
+	if err != nil {
+		return valueobjects.DueDate{}, apperrors.Validation(err)
+	}
+	return dueDate, nil
+}
+
+// checkReminder reports a reminder that is not before the task's due date,
+// checked once both have been applied so either may move in the same request
+func checkReminder(task *entities.Task) error {
+	if err := task.CheckReminder(); err != nil {
+		return &apperrors.Error{Kind: apperrors.KindValidation, Reason: "invalid_remind_at", Err: err}
+	}
+	return nil
+}
+
+// errTaskNotFound reports a missing or inaccessible task
+func errTaskNotFound() error {
+	return apperrors.NotFound("task_not_found", errors.New("task not found"))
+}
+
+// errTaskVersionConflict reports an update based on a stale copy of a task
+func errTaskVersionConflict(err error) error {
+	return apperrors.Conflict("task_version_conflict", err)
+}
+
+// errTaskSave classifies a failure to save an updated task, reporting
+// concurrent modification as a conflict
+func errTaskSave(err error) error {
+	if errors.Is(err, repositories.ErrTaskVersionConflict) {
+		return errTaskVersionConflict(err)
+	}
+	return err
+}
+
+// errInvalidTaskUpdate reports a task update rejected by the validation
+// service; illegal status transitions get their own reason
+func errInvalidTaskUpdate(err error) error {
+	if errors.Is(err, services.ErrIllegalStatusTransition) {
+		return &apperrors.Error{Kind: apperrors.KindValidation, Reason: "invalid_status_transition", Err: err}
+	}
+	return apperrors.Validation(err)
+}
+
+// applyStatus moves a task to the given status using the entity's transitions
+func applyStatus(task *entities.Task, status valueobjects.TaskStatus) error {
+	switch {
+	case status.IsCompleted():
+		return task.MarkAsCompleted()
+	case status.IsArchived():
+		return task.Archive()
+	default:
+		return task.Reopen()
+	}
+}
@@ -0,0 +1,38 @@
+package unitofwork
+
+import (
+	"context"
+
+	taskrepos "domain/task/repositories"
+	userrepos "domain/user/repositories"
+)
+
+// Repositories are repository instances bound to a single transaction
+type Repositories struct {
+	Tasks taskrepos.TaskRepository
+	Users userrepos.UserRepository
+}
+
+// UnitOfWork runs multi-step operations atomically
+type UnitOfWork interface {
+	// WithTransaction calls fn with transactional repositories, committing when
+	// fn returns nil and rolling back when it returns an error
+	WithTransaction(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// Join returns a UnitOfWork that runs work in the transaction repos are bound
+// to, so nested calls reuse the outer transaction instead of opening a new one
+func Join(repos Repositories) UnitOfWork {
+	return joinedUnitOfWork{repos: repos}
+}
+
+// joinedUnitOfWork reuses an enclosing transaction
+type joinedUnitOfWork struct {
+	repos Repositories
+}
+
+// WithTransaction calls fn with the enclosing transaction's repositories; the
+// outer WithTransaction call decides whether to commit
+func (u joinedUnitOfWork) WithTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	return fn(u.repos)
+}
@@ -1,18 +1,25 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"log"
+	"time"
 
 	"domain/user/entities"
 	"domain/user/repositories"
 	"domain/user/services"
 	"domain/user/valueobjects"
 	taskvo "domain/task/valueobjects"
+	"todo-app/application/apperrors"
+	"todo-app/application/unitofwork"
+	"todo-app/utils"
 )
 
 // RegisterUserCommand represents a command to register a new user
 type RegisterUserCommand struct {
 	Email     string
+	Password  string
 	FirstName string
 	LastName  string
 	Timezone  string
@@ -41,74 +48,134 @@ type UpdateUserPreferencesCommand struct {
 // UserApplicationService orchestrates user-related use cases
 type UserApplicationService interface {
 	// RegisterUser registers a new user with validation
-	RegisterUser(cmd RegisterUserCommand) (*entities.User, error)
+	RegisterUser(ctx context.Context, cmd RegisterUserCommand) (*entities.User, error)
 
 	// GetUserProfile retrieves a user's profile
-	GetUserProfile(userID uint) (*entities.User, error)
+	GetUserProfile(ctx context.Context, userID uint) (*entities.User, error)
 
 	// UpdateUserProfile updates user profile information
-	UpdateUserProfile(cmd UpdateUserProfileCommand) (*entities.User, error)
+	UpdateUserProfile(ctx context.Context, cmd UpdateUserProfileCommand) (*entities.User, error)
+
+	// GetUserTimezone returns the timezone of a user's profile
+	GetUserTimezone(ctx context.Context, userID uint) (*time.Location, error)
 
 	// GetUserPreferences retrieves user preferences
-	GetUserPreferences(userID uint) (valueobjects.UserPreferences, error)
+	GetUserPreferences(ctx context.Context, userID uint) (valueobjects.UserPreferences, error)
 
 	// UpdateUserPreferences updates user preferences
-	UpdateUserPreferences(cmd UpdateUserPreferencesCommand) (valueobjects.UserPreferences, error)
+	UpdateUserPreferences(ctx context.Context, cmd UpdateUserPreferencesCommand) (valueobjects.UserPreferences, error)
 
 	// GetUserByEmail retrieves a user by email address
-	GetUserByEmail(email string) (*entities.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
 
 	// ChangeUserEmail changes a user's email address
-	ChangeUserEmail(userID uint, newEmail string) (*entities.User, error)
+	ChangeUserEmail(ctx context.Context, userID uint, newEmail string) (*entities.User, error)
+
+	// VerifyEmail consumes an email verification token and marks its user's email as verified
+	VerifyEmail(ctx context.Context, token string) (*entities.User, error)
+}
+
+// ErrRegistrationUnavailable is returned by RegisterUser when there is no
+// VerificationSender to deliver the verification token with
+var ErrRegistrationUnavailable = errors.New("registration is not available: no email provider is configured")
+
+// VerificationSender delivers email verification tokens to users
+type VerificationSender interface {
+	SendVerification(email, token string) error
 }
 
 // userApplicationService implements UserApplicationService
 type userApplicationService struct {
 	userRepo         repositories.UserRepository
+	uow              unitofwork.UnitOfWork
 	authService      services.UserAuthenticationService
 	profileService   services.UserProfileService
+
+	// verificationSender delivers the token issued on registration, valid for verificationTTL
+	verificationSender VerificationSender
+	verificationTTL    time.Duration
+	now                func() time.Time
 }
 
-// NewUserApplicationService creates a new user application service
+// NewUserApplicationService creates a new user application service; a nil
+// verificationSender turns registration off
 func NewUserApplicationService(
 	userRepo repositories.UserRepository,
+	uow unitofwork.UnitOfWork,
 	authService services.UserAuthenticationService,
 	profileService services.UserProfileService,
+	verificationSender VerificationSender,
+	verificationTTL time.Duration,
 ) UserApplicationService {
 	return &userApplicationService{
-		userRepo:       userRepo,
-		authService:    authService,
-		profileService: profileService,
+		userRepo:           userRepo,
+		uow:                uow,
+		authService:        authService,
+		profileService:     profileService,
+		verificationSender: verificationSender,
+		verificationTTL:    verificationTTL,
+		now:                time.Now,
+	}
+}
+
+// RegisterUser registers a new user with complete validation; the uniqueness
+// check and the save run in one transaction
+func (s *userApplicationService) RegisterUser(ctx context.Context, cmd RegisterUserCommand) (*entities.User, error) {
+	// Without a sender no account is created whose email could never be verified
+	if s.verificationSender == nil {
+		return nil, apperrors.Unavailable("registration_unavailable", ErrRegistrationUnavailable)
+	}
+
+	var user *entities.User
+	err := s.inTransaction(ctx, func(tx *userApplicationService) error {
+		var err error
+		user, err = tx.registerUser(ctx, cmd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the token only once the user is committed; a failed delivery leaves
+	// the account unverified rather than failing the registration
+	token := user.VerificationToken()
+	if err := s.verificationSender.SendVerification(user.Email().Value(), token.Value()); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID().Value(), err)
 	}
+
+	return user, nil
 }
 
-// RegisterUser registers a new user with complete validation
-func (s *userApplicationService) RegisterUser(cmd RegisterUserCommand) (*entities.User, error) {
+// registerUser registers a user using the service's repositories
+func (s *userApplicationService) registerUser(ctx context.Context, cmd RegisterUserCommand) (*entities.User, error) {
 	// Create email value object
 	email, err := valueobjects.NewEmail(cmd.Email)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
 	// Create profile value object
 	profile, err := valueobjects.NewUserProfile(cmd.FirstName, cmd.LastName, cmd.Timezone)
 	if err != nil {
-		return nil, err
+		return nil, errInvalidProfile(err)
 	}
 
 	// Validate registration data using domain service
-	if err := s.authService.ValidateRegistrationData(email, profile); err != nil {
-		return nil, err
+	if err := s.authService.ValidateRegistrationData(ctx, email, profile); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyExists) {
+			return nil, errEmailConflict()
+		}
+		return nil, apperrors.Validation(err)
 	}
 
 	// Create preferences (use defaults or provided values)
 	preferences, err := s.createUserPreferences(cmd)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
-	// Generate user ID (will be set by repository)
-	userID := valueobjects.NewUserID(0) // Repository will assign actual ID
+	// New users have no ID until the repository assigns one on Save
+	userID := valueobjects.NewUserID(0)
 
 	// Create user entity
 	user, err := entities.NewUser(userID, email, profile, preferences)
@@ -116,14 +183,51 @@ func (s *userApplicationService) RegisterUser(cmd RegisterUserCommand) (*entitie
 		return nil, err
 	}
 
+	if err := valueobjects.ValidatePassword(cmd.Password); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	passwordHash, err := utils.HashPassword(cmd.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.SetPasswordHash(passwordHash); err != nil {
+		return nil, err
+	}
+
+	// Password registrations must prove they own the email address
+	if _, err := user.IssueEmailVerification(s.now(), s.verificationTTL); err != nil {
+		return nil, err
+	}
+
 	// Save the user
-	if err := s.userRepo.Save(user); err != nil {
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyExists) {
+			return nil, errEmailConflict()
+		}
 		return nil, err
 	}
 
 	return user, nil
 }
 
+// inTransaction runs fn with a copy of the service whose repositories share one
+// transaction; transactions started through the copy join the outer one
+func (s *userApplicationService) inTransaction(ctx context.Context, fn func(tx *userApplicationService) error) error {
+	return s.uow.WithTransaction(ctx, func(repos unitofwork.Repositories) error {
+		return fn(&userApplicationService{
+			userRepo:           repos.Users,
+			uow:                unitofwork.Join(repos),
+			authService:        services.NewUserAuthenticationService(repos.Users),
+			profileService:     services.NewUserProfileService(repos.Users),
+			verificationSender: s.verificationSender,
+			verificationTTL:    s.verificationTTL,
+			now:                s.now,
+		})
+	})
+}
+
 // createUserPreferences creates user preferences from command, using defaults for missing values
 func (s *userApplicationService) createUserPreferences(cmd RegisterUserCommand) (valueobjects.UserPreferences, error) {
 	// Set default task priority
@@ -154,33 +258,33 @@ func (s *userApplicationService) createUserPreferences(cmd RegisterUserCommand)
 }
 
 // GetUserProfile retrieves a user's complete profile
-func (s *userApplicationService) GetUserProfile(userID uint) (*entities.User, error) {
+func (s *userApplicationService) GetUserProfile(ctx context.Context, userID uint) (*entities.User, error) {
 	userIDVO := valueobjects.NewUserID(userID)
 
-	user, err := s.userRepo.FindByID(userIDVO)
+	user, err := s.userRepo.FindByID(ctx, userIDVO)
 	if err != nil {
 		return nil, err
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, errUserNotFound()
 	}
 
 	return user, nil
 }
 
 // UpdateUserProfile updates user profile information with validation
-func (s *userApplicationService) UpdateUserProfile(cmd UpdateUserProfileCommand) (*entities.User, error) {
+func (s *userApplicationService) UpdateUserProfile(ctx context.Context, cmd UpdateUserProfileCommand) (*entities.User, error) {
 	userIDVO := valueobjects.NewUserID(cmd.UserID)
 
 	// Retrieve current user
-	user, err := s.userRepo.FindByID(userIDVO)
+	user, err := s.userRepo.FindByID(ctx, userIDVO)
 	if err != nil {
 		return nil, err
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, errUserNotFound()
 	}
 
 	// Create partial update data
@@ -191,42 +295,60 @@ func (s *userApplicationService) UpdateUserProfile(cmd UpdateUserProfileCommand)
 	}
 
 	// Use domain service to update profile
-	if err := s.profileService.UpdatePartialProfile(userIDVO, updates); err != nil {
+	if err := s.profileService.UpdatePartialProfile(ctx, userIDVO, updates); err != nil {
+		if errors.Is(err, services.ErrInvalidProfile) {
+			return nil, errInvalidProfile(err)
+		}
 		return nil, err
 	}
 
 	// Return updated user
-	return s.userRepo.FindByID(userIDVO)
+	return s.userRepo.FindByID(ctx, userIDVO)
+}
+
+// GetUserTimezone returns the timezone of a user's profile, for showing and
+// interpreting the user's dates
+func (s *userApplicationService) GetUserTimezone(ctx context.Context, userID uint) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(ctx, valueobjects.NewUserID(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errUserNotFound()
+	}
+
+	return user.Profile().Location(), nil
 }
 
 // GetUserPreferences retrieves user preferences
-func (s *userApplicationService) GetUserPreferences(userID uint) (valueobjects.UserPreferences, error) {
+func (s *userApplicationService) GetUserPreferences(ctx context.Context, userID uint) (valueobjects.UserPreferences, error) {
 	userIDVO := valueobjects.NewUserID(userID)
 
-	user, err := s.userRepo.FindByID(userIDVO)
+	user, err := s.userRepo.FindByID(ctx, userIDVO)
 	if err != nil {
 		return valueobjects.UserPreferences{}, err
 	}
 
 	if user == nil {
-		return valueobjects.UserPreferences{}, errors.New("user not found")
+		return valueobjects.UserPreferences{}, errUserNotFound()
 	}
 
 	return user.Preferences(), nil
 }
 
 // UpdateUserPreferences updates user preferences
-func (s *userApplicationService) UpdateUserPreferences(cmd UpdateUserPreferencesCommand) (valueobjects.UserPreferences, error) {
+func (s *userApplicationService) UpdateUserPreferences(ctx context.Context, cmd UpdateUserPreferencesCommand) (valueobjects.UserPreferences, error) {
 	userIDVO := valueobjects.NewUserID(cmd.UserID)
 
 	// Retrieve current user
-	user, err := s.userRepo.FindByID(userIDVO)
+	user, err := s.userRepo.FindByID(ctx, userIDVO)
 	if err != nil {
 		return valueobjects.UserPreferences{}, err
 	}
 
 	if user == nil {
-		return valueobjects.UserPreferences{}, errors.New("user not found")
+		return valueobjects.UserPreferences{}, errUserNotFound()
 	}
 
 	// Get current preferences
@@ -237,7 +359,7 @@ func (s *userApplicationService) UpdateUserPreferences(cmd UpdateUserPreferences
 	if cmd.DefaultTaskPriority != nil {
 		priority, err := taskvo.NewTaskPriority(*cmd.DefaultTaskPriority)
 		if err != nil {
-			return valueobjects.UserPreferences{}, err
+			return valueobjects.UserPreferences{}, apperrors.Validation(err)
 		}
 		defaultPriority = priority
 	} else {
@@ -257,16 +379,16 @@ func (s *userApplicationService) UpdateUserPreferences(cmd UpdateUserPreferences
 	// Create new preferences
 	newPrefs, err := valueobjects.NewUserPreferences(defaultPriority, emailNotifications, themePreference)
 	if err != nil {
-		return valueobjects.UserPreferences{}, err
+		return valueobjects.UserPreferences{}, apperrors.Validation(err)
 	}
 
 	// Update user
 	if err := user.UpdatePreferences(newPrefs); err != nil {
-		return valueobjects.UserPreferences{}, err
+		return valueobjects.UserPreferences{}, apperrors.Validation(err)
 	}
 
 	// Save updated user
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return valueobjects.UserPreferences{}, err
 	}
 
@@ -274,40 +396,43 @@ func (s *userApplicationService) UpdateUserPreferences(cmd UpdateUserPreferences
 }
 
 // GetUserByEmail retrieves a user by email address
-func (s *userApplicationService) GetUserByEmail(email string) (*entities.User, error) {
+func (s *userApplicationService) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
 	emailVO, err := valueobjects.NewEmail(email)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
-	user, err := s.userRepo.FindByEmail(emailVO)
+	user, err := s.userRepo.FindByEmail(ctx, emailVO)
 	if err != nil {
 		return nil, err
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, errUserNotFound()
 	}
 
 	return user, nil
 }
 
 // ChangeUserEmail changes a user's email address with validation
-func (s *userApplicationService) ChangeUserEmail(userID uint, newEmail string) (*entities.User, error) {
+func (s *userApplicationService) ChangeUserEmail(ctx context.Context, userID uint, newEmail string) (*entities.User, error) {
 	userIDVO := valueobjects.NewUserID(userID)
 
 	emailVO, err := valueobjects.NewEmail(newEmail)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
 	// Validate email uniqueness
-	if err := s.authService.ValidateEmailUniqueness(emailVO); err != nil {
+	if err := s.authService.ValidateEmailUniqueness(ctx, emailVO); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyExists) {
+			return nil, errEmailConflict()
+		}
 		return nil, err
 	}
 
 	// Retrieve user
-	user, err := s.userRepo.FindByID(userIDVO)
+	user, err := s.userRepo.FindByID(ctx, userIDVO)
 	if err != nil {
 		return nil, err
 	}
@@ -318,13 +443,71 @@ func (s *userApplicationService) ChangeUserEmail(userID uint, newEmail string) (
 
 	// Change email
 	if err := user.ChangeEmail(emailVO); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
 	// Save updated user
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// VerifyEmail marks the email of the user holding token as verified; tokens
+// are single-use and stop working once they expire
+func (s *userApplicationService) VerifyEmail(ctx context.Context, token string) (*entities.User, error) {
+	if token == "" {
+		return nil, errInvalidVerificationToken()
+	}
+
+	user, err := s.userRepo.FindByVerificationToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errInvalidVerificationToken()
+	}
+
+	if err := user.VerifyEmail(token, s.now()); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrVerificationTokenExpired):
+			return nil, &apperrors.Error{Kind: apperrors.KindValidation, Reason: "verification_token_expired", Err: err}
+		case errors.Is(err, entities.ErrInvalidVerificationToken):
+			return nil, errInvalidVerificationToken()
+		}
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
+}
+
+// errInvalidVerificationToken reports an unknown or already used verification token
+func errInvalidVerificationToken() error {
+	return &apperrors.Error{Kind: apperrors.KindValidation, Reason: "invalid_verification_token", Err: entities.ErrInvalidVerificationToken}
+}
+
+// errInvalidProfile reports profile data that fails validation, calling out
+// timezones that are not IANA identifiers
+func errInvalidProfile(err error) error {
+	if errors.Is(err, valueobjects.ErrInvalidTimezone) {
+		return &apperrors.Error{Kind: apperrors.KindValidation, Reason: "invalid_timezone", Err: err}
+	}
+	return apperrors.Validation(err)
+}
+
+// errUserNotFound reports a missing user
+func errUserNotFound() error {
+	return apperrors.NotFound("user_not_found", errors.New("user not found"))
+}
+
+// errEmailConflict reports an email address that is already registered; the
+// storage error is not wrapped so driver details never reach clients
+func errEmailConflict() error {
+	return apperrors.Conflict("email_conflict", services.ErrEmailAlreadyExists)
 }
\ No newline at end of file
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	apptask "todo-app/application/task"
+	"todo-app/infrastructure/notification"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	"todo-app/internal/storage"
+	"todo-app/middleware"
+	presentationhttp "todo-app/presentation/http"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	"todo-app/services/reminder"
+)
+
+// setupServerRoutesTest wires every handler through setupRoutes the way main
+// does, against a migrated database
+func setupServerRoutesTest(t *testing.T) (*gin.Engine, *auth.SessionService) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, storage.InitDatabase(cfg.Database))
+	t.Cleanup(func() { storage.CloseDatabase() })
+
+	auditService := audit.NewAuditService(storage.DB, 0)
+	t.Cleanup(func() { auditService.Close(context.Background()) })
+
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(storage.DB, jwtService, cfg.Session)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+
+	unitOfWork := persistence.NewGormUnitOfWork(storage.DB)
+	userService := newUserApplicationService(storage.DB, unitOfWork, notification.NewLogVerificationSender(), cfg.Account.EmailVerificationTokenTTL)
+	taskEventHub := presentationhttp.NewTaskEventHub()
+
+	router := gin.New()
+	router.Use(presentationhttp.ErrorHandler())
+	setupRoutes(router, cfg,
+		services.NewHealthService(cfg.Health, cfg.OAuth, cfg.Database),
+		handlers.NewGoogleOAuthHandler(storage.DB, sessionService, cfg.OAuth),
+		handlers.NewOAuthHandler(services.NewGitHubOAuthService(cfg.OAuth), storage.DB, sessionService, cfg.OAuth),
+		handlers.NewPasswordAuthHandler(storage.DB, sessionService, notification.NewLogVerificationSender(), nil),
+		handlers.NewBackchannelLogoutHandler(auth.NewBackchannelLogoutService(storage.DB, sessionService, auth.NewBackchannelLogoutConfig(cfg.OAuth))),
+		handlers.NewSecurityLogHandler(sessionService),
+		handlers.NewNotificationHandler(reminder.NewReminderService(storage.DB, nil)),
+		handlers.NewSessionHandler(sessionService),
+		handlers.NewAccountHandler(storage.DB, sessionService, cfg.Account.DeletionGracePeriod),
+		handlers.NewAdminHandler(storage.DB, sessionService, auditService),
+		presentationhttp.NewUserHandlers(userService),
+		newTaskHandlers(storage.DB, unitOfWork, taskEventHub, apptask.NewSyncEventPublisher(taskEventHub), cfg.Tasks),
+		presentationhttp.NewTaskEventsHandler(taskEventHub, nil),
+		authMiddleware,
+		middleware.NewIPRateLimiter(rate.Every(time.Minute), 10),
+		middleware.NewUserRateLimiter(rate.Every(time.Second), 10),
+	)
+
+	return router, sessionService
+}
+
+// createSignedInUser creates a user with one session and returns its token
+func createSignedInUser(t *testing.T, sessionService *auth.SessionService, email string) string {
+	user := dtos.User{Email: email, Name: "Test User", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, storage.DB.Create(&user).Error)
+
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	return token
+}
+
+func authRouteRequest(method, path, token string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestAuthRoutes_SessionRefreshRotatesToken(t *testing.T) {
+	router, sessionService := setupServerRoutesTest(t)
+	token := createSignedInUser(t, sessionService, "user@example.com")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authRouteRequest(http.MethodPost, "/api/v1/auth/session/refresh", token))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var refreshed string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			refreshed = cookie.Value
+		}
+	}
+	require.NotEmpty(t, refreshed)
+	assert.NotEqual(t, token, refreshed)
+
+	// The rotated token works and the previous one no longer does
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authRouteRequest(http.MethodGet, "/api/v1/auth/sessions", refreshed))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authRouteRequest(http.MethodPost, "/api/v1/auth/session/refresh", token))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthRoutes_SessionRefreshRequiresAuth(t *testing.T) {
+	router, _ := setupServerRoutesTest(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/session/refresh", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserRoutes_RegisterIsRateLimited(t *testing.T) {
+	router, _ := setupServerRoutesTest(t)
+
+	register := func() *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"email":"not-an-email","password":"short","name":"Test User"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The signup limiter allows a burst of 10 attempts per client IP
+	for i := 0; i < 10; i++ {
+		require.NotEqual(t, http.StatusTooManyRequests, register().Code)
+	}
+
+	w := register()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"rate_limited"`)
+}
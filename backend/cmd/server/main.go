@@ -1,60 +1,151 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
 	"time"
 
+	authentities "domain/auth/entities"
 	"domain/health/entities"
+	taskservices "domain/task/services"
+	userservices "domain/user/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+	"todo-app/application/mappers"
+	apptask "todo-app/application/task"
+	"todo-app/application/unitofwork"
+	"todo-app/application/user"
+	"todo-app/infrastructure/notification"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
 	"todo-app/internal/handlers"
+	"todo-app/internal/logging"
 	"todo-app/internal/services"
 	"todo-app/internal/storage"
+	"todo-app/internal/version"
+	"todo-app/jobs"
 	"todo-app/middleware"
+	presentationhttp "todo-app/presentation/http"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	"todo-app/services/reminder"
 )
 
 func main() {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found or could not be loaded: %v", err)
+	envErr := godotenv.Load()
+
+	// Run migrations out-of-band: server migrate up|down [steps]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:], os.Stdout))
+	}
+
+	// Read the configuration once; every invalid or missing setting is listed
+	// before exiting, rather than only the first
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("invalid configuration", err)
 	}
 
-	// Initialize database
-	if err := storage.InitDatabase(); err != nil {
-		log.Fatal("Failed to initialize database:", err)
+	// Configure structured logging from LOG_LEVEL and LOG_FORMAT
+	logging.Setup(cfg.Logging)
+	if envErr != nil {
+		slog.Warn(".env file not found or could not be loaded", "error", envErr)
+	}
+	slog.Info("configuration loaded", "config", cfg.String())
+
+	// Initialize database and apply pending migrations before accepting traffic
+	if err := storage.InitDatabase(cfg.Database); err != nil {
+		fatal("failed to initialize database", err)
 	}
 	defer func() {
 		if err := storage.CloseDatabase(); err != nil {
-			log.Printf("Error closing database: %v", err)
+			slog.Error("failed to close database", "error", err)
+		}
+	}()
+
+	// Shutdown context is cancelled on SIGINT/SIGTERM and stops background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start background cleanup jobs
+	sessionCleanupJob := jobs.NewSessionCleanupJob(storage.DB, 0)
+	oauthCleanupJob := jobs.NewOAuthCleanupJob(storage.DB, 0)
+	accountPurgeJob := jobs.NewAccountPurgeJob(storage.DB, 0)
+	go sessionCleanupJob.Start(ctx)
+	go oauthCleanupJob.Start(ctx)
+	go accountPurgeJob.Start(ctx)
+
+	reminderService := reminder.NewReminderService(storage.DB, reminderNotifiers(cfg, storage.DB))
+	reminderDispatchJob := jobs.NewReminderDispatchJob(reminderService, 0)
+	go reminderDispatchJob.Start(ctx)
+	defer func() {
+		stop()
+		sessionCleanupJob.Stop()
+		oauthCleanupJob.Stop()
+		accountPurgeJob.Stop()
+		reminderDispatchJob.Stop()
+	}()
+
+	// Audit events are written in the background; flush them before the database closes
+	auditService := audit.NewAuditService(storage.DB, 0)
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := auditService.Close(flushCtx); err != nil {
+			slog.Error("failed to flush audit log", "error", err)
 		}
 	}()
 
 	// Set Gin mode
-	if os.Getenv("ENV") == "production" {
+	if cfg.Production() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Create Gin router
-	router := gin.Default()
+	// Create Gin router; requests are logged by RequestLogger and panics
+	// recovered by ErrorHandler, so Gin's text logger and recovery are not used
+	router := gin.New()
+
+	// Unmatched paths and methods get JSON errors like the rest of the API
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(presentationhttp.NotFoundHandler())
+	router.NoMethod(presentationhttp.MethodNotAllowedHandler())
+
+	// Only honor X-Forwarded-For / X-Real-IP from configured proxies so
+	// clients cannot spoof their IP (e.g. to evade rate limiting)
+	if err := router.SetTrustedProxies(cfg.RateLimit.TrustedProxies); err != nil {
+		fatal("invalid TRUSTED_PROXIES", err)
+	}
 
 	// Add middleware
-	router.Use(handlers.ErrorHandler())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.NewMetrics(prometheus.DefaultRegisterer).Middleware())
+	router.Use(presentationhttp.ErrorHandler())
 	router.Use(handlers.RequestLogger())
-	router.Use(handlers.SecurityHeaders())
+	router.Use(handlers.SecurityHeaders(hstsMaxAge(cfg)))
 
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		// Allow requests from the frontend development server
-		if origin == "http://localhost:3000" || origin == "http://127.0.0.1:3000" {
+		// Allow requests from the configured frontend origins
+		if slices.Contains(cfg.CORS.AllowedOrigins, origin) {
 			c.Header("Access-Control-Allow-Origin", origin)
 		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID, X-CSRF-Token")
 		c.Header("Access-Control-Allow-Credentials", "true")
+		c.Header("Access-Control-Expose-Headers", "X-Total-Count, X-Result-Truncated, X-Page-Limit, Link, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Request-ID, ETag, Content-Disposition")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -64,37 +155,253 @@ func main() {
 		c.Next()
 	})
 
+	// Initialize session management
+	jwtService, err := auth.NewJWTService(cfg.Session, cfg.Production())
+	if err != nil {
+		fatal("failed to initialize JWT service", err)
+	}
+	go reloadJWTKeysOnSIGHUP(ctx, jwtService)
+	sessionService := auth.NewSessionService(storage.DB, jwtService, cfg.Session)
+	redirectURIs, err := authentities.NewRedirectURIValidator(cfg.OAuth.AllowedRedirectURIs)
+	if err != nil {
+		fatal("invalid OAuth redirect URIs", err)
+	}
+	if googleConfig, err := auth.NewGoogleOAuthConfig(cfg.OAuth); err == nil {
+		// Renew Google access tokens that are about to expire as sessions are used
+		oauthService, err := auth.NewOAuthService(storage.DB, googleConfig, redirectURIs, cfg.Session.TTL, cfg.OAuth.AutoLink)
+		if err != nil {
+			fatal("failed to initialize OAuth service", err)
+		}
+		sessionService.SetOAuthTokenRefresher(oauthService)
+	} else {
+		slog.Warn("Google OAuth token refresh disabled", "error", err)
+	}
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+
 	// Initialize handlers
-	taskHandler := handlers.NewTaskHandler()
-	healthService := services.NewHealthService()
-	googleOAuthHandler := handlers.NewGoogleOAuthHandler(storage.DB)
+	healthService := services.NewHealthService(cfg.Health, cfg.OAuth, cfg.Database)
+	healthService.SetVersion(version.Get())
+	googleOAuthHandler := handlers.NewGoogleOAuthHandler(storage.DB, sessionService, cfg.OAuth)
+	googleOAuthHandler.SetAuditService(auditService)
+	githubOAuthHandler := handlers.NewOAuthHandler(services.NewGitHubOAuthService(cfg.OAuth), storage.DB, sessionService, cfg.OAuth)
+	githubOAuthHandler.SetAuditService(auditService)
+	verificationSender := emailVerificationSender(cfg)
+	passwordAuthHandler := handlers.NewPasswordAuthHandler(storage.DB, sessionService, verificationSender, passwordResetMailer(cfg))
+	passwordAuthHandler.SetVerificationTokenTTL(cfg.Account.EmailVerificationTokenTTL)
+	passwordAuthHandler.SetAuditService(auditService)
+	securityLogHandler := handlers.NewSecurityLogHandler(sessionService)
+	notificationHandler := handlers.NewNotificationHandler(reminderService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	sessionHandler.SetAuditService(auditService)
+	accountHandler := handlers.NewAccountHandler(storage.DB, sessionService, cfg.Account.DeletionGracePeriod)
+	adminHandler := handlers.NewAdminHandler(storage.DB, sessionService, auditService)
+	backchannelLogoutHandler := handlers.NewBackchannelLogoutHandler(
+		auth.NewBackchannelLogoutService(storage.DB, sessionService, auth.NewBackchannelLogoutConfig(cfg.OAuth)),
+	)
+
+	// Shared unit of work for multi-step application operations
+	unitOfWork := persistence.NewGormUnitOfWork(storage.DB)
+
+	// Initialize user handlers (DDD stack)
+	userService := newUserApplicationService(storage.DB, unitOfWork, verificationSender, cfg.Account.EmailVerificationTokenTTL)
+	userHandlers := presentationhttp.NewUserHandlers(userService)
+
+	// Initialize task handlers (DDD stack); task times are shown in the
+	// timezone of the user's profile
+	taskEventHub := presentationhttp.NewTaskEventHub()
+	taskEventHub.SetUserTimezones(userService)
+	// Task lifecycle events go to the audit log and the owner's open connections
+	taskEventPublisher := apptask.NewSyncEventPublisher(auditService, taskEventHub)
+	taskHandlers := newTaskHandlers(storage.DB, unitOfWork, taskEventHub, taskEventPublisher, cfg.Tasks)
+	taskHandlers.SetAuditService(auditService)
+	taskHandlers.SetUserTimezones(userService)
+	taskEventsHandler := presentationhttp.NewTaskEventsHandler(taskEventHub, cfg.CORS.AllowedOrigins)
 
 	// Initialize rate limiter for signup/OAuth endpoints
 	// 10 requests per 15 minutes = 10 / (15 * 60) = 0.0111 requests per second
-	signupRateLimiter := middleware.NewIPRateLimiter(rate.Every(15*time.Minute)/10, 10)
+	signupRateLimiter := middleware.NewIPRateLimiterWithConfig(rate.Every(15*time.Minute)/10, 10, middleware.IPRateLimiterConfig{
+		IdleTTL:    cfg.RateLimit.IPTTL,
+		MaxEntries: cfg.RateLimit.IPMaxEntries,
+	})
+
+	// Initialize per-user rate limiter for task writes
+	taskWriteRateLimiter := middleware.NewUserRateLimiter(rate.Limit(float64(cfg.RateLimit.UserPerMinute)/60), cfg.RateLimit.UserBurst)
 
 	// Setup routes
-	setupRoutes(router, taskHandler, healthService, googleOAuthHandler, signupRateLimiter)
+	setupRoutes(router, cfg, healthService, googleOAuthHandler, githubOAuthHandler, passwordAuthHandler, backchannelLogoutHandler, securityLogHandler, notificationHandler, sessionHandler, accountHandler, adminHandler, userHandlers, taskHandlers, taskEventsHandler, authMiddleware, signupRateLimiter, taskWriteRateLimiter)
+
+	port := strconv.Itoa(cfg.Server.Port)
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		slog.Error("failed to start server", "port", port, "error", err)
+		return
+	}
+
+	server := &http.Server{Handler: router}
+	// Event streams never finish on their own, so end them rather than wait out the shutdown timeout
+	server.RegisterOnShutdown(taskEventHub.Close)
+
+	slog.Info("server starting", "port", port)
+	if err := serveWithGracefulShutdown(ctx, server, listener, cfg.Server.ShutdownTimeout); err != nil {
+		slog.Error("server error", "error", err)
+	}
+
+	slog.Info("server stopped")
+}
+
+// fatal logs a startup failure and exits; like log.Fatal, deferred calls do not run
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// reloadJWTKeysOnSIGHUP re-reads .env and the configuration on SIGHUP and
+// swaps in the JWT signing keys, so a new key can be rolled out without a
+// restart; the rest of the configuration only changes on restart
+func reloadJWTKeysOnSIGHUP(ctx context.Context, jwtService *auth.JWTService) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := godotenv.Overload(); err != nil {
+				slog.Warn(".env file could not be reloaded", "error", err)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				slog.Error("invalid configuration, keeping current JWT keys", "error", err)
+				continue
+			}
+			if err := jwtService.ReloadKeys(cfg.Session); err != nil {
+				slog.Error("failed to reload JWT keys, keeping current keys", "error", err)
+				continue
+			}
+			slog.Info("reloaded JWT keys", "signing_key_id", jwtService.CurrentKeyID())
+		}
+	}
+}
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// newUserApplicationService wires the user service to a GORM-backed
+// repository; verificationSender delivers email verification tokens, valid for
+// verificationTokenTTL, on registration
+func newUserApplicationService(db *gorm.DB, unitOfWork unitofwork.UnitOfWork, verificationSender user.VerificationSender, verificationTokenTTL time.Duration) user.UserApplicationService {
+	userRepo := persistence.NewGormUserRepository(db, &mappers.UserMapper{})
+	return user.NewUserApplicationService(
+		userRepo,
+		unitOfWork,
+		userservices.NewUserAuthenticationService(userRepo),
+		userservices.NewUserProfileService(userRepo),
+		verificationSender,
+		verificationTokenTTL,
+	)
+}
+
+// newTaskHandlers wires the task handlers to GORM-backed repositories; task
+// changes are published on eventBus and domain events on eventPublisher
+func newTaskHandlers(db *gorm.DB, unitOfWork unitofwork.UnitOfWork, eventBus apptask.EventBus, eventPublisher apptask.EventPublisher, taskConfig config.TaskConfig) *presentationhttp.TaskHandlers {
+	taskRepo := persistence.NewGormTaskRepository(db, &mappers.TaskMapper{})
+	taskAppService := apptask.NewTaskApplicationService(
+		taskRepo,
+		unitOfWork,
+		taskservices.NewTaskValidationService(),
+		taskservices.NewTaskSearchService(taskRepo),
+		taskConfig.DueDateBounds,
+		apptask.TaskQuota{
+			MaxTasks:        taskConfig.MaxTasksPerUser,
+			ExcludeFinished: taskConfig.QuotaExcludeFinished,
+			Limits:          persistence.NewGormTaskLimits(db),
+		},
+		eventPublisher,
+		eventBus,
+	)
+	taskHandlers := presentationhttp.NewTaskHandlers(taskAppService)
+	taskHandlers.SetListWarningThreshold(taskConfig.ListWarningThreshold)
+	return taskHandlers
+}
+
+// apiCompression gzips API responses, except the task event streams, which
+// must reach clients as they are written
+func apiCompression(compressionConfig config.CompressionConfig) gin.HandlerFunc {
+	return middleware.Gzip(middleware.GzipConfig{
+		Level:         compressionConfig.GzipLevel,
+		MinLength:     compressionConfig.GzipMinLength,
+		ExcludedPaths: []string{"/api/v1/ws", "/api/v1/tasks/events"},
+	})
+}
+
+// apiTimeout puts a deadline on API requests, a longer one on task export and
+// import, and none on the task event streams, which stay open for as long as
+// clients are connected
+func apiTimeout(serverConfig config.ServerConfig) gin.HandlerFunc {
+	bulkTimeout := serverConfig.BulkRequestTimeout
+	return middleware.Timeout(middleware.TimeoutConfig{
+		Timeout: serverConfig.RequestTimeout,
+		PathTimeouts: map[string]time.Duration{
+			"/api/v1/tasks/export": bulkTimeout,
+			"/api/v1/tasks/import": bulkTimeout,
+		},
+		ExcludedPaths: []string{"/api/v1/ws", "/api/v1/tasks/events"},
+	})
+}
+
+// hstsMaxAge is the Strict-Transport-Security max-age to send; only production
+// is assumed to be served over HTTPS, so elsewhere the header is left out
+func hstsMaxAge(cfg *config.Config) time.Duration {
+	if !cfg.Production() {
+		return 0
 	}
+	return cfg.Server.HSTSMaxAge
+}
 
-	log.Printf("Server starting on :%s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+// passwordResetMailer returns the mailer for password reset emails. No email
+// provider is wired in yet, and writing reset tokens to the log would let
+// anyone who can read it take over accounts, so outside development there is
+// no mailer and password reset answers 503.
+func passwordResetMailer(cfg *config.Config) handlers.Mailer {
+	if !cfg.Development() {
+		slog.Warn("password reset is unavailable: no email provider is configured")
+		return nil
 	}
+	return notification.NewLogMailer()
+}
+
+// emailVerificationSender returns the sender of email verification tokens. As
+// with password reset, no email provider is wired in yet and the log is no
+// place for tokens outside development, so there is no sender and password
+// registration answers 503.
+func emailVerificationSender(cfg *config.Config) user.VerificationSender {
+	if !cfg.Development() {
+		slog.Warn("password registration is unavailable: no email provider is configured")
+		return nil
+	}
+	return notification.NewLogVerificationSender()
+}
+
+// reminderNotifiers returns the notifier of each task reminder channel.
+// Reminders always go to the log; the email notifier only writes emails to the
+// log as well, exposing users' addresses, so outside development the email
+// channel is left unregistered and its reminders are skipped.
+func reminderNotifiers(cfg *config.Config, db *gorm.DB) map[string]reminder.Notifier {
+	notifiers := map[string]reminder.Notifier{
+		dtos.NotificationChannelLog: reminder.NewLogNotifier(),
+	}
+	if cfg.Development() {
+		notifiers[dtos.NotificationChannelEmail] = reminder.NewEmailNotifier(persistence.NewGormUserRepository(db, &mappers.UserMapper{}))
+	}
+	return notifiers
 }
 
 // setupRoutes configures all API routes
-func setupRoutes(router *gin.Engine, taskHandler *handlers.TaskHandler, healthService *services.HealthService, googleOAuthHandler *handlers.GoogleOAuthHandler, signupRateLimiter *middleware.IPRateLimiter) {
-	// Health check handler function
-	healthHandler := func(c *gin.Context) {
-		healthResponse, err := healthService.GetHealthStatus()
+func setupRoutes(router *gin.Engine, cfg *config.Config, healthService *services.HealthService, googleOAuthHandler *handlers.GoogleOAuthHandler, githubOAuthHandler *handlers.OAuthHandler, passwordAuthHandler *handlers.PasswordAuthHandler, backchannelLogoutHandler *handlers.BackchannelLogoutHandler, securityLogHandler *handlers.SecurityLogHandler, notificationHandler *handlers.NotificationHandler, sessionHandler *handlers.SessionHandler, accountHandler *handlers.AccountHandler, adminHandler *handlers.AdminHandler, userHandlers *presentationhttp.UserHandlers, taskHandlers *presentationhttp.TaskHandlers, taskEventsHandler *presentationhttp.TaskEventsHandler, authMiddleware *middleware.AuthMiddleware, signupRateLimiter *middleware.IPRateLimiter, taskWriteRateLimiter *middleware.UserRateLimiter) {
+	// respondWithHealth runs the health checks and maps the result to a status code
+	respondWithHealth := func(c *gin.Context, getStatus func() (*entities.HealthResponse, error)) {
+		healthResponse, err := getStatus()
 		if err != nil {
-			log.Printf("Health check failed: %v", err)
+			slog.Error("health check failed", "error", err)
 			errorResponse := entities.NewErrorResponse("internal_error", "Health check failed unexpectedly")
 			c.JSON(http.StatusInternalServerError, errorResponse)
 			return
@@ -116,35 +423,126 @@ func setupRoutes(router *gin.Engine, taskHandler *handlers.TaskHandler, healthSe
 		c.JSON(statusCode, healthResponse)
 	}
 
-	// API group
-	api := router.Group("/api")
+	// Health check handler function
+	healthHandler := func(c *gin.Context) {
+		getStatus := healthService.GetHealthStatus
+		if c.Query("fresh") == "true" {
+			// Bypass the cached database check for debugging
+			getStatus = healthService.GetFreshHealthStatus
+		}
+		respondWithHealth(c, getStatus)
+	}
+
+	// Detailed health additionally probes the OAuth provider
+	detailedHealthHandler := func(c *gin.Context) {
+		respondWithHealth(c, func() (*entities.HealthResponse, error) {
+			return healthService.GetDetailedHealthStatus(c.Query("fresh") == "true")
+		})
+	}
+
+	// API group; JSON responses are compressed for clients that accept it
+	api := router.Group("/api", apiCompression(cfg.Compression), apiTimeout(cfg.Server))
 	{
 		// Health endpoint in API group
 		api.GET("/health", healthHandler)
+		api.GET("/health/detailed", detailedHealthHandler)
+
+		// Build version, commit and date of the running server
+		api.GET("/version", func(c *gin.Context) {
+			c.JSON(http.StatusOK, healthService.BuildInfo())
+		})
 
-		// API v1 routes
-		v1 := api.Group("/v1")
+		// API v1 routes; cookie-authenticated writes must carry the session's CSRF token
+		v1 := api.Group("/v1", authMiddleware.RequireCSRF())
 		{
-			// Google OAuth routes
+			// Authentication routes (email/password, Google, GitHub)
 			auth := v1.Group("/auth")
 			{
+				// Email/password sign-up and sign-in share the signup rate limit
+				auth.POST("/register", signupRateLimiter.RateLimitMiddleware(), passwordAuthHandler.Register)
+				auth.POST("/login", signupRateLimiter.RateLimitMiddleware(), passwordAuthHandler.Login)
+				auth.POST("/password/change", authMiddleware.RequireAuth(), passwordAuthHandler.ChangePassword)
+				auth.POST("/password/forgot", signupRateLimiter.RateLimitMiddleware(), passwordAuthHandler.ForgotPassword)
+				auth.POST("/password/reset", signupRateLimiter.RateLimitMiddleware(), passwordAuthHandler.ResetPassword)
+
 				// Apply rate limiter to signup/login endpoint
-				auth.GET("/google/login", signupRateLimiter.RateLimitMiddleware(), googleOAuthHandler.GoogleLogin)
+				auth.GET("/google/login", signupRateLimiter.RedirectRateLimitMiddleware(cfg.OAuth.ErrorRedirectURL), googleOAuthHandler.GoogleLogin)
 				auth.GET("/google/callback", googleOAuthHandler.GoogleCallback)
+				auth.GET("/github/login", signupRateLimiter.RedirectRateLimitMiddleware(cfg.OAuth.ErrorRedirectURL), githubOAuthHandler.Login)
+				auth.GET("/github/callback", githubOAuthHandler.Callback)
+
+				// Sign-ins matching an existing account by email are linked once that account confirms
+				auth.POST("/google/confirm-link", authMiddleware.RequireAuth(), googleOAuthHandler.ConfirmLink)
+				auth.POST("/github/confirm-link", authMiddleware.RequireAuth(), githubOAuthHandler.ConfirmLink)
+
+				// Unlinking Google requires a password to sign in with afterwards
+				auth.DELETE("/google/link", authMiddleware.RequireAuth(), googleOAuthHandler.UnlinkAccount)
+
+				// OIDC back-channel logout from the identity provider
+				auth.POST("/backchannel-logout", backchannelLogoutHandler.BackchannelLogout)
+
+				// Sign out of the current session
+				auth.POST("/logout", authMiddleware.RequireAuth(), sessionHandler.Logout)
+
+				// Sign out of every session of the current user
+				auth.POST("/logout-all", authMiddleware.RequireAuth(), sessionHandler.LogoutAll)
+
+				// Extend the current session, rotating its ID and token
+				auth.POST("/session/refresh", authMiddleware.RequireAuth(), sessionHandler.RefreshSession)
+
+				// CSRF token for the current session, for the frontend to refresh it
+				auth.GET("/csrf", authMiddleware.RequireAuth(), sessionHandler.GetCSRFToken)
+
+				// Session management for the signed-in user ("log out other devices")
+				sessions := auth.Group("/sessions", authMiddleware.RequireAuth())
+				{
+					sessions.GET("", sessionHandler.ListSessions)
+					sessions.DELETE("", sessionHandler.RevokeOtherSessions)
+					sessions.DELETE("/:id", sessionHandler.RevokeSession)
+				}
 			}
 
-			// Task routes
-			tasks := v1.Group("/tasks")
+			// Task routes (require an authenticated session); writes need a verified email and are rate limited per user
+			taskHandlers.RegisterRoutes(v1.Group("", authMiddleware.RequireAuth()), authMiddleware.RequireVerifiedEmail(), taskWriteRateLimiter.RateLimitMiddleware())
+
+			// Real-time task changes for the signed-in user's open tabs and devices
+			v1.GET("/ws", authMiddleware.RequireAuth(), taskEventsHandler.ServeWebSocket)
+			v1.GET("/tasks/events", authMiddleware.RequireAuth(), taskEventsHandler.StreamEvents) // SSE, for networks that block websockets
+
+			// User registration, profile and preferences routes
+			userHandlers.SetRegistrationMiddleware(signupRateLimiter.RateLimitMiddleware())
+			userHandlers.RegisterRoutes(v1, authMiddleware.RequireAuth())
+
+			// Current user account and security routes (require an authenticated session)
+			users := v1.Group("/users", authMiddleware.RequireAuth())
 			{
-				tasks.GET("", taskHandler.GetTasks)
-				tasks.POST("", taskHandler.CreateTask)
-				tasks.GET("/:id", taskHandler.GetTask)
-				tasks.PUT("/:id", taskHandler.UpdateTask)
-				tasks.DELETE("/:id", taskHandler.DeleteTask)
+				users.DELETE("/me", accountHandler.DeleteAccount)
+				users.GET("/me/security-log", securityLogHandler.GetSecurityLog)
+			}
+
+			// Task reminders scheduled for and delivered to the signed-in user
+			v1.GET("/notifications", authMiddleware.RequireAuth(), notificationHandler.ListNotifications)
+
+			// Accounts pending deletion can still reach restore to cancel it
+			v1.POST("/users/me/restore", authMiddleware.RequireAuthAllowingPendingDeletion(), accountHandler.RestoreAccount)
+
+			// User management and audit log for administrators
+			admin := v1.Group("/admin", authMiddleware.RequireAuth(), authMiddleware.RequireAdmin())
+			{
+				admin.GET("/users", adminHandler.ListUsers)
+				admin.GET("/users/:id", adminHandler.GetUser)
+				admin.POST("/users/:id/deactivate", adminHandler.DeactivateUser)
+				admin.POST("/users/:id/activate", adminHandler.ActivateUser)
+				admin.GET("/users/:id/sessions", adminHandler.ListUserSessions)
+				admin.GET("/audit-logs", adminHandler.ListAuditLogs)
 			}
 		}
 	}
 
 	// Enhanced health check endpoint (also available at root level)
 	router.GET("/health", healthHandler)
+	router.GET("/health/detailed", detailedHealthHandler)
+
+	// Prometheus scrape endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 }
\ No newline at end of file
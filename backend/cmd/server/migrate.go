@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"todo-app/internal/config"
+	"todo-app/internal/storage"
+)
+
+const migrateUsage = "usage: server migrate up|down [steps]|status"
+
+// runMigrateCommand handles `server migrate up|down [steps]|status` so migrations
+// can be run out-of-band; it returns the process exit code
+func runMigrateCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+
+	databaseConfig, err := config.LoadDatabase()
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	if err := storage.OpenDatabase(databaseConfig); err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	defer storage.CloseDatabase()
+
+	migrator, err := storage.NewMigrator(storage.DB)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+
+	case "down":
+		// Roll back one migration unless a step count is given
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(out, "steps must be a positive integer")
+				return 2
+			}
+		}
+
+		rolledBack, err := migrator.Down(steps)
+		for _, migration := range rolledBack {
+			fmt.Fprintf(out, "rolled back %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 1
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%03d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+
+	default:
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMigrateCommand_UpStatusDown(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, runMigrateCommand([]string{"up"}, &out))
+	assert.Contains(t, out.String(), "applied 001_create_users")
+
+	out.Reset()
+	assert.Equal(t, 0, runMigrateCommand([]string{"up"}, &out))
+	assert.Contains(t, out.String(), "no pending migrations")
+
+	out.Reset()
+	assert.Equal(t, 0, runMigrateCommand([]string{"down", "2"}, &out))
+	assert.Equal(t, 2, bytes.Count(out.Bytes(), []byte("rolled back")))
+
+	out.Reset()
+	assert.Equal(t, 0, runMigrateCommand([]string{"status"}, &out))
+	assert.Contains(t, out.String(), "001_create_users\tapplied")
+	assert.Contains(t, out.String(), "pending")
+}
+
+func TestRunMigrateCommand_RejectsBadArguments(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+
+	var out bytes.Buffer
+	assert.Equal(t, 2, runMigrateCommand(nil, &out))
+	assert.Equal(t, 2, runMigrateCommand([]string{"sideways"}, &out))
+	assert.Equal(t, 2, runMigrateCommand([]string{"down", "0"}, &out))
+}
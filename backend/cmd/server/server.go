@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// serveWithGracefulShutdown serves HTTP requests on listener until ctx is cancelled,
+// then stops accepting connections and waits up to shutdownTimeout for active
+// requests to complete
+func serveWithGracefulShutdown(ctx context.Context, server *http.Server, listener net.Listener, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// Server stopped on its own (e.g. listener failure)
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutdown signal received, draining requests", "timeout", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+)
+
+func TestServeWithGracefulShutdown_DrainsInFlightRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	requestStarted := make(chan struct{})
+	router := gin.New()
+	router.GET("/slow", func(c *gin.Context) {
+		close(requestStarted)
+		time.Sleep(500 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- serveWithGracefulShutdown(ctx, &http.Server{Handler: router}, listener, 5*time.Second)
+	}()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	select {
+	case <-requestStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow request never reached the handler")
+	}
+
+	// Deliver SIGTERM while the request is still in flight
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, http.StatusOK, res.status)
+	assert.Equal(t, "done", res.body)
+
+	select {
+	case err := <-serverDone:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	// New connections are refused after shutdown
+	_, err = net.DialTimeout("tcp", listener.Addr().String(), time.Second)
+	assert.Error(t, err)
+}
+
+func TestHSTSMaxAge_OnlyInProduction(t *testing.T) {
+	cfg := &config.Config{Env: "development", Server: config.ServerConfig{HSTSMaxAge: config.DefaultHSTSMaxAge}}
+	assert.Zero(t, hstsMaxAge(cfg))
+
+	cfg.Env = "production"
+	assert.Equal(t, config.DefaultHSTSMaxAge, hstsMaxAge(cfg))
+
+	cfg.Server.HSTSMaxAge = 0
+	assert.Zero(t, hstsMaxAge(cfg))
+}
+
+func TestPasswordResetMailer_OnlyLogsInDevelopment(t *testing.T) {
+	assert.NotNil(t, passwordResetMailer(&config.Config{Env: "development"}))
+
+	for _, env := range []string{"production", "test", ""} {
+		assert.Nil(t, passwordResetMailer(&config.Config{Env: env}), "ENV=%q", env)
+	}
+}
+
+func TestEmailVerificationSender_OnlyLogsInDevelopment(t *testing.T) {
+	assert.NotNil(t, emailVerificationSender(&config.Config{Env: "development"}))
+
+	for _, env := range []string{"production", "test", ""} {
+		assert.Nil(t, emailVerificationSender(&config.Config{Env: env}), "ENV=%q", env)
+	}
+}
+
+func TestReminderNotifiers_EmailOnlyInDevelopment(t *testing.T) {
+	notifiers := reminderNotifiers(&config.Config{Env: "development"}, nil)
+	assert.Contains(t, notifiers, dtos.NotificationChannelLog)
+	assert.Contains(t, notifiers, dtos.NotificationChannelEmail)
+
+	notifiers = reminderNotifiers(&config.Config{Env: "production"}, nil)
+	assert.Contains(t, notifiers, dtos.NotificationChannelLog)
+	assert.NotContains(t, notifiers, dtos.NotificationChannelEmail)
+}
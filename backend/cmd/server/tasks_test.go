@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+	apptask "todo-app/application/task"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/config"
+	"todo-app/internal/storage"
+	"todo-app/middleware"
+	presentationhttp "todo-app/presentation/http"
+)
+
+// setupTaskRoutesTest wires the task handlers the way main does, against a
+// migrated database, with a stand-in for the auth middleware
+func setupTaskRoutesTest(t *testing.T, writeMiddleware ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, storage.InitDatabase(cfg.Database))
+	t.Cleanup(func() { storage.CloseDatabase() })
+
+	taskEventHub := presentationhttp.NewTaskEventHub()
+	taskHandlers := newTaskHandlers(storage.DB, persistence.NewGormUnitOfWork(storage.DB), taskEventHub, apptask.NewSyncEventPublisher(taskEventHub), cfg.Tasks)
+	taskEventsHandler := presentationhttp.NewTaskEventsHandler(taskEventHub, nil)
+
+	router := gin.New()
+	router.Use(presentationhttp.ErrorHandler())
+	api := router.Group("/api", apiCompression(cfg.Compression), apiTimeout(cfg.Server))
+	v1 := api.Group("/v1", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	taskHandlers.RegisterRoutes(v1, writeMiddleware...)
+	v1.GET("/tasks/events", taskEventsHandler.StreamEvents)
+
+	return router
+}
+
+func performTaskJSON(router *gin.Engine, method, target string, payload interface{}) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	if payload != nil {
+		_ = json.NewEncoder(&body).Encode(payload)
+	}
+	req := httptest.NewRequest(method, target, &body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestTaskRoutes_FrontendContract walks the create/list/complete/delete flow
+// the frontend relies on
+func TestTaskRoutes_FrontendContract(t *testing.T) {
+	router := setupTaskRoutesTest(t)
+
+	w := performTaskJSON(router, http.MethodPost, "/api/v1/tasks", map[string]string{"title": "Buy milk"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	for _, field := range []string{"id", "title", "completed", "created_at", "updated_at"} {
+		assert.Contains(t, created, field)
+	}
+	assert.Equal(t, false, created["completed"])
+	taskPath := "/api/v1/tasks/" + strconv.Itoa(int(created["id"].(float64)))
+
+	w = performTaskJSON(router, http.MethodPut, taskPath, map[string]bool{"completed": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, true, updated["completed"])
+
+	w = performTaskJSON(router, http.MethodGet, "/api/v1/tasks?completed=true", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+		Count int                      `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Count)
+	assert.Equal(t, "Buy milk", list.Tasks[0]["title"])
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+
+	assert.Equal(t, http.StatusNoContent, performTaskJSON(router, http.MethodDelete, taskPath, nil).Code)
+	assert.Equal(t, http.StatusNotFound, performTaskJSON(router, http.MethodGet, taskPath, nil).Code)
+}
+
+func TestTaskRoutes_ErrorResponses(t *testing.T) {
+	router := setupTaskRoutesTest(t)
+
+	cases := []struct {
+		name    string
+		method  string
+		target  string
+		payload interface{}
+		status  int
+	}{
+		{"missing title", http.MethodPost, "/api/v1/tasks", map[string]string{}, http.StatusBadRequest},
+		{"invalid status filter", http.MethodGet, "/api/v1/tasks?status=invalid", nil, http.StatusBadRequest},
+		{"invalid task ID", http.MethodGet, "/api/v1/tasks/abc", nil, http.StatusBadRequest},
+		{"unknown task", http.MethodPut, "/api/v1/tasks/999", map[string]string{"title": "x"}, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performTaskJSON(router, tc.method, tc.target, tc.payload)
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.NotEmpty(t, body["error"])
+			assert.NotEmpty(t, body["message"])
+		})
+	}
+}
+
+func TestTaskRoutes_RateLimitsWritesOnly(t *testing.T) {
+	limiter := middleware.NewUserRateLimiter(rate.Every(time.Hour), 1)
+	router := setupTaskRoutesTest(t, limiter.RateLimitMiddleware())
+
+	w := performTaskJSON(router, http.MethodPost, "/api/v1/tasks", map[string]string{"title": "First"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performTaskJSON(router, http.MethodPost, "/api/v1/tasks", map[string]string{"title": "Second"})
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, performTaskJSON(router, http.MethodDelete, "/api/v1/tasks/1", nil).Code)
+
+	// Reads are never throttled
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, performTaskJSON(router, http.MethodGet, "/api/v1/tasks", nil).Code)
+	}
+}
+
+func TestTaskRoutes_CompressesLargeResponses(t *testing.T) {
+	router := setupTaskRoutesTest(t)
+	for i := 0; i < 20; i++ {
+		w := performTaskJSON(router, http.MethodPost, "/api/v1/tasks", map[string]string{
+			"title":       "Task " + strconv.Itoa(i),
+			"description": strings.Repeat("compressible ", 10),
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	plain := performTaskJSON(router, http.MethodGet, "/api/v1/tasks", nil)
+	require.Equal(t, http.StatusOK, plain.Code)
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	require.Greater(t, plain.Body.Len(), 1024)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+	assert.Less(t, w.Body.Len(), plain.Body.Len())
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.JSONEq(t, plain.Body.String(), string(decoded))
+}
+
+func TestTaskRoutes_DoesNotCompressEventStream(t *testing.T) {
+	router := setupTaskRoutesTest(t)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/tasks/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	// The stream's headers arrive as soon as it is opened, rather than once
+	// enough output has been buffered to compress
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	assert.Empty(t, resp.Header.Get("Vary"), "the compression middleware should skip the stream")
+}
+
+func TestTaskRoutes_SlowQueryTimesOut(t *testing.T) {
+	t.Setenv("REQUEST_TIMEOUT", "20ms")
+	t.Setenv("REQUEST_TIMEOUT_BULK", "5s")
+	router := setupTaskRoutesTest(t)
+
+	// Stand in for a locked or overloaded database
+	require.NoError(t, storage.DB.Callback().Query().Before("gorm:query").Register("test:slow", func(*gorm.DB) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+
+	w := performTaskJSON(router, http.MethodGet, "/api/v1/tasks", nil)
+	require.Equal(t, http.StatusGatewayTimeout, w.Code, w.Body.String())
+	var response presentationhttp.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "request_timeout", response.Error)
+	assert.Equal(t, presentationhttp.CodeTimeout, response.Code)
+
+	// Import gets the longer bulk deadline
+	w = performTaskJSON(router, http.MethodPost, "/api/v1/tasks/import", []map[string]string{{"title": "Imported"}})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
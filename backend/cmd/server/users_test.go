@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/application/apperrors"
+	"todo-app/application/user"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/internal/storage"
+	presentationhttp "todo-app/presentation/http"
+	"todo-app/utils"
+)
+
+// capturingVerificationSender records the last token sent to each email
+type capturingVerificationSender struct {
+	tokens map[string]string
+}
+
+func (s *capturingVerificationSender) SendVerification(email, token string) error {
+	s.tokens[email] = token
+	return nil
+}
+
+// setupUserRoutesTest wires the user handlers the way main does, against a
+// migrated database
+func setupUserRoutesTest(t *testing.T) (*gin.Engine, *capturingVerificationSender) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, storage.InitDatabase(cfg.Database))
+	t.Cleanup(func() { storage.CloseDatabase() })
+
+	sender := &capturingVerificationSender{tokens: map[string]string{}}
+	userHandlers := presentationhttp.NewUserHandlers(newUserApplicationService(storage.DB, persistence.NewGormUnitOfWork(storage.DB), sender, cfg.Account.EmailVerificationTokenTTL))
+
+	router := gin.New()
+	router.Use(presentationhttp.ErrorHandler())
+	userHandlers.RegisterRoutes(router.Group("/api/v1"))
+
+	return router, sender
+}
+
+func registerUser(t *testing.T, router *gin.Engine, email string) map[string]interface{} {
+	w := performTaskJSON(router, http.MethodPost, "/api/v1/users/register", map[string]interface{}{
+		"email":    email,
+		"password": "correct horse battery 9",
+		"profile": map[string]string{
+			"first_name": "Jane",
+			"last_name":  "Doe",
+			"timezone":   "UTC",
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func verifyEmail(router *gin.Engine, token string) (int, map[string]interface{}) {
+	w := performTaskJSON(router, http.MethodGet, "/api/v1/users/verify?token="+url.QueryEscape(token), nil)
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body
+}
+
+func TestUserRoutes_RegisterThenVerifyEmail(t *testing.T) {
+	router, sender := setupUserRoutesTest(t)
+
+	registered := registerUser(t, router, "jane@example.com")
+	assert.Equal(t, false, registered["email_verified"])
+
+	var stored dtos.User
+	require.NoError(t, storage.DB.Where("email = ?", "jane@example.com").First(&stored).Error)
+	assert.True(t, utils.VerifyPassword("correct horse battery 9", stored.PasswordHash))
+	assert.False(t, stored.EmailVerified)
+
+	token := sender.tokens["jane@example.com"]
+	require.NotEmpty(t, token)
+
+	status, body := verifyEmail(router, token)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, true, body["email_verified"])
+
+	require.NoError(t, storage.DB.First(&stored, stored.ID).Error)
+	assert.True(t, stored.EmailVerified)
+	assert.Nil(t, stored.VerificationToken)
+
+	// Tokens are single-use
+	status, body = verifyEmail(router, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "invalid_verification_token", body["error"])
+}
+
+func TestUserRoutes_VerifyEmailRejectsBadTokens(t *testing.T) {
+	router, sender := setupUserRoutesTest(t)
+	registerUser(t, router, "jane@example.com")
+
+	status, body := verifyEmail(router, "")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "invalid_verification_token", body["error"])
+
+	status, body = verifyEmail(router, "not-a-token")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "invalid_verification_token", body["error"])
+
+	require.NoError(t, storage.DB.Model(&dtos.User{}).
+		Where("email = ?", "jane@example.com").
+		UpdateColumn("verification_token_expires_at", time.Now().Add(-time.Minute)).Error)
+
+	status, body = verifyEmail(router, sender.tokens["jane@example.com"])
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "verification_token_expired", body["error"])
+}
+
+func TestUserRoutes_RegisterUnavailableWithoutVerificationSender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "todo.db"))
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, storage.InitDatabase(cfg.Database))
+	t.Cleanup(func() { storage.CloseDatabase() })
+
+	userService := newUserApplicationService(storage.DB, persistence.NewGormUnitOfWork(storage.DB), nil, cfg.Account.EmailVerificationTokenTTL)
+	router := gin.New()
+	router.Use(presentationhttp.ErrorHandler())
+	presentationhttp.NewUserHandlers(userService).RegisterRoutes(router.Group("/api/v1"))
+
+	w := registerUserInTimezone(router, "jane@example.com", "UTC")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "registration_unavailable")
+
+	var count int64
+	require.NoError(t, storage.DB.Model(&dtos.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func registerUserInTimezone(router *gin.Engine, email, timezone string) *httptest.ResponseRecorder {
+	return performTaskJSON(router, http.MethodPost, "/api/v1/users/register", map[string]interface{}{
+		"email":    email,
+		"password": "correct horse battery 9",
+		"profile": map[string]string{
+			"first_name": "Jane",
+			"last_name":  "Doe",
+			"timezone":   timezone,
+		},
+	})
+}
+
+func TestUserRoutes_RegisterRejectsInvalidTimezone(t *testing.T) {
+	router, _ := setupUserRoutesTest(t)
+
+	for _, timezone := range []string{"PST8PDT-ish", "Mars/Olympus_Mons", "Local"} {
+		w := registerUserInTimezone(router, "jane@example.com", timezone)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, timezone)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "invalid_timezone", body["error"], timezone)
+	}
+
+	var count int64
+	require.NoError(t, storage.DB.Model(&dtos.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUserTimezone_StoredAndUpdated(t *testing.T) {
+	router, _ := setupUserRoutesTest(t)
+	w := registerUserInTimezone(router, "jane@example.com", "America/New_York")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var stored dtos.User
+	require.NoError(t, storage.DB.Where("email = ?", "jane@example.com").First(&stored).Error)
+	assert.Equal(t, "America/New_York", stored.Timezone)
+
+	userService := newUserApplicationService(storage.DB, persistence.NewGormUnitOfWork(storage.DB), &capturingVerificationSender{tokens: map[string]string{}}, config.DefaultEmailVerificationTokenTTL)
+	location, err := userService.GetUserTimezone(context.Background(), stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", location.String())
+
+	invalid := "PST8PDT-ish"
+	_, err = userService.UpdateUserProfile(context.Background(), user.UpdateUserProfileCommand{UserID: stored.ID, Timezone: &invalid})
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "invalid_timezone", appErr.Reason)
+
+	berlin := "Europe/Berlin"
+	_, err = userService.UpdateUserProfile(context.Background(), user.UpdateUserProfileCommand{UserID: stored.ID, Timezone: &berlin})
+	require.NoError(t, err)
+	location, err = userService.GetUserTimezone(context.Background(), stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", location.String())
+}
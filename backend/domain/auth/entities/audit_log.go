@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditEventType identifies the kind of security-relevant event an AuditLog records
+type AuditEventType string
+
+const (
+	AuditEventLoginSucceeded    AuditEventType = "login_succeeded"
+	AuditEventLoginFailed       AuditEventType = "login_failed"
+	AuditEventLogout            AuditEventType = "logout"
+	AuditEventSessionTerminated AuditEventType = "session_terminated"
+	AuditEventAccountLinked     AuditEventType = "account_linked"
+	AuditEventAccountUnlinked   AuditEventType = "account_unlinked"
+	AuditEventPasswordChanged   AuditEventType = "password_changed"
+	AuditEventTaskDeleted       AuditEventType = "task_deleted"
+	AuditEventTaskCompleted     AuditEventType = "task_completed"
+	AuditEventTaskArchived      AuditEventType = "task_archived"
+	AuditEventTaskReopened      AuditEventType = "task_reopened"
+)
+
+// auditEventTypes lists every valid AuditEventType
+var auditEventTypes = map[AuditEventType]bool{
+	AuditEventLoginSucceeded:    true,
+	AuditEventLoginFailed:       true,
+	AuditEventLogout:            true,
+	AuditEventSessionTerminated: true,
+	AuditEventAccountLinked:     true,
+	AuditEventAccountUnlinked:   true,
+	AuditEventPasswordChanged:   true,
+	AuditEventTaskDeleted:       true,
+	AuditEventTaskCompleted:     true,
+	AuditEventTaskArchived:      true,
+	AuditEventTaskReopened:      true,
+}
+
+// IsValid reports whether t is a known event type
+func (t AuditEventType) IsValid() bool {
+	return auditEventTypes[t]
+}
+
+// AuditMetadata holds event-specific details, stored as a JSON object
+type AuditMetadata map[string]interface{}
+
+// Value implements driver.Valuer
+func (m AuditMetadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *AuditMetadata) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditMetadata", value)
+	}
+	return json.Unmarshal(data, m)
+}
+
+// AuditLog records a security-relevant event such as a login, logout or task
+// deletion. UserID is nil when the actor is unknown, e.g. for a failed login.
+type AuditLog struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	UserID    *uint          `json:"user_id,omitempty" gorm:"index"`
+	EventType AuditEventType `json:"event_type" gorm:"type:varchar(50);not null;index"`
+	IPAddress string         `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent string         `json:"user_agent" gorm:"type:text"`
+	Metadata  AuditMetadata  `json:"metadata" gorm:"type:text"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName specifies the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate hook to validate audit log before creation
+func (l *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	return l.Validate()
+}
+
+// Validate performs validation on the AuditLog model
+func (l *AuditLog) Validate() error {
+	if !l.EventType.IsValid() {
+		return errors.New("invalid audit event type")
+	}
+	return nil
+}
+
+// NewAuditLog creates an audit log entry; a userID of 0 records no user.
+// CreatedAt is set now rather than on insert, since entries are written asynchronously.
+func NewAuditLog(eventType AuditEventType, userID uint, ipAddress, userAgent string, metadata AuditMetadata) *AuditLog {
+	entry := &AuditLog{
+		EventType: eventType,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+	if userID != 0 {
+		entry.UserID = &userID
+	}
+	return entry
+}
@@ -4,17 +4,25 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 	userentities "domain/user/entities"
 )
 
+// DefaultSessionTTL is how long a session lasts unless configured otherwise
+const DefaultSessionTTL = 24 * time.Hour
+
+// MaxSessionTTL caps how far ahead Validate accepts SessionExpiresAt, and so
+// the session lifetime that can be configured
+const MaxSessionTTL = 30 * 24 * time.Hour
+
 // AuthenticationSession represents an active user session with OAuth token management
 type AuthenticationSession struct {
 	ID        string `json:"id" gorm:"primaryKey;type:varchar(255)"`
 	UserID    uint   `json:"user_id" gorm:"not null;index"`
-	User      userentities.User   `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	User      userentities.User   `json:"-" gorm:"-"`
 
 	// Session tokens
 	SessionToken string `json:"-" gorm:"type:text;uniqueIndex;not null"`
@@ -24,6 +32,9 @@ type AuthenticationSession struct {
 	AccessToken    string     `json:"-" gorm:"type:text"`
 	TokenExpiresAt *time.Time `json:"token_expires_at"`
 
+	// Identity provider session ID (OIDC "sid"), used for back-channel logout
+	ProviderSessionID string `json:"-" gorm:"type:varchar(255);index"`
+
 	// Session management
 	SessionExpiresAt time.Time `json:"session_expires_at" gorm:"not null;index"`
 	LastActivity     time.Time `json:"last_activity" gorm:"not null;default:CURRENT_TIMESTAMP"`
@@ -69,10 +80,10 @@ func (s *AuthenticationSession) Validate() error {
 		return errors.New("session cannot be expired")
 	}
 
-	// Session cannot be longer than 24 hours
-	maxSessionTime := time.Now().Add(24 * time.Hour)
+	// Session cannot be longer than the configured maximum
+	maxSessionTime := time.Now().Add(MaxSessionTTL)
 	if s.SessionExpiresAt.After(maxSessionTime) {
-		return errors.New("session_expires_at cannot exceed 24 hours")
+		return fmt.Errorf("session_expires_at cannot exceed %s", MaxSessionTTL)
 	}
 
 	// If access_token is present, token_expires_at is required
@@ -85,7 +96,26 @@ func (s *AuthenticationSession) Validate() error {
 
 // IsExpired returns true if the session has expired
 func (s *AuthenticationSession) IsExpired() bool {
-	return s.SessionExpiresAt.Before(time.Now()) || s.SessionExpiresAt.Equal(time.Now())
+	return s.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt returns true if the session has expired as of now
+func (s *AuthenticationSession) IsExpiredAt(now time.Time) bool {
+	return !s.SessionExpiresAt.After(now)
+}
+
+// IsIdle returns true if the session has seen no activity for longer than idleTimeout.
+// A non-positive idleTimeout disables the check.
+func (s *AuthenticationSession) IsIdle(idleTimeout time.Duration) bool {
+	return s.IsIdleAt(idleTimeout, time.Now())
+}
+
+// IsIdleAt returns true if, as of now, the session has seen no activity for longer than idleTimeout
+func (s *AuthenticationSession) IsIdleAt(idleTimeout time.Duration, now time.Time) bool {
+	if idleTimeout <= 0 {
+		return false
+	}
+	return now.Sub(s.LastActivity) > idleTimeout
 }
 
 // IsTokenExpired returns true if the OAuth tokens have expired
@@ -112,20 +142,24 @@ func (s *AuthenticationSession) UpdateActivity() {
 	s.LastActivity = time.Now()
 }
 
-// ExtendSession extends the session expiry if within allowed time
-func (s *AuthenticationSession) ExtendSession() error {
+// ExtendSession extends the session expiry to ttl from now if within allowed time
+func (s *AuthenticationSession) ExtendSession(ttl time.Duration) error {
 	// Only extend if session is still valid and user has been active
 	if s.IsExpired() {
 		return errors.New("cannot extend expired session")
 	}
 
-	// Extend session by 24 hours from now
-	s.SessionExpiresAt = time.Now().Add(24 * time.Hour)
+	s.SessionExpiresAt = time.Now().Add(ttl)
 	s.UpdateActivity()
 
 	return s.Validate()
 }
 
+// RotateID assigns the session a fresh ID so tokens issued for the old ID stop resolving
+func (s *AuthenticationSession) RotateID() {
+	s.ID = generateSessionID()
+}
+
 // UpdateOAuthTokens updates the OAuth access and refresh tokens
 func (s *AuthenticationSession) UpdateOAuthTokens(accessToken, refreshToken string, expiresAt time.Time) error {
 	s.AccessToken = accessToken
@@ -186,6 +220,45 @@ func (s *AuthenticationSession) ToResponse() SessionResponse {
 	}
 }
 
+// ActiveSessionResponse represents one of a user's sessions in the session list
+type ActiveSessionResponse struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActivity time.Time `json:"last_activity"`
+	UserAgent    string    `json:"user_agent"`
+	IPAddress    string    `json:"ip_address"`
+	Current      bool      `json:"current"`
+}
+
+// ToActiveSessionResponse converts AuthenticationSession model to ActiveSessionResponse
+// with an anonymized IP; current marks the session making the request
+func (s *AuthenticationSession) ToActiveSessionResponse(currentSessionID string) ActiveSessionResponse {
+	return ActiveSessionResponse{
+		ID:           s.ID,
+		CreatedAt:    s.CreatedAt,
+		LastActivity: s.LastActivity,
+		UserAgent:    s.UserAgent,
+		IPAddress:    AnonymizeIP(s.IPAddress),
+		Current:      s.ID == currentSessionID,
+	}
+}
+
+// SessionValidationResult.Error values for sessions that existed but are no longer usable
+const (
+	// SessionErrorExpired means the session reached its absolute expiry
+	SessionErrorExpired = "expired"
+	// SessionErrorIdleTimeout means the session went unused for longer than the idle timeout
+	SessionErrorIdleTimeout = "idle_timeout"
+	// SessionErrorReauthenticationRequired means the provider revoked the session's OAuth grant
+	SessionErrorReauthenticationRequired = "reauthentication_required"
+	// SessionErrorTokenExpired means the session token is past its exp claim
+	SessionErrorTokenExpired = "token_expired"
+	// SessionErrorTokenIssuedInFuture means the session token's iat is later than the allowed clock skew
+	SessionErrorTokenIssuedInFuture = "token_issued_in_future"
+	// SessionErrorAccountDeactivated means the session's user has been deactivated
+	SessionErrorAccountDeactivated = "account_deactivated"
+)
+
 // SessionValidationResult represents the result of session validation
 type SessionValidationResult struct {
 	Valid         bool                   `json:"valid"`
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginEvent records a session creation so users can review their login history.
+// Unlike AuthenticationSession it is kept after the session ends.
+type LoginEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	SessionID string    `json:"session_id" gorm:"type:varchar(255);index"`
+	IsOAuth   bool      `json:"is_oauth" gorm:"default:false"`
+	UserAgent string    `json:"user_agent" gorm:"type:text"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(45)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName specifies the table name for the LoginEvent model
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// BeforeCreate hook to validate login event before creation
+func (e *LoginEvent) BeforeCreate(tx *gorm.DB) error {
+	return e.Validate()
+}
+
+// Validate performs validation on the LoginEvent model
+func (e *LoginEvent) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
+// NewLoginEvent creates a login event for a newly created session
+func NewLoginEvent(session *AuthenticationSession) *LoginEvent {
+	return &LoginEvent{
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		IsOAuth:   session.IsOAuthSession(),
+		UserAgent: session.UserAgent,
+		IPAddress: session.IPAddress,
+	}
+}
+
+// LoginEventResponse represents a login event returned in the security log
+type LoginEventResponse struct {
+	Timestamp time.Time `json:"timestamp"`
+	IPAddress string    `json:"ip_address"`
+	Device    string    `json:"device"`
+	IsOAuth   bool      `json:"is_oauth"`
+}
+
+// ToResponse converts LoginEvent model to LoginEventResponse with an anonymized IP
+func (e *LoginEvent) ToResponse() LoginEventResponse {
+	return LoginEventResponse{
+		Timestamp: e.CreatedAt,
+		IPAddress: AnonymizeIP(e.IPAddress),
+		Device:    e.UserAgent,
+		IsOAuth:   e.IsOAuth,
+	}
+}
+
+// AnonymizeIP masks the host portion of an IP address
+// (last octet for IPv4, last 80 bits for IPv6)
+func AnonymizeIP(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ""
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4.Mask(net.CIDRMask(24, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -45,16 +45,11 @@ func (s *OAuthState) Validate() error {
 		return errors.New("pkce_verifier cannot be empty")
 	}
 
-	// Validate redirect URI format
+	// Validate redirect URI format; the whitelist is checked when the state is generated
 	if !isValidURL(s.RedirectURI) {
 		return errors.New("redirect_uri must be a valid URL")
 	}
 
-	// Validate redirect URI is whitelisted
-	if !ValidateRedirectURI(s.RedirectURI) {
-		return errors.New("redirect_uri not in whitelist")
-	}
-
 	// State cannot be expired
 	if s.ExpiresAt.Before(time.Now()) || s.ExpiresAt.Equal(time.Now()) {
 		return errors.New("state cannot be expired")
@@ -74,10 +69,13 @@ func (s *OAuthState) IsExpired() bool {
 	return s.ExpiresAt.Before(time.Now()) || s.ExpiresAt.Equal(time.Now())
 }
 
-// GenerateOAuthState creates a new OAuth state with PKCE
-func GenerateOAuthState(redirectURI string) (*OAuthState, error) {
-	if !ValidateRedirectURI(redirectURI) {
-		return nil, errors.New("invalid redirect URI")
+// ErrRedirectURINotAllowed is returned when a redirect URI is not in the whitelist
+var ErrRedirectURINotAllowed = errors.New("redirect URI not allowed")
+
+// GenerateOAuthState creates a new OAuth state with PKCE for a whitelisted redirect URI
+func GenerateOAuthState(redirectURI string, redirectURIs *RedirectURIValidator) (*OAuthState, error) {
+	if !redirectURIs.Allows(redirectURI) {
+		return nil, ErrRedirectURINotAllowed
 	}
 
 	stateToken := generateSecureRandomString(40) // 40 characters for extra security
@@ -93,25 +91,6 @@ func GenerateOAuthState(redirectURI string) (*OAuthState, error) {
 	return state, state.Validate()
 }
 
-// ValidateRedirectURI validates that a redirect URI is allowed
-func ValidateRedirectURI(uri string) bool {
-	// Allowed redirect URIs for the application
-	allowedURIs := []string{
-		"http://localhost:3000/",
-		"http://localhost:3000/dashboard",
-		"http://localhost:3000/auth/callback",
-	}
-
-	// In production, this should be configurable and include production domains
-	for _, allowed := range allowedURIs {
-		if strings.HasPrefix(uri, allowed) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // GeneratePKCEVerifier generates a cryptographically random PKCE verifier
 func GeneratePKCEVerifier() string {
 	// PKCE verifier: 43-128 characters, URL-safe
@@ -222,8 +201,8 @@ func ValidateAndConsume(db *gorm.DB, stateToken string) (*OAuthStateValidationRe
 }
 
 // CreateAndSave creates a new OAuth state and saves it to database
-func CreateAndSave(db *gorm.DB, redirectURI string) (*OAuthState, error) {
-	state, err := GenerateOAuthState(redirectURI)
+func CreateAndSave(db *gorm.DB, redirectURI string, redirectURIs *RedirectURIValidator) (*OAuthState, error) {
+	state, err := GenerateOAuthState(redirectURI, redirectURIs)
 	if err != nil {
 		return nil, err
 	}
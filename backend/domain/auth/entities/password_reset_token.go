@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetTokenTTL is how long a password reset link stays usable
+const PasswordResetTokenTTL = 30 * time.Minute
+
+// PasswordResetToken lets a user who forgot their password set a new one. Only
+// the SHA-256 hash of the token is stored, so the database alone cannot be used
+// to reset passwords, and the token is spent once UsedAt is set.
+type PasswordResetToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for the PasswordResetToken model
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// BeforeCreate hook to validate reset token before creation
+func (t *PasswordResetToken) BeforeCreate(tx *gorm.DB) error {
+	return t.Validate()
+}
+
+// Validate performs validation on the PasswordResetToken model
+func (t *PasswordResetToken) Validate() error {
+	if t.UserID == 0 {
+		return errors.New("user_id cannot be empty")
+	}
+	if len(t.TokenHash) != sha256.Size*2 {
+		return errors.New("token_hash must be a hex-encoded SHA-256 hash")
+	}
+	if t.ExpiresAt.IsZero() {
+		return errors.New("expires_at cannot be empty")
+	}
+	return nil
+}
+
+// IsExpired returns true if the token can no longer be used
+func (t *PasswordResetToken) IsExpired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// IsUsed returns true if the token has already reset a password
+func (t *PasswordResetToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// NewPasswordResetToken creates a reset token for userID and returns it with the
+// raw token to send to the user, which is not kept anywhere
+func NewPasswordResetToken(userID uint) (*PasswordResetToken, string) {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	raw := base64.RawURLEncoding.EncodeToString(bytes)
+
+	return &PasswordResetToken{
+		UserID:    userID,
+		TokenHash: HashPasswordResetToken(raw),
+		ExpiresAt: time.Now().Add(PasswordResetTokenTTL),
+	}, raw
+}
+
+// HashPasswordResetToken returns the hex SHA-256 hash under which a raw reset token is stored
+func HashPasswordResetToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
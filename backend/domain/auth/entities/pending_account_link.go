@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PendingAccountLinkTTL is how long a sign-in that matched an existing account by
+// email can wait for that account to confirm the link
+const PendingAccountLinkTTL = 15 * time.Minute
+
+// PendingAccountLink records an OAuth sign-in whose email matched an existing
+// account while automatic linking is disabled. The link is only made once a
+// session of that account confirms it with the token.
+type PendingAccountLink struct {
+	Token      string    `json:"-" gorm:"primaryKey;type:varchar(255)"`
+	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	Provider   string    `json:"provider" gorm:"type:varchar(50);not null"`
+	ExternalID string    `json:"-" gorm:"type:varchar(255);not null"`
+	Email      string    `json:"email" gorm:"type:varchar(255);not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for the PendingAccountLink model
+func (PendingAccountLink) TableName() string {
+	return "pending_account_links"
+}
+
+// BeforeCreate hook to validate pending link before creation
+func (l *PendingAccountLink) BeforeCreate(tx *gorm.DB) error {
+	return l.Validate()
+}
+
+// Validate performs validation on the PendingAccountLink model
+func (l *PendingAccountLink) Validate() error {
+	if len(l.Token) < 32 {
+		return errors.New("token must be at least 32 characters")
+	}
+	if l.UserID == 0 {
+		return errors.New("user_id cannot be empty")
+	}
+	if l.Provider == "" {
+		return errors.New("provider cannot be empty")
+	}
+	if l.ExternalID == "" {
+		return errors.New("external_id cannot be empty")
+	}
+	return nil
+}
+
+// IsExpired returns true if the link can no longer be confirmed
+func (l *PendingAccountLink) IsExpired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
+
+// NewPendingAccountLink creates a pending link of the provider account to userID
+func NewPendingAccountLink(userID uint, provider, externalID, email string) *PendingAccountLink {
+	return &PendingAccountLink{
+		Token:      generatePendingLinkToken(),
+		UserID:     userID,
+		Provider:   provider,
+		ExternalID: externalID,
+		Email:      email,
+		ExpiresAt:  time.Now().Add(PendingAccountLinkTTL),
+	}
+}
+
+// generatePendingLinkToken returns 32 random bytes, base64url-encoded (43 characters)
+func generatePendingLinkToken() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
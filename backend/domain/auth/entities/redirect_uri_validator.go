@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// RedirectURIValidator decides which redirect URIs an OAuth flow may return to.
+// Each allowed pattern is either an exact URI or a prefix ending in "/*"; both
+// compare scheme, host and port exactly, and prefixes match whole path segments.
+type RedirectURIValidator struct {
+	patterns []redirectURIPattern
+}
+
+// redirectURIPattern is a parsed allowed redirect URI
+type redirectURIPattern struct {
+	scheme string
+	host   string // host[:port], lower case
+	path   string
+	prefix bool
+}
+
+// NewRedirectURIValidator creates a validator from exact URIs such as
+// "https://app.example.com/auth/callback" and prefixes such as "https://app.example.com/*"
+func NewRedirectURIValidator(patterns []string) (*RedirectURIValidator, error) {
+	v := &RedirectURIValidator{}
+	for _, raw := range patterns {
+		pattern, err := parseRedirectURIPattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		v.patterns = append(v.patterns, pattern)
+	}
+	return v, nil
+}
+
+func parseRedirectURIPattern(raw string) (redirectURIPattern, error) {
+	prefix := strings.HasSuffix(raw, "/*")
+	if prefix {
+		raw = strings.TrimSuffix(raw, "*")
+	}
+	if strings.Contains(raw, "*") {
+		return redirectURIPattern{}, fmt.Errorf("redirect URI pattern %q: wildcards are only allowed as a trailing /*", raw)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return redirectURIPattern{}, fmt.Errorf("redirect URI pattern %q must be an absolute URL without credentials, query or fragment", raw)
+	}
+
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+
+	return redirectURIPattern{
+		scheme: strings.ToLower(u.Scheme),
+		host:   strings.ToLower(u.Host),
+		path:   p,
+		prefix: prefix,
+	}, nil
+}
+
+// Allows reports whether uri may be used as an OAuth redirect URI. The query
+// string is not compared; paths containing dot segments are rejected.
+func (v *RedirectURIValidator) Allows(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User != nil {
+		return false
+	}
+
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	if cleaned := path.Clean(p); cleaned != p && cleaned+"/" != p {
+		return false
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	for _, pattern := range v.patterns {
+		if pattern.scheme != scheme || pattern.host != host {
+			continue
+		}
+		if pattern.prefix && strings.HasPrefix(p, pattern.path) {
+			return true
+		}
+		if !pattern.prefix && p == pattern.path {
+			return true
+		}
+	}
+
+	return false
+}
@@ -17,7 +17,7 @@ type GoogleIdentity struct {
 	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationship
-	User           userentities.User      `json:"-" gorm:"foreignKey:UserID"`
+	User           userentities.User      `json:"-" gorm:"-"`
 }
 
 // TableName specifies the table name for the GoogleIdentity model
@@ -3,4 +3,12 @@ module todo-app/domain
 go 1.23
 
 // Domain layer: No external dependencies allowed (pure business logic)
-// Can only import standard library and other domain packages
\ No newline at end of file
+// Can only import standard library and other domain packages
+
+require gorm.io/gorm v1.31.2
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
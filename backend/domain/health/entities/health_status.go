@@ -25,13 +25,49 @@ const (
 	DatabaseStatusError        DatabaseStatus = "error"
 )
 
+// OAuthReachability reports whether the OAuth provider answered a probe
+type OAuthReachability string
+
+const (
+	OAuthReachable   OAuthReachability = "reachable"
+	OAuthUnreachable OAuthReachability = "unreachable"
+)
+
+// Dependency check names reported in the health response
+const (
+	CheckNameDatabase      = "database"
+	CheckNameOAuthProvider = "oauth_provider"
+	CheckNameDisk          = "disk"
+
+	// CheckNameOAuthReachability is only run for the detailed health view
+	CheckNameOAuthReachability = "oauth_reachability"
+)
+
+// CheckResult represents the outcome of a single dependency health check
+type CheckResult struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	Critical  bool         `json:"critical"`
+	LatencyMs int64        `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// Passed reports whether the check succeeded
+func (c CheckResult) Passed() bool {
+	return c.Status == HealthStatusHealthy
+}
+
 // HealthResponse represents the response structure for the health endpoint
 type HealthResponse struct {
-	Status    HealthStatus    `json:"status" validate:"required"`
-	Database  DatabaseStatus  `json:"database" validate:"required"`
-	Timestamp string          `json:"timestamp" validate:"required"`
-	Version   string          `json:"version,omitempty"`
-	Uptime    int64           `json:"uptime,omitempty"`
+	Status    HealthStatus      `json:"status" validate:"required"`
+	Database  DatabaseStatus    `json:"database" validate:"required"`
+	Timestamp string            `json:"timestamp" validate:"required"`
+	Version   string            `json:"version,omitempty"`
+	Commit    string            `json:"commit,omitempty"`
+	BuildDate string            `json:"build_date,omitempty"`
+	Uptime    int64             `json:"uptime,omitempty"`
+	Checks    []CheckResult     `json:"checks,omitempty"`
+	OAuth     OAuthReachability `json:"oauth,omitempty"`
 }
 
 // ErrorResponse represents the error response structure
@@ -70,6 +106,24 @@ func (h *HealthResponse) Validate() error {
 		return fmt.Errorf("version cannot be empty or whitespace-only")
 	}
 
+	// Validate OAuth reachability (only reported by the detailed view)
+	if h.OAuth != "" && h.OAuth != OAuthReachable && h.OAuth != OAuthUnreachable {
+		return fmt.Errorf("invalid oauth reachability: %s, must be one of: reachable, unreachable", h.OAuth)
+	}
+
+	// Validate dependency checks
+	for _, check := range h.Checks {
+		if check.Name == "" {
+			return fmt.Errorf("check name cannot be empty")
+		}
+		if !check.Status.IsValid() {
+			return fmt.Errorf("invalid status for check %s: %s", check.Name, check.Status)
+		}
+		if check.LatencyMs < 0 {
+			return fmt.Errorf("latency for check %s must be non-negative, got: %d", check.Name, check.LatencyMs)
+		}
+	}
+
 	return nil
 }
 
@@ -177,3 +231,20 @@ func DetermineOverallHealth(dbStatus DatabaseStatus) HealthStatus {
 		return HealthStatusUnhealthy
 	}
 }
+
+// DetermineOverallHealthFromChecks derives the overall status from dependency checks:
+// unhealthy if any critical check fails, degraded if any non-critical check fails,
+// healthy otherwise
+func DetermineOverallHealthFromChecks(checks []CheckResult) HealthStatus {
+	status := HealthStatusHealthy
+	for _, check := range checks {
+		if check.Passed() {
+			continue
+		}
+		if check.Critical {
+			return HealthStatusUnhealthy
+		}
+		status = HealthStatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"errors"
+
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+)
+
+// Tag represents a user-defined label; names are unique per owner
+type Tag struct {
+	name    valueobjects.TagName
+	ownerID uservo.UserID
+}
+
+// NewTag creates a new Tag entity
+func NewTag(name valueobjects.TagName, ownerID uservo.UserID) (*Tag, error) {
+	if name.Value() == "" {
+		return nil, errors.New("tag name cannot be empty")
+	}
+
+	if ownerID.IsZero() {
+		return nil, errors.New("user ID cannot be zero")
+	}
+
+	return &Tag{
+		name:    name,
+		ownerID: ownerID,
+	}, nil
+}
+
+// Name returns the tag name
+func (t *Tag) Name() valueobjects.TagName {
+	return t.name
+}
+
+// OwnerID returns the user ID that owns this tag
+func (t *Tag) OwnerID() uservo.UserID {
+	return t.ownerID
+}
@@ -2,12 +2,16 @@ package entities
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"domain/task/valueobjects"
 	uservo "domain/user/valueobjects"
 )
 
+// ErrReminderNotBeforeDueDate is returned when a task's reminder is at or after its due date
+var ErrReminderNotBeforeDueDate = errors.New("reminder must be before the due date")
+
 // Task represents a domain entity for task management
 type Task struct {
 	id          valueobjects.TaskID
@@ -15,12 +19,24 @@ type Task struct {
 	description valueobjects.TaskDescription
 	status      valueobjects.TaskStatus
 	priority    valueobjects.TaskPriority
+	tags        []valueobjects.TagName
+	dueDate     *time.Time
+	remindAt    *time.Time
+	deletedAt   *time.Time
 	userID      uservo.UserID
 	createdAt   time.Time
 	updatedAt   time.Time
+
+	// version counts saves of the task, starting at 1, so a save based on a
+	// stale copy can be detected
+	version int
+
+	// events are recorded by state changes until the application layer pulls them
+	events []DomainEvent
 }
 
-// NewTask creates a new Task entity
+// NewTask creates a new Task entity; the ID is zero for a task that has not
+// been persisted yet and is assigned by the repository on Save
 func NewTask(
 	id valueobjects.TaskID,
 	title valueobjects.TaskTitle,
@@ -29,10 +45,6 @@ func NewTask(
 	priority valueobjects.TaskPriority,
 	userID uservo.UserID,
 ) (*Task, error) {
-	if id.IsZero() {
-		return nil, errors.New("task ID cannot be zero")
-	}
-
 	if userID.IsZero() {
 		return nil, errors.New("user ID cannot be zero")
 	}
@@ -48,57 +60,290 @@ func NewTask(
 		userID:      userID,
 		createdAt:   now,
 		updatedAt:   now,
+		version:     1,
 	}, nil
 }
 
-// MarkAsCompleted marks the task as completed
+// AssignID sets the ID generated when a new task is first persisted
+func (t *Task) AssignID(id valueobjects.TaskID) error {
+	if !t.id.IsZero() {
+		return errors.New("task ID is already assigned")
+	}
+
+	if id.IsZero() {
+		return errors.New("task ID cannot be zero")
+	}
+
+	t.id = id
+	return nil
+}
+
+// MarkAsCompleted marks the task as completed and records a TaskCompleted
+// event unless it already was
 func (t *Task) MarkAsCompleted() error {
 	if t.status.IsArchived() {
 		return errors.New("cannot complete archived task")
 	}
 
+	wasCompleted := t.status.IsCompleted()
 	t.status = valueobjects.NewCompletedStatus()
 	t.updatedAt = time.Now()
+
+	if !wasCompleted {
+		t.events = append(t.events, TaskCompleted{
+			TaskID:      t.id,
+			UserID:      t.userID,
+			CompletedAt: t.updatedAt,
+		})
+	}
 	return nil
 }
 
-// UpdateTitle updates the task title
+// PullEvents returns the events recorded since the last call and clears them
+func (t *Task) PullEvents() []DomainEvent {
+	events := t.events
+	t.events = nil
+	return events
+}
+
+// recordUpdate adds field to the task's pending TaskUpdated event, recording
+// the event if there is none yet
+func (t *Task) recordUpdate(field string) {
+	for i, event := range t.events {
+		updated, ok := event.(TaskUpdated)
+		if !ok {
+			continue
+		}
+		if !slices.Contains(updated.Fields, field) {
+			updated.Fields = append(updated.Fields, field)
+		}
+		updated.UpdatedAt = t.updatedAt
+		t.events[i] = updated
+		return
+	}
+
+	t.events = append(t.events, TaskUpdated{
+		TaskID:    t.id,
+		UserID:    t.userID,
+		Fields:    []string{field},
+		UpdatedAt: t.updatedAt,
+	})
+}
+
+// UpdateTitle updates the task title, recording a TaskUpdated event if it changed
 func (t *Task) UpdateTitle(title valueobjects.TaskTitle) error {
 	if !t.status.CanBeModified() {
 		return errors.New("cannot modify archived task")
 	}
 
+	changed := !t.title.Equals(title)
 	t.title = title
 	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldTitle)
+	}
 	return nil
 }
 
-// UpdateDescription updates the task description
+// UpdateDescription updates the task description, recording a TaskUpdated
+// event if it changed
 func (t *Task) UpdateDescription(description valueobjects.TaskDescription) error {
 	if !t.status.CanBeModified() {
 		return errors.New("cannot modify archived task")
 	}
 
+	changed := !t.description.Equals(description)
 	t.description = description
 	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldDescription)
+	}
 	return nil
 }
 
-// ChangePriority changes the task priority
+// ChangePriority changes the task priority, recording a TaskUpdated event if
+// it changed
 func (t *Task) ChangePriority(priority valueobjects.TaskPriority) error {
 	if !t.status.CanChangePriority() {
 		return errors.New("can only change priority on pending tasks")
 	}
 
+	changed := !t.priority.Equals(priority)
 	t.priority = priority
 	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldPriority)
+	}
+	return nil
+}
+
+// SetTags replaces the task's tags, recording a TaskUpdated event if the set
+// of tags changed
+func (t *Task) SetTags(tags []valueobjects.TagName) error {
+	if !t.status.CanBeModified() {
+		return errors.New("cannot modify archived task")
+	}
+
+	changed := len(tags) != len(t.tags)
+	for _, tag := range tags {
+		if !t.HasTag(tag) {
+			changed = true
+		}
+	}
+
+	t.tags = append([]valueobjects.TagName(nil), tags...)
+	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldTags)
+	}
+	return nil
+}
+
+// SetDueDate sets the task's due date, recording a TaskUpdated event if it changed
+func (t *Task) SetDueDate(dueDate valueobjects.DueDate) error {
+	if !t.status.CanBeModified() {
+		return errors.New("cannot modify archived task")
+	}
+
+	value := dueDate.Value()
+	changed := t.dueDate == nil || !t.dueDate.Equal(value)
+	t.dueDate = &value
+	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldDueDate)
+	}
+	return nil
+}
+
+// ClearDueDate removes the task's due date, recording a TaskUpdated event if
+// it had one
+func (t *Task) ClearDueDate() error {
+	if !t.status.CanBeModified() {
+		return errors.New("cannot modify archived task")
+	}
+
+	changed := t.dueDate != nil
+	t.dueDate = nil
+	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldDueDate)
+	}
+	return nil
+}
+
+// SetRemindAt sets when the owner is reminded about the task, recording a
+// TaskUpdated event if it changed. It does not check the reminder against the
+// due date; call CheckReminder once every change has been applied.
+func (t *Task) SetRemindAt(remindAt time.Time) error {
+	if !t.status.CanBeModified() {
+		return errors.New("cannot modify archived task")
+	}
+	if remindAt.IsZero() {
+		return errors.New("reminder time cannot be empty")
+	}
+
+	changed := t.remindAt == nil || !t.remindAt.Equal(remindAt)
+	t.remindAt = &remindAt
+	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldRemindAt)
+	}
+	return nil
+}
+
+// ClearRemindAt removes the task's reminder, recording a TaskUpdated event if
+// it had one
+func (t *Task) ClearRemindAt() error {
+	if !t.status.CanBeModified() {
+		return errors.New("cannot modify archived task")
+	}
+
+	changed := t.remindAt != nil
+	t.remindAt = nil
+	t.updatedAt = time.Now()
+	if changed {
+		t.recordUpdate(TaskFieldRemindAt)
+	}
+	return nil
+}
+
+// CheckReminder returns ErrReminderNotBeforeDueDate if the task has both a
+// reminder and a due date and the reminder is not before the due date
+func (t *Task) CheckReminder() error {
+	if t.remindAt != nil && t.dueDate != nil && !t.remindAt.Before(*t.dueDate) {
+		return ErrReminderNotBeforeDueDate
+	}
 	return nil
 }
 
-// Archive archives the task
+// LoadTags restores persisted tags without marking the task as modified
+func (t *Task) LoadTags(tags []valueobjects.TagName) {
+	t.tags = append([]valueobjects.TagName(nil), tags...)
+}
+
+// LoadDueDate restores a persisted due date without marking the task as modified
+func (t *Task) LoadDueDate(dueDate *time.Time) {
+	t.dueDate = dueDate
+}
+
+// LoadRemindAt restores a persisted reminder time without marking the task as modified
+func (t *Task) LoadRemindAt(remindAt *time.Time) {
+	t.remindAt = remindAt
+}
+
+// LoadDeletedAt restores when a trashed task was deleted
+func (t *Task) LoadDeletedAt(deletedAt *time.Time) {
+	t.deletedAt = deletedAt
+}
+
+// LoadTimestamps restores when a persisted task was created and last saved
+func (t *Task) LoadTimestamps(createdAt, updatedAt time.Time) {
+	t.createdAt = createdAt
+	t.updatedAt = updatedAt
+}
+
+// LoadVersion restores the version a persisted task was last saved at
+func (t *Task) LoadVersion(version int) {
+	t.version = version
+}
+
+// IsOverdue reports whether the task is pending and its due date is before now;
+// completed and archived tasks are never overdue
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.status.IsPending() && t.dueDate != nil && t.dueDate.Before(now)
+}
+
+// Archive archives the task and records a TaskArchived event unless it
+// already was
 func (t *Task) Archive() error {
+	wasArchived := t.status.IsArchived()
 	t.status = valueobjects.NewArchivedStatus()
 	t.updatedAt = time.Now()
+
+	if !wasArchived {
+		t.events = append(t.events, TaskArchived{
+			TaskID:     t.id,
+			UserID:     t.userID,
+			ArchivedAt: t.updatedAt,
+		})
+	}
+	return nil
+}
+
+// Reopen returns the task to pending status and records a TaskReopened event
+// unless it already was pending
+func (t *Task) Reopen() error {
+	wasPending := t.status.IsPending()
+	t.status = valueobjects.NewPendingStatus()
+	t.updatedAt = time.Now()
+
+	if !wasPending {
+		t.events = append(t.events, TaskReopened{
+			TaskID:     t.id,
+			UserID:     t.userID,
+			ReopenedAt: t.updatedAt,
+		})
+	}
 	return nil
 }
 
@@ -134,6 +379,41 @@ func (t *Task) Priority() valueobjects.TaskPriority {
 	return t.priority
 }
 
+// Tags returns the task's tags
+func (t *Task) Tags() []valueobjects.TagName {
+	return append([]valueobjects.TagName(nil), t.tags...)
+}
+
+// HasTag checks if the task carries the given tag
+func (t *Task) HasTag(tag valueobjects.TagName) bool {
+	for _, existing := range t.tags {
+		if existing.Equals(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Version returns the version the task was loaded or last saved at
+func (t *Task) Version() int {
+	return t.version
+}
+
+// DueDate returns the due date, or nil if the task has none
+func (t *Task) DueDate() *time.Time {
+	return t.dueDate
+}
+
+// RemindAt returns when the owner is reminded about the task, or nil if it has no reminder
+func (t *Task) RemindAt() *time.Time {
+	return t.remindAt
+}
+
+// DeletedAt returns when the task was moved to the trash, or nil if it is not trashed
+func (t *Task) DeletedAt() *time.Time {
+	return t.deletedAt
+}
+
 // UserID returns the user ID that owns this task
 func (t *Task) UserID() uservo.UserID {
 	return t.userID
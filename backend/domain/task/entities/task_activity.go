@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+)
+
+// TaskActivityAction is what was done to a task
+type TaskActivityAction string
+
+const (
+	TaskActivityCreated   TaskActivityAction = "created"
+	TaskActivityUpdated   TaskActivityAction = "updated"
+	TaskActivityCompleted TaskActivityAction = "completed"
+	TaskActivityArchived  TaskActivityAction = "archived"
+	TaskActivityReopened  TaskActivityAction = "reopened"
+	TaskActivityDeleted   TaskActivityAction = "deleted" // moved to the trash
+	TaskActivityRestored  TaskActivityAction = "restored"
+	TaskActivityPurged    TaskActivityAction = "purged" // permanently deleted
+)
+
+// TaskActivity is one entry in a task's history: an action on the task and,
+// for changes to a field, the field's value before and after. Entries are kept
+// after the task is deleted.
+type TaskActivity struct {
+	id        uint
+	taskID    valueobjects.TaskID
+	userID    uservo.UserID
+	action    TaskActivityAction
+	field     string  // empty for actions on the whole task
+	oldValue  *string // nil when the field had no value
+	newValue  *string // nil when the field was cleared
+	createdAt time.Time
+}
+
+// NewTaskActivity creates an activity entry for something a user just did to
+// a task; field, oldValue and newValue are left empty for whole-task actions
+func NewTaskActivity(
+	taskID valueobjects.TaskID,
+	userID uservo.UserID,
+	action TaskActivityAction,
+	field string,
+	oldValue *string,
+	newValue *string,
+) (*TaskActivity, error) {
+	if taskID.IsZero() {
+		return nil, errors.New("task ID cannot be zero")
+	}
+
+	if userID.IsZero() {
+		return nil, errors.New("user ID cannot be zero")
+	}
+
+	return &TaskActivity{
+		taskID:    taskID,
+		userID:    userID,
+		action:    action,
+		field:     field,
+		oldValue:  oldValue,
+		newValue:  newValue,
+		createdAt: time.Now(),
+	}, nil
+}
+
+// LoadPersisted restores the ID and creation time of a stored entry
+func (a *TaskActivity) LoadPersisted(id uint, createdAt time.Time) {
+	a.id = id
+	a.createdAt = createdAt
+}
+
+// ID returns the entry's ID; it is zero until the entry is stored
+func (a *TaskActivity) ID() uint {
+	return a.id
+}
+
+// TaskID returns the task the entry is about
+func (a *TaskActivity) TaskID() valueobjects.TaskID {
+	return a.taskID
+}
+
+// UserID returns the user who acted on the task
+func (a *TaskActivity) UserID() uservo.UserID {
+	return a.userID
+}
+
+// Action returns what was done to the task
+func (a *TaskActivity) Action() TaskActivityAction {
+	return a.action
+}
+
+// Field returns the changed field, or "" for whole-task actions
+func (a *TaskActivity) Field() string {
+	return a.field
+}
+
+// OldValue returns the field's value before the change
+func (a *TaskActivity) OldValue() *string {
+	return a.oldValue
+}
+
+// NewValue returns the field's value after the change
+func (a *TaskActivity) NewValue() *string {
+	return a.newValue
+}
+
+// CreatedAt returns when the action happened
+func (a *TaskActivity) CreatedAt() time.Time {
+	return a.createdAt
+}
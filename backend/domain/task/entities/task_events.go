@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"time"
+
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+)
+
+// DomainEvent is something that happened to an aggregate that other parts of
+// the system may react to
+type DomainEvent interface {
+	// EventName identifies the kind of event, e.g. task.completed
+	EventName() string
+
+	// OccurredAt returns when the event happened
+	OccurredAt() time.Time
+}
+
+// TaskDomainEvent is a DomainEvent that happened to a single task
+type TaskDomainEvent interface {
+	DomainEvent
+
+	// Subject returns the task the event happened to and the user who owns it
+	Subject() (valueobjects.TaskID, uservo.UserID)
+}
+
+// Task field names listed by TaskUpdated
+const (
+	TaskFieldTitle       = "title"
+	TaskFieldDescription = "description"
+	TaskFieldPriority    = "priority"
+	TaskFieldTags        = "tags"
+	TaskFieldDueDate     = "due_date"
+	TaskFieldRemindAt    = "remind_at"
+)
+
+// TaskCompleted is recorded when a task is marked as completed
+type TaskCompleted struct {
+	TaskID      valueobjects.TaskID
+	UserID      uservo.UserID
+	CompletedAt time.Time
+}
+
+// EventName returns the name of the TaskCompleted event
+func (e TaskCompleted) EventName() string {
+	return "task.completed"
+}
+
+// OccurredAt returns when the task was completed
+func (e TaskCompleted) OccurredAt() time.Time {
+	return e.CompletedAt
+}
+
+// Subject returns the completed task and its owner
+func (e TaskCompleted) Subject() (valueobjects.TaskID, uservo.UserID) {
+	return e.TaskID, e.UserID
+}
+
+// TaskArchived is recorded when a task is archived
+type TaskArchived struct {
+	TaskID     valueobjects.TaskID
+	UserID     uservo.UserID
+	ArchivedAt time.Time
+}
+
+// EventName returns the name of the TaskArchived event
+func (e TaskArchived) EventName() string {
+	return "task.archived"
+}
+
+// OccurredAt returns when the task was archived
+func (e TaskArchived) OccurredAt() time.Time {
+	return e.ArchivedAt
+}
+
+// Subject returns the archived task and its owner
+func (e TaskArchived) Subject() (valueobjects.TaskID, uservo.UserID) {
+	return e.TaskID, e.UserID
+}
+
+// TaskReopened is recorded when a completed or archived task becomes pending again
+type TaskReopened struct {
+	TaskID     valueobjects.TaskID
+	UserID     uservo.UserID
+	ReopenedAt time.Time
+}
+
+// EventName returns the name of the TaskReopened event
+func (e TaskReopened) EventName() string {
+	return "task.reopened"
+}
+
+// OccurredAt returns when the task was reopened
+func (e TaskReopened) OccurredAt() time.Time {
+	return e.ReopenedAt
+}
+
+// Subject returns the reopened task and its owner
+func (e TaskReopened) Subject() (valueobjects.TaskID, uservo.UserID) {
+	return e.TaskID, e.UserID
+}
+
+// TaskUpdated is recorded when a task's details change. Changes made between
+// two pulls of the task's events are merged into one event listing every
+// changed field once, in the order they were first changed.
+type TaskUpdated struct {
+	TaskID    valueobjects.TaskID
+	UserID    uservo.UserID
+	Fields    []string // TaskFieldTitle, TaskFieldDescription and so on
+	UpdatedAt time.Time
+}
+
+// EventName returns the name of the TaskUpdated event
+func (e TaskUpdated) EventName() string {
+	return "task.updated"
+}
+
+// OccurredAt returns when the task was last updated
+func (e TaskUpdated) OccurredAt() time.Time {
+	return e.UpdatedAt
+}
+
+// Subject returns the updated task and its owner
+func (e TaskUpdated) Subject() (valueobjects.TaskID, uservo.UserID) {
+	return e.TaskID, e.UserID
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"time"
+
+	"domain/task/valueobjects"
+)
+
+// TaskFilter narrows a user's task list; nil fields match every task
+type TaskFilter struct {
+	Status   *valueobjects.TaskStatus
+	Priority *valueobjects.TaskPriority
+	Tag      *valueobjects.TagName
+
+	// UpdatedSince matches tasks changed or trashed after it, including
+	// trashed ones, which are otherwise left out
+	UpdatedSince *time.Time
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "time"
+
+// TaskListVersion changes whenever any of a user's tasks is created, saved,
+// trashed, restored or permanently deleted, so it can stand in for the task
+// list when checking whether a client's copy is still current
+type TaskListVersion struct {
+	Count         int64      // all of the user's tasks, including trashed ones
+	LastUpdatedAt *time.Time // nil when the user has no tasks
+	LastDeletedAt *time.Time // nil when nothing is in the trash
+}
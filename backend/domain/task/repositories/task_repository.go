@@ -1,34 +1,126 @@
 package repositories
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"domain/task/entities"
 	"domain/task/valueobjects"
 	uservo "domain/user/valueobjects"
 )
 
+// ErrTaskVersionConflict is returned by Update when the task was saved by
+// someone else since it was loaded
+var ErrTaskVersionConflict = errors.New("task was modified by another request; reload it and try again")
+
 // TaskRepository defines the interface for task persistence
 type TaskRepository interface {
-	// Save persists a task entity
-	Save(task *entities.Task) error
+	// Save persists a new task entity and assigns it the generated ID
+	Save(ctx context.Context, task *entities.Task) error
 
 	// FindByID retrieves a task by its ID
-	FindByID(id valueobjects.TaskID) (*entities.Task, error)
+	FindByID(ctx context.Context, id valueobjects.TaskID) (*entities.Task, error)
+
+	// FindByUserID retrieves all tasks for a specific user in the given order
+	FindByUserID(ctx context.Context, userID uservo.UserID, sort TaskSort) ([]*entities.Task, error)
+
+	// FindFilteredByUserID retrieves up to limit of a user's tasks matching
+	// filter, in the given order, after skipping offset, along with the total
+	// number that match; a zero limit retrieves all of them
+	FindFilteredByUserID(ctx context.Context, userID uservo.UserID, filter TaskFilter, sort TaskSort, limit, offset int) ([]*entities.Task, int64, error)
+
+	// FindPageByUserID retrieves up to limit of a user's tasks with IDs above
+	// afterID, in ID order, so all of them can be read a page at a time
+	FindPageByUserID(ctx context.Context, userID uservo.UserID, afterID valueobjects.TaskID, limit int) ([]*entities.Task, error)
+
+	// LockUserTasks makes other transactions that call it for the same user wait
+	// until the current transaction ends, so counting a user's tasks and then
+	// adding one cannot race with another request doing the same
+	LockUserTasks(ctx context.Context, userID uservo.UserID) error
 
-	// FindByUserID retrieves all tasks for a specific user
-	FindByUserID(userID uservo.UserID) ([]*entities.Task, error)
+	// CountByUserID counts a user's tasks without loading them; trashed tasks are not counted
+	CountByUserID(ctx context.Context, userID uservo.UserID) (int64, error)
+
+	// CountByUserIDAndStatus counts a user's tasks in any of the given statuses
+	// without loading them; trashed tasks are not counted
+	CountByUserIDAndStatus(ctx context.Context, userID uservo.UserID, statuses ...valueobjects.TaskStatus) (int64, error)
 
 	// FindByUserIDAndStatus retrieves tasks by user and status
-	FindByUserIDAndStatus(userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error)
+	FindByUserIDAndStatus(ctx context.Context, userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error)
 
 	// FindByUserIDAndPriority retrieves tasks by user and priority
-	FindByUserIDAndPriority(userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error)
+	FindByUserIDAndPriority(ctx context.Context, userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error)
+
+	// FindByUserIDAndTag retrieves a user's tasks carrying the given tag in the given order
+	FindByUserIDAndTag(ctx context.Context, userID uservo.UserID, tag valueobjects.TagName, sort TaskSort) ([]*entities.Task, error)
+
+	// FindUpdatedSince retrieves a user's tasks changed or trashed after since,
+	// including trashed ones, in the given order
+	FindUpdatedSince(ctx context.Context, userID uservo.UserID, since time.Time, sort TaskSort) ([]*entities.Task, error)
+
+	// SearchByText retrieves a user's tasks whose title or description contains
+	// the query (case-insensitive), most recently updated first
+	SearchByText(ctx context.Context, userID uservo.UserID, query string) ([]*entities.Task, error)
+
+	// FindOverdueByUserID retrieves a user's pending tasks due before now,
+	// earliest due date first
+	FindOverdueByUserID(ctx context.Context, userID uservo.UserID, now time.Time) ([]*entities.Task, error)
+
+	// FindDueSoonByUserID retrieves a user's pending tasks due between now and
+	// until, inclusive, earliest due date first
+	FindDueSoonByUserID(ctx context.Context, userID uservo.UserID, now, until time.Time) ([]*entities.Task, error)
 
-	// Update updates an existing task
-	Update(task *entities.Task) error
+	// GetStatsByUserID counts a user's tasks by status and priority, treating
+	// pending tasks due before now as overdue
+	GetStatsByUserID(ctx context.Context, userID uservo.UserID, now time.Time) (TaskStats, error)
 
-	// Delete removes a task by ID
-	Delete(id valueobjects.TaskID) error
+	// GetListVersionByUserID summarises a user's tasks with one aggregate query,
+	// without loading them
+	GetListVersionByUserID(ctx context.Context, userID uservo.UserID) (TaskListVersion, error)
+
+	// Update saves an existing task if it is still at the version it was
+	// loaded at, returning ErrTaskVersionConflict otherwise, and advances
+	// the task's version
+	Update(ctx context.Context, task *entities.Task) error
+
+	// UpdateBatch updates several tasks atomically; if any update fails none are applied
+	UpdateBatch(ctx context.Context, tasks []*entities.Task) error
+
+	// AttachTags links tags to a task, creating any the owner does not have yet;
+	// tags already on the task are left untouched
+	AttachTags(ctx context.Context, taskID valueobjects.TaskID, tags []*entities.Tag) error
+
+	// DetachTags unlinks tags from a task; the tags themselves are kept
+	DetachTags(ctx context.Context, taskID valueobjects.TaskID, tags []*entities.Tag) error
+
+	// Delete moves a task to the trash; it can be restored until permanently deleted
+	Delete(ctx context.Context, id valueobjects.TaskID) error
+
+	// FindDeletedByID retrieves a task from the trash, or nil if it is not trashed
+	FindDeletedByID(ctx context.Context, id valueobjects.TaskID) (*entities.Task, error)
+
+	// FindDeletedByUserID retrieves a user's trashed tasks, most recently deleted first
+	FindDeletedByUserID(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error)
+
+	// Restore moves a task out of the trash
+	Restore(ctx context.Context, id valueobjects.TaskID) error
+
+	// DeletePermanently removes a task and its tag links, whether or not it is trashed
+	DeletePermanently(ctx context.Context, id valueobjects.TaskID) error
 
 	// ExistsByID checks if a task exists by ID
-	ExistsByID(id valueobjects.TaskID) (bool, error)
+	ExistsByID(ctx context.Context, id valueobjects.TaskID) (bool, error)
+
+	// ExistsByIDAndUser checks if a task outside the trash exists and belongs
+	// to the user, without loading it
+	ExistsByIDAndUser(ctx context.Context, id valueobjects.TaskID, userID uservo.UserID) (bool, error)
+
+	// RecordActivity stores entries in tasks' histories and assigns their IDs
+	RecordActivity(ctx context.Context, activities []*entities.TaskActivity) error
+
+	// FindActivityByTaskID retrieves up to limit entries of a task's history
+	// after skipping offset, newest first, along with the total number of
+	// entries; entries remain after the task is deleted
+	FindActivityByTaskID(ctx context.Context, taskID valueobjects.TaskID, limit, offset int) ([]*entities.TaskActivity, int64, error)
 }
\ No newline at end of file
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidTaskSort is returned when a sort field or order is not supported
+var ErrInvalidTaskSort = errors.New("invalid task sort")
+
+// TaskSortField identifies a field tasks can be ordered by
+type TaskSortField string
+
+const (
+	TaskSortByCreatedAt TaskSortField = "created_at"
+	TaskSortByUpdatedAt TaskSortField = "updated_at"
+	TaskSortByPriority  TaskSortField = "priority"
+	TaskSortByTitle     TaskSortField = "title"
+)
+
+// SortOrder is the direction of a sort
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// TaskSort describes how task lists are ordered; the zero value keeps insertion order
+type TaskSort struct {
+	Field TaskSortField
+	Order SortOrder
+}
+
+// NewTaskSort validates a sort field and order. An empty field means no sorting
+// and an empty order defaults to ascending.
+func NewTaskSort(field, order string) (TaskSort, error) {
+	sort := TaskSort{
+		Field: TaskSortField(field),
+		Order: SortOrder(order),
+	}
+
+	switch sort.Field {
+	case "", TaskSortByCreatedAt, TaskSortByUpdatedAt, TaskSortByPriority, TaskSortByTitle:
+	default:
+		return TaskSort{}, fmt.Errorf("%w: sort must be one of created_at, updated_at, priority, title", ErrInvalidTaskSort)
+	}
+
+	switch sort.Order {
+	case "":
+		sort.Order = SortAscending
+	case SortAscending, SortDescending:
+	default:
+		return TaskSort{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidTaskSort)
+	}
+
+	return sort, nil
+}
+
+// IsZero reports whether no sort field is set
+func (s TaskSort) IsZero() bool {
+	return s.Field == ""
+}
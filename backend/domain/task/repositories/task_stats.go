@@ -0,0 +1,23 @@
+package repositories
+
+// TaskStatusCounts holds the number of tasks in each status
+type TaskStatusCounts struct {
+	Pending   int64
+	Completed int64
+	Archived  int64
+}
+
+// TaskPriorityCounts holds the number of tasks at each priority
+type TaskPriorityCounts struct {
+	Low    int64
+	Medium int64
+	High   int64
+}
+
+// TaskStats summarises a user's tasks
+type TaskStats struct {
+	Total      int64
+	Overdue    int64 // pending tasks whose due date has passed
+	ByStatus   TaskStatusCounts
+	ByPriority TaskPriorityCounts
+}
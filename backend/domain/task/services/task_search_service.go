@@ -1,25 +1,35 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"strings"
+
 	"domain/task/entities"
 	"domain/task/repositories"
 	"domain/task/valueobjects"
 	uservo "domain/user/valueobjects"
 )
 
+// ErrEmptySearchQuery is returned when a text search is requested without a query
+var ErrEmptySearchQuery = errors.New("search query cannot be empty")
+
 // TaskSearchService provides domain search logic for tasks
 type TaskSearchService interface {
 	// FindTasksByStatus retrieves tasks by user and status
-	FindTasksByStatus(userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error)
+	FindTasksByStatus(ctx context.Context, userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error)
 
 	// FindTasksByPriority retrieves tasks by user and priority
-	FindTasksByPriority(userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error)
+	FindTasksByPriority(ctx context.Context, userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error)
 
 	// FindActiveTasksForUser retrieves all non-archived tasks for a user
-	FindActiveTasksForUser(userID uservo.UserID) ([]*entities.Task, error)
+	FindActiveTasksForUser(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error)
 
 	// FindCompletedTasksForUser retrieves completed tasks for a user
-	FindCompletedTasksForUser(userID uservo.UserID) ([]*entities.Task, error)
+	FindCompletedTasksForUser(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error)
+
+	// SearchByText retrieves a user's tasks whose title or description matches the query
+	SearchByText(ctx context.Context, userID uservo.UserID, query string) ([]*entities.Task, error)
 }
 
 // taskSearchService implements TaskSearchService
@@ -35,18 +45,18 @@ func NewTaskSearchService(taskRepo repositories.TaskRepository) TaskSearchServic
 }
 
 // FindTasksByStatus retrieves tasks filtered by status for a specific user
-func (s *taskSearchService) FindTasksByStatus(userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error) {
-	return s.taskRepo.FindByUserIDAndStatus(userID, status)
+func (s *taskSearchService) FindTasksByStatus(ctx context.Context, userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error) {
+	return s.taskRepo.FindByUserIDAndStatus(ctx, userID, status)
 }
 
 // FindTasksByPriority retrieves tasks filtered by priority for a specific user
-func (s *taskSearchService) FindTasksByPriority(userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error) {
-	return s.taskRepo.FindByUserIDAndPriority(userID, priority)
+func (s *taskSearchService) FindTasksByPriority(ctx context.Context, userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error) {
+	return s.taskRepo.FindByUserIDAndPriority(ctx, userID, priority)
 }
 
 // FindActiveTasksForUser retrieves all tasks that are not archived
-func (s *taskSearchService) FindActiveTasksForUser(userID uservo.UserID) ([]*entities.Task, error) {
-	allTasks, err := s.taskRepo.FindByUserID(userID)
+func (s *taskSearchService) FindActiveTasksForUser(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error) {
+	allTasks, err := s.taskRepo.FindByUserID(ctx, userID, repositories.TaskSort{})
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +73,17 @@ func (s *taskSearchService) FindActiveTasksForUser(userID uservo.UserID) ([]*ent
 }
 
 // FindCompletedTasksForUser retrieves only completed tasks
-func (s *taskSearchService) FindCompletedTasksForUser(userID uservo.UserID) ([]*entities.Task, error) {
+func (s *taskSearchService) FindCompletedTasksForUser(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error) {
 	completedStatus := valueobjects.NewCompletedStatus()
-	return s.taskRepo.FindByUserIDAndStatus(userID, completedStatus)
-}
\ No newline at end of file
+	return s.taskRepo.FindByUserIDAndStatus(ctx, userID, completedStatus)
+}
+
+// SearchByText retrieves tasks whose title or description contains the query, ignoring case
+func (s *taskSearchService) SearchByText(ctx context.Context, userID uservo.UserID, query string) ([]*entities.Task, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	return s.taskRepo.SearchByText(ctx, userID, query)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
 	"domain/task/valueobjects"
 	uservo "domain/user/valueobjects"
@@ -22,6 +23,42 @@ type TaskUpdates struct {
 	Description *valueobjects.TaskDescription
 	Status      *valueobjects.TaskStatus
 	Priority    *valueobjects.TaskPriority
+
+	// Restore must be set to move an archived task back to pending
+	Restore bool
+}
+
+// ErrIllegalStatusTransition is returned for a status change the task lifecycle does not allow
+var ErrIllegalStatusTransition = errors.New("illegal task status transition")
+
+// allowedStatusTransitions lists the status changes allowed without a restore;
+// keeping the current status is always allowed
+var allowedStatusTransitions = map[string][]string{
+	valueobjects.StatusPending:   {valueobjects.StatusCompleted, valueobjects.StatusArchived},
+	valueobjects.StatusCompleted: {valueobjects.StatusArchived, valueobjects.StatusPending},
+}
+
+// ValidateStatusTransition checks that a task may move from one status to
+// another; archived tasks can only be restored to pending, and only when restore is set
+func ValidateStatusTransition(from, to valueobjects.TaskStatus, restore bool) error {
+	if from.IsArchived() {
+		if restore && to.IsPending() {
+			return nil
+		}
+		return fmt.Errorf("%w: archived tasks can only be restored to pending", ErrIllegalStatusTransition)
+	}
+
+	if from.Equals(to) {
+		return nil
+	}
+
+	for _, allowed := range allowedStatusTransitions[from.Value()] {
+		if to.Value() == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: cannot move task from %s to %s", ErrIllegalStatusTransition, from, to)
 }
 
 // taskValidationService implements TaskValidationService
@@ -47,18 +84,24 @@ func (s *taskValidationService) ValidateTaskCreation(title valueobjects.TaskTitl
 
 // ValidateTaskUpdate validates task update business rules
 func (s *taskValidationService) ValidateTaskUpdate(currentStatus valueobjects.TaskStatus, updates TaskUpdates) error {
-	// Cannot modify archived tasks (except to unarchive)
+	// Cannot modify archived tasks (except to restore them)
 	if currentStatus.IsArchived() {
-		if updates.Status == nil || !updates.Status.IsPending() {
-			return errors.New("archived tasks can only be updated to pending status")
+		if updates.Status == nil {
+			return fmt.Errorf("%w: archived tasks must be restored before they can be changed", ErrIllegalStatusTransition)
 		}
 
-		// For archived tasks being unarchived, only status change is allowed
+		// For archived tasks being restored, only status change is allowed
 		if updates.Title != nil || updates.Description != nil || updates.Priority != nil {
 			return errors.New("archived tasks can only have status updated to pending")
 		}
 	}
 
+	if updates.Status != nil {
+		if err := ValidateStatusTransition(currentStatus, *updates.Status, updates.Restore); err != nil {
+			return err
+		}
+	}
+
 	// Priority can only be changed on pending tasks
 	if updates.Priority != nil && !currentStatus.IsPending() {
 		return errors.New("task priority can only be changed on pending tasks")
@@ -0,0 +1,74 @@
+package valueobjects
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDueDateOutOfRange is returned when a due date falls outside the allowed bounds
+var ErrDueDateOutOfRange = errors.New("due date out of range")
+
+// Default due date bounds relative to now
+const (
+	DefaultDueDateMinOffset = -24 * time.Hour
+	DefaultDueDateMaxOffset = 10 * 365 * 24 * time.Hour
+)
+
+// DueDateBounds defines the allowed window for due dates relative to now.
+// A negative MinOffset allows due dates in the past (e.g. for backfilling).
+type DueDateBounds struct {
+	MinOffset time.Duration
+	MaxOffset time.Duration
+}
+
+// DefaultDueDateBounds returns the default due date bounds
+func DefaultDueDateBounds() DueDateBounds {
+	return DueDateBounds{
+		MinOffset: DefaultDueDateMinOffset,
+		MaxOffset: DefaultDueDateMaxOffset,
+	}
+}
+
+// DueDate represents a task due date with validation
+type DueDate struct {
+	value time.Time
+}
+
+// NewDueDate creates a new DueDate, validating it against the given bounds
+func NewDueDate(dueDate time.Time, bounds DueDateBounds) (DueDate, error) {
+	return newDueDateAt(dueDate, bounds, time.Now())
+}
+
+func newDueDateAt(dueDate time.Time, bounds DueDateBounds, now time.Time) (DueDate, error) {
+	if dueDate.IsZero() {
+		return DueDate{}, errors.New("due date cannot be empty")
+	}
+
+	earliest := now.Add(bounds.MinOffset)
+	if dueDate.Before(earliest) {
+		return DueDate{}, fmt.Errorf("%w: must not be before %s", ErrDueDateOutOfRange, earliest.UTC().Format(time.RFC3339))
+	}
+
+	latest := now.Add(bounds.MaxOffset)
+	if dueDate.After(latest) {
+		return DueDate{}, fmt.Errorf("%w: must not be after %s", ErrDueDateOutOfRange, latest.UTC().Format(time.RFC3339))
+	}
+
+	return DueDate{value: dueDate}, nil
+}
+
+// Value returns the underlying time value
+func (d DueDate) Value() time.Time {
+	return d.value
+}
+
+// Equals checks if two DueDates are equal
+func (d DueDate) Equals(other DueDate) bool {
+	return d.value.Equal(other.value)
+}
+
+// String returns the string representation of the DueDate
+func (d DueDate) String() string {
+	return d.value.Format(time.RFC3339)
+}
@@ -0,0 +1,52 @@
+package valueobjects
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDueDate_NewDueDate(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	bounds := DueDateBounds{
+		MinOffset: -24 * time.Hour,
+		MaxOffset: 365 * 24 * time.Hour,
+	}
+
+	t.Run("should accept a near-future due date", func(t *testing.T) {
+		value := now.Add(48 * time.Hour)
+		dueDate, err := newDueDateAt(value, bounds, now)
+		assert.NoError(t, err)
+		assert.True(t, dueDate.Value().Equal(value))
+	})
+
+	t.Run("should reject a due date beyond the max future bound", func(t *testing.T) {
+		_, err := newDueDateAt(now.Add(2*365*24*time.Hour), bounds, now)
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDueDateOutOfRange))
+	})
+
+	t.Run("should accept a past due date within the min bound", func(t *testing.T) {
+		_, err := newDueDateAt(now.Add(-time.Hour), bounds, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a due date before the min bound", func(t *testing.T) {
+		_, err := newDueDateAt(now.Add(-48*time.Hour), bounds, now)
+		assert.True(t, errors.Is(err, ErrDueDateOutOfRange))
+	})
+
+	t.Run("should reject past due dates when min offset is zero", func(t *testing.T) {
+		strict := DueDateBounds{MinOffset: 0, MaxOffset: bounds.MaxOffset}
+		_, err := newDueDateAt(now.Add(-time.Minute), strict, now)
+		assert.True(t, errors.Is(err, ErrDueDateOutOfRange))
+	})
+
+	t.Run("should reject zero time", func(t *testing.T) {
+		_, err := newDueDateAt(time.Time{}, bounds, now)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrDueDateOutOfRange))
+	})
+}
@@ -0,0 +1,66 @@
+package valueobjects
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxTagNameLength is the maximum length of a normalized tag name
+const MaxTagNameLength = 50
+
+// TagName represents a normalized (trimmed, lowercased) tag name
+type TagName struct {
+	value string
+}
+
+// NewTagName creates a new TagName, normalizing and validating the input
+func NewTagName(name string) (TagName, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" {
+		return TagName{}, errors.New("tag name cannot be empty")
+	}
+
+	if len(name) > MaxTagNameLength {
+		return TagName{}, fmt.Errorf("tag name too long: maximum %d characters, got %d", MaxTagNameLength, len(name))
+	}
+
+	return TagName{value: name}, nil
+}
+
+// NewTagNames normalizes a list of tag names, dropping duplicates while
+// keeping the first occurrence order
+func NewTagNames(names []string) ([]TagName, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]TagName, 0, len(names))
+
+	for _, name := range names {
+		tag, err := NewTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[tag.value] {
+			continue
+		}
+		seen[tag.value] = true
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// Value returns the underlying tag name
+func (t TagName) Value() string {
+	return t.value
+}
+
+// Equals checks if two TagNames are equal
+func (t TagName) Equals(other TagName) bool {
+	return t.value == other.value
+}
+
+// String returns the string representation of the TagName
+func (t TagName) String() string {
+	return t.value
+}
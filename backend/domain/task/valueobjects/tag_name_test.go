@@ -0,0 +1,45 @@
+package valueobjects
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagName_NewTagName(t *testing.T) {
+	t.Run("should trim and lowercase the name", func(t *testing.T) {
+		tag, err := NewTagName("  Work ")
+		require.NoError(t, err)
+		assert.Equal(t, "work", tag.Value())
+	})
+
+	t.Run("should reject a blank name", func(t *testing.T) {
+		_, err := NewTagName("   ")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a name over the maximum length", func(t *testing.T) {
+		_, err := NewTagName(strings.Repeat("a", MaxTagNameLength+1))
+		assert.Error(t, err)
+	})
+}
+
+func TestTagName_NewTagNames(t *testing.T) {
+	t.Run("should deduplicate after normalization keeping first order", func(t *testing.T) {
+		tags, err := NewTagNames([]string{"Work", "home", " WORK", "urgent", "Home"})
+		require.NoError(t, err)
+
+		values := make([]string, len(tags))
+		for i, tag := range tags {
+			values[i] = tag.Value()
+		}
+		assert.Equal(t, []string{"work", "home", "urgent"}, values)
+	})
+
+	t.Run("should reject the list if any name is invalid", func(t *testing.T) {
+		_, err := NewTagNames([]string{"work", ""})
+		assert.Error(t, err)
+	})
+}
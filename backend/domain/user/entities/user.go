@@ -7,6 +7,13 @@ import (
 	"domain/user/valueobjects"
 )
 
+// Email verification errors
+var (
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrInvalidVerificationToken = errors.New("verification token is invalid")
+	ErrVerificationTokenExpired = errors.New("verification token has expired")
+)
+
 // User represents a domain entity for user management
 type User struct {
 	id          valueobjects.UserID
@@ -15,19 +22,21 @@ type User struct {
 	preferences valueobjects.UserPreferences
 	createdAt   time.Time
 	updatedAt   time.Time
+
+	// Credentials and email ownership
+	passwordHash      string
+	emailVerified     bool
+	verificationToken valueobjects.VerificationToken
 }
 
-// NewUser creates a new User entity
+// NewUser creates a new User entity; the ID is zero for a user that has not
+// been persisted yet and is assigned by the repository on Save
 func NewUser(
 	id valueobjects.UserID,
 	email valueobjects.Email,
 	profile valueobjects.UserProfile,
 	preferences valueobjects.UserPreferences,
 ) (*User, error) {
-	if id.IsZero() {
-		return nil, errors.New("user ID cannot be zero")
-	}
-
 	if email.IsEmpty() {
 		return nil, errors.New("user email cannot be empty")
 	}
@@ -54,6 +63,20 @@ func NewUserWithDefaults(
 	return NewUser(id, email, profile, defaultPreferences)
 }
 
+// AssignID sets the ID generated when a new user is first persisted
+func (u *User) AssignID(id valueobjects.UserID) error {
+	if !u.id.IsZero() {
+		return errors.New("user ID is already assigned")
+	}
+
+	if id.IsZero() {
+		return errors.New("user ID cannot be zero")
+	}
+
+	u.id = id
+	return nil
+}
+
 // UpdateProfile updates the user profile
 func (u *User) UpdateProfile(profile valueobjects.UserProfile) error {
 	u.profile = profile
@@ -79,6 +102,58 @@ func (u *User) ChangeEmail(email valueobjects.Email) error {
 	return nil
 }
 
+// SetPasswordHash sets the hash of the user's password
+func (u *User) SetPasswordHash(hash string) error {
+	if hash == "" {
+		return errors.New("password hash cannot be empty")
+	}
+
+	u.passwordHash = hash
+	u.updatedAt = time.Now()
+	return nil
+}
+
+// IssueEmailVerification creates a new verification token valid for ttl,
+// replacing any earlier one
+func (u *User) IssueEmailVerification(now time.Time, ttl time.Duration) (valueobjects.VerificationToken, error) {
+	if u.emailVerified {
+		return valueobjects.VerificationToken{}, ErrEmailAlreadyVerified
+	}
+
+	token, err := valueobjects.NewVerificationToken(now, ttl)
+	if err != nil {
+		return valueobjects.VerificationToken{}, err
+	}
+
+	u.verificationToken = token
+	u.updatedAt = now
+	return token, nil
+}
+
+// VerifyEmail marks the email as verified if token matches the issued one and
+// has not expired; the token is consumed so it cannot be used again
+func (u *User) VerifyEmail(token string, now time.Time) error {
+	if !u.verificationToken.Matches(token) {
+		return ErrInvalidVerificationToken
+	}
+
+	if u.verificationToken.IsExpired(now) {
+		return ErrVerificationTokenExpired
+	}
+
+	u.emailVerified = true
+	u.verificationToken = valueobjects.VerificationToken{}
+	u.updatedAt = now
+	return nil
+}
+
+// LoadCredentials restores the stored password hash and email verification state
+func (u *User) LoadCredentials(passwordHash string, emailVerified bool, token valueobjects.VerificationToken) {
+	u.passwordHash = passwordHash
+	u.emailVerified = emailVerified
+	u.verificationToken = token
+}
+
 // GetDisplayName returns the user's display name from profile
 func (u *User) GetDisplayName() string {
 	return u.profile.DisplayName()
@@ -160,4 +235,19 @@ func (u *User) CreatedAt() time.Time {
 // UpdatedAt returns the last update time
 func (u *User) UpdatedAt() time.Time {
 	return u.updatedAt
+}
+
+// PasswordHash returns the hash of the user's password, or "" if none is set
+func (u *User) PasswordHash() string {
+	return u.passwordHash
+}
+
+// IsEmailVerified returns true once the user has proven they own their email
+func (u *User) IsEmailVerified() bool {
+	return u.emailVerified
+}
+
+// VerificationToken returns the outstanding email verification token, if any
+func (u *User) VerificationToken() valueobjects.VerificationToken {
+	return u.verificationToken
 }
\ No newline at end of file
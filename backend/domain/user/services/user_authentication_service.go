@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -9,6 +10,9 @@ import (
 	"domain/user/valueobjects"
 )
 
+// ErrEmailAlreadyExists is returned when registering an email address that is already taken
+var ErrEmailAlreadyExists = errors.New("email address is already registered")
+
 // UserCredentials represents user authentication credentials
 type UserCredentials struct {
 	UserID         valueobjects.UserID
@@ -20,13 +24,13 @@ type UserCredentials struct {
 // UserAuthenticationService provides domain authentication logic for users
 type UserAuthenticationService interface {
 	// ValidateEmailUniqueness ensures email is unique across the system
-	ValidateEmailUniqueness(email valueobjects.Email) error
+	ValidateEmailUniqueness(ctx context.Context, email valueobjects.Email) error
 
 	// GenerateUserCredentials creates authentication credentials for a new user
 	GenerateUserCredentials(email valueobjects.Email) (*UserCredentials, error)
 
 	// ValidateRegistrationData validates all data required for user registration
-	ValidateRegistrationData(email valueobjects.Email, profile valueobjects.UserProfile) error
+	ValidateRegistrationData(ctx context.Context, email valueobjects.Email, profile valueobjects.UserProfile) error
 }
 
 // userAuthenticationService implements UserAuthenticationService
@@ -42,14 +46,14 @@ func NewUserAuthenticationService(userRepo repositories.UserRepository) UserAuth
 }
 
 // ValidateEmailUniqueness ensures the email is not already taken
-func (s *userAuthenticationService) ValidateEmailUniqueness(email valueobjects.Email) error {
-	exists, err := s.userRepo.ExistsByEmail(email)
+func (s *userAuthenticationService) ValidateEmailUniqueness(ctx context.Context, email valueobjects.Email) error {
+	exists, err := s.userRepo.ExistsByEmail(ctx, email)
 	if err != nil {
 		return err
 	}
 
 	if exists {
-		return errors.New("email address is already registered")
+		return ErrEmailAlreadyExists
 	}
 
 	return nil
@@ -82,9 +86,9 @@ func (s *userAuthenticationService) GenerateUserCredentials(email valueobjects.E
 }
 
 // ValidateRegistrationData validates all data required for user registration
-func (s *userAuthenticationService) ValidateRegistrationData(email valueobjects.Email, profile valueobjects.UserProfile) error {
+func (s *userAuthenticationService) ValidateRegistrationData(ctx context.Context, email valueobjects.Email, profile valueobjects.UserProfile) error {
 	// Validate email uniqueness
-	if err := s.ValidateEmailUniqueness(email); err != nil {
+	if err := s.ValidateEmailUniqueness(ctx, email); err != nil {
 		return err
 	}
 
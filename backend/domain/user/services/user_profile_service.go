@@ -1,13 +1,17 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"time"
+	"fmt"
 
 	"domain/user/repositories"
 	"domain/user/valueobjects"
 )
 
+// ErrInvalidProfile is returned when profile updates fail validation
+var ErrInvalidProfile = errors.New("invalid profile")
+
 // ProfileUpdateData represents data for profile updates
 type ProfileUpdateData struct {
 	FirstName *string
@@ -18,13 +22,13 @@ type ProfileUpdateData struct {
 // UserProfileService provides domain profile management logic for users
 type UserProfileService interface {
 	// UpdateProfile updates user profile information with validation
-	UpdateProfile(userID valueobjects.UserID, profile valueobjects.UserProfile) error
+	UpdateProfile(ctx context.Context, userID valueobjects.UserID, profile valueobjects.UserProfile) error
 
 	// ValidateProfileData validates profile data before updates
 	ValidateProfileData(profile valueobjects.UserProfile) error
 
 	// UpdatePartialProfile updates only specified profile fields
-	UpdatePartialProfile(userID valueobjects.UserID, updates ProfileUpdateData) error
+	UpdatePartialProfile(ctx context.Context, userID valueobjects.UserID, updates ProfileUpdateData) error
 
 	// ValidateTimezoneChange validates timezone changes for consistency
 	ValidateTimezoneChange(userID valueobjects.UserID, newTimezone string) error
@@ -43,14 +47,14 @@ func NewUserProfileService(userRepo repositories.UserRepository) UserProfileServ
 }
 
 // UpdateProfile updates the complete user profile
-func (s *userProfileService) UpdateProfile(userID valueobjects.UserID, profile valueobjects.UserProfile) error {
+func (s *userProfileService) UpdateProfile(ctx context.Context, userID valueobjects.UserID, profile valueobjects.UserProfile) error {
 	// Validate the profile data
 	if err := s.ValidateProfileData(profile); err != nil {
 		return err
 	}
 
 	// Retrieve the user
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -65,7 +69,7 @@ func (s *userProfileService) UpdateProfile(userID valueobjects.UserID, profile v
 	}
 
 	// Save the updated user
-	return s.userRepo.Update(user)
+	return s.userRepo.Update(ctx, user)
 }
 
 // ValidateProfileData validates profile data for business rules
@@ -90,9 +94,9 @@ func (s *userProfileService) ValidateProfileData(profile valueobjects.UserProfil
 }
 
 // UpdatePartialProfile updates only specified profile fields
-func (s *userProfileService) UpdatePartialProfile(userID valueobjects.UserID, updates ProfileUpdateData) error {
+func (s *userProfileService) UpdatePartialProfile(ctx context.Context, userID valueobjects.UserID, updates ProfileUpdateData) error {
 	// Retrieve the current user
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -123,24 +127,23 @@ func (s *userProfileService) UpdatePartialProfile(userID valueobjects.UserID, up
 	// Create new profile with validation
 	newProfile, err := valueobjects.NewUserProfile(firstName, lastName, timezone)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrInvalidProfile, err)
 	}
 
 	// Update the user
 	if err := user.UpdateProfile(newProfile); err != nil {
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidProfile, err)
 	}
 
 	// Save the updated user
-	return s.userRepo.Update(user)
+	return s.userRepo.Update(ctx, user)
 }
 
 // ValidateTimezoneChange validates timezone changes for business consistency
 func (s *userProfileService) ValidateTimezoneChange(userID valueobjects.UserID, newTimezone string) error {
 	// Validate that the timezone is valid
-	_, err := time.LoadLocation(newTimezone)
-	if err != nil {
-		return errors.New("invalid timezone: must be a valid IANA timezone identifier")
+	if _, err := valueobjects.LoadTimezone(newTimezone); err != nil {
+		return err
 	}
 
 	// Additional business rules for timezone changes can be added here
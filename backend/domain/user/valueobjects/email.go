@@ -11,14 +11,19 @@ type Email struct {
 	value string
 }
 
+// NormalizeEmail trims spaces and lowercases an email address, the form in
+// which emails are stored and matched
+func NormalizeEmail(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
 // NewEmail creates a new Email value object with validation
 func NewEmail(value string) (Email, error) {
 	if value == "" {
 		return Email{}, errors.New("email cannot be empty")
 	}
 
-	// Normalize email by trimming spaces and converting to lowercase
-	normalizedValue := strings.ToLower(strings.TrimSpace(value))
+	normalizedValue := NormalizeEmail(value)
 
 	// Validate email format using Go's mail package
 	if _, err := mail.ParseAddress(normalizedValue); err != nil {
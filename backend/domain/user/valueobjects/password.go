@@ -0,0 +1,48 @@
+package valueobjects
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrWeakPassword is wrapped by the errors returned for passwords that fail the strength rules
+var ErrWeakPassword = errors.New("password is too weak")
+
+// Password length limits; bcrypt ignores bytes past MaxPasswordBytes
+const (
+	MinPasswordLength = 8
+	MaxPasswordBytes  = 72
+)
+
+// ValidatePassword checks a new password: 8 to 72 bytes with at least one
+// letter and one digit. Every way of setting a password uses these rules.
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return weakPassword("must be at least 8 characters")
+	}
+	if len(password) > MaxPasswordBytes {
+		return weakPassword("must be at most 72 bytes")
+	}
+	if !strings.ContainsFunc(password, unicode.IsLetter) || !strings.ContainsFunc(password, unicode.IsDigit) {
+		return weakPassword("must contain a letter and a digit")
+	}
+	return nil
+}
+
+// weakPassword returns an ErrWeakPassword explaining which rule failed
+func weakPassword(rule string) error {
+	return &weakPasswordError{rule: rule}
+}
+
+type weakPasswordError struct {
+	rule string
+}
+
+func (e *weakPasswordError) Error() string {
+	return "password " + e.rule
+}
+
+func (e *weakPasswordError) Unwrap() error {
+	return ErrWeakPassword
+}
@@ -7,6 +7,12 @@ import (
 	"time"
 )
 
+// ErrInvalidTimezone is returned for timezones that are not IANA identifiers
+var ErrInvalidTimezone = errors.New("invalid timezone: must be a valid IANA timezone identifier")
+
+// DefaultTimezone is used for profiles that have never set a timezone
+const DefaultTimezone = "UTC"
+
 // UserProfile represents user profile information value object
 type UserProfile struct {
 	firstName string
@@ -62,13 +68,22 @@ func validateTimezone(timezone string) error {
 		return errors.New("timezone cannot be empty")
 	}
 
-	// Try to load the timezone to validate it
-	_, err := time.LoadLocation(timezone)
-	if err != nil {
-		return errors.New("invalid timezone: must be a valid IANA timezone identifier")
+	_, err := LoadTimezone(timezone)
+	return err
+}
+
+// LoadTimezone loads an IANA timezone such as "Europe/Berlin". Unlike
+// time.LoadLocation it rejects "Local", which would mean the server's zone.
+func LoadTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, ErrInvalidTimezone
 	}
 
-	return nil
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return location, nil
 }
 
 // FirstName returns the first name
@@ -86,6 +101,15 @@ func (p UserProfile) Timezone() string {
 	return p.timezone
 }
 
+// Location returns the profile's timezone as a location for converting times
+func (p UserProfile) Location() *time.Location {
+	location, err := LoadTimezone(p.timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
 // FullName returns the full name (first + last)
 func (p UserProfile) FullName() string {
 	return p.firstName + " " + p.lastName
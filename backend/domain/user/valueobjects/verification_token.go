@@ -0,0 +1,65 @@
+package valueobjects
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+// verificationTokenBytes is the amount of randomness in a verification token
+const verificationTokenBytes = 32
+
+// VerificationToken is a single-use secret proving ownership of an email address
+type VerificationToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewVerificationToken generates a cryptographically random token valid for ttl from now
+func NewVerificationToken(now time.Time, ttl time.Duration) (VerificationToken, error) {
+	if ttl <= 0 {
+		return VerificationToken{}, errors.New("verification token lifetime must be positive")
+	}
+
+	bytes := make([]byte, verificationTokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return VerificationToken{}, err
+	}
+
+	return VerificationToken{
+		value:     base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(bytes),
+		expiresAt: now.Add(ttl),
+	}, nil
+}
+
+// RestoreVerificationToken rebuilds a previously issued token from storage
+func RestoreVerificationToken(value string, expiresAt time.Time) VerificationToken {
+	return VerificationToken{value: value, expiresAt: expiresAt}
+}
+
+// Value returns the token string sent to the user
+func (t VerificationToken) Value() string {
+	return t.value
+}
+
+// ExpiresAt returns when the token stops being accepted
+func (t VerificationToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// IsZero checks if no token has been issued
+func (t VerificationToken) IsZero() bool {
+	return t.value == ""
+}
+
+// IsExpired checks if the token is no longer accepted at now
+func (t VerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// Matches compares a presented token in constant time
+func (t VerificationToken) Matches(value string) bool {
+	return !t.IsZero() && subtle.ConstantTimeCompare([]byte(t.value), []byte(value)) == 1
+}
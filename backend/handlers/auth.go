@@ -1,11 +1,20 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	"domain/auth/entities"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/audit"
 	"todo-app/services/auth"
+	userservice "todo-app/services/user"
 )
 
 // AuthHandler handles authentication-related HTTP requests
@@ -14,28 +23,52 @@ type AuthHandler struct {
 	oauthService   *auth.OAuthService
 	sessionService *auth.SessionService
 	jwtService     *auth.JWTService
+	redirectURIs   *entities.RedirectURIValidator
+	auditService   *audit.AuditService
+
+	// errorRedirectURL is the frontend page failed OAuth callbacks are sent to
+	errorRedirectURL string
 }
 
-// NewAuthHandler creates a new authentication handler
+// NewAuthHandler creates a new authentication handler; redirectURIs should be
+// the validator the OAuth service was created with, and failed callbacks are
+// sent to errorRedirectURL
 func NewAuthHandler(
 	googleConfig *auth.GoogleOAuthConfig,
 	oauthService *auth.OAuthService,
 	sessionService *auth.SessionService,
 	jwtService *auth.JWTService,
+	redirectURIs *entities.RedirectURIValidator,
+	errorRedirectURL string,
 ) *AuthHandler {
 	return &AuthHandler{
 		googleConfig:   googleConfig,
 		oauthService:   oauthService,
 		sessionService: sessionService,
 		jwtService:     jwtService,
+		redirectURIs:   redirectURIs,
+
+		errorRedirectURL: errorRedirectURL,
 	}
 }
 
+// SetAuditService enables audit logging of sign-ins and sign-outs
+func (h *AuthHandler) SetAuditService(auditService *audit.AuditService) {
+	h.auditService = auditService
+}
+
 // GoogleLogin initiates the Google OAuth flow
 // GET /auth/google/login
 func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 	// Get optional redirect URI from query parameter
 	redirectURI := c.DefaultQuery("redirect_uri", "http://localhost:3000/dashboard")
+	if !h.redirectURIs.Allows(redirectURI) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_redirect_uri",
+			"message": "Redirect URI is not allowed",
+		})
+		return
+	}
 
 	// Initiate OAuth flow
 	result, err := h.oauthService.InitiateOAuthFlow(c.Request.Context(), redirectURI)
@@ -48,16 +81,8 @@ func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 		return
 	}
 
-	// Set state token as secure cookie
-	c.SetCookie(
-		"oauth_state",
-		result.StateToken,
-		300, // 5 minutes
-		"/",
-		"",
-		false, // Secure (should be true in production with HTTPS)
-		true,  // HttpOnly
-	)
+	// Set state token as secure cookie, valid for 5 minutes
+	h.sessionService.Cookies().SetOAuthStateCookie(c, result.StateToken, 300)
 
 	// Return authorization URL
 	c.JSON(http.StatusOK, gin.H{
@@ -68,52 +93,59 @@ func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 
 // GoogleCallback handles the OAuth callback from Google
 // GET /auth/google/callback
+// The browser arrives here from Google, so the result is a redirect: to the
+// redirect URI stored in the OAuth state on success, or to the frontend error
+// page with an error code. Pass response=json to get JSON responses instead.
 func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	// Get authorization code and state from query parameters
 	code := c.Query("code")
 	state := c.Query("state")
 	errorParam := c.Query("error")
 
-	// Check for OAuth errors
+	// Check for OAuth errors, e.g. the user denied consent
 	if errorParam != "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "oauth_error",
-			"message": "OAuth authorization failed",
-			"details": errorParam,
-		})
+		errorCode := "oauth_error"
+		if errorParam == "access_denied" {
+			errorCode = "access_denied"
+		}
+		h.callbackError(c, http.StatusBadRequest, errorCode, "OAuth authorization failed", errorParam)
 		return
 	}
 
 	// Validate required parameters
 	if code == "" || state == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_request",
-			"message": "Missing required parameters",
-		})
+		h.callbackError(c, http.StatusBadRequest, "invalid_request", "Missing required parameters", "")
 		return
 	}
 
 	// Verify state token from cookie
 	stateCookie, err := c.Cookie("oauth_state")
 	if err != nil || stateCookie != state {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_state",
-			"message": "Invalid or missing OAuth state",
-		})
+		h.callbackError(c, http.StatusBadRequest, "invalid_state", "Invalid or missing OAuth state", "")
 		return
 	}
 
 	// Clear state cookie
-	c.SetCookie("oauth_state", "", -1, "/", "", false, true)
+	h.sessionService.Cookies().ClearOAuthStateCookie(c)
 
 	// Process OAuth callback
 	result, err := h.oauthService.ProcessOAuthCallback(c.Request.Context(), code, state)
+	var confirmErr *userservice.LinkConfirmationRequiredError
+	if errors.As(err, &confirmErr) {
+		h.linkConfirmationRequired(c, confirmErr.Link)
+		return
+	}
+	if errors.Is(err, userservice.ErrEmailNotVerified) {
+		h.callbackError(c, http.StatusForbidden, "email_not_verified", "Google has not verified this email, so it cannot be linked to an existing account", "")
+		return
+	}
+	if errors.Is(err, userservice.ErrAccountAlreadyLinked) {
+		h.callbackError(c, http.StatusConflict, "account_already_linked", "This email is already linked to a different Google account", "")
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "oauth_callback_failed",
-			"message": "Failed to process OAuth callback",
-			"details": err.Error(),
-		})
+		log.Printf("Failed to process Google OAuth callback: %v", err)
+		h.callbackError(c, http.StatusInternalServerError, "oauth_callback_failed", "Failed to process OAuth callback", "")
 		return
 	}
 
@@ -125,10 +157,7 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		true, // isOAuth
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "token_generation_failed",
-			"message": "Failed to generate session token",
-		})
+		h.callbackError(c, http.StatusInternalServerError, "token_generation_failed", "Failed to generate session token", "")
 		return
 	}
 
@@ -136,24 +165,88 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	result.Session.SessionToken = jwtToken
 
 	// Set session cookie
-	c.SetCookie(
-		"session_token",
-		jwtToken,
-		86400, // 24 hours
-		"/",
-		"",
-		false, // Secure (should be true in production)
-		true,  // HttpOnly
-	)
+	h.sessionService.Cookies().SetSessionCookie(c, jwtToken, h.sessionService.GetSessionMaxAge())
+	middleware.IssueCSRFToken(c, h.sessionService, result.Session.ID)
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginSucceeded, result.User.ID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "google", "session_id": result.Session.ID, "new_user": result.IsNewUser}))
+
+	if c.Query("response") == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"success":      true,
+			"user":         result.User.ToResponse(),
+			"session":      result.Session.ToResponse(),
+			"is_new_user":  result.IsNewUser,
+			"redirect_uri": result.RedirectURI,
+		})
+		return
+	}
 
-	// Return success response
-	c.JSON(http.StatusOK, gin.H{
-		"success":      true,
-		"user":         result.User.ToResponse(),
-		"session":      result.Session.ToResponse(),
-		"is_new_user":  result.IsNewUser,
-		"redirect_uri": result.RedirectURI,
-	})
+	// The whitelist may have changed since the flow started
+	if !h.redirectURIs.Allows(result.RedirectURI) {
+		h.callbackError(c, http.StatusBadRequest, "invalid_redirect_uri", "Redirect URI is not allowed", "")
+		return
+	}
+
+	c.Redirect(http.StatusFound, withQuery(result.RedirectURI, url.Values{
+		"login":    {"success"},
+		"new_user": {strconv.FormatBool(result.IsNewUser)},
+	}))
+}
+
+// callbackError records the failed sign-in and reports it by redirecting to the
+// frontend error page with the error code, or as JSON when response=json is set
+func (h *AuthHandler) callbackError(c *gin.Context, status int, code, message, details string) {
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginFailed, 0, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "google", "reason": code}))
+
+	if c.Query("response") == "json" {
+		body := gin.H{
+			"error":   code,
+			"message": message,
+		}
+		if details != "" {
+			body["details"] = details
+		}
+		c.JSON(status, body)
+		return
+	}
+
+	c.Redirect(http.StatusFound, withQuery(h.errorRedirectURL, url.Values{"error": {code}}))
+}
+
+// linkConfirmationRequired reports that the Google account matched an existing
+// account by email and the link must be confirmed from a session of that account,
+// passing on the token to confirm it with
+func (h *AuthHandler) linkConfirmationRequired(c *gin.Context, link *entities.PendingAccountLink) {
+	if c.Query("response") == "json" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "link_confirmation_required",
+			"message":    "Sign in to your existing account to confirm linking this Google account",
+			"link_token": link.Token,
+			"expires_at": link.ExpiresAt,
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, withQuery(h.errorRedirectURL, url.Values{
+		"error":      {"link_confirmation_required"},
+		"link_token": {link.Token},
+	}))
+}
+
+// withQuery adds params to the query string of uri
+func withQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
 }
 
 // ValidateSession validates the current session
@@ -251,6 +344,10 @@ func (h *AuthHandler) RefreshSession(c *gin.Context) {
 	// Refresh OAuth tokens if needed
 	session, err := h.oauthService.RefreshOAuthToken(c.Request.Context(), sessionID)
 	if err != nil {
+		if isStaleSessionError(err) {
+			respondStaleSession(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "refresh_failed",
 			"message": "Failed to refresh tokens",
@@ -262,6 +359,10 @@ func (h *AuthHandler) RefreshSession(c *gin.Context) {
 	// Refresh session (extend expiration)
 	refreshedSession, newJWT, err := h.sessionService.RefreshSession(sessionID)
 	if err != nil {
+		if isStaleSessionError(err) {
+			respondStaleSession(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "session_refresh_failed",
 			"message": "Failed to refresh session",
@@ -271,15 +372,9 @@ func (h *AuthHandler) RefreshSession(c *gin.Context) {
 	}
 
 	// Update session cookie
-	c.SetCookie(
-		"session_token",
-		newJWT,
-		86400, // 24 hours
-		"/",
-		"",
-		false, // Secure
-		true,  // HttpOnly
-	)
+	h.sessionService.Cookies().SetSessionCookie(c, newJWT, h.sessionService.GetSessionMaxAge())
+	// The rotated session has a new CSRF token
+	middleware.IssueCSRFToken(c, h.sessionService, refreshedSession.ID)
 
 	// Return refreshed session
 	c.JSON(http.StatusOK, gin.H{
@@ -289,6 +384,20 @@ func (h *AuthHandler) RefreshSession(c *gin.Context) {
 	})
 }
 
+// isStaleSessionError reports whether a refresh failed because the session or its
+// refresh token was already rotated, as happens when an old refresh is replayed
+func isStaleSessionError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, auth.ErrRefreshTokenReused)
+}
+
+// respondStaleSession rejects a refresh for a session that is no longer current
+func respondStaleSession(c *gin.Context) {
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"error":   "invalid_session",
+		"message": "Session is no longer valid; please sign in again",
+	})
+}
+
 // Logout terminates the current session
 // POST /auth/logout
 func (h *AuthHandler) Logout(c *gin.Context) {
@@ -305,21 +414,20 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		// Extract session ID
 		sessionID, err := h.jwtService.ExtractSessionID(tokenString)
 		if err == nil {
-			// Terminate session
+			// Terminate session; only a verified token identifies the user in the audit log
 			h.sessionService.TerminateSession(sessionID)
+
+			var userID uint
+			if claims, err := h.jwtService.ValidateToken(tokenString); err == nil {
+				userID = claims.UserID
+			}
+			h.auditService.Record(entities.NewAuditLog(entities.AuditEventLogout, userID, c.ClientIP(), c.Request.UserAgent(),
+				entities.AuditMetadata{"session_id": sessionID}))
 		}
 	}
 
 	// Clear session cookie
-	c.SetCookie(
-		"session_token",
-		"",
-		-1, // Expire immediately
-		"/",
-		"",
-		false,
-		true,
-	)
+	h.sessionService.Cookies().ClearSessionCookie(c)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -327,6 +435,69 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	})
 }
 
+// LogoutAll terminates every session belonging to the current user
+// POST /auth/logout-all
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	// Get session token
+	tokenString, err := c.Cookie("session_token")
+	if err != nil {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+			tokenString = authHeader[7:]
+		}
+	}
+
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "no_token",
+			"message": "No session token provided",
+		})
+		return
+	}
+
+	// Resolve the current user from the session
+	result, err := h.sessionService.ValidateSession(tokenString)
+	if err != nil {
+		log.Printf("Failed to validate session for logout-all: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "validation_failed",
+			"message": "Failed to validate session",
+		})
+		return
+	}
+
+	if !result.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "invalid_session",
+			"message": result.Error,
+		})
+		return
+	}
+
+	// Terminate all sessions for the user, including the current one
+	terminated, err := h.sessionService.TerminateAllUserSessions(result.Session.UserID)
+	if err != nil {
+		log.Printf("Failed to terminate sessions for user %d: %v", result.Session.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "logout_failed",
+			"message": "Failed to terminate sessions",
+		})
+		return
+	}
+
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLogout, result.Session.UserID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"all_sessions": true, "sessions_terminated": terminated}))
+
+	// Clear session cookie
+	h.sessionService.Cookies().ClearSessionCookie(c)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":             true,
+		"message":             "Logged out of all sessions",
+		"sessions_terminated": terminated,
+	})
+}
+
 // RevokeWebhook handles OAuth revocation webhook from Google
 // POST /auth/revoke-webhook
 func (h *AuthHandler) RevokeWebhook(c *gin.Context) {
@@ -369,6 +540,7 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
 		auth.GET("/session/validate", h.ValidateSession)
 		auth.POST("/session/refresh", h.RefreshSession)
 		auth.POST("/logout", h.Logout)
+		auth.POST("/logout-all", h.LogoutAll)
 
 		// Webhook routes
 		auth.POST("/revoke-webhook", h.RevokeWebhook)
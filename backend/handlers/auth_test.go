@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/services/auth"
+)
+
+func setupAuthHandlerTest(t *testing.T) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	redirectURIs, err := entities.NewRedirectURIValidator([]string{"http://localhost:3000/dashboard"})
+	require.NoError(t, err)
+	oauthService, err := auth.NewOAuthService(db, nil, redirectURIs, cfg.Session.TTL, cfg.OAuth.AutoLink)
+	require.NoError(t, err)
+	handler := NewAuthHandler(nil, oauthService, sessionService, jwtService, redirectURIs, cfg.OAuth.ErrorRedirectURL)
+
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1"))
+
+	return db, sessionService, router
+}
+
+func createUserWithSessions(t *testing.T, db *gorm.DB, sessionService *auth.SessionService, email string, count int) (*dtos.User, []string) {
+	user := dtos.User{
+		Email:         email,
+		Name:          "Test User",
+		GoogleID:      "google-" + email,
+		OAuthProvider: "google",
+		IsActive:      true,
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	tokens := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{
+			UserID: user.ID,
+			Email:  user.Email,
+		})
+		require.NoError(t, err)
+		tokens = append(tokens, token)
+	}
+
+	return &user, tokens
+}
+
+func TestLogoutAll_TerminatesAllUserSessions(t *testing.T) {
+	db, sessionService, router := setupAuthHandlerTest(t)
+	user, tokens := createUserWithSessions(t, db, sessionService, "user@example.com", 3)
+	other, _ := createUserWithSessions(t, db, sessionService, "other@example.com", 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: tokens[0]})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, float64(3), body["sessions_terminated"])
+
+	// Session cookie should be cleared
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "session_token", cookies[0].Name)
+	assert.Empty(t, cookies[0].Value)
+
+	var remaining int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", user.ID).Count(&remaining).Error)
+	assert.Equal(t, int64(0), remaining)
+
+	// Sessions belonging to other users are untouched
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", other.ID).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestLogoutAll_RequiresSession(t *testing.T) {
+	_, _, router := setupAuthHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "no_token", body["error"])
+}
+
+func TestLogoutAll_InvalidToken(t *testing.T) {
+	_, _, router := setupAuthHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_session", body["error"])
+}
+
+func TestRefreshSession_ReplayedTokenIsRejected(t *testing.T) {
+	db, sessionService, router := setupAuthHandlerTest(t)
+	_, tokens := createUserWithSessions(t, db, sessionService, "user@example.com", 1)
+
+	// Rotate the session, leaving the original token stale
+	validation, err := sessionService.ValidateSession(tokens[0])
+	require.NoError(t, err)
+	_, _, err = sessionService.RefreshSession(validation.Session.ID)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/session/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[0])
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_session", body["error"])
+}
+
+func TestGoogleLogin_RejectsRedirectURIOutsideWhitelist(t *testing.T) {
+	_, _, router := setupAuthHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/login?redirect_uri="+url.QueryEscape("http://localhost:3000.evil.com/dashboard"), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_redirect_uri", body["error"])
+}
+
+func TestGoogleCallback_RedirectsErrorsToFrontend(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		cookie    string
+		errorCode string
+	}{
+		{name: "denied consent", query: "error=access_denied", errorCode: "access_denied"},
+		{name: "provider error", query: "error=server_error", errorCode: "oauth_error"},
+		{name: "missing code", query: "state=abc", cookie: "abc", errorCode: "invalid_request"},
+		{name: "state mismatch", query: "code=xyz&state=abc", cookie: "other", errorCode: "invalid_state"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OAUTH_ERROR_REDIRECT_URL", "http://localhost:3000/login?source=google")
+			_, _, router := setupAuthHandlerTest(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?"+tt.query, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "oauth_state", Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusFound, w.Code)
+			location, err := url.Parse(w.Header().Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, "/login", location.Path)
+			assert.Equal(t, "google", location.Query().Get("source"))
+			assert.Equal(t, tt.errorCode, location.Query().Get("error"))
+		})
+	}
+}
+
+func TestGoogleCallback_JSONResponseOnRequest(t *testing.T) {
+	_, _, router := setupAuthHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?code=xyz&state=abc&response=json", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "other"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_state", body["error"])
+}
+
+// responseCookie returns the named cookie a response sets
+func responseCookie(t *testing.T, w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	require.Failf(t, "cookie not set", "no %s cookie in response", name)
+	return nil
+}
+
+func TestLogout_SessionCookieSameSite(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    http.SameSite
+	}{
+		{"", http.SameSiteLaxMode},
+		{"Strict", http.SameSiteStrictMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			t.Setenv("SESSION_COOKIE_SAMESITE", tt.setting)
+			_, _, router := setupAuthHandlerTest(t)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			cookie := responseCookie(t, w, "session_token")
+			assert.Equal(t, tt.want, cookie.SameSite)
+			assert.True(t, cookie.HttpOnly)
+			assert.Less(t, cookie.MaxAge, 0)
+		})
+	}
+}
+
+func TestGoogleCallback_StateCookieStaysLax(t *testing.T) {
+	// Google redirects back cross-site, which a Strict cookie would not survive
+	t.Setenv("SESSION_COOKIE_SAMESITE", "Strict")
+	_, _, router := setupAuthHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?code=xyz&state=abc&response=json", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "abc"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	cookie := responseCookie(t, w, "oauth_state")
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.Less(t, cookie.MaxAge, 0)
+}
@@ -0,0 +1,18 @@
+package notification
+
+import "log"
+
+// LogMailer writes password reset emails to the server log instead of sending
+// them. It is meant for development, so only use it where the log is private.
+type LogMailer struct{}
+
+// NewLogMailer creates a new LogMailer
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// SendPasswordReset logs the password reset token for email
+func (m *LogMailer) SendPasswordReset(email, token string) error {
+	log.Printf("Password reset for %s: token=%s", email, token)
+	return nil
+}
@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE for unique_violation
+const pgUniqueViolation = "23505"
+
+// uniqueViolation reports whether err is a unique constraint violation,
+// classifying it by driver error code rather than message text. When the
+// driver identifies the violated constraint it is returned as well: the index
+// name for Postgres, "table.column" for SQLite.
+func uniqueViolation(err error) (constraint string, ok bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName, pgErr.Code == pgUniqueViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique && sqliteErr.ExtendedCode != sqlite3.ErrConstraintPrimaryKey {
+			return "", false
+		}
+		return sqliteConstraintTarget(sqliteErr.Error()), true
+	}
+
+	// Dialectors with TranslateError enabled report only the generic error
+	return "", errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// sqliteConstraintTarget extracts "table.column" from a SQLite constraint message
+// such as "UNIQUE constraint failed: users.email"
+func sqliteConstraintTarget(message string) string {
+	for i := len(message) - 1; i >= 0; i-- {
+		if message[i] == ' ' {
+			return message[i+1:]
+		}
+	}
+	return message
+}
@@ -0,0 +1,39 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+
+	uservo "domain/user/valueobjects"
+
+	apptask "todo-app/application/task"
+	"todo-app/internal/dtos"
+)
+
+// gormTaskLimits implements apptask.TaskLimits from the users table
+type gormTaskLimits struct {
+	db *gorm.DB
+}
+
+// NewGormTaskLimits gives each user the task quota in their task_limit column;
+// users whose column is NULL keep the quota's MaxTasks
+func NewGormTaskLimits(db *gorm.DB) apptask.TaskLimits {
+	return &gormTaskLimits{db: db}
+}
+
+// TaskLimit returns the user's own task limit, if they have one
+func (l *gormTaskLimits) TaskLimit(ctx context.Context, userID uservo.UserID) (int64, bool, error) {
+	var overrides []sql.NullInt64
+	if err := l.db.WithContext(ctx).Model(&dtos.User{}).
+		Where("id = ?", userID.Value()).
+		Pluck("task_limit", &overrides).Error; err != nil {
+		return 0, false, err
+	}
+
+	if len(overrides) > 0 && overrides[0].Valid {
+		return overrides[0].Int64, true, nil
+	}
+	return 0, false, nil
+}
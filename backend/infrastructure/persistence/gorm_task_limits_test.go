@@ -0,0 +1,47 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+)
+
+func TestGormTaskLimits_ReturnsUsersOwnLimit(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}))
+
+	raised, unlimited := int64(50000), int64(0)
+	users := []dtos.User{
+		{Email: "default@example.com", Name: "Default", GoogleID: "g-1", OAuthProvider: "google"},
+		{Email: "raised@example.com", Name: "Raised", GoogleID: "g-2", OAuthProvider: "google", TaskLimit: &raised},
+		{Email: "unlimited@example.com", Name: "Unlimited", GoogleID: "g-3", OAuthProvider: "google", TaskLimit: &unlimited},
+	}
+	require.NoError(t, db.Create(&users).Error)
+
+	limits := NewGormTaskLimits(db)
+	for _, tt := range []struct {
+		userID uint
+		want   int64
+		own    bool
+	}{
+		{users[0].ID, 0, false},
+		{users[1].ID, 50000, true},
+		{users[2].ID, 0, true},
+		{999, 0, false}, // unknown users keep the configured quota
+	} {
+		limit, own, err := limits.TaskLimit(context.Background(), uservo.NewUserID(tt.userID))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, limit, "user %d", tt.userID)
+		assert.Equal(t, tt.own, own, "user %d", tt.userID)
+	}
+}
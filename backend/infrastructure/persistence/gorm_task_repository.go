@@ -1,15 +1,21 @@
 package persistence
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"domain/task/entities"
+	"domain/task/repositories"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
 	"todo-app/application/mappers"
-	"todo-app/domain/task/entities"
-	"todo-app/domain/task/repositories"
-	"todo-app/domain/task/valueobjects"
-	uservo "todo-app/domain/user/valueobjects"
 	"todo-app/internal/dtos"
 )
 
@@ -27,23 +33,36 @@ func NewGormTaskRepository(db *gorm.DB, mapper *mappers.TaskMapper) repositories
 	}
 }
 
-// Save persists a task entity
-func (r *gormTaskRepository) Save(task *entities.Task) error {
+// Save persists a task entity and assigns it the auto-increment ID, scheduling
+// its reminder in the same transaction
+func (r *gormTaskRepository) Save(ctx context.Context, task *entities.Task) error {
 	// Convert entity to DTO using mapper
 	dto := r.mapper.ToDTO(task)
 
-	if err := r.db.Create(dto).Error; err != nil {
-		return err
-	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dto).Error; err != nil {
+			return err
+		}
 
-	return nil
+		// Create reads the generated ID back into the DTO
+		if task.ID().IsZero() {
+			if err := task.AssignID(valueobjects.NewTaskID(dto.ID)); err != nil {
+				return err
+			}
+		}
+
+		if dto.RemindAt == nil {
+			return nil
+		}
+		return syncReminder(tx, dto.ID, dto.UserID, dto.RemindAt)
+	})
 }
 
 // FindByID retrieves a task by its ID
-func (r *gormTaskRepository) FindByID(id valueobjects.TaskID) (*entities.Task, error) {
+func (r *gormTaskRepository) FindByID(ctx context.Context, id valueobjects.TaskID) (*entities.Task, error) {
 	var dto dtos.Task
 
-	if err := r.db.First(&dto, id.Value()).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Tags", orderTagsByName).First(&dto, id.Value()).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Return nil if not found, not an error
 		}
@@ -54,11 +73,16 @@ func (r *gormTaskRepository) FindByID(id valueobjects.TaskID) (*entities.Task, e
 	return r.mapper.ToEntity(&dto)
 }
 
-// FindByUserID retrieves all tasks for a specific user
-func (r *gormTaskRepository) FindByUserID(userID uservo.UserID) ([]*entities.Task, error) {
+// FindByUserID retrieves all tasks for a specific user in the given order
+func (r *gormTaskRepository) FindByUserID(ctx context.Context, userID uservo.UserID, sort repositories.TaskSort) ([]*entities.Task, error) {
 	var dtoList []dtos.Task
 
-	if err := r.db.Where("user_id = ?", userID.Value()).Find(&dtoList).Error; err != nil {
+	query, err := applyTaskSort(r.db.WithContext(ctx).Where("user_id = ?", userID.Value()), sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Preload("Tags", orderTagsByName).Find(&dtoList).Error; err != nil {
 		return nil, err
 	}
 
@@ -75,14 +99,132 @@ func (r *gormTaskRepository) FindByUserID(userID uservo.UserID) ([]*entities.Tas
 	return entities, nil
 }
 
-// FindByUserIDAndStatus retrieves tasks by user and status
-func (r *gormTaskRepository) FindByUserIDAndStatus(userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error) {
+// FindFilteredByUserID retrieves a page of a user's tasks matching filter,
+// counting every match with the same conditions
+func (r *gormTaskRepository) FindFilteredByUserID(ctx context.Context, userID uservo.UserID, filter repositories.TaskFilter, sort repositories.TaskSort, limit, offset int) ([]*entities.Task, int64, error) {
+	query := r.db.WithContext(ctx).Model(&dtos.Task{}).Where("user_id = ?", userID.Value())
+	if filter.UpdatedSince != nil {
+		// Soft deletes only set deleted_at, so trashing is matched separately
+		query = query.Unscoped().Where("(updated_at > ? OR deleted_at > ?)", *filter.UpdatedSince, *filter.UpdatedSince)
+	}
+	if filter.Status != nil {
+		query = query.Where(effectiveStatusSQL+" = ?", filter.Status.Value())
+	}
+	if filter.Priority != nil {
+		query = query.Where("priority = ?", filter.Priority.Value())
+	}
+	if filter.Tag != nil {
+		tagged := r.db.WithContext(ctx).Table("task_tags").
+			Select("task_tags.task_id").
+			Joins("JOIN tags ON tags.id = task_tags.tag_id").
+			Where("tags.user_id = ? AND tags.name = ?", userID.Value(), filter.Tag.Value())
+		query = query.Where("id IN (?)", tagged)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page, err := applyTaskSort(query, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit > 0 {
+		// Pages need a stable order even when none was asked for
+		if sort.IsZero() {
+			page = page.Order("id ASC")
+		}
+		page = page.Limit(limit).Offset(offset)
+	}
+
 	var dtoList []dtos.Task
+	if err := page.Preload("Tags", orderTagsByName).Find(&dtoList).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, 0, err
+		}
+		entities[i] = entity
+	}
 
-	// Map status to completed boolean for DTO query
-	completed := status.IsCompleted()
+	return entities, total, nil
+}
+
+// FindPageByUserID retrieves up to limit of a user's tasks with IDs above
+// afterID, in ID order; the soft-delete scope leaves out trashed ones
+func (r *gormTaskRepository) FindPageByUserID(ctx context.Context, userID uservo.UserID, afterID valueobjects.TaskID, limit int) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
 
-	if err := r.db.Where("user_id = ? AND completed = ?", userID.Value(), completed).Find(&dtoList).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND id > ?", userID.Value(), afterID.Value()).
+		Preload("Tags", orderTagsByName).
+		Order("id ASC").
+		Limit(limit).
+		Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// LockUserTasks locks the user's row until the transaction ends. SQLite has no
+// row locks, but only lets one transaction write at a time.
+func (r *gormTaskRepository) LockUserTasks(ctx context.Context, userID uservo.UserID) error {
+	var locked []uint
+	return r.db.WithContext(ctx).Model(&dtos.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID.Value()).Pluck("id", &locked).Error
+}
+
+// CountByUserID counts a user's tasks; the soft-delete scope leaves out trashed ones
+func (r *gormTaskRepository) CountByUserID(ctx context.Context, userID uservo.UserID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).Where("user_id = ?", userID.Value()).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountByUserIDAndStatus counts a user's tasks in the given statuses; every
+// column it reads is in idx_tasks_user_id_status, so the index alone answers it
+func (r *gormTaskRepository) CountByUserIDAndStatus(ctx context.Context, userID uservo.UserID, statuses ...valueobjects.TaskStatus) (int64, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = status.Value()
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).
+		Where("user_id = ? AND "+effectiveStatusSQL+" IN ?", userID.Value(), values).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindByUserIDAndStatus retrieves tasks by user and status
+func (r *gormTaskRepository) FindByUserIDAndStatus(ctx context.Context, userID uservo.UserID, status valueobjects.TaskStatus) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	if err := r.db.WithContext(ctx).Preload("Tags", orderTagsByName).Where("user_id = ? AND "+effectiveStatusSQL+" = ?", userID.Value(), status.Value()).Find(&dtoList).Error; err != nil {
 		return nil, err
 	}
 
@@ -100,41 +242,351 @@ func (r *gormTaskRepository) FindByUserIDAndStatus(userID uservo.UserID, status
 }
 
 // FindByUserIDAndPriority retrieves tasks by user and priority
-func (r *gormTaskRepository) FindByUserIDAndPriority(userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error) {
+func (r *gormTaskRepository) FindByUserIDAndPriority(ctx context.Context, userID uservo.UserID, priority valueobjects.TaskPriority) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	if err := r.db.WithContext(ctx).Preload("Tags", orderTagsByName).Where("user_id = ? AND priority = ?", userID.Value(), priority.Value()).Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// FindByUserIDAndTag retrieves a user's tasks carrying the given tag in the given order
+func (r *gormTaskRepository) FindByUserIDAndTag(ctx context.Context, userID uservo.UserID, tag valueobjects.TagName, sort repositories.TaskSort) ([]*entities.Task, error) {
 	var dtoList []dtos.Task
 
-	// Note: Priority is not stored in DTO, so this query will return all tasks for the user
-	// and we filter by priority in memory (not ideal, but maintains compatibility)
-	if err := r.db.Where("user_id = ?", userID.Value()).Find(&dtoList).Error; err != nil {
+	tagged := r.db.WithContext(ctx).Table("task_tags").
+		Select("task_tags.task_id").
+		Joins("JOIN tags ON tags.id = task_tags.tag_id").
+		Where("tags.user_id = ? AND tags.name = ?", userID.Value(), tag.Value())
+
+	query, err := applyTaskSort(r.db.WithContext(ctx).Where("user_id = ? AND id IN (?)", userID.Value(), tagged), sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Preload("Tags", orderTagsByName).Find(&dtoList).Error; err != nil {
 		return nil, err
 	}
 
-	// Convert DTOs to entities and filter by priority
-	var entities []*entities.Task
-	for _, dto := range dtoList {
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
 		entity, err := r.mapper.ToEntity(&dto)
 		if err != nil {
 			return nil, err
 		}
-		// Filter by priority (all tasks have medium priority from mapper)
-		if entity.Priority().Value() == priority.Value() {
-			entities = append(entities, entity)
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// FindUpdatedSince retrieves a user's tasks, trashed ones included, changed or
+// trashed after since in the given order
+func (r *gormTaskRepository) FindUpdatedSince(ctx context.Context, userID uservo.UserID, since time.Time, sort repositories.TaskSort) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	// Soft deletes only set deleted_at, so trashing is matched separately
+	query, err := applyTaskSort(r.db.WithContext(ctx).Unscoped().Where("user_id = ? AND (updated_at > ? OR deleted_at > ?)", userID.Value(), since, since), sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Preload("Tags", orderTagsByName).Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
 		}
+		entities[i] = entity
 	}
 
 	return entities, nil
 }
 
-// Update updates an existing task
-func (r *gormTaskRepository) Update(task *entities.Task) error {
+// SearchByText retrieves a user's tasks whose title or description contains the query
+func (r *gormTaskRepository) SearchByText(ctx context.Context, userID uservo.UserID, query string) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	pattern := "%" + escapeLikePattern(strings.ToLower(query)) + "%"
+
+	if err := r.db.WithContext(ctx).
+		Preload("Tags", orderTagsByName).
+		Where("user_id = ?", userID.Value()).
+		Where("(LOWER(title) LIKE ? ESCAPE '\\' OR LOWER(description) LIKE ? ESCAPE '\\')", pattern, pattern).
+		Order("updated_at DESC").
+		Order("id DESC").
+		Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// FindOverdueByUserID retrieves a user's pending tasks due before now, earliest due date first
+func (r *gormTaskRepository) FindOverdueByUserID(ctx context.Context, userID uservo.UserID, now time.Time) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	if err := r.overdueTasks(ctx, userID, now).
+		Preload("Tags", orderTagsByName).
+		Order("due_date ASC").
+		Order("id ASC").
+		Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// FindDueSoonByUserID retrieves a user's pending tasks due between now and
+// until, inclusive, earliest due date first
+func (r *gormTaskRepository) FindDueSoonByUserID(ctx context.Context, userID uservo.UserID, now, until time.Time) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).
+		Where("user_id = ?", userID.Value()).
+		Where(effectiveStatusSQL+" = ?", valueobjects.NewPendingStatus().Value()).
+		Where("due_date BETWEEN ? AND ?", now, until).
+		Preload("Tags", orderTagsByName).
+		Order("due_date ASC").
+		Order("id ASC").
+		Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// overdueTasks scopes a query to a user's pending tasks due before now
+func (r *gormTaskRepository) overdueTasks(ctx context.Context, userID uservo.UserID, now time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&dtos.Task{}).
+		Where("user_id = ?", userID.Value()).
+		Where(effectiveStatusSQL+" = ?", valueobjects.NewPendingStatus().Value()).
+		Where("due_date IS NOT NULL AND due_date < ?", now)
+}
+
+// effectiveStatusSQL derives a row's status the same way the task mapper does,
+// so rows saved before the status column existed are counted correctly
+const effectiveStatusSQL = "(CASE WHEN status = 'archived' THEN 'archived' WHEN completed THEN 'completed' ELSE 'pending' END)"
+
+// GetListVersionByUserID counts a user's tasks, trashed ones included, and
+// finds when they were last saved and last trashed
+func (r *gormTaskRepository) GetListVersionByUserID(ctx context.Context, userID uservo.UserID) (repositories.TaskListVersion, error) {
+	var row struct {
+		Count         int64
+		LastUpdatedAt sql.NullString
+		LastDeletedAt sql.NullString
+	}
+	err := r.db.WithContext(ctx).Unscoped().Model(&dtos.Task{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS last_updated_at, MAX(deleted_at) AS last_deleted_at").
+		Where("user_id = ?", userID.Value()).
+		Scan(&row).Error
+	if err != nil {
+		return repositories.TaskListVersion{}, fmt.Errorf("failed to summarise tasks: %w", err)
+	}
+
+	version := repositories.TaskListVersion{Count: row.Count}
+	if version.LastUpdatedAt, err = parseAggregateTime(row.LastUpdatedAt); err != nil {
+		return repositories.TaskListVersion{}, err
+	}
+	if version.LastDeletedAt, err = parseAggregateTime(row.LastDeletedAt); err != nil {
+		return repositories.TaskListVersion{}, err
+	}
+	return version, nil
+}
+
+// aggregateTimeLayouts are the formats MAX() of a timestamp column is read in:
+// SQLite returns the stored text, while database/sql formats Postgres values as RFC 3339
+var aggregateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseAggregateTime parses a timestamp read through an aggregate, which loses
+// the column type the driver would otherwise convert by; NULL gives nil
+func parseAggregateTime(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	for _, layout := range aggregateTimeLayouts {
+		if parsed, err := time.Parse(layout, value.String); err == nil {
+			return &parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognised timestamp %q", value.String)
+}
+
+// GetStatsByUserID counts a user's tasks with GROUP BY queries
+func (r *gormTaskRepository) GetStatsByUserID(ctx context.Context, userID uservo.UserID, now time.Time) (repositories.TaskStats, error) {
+	var stats repositories.TaskStats
+
+	type groupCount struct {
+		Value string
+		Count int64
+	}
+
+	var statusCounts []groupCount
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).
+		Select(effectiveStatusSQL+" AS value, COUNT(*) AS count").
+		Where("user_id = ?", userID.Value()).
+		Group("value").
+		Scan(&statusCounts).Error; err != nil {
+		return stats, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+
+	for _, row := range statusCounts {
+		switch row.Value {
+		case valueobjects.NewCompletedStatus().Value():
+			stats.ByStatus.Completed = row.Count
+		case valueobjects.NewArchivedStatus().Value():
+			stats.ByStatus.Archived = row.Count
+		default:
+			stats.ByStatus.Pending += row.Count
+		}
+		stats.Total += row.Count
+	}
+
+	var priorityCounts []groupCount
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).
+		Select("COALESCE(NULLIF(priority, ''), 'medium') AS value, COUNT(*) AS count").
+		Where("user_id = ?", userID.Value()).
+		Group("value").
+		Scan(&priorityCounts).Error; err != nil {
+		return stats, fmt.Errorf("failed to count tasks by priority: %w", err)
+	}
+
+	for _, row := range priorityCounts {
+		switch row.Value {
+		case valueobjects.NewLowPriority().Value():
+			stats.ByPriority.Low = row.Count
+		case valueobjects.NewHighPriority().Value():
+			stats.ByPriority.High = row.Count
+		default:
+			stats.ByPriority.Medium += row.Count
+		}
+	}
+
+	if err := r.overdueTasks(ctx, userID, now).Count(&stats.Overdue).Error; err != nil {
+		return stats, fmt.Errorf("failed to count overdue tasks: %w", err)
+	}
+
+	return stats, nil
+}
+
+// applyTaskSort adds a validated ORDER BY for the sort, with ID as a tie-breaker
+func applyTaskSort(query *gorm.DB, sort repositories.TaskSort) (*gorm.DB, error) {
+	if sort.IsZero() {
+		return query, nil
+	}
+
+	var column string
+	switch sort.Field {
+	case repositories.TaskSortByCreatedAt:
+		column = "created_at"
+	case repositories.TaskSortByUpdatedAt:
+		column = "updated_at"
+	case repositories.TaskSortByTitle:
+		column = "LOWER(title)"
+	case repositories.TaskSortByPriority:
+		// Order priorities logically rather than alphabetically
+		column = "CASE priority WHEN 'low' THEN 1 WHEN 'high' THEN 3 ELSE 2 END"
+	default:
+		return nil, fmt.Errorf("%w: unsupported sort field %q", repositories.ErrInvalidTaskSort, sort.Field)
+	}
+
+	direction := "ASC"
+	if sort.Order == repositories.SortDescending {
+		direction = "DESC"
+	}
+
+	return query.Order(column + " " + direction).Order("id " + direction), nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so they match literally
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
+
+// Update saves an existing task. The row is only written while its version
+// still matches the task's, so an update based on a stale read fails with
+// repositories.ErrTaskVersionConflict instead of overwriting a newer one. The
+// task's pending reminder is rescheduled in the same transaction.
+func (r *gormTaskRepository) Update(ctx context.Context, task *entities.Task) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := &gormTaskRepository{db: tx, mapper: r.mapper}
+		if err := txRepo.update(ctx, task); err != nil {
+			return err
+		}
+		return syncReminder(tx, task.ID().Value(), task.UserID().Value(), task.RemindAt())
+	})
+}
+
+// update writes the task's row, checking its version
+func (r *gormTaskRepository) update(ctx context.Context, task *entities.Task) error {
 	// Convert entity to DTO using mapper
 	dto := r.mapper.ToDTO(task)
 
-	// Update specific fields
-	result := r.db.Model(&dtos.Task{}).Where("id = ?", dto.ID).Updates(map[string]interface{}{
-		"title":     dto.Title,
-		"completed": dto.Completed,
-		"user_id":   dto.UserID,
+	// Update specific fields; the populated DTO is the model so its
+	// BeforeUpdate validation sees the task being saved
+	result := r.db.WithContext(ctx).Model(dto).Where("id = ? AND version = ?", dto.ID, dto.Version).Updates(map[string]interface{}{
+		"title":       dto.Title,
+		"description": dto.Description,
+		"priority":    dto.Priority,
+		"status":      dto.Status,
+		"completed":   dto.Completed,
+		"due_date":    dto.DueDate,
+		"remind_at":   dto.RemindAt,
+		"user_id":     dto.UserID,
+		"version":     gorm.Expr("version + 1"),
 	})
 
 	if result.Error != nil {
@@ -142,15 +594,152 @@ func (r *gormTaskRepository) Update(task *entities.Task) error {
 	}
 
 	if result.RowsAffected == 0 {
+		// Tell a task saved since it was read apart from one that is gone
+		var current int64
+		if err := r.db.WithContext(ctx).Model(&dtos.Task{}).Where("id = ?", dto.ID).Count(&current).Error; err != nil {
+			return err
+		}
+		if current > 0 {
+			return repositories.ErrTaskVersionConflict
+		}
 		return errors.New("task not found or no changes made")
 	}
 
+	task.LoadVersion(dto.Version + 1)
 	return nil
 }
 
-// Delete removes a task by ID
-func (r *gormTaskRepository) Delete(id valueobjects.TaskID) error {
-	result := r.db.Delete(&dtos.Task{}, id.Value())
+// UpdateBatch updates several tasks in a single transaction
+func (r *gormTaskRepository) UpdateBatch(ctx context.Context, tasks []*entities.Task) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := &gormTaskRepository{db: tx, mapper: r.mapper}
+		for _, task := range tasks {
+			if err := txRepo.Update(ctx, task); err != nil {
+				return fmt.Errorf("failed to update task %d: %w", task.ID().Value(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// syncReminder brings a task's pending notifications in line with its
+// reminder time: pending ones move to the new time, or are dropped when the
+// reminder is cleared, and each reminder channel gets a notification for the
+// time unless it already has one, sent or not.
+func syncReminder(tx *gorm.DB, taskID, userID uint, remindAt *time.Time) error {
+	if remindAt == nil {
+		return tx.Where("task_id = ? AND status = ?", taskID, dtos.NotificationPending).Delete(&dtos.Notification{}).Error
+	}
+	scheduledFor := remindAt.UTC()
+
+	var existing []dtos.Notification
+	if err := tx.Where("task_id = ?", taskID).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	scheduled := make(map[string]bool, len(dtos.ReminderChannels))
+	for _, notification := range existing {
+		if notification.ScheduledFor.Equal(scheduledFor) {
+			scheduled[notification.Channel] = true
+		}
+	}
+	for _, notification := range existing {
+		if notification.Status != dtos.NotificationPending || notification.ScheduledFor.Equal(scheduledFor) {
+			continue
+		}
+		if scheduled[notification.Channel] {
+			// Another notification already covers this channel at the new time
+			if err := tx.Delete(&dtos.Notification{}, notification.ID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(&dtos.Notification{}).Where("id = ?", notification.ID).
+			Updates(map[string]interface{}{"scheduled_for": scheduledFor, "attempts": 0, "last_error": ""}).Error; err != nil {
+			return err
+		}
+		scheduled[notification.Channel] = true
+	}
+
+	for _, channel := range dtos.ReminderChannels {
+		if scheduled[channel] {
+			continue
+		}
+		notification := dtos.Notification{
+			UserID:       userID,
+			TaskID:       taskID,
+			Channel:      channel,
+			ScheduledFor: scheduledFor,
+			Status:       dtos.NotificationPending,
+		}
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskTag is a row of the task_tags join table
+type taskTag struct {
+	TaskID uint
+	TagID  uint
+}
+
+// TableName specifies the join table name
+func (taskTag) TableName() string {
+	return "task_tags"
+}
+
+// orderTagsByName keeps preloaded tags in a stable order
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name ASC")
+}
+
+// AttachTags links tags to a task, creating missing tags for their owner
+func (r *gormTaskRepository) AttachTags(ctx context.Context, taskID valueobjects.TaskID, tags []*entities.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			tagDTO := dtos.Tag{Name: tag.Name().Value(), UserID: tag.OwnerID().Value()}
+			if err := tx.Where(&dtos.Tag{Name: tagDTO.Name, UserID: tagDTO.UserID}).FirstOrCreate(&tagDTO).Error; err != nil {
+				return fmt.Errorf("failed to save tag %q: %w", tagDTO.Name, err)
+			}
+
+			link := taskTag{TaskID: taskID.Value(), TagID: tagDTO.ID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return fmt.Errorf("failed to attach tag %q: %w", tagDTO.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DetachTags unlinks tags from a task
+func (r *gormTaskRepository) DetachTags(ctx context.Context, taskID valueobjects.TaskID, tags []*entities.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			tagIDs := tx.Model(&dtos.Tag{}).
+				Select("id").
+				Where("user_id = ? AND name = ?", tag.OwnerID().Value(), tag.Name().Value())
+
+			if err := tx.Where("task_id = ? AND tag_id IN (?)", taskID.Value(), tagIDs).Delete(&taskTag{}).Error; err != nil {
+				return fmt.Errorf("failed to detach tag %q: %w", tag.Name().Value(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// Delete soft-deletes a task; its tag links are kept so a restore brings them back
+func (r *gormTaskRepository) Delete(ctx context.Context, id valueobjects.TaskID) error {
+	result := r.db.WithContext(ctx).Delete(&dtos.Task{}, id.Value())
 
 	if result.Error != nil {
 		return result.Error
@@ -163,13 +752,162 @@ func (r *gormTaskRepository) Delete(id valueobjects.TaskID) error {
 	return nil
 }
 
+// trashedTasks scopes a query to soft-deleted tasks
+func (r *gormTaskRepository) trashedTasks(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Unscoped().Model(&dtos.Task{}).Where("deleted_at IS NOT NULL")
+}
+
+// FindDeletedByID retrieves a soft-deleted task by its ID
+func (r *gormTaskRepository) FindDeletedByID(ctx context.Context, id valueobjects.TaskID) (*entities.Task, error) {
+	var dto dtos.Task
+
+	if err := r.trashedTasks(ctx).Preload("Tags", orderTagsByName).First(&dto, id.Value()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil if not found, not an error
+		}
+		return nil, err
+	}
+
+	// Convert DTO to entity using mapper
+	return r.mapper.ToEntity(&dto)
+}
+
+// FindDeletedByUserID retrieves a user's soft-deleted tasks, most recently deleted first
+func (r *gormTaskRepository) FindDeletedByUserID(ctx context.Context, userID uservo.UserID) ([]*entities.Task, error) {
+	var dtoList []dtos.Task
+
+	if err := r.trashedTasks(ctx).
+		Where("user_id = ?", userID.Value()).
+		Preload("Tags", orderTagsByName).
+		Order("deleted_at DESC").
+		Order("id DESC").
+		Find(&dtoList).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to entities using mapper
+	entities := make([]*entities.Task, len(dtoList))
+	for i, dto := range dtoList {
+		entity, err := r.mapper.ToEntity(&dto)
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = entity
+	}
+
+	return entities, nil
+}
+
+// Restore clears a task's soft-delete marker
+func (r *gormTaskRepository) Restore(ctx context.Context, id valueobjects.TaskID) error {
+	// UpdateColumns skips the BeforeUpdate validation, which would reject the empty model;
+	// updated_at is bumped so syncing clients see the task come back
+	result := r.trashedTasks(ctx).Where("id = ?", id.Value()).UpdateColumns(map[string]interface{}{
+		"deleted_at": nil,
+		"updated_at": time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("task not found")
+	}
+
+	return nil
+}
+
+// DeletePermanently removes a task by ID along with its tag links
+func (r *gormTaskRepository) DeletePermanently(ctx context.Context, id valueobjects.TaskID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", id.Value()).Delete(&taskTag{}).Error; err != nil {
+			return err
+		}
+		// Sent notifications stay listed; ones still waiting are dropped
+		if err := tx.Where("task_id = ? AND status = ?", id.Value(), dtos.NotificationPending).Delete(&dtos.Notification{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Delete(&dtos.Task{}, id.Value())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("task not found")
+		}
+
+		return nil
+	})
+}
+
 // ExistsByID checks if a task exists by ID
-func (r *gormTaskRepository) ExistsByID(id valueobjects.TaskID) (bool, error) {
+func (r *gormTaskRepository) ExistsByID(ctx context.Context, id valueobjects.TaskID) (bool, error) {
 	var count int64
 
-	if err := r.db.Model(&dtos.Task{}).Where("id = ?", id.Value()).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).Where("id = ?", id.Value()).Count(&count).Error; err != nil {
 		return false, err
 	}
 
 	return count > 0, nil
-}
\ No newline at end of file
+}
+
+// ExistsByIDAndUser checks if a live task with the ID belongs to the user
+func (r *gormTaskRepository) ExistsByIDAndUser(ctx context.Context, id valueobjects.TaskID, userID uservo.UserID) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&dtos.Task{}).Where("id = ? AND user_id = ?", id.Value(), userID.Value()).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RecordActivity inserts task history entries and assigns their IDs
+func (r *gormTaskRepository) RecordActivity(ctx context.Context, activities []*entities.TaskActivity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+
+	dtoList := make([]*dtos.TaskActivity, len(activities))
+	for i, activity := range activities {
+		dtoList[i] = r.mapper.ActivityToDTO(activity)
+	}
+
+	if err := r.db.WithContext(ctx).Create(&dtoList).Error; err != nil {
+		return err
+	}
+
+	for i, activity := range activities {
+		activity.LoadPersisted(dtoList[i].ID, dtoList[i].CreatedAt)
+	}
+	return nil
+}
+
+// FindActivityByTaskID retrieves a page of a task's history, newest first;
+// entries recorded together are returned in reverse order of recording
+func (r *gormTaskRepository) FindActivityByTaskID(ctx context.Context, taskID valueobjects.TaskID, limit, offset int) ([]*entities.TaskActivity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&dtos.TaskActivity{}).Where("task_id = ?", taskID.Value())
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var dtoList []dtos.TaskActivity
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&dtoList).Error; err != nil {
+		return nil, 0, err
+	}
+
+	activities := make([]*entities.TaskActivity, 0, len(dtoList))
+	for i := range dtoList {
+		activity, err := r.mapper.ActivityToEntity(&dtoList[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, total, nil
+}
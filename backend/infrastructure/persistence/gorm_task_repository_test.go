@@ -0,0 +1,773 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"domain/task/entities"
+	"domain/task/repositories"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/application/mappers"
+	"todo-app/internal/dtos"
+)
+
+func setupTaskRepositoryTest(t *testing.T) (*gorm.DB, *gormTaskRepository) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}))
+
+	repo := NewGormTaskRepository(db, &mappers.TaskMapper{}).(*gormTaskRepository)
+	return db, repo
+}
+
+func TestGormTaskRepository_SearchByText(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []dtos.Task{
+		{Title: "Buy groceries", Description: "Milk and eggs", UserID: 1, UpdatedAt: base},
+		{Title: "Call mom", Description: "Ask about GROCERIES list", UserID: 1, UpdatedAt: base.Add(2 * time.Hour)},
+		{Title: "Write report", Description: "Quarterly numbers", UserID: 1, UpdatedAt: base.Add(time.Hour)},
+		{Title: "Groceries for neighbour", UserID: 2, UpdatedAt: base.Add(3 * time.Hour)},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	tasks, err := repo.SearchByText(context.Background(), uservo.NewUserID(1), "groceries")
+	require.NoError(t, err)
+
+	// Matches title or description case-insensitively, scoped to the user,
+	// most recently updated first
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Call mom", tasks[0].Title().Value())
+	assert.Equal(t, "Buy groceries", tasks[1].Title().Value())
+}
+
+func TestGormTaskRepository_SearchByText_EscapesWildcards(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "Raise budget 100%", UserID: 1},
+		{Title: "Raise budget 1000", UserID: 1},
+		{Title: "rename file_name", UserID: 1},
+		{Title: "rename filename", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	tasks, err := repo.SearchByText(context.Background(), uservo.NewUserID(1), "100%")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Raise budget 100%", tasks[0].Title().Value())
+
+	tasks, err = repo.SearchByText(context.Background(), uservo.NewUserID(1), "file_name")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "rename file_name", tasks[0].Title().Value())
+
+	tasks, err = repo.SearchByText(context.Background(), uservo.NewUserID(1), `\`)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestGormTaskRepository_FindByUserID_Sorting(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []dtos.Task{
+		{Title: "banana", Priority: "high", UserID: 1, CreatedAt: base.Add(2 * time.Hour), UpdatedAt: base},
+		{Title: "Apple", Priority: "low", UserID: 1, CreatedAt: base, UpdatedAt: base.Add(2 * time.Hour)},
+		{Title: "cherry", Priority: "medium", UserID: 1, CreatedAt: base.Add(time.Hour), UpdatedAt: base.Add(time.Hour)},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	titles := func(sort repositories.TaskSort) []string {
+		tasks, err := repo.FindByUserID(context.Background(), uservo.NewUserID(1), sort)
+		require.NoError(t, err)
+		result := make([]string, len(tasks))
+		for i, task := range tasks {
+			result[i] = task.Title().Value()
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"banana", "Apple", "cherry"}, titles(repositories.TaskSort{}))
+	assert.Equal(t, []string{"Apple", "cherry", "banana"}, titles(repositories.TaskSort{Field: repositories.TaskSortByCreatedAt, Order: repositories.SortAscending}))
+	assert.Equal(t, []string{"Apple", "cherry", "banana"}, titles(repositories.TaskSort{Field: repositories.TaskSortByUpdatedAt, Order: repositories.SortDescending}))
+	assert.Equal(t, []string{"Apple", "banana", "cherry"}, titles(repositories.TaskSort{Field: repositories.TaskSortByTitle, Order: repositories.SortAscending}))
+
+	// Priority sorts logically (low < medium < high), not alphabetically
+	assert.Equal(t, []string{"Apple", "cherry", "banana"}, titles(repositories.TaskSort{Field: repositories.TaskSortByPriority, Order: repositories.SortAscending}))
+	assert.Equal(t, []string{"banana", "cherry", "Apple"}, titles(repositories.TaskSort{Field: repositories.TaskSortByPriority, Order: repositories.SortDescending}))
+}
+
+func TestGormTaskRepository_GetListVersionByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	userID := uservo.NewUserID(1)
+
+	version, err := repo.GetListVersionByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, repositories.TaskListVersion{}, version)
+
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	seed := []dtos.Task{
+		{Title: "Older", UserID: 1},
+		{Title: "Newer", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Model(&dtos.Task{}).Where("id = ?", seed[0].ID).UpdateColumn("updated_at", updatedAt.Add(-time.Hour)).Error)
+	require.NoError(t, db.Model(&dtos.Task{}).Where("id = ?", seed[1].ID).UpdateColumn("updated_at", updatedAt).Error)
+
+	version, err = repo.GetListVersionByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version.Count)
+	require.NotNil(t, version.LastUpdatedAt)
+	assert.True(t, updatedAt.Equal(*version.LastUpdatedAt), version.LastUpdatedAt)
+	assert.Nil(t, version.LastDeletedAt)
+
+	// Trashed tasks still count, and trashing one is visible
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[0].ID)))
+	version, err = repo.GetListVersionByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version.Count)
+	assert.NotNil(t, version.LastDeletedAt)
+}
+
+func TestGormTaskRepository_GetStatsByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	seed := []dtos.Task{
+		{Title: "Late", Priority: "high", UserID: 1, DueDate: &past},
+		{Title: "Upcoming", Priority: "low", UserID: 1, DueDate: &future},
+		{Title: "No due date", UserID: 1},
+		{Title: "Done late", Priority: "high", Status: "completed", Completed: true, UserID: 1, DueDate: &past},
+		{Title: "Archived late", Status: "archived", UserID: 1, DueDate: &past},
+		{Title: "Legacy completed row", Completed: true, UserID: 1},
+		{Title: "Someone else's", Priority: "high", UserID: 2, DueDate: &past},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	stats, err := repo.GetStatsByUserID(context.Background(), uservo.NewUserID(1), now)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(6), stats.Total)
+	assert.Equal(t, repositories.TaskStatusCounts{Pending: 3, Completed: 2, Archived: 1}, stats.ByStatus)
+	assert.Equal(t, repositories.TaskPriorityCounts{Low: 1, Medium: 3, High: 2}, stats.ByPriority)
+
+	// Completed and archived tasks are never overdue
+	assert.Equal(t, int64(1), stats.Overdue)
+}
+
+func TestGormTaskRepository_FindOverdueByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lateByDay := now.Add(-24 * time.Hour)
+	lateByHour := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	seed := []dtos.Task{
+		{Title: "Late by an hour", UserID: 1, DueDate: &lateByHour},
+		{Title: "Late by a day", UserID: 1, DueDate: &lateByDay},
+		{Title: "Upcoming", UserID: 1, DueDate: &future},
+		{Title: "No due date", UserID: 1},
+		{Title: "Done late", Status: "completed", Completed: true, UserID: 1, DueDate: &lateByDay},
+		{Title: "Legacy completed late", Completed: true, UserID: 1, DueDate: &lateByDay},
+		{Title: "Archived late", Status: "archived", UserID: 1, DueDate: &lateByDay},
+		{Title: "Someone else's", UserID: 2, DueDate: &lateByDay},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	tasks, err := repo.FindOverdueByUserID(context.Background(), uservo.NewUserID(1), now)
+	require.NoError(t, err)
+
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Late by a day", tasks[0].Title().Value())
+	assert.Equal(t, "Late by an hour", tasks[1].Title().Value())
+	for _, task := range tasks {
+		assert.True(t, task.IsOverdue(now))
+	}
+}
+
+func TestGormTaskRepository_FindDueSoonByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	inHour := now.Add(time.Hour)
+	inDay := now.Add(24 * time.Hour)
+	inWeek := now.Add(7 * 24 * time.Hour)
+	late := now.Add(-time.Hour)
+
+	seed := []dtos.Task{
+		{Title: "Due tomorrow", UserID: 1, DueDate: &inDay},
+		{Title: "Due in an hour", UserID: 1, DueDate: &inHour},
+		{Title: "Due next week", UserID: 1, DueDate: &inWeek},
+		{Title: "Overdue", UserID: 1, DueDate: &late},
+		{Title: "No due date", UserID: 1},
+		{Title: "Done soon", Status: "completed", Completed: true, UserID: 1, DueDate: &inHour},
+		{Title: "Archived soon", Status: "archived", UserID: 1, DueDate: &inHour},
+		{Title: "Someone else's", UserID: 2, DueDate: &inHour},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	tasks, err := repo.FindDueSoonByUserID(context.Background(), uservo.NewUserID(1), now, inDay)
+	require.NoError(t, err)
+
+	// The end of the window is inclusive
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Due in an hour", tasks[0].Title().Value())
+	assert.Equal(t, "Due tomorrow", tasks[1].Title().Value())
+}
+
+func TestGormTaskRepository_FindPageByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	seed := []dtos.Task{
+		{Title: "One", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+		{Title: "Two", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Three", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[3]).Error)
+
+	first, err := repo.FindPageByUserID(context.Background(), uservo.NewUserID(1), valueobjects.NewTaskID(0), 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, "One", first[0].Title().Value())
+	assert.Equal(t, "Two", first[1].Title().Value())
+
+	rest, err := repo.FindPageByUserID(context.Background(), uservo.NewUserID(1), first[1].ID(), 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, "Three", rest[0].Title().Value())
+}
+
+func TestGormTaskRepository_GetStatsByUserID_NoTasks(t *testing.T) {
+	_, repo := setupTaskRepositoryTest(t)
+
+	stats, err := repo.GetStatsByUserID(context.Background(), uservo.NewUserID(1), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, repositories.TaskStats{}, stats)
+}
+
+func newTestTags(t *testing.T, ownerID uint, names ...string) []*entities.Tag {
+	tags := make([]*entities.Tag, len(names))
+	for i, name := range names {
+		tagName, err := valueobjects.NewTagName(name)
+		require.NoError(t, err)
+		tags[i], err = entities.NewTag(tagName, uservo.NewUserID(ownerID))
+		require.NoError(t, err)
+	}
+	return tags
+}
+
+func TestGormTaskRepository_AttachAndDetachTags(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "Write report", UserID: 1},
+		{Title: "Plan sprint", UserID: 1},
+		{Title: "Other user's task", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[0].ID), newTestTags(t, 1, "work", "urgent")))
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[1].ID), newTestTags(t, 1, "work")))
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[2].ID), newTestTags(t, 2, "work")))
+
+	// Attaching an existing tag again is a no-op
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[0].ID), newTestTags(t, 1, "work")))
+
+	// Tags are shared per owner, not per task
+	var tagCount int64
+	require.NoError(t, db.Model(&dtos.Tag{}).Count(&tagCount).Error)
+	assert.Equal(t, int64(3), tagCount)
+
+	task, err := repo.FindByID(context.Background(), valueobjects.NewTaskID(seed[0].ID))
+	require.NoError(t, err)
+	require.Len(t, task.Tags(), 2)
+	assert.Equal(t, "urgent", task.Tags()[0].Value())
+	assert.Equal(t, "work", task.Tags()[1].Value())
+
+	require.NoError(t, repo.DetachTags(context.Background(), valueobjects.NewTaskID(seed[0].ID), newTestTags(t, 1, "urgent")))
+
+	task, err = repo.FindByID(context.Background(), valueobjects.NewTaskID(seed[0].ID))
+	require.NoError(t, err)
+	require.Len(t, task.Tags(), 1)
+	assert.Equal(t, "work", task.Tags()[0].Value())
+}
+
+func TestGormTaskRepository_FindByUserIDAndTag(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "b tagged", UserID: 1},
+		{Title: "a tagged", UserID: 1},
+		{Title: "untagged", UserID: 1},
+		{Title: "other user's tagged", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[0].ID), newTestTags(t, 1, "work")))
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[1].ID), newTestTags(t, 1, "work", "home")))
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[3].ID), newTestTags(t, 2, "work")))
+
+	work, err := valueobjects.NewTagName("work")
+	require.NoError(t, err)
+
+	tasks, err := repo.FindByUserIDAndTag(context.Background(), uservo.NewUserID(1), work, repositories.TaskSort{Field: repositories.TaskSortByTitle})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "a tagged", tasks[0].Title().Value())
+	assert.Equal(t, "b tagged", tasks[1].Title().Value())
+	assert.Len(t, tasks[0].Tags(), 2)
+}
+
+func TestGormTaskRepository_FindFilteredByUserIDPagesMatchingTasks(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "d high", Priority: "high", UserID: 1},
+		{Title: "a high", Priority: "high", UserID: 1},
+		{Title: "c low", Priority: "low", UserID: 1},
+		{Title: "b high", Priority: "high", UserID: 1},
+		{Title: "e high done", Priority: "high", Status: "completed", Completed: true, UserID: 1},
+		{Title: "f high trashed", Priority: "high", UserID: 1},
+		{Title: "someone else's high", Priority: "high", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[5]).Error)
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[1].ID), newTestTags(t, 1, "work")))
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[3].ID), newTestTags(t, 1, "work")))
+
+	high := valueobjects.NewHighPriority()
+	pending := valueobjects.NewPendingStatus()
+	filter := repositories.TaskFilter{Status: &pending, Priority: &high}
+	byTitle := repositories.TaskSort{Field: repositories.TaskSortByTitle}
+
+	titles := func(tasks []*entities.Task) []string {
+		values := make([]string, len(tasks))
+		for i, task := range tasks {
+			values[i] = task.Title().Value()
+		}
+		return values
+	}
+
+	page, total, err := repo.FindFilteredByUserID(context.Background(), uservo.NewUserID(1), filter, byTitle, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"a high", "b high"}, titles(page))
+
+	page, total, err = repo.FindFilteredByUserID(context.Background(), uservo.NewUserID(1), filter, byTitle, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"d high"}, titles(page))
+
+	// A zero limit returns every match; pages without a sort are in ID order
+	work, err := valueobjects.NewTagName("work")
+	require.NoError(t, err)
+	page, total, err = repo.FindFilteredByUserID(context.Background(), uservo.NewUserID(1), repositories.TaskFilter{Tag: &work}, repositories.TaskSort{}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.ElementsMatch(t, []string{"a high", "b high"}, titles(page))
+
+	page, total, err = repo.FindFilteredByUserID(context.Background(), uservo.NewUserID(1), repositories.TaskFilter{}, repositories.TaskSort{}, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Equal(t, []string{"a high", "c low"}, titles(page))
+}
+
+func TestGormTaskRepository_DeletePermanentlyRemovesTagLinks(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	task := dtos.Task{Title: "Tagged", UserID: 1}
+	require.NoError(t, db.Create(&task).Error)
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(task.ID), newTestTags(t, 1, "work")))
+
+	// Trashed tasks can be removed for good too
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(task.ID)))
+	require.NoError(t, repo.DeletePermanently(context.Background(), valueobjects.NewTaskID(task.ID)))
+
+	var links, rows int64
+	require.NoError(t, db.Model(&taskTag{}).Count(&links).Error)
+	assert.Equal(t, int64(0), links)
+	require.NoError(t, db.Unscoped().Model(&dtos.Task{}).Count(&rows).Error)
+	assert.Equal(t, int64(0), rows)
+}
+
+func TestGormTaskRepository_DeleteMovesTaskToTrash(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "Trashed first", UserID: 1},
+		{Title: "Trashed second", UserID: 1},
+		{Title: "Kept", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, repo.AttachTags(context.Background(), valueobjects.NewTaskID(seed[0].ID), newTestTags(t, 1, "work")))
+
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[0].ID)))
+	require.NoError(t, db.Model(&dtos.Task{}).Unscoped().Where("id = ?", seed[0].ID).
+		UpdateColumn("deleted_at", time.Now().Add(-time.Hour)).Error)
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[1].ID)))
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[3].ID)))
+
+	// Trashed tasks are hidden from normal lookups
+	found, err := repo.FindByID(context.Background(), valueobjects.NewTaskID(seed[0].ID))
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	live, err := repo.FindByUserID(context.Background(), uservo.NewUserID(1), repositories.TaskSort{})
+	require.NoError(t, err)
+	require.Len(t, live, 1)
+	assert.Equal(t, "Kept", live[0].Title().Value())
+
+	// The trash lists the user's tasks, most recently deleted first
+	trashed, err := repo.FindDeletedByUserID(context.Background(), uservo.NewUserID(1))
+	require.NoError(t, err)
+	require.Len(t, trashed, 2)
+	assert.Equal(t, "Trashed second", trashed[0].Title().Value())
+	assert.Equal(t, "Trashed first", trashed[1].Title().Value())
+	assert.NotNil(t, trashed[0].DeletedAt())
+
+	kept, err := repo.FindDeletedByID(context.Background(), valueobjects.NewTaskID(seed[2].ID))
+	require.NoError(t, err)
+	assert.Nil(t, kept)
+
+	// Restoring brings the task back with its tags
+	require.NoError(t, repo.Restore(context.Background(), valueobjects.NewTaskID(seed[0].ID)))
+	restored, err := repo.FindByID(context.Background(), valueobjects.NewTaskID(seed[0].ID))
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Nil(t, restored.DeletedAt())
+	assert.Len(t, restored.Tags(), 1)
+
+	assert.Error(t, repo.Restore(context.Background(), valueobjects.NewTaskID(seed[2].ID)))
+}
+
+func TestGormTaskRepository_CountByUserID(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "First", UserID: 1},
+		{Title: "Second", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[2].ID)))
+
+	count, err := repo.CountByUserID(context.Background(), uservo.NewUserID(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.CountByUserID(context.Background(), uservo.NewUserID(3))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGormTaskRepository_CountByUserIDAndStatus(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Shelved", Status: "archived", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[3].ID)))
+
+	count, err := repo.CountByUserIDAndStatus(context.Background(), uservo.NewUserID(1),
+		valueobjects.NewPendingStatus(), valueobjects.NewCompletedStatus())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.CountByUserIDAndStatus(context.Background(), uservo.NewUserID(1), valueobjects.NewArchivedStatus())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = repo.CountByUserIDAndStatus(context.Background(), uservo.NewUserID(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGormTaskRepository_UpdateRejectsStaleVersion(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	ctx := context.Background()
+
+	seed := dtos.Task{Title: "Original", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+	id := valueobjects.NewTaskID(seed.ID)
+
+	// Two requests read the same version of the task
+	first, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, 1, first.Version())
+
+	title, err := valueobjects.NewTaskTitle("First writer")
+	require.NoError(t, err)
+	require.NoError(t, first.UpdateTitle(title))
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, 2, first.Version())
+
+	// The second save is based on the version the first one replaced
+	title, err = valueobjects.NewTaskTitle("Second writer")
+	require.NoError(t, err)
+	require.NoError(t, second.UpdateTitle(title))
+	assert.ErrorIs(t, repo.Update(ctx, second), repositories.ErrTaskVersionConflict)
+
+	stored, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "First writer", stored.Title().Value())
+	assert.Equal(t, 2, stored.Version())
+
+	// Reloading picks up the new version, so the retry succeeds
+	require.NoError(t, stored.UpdateTitle(title))
+	require.NoError(t, repo.Update(ctx, stored))
+	assert.Equal(t, 3, stored.Version())
+}
+
+func TestGormTaskRepository_HonoursContextCancellation(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	require.NoError(t, db.Create(&dtos.Task{Title: "First", UserID: 1}).Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.FindByUserID(ctx, uservo.NewUserID(1), repositories.TaskSort{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = repo.AttachTags(ctx, valueobjects.NewTaskID(1), newTestTags(t, 1, "home"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGormTaskRepository_ExistsByIDAndUser(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+
+	seed := []dtos.Task{
+		{Title: "Mine", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[1].ID)))
+
+	tests := []struct {
+		name   string
+		taskID uint
+		userID uint
+		want   bool
+	}{
+		{"owned", seed[0].ID, 1, true},
+		{"someone else's", seed[0].ID, 2, false},
+		{"trashed", seed[1].ID, 1, false},
+		{"missing", 999, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := repo.ExistsByIDAndUser(context.Background(), valueobjects.NewTaskID(tt.taskID), uservo.NewUserID(tt.userID))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, exists)
+		})
+	}
+}
+
+func TestGormTaskRepository_FindUpdatedSince(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	since := time.Now().Add(-time.Hour)
+	before := since.Add(-time.Hour)
+
+	seed := []dtos.Task{
+		{Title: "Unchanged", UserID: 1, UpdatedAt: before},
+		{Title: "Edited", UserID: 1, UpdatedAt: before},
+		{Title: "Trashed", UserID: 1, UpdatedAt: before},
+		{Title: "Restored", UserID: 1, UpdatedAt: before},
+		{Title: "Someone else's", UserID: 2, UpdatedAt: before},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Model(&dtos.Task{}).Where("id = ?", seed[3].ID).UpdateColumn("deleted_at", before).Error)
+
+	require.NoError(t, db.Model(&dtos.Task{}).Where("id IN ?", []uint{seed[1].ID, seed[4].ID}).UpdateColumn("updated_at", time.Now()).Error)
+	require.NoError(t, repo.Delete(context.Background(), valueobjects.NewTaskID(seed[2].ID)))
+	require.NoError(t, repo.Restore(context.Background(), valueobjects.NewTaskID(seed[3].ID)))
+
+	changed, err := repo.FindUpdatedSince(context.Background(), uservo.NewUserID(1), since, repositories.TaskSort{})
+	require.NoError(t, err)
+
+	titles := make([]string, len(changed))
+	for i, task := range changed {
+		titles[i] = task.Title().Value()
+	}
+	assert.ElementsMatch(t, []string{"Edited", "Trashed", "Restored"}, titles)
+
+	for _, task := range changed {
+		assert.Equal(t, task.Title().Value() == "Trashed", task.DeletedAt() != nil, task.Title().Value())
+	}
+}
+
+func TestGormTaskRepository_TaskActivityNewestFirstAndPaged(t *testing.T) {
+	_, repo := setupTaskRepositoryTest(t)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	title := func(value string) *string { return &value }
+
+	var activities []*entities.TaskActivity
+	for i, field := range []string{"", "title", "priority"} {
+		action := entities.TaskActivityUpdated
+		if field == "" {
+			action = entities.TaskActivityCreated
+		}
+		activity, err := entities.NewTaskActivity(valueobjects.NewTaskID(1), uservo.NewUserID(1), action, field, nil, title("v"))
+		require.NoError(t, err)
+		// The last two share a timestamp, as entries of one update do
+		activity.LoadPersisted(0, base.Add(time.Duration(min(i, 1))*time.Minute))
+		activities = append(activities, activity)
+	}
+	other, err := entities.NewTaskActivity(valueobjects.NewTaskID(2), uservo.NewUserID(1), entities.TaskActivityCreated, "", nil, nil)
+	require.NoError(t, err)
+	activities = append(activities, other)
+
+	require.NoError(t, repo.RecordActivity(context.Background(), activities))
+	for _, activity := range activities {
+		assert.NotZero(t, activity.ID())
+	}
+
+	page, total, err := repo.FindActivityByTaskID(context.Background(), valueobjects.NewTaskID(1), 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, page, 2)
+	assert.Equal(t, "priority", page[0].Field())
+	assert.Equal(t, "title", page[1].Field())
+	assert.Nil(t, page[1].OldValue())
+	require.NotNil(t, page[1].NewValue())
+	assert.Equal(t, "v", *page[1].NewValue())
+
+	page, _, err = repo.FindActivityByTaskID(context.Background(), valueobjects.NewTaskID(1), 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, entities.TaskActivityCreated, page[0].Action())
+	assert.True(t, page[0].CreatedAt().Equal(base))
+}
+
+// countQueries counts the statements db runs from here on. Subqueries are
+// built by a dry run of the same callbacks, so dry runs are not counted.
+func countQueries(t *testing.T, db *gorm.DB) *int {
+	count := 0
+	executed := func(tx *gorm.DB) {
+		if !tx.DryRun {
+			count++
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_queries", executed))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count_rows", executed))
+	return &count
+}
+
+func TestGormTaskRepository_ListsLoadTagsInConstantQueries(t *testing.T) {
+	db, repo := setupTaskRepositoryTest(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	overdue := now.Add(-time.Hour)
+
+	shared := []dtos.Tag{{Name: "work", UserID: 1}, {Name: "home", UserID: 1}, {Name: "work", UserID: 3}}
+	require.NoError(t, db.Create(&shared).Error)
+
+	// Every task has a tag of its own as well as a shared one
+	const taskCount = 100
+	seed := make([]dtos.Task, 0, 2*taskCount)
+	for i := 0; i < taskCount; i++ {
+		seed = append(seed,
+			dtos.Task{Title: "Task", Status: "pending", Priority: "high", DueDate: &overdue, UserID: 1,
+				Tags: []dtos.Tag{shared[i%2], {Name: "own-" + strconv.Itoa(i), UserID: 1}}},
+			dtos.Task{Title: "Trashed task", UserID: 3,
+				Tags: []dtos.Tag{shared[2], {Name: "own-" + strconv.Itoa(i), UserID: 3}}},
+		)
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Where("user_id = ?", 3).Delete(&dtos.Task{}).Error)
+
+	userID := uservo.NewUserID(1)
+	work, err := valueobjects.NewTagName("work")
+	require.NoError(t, err)
+	pending, err := valueobjects.NewTaskStatus("pending")
+	require.NoError(t, err)
+	high, err := valueobjects.NewTaskPriority("high")
+	require.NoError(t, err)
+
+	lists := map[string]struct {
+		find func() ([]*entities.Task, error)
+		want int
+	}{
+		"FindByUserID": {func() ([]*entities.Task, error) {
+			return repo.FindByUserID(ctx, userID, repositories.TaskSort{})
+		}, taskCount},
+		"FindPageByUserID": {func() ([]*entities.Task, error) {
+			return repo.FindPageByUserID(ctx, userID, valueobjects.NewTaskID(0), taskCount)
+		}, taskCount},
+		"FindByUserIDAndStatus": {func() ([]*entities.Task, error) {
+			return repo.FindByUserIDAndStatus(ctx, userID, pending)
+		}, taskCount},
+		"FindByUserIDAndPriority": {func() ([]*entities.Task, error) {
+			return repo.FindByUserIDAndPriority(ctx, userID, high)
+		}, taskCount},
+		"FindByUserIDAndTag": {func() ([]*entities.Task, error) {
+			return repo.FindByUserIDAndTag(ctx, userID, work, repositories.TaskSort{})
+		}, taskCount / 2},
+		"FindUpdatedSince": {func() ([]*entities.Task, error) {
+			return repo.FindUpdatedSince(ctx, userID, time.Time{}, repositories.TaskSort{})
+		}, taskCount},
+		"SearchByText": {func() ([]*entities.Task, error) {
+			return repo.SearchByText(ctx, userID, "task")
+		}, taskCount},
+		"FindOverdueByUserID": {func() ([]*entities.Task, error) {
+			return repo.FindOverdueByUserID(ctx, userID, now)
+		}, taskCount},
+		"FindDueSoonByUserID": {func() ([]*entities.Task, error) {
+			return repo.FindDueSoonByUserID(ctx, userID, overdue.Add(-time.Minute), now)
+		}, taskCount},
+		"FindDeletedByUserID": {func() ([]*entities.Task, error) {
+			return repo.FindDeletedByUserID(ctx, uservo.NewUserID(3))
+		}, taskCount},
+	}
+
+	queries := countQueries(t, db)
+	for name, list := range lists {
+		t.Run(name, func(t *testing.T) {
+			*queries = 0
+			tasks, err := list.find()
+			require.NoError(t, err)
+			require.Len(t, tasks, list.want)
+			for _, task := range tasks {
+				assert.Len(t, task.Tags(), 2)
+			}
+
+			// The tasks, their task_tags rows and their tags
+			assert.LessOrEqual(t, *queries, 3)
+		})
+	}
+
+	// Filtered lists also count their matches
+	*queries = 0
+	tasks, total, err := repo.FindFilteredByUserID(ctx, userID, repositories.TaskFilter{Tag: &work}, repositories.TaskSort{}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(taskCount/2), total)
+	require.Len(t, tasks, taskCount/2)
+	for _, task := range tasks {
+		assert.Len(t, task.Tags(), 2)
+	}
+	assert.LessOrEqual(t, *queries, 4)
+}
@@ -0,0 +1,30 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-app/application/mappers"
+	"todo-app/application/unitofwork"
+)
+
+// gormUnitOfWork implements the UnitOfWork interface using GORM transactions
+type gormUnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewGormUnitOfWork creates a new GORM unit of work
+func NewGormUnitOfWork(db *gorm.DB) unitofwork.UnitOfWork {
+	return &gormUnitOfWork{db: db}
+}
+
+// WithTransaction runs fn with repositories bound to one database transaction
+func (u *gormUnitOfWork) WithTransaction(ctx context.Context, fn func(repos unitofwork.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(unitofwork.Repositories{
+			Tasks: NewGormTaskRepository(tx, &mappers.TaskMapper{}),
+			Users: NewGormUserRepository(tx, &mappers.UserMapper{}),
+		})
+	})
+}
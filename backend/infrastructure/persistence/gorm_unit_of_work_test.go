@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"domain/task/entities"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/application/unitofwork"
+	"todo-app/internal/dtos"
+)
+
+func setupUnitOfWorkTest(t *testing.T) (*gorm.DB, unitofwork.UnitOfWork) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}))
+
+	return db, NewGormUnitOfWork(db)
+}
+
+func newUnitOfWorkTask(t *testing.T, id uint, title string) *entities.Task {
+	taskTitle, err := valueobjects.NewTaskTitle(title)
+	require.NoError(t, err)
+	description, err := valueobjects.NewTaskDescription("")
+	require.NoError(t, err)
+
+	task, err := entities.NewTask(valueobjects.NewTaskID(id), taskTitle, description,
+		valueobjects.NewPendingStatus(), valueobjects.NewMediumPriority(), uservo.NewUserID(1))
+	require.NoError(t, err)
+	return task
+}
+
+func countTasks(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&dtos.Task{}).Count(&count).Error)
+	return count
+}
+
+func TestGormUnitOfWork_CommitsOnSuccess(t *testing.T) {
+	db, uow := setupUnitOfWorkTest(t)
+
+	err := uow.WithTransaction(context.Background(), func(repos unitofwork.Repositories) error {
+		return repos.Tasks.Save(context.Background(), newUnitOfWorkTask(t, 1, "Kept"))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), countTasks(t, db))
+}
+
+func TestGormUnitOfWork_RollsBackOnError(t *testing.T) {
+	db, uow := setupUnitOfWorkTest(t)
+	failure := errors.New("simulated failure")
+
+	err := uow.WithTransaction(context.Background(), func(repos unitofwork.Repositories) error {
+		if err := repos.Tasks.Save(context.Background(), newUnitOfWorkTask(t, 1, "First")); err != nil {
+			return err
+		}
+		if err := repos.Tasks.Save(context.Background(), newUnitOfWorkTask(t, 2, "Second")); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(0), countTasks(t, db))
+}
+
+func TestGormUnitOfWork_NestedCallsJoinOuterTransaction(t *testing.T) {
+	db, uow := setupUnitOfWorkTest(t)
+	failure := errors.New("simulated failure")
+
+	err := uow.WithTransaction(context.Background(), func(repos unitofwork.Repositories) error {
+		// The inner call commits nothing on its own
+		if err := unitofwork.Join(repos).WithTransaction(context.Background(), func(inner unitofwork.Repositories) error {
+			return inner.Tasks.Save(context.Background(), newUnitOfWorkTask(t, 1, "Inner"))
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(0), countTasks(t, db))
+}
+
+func TestGormUnitOfWork_RollsBackWhenContextEnds(t *testing.T) {
+	// A file database, so every pooled connection sees the same tables
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "uow.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}))
+	uow := NewGormUnitOfWork(db)
+
+	// The request deadline passes part way through the transaction
+	ctx, cancel := context.WithCancel(context.Background())
+	err = uow.WithTransaction(ctx, func(repos unitofwork.Repositories) error {
+		if err := repos.Tasks.Save(ctx, newUnitOfWorkTask(t, 1, "First")); err != nil {
+			return err
+		}
+		cancel()
+		return repos.Tasks.Save(ctx, newUnitOfWorkTask(t, 2, "Second"))
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The transaction is rolled back and its connection released
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return sqlDB.Stats().InUse == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(0), countTasks(t, db))
+}
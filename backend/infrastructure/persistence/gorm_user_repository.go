@@ -1,14 +1,17 @@
 package persistence
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"gorm.io/gorm"
 
+	"domain/user/entities"
+	"domain/user/repositories"
+	"domain/user/services"
+	"domain/user/valueobjects"
 	"todo-app/application/mappers"
-	"todo-app/domain/user/entities"
-	"todo-app/domain/user/repositories"
-	"todo-app/domain/user/valueobjects"
 	"todo-app/internal/dtos"
 )
 
@@ -26,23 +29,28 @@ func NewGormUserRepository(db *gorm.DB, mapper *mappers.UserMapper) repositories
 	}
 }
 
-// Save persists a user entity
-func (r *gormUserRepository) Save(user *entities.User) error {
+// Save persists a user entity and assigns it the auto-increment ID
+func (r *gormUserRepository) Save(ctx context.Context, user *entities.User) error {
 	// Convert entity to DTO using mapper
 	dto := r.mapper.ToDTO(user)
 
-	if err := r.db.Create(dto).Error; err != nil {
-		return err
+	if err := r.db.WithContext(ctx).Create(dto).Error; err != nil {
+		return translateUserWriteError(err)
+	}
+
+	// Create reads the generated ID back into the DTO
+	if user.ID().IsZero() {
+		return user.AssignID(valueobjects.NewUserID(dto.ID))
 	}
 
 	return nil
 }
 
 // FindByID retrieves a user by their ID
-func (r *gormUserRepository) FindByID(id valueobjects.UserID) (*entities.User, error) {
+func (r *gormUserRepository) FindByID(ctx context.Context, id valueobjects.UserID) (*entities.User, error) {
 	var dto dtos.User
 
-	if err := r.db.First(&dto, id.Value()).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&dto, id.Value()).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Return nil if not found, not an error
 		}
@@ -54,10 +62,25 @@ func (r *gormUserRepository) FindByID(id valueobjects.UserID) (*entities.User, e
 }
 
 // FindByEmail retrieves a user by their email address
-func (r *gormUserRepository) FindByEmail(email valueobjects.Email) (*entities.User, error) {
+func (r *gormUserRepository) FindByEmail(ctx context.Context, email valueobjects.Email) (*entities.User, error) {
+	var dto dtos.User
+
+	if err := r.db.WithContext(ctx).Where("email = ?", email.Value()).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil if not found, not an error
+		}
+		return nil, err
+	}
+
+	// Convert DTO to entity using mapper
+	return r.mapper.ToEntity(&dto)
+}
+
+// FindByVerificationToken retrieves the user holding an email verification token
+func (r *gormUserRepository) FindByVerificationToken(ctx context.Context, token string) (*entities.User, error) {
 	var dto dtos.User
 
-	if err := r.db.Where("email = ?", email.Value()).First(&dto).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("verification_token = ?", token).First(&dto).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Return nil if not found, not an error
 		}
@@ -69,18 +92,24 @@ func (r *gormUserRepository) FindByEmail(email valueobjects.Email) (*entities.Us
 }
 
 // Update updates an existing user
-func (r *gormUserRepository) Update(user *entities.User) error {
+func (r *gormUserRepository) Update(ctx context.Context, user *entities.User) error {
 	// Convert entity to DTO using mapper
 	dto := r.mapper.ToDTO(user)
 
-	// Update specific fields
-	result := r.db.Model(&dtos.User{}).Where("id = ?", dto.ID).Updates(map[string]interface{}{
-		"email": dto.Email,
-		"name":  dto.Name,
+	// Update specific fields; UpdateColumns skips the model's validation hooks,
+	// which would run against an empty model rather than the stored user
+	result := r.db.WithContext(ctx).Model(&dtos.User{}).Where("id = ?", dto.ID).UpdateColumns(map[string]interface{}{
+		"email":                         dto.Email,
+		"name":                          dto.Name,
+		"timezone":                      dto.Timezone,
+		"email_verified":                dto.EmailVerified,
+		"verification_token":            dto.VerificationToken,
+		"verification_token_expires_at": dto.VerificationTokenExpiresAt,
+		"updated_at":                    dto.UpdatedAt,
 	})
 
 	if result.Error != nil {
-		return result.Error
+		return translateUserWriteError(result.Error)
 	}
 
 	if result.RowsAffected == 0 {
@@ -91,8 +120,8 @@ func (r *gormUserRepository) Update(user *entities.User) error {
 }
 
 // Delete removes a user by ID
-func (r *gormUserRepository) Delete(id valueobjects.UserID) error {
-	result := r.db.Delete(&dtos.User{}, id.Value())
+func (r *gormUserRepository) Delete(ctx context.Context, id valueobjects.UserID) error {
+	result := r.db.WithContext(ctx).Delete(&dtos.User{}, id.Value())
 
 	if result.Error != nil {
 		return result.Error
@@ -106,10 +135,10 @@ func (r *gormUserRepository) Delete(id valueobjects.UserID) error {
 }
 
 // ExistsByID checks if a user exists by ID
-func (r *gormUserRepository) ExistsByID(id valueobjects.UserID) (bool, error) {
+func (r *gormUserRepository) ExistsByID(ctx context.Context, id valueobjects.UserID) (bool, error) {
 	var count int64
 
-	if err := r.db.Model(&dtos.User{}).Where("id = ?", id.Value()).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&dtos.User{}).Where("id = ?", id.Value()).Count(&count).Error; err != nil {
 		return false, err
 	}
 
@@ -117,10 +146,10 @@ func (r *gormUserRepository) ExistsByID(id valueobjects.UserID) (bool, error) {
 }
 
 // ExistsByEmail checks if a user exists by email address
-func (r *gormUserRepository) ExistsByEmail(email valueobjects.Email) (bool, error) {
+func (r *gormUserRepository) ExistsByEmail(ctx context.Context, email valueobjects.Email) (bool, error) {
 	var count int64
 
-	if err := r.db.Model(&dtos.User{}).Where("email = ?", email.Value()).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&dtos.User{}).Where("email = ?", email.Value()).Count(&count).Error; err != nil {
 		return false, err
 	}
 
@@ -128,10 +157,10 @@ func (r *gormUserRepository) ExistsByEmail(email valueobjects.Email) (bool, erro
 }
 
 // FindAll retrieves all users (for admin purposes)
-func (r *gormUserRepository) FindAll() ([]*entities.User, error) {
+func (r *gormUserRepository) FindAll(ctx context.Context) ([]*entities.User, error) {
 	var dtoList []dtos.User
 
-	if err := r.db.Find(&dtoList).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&dtoList).Error; err != nil {
 		return nil, err
 	}
 
@@ -149,12 +178,26 @@ func (r *gormUserRepository) FindAll() ([]*entities.User, error) {
 }
 
 // Count returns the total number of users
-func (r *gormUserRepository) Count() (int64, error) {
+func (r *gormUserRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 
-	if err := r.db.Model(&dtos.User{}).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&dtos.User{}).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
 	return count, nil
+}
+
+// translateUserWriteError maps unique email violations (e.g. from concurrent
+// registrations that both passed the uniqueness check) to ErrEmailAlreadyExists
+func translateUserWriteError(err error) error {
+	if constraint, ok := uniqueViolation(err); ok && isEmailConstraint(constraint) {
+		return fmt.Errorf("%w: %v", services.ErrEmailAlreadyExists, err)
+	}
+	return err
+}
+
+// isEmailConstraint reports whether a violated constraint is the users email index
+func isEmailConstraint(constraint string) bool {
+	return constraint == "users.email" || constraint == "idx_users_email"
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"errors"
+	"testing"
+
+	"domain/user/services"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+)
+
+func TestTranslateUserWriteError_SQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}))
+	require.NoError(t, db.Create(&dtos.User{Email: "taken@example.com", Name: "First", PasswordHash: "hash", GoogleID: "g-1", OAuthProvider: "google"}).Error)
+
+	emailErr := db.Create(&dtos.User{Email: "taken@example.com", Name: "Second", PasswordHash: "hash"}).Error
+	require.Error(t, emailErr)
+	assert.ErrorIs(t, translateUserWriteError(emailErr), services.ErrEmailAlreadyExists)
+
+	googleErr := db.Create(&dtos.User{Email: "other@example.com", Name: "Third", PasswordHash: "hash", GoogleID: "g-1", OAuthProvider: "google"}).Error
+	require.Error(t, googleErr)
+	assert.Equal(t, googleErr, translateUserWriteError(googleErr))
+}
+
+func TestTranslateUserWriteError_Postgres(t *testing.T) {
+	emailErr := &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email"}
+	assert.ErrorIs(t, translateUserWriteError(emailErr), services.ErrEmailAlreadyExists)
+
+	googleErr := &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_google_id"}
+	assert.Equal(t, error(googleErr), translateUserWriteError(googleErr))
+
+	notNullErr := &pgconn.PgError{Code: "23502", ConstraintName: "idx_users_email"}
+	assert.Equal(t, error(notNullErr), translateUserWriteError(notNullErr))
+}
+
+func TestTranslateUserWriteError_IgnoresMessageText(t *testing.T) {
+	// Classification relies on driver error codes, not matching message text
+	other := errors.New("UNIQUE constraint failed: users.email")
+	assert.Equal(t, other, translateUserWriteError(other))
+	assert.False(t, errors.Is(translateUserWriteError(errors.New("database is locked")), services.ErrEmailAlreadyExists))
+}
@@ -1,20 +0,0 @@
--- Migration: Create users table for DDD User Management
--- Date: 2025-09-28
--- Description: Creates the users table with profile and preferences support
-
-CREATE TABLE IF NOT EXISTS users (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    first_name VARCHAR(50) NOT NULL,
-    last_name VARCHAR(50) NOT NULL,
-    timezone VARCHAR(50) NOT NULL,
-    preferences TEXT, -- JSON-encoded user preferences
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create index on email for faster lookups
-CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
-
--- Create index on created_at for sorting
-CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
\ No newline at end of file
@@ -0,0 +1,84 @@
+//go:build postgres
+
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"domain/task/repositories"
+	userservices "domain/user/services"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"todo-app/application/mappers"
+	"todo-app/internal/dtos"
+	"todo-app/internal/storage"
+	"todo-app/internal/storage/storagetest"
+)
+
+// Run with: TEST_POSTGRES_DSN=postgres://... go test -tags postgres ./infrastructure/persistence/
+
+func setupPostgresRepositoryTest(t *testing.T) *gorm.DB {
+	db := storagetest.OpenPostgres(t)
+
+	migrator, err := storage.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestTranslateUserWriteError_PostgresServer(t *testing.T) {
+	db := setupPostgresRepositoryTest(t)
+	require.NoError(t, db.Create(&dtos.User{Email: "taken@example.com", Name: "First", PasswordHash: "hash", GoogleID: "g-1", OAuthProvider: "google"}).Error)
+
+	emailErr := db.Create(&dtos.User{Email: "taken@example.com", Name: "Second", PasswordHash: "hash", GoogleID: "g-2"}).Error
+	require.Error(t, emailErr)
+	assert.ErrorIs(t, translateUserWriteError(emailErr), userservices.ErrEmailAlreadyExists)
+
+	googleErr := db.Create(&dtos.User{Email: "other@example.com", Name: "Third", PasswordHash: "hash", GoogleID: "g-1", OAuthProvider: "google"}).Error
+	require.Error(t, googleErr)
+	assert.NotErrorIs(t, translateUserWriteError(googleErr), userservices.ErrEmailAlreadyExists)
+}
+
+func TestGormTaskRepository_Postgres(t *testing.T) {
+	db := setupPostgresRepositoryTest(t)
+	repo := NewGormTaskRepository(db, &mappers.TaskMapper{}).(*gormTaskRepository)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	seed := []dtos.Task{
+		{Title: "Raise budget 100%", Priority: "high", UserID: 1, DueDate: &past},
+		{Title: "Raise budget 1000", Priority: "low", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1, DueDate: &past},
+		{Title: "Someone else's 100%", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	tasks, err := repo.SearchByText(context.Background(), uservo.NewUserID(1), "100%")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Raise budget 100%", tasks[0].Title().Value())
+
+	tasks, err = repo.FindByUserID(context.Background(), uservo.NewUserID(1), repositories.TaskSort{Field: repositories.TaskSortByPriority, Order: repositories.SortAscending})
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "Raise budget 1000", tasks[0].Title().Value())
+	assert.Equal(t, "Raise budget 100%", tasks[2].Title().Value())
+
+	stats, err := repo.GetStatsByUserID(context.Background(), uservo.NewUserID(1), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, repositories.TaskStatusCounts{Pending: 2, Completed: 1}, stats.ByStatus)
+	assert.Equal(t, int64(1), stats.Overdue)
+
+	version, err := repo.GetListVersionByUserID(context.Background(), uservo.NewUserID(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version.Count)
+	assert.NotNil(t, version.LastUpdatedAt)
+	assert.Nil(t, version.LastDeletedAt)
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// DefaultAccountDeletionGracePeriod is how long a deleted account can be restored before it is purged
+const DefaultAccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// DefaultEmailVerificationTokenTTL is how long an email verification token stays valid
+const DefaultEmailVerificationTokenTTL = 24 * time.Hour
+
+// AccountConfig holds the account lifecycle settings
+type AccountConfig struct {
+	// DeletionGracePeriod is how long a deleted account can be restored, from
+	// ACCOUNT_DELETION_GRACE_PERIOD (e.g. "720h")
+	DeletionGracePeriod time.Duration
+
+	// EmailVerificationTokenTTL is how long an email verification token stays
+	// valid, from EMAIL_VERIFICATION_TOKEN_TTL (e.g. "24h")
+	EmailVerificationTokenTTL time.Duration
+}
+
+// loadAccountConfig reads the account settings
+func loadAccountConfig(env *envReader) AccountConfig {
+	return AccountConfig{
+		DeletionGracePeriod:       env.positiveDuration("ACCOUNT_DELETION_GRACE_PERIOD", DefaultAccountDeletionGracePeriod),
+		EmailVerificationTokenTTL: env.positiveDuration("EMAIL_VERIFICATION_TOKEN_TTL", DefaultEmailVerificationTokenTTL),
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"compress/gzip"
+	"math"
+)
+
+// DefaultGzipMinLength is the smallest response body, in bytes, that is compressed
+const DefaultGzipMinLength = 1024
+
+// CompressionConfig holds the API response compression settings
+type CompressionConfig struct {
+	// GzipLevel is GZIP_LEVEL: 1 (fastest) to 9 (smallest), or -1 for the default
+	GzipLevel int
+
+	// GzipMinLength is the smallest response body, in bytes, that is compressed
+	GzipMinLength int
+}
+
+// loadCompressionConfig reads the compression settings
+func loadCompressionConfig(env *envReader) CompressionConfig {
+	cfg := CompressionConfig{
+		GzipLevel:     gzip.DefaultCompression,
+		GzipMinLength: env.intInRange("GZIP_MIN_LENGTH", DefaultGzipMinLength, 1, math.MaxInt),
+	}
+
+	// 0 (no compression) is left out, since compression is the point of the setting
+	if value := env.string("GZIP_LEVEL", ""); value != "" {
+		level := env.intInRange("GZIP_LEVEL", gzip.DefaultCompression, gzip.DefaultCompression, gzip.BestCompression)
+		if level == gzip.NoCompression {
+			env.invalid("GZIP_LEVEL must be between 1 and 9, or -1 for the default, got %q", value)
+			level = gzip.DefaultCompression
+		}
+		cfg.GzipLevel = level
+	}
+
+	return cfg
+}
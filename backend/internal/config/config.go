@@ -0,0 +1,500 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"domain/auth/entities"
+)
+
+// Defaults for settings that only Load reads
+const (
+	DefaultPort               = 8080
+	DefaultShutdownTimeout    = 15 * time.Second
+	DefaultJWTExpiresHours    = 24
+	DefaultJWTClockSkew       = 60 * time.Second
+	DefaultSessionIdleTimeout = 2 * time.Hour
+	DefaultOAuthTokenTTL      = time.Hour
+)
+
+// MinJWTSecretBytes is the shortest signing secret accepted with ENV=production
+const MinJWTSecretBytes = 32
+
+// MinCSRFSecretBytes is the shortest CSRF secret accepted with ENV=production
+const MinCSRFSecretBytes = 32
+
+// DefaultCORSAllowedOrigins are the frontend development servers allowed to
+// call the API from the browser when CORS_ALLOWED_ORIGINS is not set
+var DefaultCORSAllowedOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000"}
+
+// Config is the server configuration, read from the environment once at
+// startup by Load. Pass it, or the section a component needs, to constructors
+// rather than reading the environment again.
+type Config struct {
+	// Env is ENV: production, development or test
+	Env string
+
+	Server      ServerConfig
+	Database    DatabaseConfig
+	OAuth       OAuthConfig
+	Session     SessionConfig
+	CORS        CORSConfig
+	RateLimit   RateLimitConfig
+	Tasks       TaskConfig
+	Account     AccountConfig
+	Compression CompressionConfig
+	Health      HealthConfig
+	Logging     LoggingConfig
+}
+
+// ServerConfig holds the HTTP listener and request deadline settings
+type ServerConfig struct {
+	Port int
+
+	// RequestTimeout and BulkRequestTimeout are the API request deadlines; 0 disables them
+	RequestTimeout     time.Duration
+	BulkRequestTimeout time.Duration
+
+	// ShutdownTimeout is how long in-flight requests may take to drain on shutdown
+	ShutdownTimeout time.Duration
+
+	// HSTSMaxAge is the Strict-Transport-Security max-age sent in production; 0 leaves the header out
+	HSTSMaxAge time.Duration
+}
+
+// OAuthConfig holds the OAuth provider credentials and redirect settings.
+// A provider whose client ID is empty is disabled.
+type OAuthConfig struct {
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleRedirectURI  string
+
+	GitHubClientID     string
+	GitHubClientSecret string
+	GitHubRedirectURL  string
+
+	// AllowedRedirectURIs are the exact URIs, or prefixes ending in "/*", OAuth flows may return to
+	AllowedRedirectURIs []string
+	ErrorRedirectURL    string
+	SuccessRedirectURL  string
+
+	// AutoLink links sign-ins to an existing account with the same verified
+	// email; when false the existing account must confirm the link
+	AutoLink bool
+
+	// OIDCIssuer, OIDCAudience and OIDCJWKSURL identify the OpenID Connect
+	// provider whose back-channel logout tokens are accepted
+	OIDCIssuer   string
+	OIDCAudience string
+	OIDCJWKSURL  string
+}
+
+// GoogleEnabled reports whether Google sign-in is configured
+func (c OAuthConfig) GoogleEnabled() bool {
+	return c.GoogleClientID != ""
+}
+
+// GitHubEnabled reports whether GitHub sign-in is configured
+func (c OAuthConfig) GitHubEnabled() bool {
+	return c.GitHubClientID != ""
+}
+
+// SessionConfig holds the JWT, session lifetime and cookie settings
+type SessionConfig struct {
+	// JWTKeys are comma-separated id:secret signing keys; when empty JWTSecret is the only key
+	JWTKeys         string
+	JWTSecret       string
+	JWTExpiresHours int
+	JWTClockSkew    time.Duration
+
+	// CSRFSecret keys the sessions' CSRF tokens; it must not be a JWT key
+	CSRFSecret string
+
+	TTL time.Duration
+
+	// IdleTimeout is how long a session may go unused; 0 disables it
+	IdleTimeout time.Duration
+
+	// MaxSessionsPerUser caps concurrent sessions; 0 means unlimited
+	MaxSessionsPerUser int
+
+	OAuthTokenTTL         time.Duration
+	OAuthTokenAutoRefresh bool
+
+	CookieSecure   bool
+	CookieSameSite string
+}
+
+// CORSConfig holds the origins browsers may call the API from
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+// RateLimitConfig holds the client IP and per-user rate limiter settings
+type RateLimitConfig struct {
+	// TrustedProxies are the proxy IPs/CIDRs whose forwarding headers are honored
+	TrustedProxies []string
+
+	IPTTL        time.Duration
+	IPMaxEntries int
+
+	UserPerMinute int
+	UserBurst     int
+}
+
+// ValidationError lists every invalid or missing setting found by Load
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Production reports whether the server runs with ENV=production
+func (c *Config) Production() bool {
+	return c.Env == "production"
+}
+
+// Development reports whether the server runs with ENV=development
+func (c *Config) Development() bool {
+	return c.Env == "development"
+}
+
+// Load reads the configuration from the environment and rejects invalid
+// values; the returned *ValidationError lists all of them, so every problem can
+// be fixed before the next start.
+func Load() (*Config, error) {
+	env := &envReader{}
+	cfg := &Config{Env: env.string("ENV", "")}
+
+	cfg.Server = ServerConfig{
+		Port:               env.intInRange("PORT", DefaultPort, 1, 65535),
+		RequestTimeout:     env.nonNegativeDuration("REQUEST_TIMEOUT", DefaultRequestTimeout),
+		BulkRequestTimeout: env.nonNegativeDuration("REQUEST_TIMEOUT_BULK", DefaultBulkRequestTimeout),
+		ShutdownTimeout:    env.positiveDuration("SHUTDOWN_TIMEOUT", DefaultShutdownTimeout),
+		HSTSMaxAge:         env.nonNegativeDuration("HSTS_MAX_AGE", DefaultHSTSMaxAge),
+	}
+
+	cfg.Database = loadDatabaseConfig(env)
+	cfg.OAuth = loadOAuthConfig(env, cfg.Production())
+	cfg.Session = loadSessionConfig(env, cfg.Production())
+
+	cfg.CORS = CORSConfig{AllowedOrigins: env.list("CORS_ALLOWED_ORIGINS", DefaultCORSAllowedOrigins)}
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if !isOrigin(origin) {
+			env.invalid("CORS_ALLOWED_ORIGINS entry %q must be a scheme and host, e.g. https://app.example.com", origin)
+		}
+	}
+
+	cfg.RateLimit = RateLimitConfig{
+		TrustedProxies: env.list("TRUSTED_PROXIES", nil),
+		IPTTL:          env.positiveDuration("IP_RATE_LIMIT_TTL", DefaultIPRateLimitTTL),
+		IPMaxEntries:   env.intInRange("IP_RATE_LIMIT_MAX_ENTRIES", DefaultIPRateLimitMaxEntries, 1, math.MaxInt),
+		UserPerMinute:  env.intInRange("USER_RATE_LIMIT_PER_MINUTE", DefaultUserRateLimitPerMinute, 1, math.MaxInt),
+		UserBurst:      env.intInRange("USER_RATE_LIMIT_BURST", DefaultUserRateLimitBurst, 1, math.MaxInt),
+	}
+
+	cfg.Tasks = loadTaskConfig(env)
+	cfg.Account = loadAccountConfig(env)
+	cfg.Compression = loadCompressionConfig(env)
+	cfg.Health = loadHealthConfig(env)
+	cfg.Logging = loadLoggingConfig(env)
+
+	if len(env.problems) > 0 {
+		return nil, &ValidationError{Problems: env.problems}
+	}
+	return cfg, nil
+}
+
+// loadOAuthConfig reads the OAuth settings. Providers are optional, but one
+// with a client ID needs the rest of its credentials, and production has no
+// default redirect URIs.
+func loadOAuthConfig(env *envReader, production bool) OAuthConfig {
+	cfg := OAuthConfig{
+		GoogleClientID:     env.string("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: env.string("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURI:  env.stringWithAlias("GOOGLE_REDIRECT_URI", "GOOGLE_REDIRECT_URL", ""),
+		GitHubClientID:     env.string("GITHUB_CLIENT_ID", ""),
+		GitHubClientSecret: env.string("GITHUB_CLIENT_SECRET", ""),
+		GitHubRedirectURL:  env.string("GITHUB_REDIRECT_URL", ""),
+		ErrorRedirectURL:   env.string("OAUTH_ERROR_REDIRECT_URL", DefaultOAuthErrorRedirectURL),
+		SuccessRedirectURL: env.string("OAUTH_SUCCESS_REDIRECT_URL", DefaultOAuthSuccessRedirectURL),
+		AutoLink:           env.bool("OAUTH_AUTO_LINK", true),
+		OIDCIssuer:         env.string("OIDC_ISSUER", DefaultOIDCIssuer),
+		OIDCJWKSURL:        env.string("OIDC_JWKS_URL", DefaultOIDCJWKSURL),
+	}
+	cfg.OIDCAudience = env.string("OIDC_AUDIENCE", cfg.GoogleClientID)
+
+	for _, setting := range []struct{ key, value string }{
+		{"OAUTH_ERROR_REDIRECT_URL", cfg.ErrorRedirectURL},
+		{"OAUTH_SUCCESS_REDIRECT_URL", cfg.SuccessRedirectURL},
+		{"OIDC_ISSUER", cfg.OIDCIssuer},
+		{"OIDC_JWKS_URL", cfg.OIDCJWKSURL},
+	} {
+		if !isHTTPURL(setting.value) {
+			env.invalid("%s must be an http(s) URL, got %q", setting.key, setting.value)
+		}
+	}
+
+	if cfg.GoogleEnabled() {
+		env.require("GOOGLE_CLIENT_SECRET", cfg.GoogleClientSecret, "GOOGLE_CLIENT_ID is set")
+		env.require("GOOGLE_REDIRECT_URI", cfg.GoogleRedirectURI, "GOOGLE_CLIENT_ID is set")
+	}
+	if cfg.GitHubEnabled() {
+		env.require("GITHUB_CLIENT_SECRET", cfg.GitHubClientSecret, "GITHUB_CLIENT_ID is set")
+		env.require("GITHUB_REDIRECT_URL", cfg.GitHubRedirectURL, "GITHUB_CLIENT_ID is set")
+	}
+
+	defaultURIs := DefaultOAuthRedirectURIs
+	if production {
+		defaultURIs = nil
+	}
+	cfg.AllowedRedirectURIs = env.list("OAUTH_ALLOWED_REDIRECT_URIS", defaultURIs)
+	if len(cfg.AllowedRedirectURIs) == 0 && (cfg.GoogleEnabled() || cfg.GitHubEnabled()) {
+		env.invalid("OAUTH_ALLOWED_REDIRECT_URIS is required with ENV=production when an OAuth provider is configured")
+	}
+	if _, err := entities.NewRedirectURIValidator(cfg.AllowedRedirectURIs); err != nil {
+		env.invalid("OAUTH_ALLOWED_REDIRECT_URIS: %v", err)
+	}
+
+	return cfg
+}
+
+// loadSessionConfig reads the JWT, session and cookie settings. Production
+// requires signing and CSRF secrets long enough to resist brute force.
+func loadSessionConfig(env *envReader, production bool) SessionConfig {
+	cfg := SessionConfig{
+		JWTKeys:               env.string("JWT_KEYS", ""),
+		JWTSecret:             env.string("JWT_SECRET", ""),
+		JWTExpiresHours:       env.intInRange("JWT_EXPIRES_HOURS", DefaultJWTExpiresHours, 1, math.MaxInt),
+		JWTClockSkew:          env.nonNegativeDuration("JWT_CLOCK_SKEW", DefaultJWTClockSkew),
+		CSRFSecret:            env.string("CSRF_SECRET", ""),
+		TTL:                   env.positiveDuration("SESSION_TTL", entities.DefaultSessionTTL),
+		IdleTimeout:           env.nonNegativeDuration("SESSION_IDLE_TIMEOUT", DefaultSessionIdleTimeout),
+		MaxSessionsPerUser:    env.intInRange("MAX_SESSIONS_PER_USER", 0, 0, math.MaxInt),
+		OAuthTokenTTL:         env.positiveDuration("OAUTH_TOKEN_TTL", DefaultOAuthTokenTTL),
+		OAuthTokenAutoRefresh: env.bool("OAUTH_TOKEN_AUTO_REFRESH", true),
+		CookieSecure:          env.bool("SESSION_COOKIE_SECURE", production),
+		CookieSameSite:        env.string("SESSION_COOKIE_SAMESITE", "Lax"),
+	}
+
+	if production && cfg.JWTKeys == "" {
+		if cfg.JWTSecret == "" {
+			env.invalid("JWT_SECRET or JWT_KEYS is required with ENV=production")
+		} else if len(cfg.JWTSecret) < MinJWTSecretBytes {
+			env.invalid("JWT_SECRET must be at least %d bytes with ENV=production", MinJWTSecretBytes)
+		}
+	}
+
+	if production {
+		if cfg.CSRFSecret == "" {
+			env.invalid("CSRF_SECRET is required with ENV=production")
+		} else if len(cfg.CSRFSecret) < MinCSRFSecretBytes {
+			env.invalid("CSRF_SECRET must be at least %d bytes with ENV=production", MinCSRFSecretBytes)
+		}
+	}
+	if cfg.CSRFSecret != "" && cfg.CSRFSecret == cfg.JWTSecret {
+		env.invalid("CSRF_SECRET must differ from JWT_SECRET")
+	}
+
+	if cfg.TTL > entities.MaxSessionTTL {
+		env.invalid("SESSION_TTL must not exceed %s, got %s", entities.MaxSessionTTL, cfg.TTL)
+	}
+
+	if !slices.Contains([]string{"Lax", "Strict", "None"}, cfg.CookieSameSite) {
+		env.invalid("SESSION_COOKIE_SAMESITE must be Lax, Strict or None, got %q", cfg.CookieSameSite)
+	} else if cfg.CookieSameSite == "None" && !cfg.CookieSecure {
+		env.invalid("SESSION_COOKIE_SAMESITE=None requires secure cookies (ENV=production or SESSION_COOKIE_SECURE=true)")
+	}
+
+	return cfg
+}
+
+// isHTTPURL reports whether value is an absolute http(s) URL
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isOrigin reports whether value is an http(s) origin without a path
+func isOrigin(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.User == nil
+}
+
+// envReader reads typed settings from the environment, recording each
+// invalid value and returning the default in its place so that reading can go
+// on and every problem is reported at once
+type envReader struct {
+	problems []string
+}
+
+// invalid records a problem with the configuration
+func (r *envReader) invalid(format string, args ...any) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+// require records key as missing when value is empty; reason says why it is needed
+func (r *envReader) require(key, value, reason string) {
+	if value == "" {
+		r.invalid("%s is required when %s", key, reason)
+	}
+}
+
+// string returns the trimmed value of key, or defaultValue when it is blank
+func (r *envReader) string(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// stringWithAlias returns the value of key, or of alias when key is blank
+func (r *envReader) stringWithAlias(key, alias, defaultValue string) string {
+	return r.string(key, r.string(alias, defaultValue))
+}
+
+// intInRange parses an integer between lo and hi inclusive
+func (r *envReader) intInRange(key string, defaultValue, lo, hi int) int {
+	value := r.string(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err == nil && parsed >= lo && parsed <= hi {
+		return parsed
+	}
+	if hi == math.MaxInt {
+		r.invalid("%s must be an integer of at least %d, got %q", key, lo, value)
+	} else {
+		r.invalid("%s must be an integer between %d and %d, got %q", key, lo, hi, value)
+	}
+	return defaultValue
+}
+
+// nonNegativeDuration parses a Go duration of zero or more
+func (r *envReader) nonNegativeDuration(key string, defaultValue time.Duration) time.Duration {
+	duration, ok := r.duration(key, defaultValue)
+	if ok && duration < 0 {
+		r.invalid("%s must not be negative, got %s", key, duration)
+		return defaultValue
+	}
+	return duration
+}
+
+// positiveDuration parses a Go duration greater than zero
+func (r *envReader) positiveDuration(key string, defaultValue time.Duration) time.Duration {
+	duration, ok := r.duration(key, defaultValue)
+	if ok && duration <= 0 {
+		r.invalid("%s must be positive, got %s", key, duration)
+		return defaultValue
+	}
+	return duration
+}
+
+// duration parses a Go duration; ok is false when the default was used
+func (r *envReader) duration(key string, defaultValue time.Duration) (duration time.Duration, ok bool) {
+	value := r.string(key, "")
+	if value == "" {
+		return defaultValue, false
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		r.invalid("%s must be a duration such as 30s or 5m, got %q", key, value)
+		return defaultValue, false
+	}
+	return duration, true
+}
+
+// bool parses true or false, or any other form strconv.ParseBool accepts
+func (r *envReader) bool(key string, defaultValue bool) bool {
+	value := r.string(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		r.invalid("%s must be true or false, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// list splits a comma-separated value, dropping blank entries; defaultValue
+// is used when there are none
+func (r *envReader) list(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+// redacted replaces a secret with a fixed mask, keeping whether it is set visible
+func redacted(secret string) string {
+	if secret == "" {
+		return "(unset)"
+	}
+	return "****"
+}
+
+// dsnPassword matches the password of a key=value Postgres connection string
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// redactedDSN masks the password in a Postgres URL or key=value connection
+// string; SQLite paths are returned unchanged
+func redactedDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}xxxxx")
+}
+
+// String describes the configuration for startup logging, with secrets and
+// the database password masked
+func (c *Config) String() string {
+	var b strings.Builder
+	env := c.Env
+	if env == "" {
+		env = "(unset)"
+	}
+	fmt.Fprintf(&b, "env=%s", env)
+	fmt.Fprintf(&b, " server={port=%d request_timeout=%s bulk_request_timeout=%s shutdown_timeout=%s hsts_max_age=%s}",
+		c.Server.Port, c.Server.RequestTimeout, c.Server.BulkRequestTimeout, c.Server.ShutdownTimeout, c.Server.HSTSMaxAge)
+	fmt.Fprintf(&b, " database={driver=%s dsn=%s max_open_conns=%d max_idle_conns=%d conn_max_lifetime=%s slow_query_threshold=%s}",
+		c.Database.Driver, redactedDSN(c.Database.DSN), c.Database.MaxOpenConns, c.Database.MaxIdleConns, c.Database.ConnMaxLifetime, c.Database.SlowQueryThreshold)
+	fmt.Fprintf(&b, " oauth={google_client_id=%s google_client_secret=%s github_client_id=%s github_client_secret=%s allowed_redirect_uris=%v auto_link=%t}",
+		c.OAuth.GoogleClientID, redacted(c.OAuth.GoogleClientSecret), c.OAuth.GitHubClientID, redacted(c.OAuth.GitHubClientSecret), c.OAuth.AllowedRedirectURIs, c.OAuth.AutoLink)
+	fmt.Fprintf(&b, " session={jwt_secret=%s jwt_keys=%s csrf_secret=%s jwt_expires_hours=%d ttl=%s idle_timeout=%s max_sessions_per_user=%d cookie_secure=%t cookie_samesite=%s}",
+		redacted(c.Session.JWTSecret), redacted(c.Session.JWTKeys), redacted(c.Session.CSRFSecret), c.Session.JWTExpiresHours, c.Session.TTL, c.Session.IdleTimeout, c.Session.MaxSessionsPerUser, c.Session.CookieSecure, c.Session.CookieSameSite)
+	fmt.Fprintf(&b, " cors={allowed_origins=%v}", c.CORS.AllowedOrigins)
+	fmt.Fprintf(&b, " rate_limit={trusted_proxies=%v ip_ttl=%s ip_max_entries=%d user_per_minute=%d user_burst=%d}",
+		c.RateLimit.TrustedProxies, c.RateLimit.IPTTL, c.RateLimit.IPMaxEntries, c.RateLimit.UserPerMinute, c.RateLimit.UserBurst)
+	fmt.Fprintf(&b, " tasks={list_warning_threshold=%d due_date_min_offset=%s due_date_max_offset=%s max_tasks_per_user=%d quota_exclude_finished=%t}",
+		c.Tasks.ListWarningThreshold, c.Tasks.DueDateBounds.MinOffset, c.Tasks.DueDateBounds.MaxOffset, c.Tasks.MaxTasksPerUser, c.Tasks.QuotaExcludeFinished)
+	fmt.Fprintf(&b, " account={deletion_grace_period=%s email_verification_token_ttl=%s}",
+		c.Account.DeletionGracePeriod, c.Account.EmailVerificationTokenTTL)
+	fmt.Fprintf(&b, " compression={gzip_level=%d gzip_min_length=%d}", c.Compression.GzipLevel, c.Compression.GzipMinLength)
+	fmt.Fprintf(&b, " health={cache_ttl=%s oauth_check_interval=%s}", c.Health.CacheTTL, c.Health.OAuthCheckInterval)
+	fmt.Fprintf(&b, " logging={level=%s format=%s}", c.Logging.Level, c.Logging.Format)
+	return b.String()
+}
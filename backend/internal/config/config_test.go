@@ -0,0 +1,275 @@
+package config
+
+import (
+	"compress/gzip"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"domain/task/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configEnvKeys are the variables Load reads, cleared so the host
+// environment cannot leak into the tests
+var configEnvKeys = []string{
+	"ENV", "PORT", "REQUEST_TIMEOUT", "REQUEST_TIMEOUT_BULK", "SHUTDOWN_TIMEOUT", "HSTS_MAX_AGE",
+	"DATABASE_DRIVER", "DB_DRIVER", "DATABASE_DSN", "DB_DSN", "DB_PATH", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS",
+	"DB_CONN_MAX_LIFETIME", "DB_SLOW_QUERY_MS", "DB_SLOW_QUERY_THRESHOLD", "DB_CONNECT_MAX_ATTEMPTS", "DB_CONNECT_RETRY_DELAY",
+	"GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET", "GOOGLE_REDIRECT_URI", "GOOGLE_REDIRECT_URL",
+	"GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "GITHUB_REDIRECT_URL",
+	"OAUTH_ALLOWED_REDIRECT_URIS", "OAUTH_ERROR_REDIRECT_URL", "OAUTH_SUCCESS_REDIRECT_URL", "OAUTH_AUTO_LINK", "OIDC_ISSUER", "OIDC_AUDIENCE", "OIDC_JWKS_URL",
+	"JWT_KEYS", "JWT_SECRET", "CSRF_SECRET", "JWT_EXPIRES_HOURS", "JWT_CLOCK_SKEW", "SESSION_TTL", "SESSION_IDLE_TIMEOUT",
+	"MAX_SESSIONS_PER_USER", "OAUTH_TOKEN_TTL", "OAUTH_TOKEN_AUTO_REFRESH", "SESSION_COOKIE_SECURE", "SESSION_COOKIE_SAMESITE",
+	"CORS_ALLOWED_ORIGINS", "TRUSTED_PROXIES", "IP_RATE_LIMIT_TTL", "IP_RATE_LIMIT_MAX_ENTRIES",
+	"USER_RATE_LIMIT_PER_MINUTE", "USER_RATE_LIMIT_BURST",
+	"TASK_LIST_WARNING_THRESHOLD", "DUE_DATE_MIN_OFFSET", "DUE_DATE_MAX_OFFSET", "MAX_TASKS_PER_USER", "TASK_QUOTA_EXCLUDE_FINISHED",
+	"ACCOUNT_DELETION_GRACE_PERIOD", "EMAIL_VERIFICATION_TOKEN_TTL", "GZIP_LEVEL", "GZIP_MIN_LENGTH",
+	"HEALTH_CACHE_TTL", "HEALTH_OAUTH_CHECK_INTERVAL", "LOG_LEVEL", "LOG_FORMAT",
+}
+
+// setConfigEnv clears every variable Load reads, then sets env
+func setConfigEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+// productionEnv is a valid production environment for cases to modify
+func productionEnv() map[string]string {
+	return map[string]string{
+		"ENV":         "production",
+		"JWT_SECRET":  strings.Repeat("s", MinJWTSecretBytes),
+		"CSRF_SECRET": strings.Repeat("c", MinCSRFSecretBytes),
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setConfigEnv(t, nil)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Production())
+	assert.Equal(t, DefaultPort, cfg.Server.Port)
+	assert.Equal(t, DefaultRequestTimeout, cfg.Server.RequestTimeout)
+	assert.Equal(t, DefaultShutdownTimeout, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, DatabaseDriverSQLite, cfg.Database.Driver)
+	assert.Equal(t, "todo.db", cfg.Database.DSN)
+	assert.Equal(t, DefaultDBSlowQueryThreshold, cfg.Database.SlowQueryThreshold)
+	assert.Equal(t, DefaultOAuthRedirectURIs, cfg.OAuth.AllowedRedirectURIs)
+	assert.True(t, cfg.OAuth.AutoLink)
+	assert.Equal(t, DefaultOAuthSuccessRedirectURL, cfg.OAuth.SuccessRedirectURL)
+	assert.False(t, cfg.OAuth.GoogleEnabled())
+	assert.Equal(t, DefaultJWTExpiresHours, cfg.Session.JWTExpiresHours)
+	assert.Equal(t, DefaultSessionIdleTimeout, cfg.Session.IdleTimeout)
+	assert.False(t, cfg.Session.CookieSecure)
+	assert.Equal(t, "Lax", cfg.Session.CookieSameSite)
+	assert.Equal(t, DefaultCORSAllowedOrigins, cfg.CORS.AllowedOrigins)
+	assert.Empty(t, cfg.RateLimit.TrustedProxies)
+	assert.Equal(t, DefaultUserRateLimitPerMinute, cfg.RateLimit.UserPerMinute)
+	assert.Equal(t, DefaultTaskListWarningThreshold, cfg.Tasks.ListWarningThreshold)
+	assert.Equal(t, valueobjects.DefaultDueDateBounds(), cfg.Tasks.DueDateBounds)
+	assert.Equal(t, int64(DefaultMaxTasksPerUser), cfg.Tasks.MaxTasksPerUser)
+	assert.False(t, cfg.Tasks.QuotaExcludeFinished)
+	assert.Equal(t, DefaultAccountDeletionGracePeriod, cfg.Account.DeletionGracePeriod)
+	assert.Equal(t, DefaultEmailVerificationTokenTTL, cfg.Account.EmailVerificationTokenTTL)
+	assert.Equal(t, gzip.DefaultCompression, cfg.Compression.GzipLevel)
+	assert.Equal(t, DefaultGzipMinLength, cfg.Compression.GzipMinLength)
+	assert.Equal(t, DefaultHealthCacheTTL, cfg.Health.CacheTTL)
+	assert.Equal(t, slog.LevelInfo, cfg.Logging.Level)
+	assert.Equal(t, LogFormatText, cfg.Logging.Format)
+	assert.Equal(t, DefaultOIDCIssuer, cfg.OAuth.OIDCIssuer)
+	assert.Equal(t, "https://accounts.google.com/.well-known/openid-configuration", cfg.OAuth.DiscoveryURL())
+}
+
+func TestLoad_ReadsSettings(t *testing.T) {
+	env := productionEnv()
+	env["PORT"] = "9090"
+	env["SHUTDOWN_TIMEOUT"] = "30s"
+	env["REQUEST_TIMEOUT"] = "0"
+	env["DB_DRIVER"] = "postgres"
+	env["DB_DSN"] = "postgres://todo:secret@db:5432/todo"
+	env["DB_SLOW_QUERY_MS"] = "0"
+	env["GOOGLE_CLIENT_ID"] = "client"
+	env["GOOGLE_CLIENT_SECRET"] = "secret"
+	env["GOOGLE_REDIRECT_URL"] = "https://api.example.com/api/v1/auth/google/callback"
+	env["OAUTH_ALLOWED_REDIRECT_URIS"] = "https://app.example.com/*"
+	env["CORS_ALLOWED_ORIGINS"] = "https://app.example.com, https://admin.example.com"
+	env["TRUSTED_PROXIES"] = "10.0.0.0/8"
+	env["SESSION_COOKIE_SAMESITE"] = "None"
+	env["DUE_DATE_MIN_OFFSET"] = "-720h"
+	env["MAX_TASKS_PER_USER"] = "0"
+	env["TASK_QUOTA_EXCLUDE_FINISHED"] = "true"
+	env["ACCOUNT_DELETION_GRACE_PERIOD"] = "168h"
+	env["GZIP_LEVEL"] = "9"
+	env["HEALTH_OAUTH_CHECK_INTERVAL"] = "0"
+	env["LOG_LEVEL"] = "debug"
+	env["LOG_FORMAT"] = "JSON"
+	setConfigEnv(t, env)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Production())
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Zero(t, cfg.Server.RequestTimeout)
+	assert.Equal(t, DatabaseDriverPostgres, cfg.Database.Driver)
+	assert.Equal(t, "postgres://todo:secret@db:5432/todo", cfg.Database.DSN)
+	assert.Zero(t, cfg.Database.SlowQueryThreshold)
+	assert.True(t, cfg.OAuth.GoogleEnabled())
+	assert.Equal(t, "https://api.example.com/api/v1/auth/google/callback", cfg.OAuth.GoogleRedirectURI)
+	assert.Equal(t, []string{"https://app.example.com/*"}, cfg.OAuth.AllowedRedirectURIs)
+	assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, []string{"10.0.0.0/8"}, cfg.RateLimit.TrustedProxies)
+	assert.Equal(t, strings.Repeat("c", MinCSRFSecretBytes), cfg.Session.CSRFSecret)
+	assert.True(t, cfg.Session.CookieSecure)
+	assert.Equal(t, "None", cfg.Session.CookieSameSite)
+	assert.Equal(t, "client", cfg.OAuth.OIDCAudience)
+	assert.Equal(t, -720*time.Hour, cfg.Tasks.DueDateBounds.MinOffset)
+	assert.Zero(t, cfg.Tasks.MaxTasksPerUser)
+	assert.True(t, cfg.Tasks.QuotaExcludeFinished)
+	assert.Equal(t, 168*time.Hour, cfg.Account.DeletionGracePeriod)
+	assert.Equal(t, gzip.BestCompression, cfg.Compression.GzipLevel)
+	assert.Zero(t, cfg.Health.OAuthCheckInterval)
+	assert.Equal(t, slog.LevelDebug, cfg.Logging.Level)
+	assert.Equal(t, LogFormatJSON, cfg.Logging.Format)
+}
+
+func TestLoad_Validation(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		// problem is part of the expected message; empty means the settings are valid
+		problem string
+	}{
+		{"lowest port", map[string]string{"PORT": "1"}, ""},
+		{"highest port", map[string]string{"PORT": "65535"}, ""},
+		{"port zero", map[string]string{"PORT": "0"}, "PORT must be an integer between 1 and 65535"},
+		{"port too high", map[string]string{"PORT": "65536"}, "PORT must be an integer between 1 and 65535"},
+		{"port not a number", map[string]string{"PORT": "http"}, "PORT must be an integer"},
+		{"request timeout disabled", map[string]string{"REQUEST_TIMEOUT": "0"}, ""},
+		{"negative request timeout", map[string]string{"REQUEST_TIMEOUT": "-1s"}, "REQUEST_TIMEOUT must not be negative"},
+		{"zero shutdown timeout", map[string]string{"SHUTDOWN_TIMEOUT": "0s"}, "SHUTDOWN_TIMEOUT must be positive"},
+		{"unparseable duration", map[string]string{"SESSION_TTL": "a day"}, "SESSION_TTL must be a duration"},
+		{"unknown database driver", map[string]string{"DATABASE_DRIVER": "mysql"}, "unsupported DATABASE_DRIVER"},
+		{"postgres without dsn", map[string]string{"DATABASE_DRIVER": "postgres"}, "DATABASE_DSN is required"},
+		{"development without jwt secret", map[string]string{"ENV": "development"}, ""},
+		{"production without jwt secret", map[string]string{"ENV": "production", "CSRF_SECRET": strings.Repeat("c", MinCSRFSecretBytes)}, "JWT_SECRET or JWT_KEYS is required"},
+		{"production with short jwt secret", map[string]string{"ENV": "production", "JWT_SECRET": "short", "CSRF_SECRET": strings.Repeat("c", MinCSRFSecretBytes)}, "JWT_SECRET must be at least 32 bytes"},
+		{"production with jwt keys", map[string]string{"ENV": "production", "JWT_KEYS": "2024a:" + strings.Repeat("k", MinJWTSecretBytes), "CSRF_SECRET": strings.Repeat("c", MinCSRFSecretBytes)}, ""},
+		{"production without csrf secret", map[string]string{"ENV": "production", "JWT_SECRET": strings.Repeat("s", MinJWTSecretBytes)}, "CSRF_SECRET is required"},
+		{"production with short csrf secret", map[string]string{"ENV": "production", "JWT_SECRET": strings.Repeat("s", MinJWTSecretBytes), "CSRF_SECRET": "short"}, "CSRF_SECRET must be at least 32 bytes"},
+		{"csrf secret reusing the jwt secret", map[string]string{"JWT_SECRET": "shared-secret", "CSRF_SECRET": "shared-secret"}, "CSRF_SECRET must differ from JWT_SECRET"},
+		{"zero jwt lifetime", map[string]string{"JWT_EXPIRES_HOURS": "0"}, "JWT_EXPIRES_HOURS must be an integer of at least 1"},
+		{"unknown samesite", map[string]string{"SESSION_COOKIE_SAMESITE": "lax"}, "SESSION_COOKIE_SAMESITE must be Lax, Strict or None"},
+		{"samesite none without secure cookies", map[string]string{"SESSION_COOKIE_SAMESITE": "None"}, "SESSION_COOKIE_SAMESITE=None requires secure cookies"},
+		{"samesite none with secure cookies", map[string]string{"SESSION_COOKIE_SAMESITE": "None", "SESSION_COOKIE_SECURE": "true"}, ""},
+		{"unparseable bool", map[string]string{"OAUTH_AUTO_LINK": "sometimes"}, "OAUTH_AUTO_LINK must be true or false"},
+		{"google without secret", map[string]string{"GOOGLE_CLIENT_ID": "client", "GOOGLE_REDIRECT_URI": "http://localhost:8080/cb"}, "GOOGLE_CLIENT_SECRET is required when GOOGLE_CLIENT_ID is set"},
+		{"github without redirect url", map[string]string{"GITHUB_CLIENT_ID": "client", "GITHUB_CLIENT_SECRET": "secret"}, "GITHUB_REDIRECT_URL is required when GITHUB_CLIENT_ID is set"},
+		{"production provider without redirect uris", map[string]string{
+			"ENV": "production", "JWT_SECRET": strings.Repeat("s", MinJWTSecretBytes), "CSRF_SECRET": strings.Repeat("c", MinCSRFSecretBytes),
+			"GITHUB_CLIENT_ID": "client", "GITHUB_CLIENT_SECRET": "secret", "GITHUB_REDIRECT_URL": "https://api.example.com/cb",
+		}, "OAUTH_ALLOWED_REDIRECT_URIS is required with ENV=production"},
+		{"invalid redirect uri", map[string]string{"OAUTH_ALLOWED_REDIRECT_URIS": "https://*.example.com/"}, "OAUTH_ALLOWED_REDIRECT_URIS"},
+		{"cors origin with path", map[string]string{"CORS_ALLOWED_ORIGINS": "https://app.example.com/"}, "CORS_ALLOWED_ORIGINS entry"},
+		{"cors origin without scheme", map[string]string{"CORS_ALLOWED_ORIGINS": "app.example.com"}, "CORS_ALLOWED_ORIGINS entry"},
+		{"zero rate limit burst", map[string]string{"USER_RATE_LIMIT_BURST": "0"}, "USER_RATE_LIMIT_BURST must be an integer of at least 1"},
+		{"zero ip rate limit ttl", map[string]string{"IP_RATE_LIMIT_TTL": "0"}, "IP_RATE_LIMIT_TTL must be positive"},
+		{"longest session ttl", map[string]string{"SESSION_TTL": "720h"}, ""},
+		{"session ttl too long", map[string]string{"SESSION_TTL": "721h"}, "SESSION_TTL must not exceed"},
+		{"error redirect url without scheme", map[string]string{"OAUTH_ERROR_REDIRECT_URL": "app.example.com/signup"}, "OAUTH_ERROR_REDIRECT_URL must be an http(s) URL"},
+		{"success redirect url without scheme", map[string]string{"OAUTH_SUCCESS_REDIRECT_URL": "app.example.com/"}, "OAUTH_SUCCESS_REDIRECT_URL must be an http(s) URL"},
+		{"invalid oidc issuer", map[string]string{"OIDC_ISSUER": "accounts.google.com"}, "OIDC_ISSUER must be an http(s) URL"},
+		{"zero connect attempts", map[string]string{"DB_CONNECT_MAX_ATTEMPTS": "0"}, "DB_CONNECT_MAX_ATTEMPTS must be an integer of at least 1"},
+		{"negative pool size", map[string]string{"DB_MAX_OPEN_CONNS": "-1"}, "DB_MAX_OPEN_CONNS must be an integer of at least 0"},
+		{"negative slow query threshold", map[string]string{"DB_SLOW_QUERY_MS": "-5"}, "DB_SLOW_QUERY_MS must be an integer of at least 0"},
+		{"negative warning threshold", map[string]string{"TASK_LIST_WARNING_THRESHOLD": "-1"}, "TASK_LIST_WARNING_THRESHOLD must be an integer of at least 0"},
+		{"past due date window", map[string]string{"DUE_DATE_MIN_OFFSET": "48h", "DUE_DATE_MAX_OFFSET": "24h"}, "DUE_DATE_MIN_OFFSET (48h0m0s) must not be greater than DUE_DATE_MAX_OFFSET"},
+		{"negative task quota", map[string]string{"MAX_TASKS_PER_USER": "-1"}, "MAX_TASKS_PER_USER must be an integer of at least 0"},
+		{"zero deletion grace period", map[string]string{"ACCOUNT_DELETION_GRACE_PERIOD": "0s"}, "ACCOUNT_DELETION_GRACE_PERIOD must be positive"},
+		{"zero verification token ttl", map[string]string{"EMAIL_VERIFICATION_TOKEN_TTL": "0s"}, "EMAIL_VERIFICATION_TOKEN_TTL must be positive"},
+		{"gzip level disabling compression", map[string]string{"GZIP_LEVEL": "0"}, "GZIP_LEVEL must be between 1 and 9"},
+		{"gzip level too high", map[string]string{"GZIP_LEVEL": "10"}, "GZIP_LEVEL must be an integer between -1 and 9"},
+		{"zero gzip min length", map[string]string{"GZIP_MIN_LENGTH": "0"}, "GZIP_MIN_LENGTH must be an integer of at least 1"},
+		{"negative health cache ttl", map[string]string{"HEALTH_CACHE_TTL": "-1s"}, "HEALTH_CACHE_TTL must not be negative"},
+		{"unknown log level", map[string]string{"LOG_LEVEL": "verbose"}, "LOG_LEVEL must be debug, info, warn or error"},
+		{"unknown log format", map[string]string{"LOG_FORMAT": "xml"}, "LOG_FORMAT must be text or json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setConfigEnv(t, tc.env)
+
+			cfg, err := Load()
+			if tc.problem == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, cfg)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a *ValidationError, got %v", err)
+			require.Len(t, validationErr.Problems, 1, validationErr.Error())
+			assert.Contains(t, validationErr.Problems[0], tc.problem)
+			assert.Nil(t, cfg)
+		})
+	}
+}
+
+func TestLoad_ListsEveryProblem(t *testing.T) {
+	setConfigEnv(t, map[string]string{
+		"ENV":                   "production",
+		"PORT":                  "0",
+		"SHUTDOWN_TIMEOUT":      "-1s",
+		"DATABASE_DRIVER":       "postgres",
+		"USER_RATE_LIMIT_BURST": "none",
+	})
+
+	_, err := Load()
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Problems, 6)
+	for _, key := range []string{"PORT", "SHUTDOWN_TIMEOUT", "DATABASE_DSN", "JWT_SECRET", "CSRF_SECRET", "USER_RATE_LIMIT_BURST"} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
+
+func TestConfig_StringRedactsSecrets(t *testing.T) {
+	cases := []struct {
+		name   string
+		dsn    string
+		secret string
+	}{
+		{"postgres url", "postgres://todo:db-password@db:5432/todo?sslmode=disable", "db-password"},
+		{"postgres key value", "host=pg user=todo password=db-password dbname=todo", "db-password"},
+		{"postgres quoted key value", "host=pg password='db password' dbname=todo", "db password"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				Env:      "production",
+				Database: DatabaseConfig{Driver: DatabaseDriverPostgres, DSN: tc.dsn},
+				OAuth:    OAuthConfig{GoogleClientID: "google-client", GoogleClientSecret: "google-secret"},
+				Session:  SessionConfig{JWTSecret: "jwt-secret-value", JWTKeys: "2024a:jwt-key-value", CSRFSecret: "csrf-secret-value"},
+			}
+
+			out := cfg.String()
+			for _, secret := range []string{tc.secret, "google-secret", "jwt-secret-value", "jwt-key-value", "csrf-secret-value"} {
+				assert.NotContains(t, out, secret)
+			}
+			assert.Contains(t, out, "google_client_id=google-client")
+			assert.Contains(t, out, "github_client_secret=(unset)")
+			assert.Contains(t, out, "xxxxx")
+		})
+	}
+}
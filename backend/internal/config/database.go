@@ -1,111 +1,102 @@
 package config
 
 import (
-	"fmt"
-	"os"
+	"math"
 	"time"
+)
+
+// Supported DATABASE_DRIVER values
+const (
+	DatabaseDriverSQLite   = "sqlite"
+	DatabaseDriverPostgres = "postgres"
+)
 
-	"domain/auth/entities"
-	"domain/auth/valueobjects"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-	"todo-app/internal/dtos"
+// Default connection pool and logging settings
+const (
+	DefaultDBMaxOpenConns       = 25
+	DefaultDBMaxIdleConns       = 5
+	DefaultDBConnMaxLifetime    = 30 * time.Minute
+	DefaultDBSlowQueryThreshold = 200 * time.Millisecond
+	DefaultDBConnectMaxAttempts = 5
+	DefaultDBConnectRetryDelay  = time.Second
 )
 
-// DatabaseConfig holds database configuration
+// DatabaseConfig holds database connection and pool configuration
 type DatabaseConfig struct {
-	DSN             string
-	MaxIdleConns    int
+	// Env is ENV; test silences the query log and production only logs slow queries
+	Env string
+
+	// Driver is DatabaseDriverSQLite or DatabaseDriverPostgres
+	Driver string
+
+	// DSN is the SQLite file path or the Postgres connection string
+	DSN string
+
 	MaxOpenConns    int
+	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
-	LogLevel        logger.LogLevel
-}
-
-// GetDefaultDatabaseConfig returns default database configuration
-func GetDefaultDatabaseConfig() DatabaseConfig {
-	dsn := os.Getenv("DATABASE_DSN")
-	if dsn == "" {
-		dsn = "./todo.db" // Default SQLite database
-	}
 
-	logLevel := logger.Info
-	if os.Getenv("APP_ENV") == "production" {
-		logLevel = logger.Error
-	}
+	// SlowQueryThreshold is the duration above which queries are logged; 0 disables slow query logging
+	SlowQueryThreshold time.Duration
 
-	return DatabaseConfig{
-		DSN:             dsn,
-		MaxIdleConns:    10,
-		MaxOpenConns:    100,
-		ConnMaxLifetime: time.Hour,
-		LogLevel:        logLevel,
-	}
+	// ConnectMaxAttempts bounds attempts at the initial connection; the delay
+	// between attempts starts at ConnectRetryDelay and doubles each time
+	ConnectMaxAttempts int
+	ConnectRetryDelay  time.Duration
 }
 
-// NewDatabaseConnection creates a new database connection
-func NewDatabaseConnection(config DatabaseConfig) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(config.DSN), &gorm.Config{
-		Logger: logger.Default.LogMode(config.LogLevel),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
+// DefaultSQLitePath is the SQLite database file used when DB_PATH is not set
+const DefaultSQLitePath = "todo.db"
 
-	// Get underlying SQL database for connection pool settings
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get database instance: %w", err)
+// LoadDatabase reads and validates only the database settings, for commands
+// such as migrate that do not start the server
+func LoadDatabase() (DatabaseConfig, error) {
+	env := &envReader{}
+	cfg := loadDatabaseConfig(env)
+	if len(env.problems) > 0 {
+		return DatabaseConfig{}, &ValidationError{Problems: env.problems}
 	}
-
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
-	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
-
-	return db, nil
-}
-
-// AutoMigrate runs database migrations for all models
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&dtos.User{},
-		&valueobjects.GoogleIdentity{},
-		&entities.AuthenticationSession{},
-		&entities.OAuthState{},
-	)
+	return cfg, nil
 }
 
-// InitializeDatabase initializes database connection and runs migrations
-func InitializeDatabase() (*gorm.DB, error) {
-	config := GetDefaultDatabaseConfig()
-	db, err := NewDatabaseConnection(config)
-	if err != nil {
-		return nil, err
+// loadDatabaseConfig reads the database settings: DATABASE_DRIVER (sqlite or
+// postgres, default sqlite); DB_PATH for SQLite (default todo.db) or
+// DATABASE_DSN for Postgres, with DB_DRIVER and DB_DSN accepted as shorter
+// aliases; DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME for
+// the connection pool; DB_SLOW_QUERY_MS for slow query logging; and
+// DB_CONNECT_MAX_ATTEMPTS and DB_CONNECT_RETRY_DELAY for retrying the initial
+// connection
+func loadDatabaseConfig(env *envReader) DatabaseConfig {
+	cfg := DatabaseConfig{
+		Env:                env.string("ENV", ""),
+		Driver:             env.stringWithAlias("DATABASE_DRIVER", "DB_DRIVER", DatabaseDriverSQLite),
+		MaxOpenConns:       env.intInRange("DB_MAX_OPEN_CONNS", DefaultDBMaxOpenConns, 0, math.MaxInt),
+		MaxIdleConns:       env.intInRange("DB_MAX_IDLE_CONNS", DefaultDBMaxIdleConns, 0, math.MaxInt),
+		ConnMaxLifetime:    env.nonNegativeDuration("DB_CONN_MAX_LIFETIME", DefaultDBConnMaxLifetime),
+		SlowQueryThreshold: loadSlowQueryThreshold(env),
+		ConnectMaxAttempts: env.intInRange("DB_CONNECT_MAX_ATTEMPTS", DefaultDBConnectMaxAttempts, 1, math.MaxInt),
+		ConnectRetryDelay:  env.nonNegativeDuration("DB_CONNECT_RETRY_DELAY", DefaultDBConnectRetryDelay),
 	}
 
-	// Run auto migrations
-	if err := AutoMigrate(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	switch cfg.Driver {
+	case DatabaseDriverSQLite:
+		cfg.DSN = env.string("DB_PATH", DefaultSQLitePath)
+	case DatabaseDriverPostgres:
+		cfg.DSN = env.stringWithAlias("DATABASE_DSN", "DB_DSN", "")
+		env.require("DATABASE_DSN", cfg.DSN, "DATABASE_DRIVER is "+DatabaseDriverPostgres)
+	default:
+		env.invalid("unsupported DATABASE_DRIVER %q (expected %s or %s)", cfg.Driver, DatabaseDriverSQLite, DatabaseDriverPostgres)
 	}
 
-	return db, nil
+	return cfg
 }
 
-// HealthCheck performs a database health check
-func HealthCheck(db *gorm.DB) error {
-	sqlDB, err := db.DB()
-	if err != nil {
-		return err
+// loadSlowQueryThreshold reads DB_SLOW_QUERY_MS in whole milliseconds, falling
+// back to DB_SLOW_QUERY_THRESHOLD, a Go duration, for older configurations
+func loadSlowQueryThreshold(env *envReader) time.Duration {
+	if env.string("DB_SLOW_QUERY_MS", "") == "" {
+		return env.nonNegativeDuration("DB_SLOW_QUERY_THRESHOLD", DefaultDBSlowQueryThreshold)
 	}
-	return sqlDB.Ping()
+	ms := env.intInRange("DB_SLOW_QUERY_MS", int(DefaultDBSlowQueryThreshold/time.Millisecond), 0, math.MaxInt)
+	return time.Duration(ms) * time.Millisecond
 }
-
-// CloseDatabase gracefully closes database connection
-func CloseDatabase(db *gorm.DB) error {
-	sqlDB, err := db.DB()
-	if err != nil {
-		return err
-	}
-	return sqlDB.Close()
-}
\ No newline at end of file
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// DefaultHealthCacheTTL is how long a database health check result is reused
+const DefaultHealthCacheTTL = 5 * time.Second
+
+// DefaultHealthOAuthCheckInterval is how long an OAuth provider reachability
+// result is reused
+const DefaultHealthOAuthCheckInterval = 1 * time.Minute
+
+// HealthConfig holds the health check settings
+type HealthConfig struct {
+	// CacheTTL is how long a database health check result is reused; 0 disables caching
+	CacheTTL time.Duration
+
+	// OAuthCheckInterval is how often /health/detailed may probe the OAuth
+	// provider; 0 probes on every request
+	OAuthCheckInterval time.Duration
+}
+
+// loadHealthConfig reads the health check settings
+func loadHealthConfig(env *envReader) HealthConfig {
+	return HealthConfig{
+		CacheTTL:           env.nonNegativeDuration("HEALTH_CACHE_TTL", DefaultHealthCacheTTL),
+		OAuthCheckInterval: env.nonNegativeDuration("HEALTH_OAUTH_CHECK_INTERVAL", DefaultHealthOAuthCheckInterval),
+	}
+}
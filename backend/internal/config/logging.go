@@ -0,0 +1,43 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Log formats accepted by LOG_FORMAT
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LoggingConfig holds the log level and output format
+type LoggingConfig struct {
+	// Level is the minimum level of log records, from LOG_LEVEL (debug, info,
+	// warn or error); info by default
+	Level slog.Level
+
+	// Format is LogFormatText, the default, or LogFormatJSON, which suits log aggregators
+	Format string
+}
+
+// loadLoggingConfig reads the logging settings
+func loadLoggingConfig(env *envReader) LoggingConfig {
+	cfg := LoggingConfig{Level: slog.LevelInfo, Format: LogFormatText}
+
+	if value := env.string("LOG_LEVEL", ""); value != "" {
+		if err := cfg.Level.UnmarshalText([]byte(value)); err != nil {
+			env.invalid("LOG_LEVEL must be debug, info, warn or error, got %q", value)
+			cfg.Level = slog.LevelInfo
+		}
+	}
+
+	switch format := strings.ToLower(env.string("LOG_FORMAT", LogFormatText)); format {
+	case LogFormatText, LogFormatJSON:
+		cfg.Format = format
+	default:
+		env.invalid("LOG_FORMAT must be %s or %s, got %q", LogFormatText, LogFormatJSON, format)
+	}
+
+	return cfg
+}
@@ -1,18 +1,39 @@
 package config
 
 import (
-	"os"
+	"strings"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/google"
 )
 
-// GetGoogleOAuthConfig returns the OAuth2 configuration for Google authentication
-func GetGoogleOAuthConfig() *oauth2.Config {
+// DefaultOAuthRedirectURIs are the redirect URIs allowed outside production
+// when OAUTH_ALLOWED_REDIRECT_URIS is not set
+var DefaultOAuthRedirectURIs = []string{
+	"http://localhost:3000/",
+	"http://localhost:3000/dashboard",
+	"http://localhost:3000/auth/callback",
+}
+
+// DefaultOAuthErrorRedirectURL is the frontend page failed OAuth logins are redirected to
+const DefaultOAuthErrorRedirectURL = "http://localhost:3000/signup"
+
+// DefaultOAuthSuccessRedirectURL is the frontend page successful OAuth logins are redirected to
+const DefaultOAuthSuccessRedirectURL = "http://localhost:3000/"
+
+// Defaults for the OpenID Connect provider, Google
+const (
+	DefaultOIDCIssuer  = "https://accounts.google.com"
+	DefaultOIDCJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+)
+
+// GoogleOAuth2 returns the OAuth2 configuration for Google sign-in
+func (c OAuthConfig) GoogleOAuth2() *oauth2.Config {
 	return &oauth2.Config{
-		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
-		RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		ClientID:     c.GoogleClientID,
+		ClientSecret: c.GoogleClientSecret,
+		RedirectURL:  c.GoogleRedirectURI,
 		Scopes: []string{
 			"https://www.googleapis.com/auth/userinfo.email",
 			"https://www.googleapis.com/auth/userinfo.profile",
@@ -21,12 +42,18 @@ func GetGoogleOAuthConfig() *oauth2.Config {
 	}
 }
 
-// GetJWTSecret returns the JWT secret key from environment
-func GetJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// Default secret for development only
-		secret = "default-jwt-secret-change-in-production"
+// GitHubOAuth2 returns the OAuth2 configuration for GitHub sign-in
+func (c OAuthConfig) GitHubOAuth2() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.GitHubClientID,
+		ClientSecret: c.GitHubClientSecret,
+		RedirectURL:  c.GitHubRedirectURL,
+		Scopes:       []string{"read:user", "user:email"},
+		Endpoint:     github.Endpoint,
 	}
-	return secret
+}
+
+// DiscoveryURL returns the OpenID discovery document of the OIDC issuer
+func (c OAuthConfig) DiscoveryURL() string {
+	return strings.TrimSuffix(c.OIDCIssuer, "/") + "/.well-known/openid-configuration"
 }
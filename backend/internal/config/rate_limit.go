@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// DefaultIPRateLimitTTL is how long idle client IPs are tracked by the IP rate limiter
+const DefaultIPRateLimitTTL = 1 * time.Hour
+
+// DefaultIPRateLimitMaxEntries caps how many client IPs the IP rate limiter tracks
+const DefaultIPRateLimitMaxEntries = 10000
+
+// DefaultUserRateLimitPerMinute is the sustained task write rate allowed per user
+const DefaultUserRateLimitPerMinute = 120
+
+// DefaultUserRateLimitBurst is the number of task writes a user may make at once
+const DefaultUserRateLimitBurst = 20
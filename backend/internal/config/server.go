@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// DefaultRequestTimeout is how long an API request may run before its context
+// is cancelled
+const DefaultRequestTimeout = 10 * time.Second
+
+// DefaultBulkRequestTimeout is the deadline for task export and import, which
+// read or write many tasks in one request
+const DefaultBulkRequestTimeout = 2 * time.Minute
+
+// DefaultHSTSMaxAge is how long browsers are told to only reach the API over
+// HTTPS once they have seen it in production
+const DefaultHSTSMaxAge = 365 * 24 * time.Hour
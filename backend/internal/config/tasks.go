@@ -0,0 +1,48 @@
+package config
+
+import (
+	"math"
+
+	"domain/task/valueobjects"
+)
+
+// DefaultTaskListWarningThreshold is the task count above which list responses carry an advisory header
+const DefaultTaskListWarningThreshold = 500
+
+// DefaultMaxTasksPerUser is the task quota when MAX_TASKS_PER_USER is unset
+const DefaultMaxTasksPerUser = 10000
+
+// TaskConfig holds the task list, due date and quota settings
+type TaskConfig struct {
+	// ListWarningThreshold is the task count above which list responses carry
+	// advisory headers; 0 disables them
+	ListWarningThreshold int
+
+	// DueDateBounds is the allowed due date window relative to now
+	DueDateBounds valueobjects.DueDateBounds
+
+	// MaxTasksPerUser is the per-user task quota; 0 means unlimited
+	MaxTasksPerUser int64
+
+	// QuotaExcludeFinished leaves completed and archived tasks out of the quota
+	QuotaExcludeFinished bool
+}
+
+// loadTaskConfig reads the task settings. DUE_DATE_MIN_OFFSET and
+// DUE_DATE_MAX_OFFSET are Go durations relative to now (e.g. "-720h" allows
+// due dates up to 30 days in the past).
+func loadTaskConfig(env *envReader) TaskConfig {
+	bounds := valueobjects.DefaultDueDateBounds()
+	bounds.MinOffset, _ = env.duration("DUE_DATE_MIN_OFFSET", bounds.MinOffset)
+	bounds.MaxOffset, _ = env.duration("DUE_DATE_MAX_OFFSET", bounds.MaxOffset)
+	if bounds.MinOffset > bounds.MaxOffset {
+		env.invalid("DUE_DATE_MIN_OFFSET (%s) must not be greater than DUE_DATE_MAX_OFFSET (%s)", bounds.MinOffset, bounds.MaxOffset)
+	}
+
+	return TaskConfig{
+		ListWarningThreshold: env.intInRange("TASK_LIST_WARNING_THRESHOLD", DefaultTaskListWarningThreshold, 0, math.MaxInt),
+		DueDateBounds:        bounds,
+		MaxTasksPerUser:      int64(env.intInRange("MAX_TASKS_PER_USER", DefaultMaxTasksPerUser, 0, math.MaxInt)),
+		QuotaExcludeFinished: env.bool("TASK_QUOTA_EXCLUDE_FINISHED", false),
+	}
+}
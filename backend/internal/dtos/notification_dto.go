@@ -0,0 +1,42 @@
+package dtos
+
+import "time"
+
+// Notification statuses
+const (
+	NotificationPending   = "pending"   // waiting for its scheduled time
+	NotificationSent      = "sent"      // delivered by its channel
+	NotificationSkipped   = "skipped"   // the user opted out of the channel
+	NotificationCancelled = "cancelled" // the task was completed, archived or deleted first
+	NotificationFailed    = "failed"    // delivery kept failing and was given up
+)
+
+// Notification channels
+const (
+	NotificationChannelLog   = "log"
+	NotificationChannelEmail = "email"
+)
+
+// ReminderChannels are the channels a task reminder is delivered through
+var ReminderChannels = []string{NotificationChannelLog, NotificationChannelEmail}
+
+// Notification is one delivery of a task reminder through one channel. There
+// is no foreign key to tasks: sent notifications stay listed after the task is
+// permanently deleted.
+type Notification struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"-" gorm:"not null;index:idx_notifications_user_id_created_at,priority:1"`
+	TaskID       uint       `json:"task_id" gorm:"not null;index"`
+	Channel      string     `json:"channel" gorm:"type:varchar(20);not null"`
+	ScheduledFor time.Time  `json:"scheduled_for" gorm:"not null;index:idx_notifications_status_scheduled_for,priority:2"`
+	SentAt       *time.Time `json:"sent_at,omitempty"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;default:pending;index:idx_notifications_status_scheduled_for,priority:1"`
+	Attempts     int        `json:"-" gorm:"not null;default:0"`
+	LastError    string     `json:"-" gorm:"type:text"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"index:idx_notifications_user_id_created_at,priority:2"`
+}
+
+// TableName specifies the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
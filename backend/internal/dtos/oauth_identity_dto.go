@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OAuthIdentity links a user to an account at an OAuth provider. A user may
+// link one account per provider, so they can sign in with several providers.
+// The first provider linked is also kept on User.OAuthProvider and
+// User.OAuthExternalID (and User.GoogleID for Google) for backward compatibility.
+type OAuthIdentity struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_oauth_identities_user_provider"`
+	Provider   string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_oauth_identities_user_provider;uniqueIndex:idx_oauth_identities_provider_account"`
+	ExternalID string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_oauth_identities_provider_account"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for the OAuthIdentity model
+func (OAuthIdentity) TableName() string {
+	return "oauth_identities"
+}
+
+// BeforeCreate hook to validate identity before creation
+func (i *OAuthIdentity) BeforeCreate(tx *gorm.DB) error {
+	return i.Validate()
+}
+
+// Validate performs validation on the OAuthIdentity model
+func (i *OAuthIdentity) Validate() error {
+	if i.UserID == 0 {
+		return errors.New("user_id cannot be empty")
+	}
+	if i.Provider == "" {
+		return errors.New("provider cannot be empty")
+	}
+	if i.ExternalID == "" {
+		return errors.New("external_id cannot be empty")
+	}
+	return nil
+}
@@ -8,12 +8,20 @@ import (
 
 // Task represents a single TODO item
 type Task struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title" gorm:"type:varchar(500);not null" validate:"required,max=500"`
-	Completed bool      `json:"completed" gorm:"default:false"`
-	UserID    uint      `json:"-" gorm:"not null;index"` // Not exposed in API, only for database
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Title       string         `json:"title" gorm:"type:varchar(500);not null" validate:"required,max=500"`
+	Description string         `json:"description,omitempty" gorm:"type:text"`
+	Priority    string         `json:"priority,omitempty" gorm:"type:varchar(10);default:medium;index"`
+	Status      string         `json:"status,omitempty" gorm:"type:varchar(20);default:pending;index"`
+	Completed   bool           `json:"completed" gorm:"default:false"`
+	DueDate     *time.Time     `json:"due_date,omitempty" gorm:"index"`
+	RemindAt    *time.Time     `json:"remind_at,omitempty" gorm:"index"`
+	UserID      uint           `json:"-" gorm:"not null;index"` // Not exposed in API, only for database
+	Tags        []Tag          `json:"tags,omitempty" gorm:"many2many:task_tags;"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`                    // Set while the task is in the trash
+	Version     int            `json:"version" gorm:"not null;default:1"` // Incremented by every update
 }
 
 // TableName specifies the table name for the Task model
@@ -21,6 +29,37 @@ func (Task) TableName() string {
 	return "tasks"
 }
 
+// Tag represents a user-defined task label; names are unique per user
+type Tag struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	CreatedAt time.Time `json:"-" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for the Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TaskActivity is one entry in a task's history. There is no foreign key to
+// tasks: entries are kept for audit after the task is permanently deleted.
+type TaskActivity struct {
+	ID        uint      `gorm:"primaryKey"`
+	TaskID    uint      `gorm:"not null;index:idx_task_activities_task_id_created_at,priority:1"`
+	UserID    uint      `gorm:"not null;index"`
+	Action    string    `gorm:"type:varchar(20);not null"`
+	Field     string    `gorm:"type:varchar(20);not null"` // empty for actions on the whole task
+	OldValue  *string   `gorm:"type:text"`
+	NewValue  *string   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"index:idx_task_activities_task_id_created_at,priority:2"`
+}
+
+// TableName specifies the table name for the TaskActivity model
+func (TaskActivity) TableName() string {
+	return "task_activities"
+}
+
 // BeforeCreate hook to validate task before creation
 func (t *Task) BeforeCreate(tx *gorm.DB) error {
 	return t.Validate()
@@ -44,17 +83,19 @@ func (t *Task) Validate() error {
 
 // CreateTaskRequest represents the request payload for creating a task
 type CreateTaskRequest struct {
-	Title string `json:"title" binding:"required,max=500"`
+	Title   string     `json:"title" binding:"required,max=500"`
+	DueDate *time.Time `json:"due_date,omitempty"`
 }
 
 // UpdateTaskRequest represents the request payload for updating a task
 type UpdateTaskRequest struct {
-	Title     *string `json:"title,omitempty" binding:"omitempty,max=500"`
-	Completed *bool   `json:"completed,omitempty"`
+	Title     *string    `json:"title,omitempty" binding:"omitempty,max=500"`
+	Completed *bool      `json:"completed,omitempty"`
+	DueDate   *time.Time `json:"due_date,omitempty"`
 }
 
 // TaskResponse represents the response format for task operations
 type TaskResponse struct {
 	Tasks []Task `json:"tasks"`
 	Count int    `json:"count"`
-}
\ No newline at end of file
+}
@@ -5,15 +5,32 @@ import (
 	"time"
 
 	"domain/auth/valueobjects"
+	uservo "domain/user/valueobjects"
 	"gorm.io/gorm"
 )
 
+// Supported values for User.OAuthProvider
+const (
+	OAuthProviderGoogle = "google"
+	OAuthProviderGitHub = "github"
+)
+
+// ErrAccountNotPendingDeletion is returned when restoring an account that is not scheduled for deletion
+var ErrAccountNotPendingDeletion = errors.New("account is not pending deletion")
+
 // User represents a user in the system with OAuth support
 type User struct {
 	ID        uint   `json:"id" gorm:"primaryKey"`
 	Email     string `json:"email" gorm:"type:varchar(255);uniqueIndex;not null" validate:"required,email"`
 	Name      string `json:"name" gorm:"type:varchar(255);not null" validate:"required"`
 
+	// Timezone is the IANA timezone from the user's profile
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+
+	// TaskLimit overrides the configured task quota for the user; nil uses
+	// MAX_TASKS_PER_USER and 0 means unlimited
+	TaskLimit *int64 `json:"-" gorm:"column:task_limit"`
+
 	// Traditional authentication
 	PasswordHash string `json:"-" gorm:"type:varchar(255)"`
 
@@ -22,14 +39,28 @@ type User struct {
 
 	// Legacy OAuth fields (kept for backward compatibility)
 	GoogleID       string     `json:"google_id,omitempty" gorm:"type:varchar(255);uniqueIndex"`
-	OAuthProvider  string     `json:"oauth_provider,omitempty" gorm:"type:varchar(50)"`
-	OAuthCreatedAt *time.Time `json:"oauth_created_at,omitempty"`
+	OAuthProvider  string     `json:"oauth_provider,omitempty" gorm:"column:oauth_provider;type:varchar(50);uniqueIndex:idx_users_oauth_identity,where:oauth_external_id <> ''"`
+	OAuthCreatedAt *time.Time `json:"oauth_created_at,omitempty" gorm:"column:oauth_created_at"`
+
+	// OAuthExternalID is the account ID at OAuthProvider; together they identify the OAuth account
+	OAuthExternalID string `json:"-" gorm:"column:oauth_external_id;type:varchar(255);uniqueIndex:idx_users_oauth_identity,where:oauth_external_id <> ''"`
 
 	// Status and timestamps
 	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	IsAdmin   bool      `json:"is_admin" gorm:"not null;default:false"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	// DeletionScheduledAt is when a requested account deletion becomes permanent;
+	// nil unless the user has asked for their account to be deleted
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" gorm:"index"`
+
+	// Email verification for password registrations; the single-use token is
+	// cleared once the email is verified
+	EmailVerified              bool       `json:"email_verified" gorm:"not null;default:false"`
+	VerificationToken          *string    `json:"-" gorm:"type:varchar(255);uniqueIndex"`
+	VerificationTokenExpiresAt *time.Time `json:"-"`
+
 	// Relationship to GoogleIdentity (new approach)
 	GoogleIdentity *valueobjects.GoogleIdentity `json:"google_identity,omitempty" gorm:"foreignKey:UserID"`
 }
@@ -39,6 +70,12 @@ func (User) TableName() string {
 	return "users"
 }
 
+// BeforeSave hook normalizes the email so it is stored and matched in one case
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.Email = uservo.NormalizeEmail(u.Email)
+	return nil
+}
+
 // BeforeCreate hook to validate user before creation
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return u.Validate()
@@ -59,19 +96,19 @@ func (u *User) Validate() error {
 		return errors.New("name cannot be empty")
 	}
 
-	// Either password_hash OR google_id must be present
-	if u.PasswordHash == "" && u.GoogleID == "" {
-		return errors.New("either password_hash or google_id must be present")
+	// Either password_hash OR an OAuth identity must be present
+	if u.PasswordHash == "" && !u.IsOAuthUser() {
+		return errors.New("either password_hash or an OAuth identity must be present")
 	}
 
 	// If google_id is present, oauth_provider must be "google"
-	if u.GoogleID != "" && u.OAuthProvider != "google" {
+	if u.GoogleID != "" && u.OAuthProvider != OAuthProviderGoogle {
 		return errors.New("oauth_provider must be 'google' when google_id is present")
 	}
 
-	// If oauth_provider is set, google_id must be present
-	if u.OAuthProvider != "" && u.GoogleID == "" {
-		return errors.New("google_id must be present when oauth_provider is set")
+	// If oauth_provider is set, the provider's account ID must be present
+	if u.OAuthProvider != "" && !u.IsOAuthUser() {
+		return errors.New("oauth_external_id must be present when oauth_provider is set")
 	}
 
 	return nil
@@ -79,7 +116,21 @@ func (u *User) Validate() error {
 
 // IsOAuthUser returns true if the user was created via OAuth
 func (u *User) IsOAuthUser() bool {
-	return u.GoogleID != ""
+	return u.GoogleID != "" || u.OAuthExternalID != ""
+}
+
+// LinkedOAuthProvider returns the linked OAuth provider, or "" if there is none
+func (u *User) LinkedOAuthProvider() string {
+	if !u.IsOAuthUser() {
+		return ""
+	}
+	return u.OAuthProvider
+}
+
+// HasVerifiedEmail returns true if the user proved they own their email;
+// OAuth providers only hand over verified emails
+func (u *User) HasVerifiedEmail() bool {
+	return u.EmailVerified || u.IsOAuthUser()
 }
 
 // IsTraditionalUser returns true if the user has password authentication
@@ -92,20 +143,37 @@ func (u *User) IsLinkedUser() bool {
 	return u.IsOAuthUser() && u.IsTraditionalUser()
 }
 
-// LinkGoogleAccount links a Google OAuth account to an existing user
-func (u *User) LinkGoogleAccount(googleID string, linkedAt time.Time) error {
-	if googleID == "" {
-		return errors.New("google_id cannot be empty")
+// LinkOAuthAccount links an OAuth provider account to an existing user.
+// Google accounts also populate the legacy google_id column.
+func (u *User) LinkOAuthAccount(provider, externalID string, linkedAt time.Time) error {
+	if provider == "" {
+		return errors.New("oauth_provider cannot be empty")
+	}
+	if externalID == "" {
+		return errors.New("oauth_external_id cannot be empty")
 	}
 
-	u.GoogleID = googleID
-	u.OAuthProvider = "google"
+	u.GoogleID = ""
+	if provider == OAuthProviderGoogle {
+		u.GoogleID = externalID
+	}
+	u.OAuthProvider = provider
+	u.OAuthExternalID = externalID
 	u.OAuthCreatedAt = &linkedAt
 	u.UpdatedAt = time.Now()
 
 	return u.Validate()
 }
 
+// LinkGoogleAccount links a Google OAuth account to an existing user
+func (u *User) LinkGoogleAccount(googleID string, linkedAt time.Time) error {
+	if googleID == "" {
+		return errors.New("google_id cannot be empty")
+	}
+
+	return u.LinkOAuthAccount(OAuthProviderGoogle, googleID, linkedAt)
+}
+
 // UnlinkGoogleAccount removes Google OAuth linking from the user
 // Only allowed if user has password authentication
 func (u *User) UnlinkGoogleAccount() error {
@@ -115,6 +183,7 @@ func (u *User) UnlinkGoogleAccount() error {
 
 	u.GoogleID = ""
 	u.OAuthProvider = ""
+	u.OAuthExternalID = ""
 	u.OAuthCreatedAt = nil
 	u.UpdatedAt = time.Now()
 
@@ -133,6 +202,28 @@ func (u *User) Activate() {
 	u.UpdatedAt = time.Now()
 }
 
+// ScheduleDeletion deactivates the account and marks it for permanent deletion at the given time
+func (u *User) ScheduleDeletion(at time.Time) {
+	u.Deactivate()
+	u.DeletionScheduledAt = &at
+}
+
+// CancelDeletion reactivates an account that is pending deletion
+func (u *User) CancelDeletion() error {
+	if !u.IsPendingDeletion() {
+		return ErrAccountNotPendingDeletion
+	}
+
+	u.DeletionScheduledAt = nil
+	u.Activate()
+	return nil
+}
+
+// IsPendingDeletion checks if the user has requested account deletion
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil
+}
+
 // UpdateProfile updates the user's display name
 func (u *User) UpdateProfile(name string) error {
 	if name == "" {
@@ -183,26 +274,32 @@ type LinkOAuthAccountRequest struct {
 
 // UserResponse represents the user data returned in API responses
 type UserResponse struct {
-	ID             uint       `json:"id"`
-	Email          string     `json:"email"`
-	Name           string     `json:"name"`
-	OAuthProvider  string     `json:"oauth_provider,omitempty"`
-	OAuthCreatedAt *time.Time `json:"oauth_created_at,omitempty"`
-	IsActive       bool       `json:"is_active"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID                  uint       `json:"id"`
+	Email               string     `json:"email"`
+	Name                string     `json:"name"`
+	OAuthProvider       string     `json:"oauth_provider,omitempty"`
+	OAuthCreatedAt      *time.Time `json:"oauth_created_at,omitempty"`
+	IsActive            bool       `json:"is_active"`
+	IsAdmin             bool       `json:"is_admin"`
+	EmailVerified       bool       `json:"email_verified"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 // ToResponse converts User model to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:             u.ID,
-		Email:          u.Email,
-		Name:           u.Name,
-		OAuthProvider:  u.OAuthProvider,
-		OAuthCreatedAt: u.OAuthCreatedAt,
-		IsActive:       u.IsActive,
-		CreatedAt:      u.CreatedAt,
-		UpdatedAt:      u.UpdatedAt,
+		ID:                  u.ID,
+		Email:               u.Email,
+		Name:                u.Name,
+		OAuthProvider:       u.OAuthProvider,
+		OAuthCreatedAt:      u.OAuthCreatedAt,
+		IsActive:            u.IsActive,
+		IsAdmin:             u.IsAdmin,
+		EmailVerified:       u.HasVerifiedEmail(),
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+	"todo-app/services/user"
+)
+
+// AccountHandler handles account lifecycle requests for the current user
+type AccountHandler struct {
+	userService    *user.UserService
+	sessionService *auth.SessionService
+
+	// gracePeriod is how long a deleted account can be restored before it is purged
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewAccountHandler creates a new AccountHandler instance; deleted accounts
+// can be restored for gracePeriod
+func NewAccountHandler(db *gorm.DB, sessionService *auth.SessionService, gracePeriod time.Duration) *AccountHandler {
+	return &AccountHandler{
+		userService:    user.NewUserService(db),
+		sessionService: sessionService,
+		gracePeriod:    gracePeriod,
+		now:            time.Now,
+	}
+}
+
+// DeleteAccount handles DELETE /api/v1/users/me
+// The account is deactivated and signed out everywhere straight away, and
+// permanently deleted once the grace period has passed unless it is restored.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	account, err := h.userService.ScheduleDeletion(userID, h.now().Add(h.gracePeriod))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "user_not_found",
+				"message": "User not found",
+			})
+			return
+		}
+		log.Printf("Failed to schedule deletion for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to delete account",
+		})
+		return
+	}
+
+	if _, err := h.sessionService.TerminateAllUserSessions(userID); err != nil {
+		log.Printf("Failed to terminate sessions for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to delete account",
+		})
+		return
+	}
+
+	// The sessions are gone, so clear the cookie too
+	h.sessionService.Cookies().ClearSessionCookie(c)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":               "Account scheduled for deletion",
+		"deletion_scheduled_at": account.DeletionScheduledAt,
+	})
+}
+
+// RestoreAccount handles POST /api/v1/users/me/restore
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	account, err := h.userService.CancelDeletion(userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "user_not_found",
+				"message": "User not found",
+			})
+		case errors.Is(err, dtos.ErrAccountNotPendingDeletion):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "account_not_pending_deletion",
+				"message": "Account is not scheduled for deletion",
+			})
+		default:
+			log.Printf("Failed to restore account for user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "internal_error",
+				"message": "Failed to restore account",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"domain/auth/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/internal/services"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+	userservice "todo-app/services/user"
+)
+
+func setupAccountHandlerTest(t *testing.T) (*gorm.DB, *auth.SessionService, *AccountHandler, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.TaskActivity{}, &dtos.Notification{}, &dtos.User{}, &dtos.OAuthIdentity{}, &valueobjects.GoogleIdentity{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}, &entities.PasswordResetToken{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+	handler := NewAccountHandler(db, sessionService, cfg.Account.DeletionGracePeriod)
+
+	router := gin.New()
+	router.DELETE("/api/v1/users/me", authMiddleware.RequireAuth(), handler.DeleteAccount)
+	router.POST("/api/v1/users/me/restore", authMiddleware.RequireAuthAllowingPendingDeletion(), handler.RestoreAccount)
+
+	return db, sessionService, handler, router
+}
+
+func createAccountWithData(t *testing.T, db *gorm.DB, sessionService *auth.SessionService, email string) (*dtos.User, string) {
+	user := dtos.User{Email: email, Name: "Test User", GoogleID: "google-" + email, OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+
+	for _, title := range []string{"First task", "Second task"} {
+		require.NoError(t, db.Create(&dtos.Task{Title: title, UserID: user.ID}).Error)
+	}
+
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	return &user, token
+}
+
+func accountRequest(method, path, token string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	return req
+}
+
+func TestDeleteAccount_DeactivatesAndSchedulesDeletion(t *testing.T) {
+	db, sessionService, handler, router := setupAccountHandlerTest(t)
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return clock }
+	user, token := createAccountWithData(t, db, sessionService, "delete-me@example.com")
+	other, _ := createAccountWithData(t, db, sessionService, "keep-me@example.com")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/users/me", token))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	// The account is kept, deactivated, until the grace period ends
+	var stored dtos.User
+	require.NoError(t, db.First(&stored, user.ID).Error)
+	assert.False(t, stored.IsActive)
+	require.NotNil(t, stored.DeletionScheduledAt)
+	assert.True(t, clock.Add(handler.gracePeriod).Equal(*stored.DeletionScheduledAt))
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.Task{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// Other accounts are untouched
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", other.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// The terminated session can no longer be used
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/users/me", token))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestDeleteAccount_LoginBlockedUntilRestored(t *testing.T) {
+	db, sessionService, _, router := setupAccountHandlerTest(t)
+	user, token := createAccountWithData(t, db, sessionService, "restore-me@example.com")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/users/me", token))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	// Signing in again reports the pending deletion
+	oauthHandler := NewOAuthHandler(&fakeOAuthProvider{info: &services.OAuthUserInfo{
+		Provider: "google", ExternalID: user.GoogleID, Email: user.Email, EmailVerified: true, Name: user.Name,
+	}}, db, sessionService, config.OAuthConfig{ErrorRedirectURL: config.DefaultOAuthErrorRedirectURL, AutoLink: true})
+	router.GET("/auth/github/callback", oauthHandler.Callback)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-123"))
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/signup?error=account_pending_deletion", w.Header().Get("Location"))
+
+	var loginToken string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			loginToken = cookie.Value
+		}
+	}
+	require.NotEmpty(t, loginToken)
+
+	// The new session cannot reach the rest of the API
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/users/me", loginToken))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// but can restore the account
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodPost, "/api/v1/users/me/restore", loginToken))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var stored dtos.User
+	require.NoError(t, db.First(&stored, user.ID).Error)
+	assert.True(t, stored.IsActive)
+	assert.Nil(t, stored.DeletionScheduledAt)
+
+	// Restoring twice is a conflict
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodPost, "/api/v1/users/me/restore", loginToken))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestDeleteAccount_PurgedAfterGracePeriod(t *testing.T) {
+	db, sessionService, handler, router := setupAccountHandlerTest(t)
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return clock }
+	user, token := createAccountWithData(t, db, sessionService, "purge-me@example.com")
+	other, _ := createAccountWithData(t, db, sessionService, "keep-me@example.com")
+	require.NoError(t, db.Create(&valueobjects.GoogleIdentity{GoogleUserID: user.GoogleID, UserID: user.ID, Email: user.Email}).Error)
+	for _, owner := range []uint{user.ID, other.ID} {
+		require.NoError(t, db.Create(&dtos.TaskActivity{TaskID: 1, UserID: owner, Action: "created"}).Error)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/users/me", token))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	userService := userservice.NewUserService(db)
+
+	// Nothing is purged while the account can still be restored
+	purged, err := userService.PurgeScheduledDeletions(clock.Add(handler.gracePeriod - time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = userService.PurgeScheduledDeletions(clock.Add(handler.gracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.User{}).Where("id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&dtos.Task{}).Unscoped().Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&valueobjects.GoogleIdentity{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&dtos.TaskActivity{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// Other accounts are untouched
+	require.NoError(t, db.Model(&dtos.Task{}).Where("user_id = ?", other.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	require.NoError(t, db.Model(&dtos.TaskActivity{}).Where("user_id = ?", other.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestDeleteAccount_RequiresAuth(t *testing.T) {
+	_, _, _, router := setupAccountHandlerTest(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"todo-app/middleware"
+	"todo-app/services/user"
+)
+
+// ConfirmLinkRequest is the body of POST /api/v1/auth/{provider}/confirm-link
+type ConfirmLinkRequest struct {
+	LinkToken string `json:"link_token" binding:"required"`
+}
+
+// redirectLinkConfirmationRequired sends the browser to the frontend error page
+// with the token the existing account must confirm the link with once signed in
+func redirectLinkConfirmationRequired(c *gin.Context, errorRedirectURL string, link *entities.PendingAccountLink) {
+	c.Redirect(http.StatusFound, withQuery(errorRedirectURL, url.Values{
+		"error":      {"link_confirmation_required"},
+		"link_token": {link.Token},
+	}))
+}
+
+// redirectLoginSucceeded sends the browser to the frontend after a sign-in,
+// telling it whether the sign-in created the account
+func redirectLoginSucceeded(c *gin.Context, successRedirectURL string, newUser bool) {
+	c.Redirect(http.StatusFound, withQuery(successRedirectURL, url.Values{
+		"login":    {"success"},
+		"new_user": {strconv.FormatBool(newUser)},
+	}))
+}
+
+// redirectToErrorPage sends the browser to the frontend error page, telling it
+// what went wrong with ?error=errorCode
+func redirectToErrorPage(c *gin.Context, errorRedirectURL, errorCode string) {
+	c.Redirect(http.StatusFound, withQuery(errorRedirectURL, url.Values{"error": {errorCode}}))
+}
+
+// withQuery adds params to the query string of uri
+func withQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// confirmPendingLink handles POST /api/v1/auth/{provider}/confirm-link for the
+// signed-in user, linking the provider account from their pending link
+func confirmPendingLink(c *gin.Context, userService *user.UserService, provider string) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	var req ConfirmLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "link_token is required",
+		})
+		return
+	}
+
+	account, err := userService.ConfirmPendingLink(userID, provider, req.LinkToken)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, account.ToResponse())
+	case errors.Is(err, user.ErrPendingLinkNotFound):
+		// Links recorded for other accounts are reported as missing too
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "link_not_found",
+			"message": "Link request not found or expired; sign in with " + provider + " again",
+		})
+	case errors.Is(err, user.ErrOAuthProviderConflict), errors.Is(err, user.ErrOAuthIdentityInUse):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "account_already_linked",
+			"message": err.Error(),
+		})
+	default:
+		log.Printf("Failed to confirm %s link for user %d: %v", provider, userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to link account",
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	"todo-app/services/user"
+)
+
+// Page sizes for the admin user and audit log lists
+const (
+	defaultAdminUserLimit = 20
+	maxAdminUserLimit     = 100
+)
+
+// AdminHandler lets administrators manage other users' accounts and review the audit log
+type AdminHandler struct {
+	userService    *user.UserService
+	sessionService *auth.SessionService
+	auditService   *audit.AuditService
+}
+
+// NewAdminHandler creates a new AdminHandler instance
+func NewAdminHandler(db *gorm.DB, sessionService *auth.SessionService, auditService *audit.AuditService) *AdminHandler {
+	return &AdminHandler{
+		userService:    user.NewUserService(db),
+		sessionService: sessionService,
+		auditService:   auditService,
+	}
+}
+
+// ListUsers handles GET /api/v1/admin/users?email=&limit=&offset=
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	limit, offset, ok := h.pageParams(c)
+	if !ok {
+		return
+	}
+
+	users, total, err := h.userService.SearchUsers(c.Query("email"), limit, offset)
+	if err != nil {
+		log.Printf("Failed to list users: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to retrieve users",
+		})
+		return
+	}
+
+	responses := make([]dtos.UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, users[i].ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":  responses,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		h.userError(c, userID, err, "Failed to retrieve user")
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
+
+// DeactivateUser handles POST /api/v1/admin/users/:id/deactivate
+// The user is signed out of every session as well.
+func (h *AdminHandler) DeactivateUser(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	// Admins cannot lock themselves out
+	if currentUserID, _ := middleware.GetCurrentUserID(c); userID == currentUserID {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "cannot_deactivate_self",
+			"message": "You cannot deactivate your own account",
+		})
+		return
+	}
+
+	account, err := h.userService.DeactivateUser(userID)
+	if err != nil {
+		h.userError(c, userID, err, "Failed to deactivate user")
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
+
+// ActivateUser handles POST /api/v1/admin/users/:id/activate
+func (h *AdminHandler) ActivateUser(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.userService.ActivateUser(userID)
+	if err != nil {
+		h.userError(c, userID, err, "Failed to activate user")
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
+
+// ListUserSessions handles GET /api/v1/admin/users/:id/sessions
+func (h *AdminHandler) ListUserSessions(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.userService.GetUserByID(userID); err != nil {
+		h.userError(c, userID, err, "Failed to retrieve sessions")
+		return
+	}
+
+	sessions, err := h.sessionService.GetUserSessions(userID)
+	if err != nil {
+		h.userError(c, userID, err, "Failed to retrieve sessions")
+		return
+	}
+
+	responses := make([]entities.ActiveSessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, sessions[i].ToActiveSessionResponse(""))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": responses,
+		"count":    len(responses),
+	})
+}
+
+// ListAuditLogs handles GET /api/v1/admin/audit-logs?user_id=&event_type=&from=&to=&limit=&offset=
+// from and to are RFC 3339 timestamps; from is inclusive and to exclusive.
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	limit, offset, ok := h.pageParams(c)
+	if !ok {
+		return
+	}
+	filter := audit.AuditLogFilter{Limit: limit, Offset: offset}
+
+	if userIDParam := c.Query("user_id"); userIDParam != "" {
+		userID, err := strconv.ParseUint(userIDParam, 10, 32)
+		if err != nil || userID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_user_id",
+				"message": "user_id must be a positive integer",
+			})
+			return
+		}
+		id := uint(userID)
+		filter.UserID = &id
+	}
+
+	if eventType := entities.AuditEventType(c.Query("event_type")); eventType != "" {
+		if !eventType.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_event_type",
+				"message": "Unknown event_type",
+			})
+			return
+		}
+		filter.EventType = eventType
+	}
+
+	if filter.From, ok = timeQueryParam(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = timeQueryParam(c, "to"); !ok {
+		return
+	}
+
+	logs, total, err := h.auditService.List(filter)
+	if err != nil {
+		log.Printf("Failed to list audit logs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to retrieve audit logs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit_logs": logs,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// timeQueryParam parses an optional RFC 3339 query parameter, responding with 400 if it is invalid
+func timeQueryParam(c *gin.Context, name string) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, true
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_" + name,
+			"message": name + " must be an RFC 3339 timestamp",
+		})
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+// pageParams parses the limit and offset query parameters, responding with 400 if they are invalid
+func (h *AdminHandler) pageParams(c *gin.Context) (int, int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAdminUserLimit)))
+	if err != nil || limit < 1 || limit > maxAdminUserLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_limit",
+			"message": "limit must be between 1 and " + strconv.Itoa(maxAdminUserLimit),
+		})
+		return 0, 0, false
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_offset",
+			"message": "offset must be a non-negative integer",
+		})
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+// userIDParam parses the :id path parameter, responding with 400 if it is invalid
+func (h *AdminHandler) userIDParam(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_user_id",
+			"message": "User ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// userError responds with 404 for unknown users and 500 otherwise
+func (h *AdminHandler) userError(c *gin.Context, userID uint, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "user_not_found",
+			"message": "User not found",
+		})
+		return
+	}
+	log.Printf("%s for user %d: %v", message, userID, err)
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "internal_error",
+		"message": message,
+	})
+}
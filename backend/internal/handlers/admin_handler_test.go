@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+)
+
+func setupAdminHandlerTest(t *testing.T) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}, &entities.AuditLog{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+	auditService := audit.NewAuditService(db, 0)
+	t.Cleanup(func() { auditService.Close(context.Background()) })
+	handler := NewAdminHandler(db, sessionService, auditService)
+
+	router := gin.New()
+	admin := router.Group("/api/v1/admin", authMiddleware.RequireAuth(), authMiddleware.RequireAdmin())
+	admin.GET("/users", handler.ListUsers)
+	admin.GET("/users/:id", handler.GetUser)
+	admin.POST("/users/:id/deactivate", handler.DeactivateUser)
+	admin.POST("/users/:id/activate", handler.ActivateUser)
+	admin.GET("/users/:id/sessions", handler.ListUserSessions)
+	admin.GET("/audit-logs", handler.ListAuditLogs)
+
+	return db, sessionService, router
+}
+
+func createAdminTestUser(t *testing.T, db *gorm.DB, sessionService *auth.SessionService, email string, isAdmin bool) (*dtos.User, string) {
+	user := dtos.User{Email: email, Name: "Test User", GoogleID: "google-" + email, OAuthProvider: "google", IsActive: true, IsAdmin: isAdmin}
+	require.NoError(t, db.Create(&user).Error)
+
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	return &user, token
+}
+
+func adminRequest(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminDeactivateUser_TerminatesSessions(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	_, adminToken := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+	target, targetToken := createAdminTestUser(t, db, sessionService, "target@example.com", false)
+	_, _, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: target.ID, Email: target.Email})
+	require.NoError(t, err)
+
+	w := adminRequest(router, http.MethodGet, fmt.Sprintf("/api/v1/admin/users/%d/sessions", target.ID), adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed struct {
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Equal(t, 2, listed.Count)
+
+	w = adminRequest(router, http.MethodPost, fmt.Sprintf("/api/v1/admin/users/%d/deactivate", target.ID), adminToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stored dtos.User
+	require.NoError(t, db.First(&stored, target.ID).Error)
+	assert.False(t, stored.IsActive)
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", target.ID).Count(&sessions).Error)
+	assert.Equal(t, int64(0), sessions)
+
+	result, err := sessionService.ValidateSession(targetToken)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	// Reactivation restores the account; the user signs in again
+	w = adminRequest(router, http.MethodPost, fmt.Sprintf("/api/v1/admin/users/%d/activate", target.ID), adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, db.First(&stored, target.ID).Error)
+	assert.True(t, stored.IsActive)
+}
+
+func TestAdminDeactivateUser_RejectsSelfAndUnknownUsers(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	admin, adminToken := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+
+	w := adminRequest(router, http.MethodPost, fmt.Sprintf("/api/v1/admin/users/%d/deactivate", admin.ID), adminToken)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/api/v1/admin/users/999/deactivate", adminToken)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/api/v1/admin/users/abc/deactivate", adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminListUsers_PaginatesAndSearchesByEmail(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	_, adminToken := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+	for i := 1; i <= 3; i++ {
+		createAdminTestUser(t, db, sessionService, fmt.Sprintf("member%d@example.com", i), false)
+	}
+
+	type page struct {
+		Users []dtos.UserResponse `json:"users"`
+		Total int64               `json:"total"`
+	}
+
+	w := adminRequest(router, http.MethodGet, "/api/v1/admin/users?limit=2&offset=0", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var first page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, int64(4), first.Total)
+	assert.Len(t, first.Users, 2)
+
+	w = adminRequest(router, http.MethodGet, "/api/v1/admin/users?email=member&limit=2&offset=2", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var searched page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &searched))
+	assert.Equal(t, int64(3), searched.Total)
+	require.Len(t, searched.Users, 1)
+	assert.Equal(t, "member1@example.com", searched.Users[0].Email)
+
+	w = adminRequest(router, http.MethodGet, "/api/v1/admin/users?limit=1000", adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminGetUser_ReturnsProfile(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	_, adminToken := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+	target, _ := createAdminTestUser(t, db, sessionService, "target@example.com", false)
+
+	w := adminRequest(router, http.MethodGet, fmt.Sprintf("/api/v1/admin/users/%d", target.ID), adminToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var profile dtos.UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(t, target.ID, profile.ID)
+	assert.Equal(t, "target@example.com", profile.Email)
+	assert.False(t, profile.IsAdmin)
+
+	assert.Equal(t, http.StatusNotFound, adminRequest(router, http.MethodGet, "/api/v1/admin/users/999", adminToken).Code)
+	assert.Equal(t, http.StatusBadRequest, adminRequest(router, http.MethodGet, "/api/v1/admin/users/abc", adminToken).Code)
+}
+
+func TestAdminRoutes_RejectNonAdmins(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	admin, _ := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+	_, memberToken := createAdminTestUser(t, db, sessionService, "member@example.com", false)
+
+	assert.Equal(t, http.StatusForbidden, adminRequest(router, http.MethodGet, "/api/v1/admin/users", memberToken).Code)
+	assert.Equal(t, http.StatusForbidden, adminRequest(router, http.MethodGet, fmt.Sprintf("/api/v1/admin/users/%d", admin.ID), memberToken).Code)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminListAuditLogs_FiltersByUserEventTypeAndTime(t *testing.T) {
+	db, sessionService, router := setupAdminHandlerTest(t)
+	_, adminToken := createAdminTestUser(t, db, sessionService, "admin@example.com", true)
+	member, _ := createAdminTestUser(t, db, sessionService, "member@example.com", false)
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, entry := range []*entities.AuditLog{
+		entities.NewAuditLog(entities.AuditEventLoginSucceeded, member.ID, "192.0.2.1", "agent", nil),
+		entities.NewAuditLog(entities.AuditEventTaskDeleted, member.ID, "192.0.2.1", "agent", entities.AuditMetadata{"task_id": 7}),
+		entities.NewAuditLog(entities.AuditEventLoginFailed, 0, "192.0.2.2", "agent", entities.AuditMetadata{"reason": "invalid_state"}),
+		entities.NewAuditLog(entities.AuditEventLoginSucceeded, member.ID, "192.0.2.1", "agent", nil),
+	} {
+		entry.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, db.Create(entry).Error)
+	}
+
+	type page struct {
+		AuditLogs []entities.AuditLog `json:"audit_logs"`
+		Total     int64               `json:"total"`
+	}
+	list := func(query url.Values) page {
+		w := adminRequest(router, http.MethodGet, "/api/v1/admin/audit-logs?"+query.Encode(), adminToken)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	all := list(url.Values{})
+	assert.Equal(t, int64(4), all.Total)
+	require.Len(t, all.AuditLogs, 4)
+	// Newest first
+	assert.True(t, all.AuditLogs[0].CreatedAt.Equal(base.Add(3*time.Hour)))
+
+	byUser := list(url.Values{"user_id": {fmt.Sprint(member.ID)}, "event_type": {"login_succeeded"}})
+	assert.Equal(t, int64(2), byUser.Total)
+
+	window := list(url.Values{"from": {base.Add(time.Hour).Format(time.RFC3339)}, "to": {base.Add(3 * time.Hour).Format(time.RFC3339)}})
+	require.Equal(t, int64(2), window.Total)
+	assert.Equal(t, entities.AuditEventLoginFailed, window.AuditLogs[0].EventType)
+	assert.Nil(t, window.AuditLogs[0].UserID)
+	assert.Equal(t, entities.AuditEventTaskDeleted, window.AuditLogs[1].EventType)
+	assert.Equal(t, float64(7), window.AuditLogs[1].Metadata["task_id"])
+
+	for _, query := range []string{"event_type=unknown", "user_id=abc", "from=yesterday", "limit=0"} {
+		w := adminRequest(router, http.MethodGet, "/api/v1/admin/audit-logs?"+query, adminToken)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"todo-app/services/auth"
+)
+
+// BackchannelLogoutHandler receives OIDC back-channel logout requests from the identity provider
+type BackchannelLogoutHandler struct {
+	logoutService *auth.BackchannelLogoutService
+}
+
+// NewBackchannelLogoutHandler creates a new BackchannelLogoutHandler instance
+func NewBackchannelLogoutHandler(logoutService *auth.BackchannelLogoutService) *BackchannelLogoutHandler {
+	return &BackchannelLogoutHandler{
+		logoutService: logoutService,
+	}
+}
+
+// BackchannelLogout handles POST /api/v1/auth/backchannel-logout
+func (h *BackchannelLogoutHandler) BackchannelLogout(c *gin.Context) {
+	// Responses to logout requests must not be cached
+	c.Header("Cache-Control", "no-store")
+
+	logoutToken := c.PostForm("logout_token")
+	if logoutToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "logout_token is required",
+		})
+		return
+	}
+
+	terminated, err := h.logoutService.ProcessLogoutToken(logoutToken)
+	if err != nil {
+		log.Printf("Rejected back-channel logout token: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "Invalid logout token",
+		})
+		return
+	}
+
+	log.Printf("Back-channel logout terminated %d sessions", terminated)
+	c.JSON(http.StatusOK, gin.H{
+		"sessions_terminated": terminated,
+	})
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"domain/auth/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/services/auth"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "test-client-id"
+	testKeyID    = "test-key"
+)
+
+type backchannelTestEnv struct {
+	db             *gorm.DB
+	sessionService *auth.SessionService
+	router         *gin.Engine
+	signingKey     *rsa.PrivateKey
+}
+
+func setupBackchannelLogoutTest(t *testing.T) *backchannelTestEnv {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &valueobjects.GoogleIdentity{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Mock provider JWKS endpoint
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		n := base64.RawURLEncoding.EncodeToString(signingKey.N.Bytes())
+		e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(signingKey.E)).Bytes())
+		w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"` + testKeyID + `","alg":"RS256","use":"sig","n":"` + n + `","e":"` + e + `"}]}`))
+	}))
+	t.Cleanup(jwksServer.Close)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	logoutService := auth.NewBackchannelLogoutService(db, sessionService, auth.BackchannelLogoutConfig{
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		JWKSURL:  jwksServer.URL,
+	})
+
+	router := gin.New()
+	router.POST("/api/v1/auth/backchannel-logout", NewBackchannelLogoutHandler(logoutService).BackchannelLogout)
+
+	return &backchannelTestEnv{db: db, sessionService: sessionService, router: router, signingKey: signingKey}
+}
+
+func (env *backchannelTestEnv) createGoogleUser(t *testing.T, email, googleUserID string) *dtos.User {
+	user := dtos.User{Email: email, Name: "Test User", GoogleID: googleUserID, OAuthProvider: "google", IsActive: true}
+	require.NoError(t, env.db.Create(&user).Error)
+	require.NoError(t, env.db.Create(&valueobjects.GoogleIdentity{
+		UserID:        user.ID,
+		GoogleUserID:  googleUserID,
+		Email:         email,
+		EmailVerified: true,
+	}).Error)
+	return &user
+}
+
+func (env *backchannelTestEnv) createSession(t *testing.T, user *dtos.User, providerSessionID string) *entities.AuthenticationSession {
+	session, _, err := env.sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID:            user.ID,
+		Email:             user.Email,
+		ProviderSessionID: providerSessionID,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func (env *backchannelTestEnv) signLogoutToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	base := jwt.MapClaims{
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"iat":    time.Now().Unix(),
+		"jti":    "logout-" + time.Now().Format(time.RFC3339Nano),
+		"events": map[string]interface{}{auth.BackchannelLogoutEvent: map[string]interface{}{}},
+	}
+	for k, v := range claims {
+		base[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (env *backchannelTestEnv) deliver(logoutToken string) *httptest.ResponseRecorder {
+	form := url.Values{"logout_token": {logoutToken}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/backchannel-logout", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+	return w
+}
+
+func (env *backchannelTestEnv) sessionExists(t *testing.T, sessionID string) bool {
+	valid, err := env.sessionService.IsSessionValid(sessionID)
+	require.NoError(t, err)
+	return valid
+}
+
+func TestBackchannelLogout_SubTerminatesAllUserSessions(t *testing.T) {
+	env := setupBackchannelLogoutTest(t)
+	user := env.createGoogleUser(t, "user@example.com", "google-sub-1")
+	other := env.createGoogleUser(t, "other@example.com", "google-sub-2")
+	first := env.createSession(t, user, "")
+	second := env.createSession(t, user, "")
+	otherSession := env.createSession(t, other, "")
+
+	w := env.deliver(env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1"}))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.False(t, env.sessionExists(t, first.ID))
+	assert.False(t, env.sessionExists(t, second.ID))
+	assert.True(t, env.sessionExists(t, otherSession.ID))
+}
+
+func TestBackchannelLogout_SidTerminatesMatchingSession(t *testing.T) {
+	env := setupBackchannelLogoutTest(t)
+	user := env.createGoogleUser(t, "user@example.com", "google-sub-1")
+	target := env.createSession(t, user, "provider-sid-1")
+	untouched := env.createSession(t, user, "provider-sid-2")
+
+	w := env.deliver(env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1", "sid": "provider-sid-1"}))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, env.sessionExists(t, target.ID))
+	assert.True(t, env.sessionExists(t, untouched.ID))
+}
+
+func TestBackchannelLogout_RejectsInvalidTokens(t *testing.T) {
+	env := setupBackchannelLogoutTest(t)
+	user := env.createGoogleUser(t, "user@example.com", "google-sub-1")
+	session := env.createSession(t, user, "")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"wrong signing key", env.signLogoutToken(t, otherKey, jwt.MapClaims{"sub": "google-sub-1"})},
+		{"wrong audience", env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1", "aud": "someone-else"})},
+		{"wrong issuer", env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1", "iss": "https://evil.example.com"})},
+		{"missing logout event", env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1", "events": map[string]interface{}{}})},
+		{"nonce present", env.signLogoutToken(t, env.signingKey, jwt.MapClaims{"sub": "google-sub-1", "nonce": "abc"})},
+		{"missing sub and sid", env.signLogoutToken(t, env.signingKey, jwt.MapClaims{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := env.deliver(tt.token)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.True(t, env.sessionExists(t, session.ID))
+		})
+	}
+}
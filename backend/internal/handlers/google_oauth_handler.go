@@ -1,31 +1,75 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
+	"domain/auth/entities"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"todo-app/internal/config"
 	"todo-app/internal/dtos"
 	"todo-app/internal/services"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	userservice "todo-app/services/user"
 )
 
+// googleTokenRevoker revokes Google OAuth tokens
+type googleTokenRevoker interface {
+	RevokeToken(ctx context.Context, token string) error
+}
+
+// googleCodeExchanger exchanges an authorization code for the Google user's info
+type googleCodeExchanger interface {
+	ExchangeCode(ctx context.Context, code string) (*services.GoogleUserInfo, error)
+}
+
 // GoogleOAuthHandler handles Google OAuth signup/login requests
 type GoogleOAuthHandler struct {
 	oauthService   *services.GoogleOAuthService
-	sessionService *services.SessionService
+	userService    *userservice.UserService
+	sessionService *auth.SessionService
+	auditService   *audit.AuditService
+	tokenRevoker   googleTokenRevoker
+	codeExchanger  googleCodeExchanger
+
+	// errorRedirectURL and successRedirectURL are the frontend pages failed
+	// and successful sign-ins are sent to
+	errorRedirectURL   string
+	successRedirectURL string
 }
 
-// NewGoogleOAuthHandler creates a new Google OAuth handler
-func NewGoogleOAuthHandler(db *gorm.DB) *GoogleOAuthHandler {
+// NewGoogleOAuthHandler creates a new Google OAuth handler with the Google
+// credentials and redirect settings in cfg
+func NewGoogleOAuthHandler(db *gorm.DB, sessionService *auth.SessionService, cfg config.OAuthConfig) *GoogleOAuthHandler {
+	oauthService := services.NewGoogleOAuthService(db, cfg)
+	userService := userservice.NewUserService(db)
+	userService.SetOAuthAutoLink(cfg.AutoLink)
 	return &GoogleOAuthHandler{
-		oauthService:   services.NewGoogleOAuthService(db),
-		sessionService: services.NewSessionService(),
+		oauthService:   oauthService,
+		userService:    userService,
+		sessionService: sessionService,
+		tokenRevoker:   oauthService,
+		codeExchanger:  oauthService,
+
+		errorRedirectURL:   cfg.ErrorRedirectURL,
+		successRedirectURL: cfg.SuccessRedirectURL,
 	}
 }
 
+// SetAuditService enables audit logging of sign-ins
+func (h *GoogleOAuthHandler) SetAuditService(auditService *audit.AuditService) {
+	h.auditService = auditService
+	h.userService.SetAuditService(auditService)
+}
+
 // GoogleLogin initiates the Google OAuth flow
 // GET /api/v1/auth/google/login
 func (h *GoogleOAuthHandler) GoogleLogin(c *gin.Context) {
@@ -33,20 +77,12 @@ func (h *GoogleOAuthHandler) GoogleLogin(c *gin.Context) {
 	state, err := generateRandomState()
 	if err != nil {
 		log.Printf("Failed to generate state token: %v", err)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
 		return
 	}
 
 	// Store state in session cookie (10 min expiration for the OAuth flow)
-	c.SetCookie(
-		"oauth_state",
-		state,
-		600, // 10 minutes
-		"/",
-		"",
-		false, // Secure (set to true in production with HTTPS)
-		true,  // HttpOnly
-	)
+	h.sessionService.Cookies().SetOAuthStateCookie(c, state, 600) // 10 minutes
 
 	// Generate OAuth URL
 	url := h.oauthService.GenerateAuthURL(state)
@@ -65,39 +101,39 @@ func (h *GoogleOAuthHandler) GoogleCallback(c *gin.Context) {
 	savedState, err := c.Cookie("oauth_state")
 	if err != nil || state != savedState {
 		log.Printf("State validation failed: %v", err)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "invalid_state")
 		return
 	}
 
 	// Clear the state cookie
-	c.SetCookie("oauth_state", "", -1, "/", "", false, true)
+	h.sessionService.Cookies().ClearOAuthStateCookie(c)
 
 	// Handle OAuth error (user denied permission)
 	if c.Query("error") != "" {
 		log.Printf("OAuth error: %s", c.Query("error"))
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "provider_error")
 		return
 	}
 
 	// Exchange code for user info
-	userInfo, err := h.oauthService.ExchangeCode(c.Request.Context(), code)
+	userInfo, err := h.codeExchanger.ExchangeCode(c.Request.Context(), code)
 	if err != nil {
 		log.Printf("Failed to exchange code: %v", err)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "code_exchange_failed")
 		return
 	}
 
 	// Validate email is present
 	if userInfo.Email == "" {
 		log.Printf("No email provided by Google for user: %s", userInfo.GoogleUserID)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "missing_email")
 		return
 	}
 
 	// Validate email is verified
 	if !userInfo.EmailVerified {
 		log.Printf("Email not verified for user: %s", userInfo.Email)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "email_not_verified")
 		return
 	}
 
@@ -105,10 +141,30 @@ func (h *GoogleOAuthHandler) GoogleCallback(c *gin.Context) {
 	existingUser, err := h.oauthService.FindUserByGoogleID(userInfo.GoogleUserID)
 	if err != nil {
 		log.Printf("Error checking for existing user: %v", err)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "user_lookup_failed")
 		return
 	}
 
+	// Accounts linked by email, or through the other OAuth flows, carry the Google ID on the user
+	if existingUser == nil {
+		existingUser, err = h.userService.FindOrLinkOAuthUser(dtos.OAuthProviderGoogle, userInfo.GoogleUserID, userInfo.Email, userInfo.EmailVerified)
+		var confirmErr *userservice.LinkConfirmationRequiredError
+		switch {
+		case errors.As(err, &confirmErr):
+			redirectLinkConfirmationRequired(c, h.errorRedirectURL, confirmErr.Link)
+			return
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			existingUser = nil
+		case errors.Is(err, userservice.ErrAccountAlreadyLinked):
+			h.loginFailedWithError(c, "account_already_linked", "account_already_linked")
+			return
+		case err != nil:
+			log.Printf("Error linking Google account by email: %v", err)
+			h.loginFailed(c, "account_link_failed")
+			return
+		}
+	}
+
 	var user *dtos.User
 	// If user already exists, auto-login (create new session)
 	if existingUser != nil {
@@ -120,32 +176,117 @@ func (h *GoogleOAuthHandler) GoogleCallback(c *gin.Context) {
 		user, err = h.oauthService.CreateUserFromGoogle(userInfo)
 		if err != nil {
 			log.Printf("Failed to create user from Google: %v", err)
-			c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+			h.loginFailed(c, "user_creation_failed")
 			return
 		}
 	}
 
-	// Create session token
-	token, err := h.sessionService.CreateSession(user.ID)
+	// Accounts pending deletion are signed in so they can restore themselves
+	if !user.IsActive && !user.IsPendingDeletion() {
+		h.loginFailedWithError(c, "account_deactivated", "account_deactivated")
+		return
+	}
+
+	// Create a persisted session holding Google's tokens, so they can be
+	// refreshed as the session is used and revoked when Google is unlinked
+	var tokenExpiry *time.Time
+	if !userInfo.TokenExpiry.IsZero() {
+		tokenExpiry = &userInfo.TokenExpiry
+	}
+	session, token, err := h.sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID:            user.ID,
+		Email:             user.Email,
+		UserAgent:         c.Request.UserAgent(),
+		IPAddress:         c.ClientIP(),
+		IsOAuth:           true,
+		AccessToken:       userInfo.AccessToken,
+		RefreshToken:      userInfo.RefreshToken,
+		TokenExpiry:       tokenExpiry,
+		ProviderSessionID: userInfo.ProviderSessionID,
+	})
 	if err != nil {
 		log.Printf("Failed to create session: %v", err)
-		c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=authentication_failed")
+		h.loginFailed(c, "session_creation_failed")
+		return
+	}
+
+	// Set session cookie with the same lifetime as the session record
+	h.sessionService.Cookies().SetSessionCookie(c, token, h.sessionService.GetSessionMaxAge())
+	middleware.IssueCSRFToken(c, h.sessionService, session.ID)
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginSucceeded, user.ID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "google", "session_id": session.ID, "new_user": existingUser == nil}))
+
+	// Accounts pending deletion may only restore themselves; the session lets them do so
+	if user.IsPendingDeletion() {
+		redirectToErrorPage(c, h.errorRedirectURL, "account_pending_deletion")
+		return
+	}
+
+	redirectLoginSucceeded(c, h.successRedirectURL, existingUser == nil)
+}
+
+// loginFailed records a failed sign-in and sends the browser to the signup page
+func (h *GoogleOAuthHandler) loginFailed(c *gin.Context, reason string) {
+	h.loginFailedWithError(c, reason, "authentication_failed")
+}
+
+// loginFailedWithError is loginFailed for failures the user can do something
+// about, telling the signup page which one it was with ?error=errorCode
+func (h *GoogleOAuthHandler) loginFailedWithError(c *gin.Context, reason, errorCode string) {
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginFailed, 0, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "google", "reason": reason}))
+	redirectToErrorPage(c, h.errorRedirectURL, errorCode)
+}
+
+// ConfirmLink completes linking a Google account to the signed-in user
+// POST /api/v1/auth/google/confirm-link
+func (h *GoogleOAuthHandler) ConfirmLink(c *gin.Context) {
+	confirmPendingLink(c, h.userService, dtos.OAuthProviderGoogle)
+}
+
+// UnlinkAccount unlinks the signed-in user's Google account
+// DELETE /api/v1/auth/google/link
+func (h *GoogleOAuthHandler) UnlinkAccount(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	account, tokens, err := h.userService.UnlinkGoogleAccount(userID)
+	switch {
+	case errors.Is(err, userservice.ErrNoAlternativeLoginMethod):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "no_alternative_login_method",
+			"message": "No alternative login method: set a password before unlinking your Google account",
+		})
+		return
+	case errors.Is(err, userservice.ErrGoogleAccountNotLinked):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "google_account_not_linked",
+			"message": "No Google account is linked",
+		})
+		return
+	case err != nil:
+		log.Printf("Failed to unlink Google account for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to unlink Google account",
+		})
 		return
 	}
 
-	// Set session cookie with 7-day expiration
-	c.SetCookie(
-		"session_token",
-		token,
-		h.sessionService.GetSessionMaxAge(), // 7 days
-		"/",
-		"",
-		false, // Secure (set to true in production with HTTPS)
-		true,  // HttpOnly
-	)
+	// The tokens are already cleared from the sessions, so revocation is best-effort
+	for _, token := range tokens {
+		if err := h.tokenRevoker.RevokeToken(c.Request.Context(), token); err != nil {
+			log.Printf("Failed to revoke Google token for user %d: %v", userID, err)
+		}
+	}
 
-	// Redirect to frontend home page
-	c.Redirect(http.StatusFound, "http://localhost:3000/")
+	c.JSON(http.StatusOK, account.ToResponse())
 }
 
 // generateRandomState generates a random state token for CSRF protection
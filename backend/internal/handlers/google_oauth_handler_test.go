@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"domain/auth/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/internal/services"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+)
+
+// fakeTokenRevoker records revoked tokens and fails for those in failFor
+type fakeTokenRevoker struct {
+	revoked []string
+	failFor map[string]bool
+}
+
+func (r *fakeTokenRevoker) RevokeToken(ctx context.Context, token string) error {
+	r.revoked = append(r.revoked, token)
+	if r.failFor[token] {
+		return errors.New("revocation endpoint unavailable")
+	}
+	return nil
+}
+
+// fakeCodeExchanger answers every authorization code with info
+type fakeCodeExchanger struct {
+	info *services.GoogleUserInfo
+}
+
+func (e *fakeCodeExchanger) ExchangeCode(ctx context.Context, code string) (*services.GoogleUserInfo, error) {
+	return e.info, nil
+}
+
+// fakeTokenRefresher renews a session's Google tokens as the token endpoint would,
+// recording the refresh tokens it was given
+type fakeTokenRefresher struct {
+	db        *gorm.DB
+	refreshed []string
+}
+
+func (r *fakeTokenRefresher) RefreshOAuthToken(ctx context.Context, sessionID string) (*entities.AuthenticationSession, error) {
+	var session entities.AuthenticationSession
+	if err := r.db.Where("id = ?", sessionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	r.refreshed = append(r.refreshed, session.RefreshToken)
+	if err := session.UpdateOAuthTokens("access-refreshed", session.RefreshToken, time.Now().Add(time.Hour)); err != nil {
+		return nil, err
+	}
+	return &session, r.db.Save(&session).Error
+}
+
+func setupGoogleOAuthHandlerTest(t *testing.T, revoker *fakeTokenRevoker) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	return setupGoogleOAuthHandlerTestWith(t, revoker, nil)
+}
+
+// setupGoogleOAuthHandlerTestWith is setupGoogleOAuthHandlerTest with the
+// callback exchanging codes through exchanger, when it is not nil
+func setupGoogleOAuthHandlerTestWith(t *testing.T, revoker *fakeTokenRevoker, exchanger *fakeCodeExchanger) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &dtos.OAuthIdentity{}, &valueobjects.GoogleIdentity{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	handler := NewGoogleOAuthHandler(db, sessionService, cfg.OAuth)
+	handler.tokenRevoker = revoker
+	if exchanger != nil {
+		handler.codeExchanger = exchanger
+	}
+
+	router := gin.New()
+	router.GET("/auth/google/callback", handler.GoogleCallback)
+	router.DELETE("/auth/google/link", middleware.NewAuthMiddleware(sessionService, jwtService).RequireAuth(), handler.UnlinkAccount)
+
+	return db, sessionService, router
+}
+
+func unlinkGoogleRequest(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/auth/google/link", token))
+	return w
+}
+
+func createGoogleSession(t *testing.T, sessionService *auth.SessionService, user *dtos.User, accessToken, refreshToken string) string {
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID:       user.ID,
+		Email:        user.Email,
+		IsOAuth:      true,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	})
+	require.NoError(t, err)
+	return token
+}
+
+func TestUnlinkGoogle_RefusesWithoutPassword(t *testing.T) {
+	revoker := &fakeTokenRevoker{}
+	db, sessionService, router := setupGoogleOAuthHandlerTest(t, revoker)
+	user := dtos.User{Email: "google-only@example.com", Name: "Google Only", GoogleID: "google-1", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	token := createGoogleSession(t, sessionService, &user, "access-1", "refresh-1")
+
+	w := unlinkGoogleRequest(router, token)
+
+	// Unlinking would leave the account without a way to sign in
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "no_alternative_login_method", body["error"])
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.Equal(t, "google-1", reloaded.GoogleID)
+
+	var session entities.AuthenticationSession
+	require.NoError(t, db.Where("user_id = ?", user.ID).First(&session).Error)
+	assert.Equal(t, "refresh-1", session.RefreshToken)
+	assert.Empty(t, revoker.revoked)
+}
+
+func TestUnlinkGoogle_UnlinksClearsAndRevokesTokens(t *testing.T) {
+	revoker := &fakeTokenRevoker{failFor: map[string]bool{"access-2": true}}
+	db, sessionService, router := setupGoogleOAuthHandlerTest(t, revoker)
+	user := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", GoogleID: "google-2", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	token := createGoogleSession(t, sessionService, &user, "access-1", "refresh-1")
+	createGoogleSession(t, sessionService, &user, "access-2", "")
+
+	w := unlinkGoogleRequest(router, token)
+
+	// A failed revocation is logged without failing the request
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response dtos.UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.OAuthProvider)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.Empty(t, reloaded.GoogleID)
+	assert.Empty(t, reloaded.OAuthProvider)
+
+	// Each session's refresh token is revoked in preference to its access token
+	assert.ElementsMatch(t, []string{"refresh-1", "access-2"}, revoker.revoked)
+
+	var sessions []entities.AuthenticationSession
+	require.NoError(t, db.Where("user_id = ?", user.ID).Find(&sessions).Error)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.Empty(t, session.AccessToken)
+		assert.Empty(t, session.RefreshToken)
+		assert.Nil(t, session.TokenExpiresAt)
+	}
+
+	// The user stays signed in
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestUnlinkGoogle_AlreadyUnlinked(t *testing.T) {
+	revoker := &fakeTokenRevoker{}
+	db, sessionService, router := setupGoogleOAuthHandlerTest(t, revoker)
+	user := dtos.User{Email: "password@example.com", Name: "Password", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	w := unlinkGoogleRequest(router, token)
+
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "google_account_not_linked", body["error"])
+	assert.Empty(t, revoker.revoked)
+}
+
+func TestGoogleCallback_StoresProviderTokensForRefresh(t *testing.T) {
+	exchanger := &fakeCodeExchanger{info: &services.GoogleUserInfo{
+		GoogleUserID:  "google-123",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+		AccessToken:   "access-1",
+		RefreshToken:  "refresh-1",
+		TokenExpiry:   time.Now().Add(time.Minute),
+	}}
+	db, sessionService, router := setupGoogleOAuthHandlerTestWith(t, &fakeTokenRevoker{}, exchanger)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/google/callback?code=code&state=state", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "state"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/?login=success&new_user=true", w.Header().Get("Location"))
+
+	var sessionToken string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			sessionToken = cookie.Value
+		}
+	}
+	require.NotEmpty(t, sessionToken)
+
+	var session entities.AuthenticationSession
+	require.NoError(t, db.First(&session).Error)
+	assert.True(t, session.IsOAuthSession())
+	assert.Equal(t, "access-1", session.AccessToken)
+	assert.Equal(t, "refresh-1", session.RefreshToken)
+	require.NotNil(t, session.TokenExpiresAt)
+	assert.WithinDuration(t, exchanger.info.TokenExpiry, *session.TokenExpiresAt, time.Second)
+
+	// The access token is about to expire, so using the session renews it with the stored refresh token
+	refresher := &fakeTokenRefresher{db: db}
+	sessionService.SetOAuthTokenRefresher(refresher)
+	result, err := sessionService.ValidateSession(sessionToken)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+	assert.Equal(t, []string{"refresh-1"}, refresher.refreshed)
+	assert.Equal(t, "access-refreshed", result.Session.AccessToken)
+	assert.False(t, result.NeedsRefresh)
+}
+
+func TestGoogleCallback_EmailLinkedToAnotherGoogleAccount(t *testing.T) {
+	exchanger := &fakeCodeExchanger{info: &services.GoogleUserInfo{
+		GoogleUserID:  "google-new",
+		Email:         "linked@example.com",
+		EmailVerified: true,
+	}}
+	db, _, router := setupGoogleOAuthHandlerTestWith(t, &fakeTokenRevoker{}, exchanger)
+	user := dtos.User{Email: "linked@example.com", Name: "Linked", GoogleID: "google-old", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/google/callback?code=code&state=state", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "state"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// The user is told why, rather than that sign-in failed
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/signup?error=account_already_linked", w.Header().Get("Location"))
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+}
+
+func TestGoogleCallback_RejectsDeactivatedUser(t *testing.T) {
+	exchanger := &fakeCodeExchanger{info: &services.GoogleUserInfo{
+		GoogleUserID:  "google-123",
+		Email:         "deactivated@example.com",
+		EmailVerified: true,
+	}}
+	db, _, router := setupGoogleOAuthHandlerTestWith(t, &fakeTokenRevoker{}, exchanger)
+	user := dtos.User{Email: "deactivated@example.com", Name: "Deactivated", GoogleID: "google-123", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Model(&user).Update("is_active", false).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/google/callback?code=code&state=state", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "state"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/signup?error=account_deactivated", w.Header().Get("Location"))
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+}
@@ -1,64 +1,74 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
-	"runtime/debug"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"todo-app/middleware"
 )
 
-// ErrorHandler middleware handles panics and errors
-func ErrorHandler() gin.HandlerFunc {
+// RequestLogger middleware logs each request as a structured record with its
+// method, path, status, duration and request ID. Server errors are logged at
+// error level and client errors at warn level.
+func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		defer func() {
-			if err := recover(); err != nil {
-				// Log the panic
-				log.Printf("Panic occurred: %v\n%s", err, debug.Stack())
+		start := time.Now()
+		path := c.Request.URL.Path
+		raw := c.Request.URL.RawQuery
 
-				// Return generic error response
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "internal_error",
-					"message": "An internal server error occurred",
-				})
-				c.Abort()
+		// Log in a defer so a response aborted with http.ErrAbortHandler,
+		// such as a cut-short export, is still logged before the panic
+		// reaches net/http
+		defer func() {
+			recovered := recover()
+			logRequest(c, start, path, raw, recovered != nil)
+			if recovered != nil {
+				panic(recovered)
 			}
 		}()
 
+		// Process request
 		c.Next()
 	}
 }
 
-// RequestLogger middleware logs incoming requests
-func RequestLogger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
-
-		// Process request
-		c.Next()
+// logRequest writes the structured record for a finished request. Aborted
+// responses are logged at error level whatever status was already sent.
+func logRequest(c *gin.Context, start time.Time, path, raw string, aborted bool) {
+	requestID, ok := middleware.GetRequestID(c)
+	if !ok {
+		requestID = "-"
+	}
 
-		// Calculate request duration
-		duration := time.Since(start)
+	status := c.Writer.Status()
+	level := slog.LevelInfo
+	switch {
+	case aborted || status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
 
-		// Log request details
-		log.Printf(
-			"[%s] %s %s | %d | %v | %s",
-			c.Request.Method,
-			path,
-			func() string {
-				if raw != "" {
-					return "?" + raw
-				}
-				return ""
-			}(),
-			c.Writer.Status(),
-			duration,
-			c.ClientIP(),
-		)
+	attrs := []slog.Attr{
+		slog.String("method", c.Request.Method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("client_ip", c.ClientIP()),
+		slog.String("request_id", requestID),
+	}
+	if raw != "" {
+		attrs = append(attrs, slog.String("query", raw))
 	}
+	if aborted {
+		attrs = append(attrs, slog.Bool("aborted", true))
+	}
+
+	slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
 }
 
 // ValidationErrorHandler handles validation errors
@@ -81,14 +91,24 @@ func ValidationErrorHandler() gin.HandlerFunc {
 	}
 }
 
-// SecurityHeaders middleware adds security headers
-func SecurityHeaders() gin.HandlerFunc {
+// SecurityHeaders middleware adds security headers. A positive hstsMaxAge also
+// sends Strict-Transport-Security, which should only be done when the API is
+// served over HTTPS.
+func SecurityHeaders(hstsMaxAge time.Duration) gin.HandlerFunc {
+	hsts := ""
+	if hstsMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int64(hstsMaxAge.Seconds()))
+	}
+
 	return func(c *gin.Context) {
 		// Add security headers
 		c.Header("X-Content-Type-Options", "nosniff")
 		c.Header("X-Frame-Options", "DENY")
 		c.Header("X-XSS-Protection", "1; mode=block")
 		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
+		if hsts != "" {
+			c.Header("Strict-Transport-Security", hsts)
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/config"
+	"todo-app/internal/logging"
+	"todo-app/middleware"
+)
+
+func TestRequestLogger_WritesStructuredRecord(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo, config.LogFormatJSON))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), RequestLogger())
+	router.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/missing?q=1", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+	assert.Equal(t, "request", record["msg"])
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "/missing", record["path"])
+	assert.Equal(t, "q=1", record["query"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Equal(t, "req-123", record["request_id"])
+	assert.Contains(t, record, "duration")
+}
+
+func TestRequestLogger_LogsAbortedResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo, config.LogFormatJSON))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/export", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		panic(http.ErrAbortHandler)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { router.ServeHTTP(httptest.NewRecorder(), req) })
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, float64(http.StatusOK), record["status"])
+	assert.Equal(t, true, record["aborted"])
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(hstsMaxAge time.Duration) http.Header {
+		router := gin.New()
+		router.Use(SecurityHeaders(hstsMaxAge))
+		router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w.Header()
+	}
+
+	header := serve(0)
+	assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", header.Get("Referrer-Policy"))
+	assert.Empty(t, header.Get("Strict-Transport-Security"))
+
+	header = serve(365 * 24 * time.Hour)
+	assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/reminder"
+)
+
+// notificationLimit caps the number of notifications returned
+const notificationLimit = 100
+
+// NotificationHandler serves the current user's task reminder notifications
+type NotificationHandler struct {
+	reminderService *reminder.ReminderService
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(reminderService *reminder.ReminderService) *NotificationHandler {
+	return &NotificationHandler{
+		reminderService: reminderService,
+	}
+}
+
+// ListNotifications handles GET /api/v1/notifications, listing the user's
+// scheduled and delivered reminders, most recently scheduled first
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	notifications, err := h.reminderService.ListRecent(c.Request.Context(), userID, notificationLimit)
+	if err != nil {
+		log.Printf("Failed to load notifications for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to retrieve notifications",
+		})
+		return
+	}
+	if notifications == nil {
+		notifications = []dtos.Notification{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"todo-app/internal/config"
+	"todo-app/internal/services"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	"todo-app/services/user"
+)
+
+// OAuthHandler handles signup/login through any OAuthProvider
+type OAuthHandler struct {
+	provider       services.OAuthProvider
+	userService    *user.UserService
+	sessionService *auth.SessionService
+
+	// errorRedirectURL and successRedirectURL are the frontend pages failed
+	// and successful sign-ins are sent to
+	errorRedirectURL   string
+	successRedirectURL string
+}
+
+// NewOAuthHandler creates a new OAuth handler for the given provider with the
+// redirect and account linking settings in cfg
+func NewOAuthHandler(provider services.OAuthProvider, db *gorm.DB, sessionService *auth.SessionService, cfg config.OAuthConfig) *OAuthHandler {
+	userService := user.NewUserService(db)
+	userService.SetOAuthAutoLink(cfg.AutoLink)
+	return &OAuthHandler{
+		provider:       provider,
+		userService:    userService,
+		sessionService: sessionService,
+
+		errorRedirectURL:   cfg.ErrorRedirectURL,
+		successRedirectURL: cfg.SuccessRedirectURL,
+	}
+}
+
+// SetAuditService enables audit logging of accounts linked on sign-in
+func (h *OAuthHandler) SetAuditService(auditService *audit.AuditService) {
+	h.userService.SetAuditService(auditService)
+}
+
+// Login initiates the OAuth flow
+// GET /api/v1/auth/{provider}/login
+func (h *OAuthHandler) Login(c *gin.Context) {
+	// Generate random state token for CSRF protection
+	state, err := generateRandomState()
+	if err != nil {
+		log.Printf("Failed to generate state token: %v", err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	// Store state in session cookie (10 min expiration for the OAuth flow)
+	h.sessionService.Cookies().SetOAuthStateCookie(c, state, 600)
+
+	c.Redirect(http.StatusFound, h.provider.AuthURL(state))
+}
+
+// Callback handles the OAuth callback from the provider
+// GET /api/v1/auth/{provider}/callback
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	provider := h.provider.Name()
+
+	// Validate state parameter (CSRF protection)
+	savedState, err := c.Cookie("oauth_state")
+	if err != nil || c.Query("state") != savedState {
+		log.Printf("%s state validation failed: %v", provider, err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	// Clear the state cookie
+	h.sessionService.Cookies().ClearOAuthStateCookie(c)
+
+	// Handle OAuth error (user denied permission)
+	if c.Query("error") != "" {
+		log.Printf("%s OAuth error: %s", provider, c.Query("error"))
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	token, err := h.provider.Exchange(c.Request.Context(), c.Query("code"))
+	if err != nil {
+		log.Printf("Failed to exchange %s code: %v", provider, err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	userInfo, err := h.provider.FetchUserInfo(c.Request.Context(), token)
+	if err != nil {
+		log.Printf("Failed to fetch %s user info: %v", provider, err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	// A verified email is required since it is used for automatic account linking
+	if userInfo.Email == "" || !userInfo.EmailVerified {
+		log.Printf("No verified email provided by %s for user: %s", provider, userInfo.ExternalID)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	account, created, err := h.userService.FindOrCreateOAuthUser(provider, userInfo.ExternalID, userInfo.Email, userInfo.EmailVerified, userInfo.Name)
+	var confirmErr *user.LinkConfirmationRequiredError
+	if errors.As(err, &confirmErr) {
+		redirectLinkConfirmationRequired(c, h.errorRedirectURL, confirmErr.Link)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to find or create %s user: %v", provider, err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	// Accounts pending deletion are signed in so they can restore themselves
+	if !account.IsActive && !account.IsPendingDeletion() {
+		log.Printf("Rejected %s sign-in for deactivated user %d", provider, account.ID)
+		redirectToErrorPage(c, h.errorRedirectURL, "account_deactivated")
+		return
+	}
+
+	// Create a persisted session so the token is accepted by the auth middleware
+	session, sessionToken, err := h.sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID:            account.ID,
+		Email:             account.Email,
+		UserAgent:         c.Request.UserAgent(),
+		IPAddress:         c.ClientIP(),
+		ProviderSessionID: userInfo.ProviderSessionID,
+	})
+	if err != nil {
+		log.Printf("Failed to create session: %v", err)
+		redirectToErrorPage(c, h.errorRedirectURL, "authentication_failed")
+		return
+	}
+
+	// Set session cookie with the same lifetime as the session record
+	h.sessionService.Cookies().SetSessionCookie(c, sessionToken, h.sessionService.GetSessionMaxAge())
+	middleware.IssueCSRFToken(c, h.sessionService, session.ID)
+
+	// Accounts pending deletion may only restore themselves; the session lets them do so
+	if account.IsPendingDeletion() {
+		redirectToErrorPage(c, h.errorRedirectURL, "account_pending_deletion")
+		return
+	}
+
+	redirectLoginSucceeded(c, h.successRedirectURL, created)
+}
+
+// ConfirmLink completes linking the provider account to the signed-in user
+// POST /api/v1/auth/{provider}/confirm-link
+func (h *OAuthHandler) ConfirmLink(c *gin.Context) {
+	confirmPendingLink(c, h.userService, h.provider.Name())
+}
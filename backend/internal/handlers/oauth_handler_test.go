@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/internal/services"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+)
+
+type fakeOAuthProvider struct {
+	info *services.OAuthUserInfo
+}
+
+func (p *fakeOAuthProvider) Name() string { return p.info.Provider }
+
+func (p *fakeOAuthProvider) AuthURL(state string) string {
+	return "https://provider.example.com/authorize?state=" + state
+}
+
+func (p *fakeOAuthProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "token-for-" + code}, nil
+}
+
+func (p *fakeOAuthProvider) FetchUserInfo(ctx context.Context, token *oauth2.Token) (*services.OAuthUserInfo, error) {
+	return p.info, nil
+}
+
+func (p *fakeOAuthProvider) RevokeToken(ctx context.Context, token string) error {
+	return nil
+}
+
+// setupOAuthHandlerTest registers each provider's routes under /auth/{provider}
+func setupOAuthHandlerTest(t *testing.T, infos ...*services.OAuthUserInfo) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &dtos.OAuthIdentity{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}, &entities.PendingAccountLink{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+
+	router := gin.New()
+	for _, info := range infos {
+		handler := NewOAuthHandler(&fakeOAuthProvider{info: info}, db, sessionService, cfg.OAuth)
+		router.GET("/auth/"+info.Provider+"/login", handler.Login)
+		router.GET("/auth/"+info.Provider+"/callback", handler.Callback)
+		router.POST("/auth/"+info.Provider+"/confirm-link", middleware.NewAuthMiddleware(sessionService, jwtService).RequireAuth(), handler.ConfirmLink)
+	}
+
+	return db, sessionService, router
+}
+
+func oauthCallbackRequest(state string) *http.Request {
+	return providerCallbackRequest("github", state, "")
+}
+
+// providerCallbackRequest builds a callback for provider's route, with extra appended to the query
+func providerCallbackRequest(provider, state, extra string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/auth/"+provider+"/callback?code=abc&state="+state+extra, nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: state})
+	return req
+}
+
+func TestOAuthLogin_RedirectsToProviderWithState(t *testing.T) {
+	_, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{Provider: "github"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/github/login", nil))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	var state string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "oauth_state" {
+			state = cookie.Value
+		}
+	}
+	require.NotEmpty(t, state)
+
+	// gin query-escapes cookie values
+	expected, err := url.QueryUnescape(state)
+	require.NoError(t, err)
+	assert.Equal(t, "https://provider.example.com/authorize?state="+expected, w.Header().Get("Location"))
+}
+
+func TestOAuthLogin_StateCookieIsLaxEvenWhenSessionIsStrict(t *testing.T) {
+	// The provider's redirect back is cross-site; a Strict state cookie would not come with it
+	t.Setenv("SESSION_COOKIE_SAMESITE", "Strict")
+	_, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{Provider: "github"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/github/login", nil))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	var stateCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "oauth_state" {
+			stateCookie = cookie
+		}
+	}
+	require.NotNil(t, stateCookie)
+	assert.Equal(t, http.SameSiteLaxMode, stateCookie.SameSite)
+	assert.True(t, stateCookie.HttpOnly)
+}
+
+func TestOAuthCallback_CreatesUserAndSession(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "octocat@example.com", EmailVerified: true, Name: "Octocat",
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/?login=success&new_user=true", w.Header().Get("Location"))
+
+	var user dtos.User
+	require.NoError(t, db.Where("oauth_provider = ? AND oauth_external_id = ?", "github", "583231").First(&user).Error)
+	assert.Equal(t, "octocat@example.com", user.Email)
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", user.ID).Count(&sessions).Error)
+	assert.Equal(t, int64(1), sessions)
+}
+
+func TestOAuthCallback_RedirectsToConfiguredFrontend(t *testing.T) {
+	t.Setenv("OAUTH_SUCCESS_REDIRECT_URL", "https://app.example.com/home?tab=today")
+	_, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "octocat@example.com", EmailVerified: true, Name: "Octocat",
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/home?login=success&new_user=true&tab=today", w.Header().Get("Location"))
+
+	// Signing in again reports the existing account
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-2"))
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/home?login=success&new_user=false&tab=today", w.Header().Get("Location"))
+}
+
+func TestOAuthCallback_RejectsUnverifiedEmail(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "octocat@example.com", EmailVerified: false, Name: "Octocat",
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Contains(t, w.Header().Get("Location"), "error=authentication_failed")
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestOAuthCallback_RejectsDeactivatedUser(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "octocat@example.com", EmailVerified: true, Name: "Octocat",
+	})
+	existing := dtos.User{Email: "octocat@example.com", Name: "Octocat", OAuthProvider: "github", OAuthExternalID: "583231", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+	require.NoError(t, db.Model(&existing).Update("is_active", false).Error)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Contains(t, w.Header().Get("Location"), "error=account_deactivated")
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+}
+
+func TestOAuthCallback_RejectsStateMismatch(t *testing.T) {
+	_, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{Provider: "github"})
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/github/callback?code=abc&state=forged", nil)
+	req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "expected"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Contains(t, w.Header().Get("Location"), "error=authentication_failed")
+}
+
+func TestOAuthCallback_AutoLinksVerifiedEmailByDefault(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "Hybrid@Example.com", EmailVerified: true, Name: "Hybrid",
+	})
+	existing := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/?login=success&new_user=false", w.Header().Get("Location"))
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.Equal(t, "github", reloaded.OAuthProvider)
+	assert.Equal(t, "583231", reloaded.OAuthExternalID)
+}
+
+func TestOAuthCallback_RequiresLinkConfirmationWhenAutoLinkDisabled(t *testing.T) {
+	t.Setenv("OAUTH_AUTO_LINK", "false")
+	t.Setenv("OAUTH_ERROR_REDIRECT_URL", "https://app.example.com/login?source=github")
+	db, sessionService, router := setupOAuthHandlerTest(t, &services.OAuthUserInfo{
+		Provider: "github", ExternalID: "583231", Email: "hybrid@example.com", EmailVerified: true, Name: "Hybrid",
+	})
+	existing := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-1"))
+
+	// No session is issued for the existing account; the browser gets the link token
+	require.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/login", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "github", location.Query().Get("source"))
+	assert.Equal(t, "link_confirmation_required", location.Query().Get("error"))
+	linkToken := location.Query().Get("link_token")
+	require.NotEmpty(t, linkToken)
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+
+	confirm := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/github/confirm-link", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	linkBody, err := json.Marshal(ConfirmLinkRequest{LinkToken: linkToken})
+	require.NoError(t, err)
+
+	// Confirming requires a session, and a session of the account the link is for
+	assert.Equal(t, http.StatusUnauthorized, confirm("", string(linkBody)).Code)
+
+	other := dtos.User{Email: "other@example.com", Name: "Other", GoogleID: "g-other", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&other).Error)
+	_, otherToken, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: other.ID, Email: other.Email})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, confirm(otherToken, string(linkBody)).Code)
+
+	_, ownerToken, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: existing.ID, Email: existing.Email})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, confirm(ownerToken, `{}`).Code)
+
+	w = confirm(ownerToken, string(linkBody))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.Equal(t, "github", reloaded.OAuthProvider)
+	assert.Equal(t, "583231", reloaded.OAuthExternalID)
+
+	// Once linked, signing in with the provider reaches the account directly
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, oauthCallbackRequest("state-2"))
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/?login=success&new_user=false", w.Header().Get("Location"))
+}
+
+func TestOAuthCallback_ProviderComesFromRoute(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t,
+		&services.OAuthUserInfo{Provider: "google", ExternalID: "g-1", Email: "google@example.com", EmailVerified: true, Name: "Google User"},
+		&services.OAuthUserInfo{Provider: "github", ExternalID: "583231", Email: "octocat@example.com", EmailVerified: true, Name: "Octocat"},
+	)
+
+	// A provider named in the query string is ignored
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, providerCallbackRequest("github", "state-1", "&provider=google"))
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "http://localhost:3000/?login=success&new_user=true", w.Header().Get("Location"))
+
+	var users []dtos.User
+	require.NoError(t, db.Find(&users).Error)
+	require.Len(t, users, 1)
+	assert.Equal(t, "github", users[0].OAuthProvider)
+	assert.Equal(t, "583231", users[0].OAuthExternalID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, providerCallbackRequest("google", "state-2", ""))
+	require.Equal(t, http.StatusFound, w.Code)
+
+	var identities []dtos.OAuthIdentity
+	require.NoError(t, db.Order("provider").Find(&identities).Error)
+	require.Len(t, identities, 2)
+	assert.Equal(t, "github", identities[0].Provider)
+	assert.Equal(t, "google", identities[1].Provider)
+	assert.NotEqual(t, identities[0].UserID, identities[1].UserID)
+}
+
+func TestOAuthCallback_UserLinksBothProviders(t *testing.T) {
+	db, _, router := setupOAuthHandlerTest(t,
+		&services.OAuthUserInfo{Provider: "google", ExternalID: "g-1", Email: "both@example.com", EmailVerified: true, Name: "Both"},
+		&services.OAuthUserInfo{Provider: "github", ExternalID: "583231", Email: "both@example.com", EmailVerified: true, Name: "Both"},
+	)
+
+	// Sign up with Google, then sign in with GitHub, then with Google again
+	for i, provider := range []string{"google", "github", "google"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, providerCallbackRequest(provider, fmt.Sprintf("state-%d", i), ""))
+		require.Equal(t, http.StatusFound, w.Code, provider)
+		assert.Equal(t, fmt.Sprintf("http://localhost:3000/?login=success&new_user=%t", i == 0), w.Header().Get("Location"), provider)
+	}
+
+	var users []dtos.User
+	require.NoError(t, db.Find(&users).Error)
+	require.Len(t, users, 1)
+	assert.Equal(t, "google", users[0].OAuthProvider)
+	assert.Equal(t, "g-1", users[0].GoogleID)
+
+	var identities []dtos.OAuthIdentity
+	require.NoError(t, db.Where("user_id = ?", users[0].ID).Order("provider").Find(&identities).Error)
+	require.Len(t, identities, 2)
+	assert.Equal(t, "583231", identities[0].ExternalID)
+	assert.Equal(t, "g-1", identities[1].ExternalID)
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", users[0].ID).Count(&sessions).Error)
+	assert.Equal(t, int64(3), sessions)
+}
@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"domain/auth/entities"
+	uservo "domain/user/valueobjects"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+	userservice "todo-app/services/user"
+)
+
+// verificationSender delivers email verification tokens to users
+type verificationSender interface {
+	SendVerification(email, token string) error
+}
+
+// Mailer delivers password reset tokens to users; plug in a real email sender
+// in production. Without one, password reset is unavailable.
+type Mailer interface {
+	SendPasswordReset(email, token string) error
+}
+
+// Reset emails are limited per address, on top of the per-IP limit on the
+// route, so one inbox cannot be flooded from many IPs
+const (
+	passwordResetEmailInterval = 20 * time.Minute
+	passwordResetEmailBurst    = 3
+)
+
+// PasswordRegisterRequest is the body of POST /api/v1/auth/register
+type PasswordRegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required"`
+	Name     string `json:"name" binding:"required,max=255"`
+}
+
+// PasswordLoginRequest is the body of POST /api/v1/auth/login
+type PasswordLoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ChangePasswordRequest is the body of POST /api/v1/auth/password/change
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ForgotPasswordRequest is the body of POST /api/v1/auth/password/forgot
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPasswordRequest is the body of POST /api/v1/auth/password/reset
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// PasswordAuthHandler handles email/password registration and sign-in
+type PasswordAuthHandler struct {
+	userService        *userservice.UserService
+	sessionService     *auth.SessionService
+	auditService       *audit.AuditService
+	verificationSender verificationSender
+	mailer             Mailer
+	resetEmailLimiter  *middleware.IPRateLimiter
+}
+
+// NewPasswordAuthHandler creates a new password auth handler; verificationSender
+// delivers the email verification token issued on registration and mailer the
+// password reset tokens; a nil verificationSender turns registration off and a
+// nil mailer turns password reset off
+func NewPasswordAuthHandler(db *gorm.DB, sessionService *auth.SessionService, verificationSender verificationSender, mailer Mailer) *PasswordAuthHandler {
+	return &PasswordAuthHandler{
+		userService:        userservice.NewUserService(db),
+		sessionService:     sessionService,
+		verificationSender: verificationSender,
+		mailer:             mailer,
+		resetEmailLimiter:  middleware.NewIPRateLimiter(rate.Every(passwordResetEmailInterval), passwordResetEmailBurst),
+	}
+}
+
+// SetVerificationTokenTTL sets how long the email verification tokens issued
+// on registration stay valid
+func (h *PasswordAuthHandler) SetVerificationTokenTTL(ttl time.Duration) {
+	h.userService.SetVerificationTokenTTL(ttl)
+}
+
+// SetAuditService enables audit logging of sign-ins and password changes
+func (h *PasswordAuthHandler) SetAuditService(auditService *audit.AuditService) {
+	h.auditService = auditService
+	h.userService.SetAuditService(auditService)
+}
+
+// Register creates a password account and signs it in
+// POST /api/v1/auth/register
+func (h *PasswordAuthHandler) Register(c *gin.Context) {
+	if h.registrationUnavailable(c) {
+		return
+	}
+
+	var req PasswordRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "email, password and name are required",
+		})
+		return
+	}
+
+	account, token, err := h.userService.RegisterWithPassword(req.Email, req.Password, req.Name)
+	switch {
+	case errors.Is(err, userservice.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "weak_password",
+			"message": err.Error(),
+		})
+		return
+	case errors.Is(err, userservice.ErrEmailAlreadyRegistered):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "email_already_registered",
+			"message": "An account with this email already exists",
+		})
+		return
+	case err != nil:
+		log.Printf("Failed to register user: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to register",
+		})
+		return
+	}
+
+	// A failed delivery leaves the account unverified rather than failing the registration
+	if err := h.verificationSender.SendVerification(account.Email, token); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", account.ID, err)
+	}
+
+	h.signIn(c, account, http.StatusCreated)
+}
+
+// Login signs in with an email and password
+// POST /api/v1/auth/login
+func (h *PasswordAuthHandler) Login(c *gin.Context) {
+	var req PasswordLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "email and password are required",
+		})
+		return
+	}
+
+	account, err := h.userService.AuthenticatePassword(req.Email, req.Password)
+	switch {
+	case errors.Is(err, userservice.ErrPasswordNotSet):
+		h.loginFailed(c, "password_not_set")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "password_login_unavailable",
+			"message": "This account has no password; sign in with Google or GitHub instead",
+		})
+		return
+	case errors.Is(err, userservice.ErrInvalidCredentials):
+		h.loginFailed(c, "invalid_credentials")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "invalid_credentials",
+			"message": "Invalid email or password",
+		})
+		return
+	case err != nil:
+		log.Printf("Failed to authenticate user: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to sign in",
+		})
+		return
+	}
+
+	// Accounts pending deletion are signed in so they can restore themselves
+	if !account.IsActive && !account.IsPendingDeletion() {
+		h.loginFailed(c, "account_deactivated")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "account_deactivated",
+			"message": "This account has been deactivated",
+		})
+		return
+	}
+
+	h.signIn(c, account, http.StatusOK)
+}
+
+// ChangePassword replaces the signed-in user's password and signs out their other sessions
+// POST /api/v1/auth/password/change
+func (h *PasswordAuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "current_password and new_password are required",
+		})
+		return
+	}
+
+	account, err := h.userService.ChangePassword(userID, req.CurrentPassword, req.NewPassword)
+	switch {
+	case errors.Is(err, userservice.ErrPasswordNotSet):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "password_not_set",
+			"message": "This account has no password to change",
+		})
+		return
+	case errors.Is(err, userservice.ErrInvalidCredentials):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "invalid_current_password",
+			"message": "Current password is incorrect",
+		})
+		return
+	case errors.Is(err, userservice.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "weak_password",
+			"message": err.Error(),
+		})
+		return
+	case err != nil:
+		log.Printf("Failed to change password for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to change password",
+		})
+		return
+	}
+
+	// Whoever knew the old password is signed out everywhere but here
+	currentSessionID, _ := middleware.GetCurrentSessionID(c)
+	if _, err := h.sessionService.TerminateOtherUserSessions(userID, currentSessionID); err != nil {
+		log.Printf("Failed to sign out other sessions of user %d: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
+
+// ForgotPassword emails a password reset token. It responds the same way whether
+// or not the email has an account, so it cannot be used to discover accounts.
+// POST /api/v1/auth/password/forgot
+func (h *PasswordAuthHandler) ForgotPassword(c *gin.Context) {
+	if h.passwordResetUnavailable(c) {
+		return
+	}
+
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "email is required",
+		})
+		return
+	}
+
+	// Requests over the per-email limit are dropped silently, as a 429 would
+	// reveal that earlier requests for the address were accepted
+	if h.resetEmailLimiter.GetLimiter(uservo.NormalizeEmail(req.Email)).Allow() {
+		h.sendPasswordReset(req.Email)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// registrationUnavailable answers 503 when there is no sender for email
+// verification tokens, so no account is created that could never be verified
+func (h *PasswordAuthHandler) registrationUnavailable(c *gin.Context) bool {
+	if h.verificationSender != nil {
+		return false
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "registration_unavailable",
+		"message": "Registration with email and password is not available",
+	})
+	return true
+}
+
+// passwordResetUnavailable answers 503 when there is no mailer to send reset
+// tokens with, so no token is issued that could never be delivered
+func (h *PasswordAuthHandler) passwordResetUnavailable(c *gin.Context) bool {
+	if h.mailer != nil {
+		return false
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "password_reset_unavailable",
+		"message": "Password reset by email is not available",
+	})
+	return true
+}
+
+// sendPasswordReset issues and mails a reset token when email has a password account
+func (h *PasswordAuthHandler) sendPasswordReset(email string) {
+	account, token, err := h.userService.RequestPasswordReset(email)
+	if err != nil {
+		log.Printf("Failed to issue password reset token: %v", err)
+		return
+	}
+	if account == nil {
+		return
+	}
+	if err := h.mailer.SendPasswordReset(account.Email, token); err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", account.ID, err)
+	}
+}
+
+// ResetPassword sets a new password with an emailed reset token and signs the
+// user out of every session
+// POST /api/v1/auth/password/reset
+func (h *PasswordAuthHandler) ResetPassword(c *gin.Context) {
+	if h.passwordResetUnavailable(c) {
+		return
+	}
+
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_request",
+			"message": "token and new_password are required",
+		})
+		return
+	}
+
+	account, err := h.userService.ResetPassword(req.Token, req.NewPassword)
+	switch {
+	case errors.Is(err, userservice.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "weak_password",
+			"message": err.Error(),
+		})
+		return
+	case errors.Is(err, userservice.ErrInvalidResetToken):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_reset_token",
+			"message": "This password reset link is invalid or has expired",
+		})
+		return
+	case err != nil:
+		log.Printf("Failed to reset password: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to reset password",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse())
+}
+
+// signIn creates a session for account, as the OAuth callbacks do, and returns it with the user
+func (h *PasswordAuthHandler) signIn(c *gin.Context, account *dtos.User, status int) {
+	session, token, err := h.sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID:    account.ID,
+		Email:     account.Email,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		IsOAuth:   false,
+	})
+	if err != nil {
+		log.Printf("Failed to create session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to create session",
+		})
+		return
+	}
+
+	h.sessionService.Cookies().SetSessionCookie(c, token, h.sessionService.GetSessionMaxAge())
+	middleware.IssueCSRFToken(c, h.sessionService, session.ID)
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginSucceeded, account.ID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "password", "session_id": session.ID, "new_user": status == http.StatusCreated}))
+
+	c.JSON(status, gin.H{
+		"user":    account.ToResponse(),
+		"session": session.ToResponse(),
+		"token":   token,
+	})
+}
+
+// loginFailed records a failed password sign-in
+func (h *PasswordAuthHandler) loginFailed(c *gin.Context, reason string) {
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLoginFailed, 0, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"provider": "password", "reason": reason}))
+}
@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+	"todo-app/utils"
+)
+
+// fakeMailer records the verification and password reset tokens sent to each email
+type fakeMailer struct {
+	tokens      map[string]string
+	resetTokens map[string][]string
+}
+
+func (s *fakeMailer) SendVerification(email, token string) error {
+	s.tokens[email] = token
+	return nil
+}
+
+func (s *fakeMailer) SendPasswordReset(email, token string) error {
+	s.resetTokens[email] = append(s.resetTokens[email], token)
+	return nil
+}
+
+func setupPasswordAuthHandlerTest(t *testing.T) (*gorm.DB, *auth.SessionService, *fakeMailer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}, &entities.PasswordResetToken{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	sender := &fakeMailer{tokens: map[string]string{}, resetTokens: map[string][]string{}}
+	handler := NewPasswordAuthHandler(db, sessionService, sender, sender)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+	limiter := middleware.NewIPRateLimiter(rate.Every(time.Hour), 5)
+
+	router := gin.New()
+	router.POST("/auth/register", limiter.RateLimitMiddleware(), handler.Register)
+	router.POST("/auth/login", limiter.RateLimitMiddleware(), handler.Login)
+	router.POST("/auth/password/change", authMiddleware.RequireAuth(), handler.ChangePassword)
+	router.POST("/auth/password/forgot", limiter.RateLimitMiddleware(), handler.ForgotPassword)
+	router.POST("/auth/password/reset", handler.ResetPassword)
+
+	return db, sessionService, sender, router
+}
+
+// passwordAuthRequest posts body as JSON, authenticated with token if it is set
+func passwordAuthRequest(router *gin.Engine, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type passwordAuthResponse struct {
+	User    dtos.UserResponse        `json:"user"`
+	Session entities.SessionResponse `json:"session"`
+	Token   string                   `json:"token"`
+}
+
+// createPasswordUser creates a password account directly in the database
+func createPasswordUser(t *testing.T, db *gorm.DB, email, password string) *dtos.User {
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := dtos.User{Email: email, Name: "Password User", PasswordHash: hash, IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	return &user
+}
+
+func TestPasswordRegister_CreatesUserAndSession(t *testing.T) {
+	db, sessionService, sender, router := setupPasswordAuthHandlerTest(t)
+
+	w := passwordAuthRequest(router, "/auth/register", gin.H{"email": "New@Example.com", "password": "correct horse 1", "name": "New User"}, "")
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response passwordAuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "new@example.com", response.User.Email)
+	assert.Equal(t, "New User", response.User.Name)
+	assert.False(t, response.User.EmailVerified)
+	require.NotEmpty(t, response.Token)
+
+	// The session goes through the same validation as an OAuth one
+	result, err := sessionService.ValidateSession(response.Token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	var user dtos.User
+	require.NoError(t, db.First(&user, response.User.ID).Error)
+	assert.NotEqual(t, "correct horse 1", user.PasswordHash)
+	assert.True(t, utils.VerifyPassword("correct horse 1", user.PasswordHash))
+	require.NotNil(t, user.VerificationToken)
+	assert.Equal(t, *user.VerificationToken, sender.tokens["new@example.com"])
+
+	// The email is taken now, whatever its case
+	w = passwordAuthRequest(router, "/auth/register", gin.H{"email": "NEW@example.com", "password": "another pass 2", "name": "Again"}, "")
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
+
+func TestPasswordRegister_RejectsWeakPassword(t *testing.T) {
+	db, _, _, router := setupPasswordAuthHandlerTest(t)
+
+	for _, password := range []string{"short1", "onlyletters", "1234567890"} {
+		w := passwordAuthRequest(router, "/auth/register", gin.H{"email": "weak@example.com", "password": password, "name": "Weak"}, "")
+		require.Equal(t, http.StatusBadRequest, w.Code, password)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "weak_password", body["error"], password)
+	}
+
+	w := passwordAuthRequest(router, "/auth/register", gin.H{"email": "weak@example.com", "name": "Weak"}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestPasswordLogin_IssuesSession(t *testing.T) {
+	db, sessionService, _, router := setupPasswordAuthHandlerTest(t)
+	user := createPasswordUser(t, db, "login@example.com", "s3cret-password")
+
+	w := passwordAuthRequest(router, "/auth/login", gin.H{"email": "Login@Example.com", "password": "s3cret-password"}, "")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response passwordAuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, user.ID, response.User.ID)
+
+	var sessionCookie string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			sessionCookie = cookie.Value
+		}
+	}
+	assert.Equal(t, response.Token, sessionCookie)
+
+	result, err := sessionService.ValidateSession(response.Token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.False(t, result.Session.IsOAuthSession())
+}
+
+func TestPasswordLogin_SessionCookieSameSite(t *testing.T) {
+	tests := []struct {
+		sameSite string
+		secure   string
+		want     string
+	}{
+		{"", "", "SameSite=Lax"},
+		{"Strict", "", "SameSite=Strict"},
+		// SESSION_COOKIE_SECURE accepts the same values as config.Load
+		{"None", "1", "SameSite=None"},
+	}
+
+	for _, tt := range tests {
+		t.Run("SESSION_COOKIE_SAMESITE="+tt.sameSite, func(t *testing.T) {
+			t.Setenv("SESSION_COOKIE_SAMESITE", tt.sameSite)
+			t.Setenv("SESSION_COOKIE_SECURE", tt.secure)
+			db, _, _, router := setupPasswordAuthHandlerTest(t)
+			createPasswordUser(t, db, "login@example.com", "s3cret-password")
+
+			w := passwordAuthRequest(router, "/auth/login", gin.H{"email": "login@example.com", "password": "s3cret-password"}, "")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var sessionCookie string
+			for _, header := range w.Header().Values("Set-Cookie") {
+				if strings.HasPrefix(header, "session_token=") {
+					sessionCookie = header
+				}
+			}
+			require.NotEmpty(t, sessionCookie)
+			assert.Contains(t, sessionCookie, tt.want)
+			assert.Contains(t, sessionCookie, "HttpOnly")
+			assert.Equal(t, tt.secure == "1", strings.Contains(sessionCookie, "Secure"))
+		})
+	}
+}
+
+func TestPasswordLogin_RejectsBadCredentials(t *testing.T) {
+	db, _, _, router := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "login@example.com", "s3cret-password")
+
+	// A wrong password and an unknown email are indistinguishable
+	for _, body := range []gin.H{
+		{"email": "login@example.com", "password": "wrong-password1"},
+		{"email": "nobody@example.com", "password": "s3cret-password"},
+	} {
+		w := passwordAuthRequest(router, "/auth/login", body, "")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		var response map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid_credentials", response["error"])
+	}
+
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+}
+
+func TestPasswordLogin_OAuthOnlyUserGetsClearError(t *testing.T) {
+	db, _, _, router := setupPasswordAuthHandlerTest(t)
+	oauthUser := dtos.User{Email: "oauth-only@example.com", Name: "OAuth Only", GoogleID: "google-1", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&oauthUser).Error)
+
+	w := passwordAuthRequest(router, "/auth/login", gin.H{"email": "oauth-only@example.com", "password": "anything-1"}, "")
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "password_login_unavailable", response["error"])
+	assert.Contains(t, response["message"], "no password")
+}
+
+func TestPasswordLogin_RateLimited(t *testing.T) {
+	db, _, _, router := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "login@example.com", "s3cret-password")
+
+	for i := 0; i < 5; i++ {
+		w := passwordAuthRequest(router, "/auth/login", gin.H{"email": "login@example.com", "password": "wrong-password1"}, "")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// Even the right password waits once the bucket is empty
+	w := passwordAuthRequest(router, "/auth/login", gin.H{"email": "login@example.com", "password": "s3cret-password"}, "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestChangePassword_RequiresCurrentPassword(t *testing.T) {
+	db, sessionService, _, router := setupPasswordAuthHandlerTest(t)
+	user := createPasswordUser(t, db, "change@example.com", "old-password1")
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	_, otherToken, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	w := passwordAuthRequest(router, "/auth/password/change", gin.H{"current_password": "new-password2", "new_password": "new-password2"}, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = passwordAuthRequest(router, "/auth/password/change", gin.H{"current_password": "wrong-password1", "new_password": "new-password2"}, token)
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	w = passwordAuthRequest(router, "/auth/password/change", gin.H{"current_password": "old-password1", "new_password": "weak"}, token)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = passwordAuthRequest(router, "/auth/password/change", gin.H{"current_password": "old-password1", "new_password": "new-password2"}, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The new password signs in, the old one no longer does
+	w = passwordAuthRequest(router, "/auth/login", gin.H{"email": "change@example.com", "password": "new-password2"}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = passwordAuthRequest(router, "/auth/login", gin.H{"email": "change@example.com", "password": "old-password1"}, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Other sessions are signed out; the one that changed the password is kept
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	result, err = sessionService.ValidateSession(otherToken)
+	assert.True(t, err != nil || !result.Valid)
+}
+
+func TestChangePassword_OAuthOnlyUser(t *testing.T) {
+	db, sessionService, _, router := setupPasswordAuthHandlerTest(t)
+	user := dtos.User{Email: "oauth-only@example.com", Name: "OAuth Only", GoogleID: "google-1", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	w := passwordAuthRequest(router, "/auth/password/change", gin.H{"current_password": "anything-1", "new_password": "new-password2"}, token)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "password_not_set", response["error"])
+}
+
+// requestPasswordReset asks for a reset of email's password and returns the token mailed, if any
+func requestPasswordReset(t *testing.T, router *gin.Engine, sender *fakeMailer, email string) string {
+	sent := len(sender.resetTokens[email])
+	w := passwordAuthRequest(router, "/auth/password/forgot", gin.H{"email": email}, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	if len(sender.resetTokens[email]) == sent {
+		return ""
+	}
+	return sender.resetTokens[email][sent]
+}
+
+func TestForgotPassword_SameResponseForUnknownEmail(t *testing.T) {
+	db, _, sender, router := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "known@example.com", "old password 1")
+	oauthOnly := dtos.User{Email: "oauth@example.com", Name: "OAuth", GoogleID: "google-1", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&oauthOnly).Error)
+
+	known := passwordAuthRequest(router, "/auth/password/forgot", gin.H{"email": "Known@Example.com"}, "")
+	unknown := passwordAuthRequest(router, "/auth/password/forgot", gin.H{"email": "nobody@example.com"}, "")
+	oauth := passwordAuthRequest(router, "/auth/password/forgot", gin.H{"email": "oauth@example.com"}, "")
+
+	require.Equal(t, http.StatusOK, known.Code, known.Body.String())
+	assert.Equal(t, known.Code, unknown.Code)
+	assert.Equal(t, known.Body.String(), unknown.Body.String())
+	assert.Equal(t, known.Body.String(), oauth.Body.String())
+
+	// Only the password account is sent a token, and only its hash is stored
+	require.Len(t, sender.resetTokens["known@example.com"], 1)
+	assert.Empty(t, sender.resetTokens["nobody@example.com"])
+	assert.Empty(t, sender.resetTokens["oauth@example.com"])
+
+	var stored entities.PasswordResetToken
+	require.NoError(t, db.First(&stored).Error)
+	raw := sender.resetTokens["known@example.com"][0]
+	assert.NotEqual(t, raw, stored.TokenHash)
+	assert.Equal(t, entities.HashPasswordResetToken(raw), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(entities.PasswordResetTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestForgotPassword_LimitsEmailsPerAddress(t *testing.T) {
+	db, _, sender, router := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "flooded@example.com", "old password 1")
+
+	for i := 0; i < passwordResetEmailBurst+1; i++ {
+		// Requests from different IPs still count against the address
+		payload, _ := json.Marshal(gin.H{"email": "flooded@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = fmt.Sprintf("192.0.2.%d:1234", i+1)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	assert.Len(t, sender.resetTokens["flooded@example.com"], passwordResetEmailBurst)
+}
+
+func TestResetPassword_SetsPasswordAndEndsSessions(t *testing.T) {
+	db, sessionService, sender, router := setupPasswordAuthHandlerTest(t)
+	user := createPasswordUser(t, db, "reset@example.com", "old password 1")
+	_, sessionToken, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	token := requestPasswordReset(t, router, sender, "reset@example.com")
+	require.NotEmpty(t, token)
+
+	w := passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": token, "new_password": "new password 2"}, "")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.True(t, utils.VerifyPassword("new password 2", reloaded.PasswordHash))
+
+	// Every existing session is signed out
+	result, err := sessionService.ValidateSession(sessionToken)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	var sessions int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", user.ID).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+
+	login := passwordAuthRequest(router, "/auth/login", gin.H{"email": "reset@example.com", "password": "new password 2"}, "")
+	assert.Equal(t, http.StatusOK, login.Code, login.Body.String())
+}
+
+func TestResetPassword_TokenIsSingleUse(t *testing.T) {
+	db, _, sender, router := setupPasswordAuthHandlerTest(t)
+	user := createPasswordUser(t, db, "reuse@example.com", "old password 1")
+	first := requestPasswordReset(t, router, sender, "reuse@example.com")
+	second := requestPasswordReset(t, router, sender, "reuse@example.com")
+
+	w := passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": first, "new_password": "new password 2"}, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Neither the used token nor the other one issued meanwhile works again
+	for _, token := range []string{first, second} {
+		w = passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": token, "new_password": "third password 3"}, "")
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "invalid_reset_token", body["error"])
+	}
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.True(t, utils.VerifyPassword("new password 2", reloaded.PasswordHash))
+}
+
+func TestResetPassword_RejectsExpiredToken(t *testing.T) {
+	db, _, sender, router := setupPasswordAuthHandlerTest(t)
+	user := createPasswordUser(t, db, "expired@example.com", "old password 1")
+	token := requestPasswordReset(t, router, sender, "expired@example.com")
+	require.NoError(t, db.Model(&entities.PasswordResetToken{}).Where("user_id = ?", user.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	w := passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": token, "new_password": "new password 2"}, "")
+
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_reset_token", body["error"])
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.True(t, utils.VerifyPassword("old password 1", reloaded.PasswordHash))
+}
+
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	db, _, sender, router := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "weak@example.com", "old password 1")
+	token := requestPasswordReset(t, router, sender, "weak@example.com")
+
+	w := passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": token, "new_password": "short"}, "")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "weak_password", body["error"])
+
+	w = passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": token, "new_password": "new password 2"}, "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestPasswordReset_UnavailableWithoutMailer(t *testing.T) {
+	db, sessionService, sender, _ := setupPasswordAuthHandlerTest(t)
+	createPasswordUser(t, db, "nomailer@example.com", "old password 1")
+	handler := NewPasswordAuthHandler(db, sessionService, sender, nil)
+	router := gin.New()
+	router.POST("/auth/password/forgot", handler.ForgotPassword)
+	router.POST("/auth/password/reset", handler.ResetPassword)
+
+	w := passwordAuthRequest(router, "/auth/password/forgot", gin.H{"email": "nomailer@example.com"}, "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	var count int64
+	require.NoError(t, db.Model(&entities.PasswordResetToken{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	w = passwordAuthRequest(router, "/auth/password/reset", gin.H{"token": "anything", "new_password": "new password 2"}, "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+}
+
+func TestPasswordRegister_UnavailableWithoutVerificationSender(t *testing.T) {
+	db, sessionService, sender, _ := setupPasswordAuthHandlerTest(t)
+	handler := NewPasswordAuthHandler(db, sessionService, nil, sender)
+	router := gin.New()
+	router.POST("/auth/register", handler.Register)
+
+	w := passwordAuthRequest(router, "/auth/register", gin.H{"email": "new@example.com", "password": "correct horse 1", "name": "New User"}, "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "registration_unavailable")
+	var count int64
+	require.NoError(t, db.Model(&dtos.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
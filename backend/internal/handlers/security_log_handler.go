@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+)
+
+// securityLogLimit caps the number of login events returned in the security log
+const securityLogLimit = 100
+
+// SecurityLogHandler serves the current user's login history
+type SecurityLogHandler struct {
+	sessionService *auth.SessionService
+}
+
+// NewSecurityLogHandler creates a new SecurityLogHandler instance
+func NewSecurityLogHandler(sessionService *auth.SessionService) *SecurityLogHandler {
+	return &SecurityLogHandler{
+		sessionService: sessionService,
+	}
+}
+
+// GetSecurityLog handles GET /api/v1/users/me/security-log
+func (h *SecurityLogHandler) GetSecurityLog(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	events, err := h.sessionService.GetLoginHistory(userID, securityLogLimit)
+	if err != nil {
+		log.Printf("Failed to load security log for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to retrieve security log",
+		})
+		return
+	}
+
+	responses := make([]entities.LoginEventResponse, 0, len(events))
+	for i := range events {
+		responses = append(responses, events[i].ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": responses,
+		"count":  len(responses),
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/auth"
+)
+
+func TestGetSecurityLog_ListsLoginEventsNewestFirst(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+
+	router := gin.New()
+	router.GET("/api/v1/users/me/security-log", authMiddleware.RequireAuth(), NewSecurityLogHandler(sessionService).GetSecurityLog)
+
+	user := dtos.User{Email: "user@example.com", Name: "Test User", GoogleID: "google-123", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	other := dtos.User{Email: "other@example.com", Name: "Other User", GoogleID: "google-456", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&other).Error)
+
+	logins := []struct {
+		userAgent string
+		ipAddress string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0)", "203.0.113.42"},
+		{"Mozilla/5.0 (iPhone)", "198.51.100.7"},
+		{"curl/8.0", "2001:db8:abcd:12::1"},
+	}
+
+	var token string
+	for _, login := range logins {
+		_, token, err = sessionService.CreateSession(auth.CreateSessionRequest{
+			UserID:    user.ID,
+			Email:     user.Email,
+			UserAgent: login.userAgent,
+			IPAddress: login.ipAddress,
+		})
+		require.NoError(t, err)
+	}
+
+	// Another user's logins must not appear
+	_, _, err = sessionService.CreateSession(auth.CreateSessionRequest{UserID: other.ID, Email: other.Email, IPAddress: "192.0.2.1"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/security-log", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Events []entities.LoginEventResponse `json:"events"`
+		Count  int                           `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, 3, body.Count)
+	require.Len(t, body.Events, 3)
+
+	assert.Equal(t, "curl/8.0", body.Events[0].Device)
+	assert.Equal(t, "2001:db8:abcd::", body.Events[0].IPAddress)
+	assert.Equal(t, "Mozilla/5.0 (iPhone)", body.Events[1].Device)
+	assert.Equal(t, "198.51.100.0", body.Events[1].IPAddress)
+	assert.Equal(t, "Mozilla/5.0 (Windows NT 10.0)", body.Events[2].Device)
+	assert.Equal(t, "203.0.113.0", body.Events[2].IPAddress)
+
+	for _, event := range body.Events {
+		assert.False(t, event.Timestamp.IsZero())
+	}
+}
+
+func TestGetSecurityLog_RequiresAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/api/v1/users/me/security-log", NewSecurityLogHandler(nil).GetSecurityLog)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/security-log", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
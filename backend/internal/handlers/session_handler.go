@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+)
+
+// SessionHandler lets users sign out, list their sessions and sign out other devices
+type SessionHandler struct {
+	sessionService *auth.SessionService
+	auditService   *audit.AuditService
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(sessionService *auth.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// SetAuditService enables audit logging of sign-outs and revoked sessions
+func (h *SessionHandler) SetAuditService(auditService *audit.AuditService) {
+	h.auditService = auditService
+}
+
+// ListSessions handles GET /api/v1/auth/sessions
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+	currentSessionID, _ := middleware.GetCurrentSessionID(c)
+
+	sessions, err := h.sessionService.GetUserSessions(userID)
+	if err != nil {
+		log.Printf("Failed to list sessions for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to retrieve sessions",
+		})
+		return
+	}
+
+	responses := make([]entities.ActiveSessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, sessions[i].ToActiveSessionResponse(currentSessionID))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": responses,
+		"count":    len(responses),
+	})
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := h.sessionService.TerminateUserSession(userID, sessionID); err != nil {
+		// Sessions of other users are reported as missing so their IDs cannot be probed
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "session_not_found",
+				"message": "Session not found",
+			})
+			return
+		}
+		log.Printf("Failed to revoke session for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to revoke session",
+		})
+		return
+	}
+
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventSessionTerminated, userID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"session_id": sessionID}))
+
+	// Revoking the current session signs this client out too
+	if currentSessionID, _ := middleware.GetCurrentSessionID(c); sessionID == currentSessionID {
+		h.sessionService.Cookies().ClearSessionCookie(c)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions handles DELETE /api/v1/auth/sessions
+// Every session except the one making the request is revoked.
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+	currentSessionID, _ := middleware.GetCurrentSessionID(c)
+
+	revoked, err := h.sessionService.TerminateOtherUserSessions(userID, currentSessionID)
+	if err != nil {
+		log.Printf("Failed to revoke other sessions for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to revoke sessions",
+		})
+		return
+	}
+
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventSessionTerminated, userID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"kept_session_id": currentSessionID, "revoked": revoked}))
+
+	c.JSON(http.StatusOK, gin.H{
+		"revoked": revoked,
+	})
+}
+
+// Logout handles POST /api/v1/auth/logout
+// It ends the session making the request and clears its cookie.
+func (h *SessionHandler) Logout(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+	sessionID, _ := middleware.GetCurrentSessionID(c)
+
+	// A session revoked since RequireAuth checked it is already signed out
+	if err := h.sessionService.TerminateUserSession(userID, sessionID); err != nil && !errors.Is(err, auth.ErrSessionNotFound) {
+		log.Printf("Failed to sign out session for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to sign out",
+		})
+		return
+	}
+
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLogout, userID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"session_id": sessionID}))
+
+	h.sessionService.Cookies().ClearSessionCookie(c)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out successfully",
+	})
+}
+
+// LogoutAll handles POST /api/v1/auth/logout-all
+// It ends every session of the current user, including this one, and clears its cookie.
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	terminated, err := h.sessionService.TerminateAllUserSessions(userID)
+	if err != nil {
+		log.Printf("Failed to sign out all sessions for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to terminate sessions",
+		})
+		return
+	}
+
+	h.auditService.Record(entities.NewAuditLog(entities.AuditEventLogout, userID, c.ClientIP(), c.Request.UserAgent(),
+		entities.AuditMetadata{"all_sessions": true, "sessions_terminated": terminated}))
+
+	h.sessionService.Cookies().ClearSessionCookie(c)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":             true,
+		"message":             "Logged out of all sessions",
+		"sessions_terminated": terminated,
+	})
+}
+
+// RefreshSession handles POST /api/v1/auth/session/refresh
+// It extends the current session and rotates it to a new ID and token, so the
+// previous token stops working. RequireAuth has already renewed any OAuth tokens
+// that were about to expire.
+func (h *SessionHandler) RefreshSession(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+	sessionID, _ := middleware.GetCurrentSessionID(c)
+
+	refreshed, token, err := h.sessionService.RefreshSession(sessionID)
+	if err != nil {
+		// A concurrent refresh already rotated this session
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "invalid_session",
+				"message": "Session is no longer valid; please sign in again",
+			})
+			return
+		}
+		log.Printf("Failed to refresh session for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Failed to refresh session",
+		})
+		return
+	}
+
+	h.sessionService.Cookies().SetSessionCookie(c, token, h.sessionService.GetSessionMaxAge())
+	// The rotated session has a new CSRF token
+	middleware.IssueCSRFToken(c, h.sessionService, refreshed.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"session": refreshed.ToResponse(),
+	})
+}
+
+// GetCSRFToken handles GET /api/v1/auth/csrf
+// It returns the current session's CSRF token, for clients that cannot read the
+// cookie; RequireAuth has already reissued the cookie if it was missing or stale.
+func (h *SessionHandler) GetCSRFToken(c *gin.Context) {
+	sessionID, ok := middleware.GetCurrentSessionID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"csrf_token": h.sessionService.CSRFToken(sessionID),
+	})
+}
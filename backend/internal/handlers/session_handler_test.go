@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/middleware"
+	"todo-app/services/audit"
+	"todo-app/services/auth"
+)
+
+func setupSessionHandlerTest(t *testing.T) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	return setupSessionHandlerTestWith(t, func(*gorm.DB, *SessionHandler) {})
+}
+
+// setupSessionHandlerTestWith is setupSessionHandlerTest with configure run on
+// the handler before any requests
+func setupSessionHandlerTestWith(t *testing.T, configure func(db *gorm.DB, handler *SessionHandler)) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}, &entities.AuditLog{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	authMiddleware := middleware.NewAuthMiddleware(sessionService, jwtService)
+	handler := NewSessionHandler(sessionService)
+	configure(db, handler)
+
+	router := gin.New()
+	router.POST("/api/v1/auth/logout", authMiddleware.RequireAuth(), handler.Logout)
+	router.POST("/api/v1/auth/logout-all", authMiddleware.RequireAuth(), handler.LogoutAll)
+	sessions := router.Group("/api/v1/auth/sessions", authMiddleware.RequireAuth())
+	sessions.GET("", handler.ListSessions)
+	sessions.DELETE("", handler.RevokeOtherSessions)
+	sessions.DELETE("/:id", handler.RevokeSession)
+	router.GET("/api/v1/auth/csrf", authMiddleware.RequireAuth(), handler.GetCSRFToken)
+
+	return db, sessionService, router
+}
+
+type testSession struct {
+	id    string
+	token string
+}
+
+// createUserSessions creates a user signed in on the given devices
+func createUserSessions(t *testing.T, db *gorm.DB, sessionService *auth.SessionService, email string, userAgents ...string) (*dtos.User, []testSession) {
+	user := dtos.User{Email: email, Name: "Test User", GoogleID: "google-" + email, OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+
+	sessions := make([]testSession, 0, len(userAgents))
+	for _, userAgent := range userAgents {
+		session, token, err := sessionService.CreateSession(auth.CreateSessionRequest{
+			UserID:    user.ID,
+			Email:     user.Email,
+			UserAgent: userAgent,
+			IPAddress: "203.0.113.42",
+		})
+		require.NoError(t, err)
+		sessions = append(sessions, testSession{id: session.ID, token: token})
+	}
+
+	return &user, sessions
+}
+
+func assertSessionValid(t *testing.T, sessionService *auth.SessionService, token string, valid bool) {
+	t.Helper()
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.Equal(t, valid, result.Valid)
+}
+
+func TestListSessions_MarksCurrentAndHidesTokens(t *testing.T) {
+	db, sessionService, router := setupSessionHandlerTest(t)
+	_, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone")
+	createUserSessions(t, db, sessionService, "other@example.com", "Other device")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodGet, "/api/v1/auth/sessions", sessions[0].token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		Sessions []map[string]interface{} `json:"sessions"`
+		Count    int                      `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, 2, body.Count)
+
+	current := map[string]bool{}
+	for _, session := range body.Sessions {
+		keys := make([]string, 0, len(session))
+		for key := range session {
+			keys = append(keys, key)
+		}
+		assert.ElementsMatch(t, []string{"id", "created_at", "last_activity", "user_agent", "ip_address", "current"}, keys)
+		assert.Equal(t, "203.0.113.0", session["ip_address"])
+		current[session["user_agent"].(string)] = session["current"].(bool)
+	}
+	assert.Equal(t, map[string]bool{"Laptop": true, "Phone": false}, current)
+	assert.NotContains(t, w.Body.String(), sessions[0].token)
+}
+
+func TestRevokeSession_InvalidatesItImmediately(t *testing.T) {
+	db, sessionService, router := setupSessionHandlerTest(t)
+	_, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/auth/sessions/"+sessions[1].id, sessions[0].token))
+
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assertSessionValid(t, sessionService, sessions[1].token, false)
+	assertSessionValid(t, sessionService, sessions[0].token, true)
+
+	// Already revoked
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/auth/sessions/"+sessions[1].id, sessions[0].token))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeSession_OtherUsersSessionIsNotFound(t *testing.T) {
+	db, sessionService, router := setupSessionHandlerTest(t)
+	_, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop")
+	_, others := createUserSessions(t, db, sessionService, "other@example.com", "Other device")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/auth/sessions/"+others[0].id, sessions[0].token))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "session_not_found", body["error"])
+	assertSessionValid(t, sessionService, others[0].token, true)
+}
+
+func TestRevokeOtherSessions_KeepsCurrentSession(t *testing.T) {
+	db, sessionService, router := setupSessionHandlerTest(t)
+	_, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone", "Tablet")
+	_, others := createUserSessions(t, db, sessionService, "other@example.com", "Other device")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodDelete, "/api/v1/auth/sessions", sessions[0].token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(2), body["revoked"])
+
+	assertSessionValid(t, sessionService, sessions[0].token, true)
+	assertSessionValid(t, sessionService, sessions[1].token, false)
+	assertSessionValid(t, sessionService, sessions[2].token, false)
+	assertSessionValid(t, sessionService, others[0].token, true)
+}
+
+func TestLogout_EndsCurrentSessionAndIsAudited(t *testing.T) {
+	var auditService *audit.AuditService
+	db, sessionService, router := setupSessionHandlerTestWith(t, func(db *gorm.DB, handler *SessionHandler) {
+		auditService = audit.NewAuditService(db, 0)
+		handler.SetAuditService(auditService)
+	})
+	user, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodPost, "/api/v1/auth/logout", sessions[0].token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assertSessionValid(t, sessionService, sessions[0].token, false)
+	assertSessionValid(t, sessionService, sessions[1].token, true)
+
+	cleared := false
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			cleared = cookie.Value == "" && cookie.MaxAge < 0
+		}
+	}
+	assert.True(t, cleared, "session cookie is cleared")
+
+	require.NoError(t, auditService.Close(context.Background()))
+	logs, total, err := auditService.List(audit.AuditLogFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, entities.AuditEventLogout, logs[0].EventType)
+	require.NotNil(t, logs[0].UserID)
+	assert.Equal(t, user.ID, *logs[0].UserID)
+	assert.Equal(t, sessions[0].id, logs[0].Metadata["session_id"])
+}
+
+func TestLogoutAll_EndsEverySessionOfCurrentUser(t *testing.T) {
+	var auditService *audit.AuditService
+	db, sessionService, router := setupSessionHandlerTestWith(t, func(db *gorm.DB, handler *SessionHandler) {
+		auditService = audit.NewAuditService(db, 0)
+		handler.SetAuditService(auditService)
+	})
+	user, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone")
+	_, others := createUserSessions(t, db, sessionService, "other@example.com", "Other device")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodPost, "/api/v1/auth/logout-all", sessions[0].token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(2), body["sessions_terminated"])
+	assert.NotContains(t, body, "details")
+
+	assertSessionValid(t, sessionService, sessions[0].token, false)
+	assertSessionValid(t, sessionService, sessions[1].token, false)
+	assertSessionValid(t, sessionService, others[0].token, true)
+
+	require.NoError(t, auditService.Close(context.Background()))
+	logs, total, err := auditService.List(audit.AuditLogFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, entities.AuditEventLogout, logs[0].EventType)
+	require.NotNil(t, logs[0].UserID)
+	assert.Equal(t, user.ID, *logs[0].UserID)
+
+	// The signed-out session cannot call it again
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodPost, "/api/v1/auth/logout-all", sessions[0].token))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetCSRFToken_ReturnsAndSetsSessionToken(t *testing.T) {
+	db, sessionService, router := setupSessionHandlerTest(t)
+	_, sessions := createUserSessions(t, db, sessionService, "user@example.com", "Laptop", "Phone")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, accountRequest(http.MethodGet, "/api/v1/auth/csrf", sessions[0].token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, sessionService.CSRFToken(sessions[0].id), body["csrf_token"])
+	assert.NotEqual(t, sessionService.CSRFToken(sessions[1].id), body["csrf_token"], "each session has its own token")
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, middleware.CSRFCookie, cookies[0].Name)
+	assert.Equal(t, body["csrf_token"], cookies[0].Value)
+}
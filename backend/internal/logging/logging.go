@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"todo-app/internal/config"
+)
+
+// New creates a structured logger writing records at or above level to w,
+// as JSON objects or as key=value text depending on format
+func New(w io.Writer, level slog.Leveler, format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if format == config.LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+// Setup installs the logger configured by cfg as the default. Output from the
+// standard log package is routed through it as well, at info level.
+func Setup(cfg config.LoggingConfig) *slog.Logger {
+	logger := New(os.Stderr, cfg.Level, cfg.Format)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx that carries requestID, so code below
+// the HTTP layer can include it in log records
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "-" when there is none
+func RequestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return "-"
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/config"
+)
+
+func TestNew_JSONFormatWritesStructuredRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo, config.LogFormatJSON)
+
+	logger.Debug("hidden")
+	logger.Info("request", "method", "GET", "status", 200)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "request", record["msg"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, float64(200), record["status"])
+}
+
+func TestNew_TextFormatIsDefault(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn, "")
+
+	logger.Info("hidden")
+	logger.Warn("slow request", "path", "/api/v1/tasks")
+
+	assert.Contains(t, buf.String(), `level=WARN msg="slow request" path=/api/v1/tasks`)
+	assert.NotContains(t, buf.String(), "hidden")
+}
+
+func TestRequestID_CarriedByContext(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-123")
+
+	assert.Equal(t, "req-123", RequestID(ctx))
+	assert.Equal(t, "-", RequestID(context.Background()))
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/oauth2"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+)
+
+// githubAPIBaseURL is the root of the GitHub REST API
+const githubAPIBaseURL = "https://api.github.com"
+
+// GitHubOAuthService handles GitHub OAuth authentication and implements OAuthProvider
+type GitHubOAuthService struct {
+	config     *oauth2.Config
+	apiBaseURL string
+}
+
+// NewGitHubOAuthService creates a new GitHub OAuth service with the GitHub
+// credentials in cfg
+func NewGitHubOAuthService(cfg config.OAuthConfig) *GitHubOAuthService {
+	return &GitHubOAuthService{
+		config:     cfg.GitHubOAuth2(),
+		apiBaseURL: githubAPIBaseURL,
+	}
+}
+
+// Name returns the provider identifier for GitHub
+func (s *GitHubOAuthService) Name() string {
+	return dtos.OAuthProviderGitHub
+}
+
+// AuthURL creates the GitHub consent URL with state token for CSRF protection
+func (s *GitHubOAuthService) AuthURL(state string) string {
+	return s.config.AuthCodeURL(state)
+}
+
+// Exchange exchanges an authorization code for a GitHub token
+func (s *GitHubOAuthService) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	token, err := s.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	return token, nil
+}
+
+// FetchUserInfo loads the GitHub account behind the token. The profile email is
+// often private, so the primary verified address from /user/emails is used instead.
+func (s *GitHubOAuthService) FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error) {
+	client := s.config.Client(ctx, token)
+
+	var githubUser struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := s.getJSON(client, "/user", &githubUser); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	if githubUser.ID == 0 {
+		return nil, fmt.Errorf("failed to get user info: missing user ID")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := s.getJSON(client, "/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("failed to get user emails: %w", err)
+	}
+
+	info := &OAuthUserInfo{
+		Provider:   dtos.OAuthProviderGitHub,
+		ExternalID: strconv.FormatInt(githubUser.ID, 10),
+		Name:       githubUser.Name,
+	}
+	if info.Name == "" {
+		info.Name = githubUser.Login
+	}
+
+	for _, email := range emails {
+		if email.Primary {
+			info.Email = email.Email
+			info.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// RevokeToken revokes a GitHub token. GitHub authenticates the request with
+// the app's client credentials rather than the token itself.
+func (s *GitHubOAuthService) RevokeToken(ctx context.Context, token string) error {
+	body, err := json.Marshal(map[string]string{"access_token": token})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.apiBaseURL+"/applications/"+s.config.ClientID+"/token", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.config.ClientID, s.config.ClientSecret)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to revoke token: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// getJSON performs an authenticated GET against the GitHub API and decodes the response
+func (s *GitHubOAuthService) getJSON(client *http.Client, path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, s.apiBaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"todo-app/internal/config"
+)
+
+func newGitHubTestService(t *testing.T, emailsJSON string) *GitHubOAuthService {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"id": 583231, "login": "octocat", "name": ""}`))
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(emailsJSON))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	service := NewGitHubOAuthService(config.OAuthConfig{})
+	service.apiBaseURL = server.URL
+	return service
+}
+
+func TestGitHubFetchUserInfo_UsesPrimaryEmail(t *testing.T) {
+	service := newGitHubTestService(t, `[
+		{"email": "secondary@example.com", "primary": false, "verified": true},
+		{"email": "octocat@example.com", "primary": true, "verified": true}
+	]`)
+
+	info, err := service.FetchUserInfo(context.Background(), &oauth2.Token{AccessToken: "test-token"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "github", info.Provider)
+	assert.Equal(t, "583231", info.ExternalID)
+	assert.Equal(t, "octocat@example.com", info.Email)
+	assert.True(t, info.EmailVerified)
+	assert.Equal(t, "octocat", info.Name, "falls back to the login when the profile name is empty")
+}
+
+func TestGitHubFetchUserInfo_UnverifiedPrimaryEmail(t *testing.T) {
+	service := newGitHubTestService(t, `[{"email": "octocat@example.com", "primary": true, "verified": false}]`)
+
+	info, err := service.FetchUserInfo(context.Background(), &oauth2.Token{AccessToken: "test-token"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "octocat@example.com", info.Email)
+	assert.False(t, info.EmailVerified)
+}
+
+func TestGitHubFetchUserInfo_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewGitHubOAuthService(config.OAuthConfig{})
+	service.apiBaseURL = server.URL
+
+	_, err := service.FetchUserInfo(context.Background(), &oauth2.Token{AccessToken: "test-token"})
+	assert.Error(t, err)
+}
+
+func TestOAuthProviders_AuthURLCarriesState(t *testing.T) {
+	cfg := config.OAuthConfig{GoogleClientID: "google-client", GitHubClientID: "github-client"}
+
+	providers := []OAuthProvider{NewGoogleOAuthService(nil, cfg), NewGitHubOAuthService(cfg)}
+	for _, provider := range providers {
+		url := provider.AuthURL("state-123")
+		assert.Contains(t, url, "state=state-123", provider.Name())
+	}
+	assert.Contains(t, providers[1].AuthURL("s"), "github.com/login/oauth/authorize")
+}
+
+func TestGitHubRevokeToken_UsesClientCredentials(t *testing.T) {
+	var revoked string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/applications/github-client/token", r.URL.Path)
+		clientID, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "github-client", clientID)
+		assert.Equal(t, "github-secret", secret)
+
+		var body struct {
+			AccessToken string `json:"access_token"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		revoked = body.AccessToken
+		if revoked == "unknown-token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewGitHubOAuthService(config.OAuthConfig{GitHubClientID: "github-client", GitHubClientSecret: "github-secret"})
+	service.apiBaseURL = server.URL
+
+	require.NoError(t, service.RevokeToken(context.Background(), "gho_token"))
+	assert.Equal(t, "gho_token", revoked)
+
+	assert.Error(t, service.RevokeToken(context.Background(), "unknown-token"))
+}
@@ -6,8 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"domain/auth/valueobjects"
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 	"todo-app/internal/config"
@@ -20,38 +25,69 @@ type GoogleUserInfo struct {
 	Email         string
 	EmailVerified bool
 	Name          string
+
+	// ProviderSessionID is the "sid" claim from the ID token, when the provider issues one
+	ProviderSessionID string
+
+	// AccessToken, RefreshToken and TokenExpiry are the tokens the code was exchanged for
+	AccessToken  string
+	RefreshToken string
+	TokenExpiry  time.Time
 }
 
-// GoogleOAuthService handles Google OAuth authentication
+// GoogleOAuthService handles Google OAuth authentication and implements OAuthProvider
 type GoogleOAuthService struct {
-	config *oauth2.Config
-	db     *gorm.DB
+	config      *oauth2.Config
+	db          *gorm.DB
+	userInfoURL string
+	revokeURL   string
 }
 
-// NewGoogleOAuthService creates a new Google OAuth service
-func NewGoogleOAuthService(db *gorm.DB) *GoogleOAuthService {
+// NewGoogleOAuthService creates a new Google OAuth service with the Google
+// credentials in cfg
+func NewGoogleOAuthService(db *gorm.DB, cfg config.OAuthConfig) *GoogleOAuthService {
 	return &GoogleOAuthService{
-		config: config.GetGoogleOAuthConfig(),
-		db:     db,
+		config:      cfg.GoogleOAuth2(),
+		db:          db,
+		userInfoURL: googleUserInfoURL,
+		revokeURL:   googleRevokeURL,
 	}
 }
 
+// googleUserInfoURL is Google's OAuth2 userinfo endpoint
+const googleUserInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
+
+// googleRevokeURL is Google's OAuth2 token revocation endpoint
+const googleRevokeURL = "https://oauth2.googleapis.com/revoke"
+
+// Name returns the provider identifier for Google
+func (s *GoogleOAuthService) Name() string {
+	return dtos.OAuthProviderGoogle
+}
+
+// AuthURL creates the Google consent URL with state token for CSRF protection
+func (s *GoogleOAuthService) AuthURL(state string) string {
+	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+}
+
 // GenerateAuthURL creates OAuth URL with state token for CSRF protection
 func (s *GoogleOAuthService) GenerateAuthURL(state string) string {
-	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	return s.AuthURL(state)
 }
 
-// ExchangeCode exchanges authorization code for user info
-func (s *GoogleOAuthService) ExchangeCode(ctx context.Context, code string) (*GoogleUserInfo, error) {
-	// Exchange code for token
+// Exchange exchanges an authorization code for a Google token
+func (s *GoogleOAuthService) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
 	token, err := s.config.Exchange(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange code: %w", err)
 	}
+	return token, nil
+}
 
-	// Get user info from Google
+// FetchUserInfo loads the Google account behind the token from the userinfo endpoint
+func (s *GoogleOAuthService) FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error) {
 	client := s.config.Client(ctx, token)
-	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	resp, err := client.Get(s.userInfoURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
@@ -78,14 +114,80 @@ func (s *GoogleOAuthService) ExchangeCode(ctx context.Context, code string) (*Go
 		return nil, fmt.Errorf("failed to parse user info: %w", err)
 	}
 
+	return &OAuthUserInfo{
+		Provider:          dtos.OAuthProviderGoogle,
+		ExternalID:        googleUser.ID,
+		Email:             googleUser.Email,
+		EmailVerified:     googleUser.VerifiedEmail,
+		Name:              googleUser.Name,
+		ProviderSessionID: extractSessionID(token),
+	}, nil
+}
+
+// RevokeToken revokes an access or refresh token with Google; revoking a
+// refresh token also revokes the access tokens issued from it
+func (s *GoogleOAuthService) RevokeToken(ctx context.Context, token string) error {
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.revokeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to revoke token: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ExchangeCode exchanges authorization code for user info
+func (s *GoogleOAuthService) ExchangeCode(ctx context.Context, code string) (*GoogleUserInfo, error) {
+	token, err := s.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.FetchUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GoogleUserInfo{
-		GoogleUserID:  googleUser.ID,
-		Email:         googleUser.Email,
-		EmailVerified: googleUser.VerifiedEmail,
-		Name:          googleUser.Name,
+		GoogleUserID:      info.ExternalID,
+		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
+		Name:              info.Name,
+		ProviderSessionID: info.ProviderSessionID,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+		TokenExpiry:       token.Expiry,
 	}, nil
 }
 
+// extractSessionID reads the "sid" claim from the ID token returned by the token endpoint.
+// The token came directly from the provider over TLS, so its signature is not re-verified.
+func extractSessionID(token *oauth2.Token) string {
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return ""
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, claims); err != nil {
+		return ""
+	}
+
+	sid, _ := claims["sid"].(string)
+	return sid
+}
+
 // CreateUserFromGoogle creates a new user and GoogleIdentity from Google OAuth info
 func (s *GoogleOAuthService) CreateUserFromGoogle(info *GoogleUserInfo) (*dtos.User, error) {
 	// Validate email is verified
@@ -108,6 +210,10 @@ func (s *GoogleOAuthService) CreateUserFromGoogle(info *GoogleUserInfo) (*dtos.U
 		AuthMethod: "google",
 		IsActive:   true,
 	}
+	if err := user.LinkOAuthAccount(dtos.OAuthProviderGoogle, info.GoogleUserID, time.Now()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	if err := tx.Create(&user).Error; err != nil {
 		tx.Rollback()
@@ -127,6 +233,12 @@ func (s *GoogleOAuthService) CreateUserFromGoogle(info *GoogleUserInfo) (*dtos.U
 		return nil, fmt.Errorf("failed to create Google identity: %w", err)
 	}
 
+	identity := dtos.OAuthIdentity{UserID: user.ID, Provider: dtos.OAuthProviderGoogle, ExternalID: info.GoogleUserID}
+	if err := tx.Create(&identity).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create OAuth identity: %w", err)
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/config"
+)
+
+func TestGoogleRevokeToken_PostsTokenToRevocationEndpoint(t *testing.T) {
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseForm())
+		token := r.PostForm.Get("token")
+		if token == "already-revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		revoked = append(revoked, token)
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewGoogleOAuthService(nil, config.OAuthConfig{})
+	service.revokeURL = server.URL
+
+	require.NoError(t, service.RevokeToken(context.Background(), "refresh+token/1"))
+	assert.Equal(t, []string{"refresh+token/1"}, revoked)
+
+	assert.Error(t, service.RevokeToken(context.Background(), "already-revoked"))
+}
@@ -1,35 +1,127 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"domain/health/entities"
+	"todo-app/internal/config"
 	"todo-app/internal/storage"
+	"todo-app/internal/version"
 )
 
+// defaultDatabasePingTimeout bounds how long the database check may take
+const defaultDatabasePingTimeout = 2 * time.Second
+
+// defaultOAuthProbeTimeout bounds how long the OAuth provider probe may take
+const defaultOAuthProbeTimeout = 3 * time.Second
+
+// cachedDatabaseCheck is a database check result reused until it expires
+type cachedDatabaseCheck struct {
+	status    entities.DatabaseStatus
+	check     entities.CheckResult
+	checkedAt time.Time
+}
+
 // HealthService provides health checking functionality
 type HealthService struct {
-	startTime time.Time
-	version   string
+	startTime     time.Time
+	version       version.Info
+	dbPingTimeout time.Duration
+
+	// Database check caching; dbMu is held while refreshing so only one
+	// goroutine pings the database at a time
+	dbCacheTTL time.Duration
+	dbMu       sync.Mutex
+	dbCached   *cachedDatabaseCheck
+	pingDB     func() (entities.DatabaseStatus, error)
+
+	// OAuth provider reachability caching for the detailed view, so frequent
+	// polling does not turn into requests to the provider
+	oauthCheckInterval time.Duration
+	oauthMu            sync.Mutex
+	oauthCached        *entities.CheckResult
+	oauthCheckedAt     time.Time
+	oauthDiscoveryURL  string
+	httpClient         *http.Client
+	probeOAuth         func() error
+
+	// oauthConfigured reports whether Google sign-in credentials are set
+	oauthConfigured bool
+
+	// dataPath is the SQLite database file whose directory must be writable
+	dataPath string
 }
 
-// NewHealthService creates a new health service instance
-func NewHealthService() *HealthService {
-	return &HealthService{
-		startTime: time.Now(),
-		version:   "1.0.0", // This could be injected from build info
+// NewHealthService creates a new health service instance checking the OAuth
+// provider and database described by oauth and database
+func NewHealthService(health config.HealthConfig, oauth config.OAuthConfig, database config.DatabaseConfig) *HealthService {
+	hs := &HealthService{
+		startTime:     time.Now(),
+		version:       version.Get(),
+		dbPingTimeout: defaultDatabasePingTimeout,
+		dbCacheTTL:    health.CacheTTL,
+
+		oauthCheckInterval: health.OAuthCheckInterval,
+		oauthDiscoveryURL:  oauth.DiscoveryURL(),
+		httpClient:         &http.Client{Timeout: defaultOAuthProbeTimeout},
+
+		oauthConfigured: oauth.GoogleClientID != "" && oauth.GoogleClientSecret != "",
+		dataPath:        database.DSN,
 	}
+	hs.pingDB = hs.pingDatabase
+	hs.probeOAuth = hs.probeOAuthProvider
+	return hs
 }
 
-// GetHealthStatus performs comprehensive health checks and returns the current status
+// GetHealthStatus performs comprehensive health checks and returns the current status,
+// reusing a recent database check result when one is cached
 func (hs *HealthService) GetHealthStatus() (*entities.HealthResponse, error) {
-	// Check database connectivity
-	dbStatus := hs.checkDatabaseConnectivity()
+	return hs.getHealthStatus(false)
+}
 
-	// Determine overall health based on database status
-	overallHealth := entities.DetermineOverallHealth(dbStatus)
+// GetFreshHealthStatus performs health checks bypassing the database check cache
+func (hs *HealthService) GetFreshHealthStatus() (*entities.HealthResponse, error) {
+	return hs.getHealthStatus(true)
+}
+
+// GetDetailedHealthStatus performs the health checks plus a probe of the OAuth
+// provider, reported in the response's oauth field. The provider being
+// unreachable only degrades health, and the probe result is cached for
+// HEALTH_OAUTH_CHECK_INTERVAL even when fresh bypasses the database cache.
+func (hs *HealthService) GetDetailedHealthStatus(fresh bool) (*entities.HealthResponse, error) {
+	return hs.buildHealthStatus(fresh, true)
+}
+
+// getHealthStatus builds the health response, optionally forcing a new database check
+func (hs *HealthService) getHealthStatus(fresh bool) (*entities.HealthResponse, error) {
+	return hs.buildHealthStatus(fresh, false)
+}
+
+// buildHealthStatus runs the dependency checks, including the OAuth provider
+// probe when detailed is set, and builds the health response
+func (hs *HealthService) buildHealthStatus(fresh, detailed bool) (*entities.HealthResponse, error) {
+	// Run dependency checks
+	dbStatus, dbCheck := hs.cachedDatabaseCheck(fresh)
+	checks := []entities.CheckResult{
+		dbCheck,
+		hs.runCheck(entities.CheckNameOAuthProvider, false, hs.checkOAuthProvider),
+		hs.runCheck(entities.CheckNameDisk, false, hs.checkDisk),
+	}
+	if detailed {
+		checks = append(checks, hs.cachedOAuthReachabilityCheck())
+	}
+
+	// Critical check failures make the service unhealthy, others degrade it
+	overallHealth := entities.DetermineOverallHealthFromChecks(checks)
 
 	// Calculate uptime
 	uptime := int64(time.Since(hs.startTime).Seconds())
@@ -38,9 +130,18 @@ func (hs *HealthService) GetHealthStatus() (*entities.HealthResponse, error) {
 	response := entities.NewHealthResponse(
 		overallHealth,
 		dbStatus,
-		hs.version,
+		hs.version.Version,
 		uptime,
 	)
+	response.Commit = hs.version.Commit
+	response.BuildDate = hs.version.BuildDate
+	response.Checks = checks
+	if detailed {
+		response.OAuth = entities.OAuthReachable
+		if !checks[len(checks)-1].Passed() {
+			response.OAuth = entities.OAuthUnreachable
+		}
+	}
 
 	// Validate response before returning
 	if err := response.Validate(); err != nil {
@@ -51,52 +152,157 @@ func (hs *HealthService) GetHealthStatus() (*entities.HealthResponse, error) {
 	return response, nil
 }
 
+// cachedDatabaseCheck returns the cached database check if it is within the TTL,
+// otherwise runs a new check and caches it
+func (hs *HealthService) cachedDatabaseCheck(fresh bool) (entities.DatabaseStatus, entities.CheckResult) {
+	hs.dbMu.Lock()
+	defer hs.dbMu.Unlock()
+
+	if !fresh && hs.dbCached != nil && time.Since(hs.dbCached.checkedAt) < hs.dbCacheTTL {
+		return hs.dbCached.status, hs.dbCached.check
+	}
+
+	status, check := hs.runDatabaseCheck()
+	hs.dbCached = &cachedDatabaseCheck{
+		status:    status,
+		check:     check,
+		checkedAt: time.Now(),
+	}
+
+	return status, check
+}
+
+// cachedOAuthReachabilityCheck returns the cached OAuth provider probe if it
+// is within the check interval, otherwise probes the provider and caches it
+func (hs *HealthService) cachedOAuthReachabilityCheck() entities.CheckResult {
+	hs.oauthMu.Lock()
+	defer hs.oauthMu.Unlock()
+
+	if hs.oauthCached != nil && time.Since(hs.oauthCheckedAt) < hs.oauthCheckInterval {
+		return *hs.oauthCached
+	}
+
+	check := hs.runCheck(entities.CheckNameOAuthReachability, false, hs.probeOAuth)
+	hs.oauthCached = &check
+	hs.oauthCheckedAt = time.Now()
+
+	return check
+}
+
+// runDatabaseCheck checks database connectivity and reports it as a critical check
+func (hs *HealthService) runDatabaseCheck() (entities.DatabaseStatus, entities.CheckResult) {
+	start := time.Now()
+	status, err := hs.pingDB()
+
+	check := entities.CheckResult{
+		Name:      entities.CheckNameDatabase,
+		Status:    entities.HealthStatusHealthy,
+		Critical:  true,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Status = entities.HealthStatusUnhealthy
+		check.Error = err.Error()
+	}
+
+	return status, check
+}
+
+// runCheck times a dependency check and converts its outcome into a CheckResult
+func (hs *HealthService) runCheck(name string, critical bool, check func() error) entities.CheckResult {
+	start := time.Now()
+	err := check()
+
+	result := entities.CheckResult{
+		Name:      name,
+		Status:    entities.HealthStatusHealthy,
+		Critical:  critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.Printf("Health check %s failed: %v", name, err)
+		result.Status = entities.HealthStatusUnhealthy
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
 // checkDatabaseConnectivity tests the database connection and returns status
 func (hs *HealthService) checkDatabaseConnectivity() entities.DatabaseStatus {
+	status, _ := hs.pingDatabase()
+	return status
+}
+
+// pingDatabase pings the database, giving up after dbPingTimeout so a hung
+// connection cannot block the health endpoint
+func (hs *HealthService) pingDatabase() (entities.DatabaseStatus, error) {
 	// Get the database instance
 	db := storage.GetDB()
 	if db == nil {
 		log.Printf("Database instance is nil")
-		return entities.DatabaseStatusDisconnected
+		return entities.DatabaseStatusDisconnected, errors.New("database is not initialized")
 	}
 
 	// Get underlying sql.DB to test connection
 	sqlDB, err := db.DB()
 	if err != nil {
 		log.Printf("Failed to get underlying database connection: %v", err)
-		return entities.DatabaseStatusError
+		return entities.DatabaseStatusError, err
 	}
 
 	// Test connection with ping
-	if err := sqlDB.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), hs.dbPingTimeout)
+	defer cancel()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
 		log.Printf("Database ping failed: %v", err)
-		return entities.DatabaseStatusDisconnected
+		return entities.DatabaseStatusDisconnected, err
 	}
 
-	// Additional checks for database health
-	if err := hs.performDatabaseHealthChecks(sqlDB); err != nil {
-		log.Printf("Database health check failed: %v", err)
-		return entities.DatabaseStatusError
-	}
+	return entities.DatabaseStatusConnected, nil
+}
 
-	return entities.DatabaseStatusConnected
+// checkOAuthProvider verifies that OAuth provider credentials are configured
+func (hs *HealthService) checkOAuthProvider() error {
+	if !hs.oauthConfigured {
+		return errors.New("oauth provider credentials are not configured")
+	}
+	return nil
 }
 
-// performDatabaseHealthChecks performs additional database health validations
-func (hs *HealthService) performDatabaseHealthChecks(sqlDB interface{}) error {
-	// For SQLite, we can check if we can perform a simple query
-	// This ensures the database is not only connected but also responsive
+// probeOAuthProvider fetches the provider's OpenID discovery document, which
+// is served from the same infrastructure as its sign-in endpoints
+func (hs *HealthService) probeOAuthProvider() error {
+	resp, err := hs.httpClient.Get(hs.oauthDiscoveryURL)
+	if err != nil {
+		return fmt.Errorf("oauth provider is unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
 
-	// Note: In a real implementation, you might want to:
-	// - Check disk space for SQLite files
-	// - Verify database schema integrity
-	// - Test transaction capabilities
-	// - Check for any locks or connection pool issues
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oauth provider discovery document returned status %d", resp.StatusCode)
+	}
+	return nil
+}
 
-	// For now, we'll do a minimal check since the Ping() already validates basic connectivity
-	// and we don't want to impact performance significantly
+// checkDisk verifies that the database directory is writable; it only applies
+// to SQLite, since other databases do not store data on the local disk
+func (hs *HealthService) checkDisk() error {
+	if db := storage.GetDB(); db != nil && db.Dialector.Name() != config.DatabaseDriverSQLite {
+		return nil
+	}
 
-	return nil
+	dir := filepath.Dir(hs.dataPath)
+
+	probe, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	name := probe.Name()
+	probe.Close()
+	return os.Remove(name)
 }
 
 // GetDatabaseStatus returns just the database connectivity status
@@ -118,12 +324,18 @@ func (hs *HealthService) GetUptime() int64 {
 
 // GetVersion returns the service version
 func (hs *HealthService) GetVersion() string {
-	return hs.version
+	return hs.version.Version
+}
+
+// SetVersion overrides the reported build, e.g. with the linked values from
+// the version package
+func (hs *HealthService) SetVersion(info version.Info) {
+	hs.version = info
 }
 
-// SetVersion allows updating the service version (useful for testing or build info injection)
-func (hs *HealthService) SetVersion(version string) {
-	hs.version = version
+// BuildInfo returns the version, commit and build date of the service
+func (hs *HealthService) BuildInfo() version.Info {
+	return hs.version
 }
 
 // ValidateHealthResponse validates a health response structure
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"domain/health/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/storage"
+	"todo-app/internal/version"
+)
+
+// healthTestConfig is the configuration tests build their HealthService from
+type healthTestConfig struct {
+	health   config.HealthConfig
+	oauth    config.OAuthConfig
+	database config.DatabaseConfig
+}
+
+func (c *healthTestConfig) newService() *HealthService {
+	return NewHealthService(c.health, c.oauth, c.database)
+}
+
+func setupHealthServiceTest(t *testing.T) (*gorm.DB, *healthTestConfig) {
+	cfg := &healthTestConfig{
+		health: config.HealthConfig{
+			CacheTTL:           config.DefaultHealthCacheTTL,
+			OAuthCheckInterval: config.DefaultHealthOAuthCheckInterval,
+		},
+		oauth: config.OAuthConfig{
+			GoogleClientID:     "test-client-id",
+			GoogleClientSecret: "test-client-secret",
+			OIDCIssuer:         config.DefaultOIDCIssuer,
+		},
+		database: config.DatabaseConfig{
+			Driver: config.DatabaseDriverSQLite,
+			DSN:    filepath.Join(t.TempDir(), "todo.db"),
+		},
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	previous := storage.DB
+	storage.DB = db
+	t.Cleanup(func() { storage.DB = previous })
+
+	return db, cfg
+}
+
+func findCheck(t *testing.T, checks []entities.CheckResult, name string) entities.CheckResult {
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q not found", name)
+	return entities.CheckResult{}
+}
+
+func TestGetHealthStatus_AllChecksPass(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+
+	response, err := cfg.newService().GetHealthStatus()
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthStatusHealthy, response.Status)
+	assert.Equal(t, entities.DatabaseStatusConnected, response.Database)
+	require.Len(t, response.Checks, 3)
+
+	for _, name := range []string{entities.CheckNameDatabase, entities.CheckNameOAuthProvider, entities.CheckNameDisk} {
+		check := findCheck(t, response.Checks, name)
+		assert.Equal(t, entities.HealthStatusHealthy, check.Status, name)
+		assert.GreaterOrEqual(t, check.LatencyMs, int64(0), name)
+		assert.Empty(t, check.Error, name)
+	}
+	assert.True(t, findCheck(t, response.Checks, entities.CheckNameDatabase).Critical)
+}
+
+func TestGetHealthStatus_DatabaseDownIsUnhealthy(t *testing.T) {
+	db, cfg := setupHealthServiceTest(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	response, err := cfg.newService().GetHealthStatus()
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthStatusUnhealthy, response.Status)
+	assert.Equal(t, entities.DatabaseStatusDisconnected, response.Database)
+
+	check := findCheck(t, response.Checks, entities.CheckNameDatabase)
+	assert.Equal(t, entities.HealthStatusUnhealthy, check.Status)
+	assert.NotEmpty(t, check.Error)
+}
+
+func TestGetHealthStatus_NonCriticalFailureIsDegraded(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	cfg.oauth.GoogleClientID = ""
+
+	response, err := cfg.newService().GetHealthStatus()
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthStatusDegraded, response.Status)
+	assert.Equal(t, entities.DatabaseStatusConnected, response.Database)
+
+	check := findCheck(t, response.Checks, entities.CheckNameOAuthProvider)
+	assert.Equal(t, entities.HealthStatusUnhealthy, check.Status)
+	assert.False(t, check.Critical)
+	assert.NotEmpty(t, check.Error)
+}
+
+// countPings wraps the service's database ping with a counter
+func countPings(hs *HealthService) *int32 {
+	var pings int32
+	ping := hs.pingDB
+	hs.pingDB = func() (entities.DatabaseStatus, error) {
+		atomic.AddInt32(&pings, 1)
+		// Keep the ping slow enough that concurrent callers overlap
+		time.Sleep(10 * time.Millisecond)
+		return ping()
+	}
+	return &pings
+}
+
+func TestGetHealthStatus_ConcurrentRequestsShareCachedPing(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	cfg.health.CacheTTL = time.Minute
+
+	hs := cfg.newService()
+	pings := countPings(hs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := hs.GetHealthStatus()
+			assert.NoError(t, err)
+			assert.Equal(t, entities.DatabaseStatusConnected, response.Database)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(pings), int32(1))
+}
+
+func TestGetHealthStatus_RefreshesAfterTTL(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	cfg.health.CacheTTL = 20 * time.Millisecond
+
+	hs := cfg.newService()
+	pings := countPings(hs)
+
+	_, err := hs.GetHealthStatus()
+	require.NoError(t, err)
+	_, err = hs.GetHealthStatus()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(pings))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = hs.GetHealthStatus()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(pings))
+}
+
+func TestGetFreshHealthStatus_BypassesCache(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	cfg.health.CacheTTL = time.Minute
+
+	hs := cfg.newService()
+	pings := countPings(hs)
+
+	first, err := hs.GetHealthStatus()
+	require.NoError(t, err)
+	_, err = hs.GetFreshHealthStatus()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(pings))
+
+	// The timestamp is generated per request even when the check is cached
+	time.Sleep(1100 * time.Millisecond)
+	cached, err := hs.GetHealthStatus()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(pings))
+	assert.NotEqual(t, first.Timestamp, cached.Timestamp)
+}
+
+// startDiscoveryServer serves an OpenID discovery document with the given
+// status at the configured OIDC issuer, counting requests
+func startDiscoveryServer(t *testing.T, cfg *healthTestConfig, status int) *int32 {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "/.well-known/openid-configuration", r.URL.Path)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"issuer":"test"}`))
+	}))
+	t.Cleanup(server.Close)
+	cfg.oauth.OIDCIssuer = server.URL
+	return &requests
+}
+
+func TestGetDetailedHealthStatus_OAuthReachable(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	startDiscoveryServer(t, cfg, http.StatusOK)
+
+	response, err := cfg.newService().GetDetailedHealthStatus(false)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthStatusHealthy, response.Status)
+	assert.Equal(t, entities.OAuthReachable, response.OAuth)
+	require.Len(t, response.Checks, 4)
+	check := findCheck(t, response.Checks, entities.CheckNameOAuthReachability)
+	assert.Equal(t, entities.HealthStatusHealthy, check.Status)
+	assert.False(t, check.Critical)
+}
+
+func TestGetDetailedHealthStatus_OAuthUnreachableIsDegraded(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	startDiscoveryServer(t, cfg, http.StatusServiceUnavailable)
+
+	response, err := cfg.newService().GetDetailedHealthStatus(false)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthStatusDegraded, response.Status)
+	assert.Equal(t, entities.DatabaseStatusConnected, response.Database)
+	assert.Equal(t, entities.OAuthUnreachable, response.OAuth)
+	check := findCheck(t, response.Checks, entities.CheckNameOAuthReachability)
+	assert.Equal(t, entities.HealthStatusUnhealthy, check.Status)
+	assert.Contains(t, check.Error, "503")
+}
+
+func TestGetDetailedHealthStatus_ProbeTimesOut(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	cfg.oauth.OIDCIssuer = server.URL
+
+	hs := cfg.newService()
+	hs.httpClient.Timeout = 20 * time.Millisecond
+
+	response, err := hs.GetDetailedHealthStatus(false)
+	require.NoError(t, err)
+	assert.Equal(t, entities.OAuthUnreachable, response.OAuth)
+	assert.Equal(t, entities.HealthStatusDegraded, response.Status)
+}
+
+func TestGetDetailedHealthStatus_CachesProbe(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	requests := startDiscoveryServer(t, cfg, http.StatusOK)
+	cfg.health.OAuthCheckInterval = 20 * time.Millisecond
+
+	hs := cfg.newService()
+	for i := 0; i < 3; i++ {
+		_, err := hs.GetDetailedHealthStatus(true)
+		require.NoError(t, err)
+	}
+	// Not even fresh requests bypass the probe cache
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := hs.GetDetailedHealthStatus(false)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestGetHealthStatus_DoesNotProbeOAuthProvider(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+	requests := startDiscoveryServer(t, cfg, http.StatusOK)
+
+	response, err := cfg.newService().GetHealthStatus()
+	require.NoError(t, err)
+
+	assert.Empty(t, response.OAuth)
+	assert.Len(t, response.Checks, 3)
+	assert.Zero(t, atomic.LoadInt32(requests))
+}
+
+func TestDetermineOverallHealthFromChecks(t *testing.T) {
+	pass := func(name string, critical bool) entities.CheckResult {
+		return entities.CheckResult{Name: name, Status: entities.HealthStatusHealthy, Critical: critical}
+	}
+	fail := func(name string, critical bool) entities.CheckResult {
+		return entities.CheckResult{Name: name, Status: entities.HealthStatusUnhealthy, Critical: critical}
+	}
+
+	assert.Equal(t, entities.HealthStatusHealthy, entities.DetermineOverallHealthFromChecks([]entities.CheckResult{
+		pass("database", true), pass("disk", false),
+	}))
+	assert.Equal(t, entities.HealthStatusDegraded, entities.DetermineOverallHealthFromChecks([]entities.CheckResult{
+		pass("database", true), fail("disk", false),
+	}))
+	assert.Equal(t, entities.HealthStatusUnhealthy, entities.DetermineOverallHealthFromChecks([]entities.CheckResult{
+		fail("database", true), fail("disk", false),
+	}))
+}
+
+func TestGetHealthStatus_ReportsBuildInfo(t *testing.T) {
+	_, cfg := setupHealthServiceTest(t)
+
+	hs := cfg.newService()
+	hs.SetVersion(version.Info{Version: "v1.4.0", Commit: "0123456789abcdef", BuildDate: "2026-10-15T08:00:00Z"})
+
+	for _, getStatus := range []func() (*entities.HealthResponse, error){
+		hs.GetHealthStatus,
+		hs.GetFreshHealthStatus,
+	} {
+		response, err := getStatus()
+		require.NoError(t, err)
+		assert.Equal(t, "v1.4.0", response.Version)
+		assert.Equal(t, "0123456789abcdef", response.Commit)
+		assert.Equal(t, "2026-10-15T08:00:00Z", response.BuildDate)
+	}
+	assert.Equal(t, "v1.4.0", hs.GetVersion())
+	assert.Equal(t, "0123456789abcdef", hs.BuildInfo().Commit)
+}
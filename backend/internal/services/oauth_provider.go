@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuthUserInfo is the provider-neutral identity returned by an OAuthProvider
+type OAuthUserInfo struct {
+	// Provider is the OAuthProvider name, e.g. "google" or "github"
+	Provider string
+
+	// ExternalID is the account ID at the provider
+	ExternalID    string
+	Email         string
+	EmailVerified bool
+	Name          string
+
+	// ProviderSessionID is the "sid" claim from the ID token, when the provider issues one
+	ProviderSessionID string
+}
+
+// OAuthProvider is an OAuth 2.0 identity provider that users can sign in with
+type OAuthProvider interface {
+	// Name returns the provider identifier stored in users.oauth_provider
+	Name() string
+
+	// AuthURL returns the provider's consent page URL carrying the given state
+	AuthURL(state string) string
+
+	// Exchange trades an authorization code for a token
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+
+	// FetchUserInfo loads the signed-in account's identity using the token
+	FetchUserInfo(ctx context.Context, token *oauth2.Token) (*OAuthUserInfo, error)
+
+	// RevokeToken revokes a token the provider issued, e.g. when the account is unlinked
+	RevokeToken(ctx context.Context, token string) error
+}
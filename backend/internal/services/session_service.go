@@ -14,10 +14,11 @@ type SessionService struct {
 	jwtSecret string
 }
 
-// NewSessionService creates a new session service
-func NewSessionService() *SessionService {
+// NewSessionService creates a new session service signing with the JWT
+// secret in cfg
+func NewSessionService(cfg config.SessionConfig) *SessionService {
 	return &SessionService{
-		jwtSecret: config.GetJWTSecret(),
+		jwtSecret: cfg.JWTSecret,
 	}
 }
 
@@ -1,53 +1,110 @@
 package storage
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
 	"todo-app/internal/dtos"
 )
 
 var DB *gorm.DB
 
-// InitDatabase initializes the database connection and runs migrations
-func InitDatabase() error {
-	var err error
+// maxConnectRetryDelay caps the backoff between connection attempts
+const maxConnectRetryDelay = 30 * time.Second
 
-	// Use SQLite for development
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "todo.db"
+// InitDatabase initializes the database described by cfg and applies pending
+// migrations
+func InitDatabase(cfg config.DatabaseConfig) error {
+	if err := OpenDatabase(cfg); err != nil {
+		return err
 	}
 
-	// Configure GORM logger
-	gormLogger := logger.Default
-	if os.Getenv("ENV") == "production" {
-		gormLogger = logger.Default.LogMode(logger.Silent)
-	}
-
-	// Open database connection
-	DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{
-		Logger: gormLogger,
-	})
-
+	migrator, err := NewMigrator(DB)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return fmt.Errorf("failed to load migrations: %w", err)
 	}
 
-	// Run auto migrations
-	err = DB.AutoMigrate(&dtos.Task{})
+	applied, err := migrator.Up()
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
+	for _, migration := range applied {
+		log.Printf("Applied migration %03d_%s", migration.Version, migration.Name)
+	}
 
 	log.Println("Database initialized successfully")
 	return nil
 }
 
+// OpenDatabase opens the database described by cfg without touching the
+// schema, retrying with backoff while the database is unreachable
+func OpenDatabase(cfg config.DatabaseConfig) error {
+	db, err := openWithRetry(cfg, Open, time.Sleep)
+	if err != nil {
+		return err
+	}
+
+	DB = db
+	return nil
+}
+
+// openWithRetry calls open up to cfg.ConnectMaxAttempts times, doubling the
+// delay after each failure so a database that starts after the app is picked up
+func openWithRetry(cfg config.DatabaseConfig, open func(config.DatabaseConfig) (*gorm.DB, error), sleep func(time.Duration)) (*gorm.DB, error) {
+	delay := cfg.ConnectRetryDelay
+	for attempt := 1; ; attempt++ {
+		db, err := open(cfg)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= cfg.ConnectMaxAttempts {
+			return nil, fmt.Errorf("database unavailable after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("Database connection attempt %d/%d failed: %v; retrying in %s", attempt, cfg.ConnectMaxAttempts, err, delay)
+		sleep(delay)
+
+		delay *= 2
+		if delay > maxConnectRetryDelay {
+			delay = maxConnectRetryDelay
+		}
+	}
+}
+
+// Open connects to the database described by cfg and applies its pool settings
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(dialectorFor(cfg), &gorm.Config{
+		Logger: newDatabaseLogger(cfg.SlowQueryThreshold, cfg.Env),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	return db, nil
+}
+
+// dialectorFor returns the GORM dialector for the configured driver
+func dialectorFor(cfg config.DatabaseConfig) gorm.Dialector {
+	if cfg.Driver == config.DatabaseDriverPostgres {
+		return postgres.Open(cfg.DSN)
+	}
+	return sqlite.Open(cfg.DSN)
+}
+
 // CloseDatabase closes the database connection
 func CloseDatabase() error {
 	if DB == nil {
@@ -67,6 +124,20 @@ func GetDB() *gorm.DB {
 	return DB
 }
 
+// Stats returns connection pool statistics for the database instance,
+// or zero stats if it has not been opened
+func Stats() sql.DBStats {
+	if DB == nil {
+		return sql.DBStats{}
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
+
 // ResetDatabase drops all tables and recreates them (for testing)
 func ResetDatabase() error {
 	if DB == nil {
@@ -74,13 +145,13 @@ func ResetDatabase() error {
 	}
 
 	// Drop existing tables
-	err := DB.Migrator().DropTable(&dtos.Task{})
+	err := DB.Migrator().DropTable("task_tags", &dtos.TaskActivity{}, &dtos.Tag{}, &dtos.Task{})
 	if err != nil {
 		return fmt.Errorf("failed to drop tables: %w", err)
 	}
 
 	// Recreate tables
-	err = DB.AutoMigrate(&dtos.Task{})
+	err = DB.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{})
 	if err != nil {
 		return fmt.Errorf("failed to recreate tables: %w", err)
 	}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"todo-app/internal/config"
+)
+
+func TestOpen_AppliesPoolSettings(t *testing.T) {
+	db, err := Open(config.DatabaseConfig{
+		Driver:             config.DatabaseDriverSQLite,
+		DSN:                filepath.Join(t.TempDir(), "todo.db"),
+		MaxOpenConns:       7,
+		MaxIdleConns:       3,
+		ConnMaxLifetime:    time.Minute,
+		SlowQueryThreshold: config.DefaultDBSlowQueryThreshold,
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	assert.Equal(t, 7, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestStats_ReportsPoolOfOpenDatabase(t *testing.T) {
+	previous := DB
+	t.Cleanup(func() { DB = previous })
+
+	DB = nil
+	assert.Zero(t, Stats().MaxOpenConnections)
+
+	db, err := Open(config.DatabaseConfig{
+		Driver:       config.DatabaseDriverSQLite,
+		DSN:          filepath.Join(t.TempDir(), "todo.db"),
+		MaxOpenConns: 4,
+		MaxIdleConns: 2,
+	})
+	require.NoError(t, err)
+	DB = db
+	t.Cleanup(func() { CloseDatabase() })
+
+	require.NoError(t, DB.Exec("SELECT 1").Error)
+
+	stats := Stats()
+	assert.Equal(t, 4, stats.MaxOpenConnections)
+	assert.Equal(t, 1, stats.OpenConnections)
+}
+
+func TestInitDatabase_OpensAndMigrates(t *testing.T) {
+	previous := DB
+	t.Cleanup(func() { DB = previous })
+
+	err := InitDatabase(config.DatabaseConfig{
+		Driver:             config.DatabaseDriverSQLite,
+		DSN:                filepath.Join(t.TempDir(), "todo.db"),
+		MaxOpenConns:       4,
+		ConnectMaxAttempts: 1,
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := DB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	assert.Equal(t, 4, sqlDB.Stats().MaxOpenConnections)
+	assert.True(t, DB.Migrator().HasTable("users"))
+	assert.True(t, DB.Migrator().HasTable("tasks"))
+}
+
+func TestOpenDatabase_ReportsUnusableDatabase(t *testing.T) {
+	previous := DB
+	t.Cleanup(func() { DB = previous })
+
+	err := OpenDatabase(config.DatabaseConfig{
+		Driver:             config.DatabaseDriverSQLite,
+		DSN:                filepath.Join(t.TempDir(), "missing", "todo.db"),
+		ConnectMaxAttempts: 1,
+	})
+	assert.Error(t, err)
+	assert.Same(t, previous, DB)
+}
+
+func TestOpenWithRetry_BacksOffUntilDatabaseIsUp(t *testing.T) {
+	cfg := config.DatabaseConfig{ConnectMaxAttempts: 5, ConnectRetryDelay: 20 * time.Second}
+	db := &gorm.DB{}
+
+	calls := 0
+	open := func(config.DatabaseConfig) (*gorm.DB, error) {
+		calls++
+		if calls < 4 {
+			return nil, errors.New("connection refused")
+		}
+		return db, nil
+	}
+	var delays []time.Duration
+	sleep := func(d time.Duration) { delays = append(delays, d) }
+
+	got, err := openWithRetry(cfg, open, sleep)
+	require.NoError(t, err)
+	assert.Same(t, db, got)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{20 * time.Second, 30 * time.Second, 30 * time.Second}, delays)
+}
+
+func TestOpenWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	cfg := config.DatabaseConfig{ConnectMaxAttempts: 3, ConnectRetryDelay: time.Millisecond}
+	refused := errors.New("connection refused")
+
+	calls := 0
+	open := func(config.DatabaseConfig) (*gorm.DB, error) {
+		calls++
+		return nil, refused
+	}
+
+	_, err := openWithRetry(cfg, open, func(time.Duration) {})
+	assert.ErrorIs(t, err, refused)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Equal(t, 3, calls)
+}
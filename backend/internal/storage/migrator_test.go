@@ -0,0 +1,361 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"domain/auth/entities"
+	"domain/auth/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+	"todo-app/migrations"
+)
+
+// persistedModels are the models whose tables the migrations must create
+var persistedModels = []interface{}{
+	&dtos.User{},
+	&dtos.Task{},
+	&dtos.Tag{},
+	&entities.AuthenticationSession{},
+	&entities.OAuthState{},
+	&valueobjects.GoogleIdentity{},
+	&entities.LoginEvent{},
+	&entities.AuditLog{},
+	&entities.PendingAccountLink{},
+	&dtos.OAuthIdentity{},
+	&entities.PasswordResetToken{},
+	&dtos.TaskActivity{},
+	&dtos.Notification{},
+}
+
+func setupMigratorTest(t *testing.T) (*gorm.DB, *Migrator) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "todo.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	return db, migrator
+}
+
+func TestMigrator_UpCreatesEveryModelColumn(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.NotEmpty(t, applied)
+
+	assertModelColumnsExist(t, db)
+
+	// Running again is a no-op
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestMigrator_ModelsRoundTrip(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	_, err := migrator.Up()
+	require.NoError(t, err)
+
+	assertModelsRoundTrip(t, db)
+}
+
+func TestMigrations_DialectsStayInStep(t *testing.T) {
+	sqliteFS, err := migrations.ForDialect("sqlite")
+	require.NoError(t, err)
+	postgresFS, err := migrations.ForDialect("postgres")
+	require.NoError(t, err)
+
+	sqliteMigrations, err := LoadMigrations(sqliteFS)
+	require.NoError(t, err)
+	postgresMigrations, err := LoadMigrations(postgresFS)
+	require.NoError(t, err)
+
+	require.Equal(t, len(sqliteMigrations), len(postgresMigrations))
+	for i := range sqliteMigrations {
+		assert.Equal(t, sqliteMigrations[i].Version, postgresMigrations[i].Version)
+		assert.Equal(t, sqliteMigrations[i].Name, postgresMigrations[i].Name)
+	}
+}
+
+// assertModelColumnsExist checks that every persisted model field has a column
+func assertModelColumnsExist(t *testing.T, db *gorm.DB) {
+	for _, model := range persistedModels {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+
+		require.True(t, db.Migrator().HasTable(stmt.Schema.Table), "table %s", stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			assert.True(t, db.Migrator().HasColumn(model, field.DBName), "column %s.%s", stmt.Schema.Table, field.DBName)
+		}
+	}
+	assert.True(t, db.Migrator().HasTable("task_tags"))
+}
+
+// assertModelsRoundTrip writes one of each persisted model and reads it back
+func assertModelsRoundTrip(t *testing.T, db *gorm.DB) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	user := dtos.User{Email: "user@example.com", Name: "User", GoogleID: "g-1", OAuthProvider: "google", OAuthExternalID: "g-1", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+
+	due := now.Add(24 * time.Hour)
+	task := dtos.Task{
+		Title: "Write migrations", Description: "baseline", Priority: "high", Status: "pending",
+		DueDate: &due, UserID: user.ID, Tags: []dtos.Tag{{Name: "work", UserID: user.ID}},
+	}
+	require.NoError(t, db.Create(&task).Error)
+
+	session := entities.AuthenticationSession{
+		ID: "session-1", UserID: user.ID, SessionToken: "token-1",
+		SessionExpiresAt: now.Add(time.Hour), LastActivity: now, ProviderSessionID: "sid-1",
+	}
+	require.NoError(t, db.Create(&session).Error)
+
+	state := entities.OAuthState{
+		StateToken: "state-0123456789abcdef0123456789abcdef", PKCEVerifier: "verifier",
+		RedirectURI: "http://localhost:3000/dashboard", ExpiresAt: now.Add(5 * time.Minute),
+	}
+	require.NoError(t, db.Create(&state).Error)
+
+	identity := valueobjects.GoogleIdentity{UserID: user.ID, GoogleUserID: "g-1", Email: user.Email, EmailVerified: true}
+	require.NoError(t, db.Create(&identity).Error)
+
+	event := entities.LoginEvent{UserID: user.ID, SessionID: session.ID, IsOAuth: true, IPAddress: "192.0.2.1"}
+	require.NoError(t, db.Create(&event).Error)
+
+	auditLog := entities.NewAuditLog(entities.AuditEventLoginSucceeded, user.ID, "192.0.2.1", "test-agent", entities.AuditMetadata{"provider": "google"})
+	require.NoError(t, db.Create(auditLog).Error)
+
+	resetToken, _ := entities.NewPasswordResetToken(user.ID)
+	require.NoError(t, db.Create(resetToken).Error)
+
+	var loadedUser dtos.User
+	require.NoError(t, db.First(&loadedUser, user.ID).Error)
+	assert.Equal(t, "g-1", loadedUser.OAuthExternalID)
+	assert.Equal(t, "google", loadedUser.OAuthProvider)
+	assert.True(t, loadedUser.IsActive)
+
+	var loadedTask dtos.Task
+	require.NoError(t, db.Preload("Tags").First(&loadedTask, task.ID).Error)
+	assert.Equal(t, "Write migrations", loadedTask.Title)
+	assert.Equal(t, "high", loadedTask.Priority)
+	require.NotNil(t, loadedTask.DueDate)
+	assert.True(t, due.Equal(*loadedTask.DueDate))
+	require.Len(t, loadedTask.Tags, 1)
+	assert.Equal(t, "work", loadedTask.Tags[0].Name)
+
+	var loadedSession entities.AuthenticationSession
+	require.NoError(t, db.First(&loadedSession, "id = ?", session.ID).Error)
+	assert.Equal(t, "sid-1", loadedSession.ProviderSessionID)
+
+	var loadedState entities.OAuthState
+	require.NoError(t, db.First(&loadedState, "state_token = ?", state.StateToken).Error)
+	assert.Equal(t, "verifier", loadedState.PKCEVerifier)
+
+	var loadedIdentity valueobjects.GoogleIdentity
+	require.NoError(t, db.First(&loadedIdentity, identity.ID).Error)
+	assert.True(t, loadedIdentity.EmailVerified)
+
+	var loadedEvent entities.LoginEvent
+	require.NoError(t, db.First(&loadedEvent, event.ID).Error)
+	assert.True(t, loadedEvent.IsOAuth)
+
+	var loadedAuditLog entities.AuditLog
+	require.NoError(t, db.First(&loadedAuditLog, auditLog.ID).Error)
+	require.NotNil(t, loadedAuditLog.UserID)
+	assert.Equal(t, user.ID, *loadedAuditLog.UserID)
+	assert.Equal(t, "google", loadedAuditLog.Metadata["provider"])
+
+	var loadedResetToken entities.PasswordResetToken
+	require.NoError(t, db.First(&loadedResetToken, "token_hash = ?", resetToken.TokenHash).Error)
+	assert.Nil(t, loadedResetToken.UsedAt)
+
+	// Unique indexes are in place
+	assert.Error(t, db.Create(&dtos.User{Email: "user@example.com", Name: "Dup", PasswordHash: "hash"}).Error)
+}
+
+func TestMigrator_DownRollsBackInReverseOrder(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+
+	rolledBack, err := migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, applied[len(applied)-1].Version, rolledBack[0].Version)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	assert.NotNil(t, statuses[0].AppliedAt)
+
+	rolledBack, err = migrator.Down(len(applied))
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(applied)-1)
+	assert.False(t, db.Migrator().HasTable("users"))
+	assert.False(t, db.Migrator().HasTable("tasks"))
+
+	// Everything can be reapplied
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(statuses))
+}
+
+func TestMigrator_AdoptsAutoMigratedDatabase(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	require.NoError(t, db.AutoMigrate(persistedModels...))
+	// Databases created by AutoMigrate predate soft-deleted tasks, account deletion, email verification, admins, timezones, task limits, task versions and reminders
+	require.NoError(t, db.Migrator().DropIndex(&dtos.Task{}, "DeletedAt"))
+	require.NoError(t, db.Migrator().DropColumn(&dtos.Task{}, "DeletedAt"))
+	require.NoError(t, db.Migrator().DropColumn(&dtos.Task{}, "Version"))
+	require.NoError(t, db.Migrator().DropColumn(&dtos.Task{}, "RemindAt"))
+	require.NoError(t, db.Migrator().DropIndex(&dtos.User{}, "DeletionScheduledAt"))
+	require.NoError(t, db.Migrator().DropIndex(&dtos.User{}, "VerificationToken"))
+	require.NoError(t, db.Migrator().DropColumn(&dtos.User{}, "DeletionScheduledAt"))
+	for _, column := range []string{"EmailVerified", "VerificationToken", "VerificationTokenExpiresAt", "IsAdmin", "Timezone", "TaskLimit"} {
+		require.NoError(t, db.Migrator().DropColumn(&dtos.User{}, column))
+	}
+	require.NoError(t, db.Omit("DeletionScheduledAt", "EmailVerified", "VerificationToken", "VerificationTokenExpiresAt", "IsAdmin", "Timezone", "TaskLimit").
+		Create(&dtos.User{Email: "existing@example.com", Name: "Existing", PasswordHash: "hash"}).Error)
+
+	_, err := migrator.Up()
+	require.NoError(t, err)
+
+	var users []dtos.User
+	require.NoError(t, db.Find(&users).Error)
+	require.Len(t, users, 1)
+	// Accounts that predate verification are grandfathered in
+	assert.True(t, users[0].EmailVerified)
+}
+
+func TestMigrator_LowercasesExistingEmails(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	_, err := migrator.Up()
+	require.NoError(t, err)
+	// Roll back to just before 012_lowercase_user_emails
+	steps := 0
+	for _, m := range migrator.migrations {
+		if m.Version >= 12 {
+			steps++
+		}
+	}
+	_, err = migrator.Down(steps)
+	require.NoError(t, err)
+
+	// Rows written before emails were normalized; the raw insert skips the model hooks
+	for _, email := range []string{"Mixed@Example.com", "Dup@Example.com", "dup@example.com"} {
+		require.NoError(t, db.Exec("INSERT INTO users (email, name, password_hash, auth_method, created_at, updated_at) VALUES (?, 'User', 'hash', 'password', ?, ?)",
+			email, time.Now(), time.Now()).Error)
+	}
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	var emails []string
+	require.NoError(t, db.Model(&dtos.User{}).Order("id").Pluck("email", &emails).Error)
+	// Accounts differing only in case are left for a manual merge
+	assert.Equal(t, []string{"mixed@example.com", "Dup@Example.com", "dup@example.com"}, emails)
+}
+
+func TestMigrator_OAuthIdentityBelongsToOneUser(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	_, err := migrator.Up()
+	require.NoError(t, err)
+
+	insertUser := func(email, provider, externalID string) error {
+		return db.Exec("INSERT INTO users (email, name, auth_method, oauth_provider, oauth_external_id, created_at, updated_at) VALUES (?, 'User', 'oauth', ?, ?, ?, ?)",
+			email, provider, externalID, time.Now(), time.Now()).Error
+	}
+	require.NoError(t, insertUser("first@example.com", "github", "42"))
+	assert.Error(t, insertUser("second@example.com", "github", "42"))
+	require.NoError(t, insertUser("other@example.com", "google", "42"))
+
+	// Users without an OAuth account all store an empty ID
+	require.NoError(t, insertUser("signup@example.com", "", ""))
+	require.NoError(t, insertUser("another@example.com", "", ""))
+}
+
+func TestMigrator_BackfillsOAuthIdentities(t *testing.T) {
+	db, migrator := setupMigratorTest(t)
+	_, err := migrator.Up()
+	require.NoError(t, err)
+	// Roll back to just before 015_create_oauth_identities
+	steps := 0
+	for _, m := range migrator.migrations {
+		if m.Version >= 15 {
+			steps++
+		}
+	}
+	_, err = migrator.Down(steps)
+	require.NoError(t, err)
+
+	now := time.Now()
+	insertUser := func(email, googleID, provider, externalID string) uint {
+		require.NoError(t, db.Exec("INSERT INTO users (email, name, auth_method, google_id, oauth_provider, oauth_external_id, created_at, updated_at) VALUES (?, 'User', 'oauth', NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?)",
+			email, googleID, provider, externalID, now, now).Error)
+		var id uint
+		require.NoError(t, db.Raw("SELECT id FROM users WHERE email = ?", email).Scan(&id).Error)
+		return id
+	}
+	githubUser := insertUser("github@example.com", "", "github", "42")
+	googleUser := insertUser("google@example.com", "g-1", "google", "g-1")
+	legacyUser := insertUser("legacy@example.com", "g-2", "google", "")
+	signupUser := insertUser("signup@example.com", "", "", "")
+	require.NoError(t, db.Exec("INSERT INTO google_identities (user_id, google_user_id, email, email_verified, created_at, updated_at) VALUES (?, 'g-3', 'signup@example.com', true, ?, ?)",
+		signupUser, now, now).Error)
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	var identities []dtos.OAuthIdentity
+	require.NoError(t, db.Order("user_id").Find(&identities).Error)
+	got := make(map[uint]string, len(identities))
+	for _, identity := range identities {
+		got[identity.UserID] = identity.Provider + ":" + identity.ExternalID
+	}
+	assert.Equal(t, map[uint]string{
+		githubUser: "github:42",
+		googleUser: "google:g-1",
+		legacyUser: "google:g-2",
+		signupUser: "google:g-3",
+	}, got)
+}
+
+func TestLoadMigrations_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_second.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER)")},
+		"010_second.down.sql": {Data: []byte("DROP TABLE b")},
+		"002_first.up.sql":    {Data: []byte("CREATE TABLE a (id INTEGER)")},
+		"002_first.down.sql":  {Data: []byte("DROP TABLE a")},
+		"README.md":           {Data: []byte("ignored")},
+	}
+
+	loaded, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, 2, loaded[0].Version)
+	assert.Equal(t, "first", loaded[0].Name)
+	assert.Equal(t, 10, loaded[1].Version)
+}
+
+func TestLoadMigrations_RequiresDownFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_only_up.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER)")},
+	}
+
+	_, err := LoadMigrations(fsys)
+	assert.Error(t, err)
+}
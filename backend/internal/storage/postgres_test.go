@@ -0,0 +1,34 @@
+//go:build postgres
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/storage/storagetest"
+)
+
+// Run with: TEST_POSTGRES_DSN=postgres://... go test -tags postgres ./internal/storage/
+
+func TestMigrator_Postgres(t *testing.T) {
+	db := storagetest.OpenPostgres(t)
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+
+	assertModelColumnsExist(t, db)
+	assertModelsRoundTrip(t, db)
+
+	rolledBack, err := migrator.Down(len(migrator.migrations))
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(migrator.migrations))
+	for _, model := range persistedModels {
+		assert.False(t, db.Migrator().HasTable(model))
+	}
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryLogger wraps a GORM logger and additionally reports every query slower
+// than threshold, regardless of the wrapped logger's level. Bound values are
+// redacted from all logged SQL so credentials and user data stay out of the logs.
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+	slow      *slog.Logger
+}
+
+// newSlowQueryLogger wraps base, reporting slow queries as warnings to slow. A
+// non-positive threshold disables slow query reporting.
+func newSlowQueryLogger(base logger.Interface, threshold time.Duration, slow *slog.Logger) *slowQueryLogger {
+	return &slowQueryLogger{Interface: base, threshold: threshold, slow: slow}
+}
+
+// LogMode sets the level of the wrapped logger; slow queries are still reported
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return newSlowQueryLogger(l.Interface.LogMode(level), l.threshold, l.slow)
+}
+
+// Trace forwards to the wrapped logger and reports the query if it was slow
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	if l.threshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(begin); elapsed >= l.threshold {
+		sql, rows := fc()
+		l.slow.WarnContext(ctx, "slow query",
+			"sql", sql,
+			"duration_ms", elapsed.Milliseconds(),
+			"threshold_ms", l.threshold.Milliseconds(),
+			"rows_affected", rows,
+		)
+	}
+}
+
+// ParamsFilter drops bound values so logged SQL keeps its placeholders
+func (l *slowQueryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+// newDatabaseLogger builds the application's GORM logger for env (the ENV
+// setting): warnings and errors, except in production, plus slow queries at or
+// above threshold through the structured logger. Under ENV=test it logs nothing.
+func newDatabaseLogger(threshold time.Duration, env string) logger.Interface {
+	if env == "test" {
+		return logger.Discard
+	}
+
+	production := env == "production"
+	level := logger.Warn
+	if production {
+		level = logger.Silent
+	}
+
+	base := logger.New(log.Default(), logger.Config{
+		LogLevel: level,
+		// Slow queries are reported by slowQueryLogger instead
+		SlowThreshold: 0,
+		Colorful:      !production,
+	})
+	return newSlowQueryLogger(base, threshold, slog.Default())
+}
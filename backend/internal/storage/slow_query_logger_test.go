@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+)
+
+// recordedLogs is a slog handler collecting the records a slowQueryLogger
+// reports, with their attributes flattened into a map
+type recordedLogs struct {
+	mu      sync.Mutex
+	records []map[string]interface{}
+}
+
+func (r *recordedLogs) Enabled(context.Context, slog.Level) bool { return true }
+
+func (r *recordedLogs) Handle(_ context.Context, record slog.Record) error {
+	entry := map[string]interface{}{"level": record.Level, "msg": record.Message}
+	record.Attrs(func(attr slog.Attr) bool {
+		entry[attr.Key] = attr.Value.Any()
+		return true
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, entry)
+	return nil
+}
+
+func (r *recordedLogs) WithAttrs([]slog.Attr) slog.Handler { return r }
+func (r *recordedLogs) WithGroup(string) slog.Handler      { return r }
+
+func (r *recordedLogs) logger() *slog.Logger {
+	return slog.New(r)
+}
+
+func (r *recordedLogs) all() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.records...)
+}
+
+func setupSlowQueryLoggerTest(t *testing.T, threshold time.Duration) (*gorm.DB, *recordedLogs) {
+	logs := &recordedLogs{}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: newSlowQueryLogger(logger.Default.LogMode(logger.Silent), threshold, logs.logger()),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}))
+	return db, logs
+}
+
+// delayQueries makes every SELECT take at least delay
+func delayQueries(t *testing.T, db *gorm.DB, delay time.Duration) {
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:delay", func(*gorm.DB) {
+		time.Sleep(delay)
+	}))
+}
+
+func TestSlowQueryLogger_ReportsDelayedQuery(t *testing.T) {
+	db, logs := setupSlowQueryLoggerTest(t, 20*time.Millisecond)
+	require.NoError(t, db.Create(&dtos.Task{Title: "secret title", UserID: 7}).Error)
+	delayQueries(t, db, 30*time.Millisecond)
+
+	var tasks []dtos.Task
+	require.NoError(t, db.Where("title = ? AND user_id = ?", "secret title", 7).Find(&tasks).Error)
+
+	records := logs.all()
+	require.Len(t, records, 1)
+	assert.Equal(t, slog.LevelWarn, records[0]["level"])
+	assert.Equal(t, "slow query", records[0]["msg"])
+	assert.Equal(t, int64(1), records[0]["rows_affected"])
+	assert.Equal(t, int64(20), records[0]["threshold_ms"])
+	assert.GreaterOrEqual(t, records[0]["duration_ms"], int64(30))
+	assert.Contains(t, records[0]["sql"], "SELECT * FROM `tasks` WHERE (title = ? AND user_id = ?)")
+
+	// Bound values are redacted
+	assert.NotContains(t, records[0]["sql"], "secret title")
+}
+
+func TestSlowQueryLogger_IgnoresFastQueries(t *testing.T) {
+	db, logs := setupSlowQueryLoggerTest(t, time.Second)
+
+	var tasks []dtos.Task
+	require.NoError(t, db.Find(&tasks).Error)
+
+	assert.Empty(t, logs.all())
+}
+
+func TestSlowQueryLogger_ZeroThresholdDisablesReporting(t *testing.T) {
+	db, logs := setupSlowQueryLoggerTest(t, 0)
+	delayQueries(t, db, 5*time.Millisecond)
+
+	var tasks []dtos.Task
+	require.NoError(t, db.Find(&tasks).Error)
+
+	assert.Empty(t, logs.all())
+}
+
+func TestSlowQueryLogger_LogModeKeepsReporting(t *testing.T) {
+	logs := &recordedLogs{}
+	slow := newSlowQueryLogger(logger.Default, 10*time.Millisecond, logs.logger())
+
+	silenced := slow.LogMode(logger.Silent)
+	silenced.Trace(t.Context(), time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+
+	assert.Len(t, logs.all(), 1)
+}
+
+func TestDatabaseLogger_SilentInTestMode(t *testing.T) {
+	assert.Equal(t, logger.Discard, newDatabaseLogger(time.Nanosecond, "test"))
+	assert.IsType(t, &slowQueryLogger{}, newDatabaseLogger(time.Nanosecond, "production"))
+}
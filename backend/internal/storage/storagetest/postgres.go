@@ -0,0 +1,76 @@
+// Package storagetest provides database fixtures for tests that run against a real server.
+package storagetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// PostgresDSNEnv names the environment variable holding the DSN of the Postgres
+// server used by the postgres-tagged test suites
+const PostgresDSNEnv = "TEST_POSTGRES_DSN"
+
+// OpenPostgres connects to the server in TEST_POSTGRES_DSN inside a fresh schema
+// that is dropped when the test finishes. The test is skipped when the variable is
+// unset. Migrations are not applied.
+func OpenPostgres(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(PostgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", PostgresDSNEnv)
+	}
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("failed to generate schema name: %v", err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	admin := open(t, dsn)
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	return open(t, withSearchPath(dsn, schema))
+}
+
+// open connects to dsn and closes the pool on cleanup
+func open(t testing.TB, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to postgres: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// withSearchPath points every connection of a URL or key=value DSN at schema
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		return dsn + separator + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
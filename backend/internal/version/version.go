@@ -0,0 +1,94 @@
+// Package version describes the running build. Set its variables at build
+// time, e.g.
+//
+//	go build -ldflags "-X todo-app/internal/version.Version=v1.2.3 \
+//		-X todo-app/internal/version.Commit=$(git rev-parse HEAD) \
+//		-X todo-app/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Whatever is left unset is filled in from the VCS data go build records.
+package version
+
+import "runtime/debug"
+
+// Set with -ldflags -X
+var (
+	// Version is the release version, such as v1.2.3
+	Version string
+
+	// Commit is the git commit the binary was built from
+	Commit string
+
+	// BuildDate is when the binary was built, in RFC 3339
+	BuildDate string
+)
+
+// Info is the version of a build, as reported by /health and /api/version
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+}
+
+// Get returns the linked version info, completed from the binary's build info
+func Get() Info {
+	return resolve(Info{Version: Version, Commit: Commit, BuildDate: BuildDate}, debug.ReadBuildInfo)
+}
+
+// resolve fills in the fields linked leaves empty: the commit and build date
+// from the VCS revision and commit time, and the version from the module
+// version or the short revision, falling back to "dev"
+func resolve(linked Info, readBuildInfo func() (*debug.BuildInfo, bool)) Info {
+	info := linked
+	if info.Version != "" && info.Commit != "" && info.BuildDate != "" {
+		return info
+	}
+
+	build, ok := readBuildInfo()
+	if !ok {
+		if info.Version == "" {
+			info.Version = "dev"
+		}
+		return info
+	}
+
+	var revision, commitTime string
+	var modified bool
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			commitTime = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = revision
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = commitTime
+	}
+	if info.Version != "" {
+		return info
+	}
+
+	// Binaries installed with go install carry their module version
+	if build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+		return info
+	}
+	if revision == "" {
+		info.Version = "dev"
+		return info
+	}
+
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	info.Version = revision
+	return info
+}
@@ -0,0 +1,53 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	buildInfo := func(mainVersion string, settings ...debug.BuildSetting) func() (*debug.BuildInfo, bool) {
+		return func() (*debug.BuildInfo, bool) {
+			return &debug.BuildInfo{Main: debug.Module{Version: mainVersion}, Settings: settings}, true
+		}
+	}
+	revision := debug.BuildSetting{Key: "vcs.revision", Value: "0123456789abcdef0123"}
+	commitTime := debug.BuildSetting{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"}
+
+	cases := []struct {
+		name          string
+		linked        Info
+		readBuildInfo func() (*debug.BuildInfo, bool)
+		want          Info
+	}{
+		{
+			"linked values win",
+			Info{Version: "v1.4.0", Commit: "abc123", BuildDate: "2026-10-15T08:00:00Z"},
+			buildInfo("v1.3.0", revision, commitTime),
+			Info{Version: "v1.4.0", Commit: "abc123", BuildDate: "2026-10-15T08:00:00Z"},
+		},
+		{
+			"linked version only",
+			Info{Version: "v1.4.0"},
+			buildInfo("v1.3.0", revision, commitTime),
+			Info{Version: "v1.4.0", Commit: "0123456789abcdef0123", BuildDate: "2026-10-01T12:00:00Z"},
+		},
+		{"module version", Info{}, buildInfo("v1.3.0", revision), Info{Version: "v1.3.0", Commit: "0123456789abcdef0123"}},
+		{"vcs revision", Info{}, buildInfo("(devel)", revision), Info{Version: "0123456789ab", Commit: "0123456789abcdef0123"}},
+		{
+			"modified checkout",
+			Info{},
+			buildInfo("(devel)", revision, debug.BuildSetting{Key: "vcs.modified", Value: "true"}),
+			Info{Version: "0123456789ab-dirty", Commit: "0123456789abcdef0123"},
+		},
+		{"no vcs info", Info{}, buildInfo("(devel)"), Info{Version: "dev"}},
+		{"no build info", Info{}, func() (*debug.BuildInfo, bool) { return nil, false }, Info{Version: "dev"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, resolve(tc.linked, tc.readBuildInfo))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"todo-app/services/user"
+)
+
+// AccountPurgeJob permanently deletes accounts whose deletion grace period has passed
+type AccountPurgeJob struct {
+	userService *user.UserService
+	interval    time.Duration
+	now         func() time.Time
+	logger      *slog.Logger
+	done        chan bool
+}
+
+// NewAccountPurgeJob creates a new account purge job
+func NewAccountPurgeJob(db *gorm.DB, interval time.Duration) *AccountPurgeJob {
+	if interval == 0 {
+		interval = 1 * time.Hour // Default to 1 hour
+	}
+
+	return &AccountPurgeJob{
+		userService: user.NewUserService(db),
+		interval:    interval,
+		now:         time.Now,
+		logger:      slog.Default().With("job", "account_purge"),
+		done:        make(chan bool),
+	}
+}
+
+// Start begins the account purge job
+func (j *AccountPurgeJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.logger.Info("cleanup job started", "interval", j.interval)
+
+	// Run purge immediately on start
+	j.purge()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.purge()
+		case <-ctx.Done():
+			j.logger.Info("cleanup job stopped")
+			j.done <- true
+			return
+		}
+	}
+}
+
+// Stop stops the account purge job
+func (j *AccountPurgeJob) Stop() {
+	<-j.done
+}
+
+// purge deletes every account scheduled for deletion at or before now
+func (j *AccountPurgeJob) purge() {
+	purged, err := j.userService.PurgeScheduledDeletions(j.now())
+	if err != nil {
+		j.logger.Error("failed to purge deleted accounts", "error", err)
+	}
+	if purged > 0 {
+		j.logger.Info("account purge completed", "accounts_removed", purged)
+	}
+}
+
+// RunOnce executes the purge once (useful for testing or manual execution)
+func (j *AccountPurgeJob) RunOnce(ctx context.Context) error {
+	j.purge()
+	return nil
+}
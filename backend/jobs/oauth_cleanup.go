@@ -2,17 +2,18 @@ package jobs
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"gorm.io/gorm"
 	"domain/auth/entities"
 )
 
-// OAuthCleanupJob handles cleanup of expired OAuth state records
+// OAuthCleanupJob handles cleanup of expired OAuth state and pending account link records
 type OAuthCleanupJob struct {
 	db       *gorm.DB
 	interval time.Duration
+	logger   *slog.Logger
 	done     chan bool
 }
 
@@ -25,6 +26,7 @@ func NewOAuthCleanupJob(db *gorm.DB, interval time.Duration) *OAuthCleanupJob {
 	return &OAuthCleanupJob{
 		db:       db,
 		interval: interval,
+		logger:   slog.Default().With("job", "oauth_cleanup"),
 		done:     make(chan bool),
 	}
 }
@@ -34,7 +36,7 @@ func (j *OAuthCleanupJob) Start(ctx context.Context) {
 	ticker := time.NewTicker(j.interval)
 	defer ticker.Stop()
 
-	log.Printf("OAuth cleanup job started (interval: %v)", j.interval)
+	j.logger.Info("cleanup job started", "interval", j.interval)
 
 	// Run cleanup immediately on start
 	j.cleanup(ctx)
@@ -44,7 +46,7 @@ func (j *OAuthCleanupJob) Start(ctx context.Context) {
 		case <-ticker.C:
 			j.cleanup(ctx)
 		case <-ctx.Done():
-			log.Println("OAuth cleanup job stopped")
+			j.logger.Info("cleanup job stopped")
 			j.done <- true
 			return
 		}
@@ -56,7 +58,7 @@ func (j *OAuthCleanupJob) Stop() {
 	<-j.done
 }
 
-// cleanup removes expired OAuth state records
+// cleanup removes expired OAuth state and pending account link records
 func (j *OAuthCleanupJob) cleanup(ctx context.Context) {
 	startTime := time.Now()
 
@@ -66,14 +68,26 @@ func (j *OAuthCleanupJob) cleanup(ctx context.Context) {
 		Delete(&entities.OAuthState{})
 
 	if result.Error != nil {
-		log.Printf("Error cleaning up OAuth states: %v", result.Error)
+		j.logger.Error("failed to clean up OAuth states", "error", result.Error)
+		return
+	}
+
+	// Delete pending account links that can no longer be confirmed
+	links := j.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&entities.PendingAccountLink{})
+
+	if links.Error != nil {
+		j.logger.Error("failed to clean up pending account links", "error", links.Error)
 		return
 	}
 
 	duration := time.Since(startTime)
-	if result.RowsAffected > 0 {
-		log.Printf("OAuth cleanup completed: removed %d expired states in %v",
-			result.RowsAffected, duration)
+	if result.RowsAffected > 0 || links.RowsAffected > 0 {
+		j.logger.Info("OAuth cleanup completed",
+			"expired_states_removed", result.RowsAffected,
+			"expired_links_removed", links.RowsAffected,
+			"duration", duration)
 	}
 }
 
@@ -122,7 +136,7 @@ func CleanupOAuthStatesOlderThan(db *gorm.DB, duration time.Duration) error {
 	}
 
 	if result.RowsAffected > 0 {
-		log.Printf("Removed %d OAuth states older than %v", result.RowsAffected, duration)
+		slog.Info("removed old OAuth states", "states_removed", result.RowsAffected, "older_than", duration)
 	}
 
 	return nil
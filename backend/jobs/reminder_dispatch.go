@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-app/services/reminder"
+)
+
+// ReminderDispatchJob delivers task reminders as they fall due
+type ReminderDispatchJob struct {
+	reminderService *reminder.ReminderService
+	interval        time.Duration
+	now             func() time.Time
+	logger          *slog.Logger
+	done            chan bool
+}
+
+// NewReminderDispatchJob creates a new reminder dispatch job
+func NewReminderDispatchJob(reminderService *reminder.ReminderService, interval time.Duration) *ReminderDispatchJob {
+	if interval == 0 {
+		interval = 1 * time.Minute // Default to 1 minute
+	}
+
+	return &ReminderDispatchJob{
+		reminderService: reminderService,
+		interval:        interval,
+		now:             time.Now,
+		logger:          slog.Default().With("job", "reminder_dispatch"),
+		done:            make(chan bool),
+	}
+}
+
+// Start begins the reminder dispatch job
+func (j *ReminderDispatchJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.logger.Info("dispatch job started", "interval", j.interval)
+
+	// Deliver reminders that fell due while the server was down
+	j.dispatchAndLog(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			j.dispatchAndLog(ctx)
+		case <-ctx.Done():
+			j.logger.Info("dispatch job stopped")
+			j.done <- true
+			return
+		}
+	}
+}
+
+// Stop stops the reminder dispatch job
+func (j *ReminderDispatchJob) Stop() {
+	<-j.done
+}
+
+// dispatchAndLog dispatches due reminders, logging a failure rather than
+// returning it so the next tick can try again
+func (j *ReminderDispatchJob) dispatchAndLog(ctx context.Context) {
+	if err := j.dispatch(ctx); err != nil {
+		j.logger.Error("failed to dispatch reminders", "error", err)
+	}
+}
+
+// dispatch delivers every reminder due at or before now
+func (j *ReminderDispatchJob) dispatch(ctx context.Context) error {
+	result, err := j.reminderService.DispatchDue(ctx, j.now())
+	if result.Sent > 0 || result.Skipped > 0 || result.Cancelled > 0 || result.Failed > 0 {
+		j.logger.Info("reminder dispatch completed",
+			"sent", result.Sent, "skipped", result.Skipped, "cancelled", result.Cancelled, "failed", result.Failed)
+	}
+	return err
+}
+
+// RunOnce executes the dispatch once (useful for testing or manual execution)
+func (j *ReminderDispatchJob) RunOnce(ctx context.Context) error {
+	return j.dispatch(ctx)
+}
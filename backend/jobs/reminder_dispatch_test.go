@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+	"todo-app/services/reminder"
+)
+
+// channelNotifier sends the reminders it is given on delivered
+type channelNotifier struct {
+	delivered chan reminder.Reminder
+}
+
+func (n *channelNotifier) Notify(ctx context.Context, r reminder.Reminder) error {
+	n.delivered <- r
+	return nil
+}
+
+func setupReminderDispatchJobTest(t *testing.T) (*gorm.DB, *channelNotifier, *ReminderDispatchJob) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.Notification{}))
+
+	notifier := &channelNotifier{delivered: make(chan reminder.Reminder, 10)}
+	service := reminder.NewReminderService(db, map[string]reminder.Notifier{
+		dtos.NotificationChannelLog: notifier,
+	})
+	return db, notifier, NewReminderDispatchJob(service, 10*time.Millisecond)
+}
+
+// scheduleReminder stores a pending task with a log reminder due at remindAt
+func scheduleReminder(t *testing.T, db *gorm.DB, remindAt time.Time) dtos.Task {
+	task := dtos.Task{Title: "Call the plumber", Status: "pending", UserID: 1, RemindAt: &remindAt}
+	require.NoError(t, db.Create(&task).Error)
+	notification := dtos.Notification{UserID: 1, TaskID: task.ID, Channel: dtos.NotificationChannelLog, ScheduledFor: remindAt, Status: dtos.NotificationPending}
+	require.NoError(t, db.Create(&notification).Error)
+	return task
+}
+
+func TestReminderDispatchJob_RunOnceDeliversDueReminders(t *testing.T) {
+	db, notifier, job := setupReminderDispatchJobTest(t)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+	due := scheduleReminder(t, db, now.Add(-time.Minute))
+	scheduleReminder(t, db, now.Add(time.Hour))
+
+	require.NoError(t, job.RunOnce(context.Background()))
+	require.Len(t, notifier.delivered, 1)
+	assert.Equal(t, due.ID, (<-notifier.delivered).TaskID)
+}
+
+func TestReminderDispatchJob_RunOnceReturnsDispatchError(t *testing.T) {
+	db, _, job := setupReminderDispatchJobTest(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	assert.Error(t, job.RunOnce(context.Background()))
+}
+
+func TestReminderDispatchJob_StartDispatchesUntilStopped(t *testing.T) {
+	db, notifier, job := setupReminderDispatchJobTest(t)
+	scheduleReminder(t, db, time.Now().Add(-time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go job.Start(ctx)
+
+	select {
+	case <-notifier.delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("due reminder was not delivered")
+	}
+
+	// Reminders that fall due later are picked up on a tick
+	scheduleReminder(t, db, time.Now())
+	select {
+	case <-notifier.delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reminder falling due later was not delivered")
+	}
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		job.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the context was cancelled")
+	}
+}
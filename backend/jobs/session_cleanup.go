@@ -2,7 +2,7 @@ package jobs
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,6 +13,7 @@ import (
 type SessionCleanupJob struct {
 	db       *gorm.DB
 	interval time.Duration
+	logger   *slog.Logger
 	done     chan bool
 }
 
@@ -25,6 +26,7 @@ func NewSessionCleanupJob(db *gorm.DB, interval time.Duration) *SessionCleanupJo
 	return &SessionCleanupJob{
 		db:       db,
 		interval: interval,
+		logger:   slog.Default().With("job", "session_cleanup"),
 		done:     make(chan bool),
 	}
 }
@@ -34,7 +36,7 @@ func (j *SessionCleanupJob) Start(ctx context.Context) {
 	ticker := time.NewTicker(j.interval)
 	defer ticker.Stop()
 
-	log.Printf("Session cleanup job started (interval: %v)", j.interval)
+	j.logger.Info("cleanup job started", "interval", j.interval)
 
 	// Run cleanup immediately on start
 	j.cleanup(ctx)
@@ -44,7 +46,7 @@ func (j *SessionCleanupJob) Start(ctx context.Context) {
 		case <-ticker.C:
 			j.cleanup(ctx)
 		case <-ctx.Done():
-			log.Println("Session cleanup job stopped")
+			j.logger.Info("cleanup job stopped")
 			j.done <- true
 			return
 		}
@@ -66,14 +68,15 @@ func (j *SessionCleanupJob) cleanup(ctx context.Context) {
 		Delete(&entities.AuthenticationSession{})
 
 	if result.Error != nil {
-		log.Printf("Error cleaning up expired sessions: %v", result.Error)
+		j.logger.Error("failed to clean up expired sessions", "error", result.Error)
 		return
 	}
 
 	duration := time.Since(startTime)
 	if result.RowsAffected > 0 {
-		log.Printf("Session cleanup completed: removed %d expired sessions in %v",
-			result.RowsAffected, duration)
+		j.logger.Info("session cleanup completed",
+			"expired_sessions_removed", result.RowsAffected,
+			"duration", duration)
 	}
 
 	// Also cleanup inactive sessions (no activity for 7 days)
@@ -89,13 +92,14 @@ func (j *SessionCleanupJob) cleanupInactiveSessions(ctx context.Context) {
 		Delete(&entities.AuthenticationSession{})
 
 	if result.Error != nil {
-		log.Printf("Error cleaning up inactive sessions: %v", result.Error)
+		j.logger.Error("failed to clean up inactive sessions", "error", result.Error)
 		return
 	}
 
 	if result.RowsAffected > 0 {
-		log.Printf("Removed %d inactive sessions (no activity for 7+ days)",
-			result.RowsAffected)
+		j.logger.Info("removed inactive sessions",
+			"inactive_sessions_removed", result.RowsAffected,
+			"inactive_for", 7*24*time.Hour)
 	}
 }
 
@@ -161,7 +165,7 @@ func CleanupSessionsByUserID(db *gorm.DB, userID uint) error {
 	}
 
 	if result.RowsAffected > 0 {
-		log.Printf("Removed %d sessions for user %d", result.RowsAffected, userID)
+		slog.Info("removed user sessions", "sessions_removed", result.RowsAffected, "user_id", userID)
 	}
 
 	return nil
@@ -179,7 +183,7 @@ func CleanupSessionsOlderThan(db *gorm.DB, duration time.Duration) error {
 	}
 
 	if result.RowsAffected > 0 {
-		log.Printf("Removed %d sessions older than %v", result.RowsAffected, duration)
+		slog.Info("removed old sessions", "sessions_removed", result.RowsAffected, "older_than", duration)
 	}
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"domain/auth/entities"
 	"github.com/gin-gonic/gin"
 	"todo-app/internal/dtos"
 	"todo-app/services/auth"
@@ -23,8 +24,20 @@ func NewAuthMiddleware(sessionService *auth.SessionService, jwtService *auth.JWT
 	}
 }
 
-// RequireAuth middleware requires valid authentication
+// RequireAuth middleware requires valid authentication by an account that is
+// not pending deletion
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return m.requireAuth(false)
+}
+
+// RequireAuthAllowingPendingDeletion is like RequireAuth but also admits accounts
+// pending deletion, so they can reach the restore endpoint
+func (m *AuthMiddleware) RequireAuthAllowingPendingDeletion() gin.HandlerFunc {
+	return m.requireAuth(true)
+}
+
+// requireAuth validates the session, rejecting accounts pending deletion unless allowed
+func (m *AuthMiddleware) requireAuth(allowPendingDeletion bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := m.extractToken(c)
 
@@ -57,15 +70,23 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Set user and session in context
-		c.Set("user", result.User)
-		c.Set("session", result.Session)
+		if user, ok := result.User.(*dtos.User); ok && user.IsPendingDeletion() && !allowPendingDeletion {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "account_pending_deletion",
+				"message": "Account is scheduled for deletion; restore it to continue",
+			})
+			c.Abort()
+			return
+		}
+
+		setAuthContext(c, result)
 
-		// Extract user ID from interface
-		if user, ok := result.User.(*dtos.User); ok {
-			c.Set("user_id", user.ID)
+		// Cookie-authenticated clients need the session's CSRF token for writes
+		if _, ok := sessionCookie(c); ok {
+			if current, _ := c.Cookie(CSRFCookie); current != m.sessionService.CSRFToken(result.Session.ID) {
+				IssueCSRFToken(c, m.sessionService, result.Session.ID)
+			}
 		}
-		c.Set("session_id", result.Session.ID)
 
 		c.Next()
 	}
@@ -80,15 +101,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			// Validate session
 			result, err := m.sessionService.ValidateSession(tokenString)
 			if err == nil && result.Valid {
-				// Set user and session in context
-				c.Set("user", result.User)
-				c.Set("session", result.Session)
-
-				// Extract user ID from interface
-				if user, ok := result.User.(*dtos.User); ok {
-					c.Set("user_id", user.ID)
-				}
-				c.Set("session_id", result.Session.ID)
+				setAuthContext(c, result)
 			}
 		}
 
@@ -142,15 +155,41 @@ func (m *AuthMiddleware) RequireOAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Set user and session in context
-		c.Set("user", result.User)
-		c.Set("session", result.Session)
+		setAuthContext(c, result)
+
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail middleware blocks users who have not verified their email.
+// It must run after RequireAuth, which loads the user.
+func (m *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user, ok := GetCurrentUser(c).(*dtos.User); !ok || !user.HasVerifiedEmail() {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "email_not_verified",
+				"message": "Verify your email address to continue",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
 
-		// Extract user ID from interface
-		if user, ok := result.User.(*dtos.User); ok {
-			c.Set("user_id", user.ID)
+// RequireAdmin middleware restricts a route to admin users.
+// It must run after RequireAuth, which loads the user.
+func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user, ok := GetCurrentUser(c).(*dtos.User); !ok || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "admin_required",
+				"message": "Administrator access required",
+			})
+			c.Abort()
+			return
 		}
-		c.Set("session_id", result.Session.ID)
 
 		c.Next()
 	}
@@ -185,20 +224,28 @@ func (m *AuthMiddleware) RefreshIfNeeded() gin.HandlerFunc {
 			c.Header("X-Token-Refresh-Needed", "true")
 		}
 
-		// Set user and session in context
-		c.Set("user", result.User)
-		c.Set("session", result.Session)
-
-		// Extract user ID from interface
-		if user, ok := result.User.(*dtos.User); ok {
-			c.Set("user_id", user.ID)
-		}
-		c.Set("session_id", result.Session.ID)
+		setAuthContext(c, result)
 
 		c.Next()
 	}
 }
 
+// setAuthContext stores the validated user and session on the request context.
+// Both the snake_case keys used by the auth handlers and the camelCase keys
+// read by the task and user handlers are populated.
+func setAuthContext(c *gin.Context, result *entities.SessionValidationResult) {
+	c.Set("user", result.User)
+	c.Set("session", result.Session)
+
+	// Extract user ID from interface
+	if user, ok := result.User.(*dtos.User); ok {
+		c.Set("user_id", user.ID)
+		c.Set("userID", user.ID)
+	}
+	c.Set("session_id", result.Session.ID)
+	c.Set("sessionID", result.Session.ID)
+}
+
 // extractToken extracts the authentication token from cookie or Authorization header
 func (m *AuthMiddleware) extractToken(c *gin.Context) string {
 	// Try cookie first
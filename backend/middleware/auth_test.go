@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	"todo-app/services/auth"
+)
+
+func setupAuthMiddlewareTest(t *testing.T) (*gorm.DB, *auth.SessionService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	jwtService, err := auth.NewJWTService(cfg.Session, false)
+	require.NoError(t, err)
+	sessionService := auth.NewSessionService(db, jwtService, cfg.Session)
+	authMiddleware := NewAuthMiddleware(sessionService, jwtService)
+
+	router := gin.New()
+	router.GET("/protected", authMiddleware.RequireAuth(), func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		sessionID, _ := c.Get("sessionID")
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":    userID,
+			"session_id": sessionID,
+		})
+	})
+	router.POST("/restore", authMiddleware.RequireAuthAllowingPendingDeletion(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/verified", authMiddleware.RequireAuth(), authMiddleware.RequireVerifiedEmail(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/admin", authMiddleware.RequireAuth(), authMiddleware.RequireAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/csrf-protected", authMiddleware.RequireCSRF(), authMiddleware.RequireAuth(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	return db, sessionService, router
+}
+
+func createTestSession(t *testing.T, db *gorm.DB, sessionService *auth.SessionService) (*dtos.User, *entities.AuthenticationSession, string) {
+	user := dtos.User{
+		Email:         "user@example.com",
+		Name:          "Test User",
+		GoogleID:      "google-123",
+		OAuthProvider: "google",
+		IsActive:      true,
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	session, token, err := sessionService.CreateSession(auth.CreateSessionRequest{
+		UserID: user.ID,
+		Email:  user.Email,
+	})
+	require.NoError(t, err)
+
+	return &user, session, token
+}
+
+func TestRequireAuth_MissingToken(t *testing.T) {
+	_, _, router := setupAuthMiddlewareTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "no_auth_token", body["error"])
+	assert.Contains(t, body, "message")
+}
+
+func TestRequireAuth_InvalidToken(t *testing.T) {
+	_, _, router := setupAuthMiddlewareTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_session", body["error"])
+}
+
+func TestRequireAuth_ValidCookieSetsUserID(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	user, session, token := createTestSession(t, db, sessionService)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(user.ID), body["user_id"])
+	assert.Equal(t, session.ID, body["session_id"])
+}
+
+func TestRequireAuth_ValidBearerToken(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	user, _, token := createTestSession(t, db, sessionService)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(user.ID), body["user_id"])
+}
+
+func TestRequireAuth_ExpiredSession(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, session, token := createTestSession(t, db, sessionService)
+
+	// Expire the session record while leaving the JWT itself valid
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).
+		Where("id = ?", session.ID).
+		UpdateColumn("session_expires_at", time.Now().Add(-time.Minute)).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_session", body["error"])
+	assert.Equal(t, entities.SessionErrorExpired, body["message"])
+}
+
+func TestRequireAuth_PendingDeletionOnlyReachesRestore(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	user, _, token := createTestSession(t, db, sessionService)
+	require.NoError(t, db.Model(user).UpdateColumn("deletion_scheduled_at", time.Now().Add(time.Hour)).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "account_pending_deletion", body["error"])
+
+	req = httptest.NewRequest(http.MethodPost, "/restore", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRequireVerifiedEmail_BlocksUnverifiedPasswordUsers(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+
+	user := dtos.User{Email: "password@example.com", Name: "Password User", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	_, token, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	verifiedPost := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/verified", nil)
+		req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := verifiedPost()
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "email_not_verified", body["error"])
+
+	require.NoError(t, db.Model(&user).UpdateColumn("email_verified", true).Error)
+	assert.Equal(t, http.StatusNoContent, verifiedPost().Code)
+}
+
+func TestRequireVerifiedEmail_AllowsOAuthUsers(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, _, token := createTestSession(t, db, sessionService)
+
+	req := httptest.NewRequest(http.MethodPost, "/verified", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	user, _, token := createTestSession(t, db, sessionService)
+
+	adminGet := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, adminGet("").Code)
+
+	w := adminGet(token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "admin_required", body["error"])
+
+	require.NoError(t, db.Model(user).UpdateColumn("is_admin", true).Error)
+	assert.Equal(t, http.StatusNoContent, adminGet(token).Code)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"todo-app/services/auth"
+)
+
+// CSRFHeader carries the CSRF token on state-changing requests
+const CSRFHeader = "X-CSRF-Token"
+
+// CSRFCookie holds the session's CSRF token where the frontend's JavaScript can read it
+const CSRFCookie = "csrf_token"
+
+// RequireCSRF rejects state-changing requests authenticated by the session
+// cookie unless X-CSRF-Token carries the session's CSRF token. Requests
+// authenticated with a Bearer token cannot be forged cross-site and skip the check.
+func (m *AuthMiddleware) RequireCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		token, ok := sessionCookie(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		sessionID, err := m.jwtService.ExtractSessionID(token)
+		if err != nil {
+			// An invalid cookie authenticates nothing; the auth middleware rejects it
+			c.Next()
+			return
+		}
+
+		expected := m.sessionService.CSRFToken(sessionID)
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(CSRFHeader)), []byte(expected)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "csrf_mismatch",
+				"message": "Missing or invalid CSRF token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IssueCSRFToken sets the CSRF cookie for a session and returns the token.
+// The cookie shares the session cookie's path, domain, Secure flag and SameSite
+// mode, so the frontend can read it wherever the session cookie is sent.
+func IssueCSRFToken(c *gin.Context, sessionService *auth.SessionService, sessionID string) string {
+	token := sessionService.CSRFToken(sessionID)
+	cookies := sessionService.Cookies()
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     CSRFCookie,
+		Value:    token,
+		Path:     cookies.Path,
+		Domain:   cookies.Domain,
+		MaxAge:   sessionService.GetSessionMaxAge(),
+		Secure:   cookies.Secure,
+		HttpOnly: false, // The frontend reads it to echo it back in X-CSRF-Token
+		SameSite: cookies.SameSiteMode(),
+	})
+	return token
+}
+
+// sessionCookie returns the session token if the request is authenticated by
+// the session cookie rather than a Bearer token
+func sessionCookie(c *gin.Context) (string, bool) {
+	token, err := c.Cookie("session_token")
+	return token, err == nil && token != ""
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/services/auth"
+)
+
+func performCSRFRequest(router *gin.Engine, cookieToken, bearerToken, csrfToken string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/csrf-protected", nil)
+	if cookieToken != "" {
+		req.AddCookie(&http.Cookie{Name: "session_token", Value: cookieToken})
+	}
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+	if csrfToken != "" {
+		req.Header.Set(CSRFHeader, csrfToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func assertCSRFMismatch(t *testing.T, w *httptest.ResponseRecorder) {
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "csrf_mismatch", body["error"])
+}
+
+func TestRequireCSRF_MissingHeader(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, _, token := createTestSession(t, db, sessionService)
+
+	assertCSRFMismatch(t, performCSRFRequest(router, token, "", ""))
+}
+
+func TestRequireCSRF_MismatchedToken(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, _, token := createTestSession(t, db, sessionService)
+
+	// A token for another session does not match
+	otherSession, _, err := sessionService.CreateSession(auth.CreateSessionRequest{UserID: 99, Email: "other@example.com"})
+	require.NoError(t, err)
+
+	assertCSRFMismatch(t, performCSRFRequest(router, token, "", sessionService.CSRFToken(otherSession.ID)))
+}
+
+func TestRequireCSRF_BearerAuthIsExempt(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, _, token := createTestSession(t, db, sessionService)
+
+	w := performCSRFRequest(router, "", token, "")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRequireCSRF_MatchingToken(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, session, token := createTestSession(t, db, sessionService)
+
+	w := performCSRFRequest(router, token, "", sessionService.CSRFToken(session.ID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRequireAuth_IssuesCSRFCookieForCookieSessions(t *testing.T) {
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, session, token := createTestSession(t, db, sessionService)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var csrfCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == CSRFCookie {
+			csrfCookie = cookie
+		}
+	}
+	require.NotNil(t, csrfCookie)
+	assert.Equal(t, sessionService.CSRFToken(session.ID), csrfCookie.Value)
+	assert.False(t, csrfCookie.HttpOnly, "the frontend must be able to read it")
+	assert.Equal(t, http.SameSiteLaxMode, csrfCookie.SameSite, "it follows the session cookie's SameSite mode")
+
+	// Bearer-token clients do not get one
+	req = httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestIssueCSRFToken_UsesSessionCookieSameSite(t *testing.T) {
+	t.Setenv("SESSION_COOKIE_SAMESITE", "Strict")
+	db, sessionService, router := setupAuthMiddlewareTest(t)
+	_, _, token := createTestSession(t, db, sessionService)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, CSRFCookie, cookies[0].Name)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+	assert.Equal(t, "/", cookies[0].Path)
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipConfig configures response compression
+type GzipConfig struct {
+	// Level is a compress/gzip level, e.g. gzip.DefaultCompression
+	Level int
+
+	// MinLength is the smallest body, in bytes, worth compressing
+	MinLength int
+
+	// ExcludedPaths are request paths that are never compressed, such as
+	// streaming endpoints that must not be buffered
+	ExcludedPaths []string
+}
+
+// incompressibleTypes are content types that are already compressed, or
+// streamed, and are passed through as is
+var incompressibleTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/pdf",
+	"application/octet-stream",
+	"audio/",
+	"font/woff",
+	"image/",
+	"text/event-stream",
+	"video/",
+}
+
+// Gzip compresses response bodies of at least MinLength bytes for clients
+// that accept gzip. The body is buffered until it reaches MinLength, so small
+// responses go out uncompressed and with their original headers.
+func Gzip(config GzipConfig) gin.HandlerFunc {
+	// Validate the level once, so writers from the pool cannot fail to build
+	if _, err := gzip.NewWriterLevel(io.Discard, config.Level); err != nil {
+		config.Level = gzip.DefaultCompression
+	}
+	pool := &sync.Pool{New: func() interface{} {
+		gz, _ := gzip.NewWriterLevel(io.Discard, config.Level)
+		return gz
+	}}
+
+	excluded := make(map[string]struct{}, len(config.ExcludedPaths))
+	for _, path := range config.ExcludedPaths {
+		excluded[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := excluded[c.Request.URL.Path]; ok || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		// Caches must keep compressed and uncompressed responses apart
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, pool: pool, minLength: config.MinLength}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either by
+// name or through "*", with a non-zero quality
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if quality, err := strconv.ParseFloat(q, 64); err == nil && quality == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isCompressibleType reports whether a Content-Type is worth compressing
+func isCompressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+	if mediaType == "image/svg+xml" {
+		return true
+	}
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// gzipResponseWriter buffers the start of a response body until it knows
+// whether to compress it: once the body reaches minLength, or is flushed, the
+// headers are settled and the rest is written straight through.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	pool      *sync.Pool
+	minLength int
+
+	buf     bytes.Buffer
+	decided bool
+	gz      *gzip.Writer // set when the response is being compressed
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf.Write(data)
+		if w.buf.Len() < w.minLength {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether any of the body has been accepted, buffered or not
+func (w *gzipResponseWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush settles the compression decision with what has been written so far,
+// so streamed responses are not held back
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide compresses the response if it is big enough and of a compressible
+// type that is not already encoded, then writes out the buffered body
+func (w *gzipResponseWriter) decide() error {
+	w.decided = true
+
+	header := w.Header()
+	status := w.Status()
+	if w.buf.Len() >= w.minLength && header.Get("Content-Encoding") == "" &&
+		isCompressibleType(header.Get("Content-Type")) &&
+		status != http.StatusNoContent && status != http.StatusNotModified {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	data := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	if w.gz != nil {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// finish writes out a body that never reached minLength and completes the
+// gzip stream
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(io.Discard)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
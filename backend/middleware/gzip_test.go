@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// performGzipRequest serves body with the given content type through the
+// gzip middleware, which compresses bodies of 100 bytes or more
+func performGzipRequest(path, acceptEncoding, contentType, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip(GzipConfig{Level: gzip.BestSpeed, MinLength: 100, ExcludedPaths: []string{"/excluded"}}))
+	handler := func(c *gin.Context) {
+		c.Data(http.StatusOK, contentType, []byte(body))
+	}
+	router.GET("/test", handler)
+	router.GET("/excluded", handler)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzip_CompressesLargeResponses(t *testing.T) {
+	body := `{"items":"` + strings.Repeat("a", 500) + `"}`
+	w := performGzipRequest("/test", "deflate, gzip", "application/json", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestGzip_PassesThroughUncompressed(t *testing.T) {
+	large := strings.Repeat("a", 500)
+	cases := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		contentType    string
+		body           string
+	}{
+		{"below threshold", "/test", "gzip", "application/json", `{"ok":true}`},
+		{"gzip not accepted", "/test", "", "application/json", large},
+		{"gzip refused", "/test", "gzip;q=0, deflate", "application/json", large},
+		{"already compressed", "/test", "gzip", "image/png", large},
+		{"excluded path", "/excluded", "gzip", "application/json", large},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performGzipRequest(tc.path, tc.acceptEncoding, tc.contentType, tc.body)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tc.body, w.Body.String())
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("br, GZIP;q=0.5"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("identity"))
+	assert.False(t, acceptsGzip("gzip; q=0"))
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests that did not match a registered route, so
+// scanners probing random paths cannot blow up label cardinality
+const unmatchedRoute = "unmatched"
+
+// taskOperations maps task write routes to the operation counted in
+// todo_task_operations_total
+var taskOperations = map[string]string{
+	http.MethodPost + " /api/v1/tasks":       "create",
+	http.MethodPut + " /api/v1/tasks/:id":    "update",
+	http.MethodDelete + " /api/v1/tasks/:id": "delete",
+}
+
+// Metrics collects Prometheus metrics for HTTP traffic. Routes are labeled by
+// their registered pattern (e.g. /api/v1/tasks/:id), not the raw request path.
+type Metrics struct {
+	requestsTotal   *prometheus.CounterVec
+	responsesTotal  *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	taskOperations  *prometheus.CounterVec
+}
+
+// NewMetrics creates the HTTP metrics and registers them with registerer,
+// normally prometheus.DefaultRegisterer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests.",
+		}, []string{"route", "method"}),
+		responsesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_responses_total",
+			Help: "Total number of HTTP responses by status code.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request duration in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		taskOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "todo_task_operations_total",
+			Help: "Total number of successful task creates, updates and deletes.",
+		}, []string{"operation"}),
+	}
+
+	registerer.MustRegister(m.requestsTotal, m.responsesTotal, m.requestDuration, m.taskOperations)
+	return m
+}
+
+// Middleware records request counts, status codes and durations for every request
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Record in a defer so responses aborted with http.ErrAbortHandler,
+		// such as a cut-short export, are still counted
+		defer m.record(c, start)
+
+		c.Next()
+	}
+}
+
+// record updates the metrics for a finished or aborted request
+func (m *Metrics) record(c *gin.Context, start time.Time) {
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	method := c.Request.Method
+	status := c.Writer.Status()
+
+	m.requestsTotal.WithLabelValues(route, method).Inc()
+	m.responsesTotal.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+
+	if operation, ok := taskOperations[method+" "+route]; ok && status >= 200 && status < 300 {
+		m.taskOperations.WithLabelValues(operation).Inc()
+	}
+}
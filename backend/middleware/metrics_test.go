@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMetricsTest() (*Metrics, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	registry := prometheus.NewRegistry()
+	metrics := NewMetrics(registry)
+
+	router := gin.New()
+	router.Use(metrics.Middleware())
+	router.POST("/api/v1/tasks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.PUT("/api/v1/tasks/:id", func(c *gin.Context) { c.Status(http.StatusUnprocessableEntity) })
+	router.DELETE("/api/v1/tasks/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
+
+	return metrics, router
+}
+
+func serveMetricsRequest(router *gin.Engine, method, path string) {
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+}
+
+func TestMetrics_CountsRequestsByRouteMethodAndStatus(t *testing.T) {
+	metrics, router := setupMetricsTest()
+
+	serveMetricsRequest(router, http.MethodPost, "/api/v1/tasks")
+	serveMetricsRequest(router, http.MethodPost, "/api/v1/tasks")
+	serveMetricsRequest(router, http.MethodDelete, "/api/v1/tasks/1")
+	serveMetricsRequest(router, http.MethodDelete, "/api/v1/tasks/2")
+	serveMetricsRequest(router, http.MethodGet, "/wp-admin/1")
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("/api/v1/tasks", http.MethodPost)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("/api/v1/tasks/:id", http.MethodDelete)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.responsesTotal.WithLabelValues("/api/v1/tasks", http.MethodPost, "201")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues(unmatchedRoute, http.MethodGet)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.responsesTotal.WithLabelValues(unmatchedRoute, http.MethodGet, "404")))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.requestDuration))
+}
+
+func TestMetrics_CountsSuccessfulTaskOperations(t *testing.T) {
+	metrics, router := setupMetricsTest()
+
+	serveMetricsRequest(router, http.MethodPost, "/api/v1/tasks")
+	serveMetricsRequest(router, http.MethodPut, "/api/v1/tasks/1")
+	serveMetricsRequest(router, http.MethodDelete, "/api/v1/tasks/1")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.taskOperations.WithLabelValues("create")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.taskOperations.WithLabelValues("delete")))
+	// The update was rejected, so it is not counted
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.taskOperations.WithLabelValues("update")))
+}
+
+func TestMetrics_CountsAbortedResponses(t *testing.T) {
+	metrics, router := setupMetricsTest()
+	router.GET("/api/v1/tasks/export", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serveMetricsRequest(router, http.MethodGet, "/api/v1/tasks/export")
+	})
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("/api/v1/tasks/export", http.MethodGet)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.responsesTotal.WithLabelValues("/api/v1/tasks/export", http.MethodGet, "200")))
+}
+
+func TestMetrics_ExposesPrometheusFormat(t *testing.T) {
+	_, router := setupMetricsTest()
+	serveMetricsRequest(router, http.MethodPost, "/api/v1/tasks")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+	body := w.Body.String()
+	assert.Contains(t, body, `http_requests_total{method="POST",route="/api/v1/tasks"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="POST",route="/api/v1/tasks",le="+Inf"} 1`)
+	assert.Contains(t, body, `todo_task_operations_total{operation="create"} 1`)
+}
@@ -1,30 +1,67 @@
 package middleware
 
 import (
+	"container/list"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
+	"todo-app/internal/config"
 )
 
+// IPRateLimiterConfig controls how long and how many client IPs are tracked
+type IPRateLimiterConfig struct {
+	IdleTTL    time.Duration // idle time after which an IP entry is evicted
+	MaxEntries int           // hard cap; least recently seen IPs are evicted first
+}
+
+// ipLimiterEntry pairs an IP's limiter with the time it was last used
+type ipLimiterEntry struct {
+	ip       string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
 // IPRateLimiter manages rate limiting per IP address
 type IPRateLimiter struct {
-	ips map[string]*rate.Limiter
-	mu  sync.RWMutex
-	r   rate.Limit // requests per second
-	b   int        // bucket size (burst)
+	ips    map[string]*list.Element // values are *ipLimiterEntry
+	recent *list.List               // most recently seen IPs at the front
+	mu     sync.RWMutex
+	r      rate.Limit // requests per second
+	b      int        // bucket size (burst)
+	config IPRateLimiterConfig
+	now    func() time.Time // clock, replaceable in tests
 }
 
 // NewIPRateLimiter creates a new IP-based rate limiter
 // r: rate limit (requests per second)
 // b: burst size (maximum tokens in bucket)
 func NewIPRateLimiter(r rate.Limit, b int) *IPRateLimiter {
+	return NewIPRateLimiterWithConfig(r, b, IPRateLimiterConfig{})
+}
+
+// NewIPRateLimiterWithConfig creates a new IP-based rate limiter with custom
+// eviction settings; zero values fall back to the configuration defaults
+func NewIPRateLimiterWithConfig(r rate.Limit, b int, settings IPRateLimiterConfig) *IPRateLimiter {
+	if settings.IdleTTL <= 0 {
+		settings.IdleTTL = config.DefaultIPRateLimitTTL
+	}
+	if settings.MaxEntries <= 0 {
+		settings.MaxEntries = config.DefaultIPRateLimitMaxEntries
+	}
+
 	limiter := &IPRateLimiter{
-		ips: make(map[string]*rate.Limiter),
-		r:   r,
-		b:   b,
+		ips:    make(map[string]*list.Element),
+		recent: list.New(),
+		r:      r,
+		b:      b,
+		config: settings,
+		now:    time.Now,
 	}
 
 	// Start cleanup goroutine to remove inactive IPs
@@ -39,62 +76,99 @@ func (i *IPRateLimiter) GetLimiter(ip string) *rate.Limiter {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	limiter, exists := i.ips[ip]
-	if !exists {
-		limiter = rate.NewLimiter(i.r, i.b)
-		i.ips[ip] = limiter
+	if element, exists := i.ips[ip]; exists {
+		entry := element.Value.(*ipLimiterEntry)
+		entry.lastSeen = i.now()
+		i.recent.MoveToFront(element)
+		return entry.limiter
 	}
 
-	return limiter
+	// Make room by dropping the least recently seen IPs
+	for len(i.ips) >= i.config.MaxEntries {
+		i.removeElement(i.recent.Back())
+	}
+
+	entry := &ipLimiterEntry{
+		ip:       ip,
+		limiter:  rate.NewLimiter(i.r, i.b),
+		lastSeen: i.now(),
+	}
+	i.ips[ip] = i.recent.PushFront(entry)
+
+	return entry.limiter
 }
 
-// cleanupInactive removes IP entries that haven't been used in 30 minutes
+// GetLimiterCount returns the number of IPs currently tracked
+func (i *IPRateLimiter) GetLimiterCount() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.ips)
+}
+
+// cleanupInactive periodically evicts IPs that have been idle longer than the TTL
 // Prevents memory leak from IP accumulation
 func (i *IPRateLimiter) cleanupInactive() {
-	ticker := time.NewTicker(10 * time.Minute)
+	ticker := time.NewTicker(i.config.IdleTTL / 3)
 	defer ticker.Stop()
 
 	for range ticker.C {
-		i.mu.Lock()
-		// Note: In production, you'd want to track last access time
-		// For now, we'll just periodically clear the map
-		// A more sophisticated approach would store access timestamps
-		if len(i.ips) > 10000 {
-			// Reset if too many IPs (memory protection)
-			i.ips = make(map[string]*rate.Limiter)
+		i.evictStale()
+	}
+}
+
+// evictStale removes IP entries not seen within the TTL and returns how many were removed
+func (i *IPRateLimiter) evictStale() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	cutoff := i.now().Add(-i.config.IdleTTL)
+	removed := 0
+
+	// Entries are ordered by last use, so stop at the first recent one
+	for element := i.recent.Back(); element != nil; element = i.recent.Back() {
+		if !element.Value.(*ipLimiterEntry).lastSeen.Before(cutoff) {
+			break
 		}
-		i.mu.Unlock()
+		i.removeElement(element)
+		removed++
 	}
+
+	return removed
 }
 
-// RateLimitMiddleware creates a Gin middleware for rate limiting
+// removeElement drops an entry from both the map and the recency list; callers hold mu
+func (i *IPRateLimiter) removeElement(element *list.Element) {
+	entry := i.recent.Remove(element).(*ipLimiterEntry)
+	delete(i.ips, entry.ip)
+}
+
+// RateLimitMiddleware creates a Gin middleware for rate limiting API routes.
+// Limited requests get a 429 JSON error with the wait in seconds.
 func (i *IPRateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := i.GetLimiter(ip)
-
-		if !limiter.Allow() {
-			// Calculate retry-after in seconds
-			reservation := limiter.Reserve()
-			delay := reservation.DelayFrom(time.Now())
-			retryAfter := int(delay.Seconds())
-			reservation.Cancel() // Cancel the reservation since we're rejecting
+		retryAfter, allowed := i.allowRequest(c, c.ClientIP())
+		if !allowed {
+			abortRateLimited(c, retryAfter)
+			return
+		}
 
-			c.Header("Retry-After", string(rune(retryAfter)))
+		c.Next()
+	}
+}
 
-			// Check if client wants JSON response
+// RedirectRateLimitMiddleware creates a rate limiting middleware for routes a
+// browser navigates to, such as OAuth logins. Limited browsers are redirected
+// to redirectURL with error=rate_limit_exceeded; JSON clients get the 429 error.
+func (i *IPRateLimiter) RedirectRateLimitMiddleware(redirectURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retryAfter, allowed := i.allowRequest(c, c.ClientIP())
+		if !allowed {
 			if c.GetHeader("Accept") == "application/json" {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":       "rate_limit_exceeded",
-					"message":     "Too many signup attempts. Please try again later.",
-					"retry_after": retryAfter,
-				})
-				c.Abort()
+				abortRateLimited(c, retryAfter)
 				return
 			}
 
-			// For HTML clients (browser), redirect to signup page with error
-			c.Redirect(http.StatusFound, "http://localhost:3000/signup?error=rate_limit_exceeded")
+			c.Redirect(http.StatusFound, rateLimitRedirectURL(redirectURL))
 			c.Abort()
 			return
 		}
@@ -102,3 +176,83 @@ func (i *IPRateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// abortRateLimited writes the 429 JSON error and stops the handler chain
+func abortRateLimited(c *gin.Context, retryAfter int) {
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":   "rate_limited",
+		"message": "Too many attempts. Please try again later.",
+		"details": gin.H{
+			"retry_after": retryAfter,
+		},
+	})
+	c.Abort()
+}
+
+// rateLimitRedirectURL adds error=rate_limit_exceeded to redirectURL's query
+func rateLimitRedirectURL(redirectURL string) string {
+	target, err := url.Parse(redirectURL)
+	if err != nil {
+		return redirectURL
+	}
+	query := target.Query()
+	query.Set("error", "rate_limit_exceeded")
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// allowRequest takes a token from the key's bucket and sets the rate limit
+// headers. When the bucket is empty it also sets Retry-After and returns the
+// wait in seconds with allowed set to false.
+func (i *IPRateLimiter) allowRequest(c *gin.Context, key string) (retryAfter int, allowed bool) {
+	limiter := i.GetLimiter(key)
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	limited := !reservation.OK() || delay > 0
+	if limited {
+		// Release the token since the request is rejected
+		reservation.Cancel()
+	}
+
+	i.setRateLimitHeaders(c, limiter)
+
+	if limited {
+		retryAfter = retryAfterSeconds(delay)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		return retryAfter, false
+	}
+
+	return 0, true
+}
+
+// setRateLimitHeaders reports the bucket size, tokens left, and the Unix time
+// at which the bucket will be full again
+func (i *IPRateLimiter) setRateLimitHeaders(c *gin.Context, limiter *rate.Limiter) {
+	now := time.Now()
+	tokens := limiter.TokensAt(now)
+
+	remaining := int(math.Floor(tokens))
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	reset := now
+	if missing := float64(i.b) - tokens; missing > 0 && i.r > 0 && i.r != rate.Inf {
+		reset = now.Add(time.Duration(missing / float64(i.r) * float64(time.Second)))
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(i.b))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(float64(reset.UnixMilli())/1000)), 10))
+}
+
+// retryAfterSeconds converts a wait duration into a Retry-After value,
+// rounding up to whole seconds with a minimum of 1
+func retryAfterSeconds(delay time.Duration) int {
+	seconds := int(math.Ceil(delay.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
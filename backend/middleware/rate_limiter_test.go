@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func setupRateLimitedRouter(limiter *IPRateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auth/google/login", limiter.RateLimitMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func performLoginRequest(router *gin.Engine) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/auth/google/login", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_SetsRetryAfterOnRejection(t *testing.T) {
+	// One token every 10 seconds with a burst of 1
+	router := setupRateLimitedRouter(NewIPRateLimiter(rate.Every(10*time.Second), 1))
+
+	first := performLoginRequest(router)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Empty(t, first.Header().Get("Retry-After"))
+
+	second := performLoginRequest(router)
+	require.Equal(t, http.StatusTooManyRequests, second.Code)
+
+	retryAfter, err := strconv.Atoi(second.Header().Get("Retry-After"))
+	require.NoError(t, err, "Retry-After must be an integer number of seconds")
+	assert.Equal(t, 10, retryAfter)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(second.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body["error"])
+	assert.NotEmpty(t, body["message"])
+	assert.Equal(t, float64(10), body["details"].(map[string]interface{})["retry_after"])
+}
+
+func TestRateLimitMiddleware_RejectionDoesNotConsumeTokens(t *testing.T) {
+	limiter := NewIPRateLimiter(rate.Every(10*time.Second), 1)
+	router := setupRateLimitedRouter(limiter)
+
+	require.Equal(t, http.StatusOK, performLoginRequest(router).Code)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusTooManyRequests, performLoginRequest(router).Code)
+	}
+
+	// Rejected requests must not push the next available token further out
+	retryAfter, err := strconv.Atoi(performLoginRequest(router).Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, retryAfter, 10)
+}
+
+func TestRetryAfterSeconds_RoundsUp(t *testing.T) {
+	assert.Equal(t, 1, retryAfterSeconds(0))
+	assert.Equal(t, 1, retryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 2, retryAfterSeconds(1001*time.Millisecond))
+	assert.Equal(t, 5, retryAfterSeconds(5*time.Second))
+}
+
+func TestIPRateLimiter_EvictsIdleIPs(t *testing.T) {
+	limiter := NewIPRateLimiterWithConfig(rate.Every(time.Second), 1, IPRateLimiterConfig{IdleTTL: 10 * time.Minute})
+	current := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return current }
+
+	for i := 0; i < 1000; i++ {
+		limiter.GetLimiter("10.0.0." + strconv.Itoa(i))
+	}
+	require.Equal(t, 1000, limiter.GetLimiterCount())
+
+	// Half the IPs stay active partway through the TTL
+	current = current.Add(6 * time.Minute)
+	for i := 0; i < 500; i++ {
+		limiter.GetLimiter("10.0.0." + strconv.Itoa(i))
+	}
+
+	current = current.Add(5 * time.Minute)
+	assert.Equal(t, 500, limiter.evictStale())
+	assert.Equal(t, 500, limiter.GetLimiterCount())
+
+	current = current.Add(11 * time.Minute)
+	assert.Equal(t, 500, limiter.evictStale())
+	assert.Equal(t, 0, limiter.GetLimiterCount())
+}
+
+func TestIPRateLimiter_KeepsStateForActiveIPs(t *testing.T) {
+	limiter := NewIPRateLimiterWithConfig(rate.Every(time.Hour), 1, IPRateLimiterConfig{IdleTTL: time.Minute})
+
+	first := limiter.GetLimiter("192.0.2.1")
+	require.True(t, first.Allow())
+
+	assert.Equal(t, 0, limiter.evictStale())
+	assert.Same(t, first, limiter.GetLimiter("192.0.2.1"))
+}
+
+func TestIPRateLimiter_CapEvictsLeastRecentlySeen(t *testing.T) {
+	limiter := NewIPRateLimiterWithConfig(rate.Every(time.Second), 1, IPRateLimiterConfig{MaxEntries: 3})
+
+	limiter.GetLimiter("192.0.2.1")
+	limiter.GetLimiter("192.0.2.2")
+	limiter.GetLimiter("192.0.2.3")
+
+	// Touch the oldest entry so 192.0.2.2 becomes least recently seen
+	limiter.GetLimiter("192.0.2.1")
+	limiter.GetLimiter("192.0.2.4")
+
+	assert.Equal(t, 3, limiter.GetLimiterCount())
+	assert.Contains(t, limiter.ips, "192.0.2.1")
+	assert.NotContains(t, limiter.ips, "192.0.2.2")
+	assert.Contains(t, limiter.ips, "192.0.2.3")
+	assert.Contains(t, limiter.ips, "192.0.2.4")
+}
+
+func TestRateLimitMiddleware_ForwardedHeadersFromTrustedProxy(t *testing.T) {
+	limiter := NewIPRateLimiter(rate.Every(time.Hour), 1)
+	router := setupRateLimitedRouter(limiter)
+	require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.1"}))
+
+	request := func(remoteAddr string, headers map[string]string) int {
+		req := httptest.NewRequest(http.MethodGet, "/auth/google/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Accept", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Clients behind the trusted proxy get their own buckets
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:4000", map[string]string{"X-Forwarded-For": "203.0.113.5"}))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:4000", map[string]string{"X-Forwarded-For": "203.0.113.5"}))
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:4000", map[string]string{"X-Real-IP": "203.0.113.6"}))
+	assert.Contains(t, limiter.ips, "203.0.113.5")
+	assert.Contains(t, limiter.ips, "203.0.113.6")
+	assert.NotContains(t, limiter.ips, "10.0.0.1")
+
+	// A client-supplied hop in front of the proxy's entry cannot pick the bucket
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:4000", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.5"}))
+	assert.NotContains(t, limiter.ips, "198.51.100.1")
+
+	// Untrusted peers cannot spoof their address with forwarded headers
+	assert.Equal(t, http.StatusOK, request("192.0.2.50:5000", map[string]string{"X-Forwarded-For": "203.0.113.77"}))
+	assert.Equal(t, http.StatusTooManyRequests, request("192.0.2.50:5000", map[string]string{"X-Forwarded-For": "203.0.113.78"}))
+	assert.Contains(t, limiter.ips, "192.0.2.50")
+	assert.NotContains(t, limiter.ips, "203.0.113.77")
+}
+
+func TestRateLimitMiddleware_SetsRateLimitHeaders(t *testing.T) {
+	// Burst of 3, refilling one token every 20 seconds
+	router := setupRateLimitedRouter(NewIPRateLimiter(rate.Every(20*time.Second), 3))
+	start := time.Now()
+
+	for expectedRemaining := 2; expectedRemaining >= 0; expectedRemaining-- {
+		w := performLoginRequest(router)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(expectedRemaining), w.Header().Get("X-RateLimit-Remaining"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+	}
+
+	// Burst exhausted
+	w := performLoginRequest(router)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Next token arrives within one refill interval
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, retryAfter, 19)
+	assert.LessOrEqual(t, retryAfter, 20)
+
+	// Bucket is full again after three refill intervals
+	reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, start.Add(60*time.Second).Unix(), reset, 2)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body["error"])
+	assert.NotEmpty(t, body["message"])
+}
+
+func TestRateLimitMiddleware_ReturnsJSONWithoutAcceptHeader(t *testing.T) {
+	router := setupRateLimitedRouter(NewIPRateLimiter(rate.Every(10*time.Second), 1))
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/google/login", nil))
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, request().Code)
+	w := request()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body["error"])
+}
+
+func TestRedirectRateLimitMiddleware_RedirectsBrowsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewIPRateLimiter(rate.Every(10*time.Second), 1)
+	router := gin.New()
+	router.GET("/auth/google/login", limiter.RedirectRateLimitMiddleware("https://app.example.com/login?from=oauth"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/auth/google/login", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, request("").Code)
+
+	w := request("text/html")
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/login?error=rate_limit_exceeded&from=oauth", w.Header().Get("Location"))
+
+	w = request("application/json")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
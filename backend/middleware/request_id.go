@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"todo-app/internal/logging"
+)
+
+// RequestIDHeader carries the request ID on requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestID assigns every request a correlation ID. A well-formed incoming
+// X-Request-ID is reused so IDs can be traced across services; otherwise a
+// random UUID is generated. The ID is stored on the Gin context and the request
+// context, and echoed in the response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// GetRequestID retrieves the current request ID from context
+func GetRequestID(c *gin.Context) (string, bool) {
+	requestID, exists := c.Get("request_id")
+	if !exists {
+		return "", false
+	}
+	if id, ok := requestID.(string); ok && id != "" {
+		return id, true
+	}
+	return "", false
+}
+
+// isValidRequestID accepts short IDs of visible ASCII characters so
+// client-supplied values cannot inject content into log lines
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random (version 4) UUID
+func newRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error on supported platforms
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
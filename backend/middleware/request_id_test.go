@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"todo-app/internal/logging"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// performRequestIDRequest returns the response and the request ID seen by the handler
+func performRequestIDRequest(incoming string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var seen string
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		seen, _ = GetRequestID(c)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if incoming != "" {
+		req.Header.Set(RequestIDHeader, incoming)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestRequestID_GeneratesUUIDWhenMissing(t *testing.T) {
+	w, seen := performRequestIDRequest("")
+
+	assert.Regexp(t, uuidPattern, seen)
+	assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+
+	// Every request gets its own ID
+	_, other := performRequestIDRequest("")
+	assert.NotEqual(t, seen, other)
+}
+
+func TestRequestID_ReusesIncomingHeader(t *testing.T) {
+	w, seen := performRequestIDRequest("upstream-abc.123")
+
+	assert.Equal(t, "upstream-abc.123", seen)
+	assert.Equal(t, "upstream-abc.123", w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestID_StoredOnRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var seen string
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "upstream-abc.123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "upstream-abc.123", seen)
+}
+
+func TestRequestID_ReplacesMalformedIncomingHeader(t *testing.T) {
+	for _, incoming := range []string{"has space", "tab\there", strings.Repeat("a", maxRequestIDLength+1)} {
+		w, seen := performRequestIDRequest(incoming)
+
+		assert.Regexp(t, uuidPattern, seen, incoming)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestGetRequestID_MissingWithoutMiddleware(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, ok := GetRequestID(c)
+	assert.False(t, ok)
+}
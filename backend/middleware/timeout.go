@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutConfig configures request deadlines
+type TimeoutConfig struct {
+	// Timeout is how long a request may run; 0 disables the deadline
+	Timeout time.Duration
+
+	// PathTimeouts replace Timeout for particular request paths, such as
+	// bulk endpoints that are expected to take longer; 0 disables the deadline
+	PathTimeouts map[string]time.Duration
+
+	// ExcludedPaths are request paths that get no deadline, such as
+	// long-lived streaming endpoints
+	ExcludedPaths []string
+}
+
+// Timeout puts a deadline on each request's context, so database queries and
+// other work that honour the context are abandoned once it passes instead of
+// holding the request open; transactions begun with the context are rolled
+// back. Handlers that ignore the context are not interrupted.
+func Timeout(config TimeoutConfig) gin.HandlerFunc {
+	excluded := make(map[string]struct{}, len(config.ExcludedPaths))
+	for _, path := range config.ExcludedPaths {
+		excluded[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		timeout := config.Timeout
+		if pathTimeout, ok := config.PathTimeouts[c.Request.URL.Path]; ok {
+			timeout = pathTimeout
+		}
+		if _, ok := excluded[c.Request.URL.Path]; ok || timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
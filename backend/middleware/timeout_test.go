@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// performTimeoutRequest reports whether the handler for path saw a deadline
+// on its request context, and how far off it was
+func performTimeoutRequest(timeout time.Duration, path string) (hasDeadline bool, remaining time.Duration) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(TimeoutConfig{
+		Timeout:       timeout,
+		PathTimeouts:  map[string]time.Duration{"/bulk": time.Minute},
+		ExcludedPaths: []string{"/excluded"},
+	}))
+	handler := func(c *gin.Context) {
+		var deadline time.Time
+		deadline, hasDeadline = c.Request.Context().Deadline()
+		remaining = time.Until(deadline)
+		c.Status(http.StatusNoContent)
+	}
+	router.GET("/test", handler)
+	router.GET("/excluded", handler)
+	router.GET("/bulk", handler)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	return hasDeadline, remaining
+}
+
+func TestTimeout_SetsRequestDeadline(t *testing.T) {
+	hasDeadline, remaining := performTimeoutRequest(5*time.Second, "/test")
+
+	assert.True(t, hasDeadline)
+	assert.LessOrEqual(t, remaining, 5*time.Second)
+	assert.Greater(t, remaining, 4*time.Second)
+}
+
+func TestTimeout_SkipsExcludedPaths(t *testing.T) {
+	hasDeadline, _ := performTimeoutRequest(5*time.Second, "/excluded")
+
+	assert.False(t, hasDeadline)
+}
+
+func TestTimeout_PathTimeoutReplacesDefault(t *testing.T) {
+	hasDeadline, remaining := performTimeoutRequest(5*time.Second, "/bulk")
+
+	assert.True(t, hasDeadline)
+	assert.Greater(t, remaining, 55*time.Second)
+
+	// The longer deadline applies even when the default is disabled
+	hasDeadline, _ = performTimeoutRequest(0, "/bulk")
+	assert.True(t, hasDeadline)
+}
+
+func TestTimeout_ZeroDisablesDeadline(t *testing.T) {
+	hasDeadline, _ := performTimeoutRequest(0, "/test")
+
+	assert.False(t, hasDeadline)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// UserRateLimiter rate limits requests per authenticated user, falling back to
+// the client IP for anonymous requests
+type UserRateLimiter struct {
+	limiters *IPRateLimiter
+}
+
+// NewUserRateLimiter creates a new per-user rate limiter
+// r: rate limit (requests per second)
+// burst: maximum requests allowed at once
+func NewUserRateLimiter(r rate.Limit, burst int) *UserRateLimiter {
+	return &UserRateLimiter{
+		limiters: NewIPRateLimiter(r, burst),
+	}
+}
+
+// RateLimitMiddleware creates a Gin middleware limiting requests per user.
+// It must run after the auth middleware so the user ID is in the context.
+func (u *UserRateLimiter) RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retryAfter, allowed := u.limiters.allowRequest(c, rateLimitKey(c))
+		if !allowed {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "rate_limited",
+				"message": "Too many requests. Please try again later.",
+				"details": gin.H{
+					"retry_after": retryAfter,
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the caller by user ID, or by client IP when unauthenticated
+func rateLimitKey(c *gin.Context) string {
+	if userID, ok := GetCurrentUserID(c); ok {
+		return "user:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "ip:" + c.ClientIP()
+}
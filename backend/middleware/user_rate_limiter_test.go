@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// setupUserRateLimitedRouter authenticates requests from the X-Test-User header
+func setupUserRateLimitedRouter(limiter *UserRateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		if header := c.GetHeader("X-Test-User"); header != "" {
+			id, _ := strconv.ParseUint(header, 10, 32)
+			c.Set("user_id", uint(id))
+		}
+		c.Next()
+	}
+	router.POST("/tasks", authenticate, limiter.RateLimitMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func performTaskWrite(router *gin.Engine, userID string, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	if remoteAddr != "" {
+		req.RemoteAddr = remoteAddr
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserRateLimiter_UsersHaveSeparateBuckets(t *testing.T) {
+	router := setupUserRateLimitedRouter(NewUserRateLimiter(rate.Every(time.Minute), 2))
+
+	// User 1 exhausts their burst
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "1", "").Code)
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "1", "").Code)
+
+	limited := performTaskWrite(router, "1", "")
+	require.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(limited.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body["error"])
+
+	// User 2 shares the same client IP but has their own bucket
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "2", "").Code)
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "2", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, performTaskWrite(router, "2", "").Code)
+}
+
+func TestUserRateLimiter_FallsBackToClientIP(t *testing.T) {
+	router := setupUserRateLimitedRouter(NewUserRateLimiter(rate.Every(time.Minute), 1))
+
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "", "192.0.2.1:1000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, performTaskWrite(router, "", "192.0.2.1:1000").Code)
+
+	// Other anonymous clients and authenticated users are unaffected
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "", "192.0.2.2:1000").Code)
+	assert.Equal(t, http.StatusCreated, performTaskWrite(router, "1", "192.0.2.1:1000").Code)
+}
+
+func TestUserRateLimiter_ConcurrentRequestsRespectBurst(t *testing.T) {
+	router := setupUserRateLimitedRouter(NewUserRateLimiter(rate.Every(time.Hour), 20))
+
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if performTaskWrite(router, "7", "").Code == http.StatusCreated {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(20), atomic.LoadInt32(&allowed))
+}
@@ -0,0 +1,26 @@
+// Package migrations holds the versioned SQL schema migrations.
+//
+// Each supported database has its own directory (sqlite, postgres) with the
+// same sequence of migrations, written as pairs of files named
+// NNN_description.up.sql and NNN_description.down.sql. Versions are applied
+// in ascending order and recorded in the schema_migrations table.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// ForDialect returns the migrations for a GORM dialector name ("sqlite" or "postgres")
+func ForDialect(dialect string) (fs.FS, error) {
+	switch dialect {
+	case "sqlite", "postgres":
+		return fs.Sub(files, dialect)
+	default:
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+}
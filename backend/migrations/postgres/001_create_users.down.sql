@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_oauth_identity;
+DROP INDEX IF EXISTS idx_users_google_id;
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,23 @@
+-- Migration: Create users table
+-- Description: Users with password and provider-neutral OAuth authentication
+-- Baseline migrations use IF NOT EXISTS so databases created by GORM AutoMigrate adopt them cleanly
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255),
+    auth_method VARCHAR(50) NOT NULL DEFAULT 'password',   -- "password", "google", "github" or "hybrid"
+    google_id VARCHAR(255),                                -- Legacy Google account ID
+    oauth_provider VARCHAR(50),
+    oauth_created_at TIMESTAMPTZ,
+    oauth_external_id VARCHAR(255),                        -- Account ID at oauth_provider
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users(google_id);
+-- Each OAuth account belongs to one user; users without one store an empty ID
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oauth_identity ON users(oauth_provider, oauth_external_id) WHERE oauth_external_id <> '';
@@ -0,0 +1,20 @@
+-- Migration: Create tasks table
+-- Description: User-owned TODO items
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) DEFAULT 'medium',
+    status VARCHAR(20) DEFAULT 'pending',                  -- "pending", "completed" or "archived"
+    completed BOOLEAN DEFAULT false,
+    due_date TIMESTAMPTZ,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
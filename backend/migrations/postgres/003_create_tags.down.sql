@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_tags;
+DROP INDEX IF EXISTS idx_tags_user_name;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,19 @@
+-- Migration: Create tags and task_tags tables
+-- Description: User-scoped task labels with a many-to-many join to tasks
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(name, user_id);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id BIGINT,
+    tag_id BIGINT,
+    PRIMARY KEY (task_id, tag_id),
+    CONSTRAINT fk_task_tags_task FOREIGN KEY (task_id) REFERENCES tasks(id),
+    CONSTRAINT fk_task_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(id)
+);
@@ -0,0 +1,23 @@
+-- Migration: Create authentication_sessions table
+-- Description: Session management for OAuth and traditional authentication
+
+CREATE TABLE IF NOT EXISTS authentication_sessions (
+    id VARCHAR(255),                                       -- Session identifier
+    user_id BIGINT NOT NULL,
+    session_token TEXT NOT NULL,                           -- JWT session token
+    refresh_token TEXT,                                    -- OAuth refresh token (encrypted)
+    access_token TEXT,                                     -- OAuth access token (encrypted)
+    token_expires_at TIMESTAMPTZ,                          -- OAuth token expiration time
+    provider_session_id VARCHAR(255),                      -- Identity provider session ID (OIDC "sid")
+    session_expires_at TIMESTAMPTZ NOT NULL,
+    last_activity TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authentication_sessions_session_token ON authentication_sessions(session_token);
+CREATE INDEX IF NOT EXISTS idx_authentication_sessions_user_id ON authentication_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_authentication_sessions_session_expires_at ON authentication_sessions(session_expires_at);
+CREATE INDEX IF NOT EXISTS idx_authentication_sessions_provider_session_id ON authentication_sessions(provider_session_id);
@@ -0,0 +1,14 @@
+-- Migration: Create oauth_states table
+-- Description: Temporary storage for OAuth flow state validation (CSRF protection)
+
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state_token VARCHAR(255),                              -- Random state parameter for CSRF
+    pkce_verifier VARCHAR(255) NOT NULL,                   -- PKCE code verifier
+    redirect_uri VARCHAR(1000) NOT NULL,                   -- Post-auth redirect destination
+    created_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (state_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires_at ON oauth_states(expires_at);
+CREATE INDEX IF NOT EXISTS idx_oauth_states_created_at ON oauth_states(created_at);
@@ -0,0 +1,16 @@
+-- Migration: Create google_identities table
+-- Description: Google account links created by the Google signup flow
+
+CREATE TABLE IF NOT EXISTS google_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    google_user_id VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    email_verified BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_google_identity FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_google_identities_user_id ON google_identities(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_google_identities_google_user_id ON google_identities(google_user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_login_events_created_at;
+DROP INDEX IF EXISTS idx_login_events_session_id;
+DROP INDEX IF EXISTS idx_login_events_user_id;
+DROP TABLE IF EXISTS login_events;
@@ -0,0 +1,16 @@
+-- Migration: Create login_events table
+-- Description: Persistent login history backing the user security log
+
+CREATE TABLE IF NOT EXISTS login_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    session_id VARCHAR(255),                               -- Session created by this login
+    is_o_auth BOOLEAN DEFAULT false,                       -- Whether the session carries OAuth tokens
+    user_agent TEXT,
+    ip_address VARCHAR(45),                                -- Client IP, anonymized on read
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_events_user_id ON login_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_login_events_session_id ON login_events(session_id);
+CREATE INDEX IF NOT EXISTS idx_login_events_created_at ON login_events(created_at);
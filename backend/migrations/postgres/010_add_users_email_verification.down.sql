@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_verification_token;
+ALTER TABLE users DROP COLUMN verification_token_expires_at;
+ALTER TABLE users DROP COLUMN verification_token;
+ALTER TABLE users DROP COLUMN email_verified;
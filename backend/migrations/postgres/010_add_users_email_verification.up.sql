@@ -0,0 +1,11 @@
+-- Migration: Add email verification to users
+-- Description: Password registrations must verify their email with a single-use token
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_token VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_token_expires_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_verification_token ON users(verification_token);
+
+-- Existing accounts predate verification and stay usable
+UPDATE users SET email_verified = TRUE;
@@ -0,0 +1,16 @@
+-- Migration: Create pending_account_links table
+-- Description: OAuth sign-ins matching an existing account by email, awaiting confirmation by that account
+
+CREATE TABLE IF NOT EXISTS pending_account_links (
+    token VARCHAR(255),                                    -- Random token returned to the OAuth client
+    user_id BIGINT NOT NULL,                                 -- Existing account the provider account would be linked to
+    provider VARCHAR(50) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,                     -- Account ID at the provider
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_account_links_user_id ON pending_account_links(user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_account_links_expires_at ON pending_account_links(expires_at);
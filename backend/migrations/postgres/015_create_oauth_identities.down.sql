@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_oauth_identities_provider_account;
+DROP INDEX IF EXISTS idx_oauth_identities_user_provider;
+DROP TABLE IF EXISTS oauth_identities;
@@ -0,0 +1,37 @@
+-- Migration: Create oauth_identities table
+-- Description: OAuth provider accounts linked to users, one per provider, so a user can sign in with several providers
+
+CREATE TABLE IF NOT EXISTS oauth_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,                     -- Account ID at the provider
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_oauth_identities FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_identities_user_provider ON oauth_identities(user_id, provider);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_identities_provider_account ON oauth_identities(provider, external_id);
+
+-- Accounts linked on the user row; Google accounts linked before oauth_external_id existed only have google_id
+INSERT INTO oauth_identities (user_id, provider, external_id, created_at)
+SELECT id, oauth_provider, oauth_external_id, COALESCE(oauth_created_at, created_at)
+FROM users
+WHERE oauth_provider IS NOT NULL AND oauth_provider <> ''
+  AND oauth_external_id IS NOT NULL AND oauth_external_id <> '';
+
+INSERT INTO oauth_identities (user_id, provider, external_id, created_at)
+SELECT id, 'google', google_id, COALESCE(oauth_created_at, created_at)
+FROM users
+WHERE google_id IS NOT NULL AND google_id <> ''
+  AND NOT EXISTS (SELECT 1 FROM oauth_identities WHERE oauth_identities.user_id = users.id AND oauth_identities.provider = 'google');
+
+-- Accounts created by the Google signup flow
+INSERT INTO oauth_identities (user_id, provider, external_id, created_at)
+SELECT user_id, 'google', google_user_id, created_at
+FROM google_identities
+WHERE NOT EXISTS (
+    SELECT 1 FROM oauth_identities
+    WHERE oauth_identities.provider = 'google'
+      AND (oauth_identities.user_id = google_identities.user_id OR oauth_identities.external_id = google_identities.google_user_id)
+);
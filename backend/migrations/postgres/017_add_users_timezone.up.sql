@@ -0,0 +1,4 @@
+-- Migration: Add timezone to users
+-- Description: IANA timezone from the user's profile, used to interpret date-only due dates and show local times
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
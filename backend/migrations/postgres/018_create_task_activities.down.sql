@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_activities_user_id;
+DROP INDEX IF EXISTS idx_task_activities_task_id_created_at;
+DROP TABLE IF EXISTS task_activities;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_user_id_status;
+ALTER TABLE users DROP COLUMN task_limit;
@@ -0,0 +1,23 @@
+-- Migration: Add task reminders
+-- Description: A remind_at time on tasks and the notifications that deliver it; notifications have no foreign key so sent ones outlive deleted tasks
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remind_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_remind_at ON tasks(remind_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    task_id BIGINT NOT NULL,
+    channel VARCHAR(20) NOT NULL,                          -- log or email
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',         -- pending, sent, skipped, cancelled or failed
+    attempts INTEGER NOT NULL DEFAULT 0,                   -- Failed deliveries so far
+    last_error TEXT,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_status_scheduled_for ON notifications(status, scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_task_id ON notifications(task_id);
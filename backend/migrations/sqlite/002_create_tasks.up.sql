@@ -0,0 +1,20 @@
+-- Migration: Create tasks table
+-- Description: User-owned TODO items
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) DEFAULT 'medium',
+    status VARCHAR(20) DEFAULT 'pending',                  -- "pending", "completed" or "archived"
+    completed NUMERIC DEFAULT false,
+    due_date DATETIME,
+    user_id INTEGER NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_authentication_sessions_provider_session_id;
+DROP INDEX IF EXISTS idx_authentication_sessions_session_expires_at;
+DROP INDEX IF EXISTS idx_authentication_sessions_user_id;
+DROP INDEX IF EXISTS idx_authentication_sessions_session_token;
+DROP TABLE IF EXISTS authentication_sessions;
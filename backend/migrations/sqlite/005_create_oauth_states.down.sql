@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_oauth_states_created_at;
+DROP INDEX IF EXISTS idx_oauth_states_expires_at;
+DROP TABLE IF EXISTS oauth_states;
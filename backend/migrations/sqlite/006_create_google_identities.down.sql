@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_google_identities_google_user_id;
+DROP INDEX IF EXISTS idx_google_identities_user_id;
+DROP TABLE IF EXISTS google_identities;
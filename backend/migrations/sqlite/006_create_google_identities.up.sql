@@ -0,0 +1,16 @@
+-- Migration: Create google_identities table
+-- Description: Google account links created by the Google signup flow
+
+CREATE TABLE IF NOT EXISTS google_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    google_user_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    email_verified NUMERIC NOT NULL DEFAULT false,
+    created_at DATETIME,
+    updated_at DATETIME,
+    CONSTRAINT fk_users_google_identity FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_google_identities_user_id ON google_identities(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_google_identities_google_user_id ON google_identities(google_user_id);
@@ -0,0 +1,6 @@
+-- Migration: Add deleted_at to tasks
+-- Description: Soft deletes; tasks with deleted_at set are in the trash
+
+ALTER TABLE tasks ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at);
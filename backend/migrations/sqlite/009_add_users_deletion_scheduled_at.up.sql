@@ -0,0 +1,6 @@
+-- Migration: Add deletion_scheduled_at to users
+-- Description: Accounts pending deletion are purged once this time has passed
+
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at);
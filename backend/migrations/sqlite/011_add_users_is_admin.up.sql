@@ -0,0 +1,4 @@
+-- Migration: Add is_admin to users
+-- Description: Admins can manage other users' accounts through the /admin API
+
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- The original casing of emails is not kept, so this migration cannot be reverted
+SELECT 1;
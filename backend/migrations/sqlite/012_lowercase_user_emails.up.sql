@@ -0,0 +1,13 @@
+-- Migration: Lowercase user emails
+-- Description: Emails are now stored and matched trimmed and lowercase. Existing
+-- mixed-case emails are normalized unless another account differs from them only
+-- in case; such duplicates must be merged by hand before they can sign in by email.
+
+UPDATE users
+SET email = LOWER(TRIM(email))
+WHERE email <> LOWER(TRIM(email))
+  AND NOT EXISTS (
+    SELECT 1 FROM users other
+    WHERE other.id <> users.id
+      AND LOWER(TRIM(other.email)) = LOWER(TRIM(users.email))
+  );
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_event_type;
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,16 @@
+-- Migration: Create audit_logs table
+-- Description: Security-relevant events (logins, logouts, account linking, task deletions) for admins to review
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,                                          -- NULL when the actor is unknown, e.g. a failed login
+    event_type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    metadata TEXT ,                                         -- Event-specific details as a JSON object
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_event_type ON audit_logs(event_type);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at);
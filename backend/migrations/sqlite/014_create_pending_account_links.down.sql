@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pending_account_links_expires_at;
+DROP INDEX IF EXISTS idx_pending_account_links_user_id;
+DROP TABLE IF EXISTS pending_account_links;
@@ -0,0 +1,16 @@
+-- Migration: Create password_reset_tokens table
+-- Description: Single-use tokens emailed to users who forgot their password; only the token's hash is stored
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,                       -- Hex SHA-256 of the emailed token
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,                                      -- Set once the token has reset a password
+    created_at DATETIME,
+    CONSTRAINT fk_users_password_reset_tokens FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token_hash ON password_reset_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_expires_at ON password_reset_tokens(expires_at);
@@ -0,0 +1,16 @@
+-- Migration: Create task_activities table
+-- Description: Per-task history of changes; rows have no foreign key so they outlive deleted tasks for audit
+
+CREATE TABLE IF NOT EXISTS task_activities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,                              -- The user who acted on the task
+    action VARCHAR(20) NOT NULL,                           -- created, updated, completed, archived, reopened, deleted, restored or purged
+    field VARCHAR(20) NOT NULL,                            -- The changed field; empty for actions on the whole task
+    old_value TEXT,
+    new_value TEXT,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_activities_task_id_created_at ON task_activities(task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_task_activities_user_id ON task_activities(user_id);
@@ -0,0 +1,6 @@
+-- Migration: Add task limits
+-- Description: Per-user override of the task quota (MAX_TASKS_PER_USER), and an index that answers the count it is checked against
+
+ALTER TABLE users ADD COLUMN task_limit INTEGER;           -- NULL uses MAX_TASKS_PER_USER; 0 means unlimited
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id_status ON tasks(user_id, deleted_at, status, completed);
@@ -0,0 +1,4 @@
+-- Migration: Add task versions
+-- Description: Count saves of each task so concurrent updates are detected instead of overwriting each other
+
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
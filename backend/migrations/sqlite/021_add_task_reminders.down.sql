@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_task_id;
+DROP INDEX IF EXISTS idx_notifications_user_id_created_at;
+DROP INDEX IF EXISTS idx_notifications_status_scheduled_for;
+DROP TABLE IF EXISTS notifications;
+DROP INDEX IF EXISTS idx_tasks_remind_at;
+ALTER TABLE tasks DROP COLUMN remind_at;
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one request field that failed validation
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // JSON path of the field, e.g. profile.first_name
+	Rule    string `json:"rule"`            // binding rule that failed, e.g. required or max
+	Message string `json:"message"`         // human-readable description
+}
+
+func init() {
+	// Report fields by their JSON names rather than Go struct field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// bindErrorResponse builds the 400 response for a request body that could not be
+// bound. Validation failures list each offending field in Details; anything
+// else, such as malformed JSON, gets a single generic message.
+func bindErrorResponse(err error) ErrorResponse {
+	response := ErrorResponse{
+		Error:   "invalid_request",
+		Code:    CodeBadRequest,
+		Message: "Invalid request format",
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		response.Details = []FieldError{{Rule: "format", Message: "Request body must be valid JSON matching the expected fields"}}
+		return response
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	response.Details = fieldErrors
+	return response
+}
+
+// fieldPath returns the field's JSON path without the request struct name
+func fieldPath(fe validator.FieldError) string {
+	parts := strings.SplitN(fe.Namespace(), ".", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return fe.Field()
+}
+
+// fieldErrorMessage describes a failed rule in plain language
+func fieldErrorMessage(fe validator.FieldError) string {
+	field := fieldPath(fe)
+
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s%s", field, fe.Param(), lengthUnit(fe))
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", field, fe.Param(), lengthUnit(fe))
+	default:
+		return field + " is invalid"
+	}
+}
+
+// lengthUnit names what min and max count for the field's kind
+func lengthUnit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-app/application/apperrors"
+	"todo-app/application/task"
+	"todo-app/middleware"
+)
+
+// Machine-readable error codes returned in ErrorResponse.Code
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeValidation   = string(apperrors.KindValidation)
+	CodeNotFound     = string(apperrors.KindNotFound)
+	CodeForbidden    = string(apperrors.KindForbidden)
+	CodeConflict     = string(apperrors.KindConflict)
+	CodeQuota        = string(apperrors.KindQuota)
+	CodeUnavailable  = string(apperrors.KindUnavailable)
+	CodeInternal     = "internal"
+	CodeTimeout      = "request_timeout"
+
+	CodeMethodNotAllowed = "method_not_allowed"
+)
+
+// ErrorHandler recovers from panics and renders errors that handlers record
+// with c.Error, mapping classified application errors to HTTP statuses.
+// Unclassified errors and panics become a generic 500 that never exposes
+// internal details; they are logged with the request ID instead. The request
+// ID assigned by middleware.RequestID is included in every error body.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				// Handlers abort a response already under way with
+				// http.ErrAbortHandler; net/http closes the connection quietly
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				slog.Error("panic recovered",
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"request_id", requestIDForLog(c),
+					"panic", recovered,
+					"stack", string(debug.Stack()),
+				)
+
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				response := internalErrorResponse()
+				response.RequestID, _ = middleware.GetRequestID(c)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, response := errorResponseFor(err)
+		switch status {
+		case http.StatusInternalServerError:
+			slog.Error("request failed",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"request_id", requestIDForLog(c),
+				"error", err,
+			)
+		case http.StatusGatewayTimeout:
+			slog.Warn("request timed out",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"request_id", requestIDForLog(c),
+				"error", err,
+			)
+		}
+
+		response.RequestID, _ = middleware.GetRequestID(c)
+		c.JSON(status, response)
+	}
+}
+
+// NotFoundHandler answers requests for paths no route matches with a JSON
+// 404, in place of Gin's plain text; register it with router.NoRoute
+func NotFoundHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondWithError(c, http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Code:    CodeNotFound,
+			Message: fmt.Sprintf("No endpoint matches %s", c.Request.URL.Path),
+		})
+	}
+}
+
+// MethodNotAllowedHandler answers requests whose path exists but not for the
+// request method with a JSON 405; register it with router.NoMethod and set
+// router.HandleMethodNotAllowed
+func MethodNotAllowedHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondWithError(c, http.StatusMethodNotAllowed, ErrorResponse{
+			Error:   "method_not_allowed",
+			Code:    CodeMethodNotAllowed,
+			Message: fmt.Sprintf("%s is not supported for %s", c.Request.Method, c.Request.URL.Path),
+		})
+	}
+}
+
+// respondWithError writes an error body a handler built itself, adding the
+// request ID as ErrorHandler does for the errors it renders
+func respondWithError(c *gin.Context, status int, response ErrorResponse) {
+	response.RequestID, _ = middleware.GetRequestID(c)
+	c.JSON(status, response)
+}
+
+// errorResponseFor maps an error to its HTTP status and response body
+func errorResponseFor(err error) (int, ErrorResponse) {
+	// The request deadline passed, e.g. while a slow query was running
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, ErrorResponse{
+			Error:   "request_timeout",
+			Code:    CodeTimeout,
+			Message: "The request took too long to complete",
+		}
+	}
+
+	appErr, ok := apperrors.As(err)
+	if !ok {
+		return http.StatusInternalServerError, internalErrorResponse()
+	}
+
+	var status int
+	switch appErr.Kind {
+	case apperrors.KindValidation:
+		status = http.StatusUnprocessableEntity
+	case apperrors.KindNotFound:
+		status = http.StatusNotFound
+	case apperrors.KindForbidden:
+		status = http.StatusForbidden
+	case apperrors.KindConflict:
+		status = http.StatusConflict
+	case apperrors.KindQuota:
+		status = http.StatusUnprocessableEntity
+	case apperrors.KindUnavailable:
+		status = http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError, internalErrorResponse()
+	}
+
+	response := ErrorResponse{
+		Error:   appErr.Reason,
+		Code:    string(appErr.Kind),
+		Message: appErr.Error(),
+	}
+	var quotaErr *task.TaskQuotaError
+	if errors.As(err, &quotaErr) {
+		response.Details = TaskQuotaDetails{Count: quotaErr.Count, Limit: quotaErr.Limit}
+	}
+	return status, response
+}
+
+// TaskQuotaDetails are the Details of a task_quota_exceeded error
+type TaskQuotaDetails struct {
+	Count int64 `json:"count"` // tasks counted towards the quota
+	Limit int64 `json:"limit"`
+}
+
+// internalErrorResponse is the generic body for unexpected failures
+func internalErrorResponse() ErrorResponse {
+	return ErrorResponse{
+		Error:   "internal_error",
+		Code:    CodeInternal,
+		Message: "An internal server error occurred",
+	}
+}
+
+// requestIDForLog returns the request ID for log correlation, if one was assigned
+func requestIDForLog(c *gin.Context) string {
+	if id, ok := middleware.GetRequestID(c); ok {
+		return id
+	}
+	return "-"
+}
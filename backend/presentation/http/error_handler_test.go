@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/application/apperrors"
+	"todo-app/middleware"
+)
+
+func performErrorHandlerRequest(t *testing.T, handler gin.HandlerFunc) (*httptest.ResponseRecorder, ErrorResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(ErrorHandler())
+	router.GET("/test", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	if w.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	}
+	return w, response
+}
+
+func TestErrorHandler_MapsApplicationErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+		wantCode   string
+	}{
+		{"validation", apperrors.Validation(errors.New("title cannot be empty")), http.StatusUnprocessableEntity, "validation_error", CodeValidation},
+		{"not found", apperrors.NotFound("task_not_found", errors.New("task not found")), http.StatusNotFound, "task_not_found", CodeNotFound},
+		{"forbidden", apperrors.Forbidden("access_denied", errors.New("access denied")), http.StatusForbidden, "access_denied", CodeForbidden},
+		{"conflict", apperrors.Conflict("email_conflict", errors.New("email address is already registered")), http.StatusConflict, "email_conflict", CodeConflict},
+		{"unavailable", apperrors.Unavailable("registration_unavailable", errors.New("registration is not available")), http.StatusServiceUnavailable, "registration_unavailable", CodeUnavailable},
+		{"wrapped", fmt.Errorf("context: %w", apperrors.NotFound("user_not_found", errors.New("user not found"))), http.StatusNotFound, "user_not_found", CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantError, response.Error)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.NotEmpty(t, response.Message)
+			assert.Equal(t, "req-123", response.RequestID)
+		})
+	}
+}
+
+func TestErrorHandler_ClassificationIgnoresMessageText(t *testing.T) {
+	// A validation message that mentions "not found" must still be a validation error
+	w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+		c.Error(apperrors.Validation(errors.New("timezone not found in tz database")))
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, CodeValidation, response.Code)
+}
+
+func TestErrorHandler_UnclassifiedErrorDoesNotLeakDetails(t *testing.T) {
+	w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+		c.Error(errors.New("pq: connection refused to 10.0.0.5:5432"))
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "internal_error", response.Error)
+	assert.Equal(t, CodeInternal, response.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+	assert.Equal(t, "req-123", response.RequestID)
+}
+
+func TestErrorHandler_MapsDeadlineExceededToTimeout(t *testing.T) {
+	w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+		c.Error(fmt.Errorf("failed to load tasks: %w", context.DeadlineExceeded))
+	})
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "request_timeout", response.Error)
+	assert.Equal(t, CodeTimeout, response.Code)
+	assert.Equal(t, "req-123", response.RequestID)
+}
+
+func TestErrorHandler_RecoversFromPanic(t *testing.T) {
+	w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+		panic("secret internal state")
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, CodeInternal, response.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	assert.Equal(t, "req-123", response.RequestID)
+}
+
+func TestErrorHandler_LeavesWrittenResponsesAlone(t *testing.T) {
+	w, _ := performErrorHandlerRequest(t, func(c *gin.Context) {
+		c.Error(apperrors.Validation(errors.New("ignored")))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_query", Code: CodeBadRequest, Message: "bad sort"})
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_query")
+}
+
+func TestRespondWithError_IncludesRequestID(t *testing.T) {
+	w, response := performErrorHandlerRequest(t, func(c *gin.Context) {
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{Error: "invalid_query", Code: CodeBadRequest, Message: "bad sort"})
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_query", response.Error)
+	assert.Equal(t, "req-123", response.RequestID)
+}
+
+func TestNotFoundAndMethodNotAllowedHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(ErrorHandler())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NotFoundHandler())
+	router.NoMethod(MethodNotAllowedHandler())
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		method, path string
+		wantStatus   int
+		wantCode     string
+	}{
+		{http.MethodGet, "/missing", http.StatusNotFound, CodeNotFound},
+		{http.MethodPost, "/missing", http.StatusNotFound, CodeNotFound},
+		{http.MethodPost, "/health", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+		{http.MethodDelete, "/health", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-Request-ID", "req-123")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+			assert.Equal(t, tt.wantCode, response.Error)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, "req-123", response.RequestID)
+			if tt.wantStatus == http.StatusMethodNotAllowed {
+				assert.Equal(t, http.MethodGet, w.Header().Get("Allow"))
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// dateOnlyLayout is the format of date-only request values such as "2024-07-01"
+const dateOnlyLayout = "2006-01-02"
+
+// NullableTime is a request field that tells an absent key apart from an
+// explicit null: Set is false when the key was absent, and true with a nil
+// Value when it was null. Besides RFC 3339 timestamps it accepts date-only
+// values, which are marked DateOnly with Value at midnight UTC of that date,
+// so the caller can place them in the user's timezone.
+type NullableTime struct {
+	Set      bool
+	Value    *time.Time
+	DateOnly bool
+}
+
+// UnmarshalJSON records that the key was present; it is only called for keys
+// in the request body, including those set to null
+func (n *NullableTime) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	n.DateOnly = false
+	if bytes.Equal(data, []byte("null")) {
+		n.Value = nil
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		if date, err := time.Parse(dateOnlyLayout, raw); err == nil {
+			n.Value = &date
+			n.DateOnly = true
+			return nil
+		}
+	}
+
+	var value time.Time
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
+
+// MarshalJSON writes the value, or null when it is unset or cleared
+func (n NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
+// IsNull reports whether the key was present and set to null
+func (n NullableTime) IsNull() bool {
+	return n.Set && n.Value == nil
+}
+
+// In returns the value as a UTC instant, reading a date-only value as the end
+// of that day in location, so a task due on a date stays due until the day is over
+func (n NullableTime) In(location *time.Location) *time.Time {
+	if n.Value == nil {
+		return nil
+	}
+
+	value := n.Value.UTC()
+	if n.DateOnly {
+		year, month, day := n.Value.Date()
+		value = time.Date(year, month, day, 23, 59, 59, 0, location).UTC()
+	}
+	return &value
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableTime_DateOnlyAcrossDSTTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		timezone   string
+		date       string
+		wantOffset string
+	}{
+		{"new york before spring forward", "America/New_York", "2024-03-09", "-05:00"},
+		{"new york on spring forward", "America/New_York", "2024-03-10", "-04:00"},
+		{"new york on fall back", "America/New_York", "2024-11-03", "-05:00"},
+		{"berlin before fall back", "Europe/Berlin", "2024-10-26", "+02:00"},
+		{"berlin on fall back", "Europe/Berlin", "2024-10-27", "+01:00"},
+		{"sydney on fall back", "Australia/Sydney", "2024-04-07", "+10:00"},
+		{"utc", "UTC", "2024-07-01", "Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := time.LoadLocation(tt.timezone)
+			require.NoError(t, err)
+
+			var value NullableTime
+			require.NoError(t, json.Unmarshal([]byte(`"`+tt.date+`"`), &value))
+			assert.True(t, value.DateOnly)
+
+			due := value.In(location)
+			require.NotNil(t, due)
+			assert.Equal(t, time.UTC, due.Location())
+			assert.Equal(t, tt.date+"T23:59:59"+tt.wantOffset, due.In(location).Format(time.RFC3339))
+		})
+	}
+}
+
+func TestNullableTime_TimestampIsNotMoved(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	var value NullableTime
+	require.NoError(t, json.Unmarshal([]byte(`"2024-03-10T02:30:00+01:00"`), &value))
+	assert.False(t, value.DateOnly)
+
+	due := value.In(location)
+	require.NotNil(t, due)
+	assert.Equal(t, time.Date(2024, 3, 10, 1, 30, 0, 0, time.UTC), *due)
+}
+
+func TestNullableTime_NullAndMalformed(t *testing.T) {
+	var value NullableTime
+	require.NoError(t, json.Unmarshal([]byte(`null`), &value))
+	assert.True(t, value.IsNull())
+	assert.Nil(t, value.In(time.UTC))
+
+	assert.Error(t, json.Unmarshal([]byte(`"2024-02-30"`), &value))
+	assert.Error(t, json.Unmarshal([]byte(`"07/01/2024"`), &value))
+}
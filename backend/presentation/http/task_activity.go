@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultTaskActivityLimit is the page size of GET /tasks/:id/activity
+	defaultTaskActivityLimit = 50
+
+	// maxTaskActivityLimit is the largest page a client may ask for
+	maxTaskActivityLimit = 200
+)
+
+// TaskActivityResponse is one entry in a task's history. Field, OldValue and
+// NewValue are set for changes to a single field; values are null when the
+// field was empty.
+type TaskActivityResponse struct {
+	ID        uint      `json:"id"`
+	TaskID    uint      `json:"task_id"`
+	UserID    uint      `json:"user_id"`
+	Action    string    `json:"action"`
+	Field     string    `json:"field,omitempty"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskActivityListResponse is a page of a task's history, newest first
+type TaskActivityListResponse struct {
+	Activity []TaskActivityResponse `json:"activity"`
+	Total    int64                  `json:"total"`
+	Limit    int                    `json:"limit"`
+	Offset   int                    `json:"offset"`
+}
+
+// GetTaskActivity handles GET /api/v1/tasks/:id/activity[?limit=&offset=],
+// listing what was done to a live or trashed task
+func (h *TaskHandlers) GetTaskActivity(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse task ID from path
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_id",
+			Code:    CodeBadRequest,
+			Message: "Invalid task ID format",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTaskActivityLimit)))
+	if err != nil || limit < 1 || limit > maxTaskActivityLimit {
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_query",
+			Code:    CodeBadRequest,
+			Message: "limit must be between 1 and " + strconv.Itoa(maxTaskActivityLimit),
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_query",
+			Code:    CodeBadRequest,
+			Message: "offset must be a non-negative integer",
+		})
+		return
+	}
+
+	activities, total, err := h.taskService.GetTaskActivity(c.Request.Context(), uint(taskID), userIDUint, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := TaskActivityListResponse{
+		Activity: make([]TaskActivityResponse, 0, len(activities)),
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	for _, activity := range activities {
+		response.Activity = append(response.Activity, TaskActivityResponse{
+			ID:        activity.ID(),
+			TaskID:    activity.TaskID().Value(),
+			UserID:    activity.UserID().Value(),
+			Action:    string(activity.Action()),
+			Field:     activity.Field(),
+			OldValue:  activity.OldValue(),
+			NewValue:  activity.NewValue(),
+			CreatedAt: activity.CreatedAt().UTC(),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/dtos"
+)
+
+func getTaskActivity(t *testing.T, router *gin.Engine, target string) TaskActivityListResponse {
+	w := performTaskRequest(router, http.MethodGet, target)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskActivityListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func createTaskForActivity(t *testing.T, router *gin.Engine, payload map[string]interface{}) TaskResponse {
+	w := performCreateTask(router, payload)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	return created
+}
+
+func activityTarget(taskID uint) string {
+	return fmt.Sprintf("/api/v1/tasks/%d/activity", taskID)
+}
+
+func TestTaskActivity_CreationLogsSingleEntry(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+	created := createTaskForActivity(t, router, map[string]interface{}{"title": "Task", "tags": []string{"home"}})
+
+	history := getTaskActivity(t, router, activityTarget(created.ID))
+	require.Len(t, history.Activity, 1)
+	assert.Equal(t, int64(1), history.Total)
+	entry := history.Activity[0]
+	assert.Equal(t, "created", entry.Action)
+	assert.Equal(t, created.ID, entry.TaskID)
+	assert.Equal(t, uint(1), entry.UserID)
+	assert.Empty(t, entry.Field)
+}
+
+func TestTaskActivity_PartialUpdateRecordsOnlyChangedFields(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	created := createTaskForActivity(t, router, map[string]interface{}{
+		"title": "Draft", "description": "Notes", "priority": "low", "due_date": due,
+	})
+
+	// Title changes, description is resent unchanged, priority is absent
+	w := performUpdateTask(router, created.ID, map[string]interface{}{"title": "Final", "description": "Notes"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	history := getTaskActivity(t, router, activityTarget(created.ID))
+	require.Len(t, history.Activity, 2)
+	entry := history.Activity[0]
+	assert.Equal(t, "updated", entry.Action)
+	assert.Equal(t, "title", entry.Field)
+	require.NotNil(t, entry.OldValue)
+	require.NotNil(t, entry.NewValue)
+	assert.Equal(t, "Draft", *entry.OldValue)
+	assert.Equal(t, "Final", *entry.NewValue)
+
+	// An update that changes nothing records nothing
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"title": "Final", "priority": "low"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, getTaskActivity(t, router, activityTarget(created.ID)).Activity, 2)
+
+	// Several fields at once, including clearing one and replacing tags
+	w = performUpdateTask(router, created.ID, map[string]interface{}{
+		"priority": "high", "due_date": nil, "tags": []string{"work", "urgent"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	history = getTaskActivity(t, router, activityTarget(created.ID))
+	require.Len(t, history.Activity, 5)
+	changes := map[string]TaskActivityResponse{}
+	for _, entry := range history.Activity[:3] {
+		changes[entry.Field] = entry
+	}
+	require.Contains(t, changes, "priority")
+	assert.Equal(t, "low", *changes["priority"].OldValue)
+	assert.Equal(t, "high", *changes["priority"].NewValue)
+	require.Contains(t, changes, "due_date")
+	assert.Equal(t, due.Format(time.RFC3339), *changes["due_date"].OldValue)
+	assert.Nil(t, changes["due_date"].NewValue)
+	require.Contains(t, changes, "tags")
+	assert.Nil(t, changes["tags"].OldValue)
+	assert.Equal(t, `["urgent","work"]`, *changes["tags"].NewValue)
+}
+
+func TestTaskActivity_StatusChangesAndDeletion(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	created := createTaskForActivity(t, router, map[string]interface{}{"title": "Task"})
+
+	w := performUpdateTask(router, created.ID, map[string]interface{}{"completed": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"status": "archived"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"status": "pending", "restore": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = performTaskRequest(router, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d", created.ID))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	// Trashed tasks keep their history visible
+	history := getTaskActivity(t, router, activityTarget(created.ID))
+	actions := make([]string, 0, len(history.Activity))
+	for _, entry := range history.Activity {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{"deleted", "reopened", "archived", "completed", "created"}, actions)
+	assert.Equal(t, "completed", *history.Activity[3].NewValue)
+	assert.Equal(t, "pending", *history.Activity[3].OldValue)
+
+	w = performTaskRequest(router, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/restore", created.ID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "restored", getTaskActivity(t, router, activityTarget(created.ID)).Activity[0].Action)
+
+	// The history is kept for audit once the task is gone for good
+	w = performTaskRequest(router, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d?permanent=true", created.ID))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = performTaskRequest(router, http.MethodGet, activityTarget(created.ID))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var kept []dtos.TaskActivity
+	require.NoError(t, db.Where("task_id = ?", created.ID).Order("id").Find(&kept).Error)
+	require.Len(t, kept, 7)
+	assert.Equal(t, "purged", kept[6].Action)
+}
+
+func TestTaskActivity_BulkStatusChangesAreRecorded(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+	first := createTaskForActivity(t, router, map[string]interface{}{"title": "First"})
+	second := createTaskForActivity(t, router, map[string]interface{}{"title": "Second"})
+
+	w := performBulkStatus(router, map[string]interface{}{"task_ids": []uint{first.ID, second.ID}, "status": "completed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	for _, id := range []uint{first.ID, second.ID} {
+		history := getTaskActivity(t, router, activityTarget(id))
+		require.Len(t, history.Activity, 2)
+		assert.Equal(t, "completed", history.Activity[0].Action)
+		assert.Equal(t, "status", history.Activity[0].Field)
+	}
+}
+
+func TestTaskActivity_ReadsRecordNothing(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	created := createTaskForActivity(t, router, map[string]interface{}{"title": "Task"})
+
+	for _, target := range []string{
+		fmt.Sprintf("/api/v1/tasks/%d", created.ID),
+		"/api/v1/tasks",
+		"/api/v1/tasks/search?q=Task",
+		"/api/v1/tasks/stats",
+		"/api/v1/tasks/overdue",
+		activityTarget(created.ID),
+	} {
+		w := performTaskRequest(router, http.MethodGet, target)
+		require.Equal(t, http.StatusOK, w.Code, target)
+	}
+	w := performExport(router, "/api/v1/tasks/export")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.TaskActivity{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTaskActivity_PaginationAndAccess(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	created := createTaskForActivity(t, router, map[string]interface{}{"title": "Title 0"})
+	for i := 1; i <= 4; i++ {
+		w := performUpdateTask(router, created.ID, map[string]interface{}{"title": fmt.Sprintf("Title %d", i)})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	page := getTaskActivity(t, router, activityTarget(created.ID)+"?limit=2&offset=1")
+	assert.Equal(t, int64(5), page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.Equal(t, 1, page.Offset)
+	require.Len(t, page.Activity, 2)
+	assert.Equal(t, "Title 3", *page.Activity[0].NewValue)
+	assert.Equal(t, "Title 2", *page.Activity[1].NewValue)
+
+	for _, query := range []string{"?limit=0", "?limit=201", "?limit=x", "?offset=-1"} {
+		w := performTaskRequest(router, http.MethodGet, activityTarget(created.ID)+query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	// Other users' tasks are reported as missing
+	other := dtos.Task{Title: "Not mine", UserID: 2}
+	require.NoError(t, db.Create(&other).Error)
+	w := performTaskRequest(router, http.MethodGet, activityTarget(other.ID))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/abc/activity", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"domain/task/entities"
+	"todo-app/application/task"
+)
+
+const (
+	// taskEventSendBuffer is how many events may queue for a connection before
+	// it is considered too slow and dropped
+	taskEventSendBuffer = 32
+
+	// taskEventHistorySize is how many recent events are kept per user for
+	// clients that reconnect with Last-Event-ID
+	taskEventHistorySize = 64
+
+	// taskEventReplayWindow is how long after an event a reconnecting client
+	// can still have it replayed
+	taskEventReplayWindow = 2 * time.Minute
+)
+
+// TaskEvent is the JSON message pushed to websocket and SSE clients when one
+// of their tasks changes
+type TaskEvent struct {
+	Type string       `json:"type"` // task.created, task.updated or task.deleted
+	Task TaskResponse `json:"task"`
+}
+
+// TaskLifecycleEvent is the JSON message pushed when one of the user's tasks is
+// completed, archived or reopened, alongside the task.updated TaskEvent that
+// carries the task itself
+type TaskLifecycleEvent struct {
+	Type   string `json:"type"` // task.completed, task.archived or task.reopened
+	TaskID uint   `json:"task_id"`
+}
+
+// taskLifecycleEvents are the domain events relayed to clients as TaskLifecycleEvents
+var taskLifecycleEvents = map[string]bool{
+	entities.TaskCompleted{}.EventName(): true,
+	entities.TaskArchived{}.EventName():  true,
+	entities.TaskReopened{}.EventName():  true,
+}
+
+// taskEventMessage is an encoded event with the ID clients resume from
+type taskEventMessage struct {
+	id        uint64
+	eventType string
+	data      []byte // JSON-encoded TaskEvent or TaskLifecycleEvent
+	at        time.Time
+}
+
+// taskEventClient is one connection waiting for its user's task events
+type taskEventClient struct {
+	userID uint
+	send   chan taskEventMessage // closed by the hub when the client is unregistered
+}
+
+// taskEventHistory holds a user's recent events. Every event after since is
+// in events, so a client that last saw since or later can be caught up.
+type taskEventHistory struct {
+	events         []taskEventMessage
+	since          uint64
+	disconnectedAt time.Time // when the user's last connection closed; zero while connected
+}
+
+// TaskEventHub fans task changes out to every websocket and SSE connection of
+// the task's owner, keeping a short history of each user's events for clients
+// that reconnect. It implements task.EventBus and task.EventSubscriber.
+type TaskEventHub struct {
+	mu      sync.Mutex
+	clients map[uint]map[*taskEventClient]struct{}
+	history map[uint]*taskEventHistory
+	lastID  uint64
+	closed  bool
+	now     func() time.Time // clock, replaceable in tests
+
+	// userTimezones, if set, supplies the timezone events show local times in
+	userTimezones UserTimezones
+}
+
+// NewTaskEventHub creates a hub with no connections. Event IDs start from the
+// current time so they keep increasing across restarts.
+func NewTaskEventHub() *TaskEventHub {
+	return &TaskEventHub{
+		clients: make(map[uint]map[*taskEventClient]struct{}),
+		history: make(map[uint]*taskEventHistory),
+		lastID:  uint64(time.Now().UnixMicro()),
+		now:     time.Now,
+	}
+}
+
+// SetUserTimezones makes events carry task times in each user's timezone.
+// It must be called before the hub is shared.
+func (h *TaskEventHub) SetUserTimezones(userTimezones UserTimezones) {
+	h.userTimezones = userTimezones
+}
+
+// PublishTaskChange queues the change for each of the owner's connections.
+// Connections whose buffer is full are dropped rather than blocking the
+// request that changed the task; their clients reconnect and catch up.
+func (h *TaskEventHub) PublishTaskChange(change task.TaskChange) {
+	userID := change.Task.UserID().Value()
+
+	// Looked up before locking, so a slow query does not hold up other users
+	var location *time.Location
+	if h.userTimezones != nil {
+		location = lookupUserLocation(context.Background(), h.userTimezones, userID)
+	}
+
+	h.publish(userID, change.Type, TaskEvent{Type: change.Type, Task: newTaskResponse(change.Task, location)})
+}
+
+// HandleDomainEvent relays task completions, archivals and reopenings to the
+// owner's connections. Other events are ignored; edits already reach clients
+// as task.updated changes.
+func (h *TaskEventHub) HandleDomainEvent(event entities.DomainEvent) {
+	taskEvent, ok := event.(entities.TaskDomainEvent)
+	if !ok || !taskLifecycleEvents[event.EventName()] {
+		return
+	}
+
+	taskID, userID := taskEvent.Subject()
+	h.publish(userID.Value(), event.EventName(), TaskLifecycleEvent{Type: event.EventName(), TaskID: taskID.Value()})
+}
+
+// publish encodes payload and queues it for each of userID's connections
+func (h *TaskEventHub) publish(userID uint, eventType string, payload interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Events are only kept for users who are connected or recently were
+	history := h.history[userID]
+	if history == nil {
+		return
+	}
+	if len(h.clients[userID]) == 0 && h.now().Sub(history.disconnectedAt) > taskEventReplayWindow {
+		delete(h.history, userID)
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", eventType, err)
+		return
+	}
+	h.lastID++
+	message := taskEventMessage{id: h.lastID, eventType: eventType, data: data, at: h.now()}
+	h.remember(history, message)
+
+	for client := range h.clients[userID] {
+		select {
+		case client.send <- message:
+		default:
+			h.removeLocked(client)
+		}
+	}
+}
+
+// remember adds message to history, evicting events past the size or age limit
+func (h *TaskEventHub) remember(history *taskEventHistory, message taskEventMessage) {
+	history.events = append(history.events, message)
+	cutoff := h.now().Add(-taskEventReplayWindow)
+	for len(history.events) > taskEventHistorySize || (len(history.events) > 0 && history.events[0].at.Before(cutoff)) {
+		history.since = history.events[0].id
+		history.events = history.events[1:]
+	}
+}
+
+// ConnectionCount returns how many connections userID has open
+func (h *TaskEventHub) ConnectionCount(userID uint) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients[userID])
+}
+
+// Close disconnects every client, e.g. when the server shuts down, and turns
+// away new ones
+func (h *TaskEventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, clients := range h.clients {
+		for client := range clients {
+			h.removeLocked(client)
+		}
+	}
+}
+
+// register adds a connection for userID
+func (h *TaskEventHub) register(userID uint) *taskEventClient {
+	client, _, _ := h.subscribe(userID, nil)
+	return client
+}
+
+// subscribe adds a connection for userID. When lastEventID is set it also
+// returns the user's events after it; caughtUp is false when some of those
+// are no longer kept, so the client must refetch its tasks instead.
+func (h *TaskEventHub) subscribe(userID uint, lastEventID *uint64) (client *taskEventClient, missed []taskEventMessage, caughtUp bool) {
+	client = &taskEventClient{userID: userID, send: make(chan taskEventMessage, taskEventSendBuffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(client.send)
+		return client, nil, true
+	}
+
+	h.forgetDisconnected()
+	history := h.history[userID]
+	if history == nil {
+		history = &taskEventHistory{since: h.lastID}
+		h.history[userID] = history
+	}
+	history.disconnectedAt = time.Time{}
+
+	caughtUp = true
+	if lastEventID != nil {
+		if *lastEventID < history.since {
+			caughtUp = false
+		} else {
+			for _, message := range history.events {
+				if message.id > *lastEventID {
+					missed = append(missed, message)
+				}
+			}
+		}
+	}
+
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*taskEventClient]struct{})
+	}
+	h.clients[userID][client] = struct{}{}
+	return client, missed, caughtUp
+}
+
+// forgetDisconnected drops the history of users who left longer ago than the
+// replay window; callers hold mu
+func (h *TaskEventHub) forgetDisconnected() {
+	cutoff := h.now().Add(-taskEventReplayWindow)
+	for userID, history := range h.history {
+		if len(h.clients[userID]) == 0 && history.disconnectedAt.Before(cutoff) {
+			delete(h.history, userID)
+		}
+	}
+}
+
+// unregister removes a connection; it is safe to call more than once
+func (h *TaskEventHub) unregister(client *taskEventClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client)
+}
+
+// removeLocked removes a connection and closes its send channel; callers hold mu
+func (h *TaskEventHub) removeLocked(client *taskEventClient) {
+	clients := h.clients[client.userID]
+	if _, ok := clients[client]; !ok {
+		return
+	}
+	delete(clients, client)
+	close(client.send)
+	if len(clients) == 0 {
+		delete(h.clients, client.userID)
+		if history := h.history[client.userID]; history != nil {
+			history.disconnectedAt = h.now()
+		}
+	}
+}
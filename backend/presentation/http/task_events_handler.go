@@ -0,0 +1,241 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsWriteWait is how long a single write to a websocket may take
+	wsWriteWait = 10 * time.Second
+
+	// wsPongWait is how long a connection may stay silent before it is closed
+	wsPongWait = 60 * time.Second
+
+	// wsPingPeriod is how often the server pings; it must be less than wsPongWait
+	wsPingPeriod = wsPongWait * 9 / 10
+
+	// wsMaxMessageSize caps client messages; clients only send control frames
+	wsMaxMessageSize = 512
+
+	// sseKeepaliveInterval is how often an idle event stream gets a comment,
+	// so proxies do not time it out
+	sseKeepaliveInterval = 15 * time.Second
+)
+
+// TaskEventsHandler serves the websocket, and the Server-Sent Events stream for
+// networks that block websockets, that push a user's task changes to every
+// device and tab they have open, so clients need not poll GET /tasks
+type TaskEventsHandler struct {
+	hub      *TaskEventHub
+	upgrader websocket.Upgrader
+
+	// keepaliveInterval is sseKeepaliveInterval, shortened in tests
+	keepaliveInterval time.Duration
+}
+
+// NewTaskEventsHandler creates a new task events handler. Browsers may connect
+// from the API's own origin or one of allowedOrigins; the session cookie is
+// sent along with the upgrade, so other sites must not be able to open one.
+func NewTaskEventsHandler(hub *TaskEventHub, allowedOrigins []string) *TaskEventsHandler {
+	return &TaskEventsHandler{
+		hub:               hub,
+		keepaliveInterval: sseKeepaliveInterval,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				return isAllowedOrigin(r, allowedOrigins)
+			},
+		},
+	}
+}
+
+// isAllowedOrigin accepts requests without an Origin header (non-browser
+// clients), from the API's own host, or from one of allowedOrigins
+func isAllowedOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeWebSocket handles GET /api/v1/ws. The request is authenticated by the
+// auth middleware before the upgrade; afterwards the connection receives a
+// TaskEvent for each of the user's tasks that is created, updated or deleted.
+func (h *TaskEventsHandler) ServeWebSocket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// On failure Upgrade has already replied with an HTTP error
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	client := h.hub.register(userIDUint)
+	go writeTaskEvents(conn, client)
+	h.readUntilClosed(conn, client)
+}
+
+// readUntilClosed reads, and discards, client messages so pongs and close
+// frames are handled, and unregisters the client once the connection ends
+func (h *TaskEventsHandler) readUntilClosed(conn *websocket.Conn, client *taskEventClient) {
+	defer func() {
+		h.hub.unregister(client)
+		conn.Close()
+	}()
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writeTaskEvents sends queued events and keepalive pings until the hub
+// closes the client's send channel or a write fails
+func writeTaskEvents(conn *websocket.Conn, client *taskEventClient) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-client.send:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				// Unregistered, e.g. because the client fell too far behind
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, message.data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// StreamEvents handles GET /api/v1/tasks/events, an SSE stream of the same
+// events as the websocket. Each event's id can be sent back in Last-Event-ID,
+// as EventSource does when it reconnects, to replay the events missed in
+// between; if they are no longer kept a resync event tells the client to
+// refetch its tasks instead.
+func (h *TaskEventsHandler) StreamEvents(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// A malformed Last-Event-ID is treated as a fresh connection
+	var lastEventID *uint64
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		if parsed, err := strconv.ParseUint(header, 10, 64); err == nil {
+			lastEventID = &parsed
+		}
+	}
+
+	client, missed, caughtUp := h.hub.subscribe(userIDUint, lastEventID)
+	defer h.hub.unregister(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	if !caughtUp {
+		fmt.Fprint(c.Writer, "event: resync\ndata: {}\n\n")
+	}
+	for _, message := range missed {
+		writeServerSentEvent(c.Writer, message)
+	}
+	c.Writer.Flush()
+
+	keepalive := time.NewTicker(h.keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case message, ok := <-client.send:
+			if !ok {
+				// Unregistered, e.g. because the client fell too far behind
+				return
+			}
+			writeServerSentEvent(c.Writer, message)
+			c.Writer.Flush()
+		case <-keepalive.C:
+			fmt.Fprint(c.Writer, ": keepalive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeServerSentEvent frames a task event for an SSE stream; the JSON data
+// never contains newlines, so it fits on one data line
+func writeServerSentEvent(w gin.ResponseWriter, message taskEventMessage) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.id, message.eventType, message.data)
+}
@@ -0,0 +1,411 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"domain/task/entities"
+	"domain/task/services"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/application/mappers"
+	"todo-app/application/task"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/dtos"
+)
+
+// setupTaskEventsTest serves the task routes and the websocket with a stand-in
+// for the auth middleware that maps session_token cookies to user IDs
+func setupTaskEventsTest(t *testing.T, sessions map[string]uint) (*TaskEventHub, *httptest.Server) {
+	return setupTaskEventsTestWithPublisher(t, sessions, func(*TaskEventHub) task.EventPublisher {
+		return task.NoopEventPublisher{}
+	})
+}
+
+// setupTaskEventsTestWithPublisher is setupTaskEventsTest with the domain
+// event publisher returned by newPublisher for the hub
+func setupTaskEventsTestWithPublisher(t *testing.T, sessions map[string]uint, newPublisher func(*TaskEventHub) task.EventPublisher) (*TaskEventHub, *httptest.Server) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}))
+
+	hub := NewTaskEventHub()
+	repo := persistence.NewGormTaskRepository(db, &mappers.TaskMapper{})
+	taskService := task.NewTaskApplicationService(
+		repo,
+		persistence.NewGormUnitOfWork(db),
+		services.NewTaskValidationService(),
+		services.NewTaskSearchService(repo),
+		valueobjects.DefaultDueDateBounds(),
+		task.TaskQuota{},
+		newPublisher(hub),
+		hub,
+	)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	api := router.Group("/api/v1", func(c *gin.Context) {
+		token, _ := c.Cookie("session_token")
+		userID, ok := sessions[token]
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("userID", userID)
+		c.Next()
+	})
+	NewTaskHandlers(taskService).RegisterRoutes(api)
+	events := NewTaskEventsHandler(hub, []string{"http://localhost:3000"})
+	events.keepaliveInterval = 50 * time.Millisecond
+	api.GET("/ws", events.ServeWebSocket)
+	api.GET("/tasks/events", events.StreamEvents)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return hub, server
+}
+
+// dialTaskEvents opens the websocket with the session cookie and origin given
+func dialTaskEvents(server *httptest.Server, sessionToken, origin string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	header.Set("Cookie", "session_token="+sessionToken)
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws", header)
+}
+
+// waitForConnections waits until the hub has registered n connections of userID
+func waitForConnections(t *testing.T, hub *TaskEventHub, userID uint, n int) {
+	require.Eventually(t, func() bool { return hub.ConnectionCount(userID) == n }, 2*time.Second, 10*time.Millisecond)
+}
+
+func readTaskEvent(t *testing.T, conn *websocket.Conn) TaskEvent {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var event TaskEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	return event
+}
+
+// sendTaskRequest makes a task API request as the session's user and checks its status
+func sendTaskRequest(t *testing.T, server *httptest.Server, sessionToken, method, path string, payload interface{}, wantStatus int) {
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+	}
+	req, err := http.NewRequest(method, server.URL+path, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session_token="+sessionToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, wantStatus, resp.StatusCode)
+}
+
+func TestTaskEvents_OtherConnectionsOfUserReceiveChanges(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice-tab-1": 1, "alice-tab-2": 1, "bob": 2})
+
+	first, _, err := dialTaskEvents(server, "alice-tab-1", "")
+	require.NoError(t, err)
+	defer first.Close()
+	second, _, err := dialTaskEvents(server, "alice-tab-2", "http://localhost:3000")
+	require.NoError(t, err)
+	defer second.Close()
+	other, _, err := dialTaskEvents(server, "bob", "")
+	require.NoError(t, err)
+	defer other.Close()
+	waitForConnections(t, hub, 1, 2)
+	waitForConnections(t, hub, 2, 1)
+
+	// The first tab creates a task
+	body, _ := json.Marshal(CreateTaskRequest{Title: "Buy milk", Priority: "high"})
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/tasks", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session_token=alice-tab-1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	for _, conn := range []*websocket.Conn{second, first} {
+		event := readTaskEvent(t, conn)
+		assert.Equal(t, task.TaskCreated, event.Type)
+		assert.Equal(t, "Buy milk", event.Task.Title)
+		assert.Equal(t, "high", event.Task.Priority)
+		assert.Equal(t, uint(1), event.Task.UserID)
+		assert.NotZero(t, event.Task.ID)
+	}
+
+	// Deleting it reaches the other tab too
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/api/v1/tasks/1", nil)
+	req.Header.Set("Cookie", "session_token=alice-tab-1")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	event := readTaskEvent(t, second)
+	assert.Equal(t, task.TaskDeleted, event.Type)
+	assert.True(t, event.Task.Deleted)
+
+	// Another user's connection hears nothing
+	require.NoError(t, other.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err = other.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestTaskEvents_ClosedConnectionsAreUnregistered(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice": 1})
+
+	conn, _, err := dialTaskEvents(server, "alice", "")
+	require.NoError(t, err)
+	waitForConnections(t, hub, 1, 1)
+
+	require.NoError(t, conn.Close())
+	waitForConnections(t, hub, 1, 0)
+}
+
+func TestTaskEvents_RejectsUnauthenticatedAndCrossSite(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice": 1})
+
+	_, resp, err := dialTaskEvents(server, "unknown", "")
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The browser would send the session cookie along from any site
+	_, resp, err = dialTaskEvents(server, "alice", "https://evil.example")
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Zero(t, hub.ConnectionCount(1))
+}
+
+// serverSentEvent is one block of an SSE stream; comment is set for comment-only blocks
+type serverSentEvent struct {
+	id, event, data, comment string
+}
+
+// openTaskEventStream connects to the SSE stream and parses its blocks onto the
+// returned channel until the stream is closed with the returned func
+func openTaskEventStream(t *testing.T, server *httptest.Server, sessionToken, lastEventID string) (*http.Response, <-chan serverSentEvent, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/tasks/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Cookie", "session_token="+sessionToken)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	events := make(chan serverSentEvent, 16)
+	go func() {
+		defer close(events)
+		reader := bufio.NewReader(resp.Body)
+		var event serverSentEvent
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				events <- event
+				event = serverSentEvent{}
+			case strings.HasPrefix(line, ":"):
+				event.comment = line
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			default:
+				t.Errorf("unexpected SSE line %q", line)
+			}
+		}
+	}()
+
+	return resp, events, func() {
+		cancel()
+		resp.Body.Close()
+	}
+}
+
+// nextTaskEvent returns the next event from the stream, skipping keepalives
+func nextTaskEvent(t *testing.T, events <-chan serverSentEvent) serverSentEvent {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "stream closed")
+			if event.comment != "" && event.event == "" {
+				continue
+			}
+			return event
+		case <-timeout:
+			t.Fatal("no event received")
+		}
+	}
+}
+
+func TestTaskEventStream_FramesEventsAndKeepalives(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice": 1})
+
+	resp, events, closeStream := openTaskEventStream(t, server, "alice", "")
+	defer closeStream()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	waitForConnections(t, hub, 1, 1)
+
+	// Idle streams get keepalive comments
+	select {
+	case event := <-events:
+		assert.Equal(t, serverSentEvent{comment: ": keepalive"}, event)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no keepalive received")
+	}
+
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Stream me"}, http.StatusCreated)
+
+	event := nextTaskEvent(t, events)
+	assert.Equal(t, task.TaskCreated, event.event)
+	_, err := strconv.ParseUint(event.id, 10, 64)
+	assert.NoError(t, err, "event IDs are numeric")
+	var payload TaskEvent
+	require.NoError(t, json.Unmarshal([]byte(event.data), &payload))
+	assert.Equal(t, task.TaskCreated, payload.Type)
+	assert.Equal(t, "Stream me", payload.Task.Title)
+
+	// Closing the stream unregisters it
+	closeStream()
+	waitForConnections(t, hub, 1, 0)
+}
+
+func TestTaskEventStream_ReplaysEventsMissedWhileReconnecting(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice": 1})
+
+	_, events, closeStream := openTaskEventStream(t, server, "alice", "")
+	waitForConnections(t, hub, 1, 1)
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Seen"}, http.StatusCreated)
+	seen := nextTaskEvent(t, events)
+	closeStream()
+	waitForConnections(t, hub, 1, 0)
+
+	// Changes made while the client is away
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Missed"}, http.StatusCreated)
+	sendTaskRequest(t, server, "alice", http.MethodPut, "/api/v1/tasks/1", gin.H{"status": "completed"}, http.StatusOK)
+
+	_, events, closeStream = openTaskEventStream(t, server, "alice", seen.id)
+	defer closeStream()
+
+	created := nextTaskEvent(t, events)
+	assert.Equal(t, task.TaskCreated, created.event)
+	assert.Contains(t, created.data, `"title":"Missed"`)
+	updated := nextTaskEvent(t, events)
+	assert.Equal(t, task.TaskUpdated, updated.event)
+	assert.Contains(t, updated.data, `"status":"completed"`)
+	assert.Greater(t, updated.id, created.id)
+	assert.Greater(t, created.id, seen.id)
+
+	// New events follow the replayed ones
+	waitForConnections(t, hub, 1, 1)
+	sendTaskRequest(t, server, "alice", http.MethodDelete, "/api/v1/tasks/2", nil, http.StatusNoContent)
+	assert.Equal(t, task.TaskDeleted, nextTaskEvent(t, events).event)
+}
+
+func TestTaskEventStream_ResyncWhenMissedEventsAreGone(t *testing.T) {
+	hub, server := setupTaskEventsTest(t, map[string]uint{"alice": 1})
+
+	_, events, closeStream := openTaskEventStream(t, server, "alice", "")
+	waitForConnections(t, hub, 1, 1)
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Seen"}, http.StatusCreated)
+	seen := nextTaskEvent(t, events)
+	closeStream()
+	waitForConnections(t, hub, 1, 0)
+
+	// More changes than the history keeps
+	for i := 0; i < taskEventHistorySize+1; i++ {
+		sendTaskRequest(t, server, "alice", http.MethodPut, "/api/v1/tasks/1", gin.H{"title": fmt.Sprintf("Edit %d", i)}, http.StatusOK)
+	}
+
+	_, events, closeStream = openTaskEventStream(t, server, "alice", seen.id)
+	defer closeStream()
+
+	resync := nextTaskEvent(t, events)
+	assert.Equal(t, "resync", resync.event)
+	assert.Empty(t, resync.id)
+}
+
+func TestTaskEventHub_RelaysLifecycleEvents(t *testing.T) {
+	hub := NewTaskEventHub()
+	client := hub.register(7)
+	defer hub.unregister(client)
+
+	now := time.Now()
+	hub.HandleDomainEvent(entities.TaskUpdated{TaskID: valueobjects.NewTaskID(3), UserID: uservo.NewUserID(7), Fields: []string{entities.TaskFieldTitle}, UpdatedAt: now})
+	hub.HandleDomainEvent(entities.TaskArchived{TaskID: valueobjects.NewTaskID(3), UserID: uservo.NewUserID(7), ArchivedAt: now})
+	hub.HandleDomainEvent(entities.TaskCompleted{TaskID: valueobjects.NewTaskID(4), UserID: uservo.NewUserID(8), CompletedAt: now})
+
+	// Only the owner's archival is relayed; edits arrive as task.updated changes
+	require.Len(t, client.send, 1)
+	message := <-client.send
+	assert.Equal(t, "task.archived", message.eventType)
+
+	var event TaskLifecycleEvent
+	require.NoError(t, json.Unmarshal(message.data, &event))
+	assert.Equal(t, TaskLifecycleEvent{Type: "task.archived", TaskID: 3}, event)
+}
+
+func TestTaskEvents_TaskChangeArrivesBeforeLifecycleEvent(t *testing.T) {
+	// The hub hears domain events as it does in the server
+	hub, server := setupTaskEventsTestWithPublisher(t, map[string]uint{"alice": 1}, func(hub *TaskEventHub) task.EventPublisher {
+		return task.NewSyncEventPublisher(hub)
+	})
+	conn, _, err := dialTaskEvents(server, "alice", "")
+	require.NoError(t, err)
+	defer conn.Close()
+	waitForConnections(t, hub, 1, 1)
+
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Buy milk"}, http.StatusCreated)
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks", CreateTaskRequest{Title: "Buy bread"}, http.StatusCreated)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, task.TaskCreated, readTaskEvent(t, conn).Type)
+	}
+
+	// Clients have the completed task by the time they hear it was completed
+	sendTaskRequest(t, server, "alice", http.MethodPut, "/api/v1/tasks/1", map[string]interface{}{"status": "completed"}, http.StatusOK)
+	assert.Equal(t, task.TaskUpdated, readTaskEvent(t, conn).Type)
+	assert.Equal(t, "task.completed", readTaskEvent(t, conn).Type)
+
+	sendTaskRequest(t, server, "alice", http.MethodPost, "/api/v1/tasks/bulk-status", map[string]interface{}{"task_ids": []uint{2}, "status": "archived"}, http.StatusOK)
+	assert.Equal(t, task.TaskUpdated, readTaskEvent(t, conn).Type)
+	assert.Equal(t, "task.archived", readTaskEvent(t, conn).Type)
+}
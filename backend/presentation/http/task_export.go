@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"domain/task/entities"
+	"github.com/gin-gonic/gin"
+)
+
+// exportPageSize is how many tasks an export loads from the database at a time
+const exportPageSize = 500
+
+// taskExportColumns is the CSV header row
+var taskExportColumns = []string{"id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at"}
+
+// ExportTasks handles GET /api/v1/tasks/export?format=csv|json, downloading
+// all of the user's tasks outside the trash. Tasks are read and written a page
+// at a time, so a large export is never held in memory whole.
+func (h *TaskHandlers) ExportTasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	var encoder taskExportEncoder
+	switch format {
+	case "csv":
+		encoder = &csvTaskExportEncoder{}
+	case "json":
+		encoder = &jsonTaskExportEncoder{location: h.userLocation(c.Request.Context(), userIDUint)}
+	default:
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_query",
+			Code:    CodeBadRequest,
+			Message: "format must be csv or json",
+		})
+		return
+	}
+
+	// The first page is loaded before any output, so a failure can still be
+	// reported with an error status
+	page, err := h.taskService.GetTaskPage(c.Request.Context(), userIDUint, 0, exportPageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("tasks-%s.%s", time.Now().UTC().Format("2006-01-02"), format)
+	c.Header("Content-Type", encoder.contentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+
+	started, complete := false, false
+	c.Stream(func(w io.Writer) bool {
+		if !started {
+			started = true
+			if err := encoder.begin(w); err != nil {
+				return false
+			}
+		}
+
+		if err := encoder.write(w, page); err != nil {
+			return false
+		}
+		if len(page) < exportPageSize {
+			complete = encoder.end(w) == nil
+			return false
+		}
+
+		afterID := page[len(page)-1].ID().Value()
+		page, err = h.taskService.GetTaskPage(c.Request.Context(), userIDUint, afterID, exportPageSize)
+		if err != nil {
+			log.Printf("Task export for user %d failed after task %d: %v", userIDUint, afterID, err)
+			return false
+		}
+		return true
+	})
+
+	// The status is already sent, so an export cut short, such as by the
+	// request deadline, aborts the connection; the client then sees a failed
+	// download instead of a file that merely looks complete
+	if !complete {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// taskExportEncoder writes tasks in an export format, one page at a time
+type taskExportEncoder interface {
+	contentType() string
+	begin(w io.Writer) error
+	write(w io.Writer, tasks []*entities.Task) error
+	end(w io.Writer) error
+}
+
+// csvTaskExportEncoder writes a header row and one row per task
+type csvTaskExportEncoder struct{}
+
+func (e *csvTaskExportEncoder) contentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *csvTaskExportEncoder) begin(w io.Writer) error {
+	return e.writeRecords(w, [][]string{taskExportColumns})
+}
+
+func (e *csvTaskExportEncoder) write(w io.Writer, tasks []*entities.Task) error {
+	records := make([][]string, len(tasks))
+	for i, task := range tasks {
+		var dueDate string
+		if due := task.DueDate(); due != nil {
+			dueDate = due.UTC().Format(time.RFC3339)
+		}
+		records[i] = []string{
+			strconv.FormatUint(uint64(task.ID().Value()), 10),
+			csvSafe(task.Title().Value()),
+			csvSafe(task.Description().Value()),
+			task.Status().Value(),
+			task.Priority().Value(),
+			dueDate,
+			task.CreatedAt().UTC().Format(time.RFC3339),
+			task.UpdatedAt().UTC().Format(time.RFC3339),
+		}
+	}
+	return e.writeRecords(w, records)
+}
+
+func (e *csvTaskExportEncoder) end(w io.Writer) error {
+	return nil
+}
+
+func (e *csvTaskExportEncoder) writeRecords(w io.Writer, records [][]string) error {
+	writer := csv.NewWriter(w)
+	return writer.WriteAll(records)
+}
+
+// csvSafe keeps user text from being run as a formula when the export is
+// opened in a spreadsheet, by prefixing cells that start like one with a quote
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// jsonTaskExportEncoder writes a JSON array of TaskResponse objects, with
+// local times in location
+type jsonTaskExportEncoder struct {
+	location *time.Location
+	written  bool
+}
+
+func (e *jsonTaskExportEncoder) contentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (e *jsonTaskExportEncoder) begin(w io.Writer) error {
+	_, err := io.WriteString(w, "[")
+	return err
+}
+
+func (e *jsonTaskExportEncoder) write(w io.Writer, tasks []*entities.Task) error {
+	for _, task := range tasks {
+		data, err := json.Marshal(newTaskResponse(task, e.location))
+		if err != nil {
+			return err
+		}
+		if e.written {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		e.written = true
+	}
+	return nil
+}
+
+func (e *jsonTaskExportEncoder) end(w io.Writer) error {
+	_, err := io.WriteString(w, "]")
+	return err
+}
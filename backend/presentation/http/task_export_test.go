@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"todo-app/internal/dtos"
+)
+
+// closeNotifyingRecorder adds the http.CloseNotifier that gin's Context.Stream
+// needs to a ResponseRecorder
+type closeNotifyingRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r closeNotifyingRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func performExport(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := closeNotifyingRecorder{httptest.NewRecorder()}
+	router.ServeHTTP(w, req)
+	return w.ResponseRecorder
+}
+
+func readExportCSV(t *testing.T, w *httptest.ResponseRecorder) [][]string {
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.Equal(t, taskExportColumns, records[0])
+	return records[1:]
+}
+
+func TestExportTasks_CSV(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	dueDate := time.Date(2026, 5, 1, 17, 0, 0, 0, time.UTC)
+	seed := []dtos.Task{
+		{Title: "Write report", Description: "Q1, \"final\" numbers", Priority: "high", UserID: 1, DueDate: &dueDate},
+		{Title: "=HYPERLINK(\"http://evil\")", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Not mine", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[2]).Error)
+
+	w := performExport(router, "/api/v1/tasks/export?format=csv")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="tasks-\d{4}-\d{2}-\d{2}\.csv"$`, w.Header().Get("Content-Disposition"))
+
+	rows := readExportCSV(t, w)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{fmt.Sprint(seed[0].ID), "Write report", "Q1, \"final\" numbers", "pending", "high", "2026-05-01T17:00:00Z"}, rows[0][:6])
+	assert.NotEmpty(t, rows[0][6])
+	assert.NotEmpty(t, rows[0][7])
+
+	// Formulas are neutralised and a missing due date is left blank
+	assert.Equal(t, "'=HYPERLINK(\"http://evil\")", rows[1][1])
+	assert.Equal(t, "completed", rows[1][3])
+	assert.Empty(t, rows[1][5])
+}
+
+func TestExportTasks_CSVIsTheDefaultAndPagesThroughAllTasks(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := make([]dtos.Task, exportPageSize*2+1)
+	for i := range seed {
+		seed[i] = dtos.Task{Title: fmt.Sprintf("Task %d", i), UserID: 1}
+	}
+	require.NoError(t, db.Session(&gorm.Session{CreateBatchSize: 100}).Create(&seed).Error)
+
+	w := performExport(router, "/api/v1/tasks/export")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	rows := readExportCSV(t, w)
+	require.Len(t, rows, len(seed))
+	for i, row := range rows {
+		assert.Equal(t, fmt.Sprint(seed[i].ID), row[0])
+	}
+}
+
+func TestExportTasks_AbortsWhenCutShort(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := make([]dtos.Task, exportPageSize+1)
+	for i := range seed {
+		seed[i] = dtos.Task{Title: fmt.Sprintf("Task %d", i), UserID: 1}
+	}
+	require.NoError(t, db.Session(&gorm.Session{CreateBatchSize: 100}).Create(&seed).Error)
+
+	// Fail loading the second page, as when the request deadline passes
+	pages := 0
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:fail_second_page", func(tx *gorm.DB) {
+		if tx.Statement.Table == "tasks" {
+			pages++
+			if pages == 2 {
+				tx.AddError(context.DeadlineExceeded)
+			}
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/export", nil)
+	w := closeNotifyingRecorder{httptest.NewRecorder()}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { router.ServeHTTP(w, req) })
+	assert.Equal(t, 2, pages)
+}
+
+func TestExportTasks_JSON(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "First", UserID: 1},
+		{Title: "Second", UserID: 1},
+	}).Error)
+
+	w := performExport(router, "/api/v1/tasks/export?format=json")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".json")
+
+	var tasks []TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "First", tasks[0].Title)
+	assert.Equal(t, "Second", tasks[1].Title)
+}
+
+func TestExportTasks_EmptyJSONIsAnEmptyArray(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	w := performExport(router, "/api/v1/tasks/export?format=json")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestExportTasks_UnknownFormat(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	w := performExport(router, "/api/v1/tasks/export?format=xml")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_query", body.Error)
+}
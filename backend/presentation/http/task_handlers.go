@@ -1,78 +1,227 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	authentities "domain/auth/entities"
 	"domain/task/entities"
+	"domain/task/repositories"
+	"domain/task/services"
+	"todo-app/application/apperrors"
 	"todo-app/application/task"
+	"todo-app/internal/config"
+	"todo-app/services/audit"
 )
 
 // TaskResponse represents the HTTP response format for a task
 type TaskResponse struct {
-	ID          uint      `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    string    `json:"priority"`
-	UserID      uint      `json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Completed   bool       `json:"completed"` // kept for clients that predate status
+	Priority    string     `json:"priority"`
+	Tags        []string   `json:"tags"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	RemindAt    *time.Time `json:"remind_at,omitempty"`
+	IsOverdue   bool       `json:"is_overdue"`           // computed when the response is built
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // set for tasks in the trash
+	Deleted     bool       `json:"deleted,omitempty"`    // lets syncing clients reconcile removals
+	UserID      uint       `json:"user_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"` // send back on update to detect concurrent changes
+
+	// Local repeats the times in the user's timezone
+	Local *TaskLocalTimes `json:"local,omitempty"`
+}
+
+// TaskLocalTimes are a task's times formatted in the timezone of its owner's
+// profile, for display; the UTC instants in TaskResponse are authoritative
+type TaskLocalTimes struct {
+	Timezone  string  `json:"timezone"`
+	DueDate   *string `json:"due_date,omitempty"`
+	RemindAt  *string `json:"remind_at,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
 }
 
 // TaskListResponse represents the HTTP response format for task lists
 type TaskListResponse struct {
 	Tasks []TaskResponse `json:"tasks"`
-	Count int            `json:"count"`
+	Count int            `json:"count"`           // number of tasks in this response
+	Total *int64         `json:"total,omitempty"` // all of the user's tasks, excluding trashed ones; set on GET /tasks
 }
 
 // CreateTaskRequest represents the HTTP request format for creating a task
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required,max=500"`
-	Description string `json:"description" binding:"max=2000"`
-	Priority    string `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Title       string       `json:"title" binding:"required,max=500"`
+	Description string       `json:"description" binding:"max=2000"`
+	Priority    string       `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Tags        []string     `json:"tags,omitempty"`
+	DueDate     NullableTime `json:"due_date"`  // RFC 3339, or a date such as 2024-07-01 in the user's timezone
+	RemindAt    NullableTime `json:"remind_at"` // as for due_date; must be before the due date
 }
 
 // UpdateTaskRequest represents the HTTP request format for updating a task
 type UpdateTaskRequest struct {
-	Title       *string `json:"title,omitempty" binding:"omitempty,max=500"`
-	Description *string `json:"description,omitempty" binding:"omitempty,max=2000"`
-	Status      *string `json:"status,omitempty" binding:"omitempty,oneof=pending completed archived"`
-	Priority    *string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	Title       *string      `json:"title,omitempty" binding:"omitempty,min=1,max=500"`
+	Description *string      `json:"description,omitempty" binding:"omitempty,max=2000"`
+	Status      *string      `json:"status,omitempty" binding:"omitempty,oneof=pending completed archived"`
+	Priority    *string      `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	Tags        *[]string    `json:"tags,omitempty"`      // replaces the task's tags; [] clears them
+	Completed   *bool        `json:"completed,omitempty"` // shorthand for status completed/pending; status wins if both are set
+	DueDate     NullableTime `json:"due_date"`            // as for create; absent leaves the due date alone and null clears it
+	RemindAt    NullableTime `json:"remind_at"`           // as for due_date; a new time reschedules the reminder
+	Restore     bool         `json:"restore,omitempty"`   // required to move an archived task back to pending
+
+	// Version is the version of the task the client last read; if the task
+	// has been saved since, the update fails with 409 instead of overwriting it
+	Version *int `json:"version,omitempty" binding:"omitempty,min=1"`
+}
+
+// BulkUpdateStatusRequest represents the HTTP request format for updating the status of several tasks
+type BulkUpdateStatusRequest struct {
+	TaskIDs []uint `json:"task_ids" binding:"required,min=1,max=100"`
+	Status  string `json:"status" binding:"required,oneof=pending completed archived"`
+}
+
+// BulkUpdateStatusResponse represents the HTTP response format for bulk status updates
+type BulkUpdateStatusResponse struct {
+	Updated int            `json:"updated"`
+	Tasks   []TaskResponse `json:"tasks"`
+}
+
+// TaskStatsResponse represents the HTTP response format for task statistics;
+// every count is always present so clients can bind to it directly
+type TaskStatsResponse struct {
+	Total      int64              `json:"total"`
+	Overdue    int64              `json:"overdue"`
+	ByStatus   TaskStatusCounts   `json:"by_status"`
+	ByPriority TaskPriorityCounts `json:"by_priority"`
+}
+
+// TaskStatusCounts represents task counts per status
+type TaskStatusCounts struct {
+	Pending   int64 `json:"pending"`
+	Completed int64 `json:"completed"`
+	Archived  int64 `json:"archived"`
+}
+
+// TaskPriorityCounts represents task counts per priority
+type TaskPriorityCounts struct {
+	Low    int64 `json:"low"`
+	Medium int64 `json:"medium"`
+	High   int64 `json:"high"`
 }
 
 // ErrorResponse represents the HTTP error response format
 type ErrorResponse struct {
-	Error   string      `json:"error"`
-	Message string      `json:"message"`
-	Details interface{} `json:"details,omitempty"`
+	Error     string      `json:"error"` // specific error identifier, e.g. task_not_found
+	Code      string      `json:"code"`  // error category, one of the Code* constants
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"` // correlates the response with server logs
 }
 
 // TaskHandlers contains HTTP handlers for task-related endpoints
 type TaskHandlers struct {
 	taskService task.TaskApplicationService
+
+	// listWarningThreshold is the task count above which list
+	// responses advise clients to narrow their query (0 disables)
+	listWarningThreshold int
+
+	// auditService, if set, records task deletions
+	auditService *audit.AuditService
+
+	// userTimezones, if set, supplies the timezone date-only due dates are
+	// read in and local times are shown in; otherwise UTC is used
+	userTimezones UserTimezones
+}
+
+// UserTimezones looks up the timezone of a user's profile
+type UserTimezones interface {
+	GetUserTimezone(ctx context.Context, userID uint) (*time.Location, error)
 }
 
 // NewTaskHandlers creates a new task handlers instance
 func NewTaskHandlers(taskService task.TaskApplicationService) *TaskHandlers {
 	return &TaskHandlers{
-		taskService: taskService,
+		taskService:          taskService,
+		listWarningThreshold: config.DefaultTaskListWarningThreshold,
 	}
 }
 
-// RegisterRoutes registers all task-related routes
-func (h *TaskHandlers) RegisterRoutes(router *gin.RouterGroup) {
+// SetListWarningThreshold sets the task count above which list responses
+// advise clients to narrow their query; 0 disables the advice
+func (h *TaskHandlers) SetListWarningThreshold(threshold int) {
+	h.listWarningThreshold = threshold
+}
+
+// SetAuditService enables audit logging of task deletions
+func (h *TaskHandlers) SetAuditService(auditService *audit.AuditService) {
+	h.auditService = auditService
+}
+
+// SetUserTimezones makes the handlers use each user's profile timezone
+func (h *TaskHandlers) SetUserTimezones(userTimezones UserTimezones) {
+	h.userTimezones = userTimezones
+}
+
+// userLocation returns the user's timezone, falling back to UTC when it
+// cannot be looked up
+func (h *TaskHandlers) userLocation(ctx context.Context, userID uint) *time.Location {
+	return lookupUserLocation(ctx, h.userTimezones, userID)
+}
+
+// lookupUserLocation returns the user's timezone from userTimezones, or UTC
+// when there is none or it cannot be looked up
+func lookupUserLocation(ctx context.Context, userTimezones UserTimezones, userID uint) *time.Location {
+	if userTimezones == nil {
+		return time.UTC
+	}
+	location, err := userTimezones.GetUserTimezone(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to look up timezone of user %d, using UTC: %v", userID, err)
+		return time.UTC
+	}
+	return location
+}
+
+// RegisterRoutes registers all task-related routes.
+// writeMiddleware is applied only to routes that modify tasks; reads are exempt.
+func (h *TaskHandlers) RegisterRoutes(router *gin.RouterGroup, writeMiddleware ...gin.HandlerFunc) {
 	taskRoutes := router.Group("/tasks")
 	{
 		taskRoutes.GET("", h.GetTasks)
-		taskRoutes.POST("", h.CreateTask)
+		taskRoutes.GET("/search", h.SearchTasks)
+		taskRoutes.GET("/stats", h.GetTaskStats)
+		taskRoutes.GET("/overdue", h.GetOverdueTasks)
+		taskRoutes.GET("/due-soon", h.GetTasksDueSoon)
+		taskRoutes.GET("/export", h.ExportTasks)
+		taskRoutes.GET("/trash", h.GetTrashedTasks)
 		taskRoutes.GET("/:id", h.GetTask)
-		taskRoutes.PUT("/:id", h.UpdateTask)
-		taskRoutes.DELETE("/:id", h.DeleteTask)
+		taskRoutes.GET("/:id/activity", h.GetTaskActivity)
+
+		writes := taskRoutes.Group("", writeMiddleware...)
+		writes.POST("", h.CreateTask)
+		writes.POST("/bulk-status", h.BulkUpdateStatus)
+		writes.POST("/import", h.ImportTasks)
+		writes.POST("/:id/restore", h.RestoreTask)
+		writes.PUT("/:id", h.UpdateTask)
+		writes.DELETE("/:id", h.DeleteTask)
 	}
 }
 
@@ -81,8 +230,9 @@ func (h *TaskHandlers) GetTasks(c *gin.Context) {
 	// Get user ID from context (would be set by authentication middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -90,8 +240,9 @@ func (h *TaskHandlers) GetTasks(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -102,9 +253,21 @@ func (h *TaskHandlers) GetTasks(c *gin.Context) {
 		UserID: userIDUint,
 	}
 
-	// Parse optional status filter
+	// Parse optional status filter; completed=true|false is the older spelling
 	if statusParam := c.Query("status"); statusParam != "" {
 		query.Status = &statusParam
+	} else if completedParam := c.Query("completed"); completedParam != "" {
+		completed, err := strconv.ParseBool(completedParam)
+		if err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "completed must be true or false",
+			})
+			return
+		}
+		status := completedStatus(completed)
+		query.Status = &status
 	}
 
 	// Parse optional priority filter
@@ -112,19 +275,137 @@ func (h *TaskHandlers) GetTasks(c *gin.Context) {
 		query.Priority = &priorityParam
 	}
 
-	// Get tasks from application service
-	tasks, err := h.taskService.GetUserTasks(query)
+	// Parse optional tag filter
+	if tagParam := c.Query("tag"); tagParam != "" {
+		query.Tag = &tagParam
+	}
+
+	// Parse optional sync cursor; trashed tasks are included so clients can reconcile
+	if sinceParam := c.Query("updated_since"); sinceParam != "" {
+		since, err := time.Parse(time.RFC3339, sinceParam)
+		if err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "updated_since must be an RFC3339 timestamp",
+			})
+			return
+		}
+		query.UpdatedSince = &since
+	}
+
+	// Parse optional sorting (validated by the application service)
+	query.Sort = c.Query("sort")
+	query.Order = c.Query("order")
+
+	// Parse optional pagination; without a limit the whole list is returned
+	page, err := parseTaskListPage(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_query",
+			Code:    CodeBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
+	query.Limit = page.limit
+	query.Offset = page.offset
+
+	// Unchanged lists are answered from a summary query, without loading the
+	// tasks. The version is read first, so a change made while the list loads
+	// only makes the next poll fetch the list again.
+	version, err := h.taskService.GetTaskListVersion(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	location := h.userLocation(c.Request.Context(), userIDUint)
+	etag := taskListETag(version, c.Request.URL.Query(), location, time.Now())
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		setCacheValidators(c, etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Get the page of tasks, and how many match, from application service
+	tasks, matching, err := h.taskService.GetUserTasks(c.Request.Context(), query)
+	if err != nil {
+		// Invalid filters or sorting are a malformed query rather than an invalid entity
+		if apperrors.IsKind(err, apperrors.KindValidation) {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	// Advise clients to filter or paginate when the result set is very large
+	c.Header("X-Total-Count", strconv.FormatInt(matching, 10))
+	if page.limit == 0 && h.listWarningThreshold > 0 && matching > int64(h.listWarningThreshold) {
+		c.Header("X-Result-Truncated", "true")
+	}
+	setPaginationHeaders(c, page, matching)
+
+	total, err := h.taskService.CountUserTasks(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
 	// Convert to response format
 	response := TaskListResponse{
-		Tasks: h.convertTasksToResponse(tasks),
+		Tasks: h.convertTasksToResponse(tasks, location),
+		Count: len(tasks),
+		Total: &total,
+	}
+
+	setCacheValidators(c, etag)
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchTasks handles GET /api/v1/tasks/search?q=
+func (h *TaskHandlers) SearchTasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	tasks, err := h.taskService.SearchTasks(c.Request.Context(), userIDUint, c.Query("q"))
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "Query parameter 'q' is required",
+			})
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	response := TaskListResponse{
+		Tasks: h.convertTasksToResponse(tasks, h.userLocation(c.Request.Context(), userIDUint)),
 		Count: len(tasks),
 	}
 
@@ -136,8 +417,9 @@ func (h *TaskHandlers) CreateTask(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -145,8 +427,9 @@ func (h *TaskHandlers) CreateTask(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -155,11 +438,7 @@ func (h *TaskHandlers) CreateTask(c *gin.Context) {
 	// Parse request body
 	var req CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-			Details: err.Error(),
-		})
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
 		return
 	}
 
@@ -168,36 +447,292 @@ func (h *TaskHandlers) CreateTask(c *gin.Context) {
 		req.Priority = "medium"
 	}
 
+	location := h.userLocation(c.Request.Context(), userIDUint)
+
 	// Create command
 	cmd := task.CreateTaskCommand{
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
+		Tags:        req.Tags,
+		DueDate:     req.DueDate.In(location),
+		RemindAt:    req.RemindAt.In(location),
 		UserID:      userIDUint,
 	}
 
 	// Create task using application service
-	createdTask, err := h.taskService.CreateTask(cmd)
+	createdTask, err := h.taskService.CreateTask(c.Request.Context(), cmd)
 	if err != nil {
-		// Determine appropriate HTTP status based on error
-		if isValidationError(err) {
-			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
+		c.Error(err)
+		return
+	}
+
+	// Convert to response format
+	response := h.convertTaskToResponse(createdTask, location)
+	c.JSON(http.StatusCreated, response)
+}
+
+// BulkUpdateStatus handles POST /api/v1/tasks/bulk-status
+func (h *TaskHandlers) BulkUpdateStatus(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req BulkUpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	// Update all tasks atomically using application service
+	updatedTasks, err := h.taskService.BulkUpdateStatus(c.Request.Context(), userIDUint, req.TaskIDs, req.Status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkUpdateStatusResponse{
+		Updated: len(updatedTasks),
+		Tasks:   h.convertTasksToResponse(updatedTasks, h.userLocation(c.Request.Context(), userIDUint)),
+	})
+}
+
+// GetTaskStats handles GET /api/v1/tasks/stats
+func (h *TaskHandlers) GetTaskStats(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	stats, err := h.taskService.GetTaskStats(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TaskStatsResponse{
+		Total:   stats.Total,
+		Overdue: stats.Overdue,
+		ByStatus: TaskStatusCounts{
+			Pending:   stats.ByStatus.Pending,
+			Completed: stats.ByStatus.Completed,
+			Archived:  stats.ByStatus.Archived,
+		},
+		ByPriority: TaskPriorityCounts{
+			Low:    stats.ByPriority.Low,
+			Medium: stats.ByPriority.Medium,
+			High:   stats.ByPriority.High,
+		},
+	})
+}
+
+// GetOverdueTasks handles GET /api/v1/tasks/overdue
+func (h *TaskHandlers) GetOverdueTasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	tasks, err := h.taskService.GetOverdueTasks(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TaskListResponse{
+		Tasks: h.convertTasksToResponse(tasks, h.userLocation(c.Request.Context(), userIDUint)),
+		Count: len(tasks),
+	})
+}
+
+// defaultDueSoonWindow is how far ahead GET /tasks/due-soon looks without ?within
+const defaultDueSoonWindow = 24 * time.Hour
+
+// GetTasksDueSoon handles GET /api/v1/tasks/due-soon?within=24h, listing
+// pending tasks due within the window, earliest first
+func (h *TaskHandlers) GetTasksDueSoon(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	within := defaultDueSoonWindow
+	if withinParam := c.Query("within"); withinParam != "" {
+		parsed, err := time.ParseDuration(withinParam)
+		if err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "within must be a duration such as 24h or 90m",
 			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "creation_failed",
-				Message: "Failed to create task",
-				Details: err.Error(),
+			return
+		}
+		within = parsed
+	}
+
+	tasks, err := h.taskService.GetTasksDueSoon(c.Request.Context(), userIDUint, within)
+	if err != nil {
+		if apperrors.IsKind(err, apperrors.KindValidation) {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: err.Error(),
 			})
+			return
 		}
+		c.Error(err)
 		return
 	}
 
-	// Convert to response format
-	response := h.convertTaskToResponse(createdTask)
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusOK, TaskListResponse{
+		Tasks: h.convertTasksToResponse(tasks, h.userLocation(c.Request.Context(), userIDUint)),
+		Count: len(tasks),
+	})
+}
+
+// GetTrashedTasks handles GET /api/v1/tasks/trash
+func (h *TaskHandlers) GetTrashedTasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	tasks, err := h.taskService.GetTrashedTasks(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TaskListResponse{
+		Tasks: h.convertTasksToResponse(tasks, h.userLocation(c.Request.Context(), userIDUint)),
+		Count: len(tasks),
+	})
+}
+
+// RestoreTask handles POST /api/v1/tasks/:id/restore
+func (h *TaskHandlers) RestoreTask(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse task ID from path
+	taskIDParam := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDParam, 10, 32)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_id",
+			Code:    CodeBadRequest,
+			Message: "Invalid task ID format",
+		})
+		return
+	}
+
+	restoredTask, err := h.taskService.RestoreTask(c.Request.Context(), uint(taskID), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertTaskToResponse(restoredTask, h.userLocation(c.Request.Context(), userIDUint)))
 }
 
 // GetTask handles GET /api/v1/tasks/:id
@@ -205,8 +740,9 @@ func (h *TaskHandlers) GetTask(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -214,8 +750,9 @@ func (h *TaskHandlers) GetTask(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -225,47 +762,96 @@ func (h *TaskHandlers) GetTask(c *gin.Context) {
 	taskIDParam := c.Param("id")
 	taskID, err := strconv.ParseUint(taskIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_id",
+			Code:    CodeBadRequest,
 			Message: "Invalid task ID format",
 		})
 		return
 	}
 
 	// Get task from application service
-	taskEntity, err := h.taskService.GetTask(uint(taskID), userIDUint)
+	taskEntity, err := h.taskService.GetTask(c.Request.Context(), uint(taskID), userIDUint)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else if isAccessDeniedError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{ // Return 404 instead of 403 for security
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "retrieval_failed",
-				Message: "Failed to retrieve task",
-			})
-		}
+		c.Error(err)
+		return
+	}
+
+	// Clients that already have this version of the task get no body
+	etag := taskETag(taskEntity)
+	setCacheValidators(c, etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
 	// Convert to response format
-	response := h.convertTaskToResponse(taskEntity)
+	response := h.convertTaskToResponse(taskEntity, h.userLocation(c.Request.Context(), userIDUint))
 	c.JSON(http.StatusOK, response)
 }
 
+// taskETag returns a weak ETag for a task, which changes whenever the task is
+// saved. It is weak because is_overdue can flip without the task changing.
+func taskETag(task *entities.Task) string {
+	return fmt.Sprintf(`W/"%d-%d"`, task.ID().Value(), task.UpdatedAt().UnixNano())
+}
+
+// taskListETagPeriod is how long a task list's ETag can stay the same while
+// its tasks do not change; is_overdue is computed against the clock, so it
+// may be stale by at most this much in a 304 response
+const taskListETagPeriod = time.Minute
+
+// taskListETag returns a weak ETag for a user's task list as requested with
+// query and shown in location at now; any change to one of the user's tasks
+// changes the version
+func taskListETag(version repositories.TaskListVersion, query url.Values, location *time.Location, now time.Time) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(query.Encode())) // Encode sorts by key, so parameter order does not matter
+	hash.Write([]byte{0})
+	hash.Write([]byte(location.String()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(strconv.FormatInt(now.Truncate(taskListETagPeriod).Unix(), 10)))
+	return fmt.Sprintf(`W/"list-%d-%d-%d-%x"`, version.Count, unixNanoOrZero(version.LastUpdatedAt), unixNanoOrZero(version.LastDeletedAt), hash.Sum64())
+}
+
+// unixNanoOrZero returns t in Unix nanoseconds, or 0 when it is nil
+func unixNanoOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// setCacheValidators sets the ETag of a task response. Responses are per user,
+// so shared caches must not keep them, and clients must revalidate each time.
+func setCacheValidators(c *gin.Context, etag string) {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison that conditional GETs call for
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateTask handles PUT /api/v1/tasks/:id
 func (h *TaskHandlers) UpdateTask(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -273,8 +859,9 @@ func (h *TaskHandlers) UpdateTask(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -284,8 +871,9 @@ func (h *TaskHandlers) UpdateTask(c *gin.Context) {
 	taskIDParam := c.Param("id")
 	taskID, err := strconv.ParseUint(taskIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_id",
+			Code:    CodeBadRequest,
 			Message: "Invalid task ID format",
 		})
 		return
@@ -294,64 +882,55 @@ func (h *TaskHandlers) UpdateTask(c *gin.Context) {
 	// Parse request body
 	var req UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-			Details: err.Error(),
-		})
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
 		return
 	}
 
+	status := req.Status
+	if status == nil && req.Completed != nil {
+		completed := completedStatus(*req.Completed)
+		status = &completed
+	}
+
+	location := h.userLocation(c.Request.Context(), userIDUint)
+
 	// Create command
 	cmd := task.UpdateTaskCommand{
-		TaskID:      uint(taskID),
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      req.Status,
-		Priority:    req.Priority,
-		UserID:      userIDUint,
+		TaskID:        uint(taskID),
+		Title:         req.Title,
+		Description:   req.Description,
+		Status:        status,
+		Priority:      req.Priority,
+		Tags:          req.Tags,
+		DueDate:       req.DueDate.In(location),
+		ClearDueDate:  req.DueDate.IsNull(),
+		RemindAt:      req.RemindAt.In(location),
+		ClearRemindAt: req.RemindAt.IsNull(),
+		UserID:        userIDUint,
+		Restore:       req.Restore,
+		Version:       req.Version,
 	}
 
 	// Update task using application service
-	updatedTask, err := h.taskService.UpdateTask(cmd)
+	updatedTask, err := h.taskService.UpdateTask(c.Request.Context(), cmd)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else if isAccessDeniedError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{ // Return 404 instead of 403 for security
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else if isValidationError(err) {
-			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "update_failed",
-				Message: "Failed to update task",
-				Details: err.Error(),
-			})
-		}
+		c.Error(err)
 		return
 	}
 
 	// Convert to response format
-	response := h.convertTaskToResponse(updatedTask)
+	response := h.convertTaskToResponse(updatedTask, location)
 	c.JSON(http.StatusOK, response)
 }
 
-// DeleteTask handles DELETE /api/v1/tasks/:id
+// DeleteTask handles DELETE /api/v1/tasks/:id[?permanent=true]
 func (h *TaskHandlers) DeleteTask(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -359,8 +938,9 @@ func (h *TaskHandlers) DeleteTask(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -370,108 +950,123 @@ func (h *TaskHandlers) DeleteTask(c *gin.Context) {
 	taskIDParam := c.Param("id")
 	taskID, err := strconv.ParseUint(taskIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondWithError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_id",
+			Code:    CodeBadRequest,
 			Message: "Invalid task ID format",
 		})
 		return
 	}
 
-	// Delete task using application service
-	err = h.taskService.DeleteTask(uint(taskID), userIDUint)
-	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else if isAccessDeniedError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{ // Return 404 instead of 403 for security
-				Error:   "task_not_found",
-				Message: "Task not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "deletion_failed",
-				Message: "Failed to delete task",
+	// Tasks go to the trash unless permanent deletion is requested
+	permanent := false
+	if permanentParam := c.Query("permanent"); permanentParam != "" {
+		permanent, err = strconv.ParseBool(permanentParam)
+		if err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "permanent must be true or false",
 			})
+			return
 		}
+	}
+
+	// Delete task using application service
+	if permanent {
+		err = h.taskService.PermanentlyDeleteTask(c.Request.Context(), uint(taskID), userIDUint)
+	} else {
+		err = h.taskService.DeleteTask(c.Request.Context(), uint(taskID), userIDUint)
+	}
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.auditService.Record(authentities.NewAuditLog(authentities.AuditEventTaskDeleted, userIDUint, c.ClientIP(), c.Request.UserAgent(),
+		authentities.AuditMetadata{"task_id": taskID, "permanent": permanent}))
+
 	// Return 204 No Content for successful deletion
 	c.Status(http.StatusNoContent)
 }
 
 // Helper functions
 
-// convertTaskToResponse converts a domain task entity to HTTP response format
-func (h *TaskHandlers) convertTaskToResponse(taskEntity interface{}) TaskResponse {
-	task, ok := taskEntity.(*entities.Task)
-	if !ok {
-		// Return empty response if type assertion fails
-		return TaskResponse{}
+// completedStatus maps the legacy completed flag to a task status
+func completedStatus(completed bool) string {
+	if completed {
+		return "completed"
 	}
+	return "pending"
+}
 
-	return TaskResponse{
+// convertTaskToResponse converts a domain task entity to HTTP response format,
+// with local times in location
+func (h *TaskHandlers) convertTaskToResponse(task *entities.Task, location *time.Location) TaskResponse {
+	return newTaskResponse(task, location)
+}
+
+// newTaskResponse builds the HTTP representation of a task. Times are given
+// in UTC, and again in location when it is set.
+func newTaskResponse(task *entities.Task, location *time.Location) TaskResponse {
+	tags := make([]string, 0, len(task.Tags()))
+	for _, tag := range task.Tags() {
+		tags = append(tags, tag.Value())
+	}
+
+	response := TaskResponse{
 		ID:          task.ID().Value(),
 		Title:       task.Title().Value(),
 		Description: task.Description().Value(),
 		Status:      task.Status().String(),
+		Completed:   task.Status().IsCompleted(),
 		Priority:    task.Priority().String(),
+		Tags:        tags,
+		DueDate:     utcTime(task.DueDate()),
+		RemindAt:    utcTime(task.RemindAt()),
+		IsOverdue:   task.IsOverdue(time.Now()),
+		DeletedAt:   utcTime(task.DeletedAt()),
+		Deleted:     task.DeletedAt() != nil,
 		UserID:      task.UserID().Value(),
-		CreatedAt:   task.CreatedAt(),
-		UpdatedAt:   task.UpdatedAt(),
-	}
-}
-
-// convertTasksToResponse converts multiple domain task entities to HTTP response format
-func (h *TaskHandlers) convertTasksToResponse(tasks interface{}) []TaskResponse {
-	taskList, ok := tasks.([]*entities.Task)
-	if !ok {
-		// Return empty slice if type assertion fails
-		return []TaskResponse{}
+		CreatedAt:   task.CreatedAt().UTC(),
+		UpdatedAt:   task.UpdatedAt().UTC(),
+		Version:     task.Version(),
 	}
 
-	responses := make([]TaskResponse, 0, len(taskList))
-	for _, task := range taskList {
-		responses = append(responses, h.convertTaskToResponse(task))
+	if location != nil {
+		response.Local = &TaskLocalTimes{
+			Timezone:  location.String(),
+			CreatedAt: task.CreatedAt().In(location).Format(time.RFC3339),
+			UpdatedAt: task.UpdatedAt().In(location).Format(time.RFC3339),
+		}
+		if dueDate := task.DueDate(); dueDate != nil {
+			local := dueDate.In(location).Format(time.RFC3339)
+			response.Local.DueDate = &local
+		}
+		if remindAt := task.RemindAt(); remindAt != nil {
+			local := remindAt.In(location).Format(time.RFC3339)
+			response.Local.RemindAt = &local
+		}
 	}
 
-	return responses
+	return response
 }
 
-// Error checking helper functions
-func isValidationError(err error) bool {
-	if err == nil {
-		return false
+// utcTime returns a copy of t in UTC, or nil
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
 	}
-	// Check if error message contains validation-related keywords
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "validation") ||
-		strings.Contains(errMsg, "invalid") ||
-		strings.Contains(errMsg, "cannot be empty") ||
-		strings.Contains(errMsg, "must be") ||
-		strings.Contains(errMsg, "required")
+	utc := t.UTC()
+	return &utc
 }
 
-func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
+// convertTasksToResponse converts multiple domain task entities to HTTP response format
+func (h *TaskHandlers) convertTasksToResponse(tasks []*entities.Task, location *time.Location) []TaskResponse {
+	responses := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		responses = append(responses, h.convertTaskToResponse(task, location))
 	}
-	// Check if error message contains not found keywords
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "not found") ||
-		strings.Contains(errMsg, "does not exist")
-}
 
-func isAccessDeniedError(err error) bool {
-	if err == nil {
-		return false
-	}
-	// Check if error message contains access denied keywords
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "access denied") ||
-		strings.Contains(errMsg, "not authorized") ||
-		strings.Contains(errMsg, "not owned by")
+	return responses
 }
\ No newline at end of file
@@ -0,0 +1,1573 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	authentities "domain/auth/entities"
+	"domain/task/entities"
+	"domain/task/repositories"
+	"domain/task/services"
+	"domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/application/mappers"
+	"todo-app/application/task"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/dtos"
+	"todo-app/services/audit"
+)
+
+func setupTaskHandlersTest(t *testing.T, userID uint) (*gorm.DB, *gin.Engine) {
+	return setupTaskHandlersTestWithPublisher(t, userID, task.NoopEventPublisher{})
+}
+
+func setupTaskHandlersTestWithPublisher(t *testing.T, userID uint, publisher task.EventPublisher) (*gorm.DB, *gin.Engine) {
+	return setupTaskHandlersTestWith(t, userID, task.TaskQuota{}, publisher)
+}
+
+// ownTaskLimit gives every user a task limit of their own
+type ownTaskLimit int64
+
+func (l ownTaskLimit) TaskLimit(ctx context.Context, userID uservo.UserID) (int64, bool, error) {
+	return int64(l), true, nil
+}
+
+func setupTaskHandlersTestWith(t *testing.T, userID uint, quota task.TaskQuota, publisher task.EventPublisher, configure ...func(*TaskHandlers)) (*gorm.DB, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}))
+
+	repo := persistence.NewGormTaskRepository(db, &mappers.TaskMapper{})
+	taskService := task.NewTaskApplicationService(
+		repo,
+		persistence.NewGormUnitOfWork(db),
+		services.NewTaskValidationService(),
+		services.NewTaskSearchService(repo),
+		valueobjects.DefaultDueDateBounds(),
+		quota,
+		publisher,
+		task.NoopEventBus{},
+	)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	api := router.Group("/api/v1", func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	handlers := NewTaskHandlers(taskService)
+	for _, fn := range configure {
+		fn(handlers)
+	}
+	handlers.RegisterRoutes(api)
+
+	return db, router
+}
+
+func TestSearchTasks_MatchesTitleAndDescription(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Plan trip", Description: "Book hotel in Lisbon", UserID: 1},
+		{Title: "Lisbon photos", UserID: 1},
+		{Title: "Unrelated", UserID: 1},
+		{Title: "Lisbon (someone else)", UserID: 2},
+	}).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/search?q="+url.QueryEscape("LISBON"), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	for _, task := range response.Tasks {
+		assert.Equal(t, uint(1), task.UserID)
+	}
+}
+
+func TestSearchTasks_EmptyQueryReturnsBadRequest(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	for _, target := range []string{"/api/v1/tasks/search", "/api/v1/tasks/search?q=%20%20"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid_query", response.Error)
+	}
+}
+
+func TestGetTasks_SortsByRequestedField(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Medium task", Priority: "medium", UserID: 1},
+		{Title: "High task", Priority: "high", UserID: 1},
+		{Title: "Low task", Priority: "low", UserID: 1},
+	}).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks?sort=priority&order=desc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Tasks, 3)
+	assert.Equal(t, "high", response.Tasks[0].Priority)
+	assert.Equal(t, "medium", response.Tasks[1].Priority)
+	assert.Equal(t, "low", response.Tasks[2].Priority)
+}
+
+func TestGetTasks_InvalidSortReturnsBadRequest(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	for _, target := range []string{"/api/v1/tasks?sort=owner", "/api/v1/tasks?sort=title&order=sideways"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
+
+func performBulkStatus(router *gin.Engine, payload map[string]interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/bulk-status", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func seedBulkTasks(t *testing.T, db *gorm.DB) []dtos.Task {
+	tasks := []dtos.Task{
+		{Title: "First", UserID: 1},
+		{Title: "Second", UserID: 1},
+		{Title: "Third", UserID: 1},
+		{Title: "Not mine", UserID: 2},
+	}
+	require.NoError(t, db.Create(&tasks).Error)
+	return tasks
+}
+
+func countCompleted(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&dtos.Task{}).Where("completed = ?", true).Count(&count).Error)
+	return count
+}
+
+func TestBulkUpdateStatus_CompletesOwnedTasks(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	tasks := seedBulkTasks(t, db)
+
+	w := performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID},
+		"status":   "completed",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response BulkUpdateStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Updated)
+	require.Len(t, response.Tasks, 3)
+	for _, task := range response.Tasks {
+		assert.Equal(t, "completed", task.Status)
+	}
+	assert.Equal(t, int64(3), countCompleted(t, db))
+}
+
+func TestBulkUpdateStatus_UnownedTaskRejectsWholeRequest(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	tasks := seedBulkTasks(t, db)
+
+	for _, ids := range [][]uint{{tasks[0].ID, tasks[3].ID}, {tasks[0].ID, 9999}} {
+		w := performBulkStatus(router, map[string]interface{}{
+			"task_ids": ids,
+			"status":   "completed",
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	}
+	assert.Equal(t, int64(0), countCompleted(t, db))
+}
+
+func TestBulkUpdateStatus_RollsBackOnPartialFailure(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	tasks := seedBulkTasks(t, db)
+
+	// Fail the second task update inside the transaction
+	updates := 0
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_second_update", func(tx *gorm.DB) {
+		updates++
+		if updates == 2 {
+			tx.AddError(errors.New("simulated failure"))
+		}
+	}))
+
+	w := performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID},
+		"status":   "completed",
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	assert.Equal(t, int64(0), countCompleted(t, db))
+}
+
+func TestBulkUpdateStatus_PublishesNoEventsOnRollback(t *testing.T) {
+	publisher := &recordingEventPublisher{}
+	db, router := setupTaskHandlersTestWithPublisher(t, 1, publisher)
+	tasks := seedBulkTasks(t, db)
+
+	// Fail the last task update inside the transaction, after the others are written
+	updates := 0
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_last_update", func(tx *gorm.DB) {
+		updates++
+		if updates == 3 {
+			tx.AddError(errors.New("simulated failure"))
+		}
+	}))
+
+	w := performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID},
+		"status":   "completed",
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	assert.Empty(t, publisher.events)
+}
+
+func TestBulkUpdateStatus_ValidatesRequest(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	assert.Equal(t, http.StatusBadRequest, performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{},
+		"status":   "completed",
+	}).Code)
+	assert.Equal(t, http.StatusBadRequest, performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{1},
+		"status":   "done",
+	}).Code)
+}
+
+func TestGetTaskStats_ReturnsAllCounts(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Late", Priority: "high", UserID: 1, DueDate: &past},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Not mine", UserID: 2},
+	}).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, TaskStatsResponse{
+		Total:      2,
+		Overdue:    1,
+		ByStatus:   TaskStatusCounts{Pending: 1, Completed: 1},
+		ByPriority: TaskPriorityCounts{Medium: 1, High: 1},
+	}, response)
+
+	// Zero counts are still serialised so the shape is stable
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.Contains(t, raw["by_status"], "archived")
+	assert.Contains(t, raw["by_priority"], "low")
+}
+
+func TestGetOverdueTasks_ReturnsPendingTasksPastDue(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	now := time.Now()
+	lateByDay := now.Add(-24 * time.Hour)
+	lateByHour := now.Add(-time.Hour)
+	upcoming := now.Add(time.Hour)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Late by an hour", UserID: 1, DueDate: &lateByHour},
+		{Title: "Late by a day", UserID: 1, DueDate: &lateByDay},
+		{Title: "Upcoming", UserID: 1, DueDate: &upcoming},
+		{Title: "No due date", UserID: 1},
+		{Title: "Done late", Status: "completed", Completed: true, UserID: 1, DueDate: &lateByDay},
+		{Title: "Archived late", Status: "archived", UserID: 1, DueDate: &lateByDay},
+		{Title: "Not mine", UserID: 2, DueDate: &lateByDay},
+	}).Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/overdue", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 2, response.Count)
+
+	// Earliest due date first
+	assert.Equal(t, "Late by a day", response.Tasks[0].Title)
+	assert.Equal(t, "Late by an hour", response.Tasks[1].Title)
+	for _, task := range response.Tasks {
+		assert.True(t, task.IsOverdue)
+		require.NotNil(t, task.DueDate)
+	}
+}
+
+func TestGetTasksDueSoon_ReturnsPendingTasksInWindow(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	now := time.Now()
+	inHour := now.Add(time.Hour)
+	inHalfDay := now.Add(12 * time.Hour)
+	inTwoDays := now.Add(48 * time.Hour)
+	late := now.Add(-time.Hour)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "In half a day", UserID: 1, DueDate: &inHalfDay},
+		{Title: "In an hour", UserID: 1, DueDate: &inHour},
+		{Title: "In two days", UserID: 1, DueDate: &inTwoDays},
+		{Title: "Late", UserID: 1, DueDate: &late},
+		{Title: "Done soon", Status: "completed", Completed: true, UserID: 1, DueDate: &inHour},
+		{Title: "Not mine", UserID: 2, DueDate: &inHour},
+	}).Error)
+
+	titles := func(target string) []string {
+		w := performTaskRequest(router, http.MethodGet, target)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response TaskListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		titles := make([]string, len(response.Tasks))
+		for i, task := range response.Tasks {
+			titles[i] = task.Title
+		}
+		return titles
+	}
+
+	// Defaults to the next 24 hours, earliest due date first
+	assert.Equal(t, []string{"In an hour", "In half a day"}, titles("/api/v1/tasks/due-soon"))
+	assert.Equal(t, []string{"In an hour"}, titles("/api/v1/tasks/due-soon?within=2h"))
+	assert.Equal(t, []string{"In an hour", "In half a day", "In two days"}, titles("/api/v1/tasks/due-soon?within=72h"))
+}
+
+func TestGetTasksDueSoon_InvalidWindow(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	for _, within := range []string{"tomorrow", "0s", "-1h", "721h"} {
+		w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks/due-soon?within="+within)
+		assert.Equal(t, http.StatusBadRequest, w.Code, within)
+
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "invalid_query", body.Error, within)
+	}
+}
+
+func TestGetTask_IsOverdueFlag(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	seed := []dtos.Task{
+		{Title: "Late", UserID: 1, DueDate: &past},
+		{Title: "Upcoming", UserID: 1, DueDate: &future},
+		{Title: "Done late", Status: "completed", Completed: true, UserID: 1, DueDate: &past},
+		{Title: "No due date", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	expected := map[string]bool{"Late": true, "Upcoming": false, "Done late": false, "No due date": false}
+	for _, task := range seed {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+strconv.FormatUint(uint64(task.ID), 10), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		// The flag is always serialised
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+		assert.Equal(t, expected[task.Title], raw["is_overdue"], task.Title)
+	}
+}
+
+func TestCreateTask_ReturnsPersistedID(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	var ids []uint
+	for _, title := range []string{"First", "Second"} {
+		body, _ := json.Marshal(map[string]interface{}{"title": title, "tags": []string{"work"}})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created TaskResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		require.NotZero(t, created.ID)
+		ids = append(ids, created.ID)
+
+		// The returned ID can be fetched straight away
+		w = performTaskRequest(router, http.MethodGet, "/api/v1/tasks/"+strconv.FormatUint(uint64(created.ID), 10))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var fetched TaskResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+		assert.Equal(t, title, fetched.Title)
+		assert.Equal(t, []string{"work"}, fetched.Tags)
+	}
+	assert.NotEqual(t, ids[0], ids[1])
+}
+
+func TestCreateTask_RejectedWhenQuotaReached(t *testing.T) {
+	db, router := setupTaskHandlersTestWith(t, 1, task.TaskQuota{MaxTasks: 2}, task.NoopEventPublisher{})
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[1]).Error)
+
+	// Trashed tasks and other users' tasks do not count towards the quota
+	w := performCreateTask(router, map[string]interface{}{"title": "Second"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performCreateTask(router, map[string]interface{}{"title": "Third"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task_quota_exceeded", response.Error)
+	assert.Equal(t, CodeQuota, response.Code)
+	assert.Contains(t, response.Message, "limit of 2 tasks")
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.Task{}).Where("user_id = ?", 1).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestCreateTask_QuotaCanExcludeFinishedTasks(t *testing.T) {
+	quota := task.TaskQuota{MaxTasks: 2, ExcludeFinished: true}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Shelved", Status: "archived", UserID: 1},
+	}).Error)
+
+	w := performCreateTask(router, map[string]interface{}{"title": "Second open"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performCreateTask(router, map[string]interface{}{"title": "Third open"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+func TestCreateTask_QuotaErrorReportsCountAndLimit(t *testing.T) {
+	// The user's own limit takes precedence over MaxTasks
+	quota := task.TaskQuota{MaxTasks: 10, Limits: ownTaskLimit(2)}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}).Error)
+
+	w := performCreateTask(router, map[string]interface{}{"title": "One too many"})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var response struct {
+		ErrorResponse
+		Details TaskQuotaDetails `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task_quota_exceeded", response.Error)
+	assert.Equal(t, CodeQuota, response.Code)
+	assert.Equal(t, TaskQuotaDetails{Count: 2, Limit: 2}, response.Details)
+	assert.Contains(t, response.Message, "limit of 2 tasks")
+}
+
+func TestCreateTask_AllowedJustUnderQuota(t *testing.T) {
+	quota := task.TaskQuota{ExcludeFinished: true, Limits: ownTaskLimit(2)}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Shelved", Status: "archived", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[2]).Error)
+
+	w := performCreateTask(router, map[string]interface{}{"title": "Last one"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performCreateTask(router, map[string]interface{}{"title": "One too many"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+func TestTaskQuota_AppliesToUnarchivingAndRestoring(t *testing.T) {
+	quota := task.TaskQuota{MaxTasks: 1, ExcludeFinished: true}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Shelved", Status: "archived", UserID: 1},
+		{Title: "Trashed", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[2]).Error)
+
+	w := performUpdateTask(router, seed[1].ID, map[string]interface{}{"status": "pending", "restore": true})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+strconv.FormatUint(uint64(seed[2].ID), 10)+"/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	// Once a task is archived there is room again
+	w = performUpdateTask(router, seed[0].ID, map[string]interface{}{"status": "archived"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = performUpdateTask(router, seed[1].ID, map[string]interface{}{"status": "pending", "restore": true})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestTaskQuota_AppliesToBulkReopening(t *testing.T) {
+	quota := task.TaskQuota{MaxTasks: 2, ExcludeFinished: true}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Also done", Status: "completed", Completed: true, UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	// Reopening both would make three pending tasks, so neither is reopened
+	w := performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{seed[1].ID, seed[2].ID},
+		"status":   "pending",
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Equal(t, int64(2), countCompleted(t, db))
+
+	w = performBulkStatus(router, map[string]interface{}{
+		"task_ids": []uint{seed[0].ID, seed[1].ID},
+		"status":   "pending",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, int64(1), countCompleted(t, db))
+}
+
+func performUpdateTask(router *gin.Engine, taskID uint, payload map[string]interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+strconv.FormatUint(uint64(taskID), 10), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateTask_ReplacesNormalizedTags(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Tag me", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"tags": []string{" Work", "work", "HOME"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"work", "home"}, response.Tags)
+
+	// Replacing the set detaches tags that are no longer listed
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"tags": []string{"home"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var links int64
+	require.NoError(t, db.Table("task_tags").Count(&links).Error)
+	assert.Equal(t, int64(1), links)
+
+	// An empty list clears them; omitting the field leaves them alone
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"title": "Renamed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"home"}, response.Tags)
+
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"tags": []string{}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Tags)
+}
+
+func TestUpdateTask_RollsBackWhenTagsFail(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Tag me", Priority: "low", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	// Fail linking tags after the task row has been updated
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_task_tags", func(tx *gorm.DB) {
+		if tx.Statement.Table == "task_tags" {
+			tx.AddError(errors.New("simulated failure"))
+		}
+	}))
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{
+		"title":    "Renamed",
+		"priority": "high",
+		"tags":     []string{"work"},
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+
+	var stored dtos.Task
+	require.NoError(t, db.First(&stored, seed.ID).Error)
+	assert.Equal(t, "Tag me", stored.Title)
+	assert.Equal(t, "low", stored.Priority)
+
+	var tags int64
+	require.NoError(t, db.Model(&dtos.Tag{}).Count(&tags).Error)
+	assert.Equal(t, int64(0), tags)
+}
+
+func TestUpdateTask_BlankTagIsRejected(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Tag me", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"tags": []string{"work", "  "}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+func TestGetTasks_FiltersByTag(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := []dtos.Task{
+		{Title: "Work task", UserID: 1},
+		{Title: "Home task", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.Equal(t, http.StatusOK, performUpdateTask(router, seed[0].ID, map[string]interface{}{"tags": []string{"work"}}).Code)
+	require.Equal(t, http.StatusOK, performUpdateTask(router, seed[1].ID, map[string]interface{}{"tags": []string{"home"}}).Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks?tag=Work", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "Work task", response.Tasks[0].Title)
+	assert.Equal(t, []string{"work"}, response.Tasks[0].Tags)
+}
+
+func performTaskRequest(router *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetTask_ConditionalGetWithETag(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Poll me", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+	taskPath := "/api/v1/tasks/" + strconv.FormatUint(uint64(seed.ID), 10)
+
+	w := performTaskRequest(router, http.MethodGet, taskPath)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `W/"`), etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	conditionalGet := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, taskPath, nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// An unchanged task is not sent again, whether the client lists one tag or several
+	for _, ifNoneMatch := range []string{etag, `"other", ` + etag, strings.TrimPrefix(etag, "W/")} {
+		w = conditionalGet(ifNoneMatch)
+		assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	}
+
+	// Saving the task changes its ETag
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"title": "Polled"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = conditionalGet(etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	var response TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Polled", response.Title)
+}
+
+// conditionalTaskGet requests target with If-None-Match set to etag
+func conditionalTaskGet(router *gin.Engine, target, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetTasks_ConditionalGetWithETag(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := []dtos.Task{
+		{Title: "First", UserID: 1},
+		{Title: "Second", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	// An unchanged list is not sent again
+	w = conditionalTaskGet(router, "/api/v1/tasks", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	// Each query has its own ETag, whatever the parameter order
+	w = conditionalTaskGet(router, "/api/v1/tasks?status=pending", etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	filtered := w.Header().Get("ETag")
+	assert.NotEqual(t, etag, filtered)
+	w = conditionalTaskGet(router, "/api/v1/tasks?sort=title&status=pending", filtered)
+	require.Equal(t, http.StatusOK, w.Code)
+	sorted := w.Header().Get("ETag")
+	assert.NotEqual(t, filtered, sorted)
+	assert.Equal(t, http.StatusNotModified, conditionalTaskGet(router, "/api/v1/tasks?status=pending&sort=title", sorted).Code)
+
+	// Updating, trashing, restoring and creating tasks each change the list's ETag
+	changes := []func(){
+		func() { performUpdateTask(router, seed[0].ID, map[string]interface{}{"title": "First, renamed"}) },
+		func() {
+			performTaskRequest(router, http.MethodDelete, "/api/v1/tasks/"+strconv.FormatUint(uint64(seed[1].ID), 10))
+		},
+		func() {
+			performTaskRequest(router, http.MethodPost, "/api/v1/tasks/"+strconv.FormatUint(uint64(seed[1].ID), 10)+"/restore")
+		},
+		func() { performCreateTask(router, map[string]interface{}{"title": "Third"}) },
+	}
+	for i, change := range changes {
+		change()
+		w = conditionalTaskGet(router, "/api/v1/tasks", etag)
+		require.Equal(t, http.StatusOK, w.Code, "change %d", i)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"), "change %d", i)
+		etag = w.Header().Get("ETag")
+	}
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Count)
+}
+
+func TestTaskListETag_ChangesWithTimezoneAndClock(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	version := repositories.TaskListVersion{Count: 2}
+	query := url.Values{"status": {"pending"}}
+	now := time.Date(2024, 7, 1, 12, 0, 10, 0, time.UTC)
+
+	etag := taskListETag(version, query, time.UTC, now)
+	assert.Equal(t, etag, taskListETag(version, query, time.UTC, now.Add(30*time.Second)))
+
+	// Local times and is_overdue depend on the timezone and the clock
+	assert.NotEqual(t, etag, taskListETag(version, query, tokyo, now))
+	assert.NotEqual(t, etag, taskListETag(version, query, time.UTC, now.Add(taskListETagPeriod)))
+}
+
+func TestDeleteTask_MovesTaskToTrashAndRestores(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := []dtos.Task{
+		{Title: "Oops", UserID: 1},
+		{Title: "Kept", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	taskPath := "/api/v1/tasks/" + strconv.FormatUint(uint64(seed[0].ID), 10)
+
+	w := performTaskRequest(router, http.MethodDelete, taskPath)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusNotFound, performTaskRequest(router, http.MethodGet, taskPath).Code)
+
+	w = performTaskRequest(router, http.MethodGet, "/api/v1/tasks/trash")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var trash TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	require.Equal(t, 1, trash.Count)
+	assert.Equal(t, "Oops", trash.Tasks[0].Title)
+	assert.NotNil(t, trash.Tasks[0].DeletedAt)
+
+	w = performTaskRequest(router, http.MethodPost, taskPath+"/restore")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "Oops", restored.Title)
+	assert.Nil(t, restored.DeletedAt)
+
+	assert.Equal(t, http.StatusOK, performTaskRequest(router, http.MethodGet, taskPath).Code)
+
+	// Only trashed tasks can be restored
+	assert.Equal(t, http.StatusNotFound, performTaskRequest(router, http.MethodPost, taskPath+"/restore").Code)
+}
+
+func TestDeleteTask_PermanentRemovesTask(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := []dtos.Task{
+		{Title: "Gone", UserID: 1},
+		{Title: "Trashed then gone", UserID: 1},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	livePath := "/api/v1/tasks/" + strconv.FormatUint(uint64(seed[0].ID), 10)
+	trashedPath := "/api/v1/tasks/" + strconv.FormatUint(uint64(seed[1].ID), 10)
+	require.Equal(t, http.StatusNoContent, performTaskRequest(router, http.MethodDelete, trashedPath).Code)
+
+	for _, target := range []string{livePath, trashedPath} {
+		w := performTaskRequest(router, http.MethodDelete, target+"?permanent=true")
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	var rows int64
+	require.NoError(t, db.Unscoped().Model(&dtos.Task{}).Count(&rows).Error)
+	assert.Equal(t, int64(0), rows)
+
+	assert.Equal(t, http.StatusBadRequest, performTaskRequest(router, http.MethodDelete, livePath+"?permanent=maybe").Code)
+}
+
+func TestDeleteTask_RecordsAuditEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// The audit writer runs concurrently; share the one in-memory database
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.TaskActivity{}, &dtos.Notification{}, &authentities.AuditLog{}))
+
+	repo := persistence.NewGormTaskRepository(db, &mappers.TaskMapper{})
+	handlers := NewTaskHandlers(task.NewTaskApplicationService(
+		repo,
+		persistence.NewGormUnitOfWork(db),
+		services.NewTaskValidationService(),
+		services.NewTaskSearchService(repo),
+		valueobjects.DefaultDueDateBounds(),
+		task.TaskQuota{},
+		task.NoopEventPublisher{},
+		task.NoopEventBus{},
+	))
+	auditService := audit.NewAuditService(db, 0)
+	handlers.SetAuditService(auditService)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	handlers.RegisterRoutes(router.Group("/api/v1", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	}))
+
+	seed := dtos.Task{Title: "Audited", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+	taskPath := "/api/v1/tasks/" + strconv.FormatUint(uint64(seed.ID), 10)
+	require.Equal(t, http.StatusNoContent, performTaskRequest(router, http.MethodDelete, taskPath+"?permanent=true").Code)
+	// Failed deletions are not audited
+	require.Equal(t, http.StatusNotFound, performTaskRequest(router, http.MethodDelete, taskPath).Code)
+
+	require.NoError(t, auditService.Close(context.Background()))
+
+	logs, total, err := auditService.List(audit.AuditLogFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, authentities.AuditEventTaskDeleted, logs[0].EventType)
+	require.NotNil(t, logs[0].UserID)
+	assert.Equal(t, uint(1), *logs[0].UserID)
+	assert.Equal(t, float64(seed.ID), logs[0].Metadata["task_id"])
+	assert.Equal(t, true, logs[0].Metadata["permanent"])
+}
+
+func TestTrash_EnforcesOwnership(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	theirs := dtos.Task{Title: "Not mine", UserID: 2}
+	require.NoError(t, db.Create(&theirs).Error)
+	require.NoError(t, db.Delete(&theirs).Error)
+	theirPath := "/api/v1/tasks/" + strconv.FormatUint(uint64(theirs.ID), 10)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks/trash")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var trash TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	assert.Equal(t, 0, trash.Count)
+
+	assert.Equal(t, http.StatusNotFound, performTaskRequest(router, http.MethodPost, theirPath+"/restore").Code)
+	assert.Equal(t, http.StatusNotFound, performTaskRequest(router, http.MethodDelete, theirPath+"?permanent=true").Code)
+
+	var rows int64
+	require.NoError(t, db.Unscoped().Model(&dtos.Task{}).Where("deleted_at IS NOT NULL").Count(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+}
+
+func performCreateTask(router *gin.Engine, payload map[string]interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// bindErrorDetails decodes the structured validation details of a 400 response
+func bindErrorDetails(t *testing.T, w *httptest.ResponseRecorder) []FieldError {
+	t.Helper()
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	var body struct {
+		Error   string       `json:"error"`
+		Details []FieldError `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_request", body.Error)
+	return body.Details
+}
+
+func TestCreateTask_ValidationErrorsAreStructured(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	details := bindErrorDetails(t, performCreateTask(router, map[string]interface{}{
+		"priority": "urgent",
+	}))
+
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "title", Rule: "required", Message: "title is required"},
+		{Field: "priority", Rule: "oneof", Message: "priority must be one of: low, medium, high"},
+	}, details)
+}
+
+func TestCreateTask_MalformedJSONGetsGenericDetail(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader([]byte(`{"title":`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	details := bindErrorDetails(t, w)
+	require.Len(t, details, 1)
+	assert.Empty(t, details[0].Field)
+	assert.Equal(t, "format", details[0].Rule)
+}
+
+func TestUpdateTask_ValidationErrorsAreStructured(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	details := bindErrorDetails(t, performUpdateTask(router, 1, map[string]interface{}{
+		"title": "",
+	}))
+
+	assert.Equal(t, []FieldError{
+		{Field: "title", Rule: "min", Message: "title must be at least 1 characters"},
+	}, details)
+}
+
+func TestCreateTask_DueDateBeyondMaxBound(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	w := performCreateTask(router, map[string]interface{}{
+		"title":    "Far future task",
+		"due_date": time.Now().Add(valueobjects.DefaultDueDateMaxOffset + 24*time.Hour),
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_due_date", body.Error)
+}
+
+func TestCreateTask_NearFutureDueDate(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	w := performCreateTask(router, map[string]interface{}{"title": "Near future task", "due_date": dueDate})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.DueDate)
+	assert.True(t, created.DueDate.Equal(dueDate))
+}
+
+func TestUpdateTask_PastDueDateRejected(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": time.Now().Add(-72 * time.Hour)})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+func TestUpdateTask_DueDateAbsentNullOrSet(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	original := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	seed := dtos.Task{Title: "Task", UserID: 1, DueDate: &original}
+	require.NoError(t, db.Create(&seed).Error)
+
+	// An absent key leaves the due date alone
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"title": "Renamed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.NotNil(t, updated.DueDate)
+	assert.True(t, updated.DueDate.Equal(original))
+
+	// A value replaces it
+	replacement := original.Add(24 * time.Hour)
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": replacement})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated = TaskResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.NotNil(t, updated.DueDate)
+	assert.True(t, updated.DueDate.Equal(replacement))
+
+	// An explicit null clears it
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": nil})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated = TaskResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Nil(t, updated.DueDate)
+
+	var stored dtos.Task
+	require.NoError(t, db.First(&stored, seed.ID).Error)
+	assert.Nil(t, stored.DueDate)
+	assert.Equal(t, "Renamed", stored.Title)
+}
+
+func TestUpdateTask_MalformedDueDateReturnsBadRequest(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": "next tuesday"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestCreateTask_ReminderNotBeforeDueDateRejected(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	for _, remindAt := range []time.Time{dueDate, dueDate.Add(time.Hour)} {
+		w := performCreateTask(router, map[string]interface{}{"title": "Task", "due_date": dueDate, "remind_at": remindAt})
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "invalid_remind_at", body.Error)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&dtos.Task{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUpdateTask_DueDateBeforeReminderRejected(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	remindAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	seed := dtos.Task{Title: "Task", UserID: 1, RemindAt: &remindAt}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": remindAt.Add(-time.Hour)})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	// Moving both in one request is checked against the new values
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": remindAt.Add(-time.Hour), "remind_at": remindAt.Add(-2 * time.Hour)})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestUpdateTask_RemindAtReschedulesNotifications(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	remindAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	w := performCreateTask(router, map[string]interface{}{"title": "Task", "remind_at": remindAt})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.RemindAt)
+	assert.True(t, created.RemindAt.Equal(remindAt))
+
+	pending := func() []dtos.Notification {
+		var notifications []dtos.Notification
+		require.NoError(t, db.Where("task_id = ? AND status = ?", created.ID, dtos.NotificationPending).Order("channel").Find(&notifications).Error)
+		return notifications
+	}
+	notifications := pending()
+	require.Len(t, notifications, len(dtos.ReminderChannels))
+	for _, notification := range notifications {
+		assert.True(t, notification.ScheduledFor.Equal(remindAt))
+		assert.Equal(t, uint(1), notification.UserID)
+	}
+
+	// Other edits leave the reminder alone
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"title": "Renamed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, pending(), len(dtos.ReminderChannels))
+
+	// A new time moves the pending notifications
+	rescheduled := remindAt.Add(2 * time.Hour)
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"remind_at": rescheduled})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	moved := pending()
+	require.Len(t, moved, len(dtos.ReminderChannels))
+	for i, notification := range moved {
+		assert.Equal(t, notifications[i].ID, notification.ID)
+		assert.True(t, notification.ScheduledFor.Equal(rescheduled))
+	}
+
+	// Clearing it drops them
+	w = performUpdateTask(router, created.ID, map[string]interface{}{"remind_at": nil})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, pending())
+}
+
+// fixedUserTimezones gives every user the same timezone, or fails lookups
+type fixedUserTimezones struct {
+	location *time.Location
+	err      error
+}
+
+func (f fixedUserTimezones) GetUserTimezone(ctx context.Context, userID uint) (*time.Location, error) {
+	return f.location, f.err
+}
+
+func setupTaskHandlersTestInTimezone(t *testing.T, userTimezones UserTimezones) (*gorm.DB, *gin.Engine) {
+	return setupTaskHandlersTestWith(t, 1, task.TaskQuota{}, task.NoopEventPublisher{}, func(h *TaskHandlers) {
+		h.SetUserTimezones(userTimezones)
+	})
+}
+
+func TestCreateTask_DateOnlyDueDateIsEndOfDayInUserTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	db, router := setupTaskHandlersTestInTimezone(t, fixedUserTimezones{location: newYork})
+
+	date := time.Now().In(newYork).AddDate(0, 0, 7)
+	w := performCreateTask(router, map[string]interface{}{"title": "Pay rent", "due_date": date.Format("2006-01-02")})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	want := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, newYork)
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.DueDate)
+	assert.True(t, created.DueDate.Equal(want), "due %s, want %s", created.DueDate, want)
+	assert.Equal(t, time.UTC, created.DueDate.Location())
+
+	require.NotNil(t, created.Local)
+	assert.Equal(t, "America/New_York", created.Local.Timezone)
+	require.NotNil(t, created.Local.DueDate)
+	assert.Equal(t, want.Format(time.RFC3339), *created.Local.DueDate)
+
+	var stored dtos.Task
+	require.NoError(t, db.First(&stored, created.ID).Error)
+	require.NotNil(t, stored.DueDate)
+	assert.True(t, stored.DueDate.Equal(want))
+}
+
+func TestUpdateTask_DateOnlyDueDateIsEndOfDayInUserTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	db, router := setupTaskHandlersTestInTimezone(t, fixedUserTimezones{location: berlin})
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	date := time.Now().In(berlin).AddDate(0, 1, 0)
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"due_date": date.Format("2006-01-02")})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	want := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, berlin)
+	var updated TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.NotNil(t, updated.DueDate)
+	assert.True(t, updated.DueDate.Equal(want), "due %s, want %s", updated.DueDate, want)
+	require.NotNil(t, updated.Local)
+	require.NotNil(t, updated.Local.DueDate)
+	assert.Equal(t, want.Format(time.RFC3339), *updated.Local.DueDate)
+}
+
+func TestCreateTask_TimestampDueDateIgnoresUserTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	_, router := setupTaskHandlersTestInTimezone(t, fixedUserTimezones{location: tokyo})
+
+	due := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	w := performCreateTask(router, map[string]interface{}{"title": "Call", "due_date": due.Format(time.RFC3339)})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.DueDate)
+	assert.True(t, created.DueDate.Equal(due))
+	require.NotNil(t, created.Local)
+	assert.Equal(t, due.In(tokyo).Format(time.RFC3339), *created.Local.DueDate)
+}
+
+func TestCreateTask_FallsBackToUTCWhenTimezoneLookupFails(t *testing.T) {
+	_, router := setupTaskHandlersTestInTimezone(t, fixedUserTimezones{err: errors.New("user not found")})
+
+	date := time.Now().UTC().AddDate(0, 0, 3)
+	w := performCreateTask(router, map[string]interface{}{"title": "Task", "due_date": date.Format("2006-01-02")})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.DueDate)
+	assert.True(t, created.DueDate.Equal(time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, time.UTC)))
+	require.NotNil(t, created.Local)
+	assert.Equal(t, "UTC", created.Local.Timezone)
+}
+
+func TestUpdateTask_CompletedFlag(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"completed": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.True(t, updated.Completed)
+	assert.Equal(t, "completed", updated.Status)
+
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"completed": false})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.False(t, updated.Completed)
+	assert.Equal(t, "pending", updated.Status)
+}
+
+func TestUpdateTask_StaleVersionReturnsConflict(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	// Two clients read version 1; the first to save moves it to 2
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"title": "First", "version": 1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 2, updated.Version)
+
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"title": "Second", "version": 1})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "task_version_conflict", errResp.Error)
+	assert.Equal(t, CodeConflict, errResp.Code)
+
+	var stored dtos.Task
+	require.NoError(t, db.First(&stored, seed.ID).Error)
+	assert.Equal(t, "First", stored.Title)
+	assert.Equal(t, 2, stored.Version)
+
+	// Updates without a version still apply, and advance it
+	w = performUpdateTask(router, seed.ID, map[string]interface{}{"title": "Third"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 3, updated.Version)
+}
+
+func TestUpdateTask_EmptyTitleReturnsBadRequest(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := dtos.Task{Title: "Task", UserID: 1}
+	require.NoError(t, db.Create(&seed).Error)
+
+	w := performUpdateTask(router, seed.ID, map[string]interface{}{"title": ""})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestGetTasks_FiltersByCompleted(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+	}).Error)
+
+	for completed, title := range map[string]string{"true": "Done", "false": "Open"} {
+		w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks?completed="+completed)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response TaskListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Equal(t, 1, response.Count)
+		assert.Equal(t, title, response.Tasks[0].Title)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, performTaskRequest(router, http.MethodGet, "/api/v1/tasks?completed=maybe").Code)
+}
+
+func TestGetTasks_TotalCountsAllLiveTasks(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seed := []dtos.Task{
+		{Title: "Open", UserID: 1},
+		{Title: "Done", Status: "completed", Completed: true, UserID: 1},
+		{Title: "Trashed", UserID: 1},
+		{Title: "Someone else's", UserID: 2},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.NoError(t, db.Delete(&seed[2]).Error)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks?completed=false")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// count is the filtered page; total is every task the user has outside the trash
+	assert.Equal(t, 1, response.Count)
+	require.NotNil(t, response.Total)
+	assert.Equal(t, int64(2), *response.Total)
+}
+
+func seedTasks(t *testing.T, db *gorm.DB, count int) {
+	tasks := make([]dtos.Task, 0, count)
+	for i := 0; i < count; i++ {
+		tasks = append(tasks, dtos.Task{Title: "Task " + strconv.Itoa(i+1), UserID: 1})
+	}
+	require.NoError(t, db.CreateInBatches(tasks, 100).Error)
+}
+
+func TestGetTasks_LargeResultSetAdvisoryHeaders(t *testing.T) {
+	db, router := setupTaskHandlersTestWith(t, 1, task.TaskQuota{}, task.NoopEventPublisher{}, func(h *TaskHandlers) {
+		h.SetListWarningThreshold(250)
+	})
+	seedTasks(t, db, 300)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-Result-Truncated"))
+	assert.Equal(t, "300", w.Header().Get("X-Total-Count"))
+}
+
+func TestGetTasks_NoAdvisoryHeaderBelowThreshold(t *testing.T) {
+	db, router := setupTaskHandlersTestWith(t, 1, task.TaskQuota{}, task.NoopEventPublisher{}, func(h *TaskHandlers) {
+		h.SetListWarningThreshold(250)
+	})
+	seedTasks(t, db, 10)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Result-Truncated"))
+	assert.Equal(t, "10", w.Header().Get("X-Total-Count"))
+}
+
+func TestGetTasks_PaginationHeaders(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	seedTasks(t, db, 5)
+
+	w := performTaskRequest(router, http.MethodGet, "/api/v1/tasks?sort=created_at&limit=2&offset=2")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, "2", w.Header().Get("X-Page-Limit"))
+	assert.Equal(t, `</api/v1/tasks?limit=2&offset=4&sort=created_at>; rel="next", `+
+		`</api/v1/tasks?limit=2&offset=0&sort=created_at>; rel="prev"`, w.Header().Get("Link"))
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Tasks, 2)
+	assert.Equal(t, "Task 3", response.Tasks[0].Title)
+	assert.Equal(t, 2, response.Count)
+	require.NotNil(t, response.Total)
+	assert.Equal(t, int64(5), *response.Total)
+
+	// The last page has no next link
+	w = performTaskRequest(router, http.MethodGet, "/api/v1/tasks?sort=created_at&limit=2&offset=4")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `</api/v1/tasks?limit=2&offset=2&sort=created_at>; rel="prev"`, w.Header().Get("Link"))
+
+	// Without a limit the whole list is returned, as before
+	w = performTaskRequest(router, http.MethodGet, "/api/v1/tasks")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Page-Limit"))
+	assert.Empty(t, w.Header().Get("Link"))
+
+	for _, query := range []string{"limit=0", "limit=201", "limit=two", "offset=2", "limit=2&offset=-1"} {
+		assert.Equal(t, http.StatusBadRequest, performTaskRequest(router, http.MethodGet, "/api/v1/tasks?"+query).Code, query)
+	}
+}
+
+func TestGetTasks_UpdatedSinceIncludesDeletedTasks(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	since := time.Now().Add(-time.Hour)
+	before := since.Add(-time.Hour)
+	seed := []dtos.Task{
+		{Title: "Stale", UserID: 1, UpdatedAt: before},
+		{Title: "Edited", UserID: 1, UpdatedAt: before},
+		{Title: "Removed", UserID: 1, UpdatedAt: before},
+	}
+	require.NoError(t, db.Create(&seed).Error)
+	require.Equal(t, http.StatusOK, performUpdateTask(router, seed[1].ID, map[string]interface{}{"tags": []string{"work"}}).Code)
+	require.Equal(t, http.StatusNoContent, performTaskRequest(router, http.MethodDelete, "/api/v1/tasks/"+strconv.FormatUint(uint64(seed[2].ID), 10)).Code)
+
+	target := "/api/v1/tasks?updated_since=" + url.QueryEscape(since.Format(time.RFC3339))
+	w := performTaskRequest(router, http.MethodGet, target)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 2, response.Count)
+	deleted := map[string]bool{}
+	for _, task := range response.Tasks {
+		deleted[task.Title] = task.Deleted
+	}
+	assert.Equal(t, map[string]bool{"Edited": false, "Removed": true}, deleted)
+
+	// Other filters still apply
+	w = performTaskRequest(router, http.MethodGet, target+"&tag=work")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "Edited", response.Tasks[0].Title)
+
+	assert.Equal(t, http.StatusBadRequest, performTaskRequest(router, http.MethodGet, "/api/v1/tasks?updated_since=yesterday").Code)
+}
+
+// recordingEventPublisher keeps every published event
+type recordingEventPublisher struct {
+	events []entities.DomainEvent
+}
+
+func (p *recordingEventPublisher) Publish(events []entities.DomainEvent) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func TestUpdateTask_PublishesTaskCompletedOnce(t *testing.T) {
+	publisher := &recordingEventPublisher{}
+	db, router := setupTaskHandlersTestWithPublisher(t, 1, publisher)
+	pending := dtos.Task{Title: "Write report", UserID: 1}
+	require.NoError(t, db.Create(&pending).Error)
+	taskPath := "/api/v1/tasks/" + strconv.Itoa(int(pending.ID))
+
+	complete := func() {
+		body, err := json.Marshal(map[string]string{"status": "completed"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, taskPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	before := time.Now()
+	complete()
+
+	require.Len(t, publisher.events, 1)
+	completed, ok := publisher.events[0].(entities.TaskCompleted)
+	require.True(t, ok)
+	assert.Equal(t, pending.ID, completed.TaskID.Value())
+	assert.Equal(t, uint(1), completed.UserID.Value())
+	assert.False(t, completed.OccurredAt().Before(before))
+
+	// Completing an already completed task records nothing new
+	complete()
+	assert.Len(t, publisher.events, 1)
+}
+
+func TestUpdateTask_PublishesChangedFieldsAndLifecycleEvents(t *testing.T) {
+	publisher := &recordingEventPublisher{}
+	db, router := setupTaskHandlersTestWithPublisher(t, 1, publisher)
+	pending := dtos.Task{Title: "Write report", Description: "Quarterly", Priority: "medium", UserID: 1}
+	require.NoError(t, db.Create(&pending).Error)
+	taskPath := "/api/v1/tasks/" + strconv.Itoa(int(pending.ID))
+
+	update := func(payload map[string]interface{}) {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, taskPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// Unchanged fields are left out of the event
+	update(map[string]interface{}{"title": "Write the report", "description": "Quarterly", "priority": "high"})
+	require.Len(t, publisher.events, 1)
+	updated, ok := publisher.events[0].(entities.TaskUpdated)
+	require.True(t, ok)
+	assert.Equal(t, pending.ID, updated.TaskID.Value())
+	assert.Equal(t, []string{entities.TaskFieldTitle, entities.TaskFieldPriority}, updated.Fields)
+
+	// A request that changes nothing records nothing
+	update(map[string]interface{}{"title": "Write the report"})
+	require.Len(t, publisher.events, 1)
+
+	update(map[string]interface{}{"status": "archived"})
+	update(map[string]interface{}{"status": "pending", "restore": true})
+	require.Len(t, publisher.events, 3)
+	assert.IsType(t, entities.TaskArchived{}, publisher.events[1])
+	assert.IsType(t, entities.TaskReopened{}, publisher.events[2])
+}
+
+func TestUpdateTask_StatusTransitions(t *testing.T) {
+	cases := []struct {
+		from   string
+		body   map[string]interface{}
+		status int
+	}{
+		{"pending", map[string]interface{}{"status": "completed"}, http.StatusOK},
+		{"pending", map[string]interface{}{"status": "archived"}, http.StatusOK},
+		{"completed", map[string]interface{}{"status": "archived"}, http.StatusOK},
+		{"completed", map[string]interface{}{"status": "pending"}, http.StatusOK},
+		{"archived", map[string]interface{}{"status": "pending"}, http.StatusUnprocessableEntity},
+		{"archived", map[string]interface{}{"status": "completed"}, http.StatusUnprocessableEntity},
+		{"archived", map[string]interface{}{"completed": true}, http.StatusUnprocessableEntity},
+		{"archived", map[string]interface{}{"status": "completed", "restore": true}, http.StatusUnprocessableEntity},
+		{"archived", map[string]interface{}{"status": "pending", "restore": true}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %v", tc.from, tc.body), func(t *testing.T) {
+			db, router := setupTaskHandlersTest(t, 1)
+			existing := dtos.Task{Title: "Write report", Status: tc.from, UserID: 1}
+			require.NoError(t, db.Create(&existing).Error)
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+strconv.Itoa(int(existing.ID)), bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+			if tc.status == http.StatusUnprocessableEntity {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "invalid_status_transition", response.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"todo-app/application/task"
+)
+
+// ImportTaskRequest is one task in a POST /tasks/import body. It takes the
+// same fields as CreateTaskRequest, but they are checked task by task so one
+// bad task is reported rather than rejecting the whole body.
+type ImportTaskRequest struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Priority    string       `json:"priority"`
+	Tags        []string     `json:"tags"`
+	DueDate     NullableTime `json:"due_date"`
+}
+
+// TaskImportResponse summarises an import
+type TaskImportResponse struct {
+	Imported int                       `json:"imported"`
+	Failed   int                       `json:"failed"`
+	Errors   []TaskImportErrorResponse `json:"errors"`
+
+	// LimitReached is set when the import stopped at the user's task limit
+	LimitReached bool `json:"limit_reached"`
+}
+
+// TaskImportErrorResponse explains why the task at Index in the request was
+// not imported
+type TaskImportErrorResponse struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// ImportTasks handles POST /api/v1/tasks/import[?atomic=true], creating a task
+// for each valid object in a JSON array. Invalid tasks are listed in the
+// response; with atomic=true any of them means none are imported and the
+// summary comes back with 422.
+func (h *TaskHandlers) ImportTasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	atomic := false
+	if atomicParam := c.Query("atomic"); atomicParam != "" {
+		var err error
+		atomic, err = strconv.ParseBool(atomicParam)
+		if err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_query",
+				Code:    CodeBadRequest,
+				Message: "atomic must be true or false",
+			})
+			return
+		}
+	}
+
+	var rows []ImportTaskRequest
+	if err := c.ShouldBindJSON(&rows); err != nil {
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
+		return
+	}
+
+	location := h.userLocation(c.Request.Context(), userIDUint)
+	cmds := make([]task.CreateTaskCommand, len(rows))
+	for i, row := range rows {
+		priority := row.Priority
+		if priority == "" {
+			priority = "medium"
+		}
+		cmds[i] = task.CreateTaskCommand{
+			Title:       row.Title,
+			Description: row.Description,
+			Priority:    priority,
+			Tags:        row.Tags,
+			DueDate:     row.DueDate.In(location),
+		}
+	}
+
+	result, err := h.taskService.ImportTasks(c.Request.Context(), task.ImportTasksCommand{
+		UserID: userIDUint,
+		Tasks:  cmds,
+		Atomic: atomic,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := TaskImportResponse{
+		Imported:     len(result.Imported),
+		Failed:       len(result.Errors),
+		Errors:       make([]TaskImportErrorResponse, 0, len(result.Errors)),
+		LimitReached: result.LimitReached,
+	}
+	for _, importErr := range result.Errors {
+		response.Errors = append(response.Errors, TaskImportErrorResponse{
+			Index:   importErr.Index,
+			Message: importErr.Err.Error(),
+		})
+	}
+
+	status := http.StatusOK
+	if atomic && response.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, response)
+}
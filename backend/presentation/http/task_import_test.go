@@ -0,0 +1,203 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"todo-app/application/task"
+	"todo-app/internal/dtos"
+)
+
+func performImport(router *gin.Engine, target string, payload interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeImport(t *testing.T, w *httptest.ResponseRecorder) TaskImportResponse {
+	var response TaskImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response
+}
+
+func storedTaskTitles(t *testing.T, db *gorm.DB) []string {
+	var titles []string
+	require.NoError(t, db.Model(&dtos.Task{}).Order("id").Pluck("title", &titles).Error)
+	return titles
+}
+
+func TestImportTasks_ImportsValidTasksAndReportsInvalidOnes(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	w := performImport(router, "/api/v1/tasks/import", []map[string]interface{}{
+		{"title": "Buy milk", "priority": "high", "tags": []string{"home"}, "due_date": due},
+		{"title": ""},
+		{"title": "Plan trip", "priority": "urgent"},
+		{"title": "Write report", "description": "Quarterly"},
+		{"title": "Old", "due_date": time.Now().Add(-72 * time.Hour)},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	response := decodeImport(t, w)
+	assert.Equal(t, 2, response.Imported)
+	assert.Equal(t, 3, response.Failed)
+	assert.False(t, response.LimitReached)
+	require.Len(t, response.Errors, 3)
+	for i, index := range []int{1, 2, 4} {
+		assert.Equal(t, index, response.Errors[i].Index)
+		assert.NotEmpty(t, response.Errors[i].Message)
+	}
+
+	assert.Equal(t, []string{"Buy milk", "Write report"}, storedTaskTitles(t, db))
+
+	var stored dtos.Task
+	require.NoError(t, db.Preload("Tags").Where("title = ?", "Buy milk").First(&stored).Error)
+	assert.Equal(t, "high", stored.Priority)
+	require.NotNil(t, stored.DueDate)
+	assert.True(t, stored.DueDate.Equal(due))
+	require.Len(t, stored.Tags, 1)
+	assert.Equal(t, "home", stored.Tags[0].Name)
+
+	var defaulted dtos.Task
+	require.NoError(t, db.Where("title = ?", "Write report").First(&defaulted).Error)
+	assert.Equal(t, "medium", defaulted.Priority)
+	assert.Equal(t, uint(1), defaulted.UserID)
+}
+
+func TestImportTasks_AtomicImportsNothingWhenATaskIsInvalid(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+
+	w := performImport(router, "/api/v1/tasks/import?atomic=true", []map[string]interface{}{
+		{"title": "Valid"},
+		{"title": strings.Repeat("x", 501)},
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	response := decodeImport(t, w)
+	assert.Equal(t, 0, response.Imported)
+	assert.Equal(t, 1, response.Failed)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, 1, response.Errors[0].Index)
+	assert.Empty(t, storedTaskTitles(t, db))
+
+	w = performImport(router, "/api/v1/tasks/import?atomic=true", []map[string]interface{}{
+		{"title": "One"},
+		{"title": "Two"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 2, decodeImport(t, w).Imported)
+	assert.Equal(t, []string{"One", "Two"}, storedTaskTitles(t, db))
+}
+
+func TestImportTasks_TasksOverQuotaFail(t *testing.T) {
+	db, router := setupTaskHandlersTestWith(t, 1, task.TaskQuota{MaxTasks: 3}, task.NoopEventPublisher{})
+	require.NoError(t, db.Create(&dtos.Task{Title: "Existing", UserID: 1}).Error)
+
+	rows := []map[string]interface{}{{"title": "A"}, {"title": "B"}, {"title": "C"}}
+
+	w := performImport(router, "/api/v1/tasks/import?atomic=true", rows)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	response := decodeImport(t, w)
+	assert.Equal(t, 0, response.Imported)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, 2, response.Errors[0].Index)
+	assert.Equal(t, []string{"Existing"}, storedTaskTitles(t, db))
+
+	w = performImport(router, "/api/v1/tasks/import", rows)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	response = decodeImport(t, w)
+	assert.Equal(t, 2, response.Imported)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, 2, response.Errors[0].Index)
+	assert.Contains(t, response.Errors[0].Message, "limit of 3 tasks")
+	assert.Equal(t, []string{"Existing", "A", "B"}, storedTaskTitles(t, db))
+}
+
+func TestImportTasks_StopsAtQuota(t *testing.T) {
+	quota := task.TaskQuota{Limits: ownTaskLimit(3)}
+	db, router := setupTaskHandlersTestWith(t, 1, quota, task.NoopEventPublisher{})
+	require.NoError(t, db.Create(&dtos.Task{Title: "Existing", UserID: 1}).Error)
+
+	w := performImport(router, "/api/v1/tasks/import?atomic=true", []map[string]interface{}{{"title": "A"}, {"title": "B"}, {"title": "C"}})
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	response := decodeImport(t, w)
+	assert.Equal(t, 0, response.Imported)
+	assert.True(t, response.LimitReached)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, 2, response.Errors[0].Index)
+	assert.Equal(t, []string{"Existing"}, storedTaskTitles(t, db))
+
+	rows := []map[string]interface{}{{"title": "A"}, {"title": ""}, {"title": "B"}, {"title": "C"}, {"title": "D"}}
+
+	// The valid tasks fill the user's remaining room, and the import stops there
+	w = performImport(router, "/api/v1/tasks/import", rows)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	response = decodeImport(t, w)
+	assert.Equal(t, 2, response.Imported)
+	assert.Equal(t, 3, response.Failed)
+	assert.True(t, response.LimitReached)
+	require.Len(t, response.Errors, 3)
+	assert.Equal(t, 1, response.Errors[0].Index)
+	assert.Equal(t, 3, response.Errors[1].Index)
+	assert.Contains(t, response.Errors[1].Message, "limit of 3 tasks")
+	assert.Equal(t, 4, response.Errors[2].Index)
+	assert.Equal(t, []string{"Existing", "A", "B"}, storedTaskTitles(t, db))
+
+	w = performImport(router, "/api/v1/tasks/import", []map[string]interface{}{{"title": "E"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	response = decodeImport(t, w)
+	assert.Equal(t, 0, response.Imported)
+	assert.True(t, response.LimitReached)
+}
+
+func TestImportTasks_RejectsMalformedRequests(t *testing.T) {
+	_, router := setupTaskHandlersTest(t, 1)
+
+	w := performImport(router, "/api/v1/tasks/import", map[string]interface{}{"title": "Not an array"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = performImport(router, "/api/v1/tasks/import", []map[string]interface{}{{"title": 42}})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = performImport(router, "/api/v1/tasks/import?atomic=maybe", []map[string]interface{}{{"title": "Task"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	rows := make([]map[string]interface{}, task.MaxImportTasks+1)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"title": "Task"}
+	}
+	w = performImport(router, "/api/v1/tasks/import", rows)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+func TestImportTasks_AcceptsExportedTasks(t *testing.T) {
+	db, router := setupTaskHandlersTest(t, 1)
+	due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.Create(&[]dtos.Task{
+		{Title: "First", Priority: "low", UserID: 1, DueDate: &due},
+		{Title: "Second", Priority: "high", UserID: 1},
+	}).Error)
+
+	w := performExport(router, "/api/v1/tasks/export?format=json")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var exported []interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+
+	w = performImport(router, "/api/v1/tasks/import", exported)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 2, decodeImport(t, w).Imported)
+	assert.Equal(t, []string{"First", "Second", "First", "Second"}, storedTaskTitles(t, db))
+}
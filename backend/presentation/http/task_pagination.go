@@ -0,0 +1,78 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTaskListLimit is the largest page of GET /tasks a client may ask for
+const maxTaskListLimit = 200
+
+// taskListPage is the part of the task list a client asked for with limit and
+// offset; a zero limit means the whole list
+type taskListPage struct {
+	limit  int
+	offset int
+}
+
+// parseTaskListPage reads the optional limit and offset query parameters
+func parseTaskListPage(c *gin.Context) (taskListPage, error) {
+	var page taskListPage
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxTaskListLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", maxTaskListLimit)
+		}
+		page.limit = limit
+	}
+
+	if offsetParam := c.Query("offset"); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset must be a non-negative integer")
+		}
+		if page.limit == 0 {
+			return page, fmt.Errorf("offset requires limit")
+		}
+		page.offset = offset
+	}
+	return page, nil
+}
+
+// setPaginationHeaders describes a page of total items in X-Page-Limit and an
+// RFC 8288 Link header with next and prev URLs relative to the request, which
+// keep its other query parameters
+func setPaginationHeaders(c *gin.Context, page taskListPage, total int64) {
+	if page.limit == 0 {
+		return
+	}
+	c.Header("X-Page-Limit", strconv.Itoa(page.limit))
+
+	var links []string
+	if int64(page.offset+page.limit) < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(c, page.limit, page.offset+page.limit)))
+	}
+	if page.offset > 0 {
+		prev := max(min(int64(page.offset), total)-int64(page.limit), 0)
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(c, page.limit, int(prev))))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL returns the request's path and query with limit and offset
+// replaced. It is left relative because the host and scheme clients see
+// depend on the proxies in front of the server.
+func pageURL(c *gin.Context, limit, offset int) string {
+	query := c.Request.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	target := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	return target.String()
+}
@@ -2,11 +2,11 @@ package http
 
 import (
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	authentities "domain/auth/entities"
 	"domain/user/entities"
 	"domain/user/valueobjects"
 	"todo-app/application/user"
@@ -14,12 +14,27 @@ import (
 
 // UserResponse represents the HTTP response format for a user
 type UserResponse struct {
-	ID          uint                `json:"id"`
-	Email       string              `json:"email"`
-	Profile     UserProfileResponse `json:"profile"`
-	Preferences UserPreferencesResponse `json:"preferences"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	ID            uint                    `json:"id"`
+	Email         string                  `json:"email"`
+	EmailVerified bool                    `json:"email_verified"`
+	Profile       UserProfileResponse     `json:"profile"`
+	Preferences   UserPreferencesResponse `json:"preferences"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// CurrentUserResponse represents the HTTP response format for the authenticated user
+type CurrentUserResponse struct {
+	UserResponse
+	IsOAuthUser bool                    `json:"is_oauth_user"`
+	Provider    string                  `json:"provider"`
+	Session     *CurrentSessionResponse `json:"session,omitempty"`
+}
+
+// CurrentSessionResponse represents the HTTP response format for the session of the current request
+type CurrentSessionResponse struct {
+	ExpiresAt    time.Time `json:"expires_at"`
+	NeedsRefresh bool      `json:"needs_refresh"`
 }
 
 // UserProfileResponse represents the HTTP response format for user profile
@@ -38,8 +53,9 @@ type UserPreferencesResponse struct {
 
 // RegisterUserRequest represents the HTTP request format for user registration
 type RegisterUserRequest struct {
-	Email     string                      `json:"email" binding:"required,email,max=255"`
-	Profile   RegisterUserProfileRequest  `json:"profile" binding:"required"`
+	Email       string                          `json:"email" binding:"required,email,max=255"`
+	Password    string                          `json:"password" binding:"required,min=8,max=72"`
+	Profile     RegisterUserProfileRequest      `json:"profile" binding:"required"`
 	Preferences *RegisterUserPreferencesRequest `json:"preferences,omitempty"`
 }
 
@@ -71,9 +87,17 @@ type UpdateUserPreferencesRequest struct {
 	ThemePreference     *string `json:"theme_preference,omitempty" binding:"omitempty,oneof=light dark auto"`
 }
 
+// oauthAccount is implemented by the authenticated user the auth middleware
+// stores on the context under "user"
+type oauthAccount interface {
+	IsOAuthUser() bool
+	LinkedOAuthProvider() string
+}
+
 // UserHandlers contains HTTP handlers for user-related endpoints
 type UserHandlers struct {
-	userService user.UserApplicationService
+	userService            user.UserApplicationService
+	registrationMiddleware []gin.HandlerFunc
 }
 
 // NewUserHandlers creates a new user handlers instance
@@ -83,15 +107,26 @@ func NewUserHandlers(userService user.UserApplicationService) *UserHandlers {
 	}
 }
 
-// RegisterRoutes registers all user-related routes
-func (h *UserHandlers) RegisterRoutes(router *gin.RouterGroup) {
+// SetRegistrationMiddleware sets middleware, such as a rate limiter, that runs
+// before POST /users/register. Call it before RegisterRoutes.
+func (h *UserHandlers) SetRegistrationMiddleware(middleware ...gin.HandlerFunc) {
+	h.registrationMiddleware = middleware
+}
+
+// RegisterRoutes registers all user-related routes.
+// authMiddleware is applied to every route except registration and email verification.
+func (h *UserHandlers) RegisterRoutes(router *gin.RouterGroup, authMiddleware ...gin.HandlerFunc) {
 	userRoutes := router.Group("/users")
 	{
-		userRoutes.POST("/register", h.RegisterUser)
-		userRoutes.GET("/profile", h.GetUserProfile)
-		userRoutes.PUT("/profile", h.UpdateUserProfile)
-		userRoutes.GET("/preferences", h.GetUserPreferences)
-		userRoutes.PUT("/preferences", h.UpdateUserPreferences)
+		userRoutes.POST("/register", append(h.registrationMiddleware, h.RegisterUser)...)
+		userRoutes.GET("/verify", h.VerifyEmail)
+
+		authenticated := userRoutes.Group("", authMiddleware...)
+		authenticated.GET("/me", h.GetCurrentUser)
+		authenticated.GET("/profile", h.GetUserProfile)
+		authenticated.PUT("/profile", h.UpdateUserProfile)
+		authenticated.GET("/preferences", h.GetUserPreferences)
+		authenticated.PUT("/preferences", h.UpdateUserPreferences)
 	}
 }
 
@@ -100,17 +135,14 @@ func (h *UserHandlers) RegisterUser(c *gin.Context) {
 	// Parse request body
 	var req RegisterUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-			Details: err.Error(),
-		})
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
 		return
 	}
 
 	// Create command
 	cmd := user.RegisterUserCommand{
 		Email:     req.Email,
+		Password:  req.Password,
 		FirstName: req.Profile.FirstName,
 		LastName:  req.Profile.LastName,
 		Timezone:  req.Profile.Timezone,
@@ -124,24 +156,9 @@ func (h *UserHandlers) RegisterUser(c *gin.Context) {
 	}
 
 	// Register user using application service
-	registeredUser, err := h.userService.RegisterUser(cmd)
+	registeredUser, err := h.userService.RegisterUser(c.Request.Context(), cmd)
 	if err != nil {
-		if isValidationError(err) || isEmailConflictError(err) {
-			statusCode := http.StatusUnprocessableEntity
-			if isEmailConflictError(err) {
-				statusCode = http.StatusConflict
-			}
-			c.JSON(statusCode, ErrorResponse{
-				Error:   "registration_failed",
-				Message: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "registration_failed",
-				Message: "Failed to register user",
-				Details: err.Error(),
-			})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -150,13 +167,25 @@ func (h *UserHandlers) RegisterUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// VerifyEmail handles GET /api/v1/users/verify?token=
+func (h *UserHandlers) VerifyEmail(c *gin.Context) {
+	verifiedUser, err := h.userService.VerifyEmail(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertUserToResponse(verifiedUser))
+}
+
 // GetUserProfile handles GET /api/v1/users/profile
 func (h *UserHandlers) GetUserProfile(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -164,27 +193,18 @@ func (h *UserHandlers) GetUserProfile(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
 	}
 
 	// Get user profile from application service
-	userEntity, err := h.userService.GetUserProfile(userIDUint)
+	userEntity, err := h.userService.GetUserProfile(c.Request.Context(), userIDUint)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "user_not_found",
-				Message: "User not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "retrieval_failed",
-				Message: "Failed to retrieve user profile",
-			})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -193,13 +213,67 @@ func (h *UserHandlers) GetUserProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetCurrentUser handles GET /api/v1/users/me
+func (h *UserHandlers) GetCurrentUser(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Code:    CodeInternal,
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Get user profile from application service
+	userEntity, err := h.userService.GetUserProfile(c.Request.Context(), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := CurrentUserResponse{UserResponse: h.convertUserToResponse(userEntity)}
+
+	// OAuth linkage comes from the account the auth middleware loaded
+	if value, exists := c.Get("user"); exists {
+		if account, ok := value.(oauthAccount); ok {
+			response.IsOAuthUser = account.IsOAuthUser()
+			response.Provider = account.LinkedOAuthProvider()
+		}
+	}
+
+	// Session metadata comes from the session validated for this request
+	if value, exists := c.Get("session"); exists {
+		if session, ok := value.(*authentities.AuthenticationSession); ok {
+			response.Session = &CurrentSessionResponse{
+				ExpiresAt:    session.SessionExpiresAt,
+				NeedsRefresh: session.NeedsRefresh(),
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // UpdateUserProfile handles PUT /api/v1/users/profile
 func (h *UserHandlers) UpdateUserProfile(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -207,8 +281,9 @@ func (h *UserHandlers) UpdateUserProfile(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -217,11 +292,7 @@ func (h *UserHandlers) UpdateUserProfile(c *gin.Context) {
 	// Parse request body
 	var req UpdateUserProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-			Details: err.Error(),
-		})
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
 		return
 	}
 
@@ -234,25 +305,9 @@ func (h *UserHandlers) UpdateUserProfile(c *gin.Context) {
 	}
 
 	// Update user profile using application service
-	updatedUser, err := h.userService.UpdateUserProfile(cmd)
+	updatedUser, err := h.userService.UpdateUserProfile(c.Request.Context(), cmd)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "user_not_found",
-				Message: "User not found",
-			})
-		} else if isValidationError(err) {
-			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "update_failed",
-				Message: "Failed to update user profile",
-				Details: err.Error(),
-			})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -266,8 +321,9 @@ func (h *UserHandlers) GetUserPreferences(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -275,27 +331,18 @@ func (h *UserHandlers) GetUserPreferences(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
 	}
 
 	// Get user preferences from application service
-	preferences, err := h.userService.GetUserPreferences(userIDUint)
+	preferences, err := h.userService.GetUserPreferences(c.Request.Context(), userIDUint)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "user_not_found",
-				Message: "User not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "retrieval_failed",
-				Message: "Failed to retrieve user preferences",
-			})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -309,8 +356,9 @@ func (h *UserHandlers) UpdateUserPreferences(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		respondWithError(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "unauthorized",
+			Code:    CodeUnauthorized,
 			Message: "User not authenticated",
 		})
 		return
@@ -318,8 +366,9 @@ func (h *UserHandlers) UpdateUserPreferences(c *gin.Context) {
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		respondWithError(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
+			Code:    CodeInternal,
 			Message: "Invalid user ID format",
 		})
 		return
@@ -328,11 +377,7 @@ func (h *UserHandlers) UpdateUserPreferences(c *gin.Context) {
 	// Parse request body
 	var req UpdateUserPreferencesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request format",
-			Details: err.Error(),
-		})
+		respondWithError(c, http.StatusBadRequest, bindErrorResponse(err))
 		return
 	}
 
@@ -345,25 +390,9 @@ func (h *UserHandlers) UpdateUserPreferences(c *gin.Context) {
 	}
 
 	// Update user preferences using application service
-	updatedPreferences, err := h.userService.UpdateUserPreferences(cmd)
+	updatedPreferences, err := h.userService.UpdateUserPreferences(c.Request.Context(), cmd)
 	if err != nil {
-		if isNotFoundError(err) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error:   "user_not_found",
-				Message: "User not found",
-			})
-		} else if isValidationError(err) {
-			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "update_failed",
-				Message: "Failed to update user preferences",
-				Details: err.Error(),
-			})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -375,54 +404,29 @@ func (h *UserHandlers) UpdateUserPreferences(c *gin.Context) {
 // Helper functions
 
 // convertUserToResponse converts a domain user entity to HTTP response format
-func (h *UserHandlers) convertUserToResponse(userEntity interface{}) UserResponse {
-	user, ok := userEntity.(*entities.User)
-	if !ok {
-		// Return empty response if type assertion fails
-		return UserResponse{}
-	}
+func (h *UserHandlers) convertUserToResponse(user *entities.User) UserResponse {
+	profile := user.Profile()
 
 	return UserResponse{
-		ID:    user.ID().Value(),
-		Email: user.Email().Value(),
+		ID:            user.ID().Value(),
+		Email:         user.Email().Value(),
+		EmailVerified: user.IsEmailVerified(),
 		Profile: UserProfileResponse{
-			FirstName: user.Profile().FirstName(),
-			LastName:  user.Profile().LastName(),
-			Timezone:  user.Profile().Timezone(),
-		},
-		Preferences: UserPreferencesResponse{
-			DefaultTaskPriority: user.Preferences().DefaultTaskPriority(),
-			EmailNotifications:  user.Preferences().EmailNotifications(),
-			ThemePreference:     user.Preferences().ThemePreference(),
+			FirstName: profile.FirstName(),
+			LastName:  profile.LastName(),
+			Timezone:  profile.Timezone(),
 		},
-		CreatedAt: user.CreatedAt(),
-		UpdatedAt: user.UpdatedAt(),
+		Preferences: h.convertPreferencesToResponse(user.Preferences()),
+		CreatedAt:   user.CreatedAt(),
+		UpdatedAt:   user.UpdatedAt(),
 	}
 }
 
 // convertPreferencesToResponse converts user preferences to HTTP response format
-func (h *UserHandlers) convertPreferencesToResponse(preferences interface{}) UserPreferencesResponse {
-	prefs, ok := preferences.(valueobjects.UserPreferences)
-	if !ok {
-		// Return empty response if type assertion fails
-		return UserPreferencesResponse{}
-	}
-
+func (h *UserHandlers) convertPreferencesToResponse(preferences valueobjects.UserPreferences) UserPreferencesResponse {
 	return UserPreferencesResponse{
-		DefaultTaskPriority: prefs.DefaultTaskPriority(),
-		EmailNotifications:  prefs.EmailNotifications(),
-		ThemePreference:     prefs.ThemePreference(),
-	}
-}
-
-// Error checking helper functions specific to user operations
-func isEmailConflictError(err error) bool {
-	if err == nil {
-		return false
+		DefaultTaskPriority: preferences.DefaultTaskPriority().Value(),
+		EmailNotifications:  preferences.EmailNotifications(),
+		ThemePreference:     preferences.ThemePreference(),
 	}
-	// Check if error indicates email conflict (already exists)
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "email already exists") ||
-		strings.Contains(errMsg, "duplicate") ||
-		strings.Contains(errMsg, "unique constraint")
 }
\ No newline at end of file
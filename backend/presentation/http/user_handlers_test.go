@@ -0,0 +1,411 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authentities "domain/auth/entities"
+	"domain/task/valueobjects"
+	"domain/user/entities"
+	"domain/user/repositories"
+	"domain/user/services"
+	uservo "domain/user/valueobjects"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/application/apperrors"
+	"todo-app/application/user"
+	"todo-app/internal/dtos"
+)
+
+// memoryUserRepository tracks registered emails for uniqueness checks
+type memoryUserRepository struct {
+	repositories.UserRepository
+	emails map[string]bool
+}
+
+func (r *memoryUserRepository) Save(ctx context.Context, u *entities.User) error {
+	r.emails[u.Email().Value()] = true
+	return nil
+}
+
+func (r *memoryUserRepository) ExistsByEmail(ctx context.Context, email uservo.Email) (bool, error) {
+	return r.emails[email.Value()], nil
+}
+
+// stubUserService builds entities from commands without persistence.
+// When repo is set, registration runs the domain uniqueness check and records the user.
+type stubUserService struct {
+	user.UserApplicationService
+	registered *entities.User
+	repo       *memoryUserRepository
+}
+
+func (s *stubUserService) RegisterUser(ctx context.Context, cmd user.RegisterUserCommand) (*entities.User, error) {
+	email, err := uservo.NewEmail(cmd.Email)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := uservo.NewUserProfile(cmd.FirstName, cmd.LastName, cmd.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	if s.repo != nil {
+		if err := services.NewUserAuthenticationService(s.repo).ValidateRegistrationData(ctx, email, profile); err != nil {
+			if errors.Is(err, services.ErrEmailAlreadyExists) {
+				return nil, apperrors.Conflict("email_conflict", err)
+			}
+			return nil, apperrors.Validation(err)
+		}
+	}
+
+	priority := valueobjects.NewMediumPriority()
+	if cmd.DefaultTaskPriority != nil {
+		if priority, err = valueobjects.NewTaskPriority(*cmd.DefaultTaskPriority); err != nil {
+			return nil, err
+		}
+	}
+	preferences, err := uservo.NewUserPreferences(priority, *cmd.EmailNotifications, *cmd.ThemePreference)
+	if err != nil {
+		return nil, err
+	}
+
+	s.registered, err = entities.NewUser(uservo.NewUserID(42), email, profile, preferences)
+	if err != nil {
+		return nil, err
+	}
+	if s.repo != nil {
+		if err := s.repo.Save(ctx, s.registered); err != nil {
+			return nil, err
+		}
+	}
+	return s.registered, nil
+}
+
+func (s *stubUserService) GetUserPreferences(ctx context.Context, userID uint) (uservo.UserPreferences, error) {
+	return s.registered.Preferences(), nil
+}
+
+func (s *stubUserService) GetUserProfile(ctx context.Context, userID uint) (*entities.User, error) {
+	if s.registered == nil {
+		return nil, apperrors.NotFound("user_not_found", errors.New("user not found"))
+	}
+	return s.registered, nil
+}
+
+func TestRegisterUser_ReturnsSubmittedData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &stubUserService{}
+	router := gin.New()
+	router.Use(ErrorHandler())
+	NewUserHandlers(service).RegisterRoutes(router.Group("/api/v1"))
+
+	payload := map[string]interface{}{
+		"email":    "jane@example.com",
+		"password": "correct horse battery",
+		"profile": map[string]interface{}{
+			"first_name": "Jane",
+			"last_name":  "Doe",
+			"timezone":   "Europe/Berlin",
+		},
+		"preferences": map[string]interface{}{
+			"default_task_priority": "high",
+			"email_notifications":   false,
+			"theme_preference":      "dark",
+		},
+	}
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(42), response.ID)
+	assert.Equal(t, "jane@example.com", response.Email)
+	assert.Equal(t, "Jane", response.Profile.FirstName)
+	assert.Equal(t, "Doe", response.Profile.LastName)
+	assert.Equal(t, "Europe/Berlin", response.Profile.Timezone)
+	assert.Equal(t, "high", response.Preferences.DefaultTaskPriority)
+	assert.False(t, response.Preferences.EmailNotifications)
+	assert.Equal(t, "dark", response.Preferences.ThemePreference)
+	assert.False(t, response.CreatedAt.IsZero())
+}
+
+func TestRegisterUser_DuplicateEmailReturnsConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &stubUserService{repo: &memoryUserRepository{emails: map[string]bool{}}}
+	router := gin.New()
+	router.Use(ErrorHandler())
+	NewUserHandlers(service).RegisterRoutes(router.Group("/api/v1"))
+
+	body, err := json.Marshal(map[string]interface{}{
+		"email":    "jane@example.com",
+		"password": "correct horse battery",
+		"profile": map[string]interface{}{
+			"first_name": "Jane",
+			"last_name":  "Doe",
+			"timezone":   "Europe/Berlin",
+		},
+		"preferences": map[string]interface{}{
+			"default_task_priority": "medium",
+			"email_notifications":   true,
+			"theme_preference":      "light",
+		},
+	})
+	require.NoError(t, err)
+
+	register := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := register()
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+
+	second := register()
+	require.Equal(t, http.StatusConflict, second.Code, second.Body.String())
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(second.Body.Bytes(), &response))
+	assert.Equal(t, "email_conflict", response.Error)
+	assert.NotEmpty(t, response.Message)
+}
+
+func TestRegisterUser_RequiresPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	NewUserHandlers(&stubUserService{}).RegisterRoutes(router.Group("/api/v1"))
+
+	for _, password := range []string{"", "short"} {
+		body, err := json.Marshal(map[string]interface{}{
+			"email":    "jane@example.com",
+			"password": password,
+			"profile": map[string]interface{}{
+				"first_name": "Jane",
+				"last_name":  "Doe",
+				"timezone":   "UTC",
+			},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "password %q", password)
+	}
+}
+
+func TestRegisterUser_ValidationErrorsAreStructured(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	NewUserHandlers(&stubUserService{}).RegisterRoutes(router.Group("/api/v1"))
+
+	body, err := json.Marshal(map[string]interface{}{
+		"email":    "not-an-email",
+		"password": "short",
+		"profile": map[string]interface{}{
+			"last_name": "Doe",
+			"timezone":  "UTC",
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "password", Rule: "min", Message: "password must be at least 8 characters"},
+		{Field: "profile.first_name", Rule: "required", Message: "profile.first_name is required"},
+	}, bindErrorDetails(t, w))
+}
+
+func TestGetUserPreferences_ReturnsPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	email, _ := uservo.NewEmail("jane@example.com")
+	profile, _ := uservo.NewUserProfile("Jane", "Doe", "UTC")
+	preferences, _ := uservo.NewUserPreferences(valueobjects.NewLowPriority(), true, uservo.ThemeLight)
+	registered, err := entities.NewUser(uservo.NewUserID(7), email, profile, preferences)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	authenticate := func(c *gin.Context) { c.Set("userID", uint(7)) }
+	NewUserHandlers(&stubUserService{registered: registered}).RegisterRoutes(router.Group("/api/v1"), authenticate)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/preferences", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response UserPreferencesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "low", response.DefaultTaskPriority)
+	assert.True(t, response.EmailNotifications)
+	assert.Equal(t, "light", response.ThemePreference)
+}
+
+func TestGetUserProfile_ReturnsPopulatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	email, _ := uservo.NewEmail("jane@example.com")
+	profile, _ := uservo.NewUserProfile("Jane", "Doe", "Asia/Tokyo")
+	preferences, _ := uservo.NewUserPreferences(valueobjects.NewHighPriority(), false, uservo.ThemeDark)
+	registered, err := entities.NewUser(uservo.NewUserID(7), email, profile, preferences)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	authenticate := func(c *gin.Context) { c.Set("userID", uint(7)) }
+	NewUserHandlers(&stubUserService{registered: registered}).RegisterRoutes(router.Group("/api/v1"), authenticate)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(7), response.ID)
+	assert.Equal(t, "jane@example.com", response.Email)
+	assert.Equal(t, "Jane", response.Profile.FirstName)
+	assert.Equal(t, "Doe", response.Profile.LastName)
+	assert.Equal(t, "Asia/Tokyo", response.Profile.Timezone)
+	assert.Equal(t, "high", response.Preferences.DefaultTaskPriority)
+	assert.False(t, response.Preferences.EmailNotifications)
+	assert.Equal(t, "dark", response.Preferences.ThemePreference)
+	assert.Equal(t, registered.CreatedAt().Unix(), response.CreatedAt.Unix())
+	assert.Equal(t, registered.UpdatedAt().Unix(), response.UpdatedAt.Unix())
+}
+
+func TestUserRoutes_RequireAuthExceptRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	deny := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	NewUserHandlers(&stubUserService{}).RegisterRoutes(router.Group("/api/v1"), deny)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func keysOf(value interface{}) []string {
+	object, _ := value.(map[string]interface{})
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TestGetCurrentUser_ContractFields pins the JSON field names the frontend relies on
+func TestGetCurrentUser_ContractFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	email, _ := uservo.NewEmail("jane@example.com")
+	profile, _ := uservo.NewUserProfile("Jane", "Doe", "Asia/Tokyo")
+	preferences, _ := uservo.NewUserPreferences(valueobjects.NewHighPriority(), false, uservo.ThemeDark)
+	registered, err := entities.NewUser(uservo.NewUserID(7), email, profile, preferences)
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tokenExpiresAt := time.Now().Add(time.Minute)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		c.Set("user", &dtos.User{ID: 7, Email: "jane@example.com", OAuthProvider: "github", OAuthExternalID: "583231"})
+		c.Set("session", &authentities.AuthenticationSession{
+			SessionExpiresAt: expiresAt,
+			AccessToken:      "access",
+			TokenExpiresAt:   &tokenExpiresAt,
+		})
+	}
+	NewUserHandlers(&stubUserService{registered: registered}).RegisterRoutes(router.Group("/api/v1"), authenticate)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.ElementsMatch(t, []string{"id", "email", "email_verified", "profile", "preferences", "created_at", "updated_at", "is_oauth_user", "provider", "session"}, keysOf(body))
+	assert.ElementsMatch(t, []string{"first_name", "last_name", "timezone"}, keysOf(body["profile"]))
+	assert.ElementsMatch(t, []string{"default_task_priority", "email_notifications", "theme_preference"}, keysOf(body["preferences"]))
+	assert.ElementsMatch(t, []string{"expires_at", "needs_refresh"}, keysOf(body["session"]))
+
+	var response CurrentUserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(7), response.ID)
+	assert.Equal(t, "jane@example.com", response.Email)
+	assert.Equal(t, "Jane", response.Profile.FirstName)
+	assert.Equal(t, "dark", response.Preferences.ThemePreference)
+	assert.True(t, response.IsOAuthUser)
+	assert.Equal(t, "github", response.Provider)
+	require.NotNil(t, response.Session)
+	assert.True(t, expiresAt.Equal(response.Session.ExpiresAt))
+	assert.True(t, response.Session.NeedsRefresh)
+}
+
+func TestGetCurrentUser_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	NewUserHandlers(&stubUserService{}).RegisterRoutes(router.Group("/api/v1"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetCurrentUser_UserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	authenticate := func(c *gin.Context) { c.Set("userID", uint(7)) }
+	NewUserHandlers(&stubUserService{}).RegisterRoutes(router.Group("/api/v1"), authenticate)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "user_not_found", body["error"])
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"domain/auth/entities"
+	"gorm.io/gorm"
+)
+
+// DefaultBufferSize is how many audit events can be queued before Record waits for the writer
+const DefaultBufferSize = 1024
+
+// AuditLogFilter selects audit logs; zero values match everything
+type AuditLogFilter struct {
+	UserID    *uint
+	EventType entities.AuditEventType
+	From      time.Time // inclusive
+	To        time.Time // exclusive
+	Limit     int       // 0 means no limit
+	Offset    int
+}
+
+// AuditService records security-relevant events without slowing down requests:
+// Record queues the event and a single writer goroutine persists it. A nil
+// *AuditService is valid and records nothing.
+type AuditService struct {
+	db     *gorm.DB
+	events chan *entities.AuditLog
+	done   chan struct{}
+
+	// mu guards closed so no event is queued once the channel is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAuditService creates an audit service and starts its writer; call Close on shutdown
+func NewAuditService(db *gorm.DB, bufferSize int) *AuditService {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	s := &AuditService{
+		db:     db,
+		events: make(chan *entities.AuditLog, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Record queues an event for writing. It only blocks if the buffer is full,
+// so events are never dropped while the service is running.
+func (s *AuditService) Record(entry *entities.AuditLog) {
+	if s == nil || entry == nil {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		log.Printf("Audit service is closed; discarding %s event", entry.EventType)
+		return
+	}
+	s.events <- entry
+}
+
+// Close stops accepting events and waits until every queued event has been
+// written, or until ctx is done
+func (s *AuditService) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run writes queued events until the channel is closed and drained
+func (s *AuditService) run() {
+	defer close(s.done)
+
+	for entry := range s.events {
+		if err := s.db.Create(entry).Error; err != nil {
+			log.Printf("Failed to write %s audit log: %v", entry.EventType, err)
+		}
+	}
+}
+
+// List returns a page of audit logs matching filter, newest first, along with
+// the total number of matches
+func (s *AuditService) List(filter AuditLogFilter) ([]entities.AuditLog, int64, error) {
+	query := s.db.Model(&entities.AuditLog{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := query.Order("created_at DESC, id DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		page = page.Limit(filter.Limit)
+	}
+
+	var logs []entities.AuditLog
+	if err := page.Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
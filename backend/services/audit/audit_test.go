@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	taskentities "domain/task/entities"
+	taskvo "domain/task/valueobjects"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupAuditTest(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&entities.AuditLog{}))
+	return db
+}
+
+func TestAuditService_PersistsRecordedEvents(t *testing.T) {
+	db := setupAuditTest(t)
+	service := NewAuditService(db, 0)
+
+	service.Record(entities.NewAuditLog(entities.AuditEventLogout, 42, "192.0.2.1", "test-agent", entities.AuditMetadata{"session_id": "s-1"}))
+	service.Record(entities.NewAuditLog(entities.AuditEventLoginFailed, 0, "192.0.2.2", "test-agent", nil))
+	require.NoError(t, service.Close(context.Background()))
+
+	logs, total, err := service.List(AuditLogFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, logs, 2)
+
+	userID := uint(42)
+	logs, total, err = service.List(AuditLogFilter{UserID: &userID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, entities.AuditEventLogout, logs[0].EventType)
+	assert.Equal(t, "192.0.2.1", logs[0].IPAddress)
+	assert.Equal(t, "test-agent", logs[0].UserAgent)
+	assert.Equal(t, "s-1", logs[0].Metadata["session_id"])
+
+	logs, _, err = service.List(AuditLogFilter{EventType: entities.AuditEventLoginFailed})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Nil(t, logs[0].UserID)
+}
+
+func TestAuditService_CloseFlushesQueuedEvents(t *testing.T) {
+	db := setupAuditTest(t)
+	// A small buffer makes producers wait on the writer instead of dropping events
+	service := NewAuditService(db, 4)
+
+	const producers, perProducer = 10, 50
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				service.Record(entities.NewAuditLog(entities.AuditEventTaskDeleted, userID, "", "", entities.AuditMetadata{"task_id": i}))
+			}
+		}(uint(p + 1))
+	}
+	wg.Wait()
+
+	require.NoError(t, service.Close(context.Background()))
+
+	var count int64
+	require.NoError(t, db.Model(&entities.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(producers*perProducer), count)
+}
+
+func TestAuditService_RecordAfterCloseIsDiscarded(t *testing.T) {
+	db := setupAuditTest(t)
+	service := NewAuditService(db, 0)
+	require.NoError(t, service.Close(context.Background()))
+	// Closing twice is harmless
+	require.NoError(t, service.Close(context.Background()))
+
+	service.Record(entities.NewAuditLog(entities.AuditEventLogout, 1, "", "", nil))
+
+	var count int64
+	require.NoError(t, db.Model(&entities.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// A nil service records nothing, so callers need not check whether auditing is enabled
+	var disabled *AuditService
+	disabled.Record(entities.NewAuditLog(entities.AuditEventLogout, 1, "", "", nil))
+	assert.NoError(t, disabled.Close(context.Background()))
+}
+
+func TestAuditService_RecordsTaskLifecycleEvents(t *testing.T) {
+	db := setupAuditTest(t)
+	service := NewAuditService(db, 0)
+
+	archivedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.HandleDomainEvent(taskentities.TaskArchived{TaskID: taskvo.NewTaskID(9), UserID: uservo.NewUserID(42), ArchivedAt: archivedAt})
+	service.HandleDomainEvent(taskentities.TaskUpdated{TaskID: taskvo.NewTaskID(9), UserID: uservo.NewUserID(42), Fields: []string{taskentities.TaskFieldTitle}})
+	require.NoError(t, service.Close(context.Background()))
+
+	// Edits are left to the task's activity history
+	logs, total, err := service.List(AuditLogFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, entities.AuditEventTaskArchived, logs[0].EventType)
+	require.NotNil(t, logs[0].UserID)
+	assert.Equal(t, uint(42), *logs[0].UserID)
+	assert.Equal(t, float64(9), logs[0].Metadata["task_id"])
+	assert.True(t, logs[0].CreatedAt.Equal(archivedAt))
+}
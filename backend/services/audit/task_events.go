@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"domain/auth/entities"
+	taskentities "domain/task/entities"
+)
+
+// taskAuditEventTypes are the task domain events kept in the audit log. Edits
+// to a task's details are left to its activity history.
+var taskAuditEventTypes = map[string]entities.AuditEventType{
+	taskentities.TaskCompleted{}.EventName(): entities.AuditEventTaskCompleted,
+	taskentities.TaskArchived{}.EventName():  entities.AuditEventTaskArchived,
+	taskentities.TaskReopened{}.EventName():  entities.AuditEventTaskReopened,
+}
+
+// HandleDomainEvent records task completions, archivals and reopenings, so it
+// can subscribe to the task application service's events. The events come
+// from the domain rather than a request, so no IP address or user agent is
+// recorded.
+func (s *AuditService) HandleDomainEvent(event taskentities.DomainEvent) {
+	eventType, ok := taskAuditEventTypes[event.EventName()]
+	if !ok {
+		return
+	}
+	taskEvent, ok := event.(taskentities.TaskDomainEvent)
+	if !ok {
+		return
+	}
+
+	taskID, userID := taskEvent.Subject()
+	entry := entities.NewAuditLog(eventType, userID.Value(), "", "", entities.AuditMetadata{"task_id": taskID.Value()})
+	entry.CreatedAt = event.OccurredAt()
+	s.Record(entry)
+}
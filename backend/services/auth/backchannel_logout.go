@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"domain/auth/valueobjects"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+)
+
+// BackchannelLogoutEvent is the event type identifying an OIDC back-channel logout token
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// jwksCacheTTL is how long provider signing keys are cached before refetching
+const jwksCacheTTL = 1 * time.Hour
+
+// jwksMinRefreshInterval limits refetches triggered by unknown key IDs
+const jwksMinRefreshInterval = 1 * time.Minute
+
+// BackchannelLogoutConfig holds the identity provider settings used to validate logout tokens
+type BackchannelLogoutConfig struct {
+	Issuer   string
+	Audience string
+	JWKSURL  string
+}
+
+// NewBackchannelLogoutConfig returns the back-channel logout settings of the
+// OpenID Connect provider in cfg
+func NewBackchannelLogoutConfig(cfg config.OAuthConfig) BackchannelLogoutConfig {
+	return BackchannelLogoutConfig{
+		Issuer:   cfg.OIDCIssuer,
+		Audience: cfg.OIDCAudience,
+		JWKSURL:  cfg.OIDCJWKSURL,
+	}
+}
+
+// LogoutTokenClaims represents the claims of an OIDC back-channel logout token
+type LogoutTokenClaims struct {
+	SessionID string                     `json:"sid,omitempty"`
+	Events    map[string]json.RawMessage `json:"events"`
+	Nonce     string                     `json:"nonce,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// BackchannelLogoutService validates provider-issued logout tokens and terminates the mapped sessions
+type BackchannelLogoutService struct {
+	db             *gorm.DB
+	sessionService *SessionService
+	config         BackchannelLogoutConfig
+	keys           *jwksCache
+}
+
+// NewBackchannelLogoutService creates a new back-channel logout service
+func NewBackchannelLogoutService(db *gorm.DB, sessionService *SessionService, config BackchannelLogoutConfig) *BackchannelLogoutService {
+	return &BackchannelLogoutService{
+		db:             db,
+		sessionService: sessionService,
+		config:         config,
+		keys: &jwksCache{
+			url:    config.JWKSURL,
+			client: &http.Client{Timeout: 10 * time.Second},
+		},
+	}
+}
+
+// ValidateLogoutToken verifies the logout token signature and required claims
+func (s *BackchannelLogoutService) ValidateLogoutToken(tokenString string) (*LogoutTokenClaims, error) {
+	if s.config.Audience == "" {
+		return nil, errors.New("back-channel logout audience is not configured")
+	}
+
+	claims := &LogoutTokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.keys.getKey(kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(s.config.Issuer),
+		jwt.WithAudience(s.config.Audience),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logout token: %w", err)
+	}
+
+	if claims.IssuedAt == nil {
+		return nil, errors.New("logout token missing iat claim")
+	}
+
+	if _, ok := claims.Events[BackchannelLogoutEvent]; !ok {
+		return nil, errors.New("logout token missing back-channel logout event")
+	}
+
+	if claims.Subject == "" && claims.SessionID == "" {
+		return nil, errors.New("logout token must contain sub or sid claim")
+	}
+
+	if claims.Nonce != "" {
+		return nil, errors.New("logout token must not contain nonce claim")
+	}
+
+	return claims, nil
+}
+
+// ProcessLogoutToken validates a logout token and terminates the sessions it refers to.
+// A sid terminates the matching provider session; a sub alone terminates all of that user's sessions.
+func (s *BackchannelLogoutService) ProcessLogoutToken(tokenString string) (int64, error) {
+	claims, err := s.ValidateLogoutToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	var userID uint
+	if claims.Subject != "" {
+		userID, err = s.findUserIDBySubject(claims.Subject)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if claims.SessionID != "" {
+		return s.sessionService.TerminateProviderSessions(claims.SessionID, userID)
+	}
+
+	if userID == 0 {
+		// Unknown subject: nothing to terminate
+		return 0, nil
+	}
+
+	return s.sessionService.TerminateAllUserSessions(userID)
+}
+
+// findUserIDBySubject maps the provider subject to a local user ID (0 if unknown)
+func (s *BackchannelLogoutService) findUserIDBySubject(subject string) (uint, error) {
+	var identity valueobjects.GoogleIdentity
+	err := s.db.Where("google_user_id = ?", subject).First(&identity).Error
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	// Fall back to legacy google_id column on users
+	var user dtos.User
+	err = s.db.Where("google_id = ?", subject).First(&user).Error
+	if err == nil {
+		return user.ID, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return 0, err
+}
+
+// jwksCache fetches and caches RSA signing keys from a JWKS endpoint
+type jwksCache struct {
+	url       string
+	client    *http.Client
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// getKey returns the key with the given ID, refreshing the key set if it is stale or the key is unknown
+func (c *jwksCache) getKey(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, ok := c.keys[kid]
+	age := time.Since(c.fetchedAt)
+	if ok && age < jwksCacheTTL {
+		return key, nil
+	}
+
+	// Refetch when the cache is stale or the key is unknown (to pick up
+	// rotations), but at most once per minimum interval
+	if age >= jwksMinRefreshInterval {
+		if err := c.refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	key, ok = c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+	return key, nil
+}
+
+// refresh downloads the key set from the JWKS endpoint
+func (c *jwksCache) refresh() error {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch signing keys: status %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to parse signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
@@ -5,11 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"os"
 	"strings"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"todo-app/internal/config"
 )
 
 // GoogleOAuthConfig holds the Google OAuth 2.0 configuration
@@ -32,12 +32,10 @@ type GoogleUserInfo struct {
 	Locale        string `json:"locale"`
 }
 
-// NewGoogleOAuthConfig creates a new Google OAuth configuration from environment variables
-func NewGoogleOAuthConfig() (*GoogleOAuthConfig, error) {
-	clientID := os.Getenv("GOOGLE_CLIENT_ID")
-	clientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
-	redirectURI := os.Getenv("GOOGLE_REDIRECT_URI")
-
+// NewGoogleOAuthConfig creates a new Google OAuth configuration from the OAuth
+// configuration; it fails when Google sign-in is not configured
+func NewGoogleOAuthConfig(cfg config.OAuthConfig) (*GoogleOAuthConfig, error) {
+	clientID, clientSecret, redirectURI := cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRedirectURI
 	if clientID == "" {
 		return nil, errors.New("GOOGLE_CLIENT_ID environment variable is not set")
 	}
@@ -1,19 +1,63 @@
 package auth
 
 import (
+	"crypto/rand"
 	"errors"
-	"os"
+	"fmt"
+	"log/slog"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"todo-app/internal/config"
 )
 
-// JWTService handles JWT token operations
+// DefaultJWTKeyID is the key ID given to JWT_SECRET when JWT_KEYS is not set
+const DefaultJWTKeyID = "default"
+
+// MinJWTSecretBytes is the shortest signing secret accepted with ENV=production
+const MinJWTSecretBytes = config.MinJWTSecretBytes
+
+// ephemeralJWTKey signs tokens outside production when no secret is configured.
+// It lasts for the life of the process, so sessions end on restart.
+var ephemeralJWTKey = sync.OnceValue(func() []byte {
+	key := make([]byte, MinJWTSecretBytes)
+	rand.Read(key)
+	return key
+})
+
+var (
+	// ErrMissingKeyID is returned for tokens without a kid header
+	ErrMissingKeyID = errors.New("token has no key ID")
+	// ErrUnknownKeyID is returned for tokens signed with a key that is not configured
+	ErrUnknownKeyID = errors.New("token signed with unknown key")
+	// ErrTokenExpired is returned for tokens past their exp claim
+	ErrTokenExpired = errors.New("token has expired")
+	// ErrTokenIssuedInFuture is returned for tokens whose iat or nbf claim is
+	// later than now by more than the allowed clock skew
+	ErrTokenIssuedInFuture = errors.New("token issued in the future")
+)
+
+// DefaultJWTClockSkew is how far ahead of this server another server's clock may
+// be when it issues a token
+const DefaultJWTClockSkew = config.DefaultJWTClockSkew
+
+// JWTService handles JWT token operations. Tokens are signed with the current
+// key and carry its ID in the kid header; any configured key validates, so
+// tokens signed before a rotation keep working until the old key is removed.
 type JWTService struct {
-	secretKey     []byte
-	expiresHours  int
-	issuer        string
+	mu           sync.RWMutex
+	keys         map[string][]byte
+	currentKeyID string
+	expiresHours int
+	issuer       string
+
+	// clockSkew is the leeway allowed on iat and nbf; exp is enforced exactly
+	clockSkew  time.Duration
+	production bool
+	now        func() time.Time
 }
 
 // JWTClaims represents the claims stored in the JWT token
@@ -25,28 +69,153 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-// NewJWTService creates a new JWT service from environment variables
-func NewJWTService() (*JWTService, error) {
-	secretKey := os.Getenv("JWT_SECRET")
+// NewJWTService creates a new JWT service from the session configuration.
+// production rejects missing or short signing secrets instead of warning.
+func NewJWTService(cfg config.SessionConfig, production bool) (*JWTService, error) {
+	keys, currentKeyID, err := parseJWTKeyConfig(cfg.JWTKeys, cfg.JWTSecret, production)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTService{
+		keys:         keys,
+		currentKeyID: currentKeyID,
+		expiresHours: cfg.JWTExpiresHours,
+		issuer:       "todo-app",
+		clockSkew:    cfg.JWTClockSkew,
+		production:   production,
+		now:          time.Now,
+	}, nil
+}
+
+// parseJWTKeyConfig parses the JWT_KEYS value, falling back to secretKey as a
+// single key with DefaultJWTKeyID
+func parseJWTKeyConfig(keysValue, secretKey string, production bool) (map[string][]byte, string, error) {
+	if value := strings.TrimSpace(keysValue); value != "" {
+		keys, currentKeyID, err := ParseJWTKeys(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid JWT_KEYS: %w", err)
+		}
+		for id, secret := range keys {
+			if err := checkJWTSecret("JWT_KEYS key "+strconv.Quote(id), secret, production); err != nil {
+				return nil, "", err
+			}
+		}
+		return keys, currentKeyID, nil
+	}
+
 	if secretKey == "" {
-		return nil, errors.New("JWT_SECRET environment variable is not set")
+		if production {
+			return nil, "", errors.New("JWT_SECRET environment variable is not set")
+		}
+		slog.Warn("JWT_SECRET is not set; signing with an ephemeral key, so sessions end when the server restarts")
+		return map[string][]byte{DefaultJWTKeyID: ephemeralJWTKey()}, DefaultJWTKeyID, nil
 	}
+	if err := checkJWTSecret("JWT_SECRET", []byte(secretKey), production); err != nil {
+		return nil, "", err
+	}
+	return map[string][]byte{DefaultJWTKeyID: []byte(secretKey)}, DefaultJWTKeyID, nil
+}
+
+// checkJWTSecret rejects a secret shorter than MinJWTSecretBytes in production
+// and warns about one elsewhere
+func checkJWTSecret(name string, secret []byte, production bool) error {
+	if len(secret) >= MinJWTSecretBytes {
+		return nil
+	}
+	if production {
+		return fmt.Errorf("%s must be at least %d bytes", name, MinJWTSecretBytes)
+	}
+	slog.Warn("JWT signing secret is too short for production", "secret", name, "min_bytes", MinJWTSecretBytes)
+	return nil
+}
+
+// ParseJWTKeys parses comma-separated id:secret pairs, e.g. "2024b:newsecret,2024a:oldsecret".
+// The first key is the current signing key; the rest only validate.
+func ParseJWTKeys(value string) (map[string][]byte, string, error) {
+	keys := make(map[string][]byte)
+	currentKeyID := ""
 
-	expiresHoursStr := os.Getenv("JWT_EXPIRES_HOURS")
-	if expiresHoursStr == "" {
-		expiresHoursStr = "24" // Default to 24 hours
+	for _, pair := range strings.Split(value, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, "", fmt.Errorf("key %q must be in id:secret form", pair)
+		}
+		if id == "" || secret == "" {
+			return nil, "", fmt.Errorf("key %q has an empty id or secret", pair)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, "", fmt.Errorf("duplicate key id %q", id)
+		}
+
+		keys[id] = []byte(secret)
+		if currentKeyID == "" {
+			currentKeyID = id
+		}
 	}
 
-	expiresHours, err := strconv.Atoi(expiresHoursStr)
+	return keys, currentKeyID, nil
+}
+
+// Rotate makes a new key the signing key. Previously configured keys still
+// validate tokens until they are dropped by ReloadKeys.
+func (s *JWTService) Rotate(keyID, secret string) error {
+	if keyID == "" || secret == "" {
+		return errors.New("key id and secret cannot be empty")
+	}
+	if err := checkJWTSecret("key "+strconv.Quote(keyID), []byte(secret), s.production); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make(map[string][]byte, len(s.keys)+1)
+	for id, key := range s.keys {
+		keys[id] = key
+	}
+	keys[keyID] = []byte(secret)
+
+	s.keys = keys
+	s.currentKeyID = keyID
+	return nil
+}
+
+// ReloadKeys replaces the key set with the one in cfg, a freshly loaded
+// configuration. On error the existing keys are kept.
+func (s *JWTService) ReloadKeys(cfg config.SessionConfig) error {
+	keys, currentKeyID, err := parseJWTKeyConfig(cfg.JWTKeys, cfg.JWTSecret, s.production)
 	if err != nil {
-		return nil, errors.New("JWT_EXPIRES_HOURS must be a valid integer")
+		return err
 	}
 
-	return &JWTService{
-		secretKey:    []byte(secretKey),
-		expiresHours: expiresHours,
-		issuer:       "todo-app",
-	}, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+	s.currentKeyID = currentKeyID
+	return nil
+}
+
+// CurrentKeyID returns the ID of the key new tokens are signed with
+func (s *JWTService) CurrentKeyID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentKeyID
+}
+
+// currentKey returns the ID and secret of the signing key
+func (s *JWTService) currentKey() (string, []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentKeyID, s.keys[s.currentKeyID]
+}
+
+// key returns the secret for a key ID
+func (s *JWTService) key(keyID string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[keyID]
+	return key, ok
 }
 
 // GenerateToken generates a new JWT token for a user session
@@ -69,8 +238,10 @@ func (s *JWTService) GenerateToken(userID uint, email, sessionID string, isOAuth
 		},
 	}
 
+	keyID, secretKey := s.currentKey()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
+	token.Header["kid"] = keyID
+	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
 		return "", err
 	}
@@ -85,18 +256,50 @@ func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return s.secretKey, nil
-	})
+
+		keyID, _ := token.Header["kid"].(string)
+		if keyID == "" {
+			return nil, ErrMissingKeyID
+		}
+		key, ok := s.key(keyID)
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+		return key, nil
+	}, jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if err := s.validateTimes(claims); err != nil {
+		return nil, err
 	}
 
-	return nil, errors.New("invalid token")
+	return claims, nil
+}
+
+// validateTimes checks the token's time claims. Tokens issued by a server whose
+// clock runs ahead are accepted within clockSkew, but an expired token is
+// rejected at once.
+func (s *JWTService) validateTimes(claims *JWTClaims) error {
+	now := s.now()
+	if claims.ExpiresAt == nil || !now.Before(claims.ExpiresAt.Time) {
+		return ErrTokenExpired
+	}
+
+	latest := now.Add(s.clockSkew)
+	if claims.IssuedAt != nil && claims.IssuedAt.After(latest) {
+		return ErrTokenIssuedInFuture
+	}
+	if claims.NotBefore != nil && claims.NotBefore.After(latest) {
+		return ErrTokenIssuedInFuture
+	}
+	return nil
 }
 
 // RefreshToken generates a new token with extended expiration
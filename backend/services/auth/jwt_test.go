@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"todo-app/internal/config"
+)
+
+// testSessionConfig is the session configuration the tests' JWT services start from
+func testSessionConfig(keys string) config.SessionConfig {
+	return config.SessionConfig{
+		JWTKeys:         keys,
+		JWTExpiresHours: config.DefaultJWTExpiresHours,
+		JWTClockSkew:    config.DefaultJWTClockSkew,
+	}
+}
+
+func newTestJWTService(t *testing.T, keys string) *JWTService {
+	jwtService, err := NewJWTService(testSessionConfig(keys), false)
+	require.NoError(t, err)
+	return jwtService
+}
+
+func TestJWTService_RotationKeepsOldTokensValid(t *testing.T) {
+	jwtService := newTestJWTService(t, "a:secret-a")
+
+	oldToken, err := jwtService.GenerateToken(1, "user@example.com", "session-1", false)
+	require.NoError(t, err)
+
+	require.NoError(t, jwtService.Rotate("b", "secret-b"))
+	assert.Equal(t, "b", jwtService.CurrentKeyID())
+
+	newToken, err := jwtService.GenerateToken(1, "user@example.com", "session-2", false)
+	require.NoError(t, err)
+
+	for token, keyID := range map[string]string{oldToken: "a", newToken: "b"} {
+		parsed, _, err := new(jwt.Parser).ParseUnverified(token, &JWTClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, keyID, parsed.Header["kid"])
+
+		_, err = jwtService.ValidateToken(token)
+		assert.NoError(t, err, "token signed with key %s", keyID)
+	}
+}
+
+func TestJWTService_ReloadKeysDropsRetiredKeys(t *testing.T) {
+	jwtService := newTestJWTService(t, "b:secret-b,a:secret-a")
+	assert.Equal(t, "b", jwtService.CurrentKeyID())
+
+	require.NoError(t, jwtService.Rotate("a", "secret-a"))
+	oldToken, err := jwtService.GenerateToken(1, "user@example.com", "session-1", false)
+	require.NoError(t, err)
+
+	require.NoError(t, jwtService.ReloadKeys(testSessionConfig("b:secret-b")))
+	assert.Equal(t, "b", jwtService.CurrentKeyID())
+
+	_, err = jwtService.ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+
+	// Malformed config keeps the current keys
+	assert.Error(t, jwtService.ReloadKeys(testSessionConfig("b")))
+	assert.Equal(t, "b", jwtService.CurrentKeyID())
+}
+
+func TestJWTService_RejectsTokenWithoutKeyID(t *testing.T) {
+	jwtService := newTestJWTService(t, "a:secret-a")
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{UserID: 1}).SignedString([]byte("secret-a"))
+	require.NoError(t, err)
+
+	_, err = jwtService.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrMissingKeyID)
+}
+
+func TestJWTService_FallsBackToJWTSecret(t *testing.T) {
+	cfg := testSessionConfig("")
+	cfg.JWTSecret = "legacy-secret"
+
+	jwtService, err := NewJWTService(cfg, false)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultJWTKeyID, jwtService.CurrentKeyID())
+}
+
+func TestNewJWTService_UsesLoadedSettings(t *testing.T) {
+	// The environment is ignored in favour of the loaded configuration
+	t.Setenv("JWT_KEYS", "env:secret-env")
+	t.Setenv("JWT_EXPIRES_HOURS", "48")
+
+	jwtService, err := NewJWTService(config.SessionConfig{
+		JWTKeys:         "a:secret-a",
+		JWTExpiresHours: 2,
+		JWTClockSkew:    5 * time.Second,
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "a", jwtService.CurrentKeyID())
+	assert.Equal(t, 5*time.Second, jwtService.clockSkew)
+
+	token, err := jwtService.GenerateToken(1, "user@example.com", "session-1", false)
+	require.NoError(t, err)
+	claims, err := jwtService.ValidateToken(token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	jwtService, err = NewJWTService(config.SessionConfig{JWTSecret: "legacy-secret", JWTExpiresHours: 1}, false)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultJWTKeyID, jwtService.CurrentKeyID())
+
+	_, err = NewJWTService(config.SessionConfig{JWTKeys: "a:", JWTExpiresHours: 1}, false)
+	assert.Error(t, err)
+}
+
+func TestParseJWTKeys_RejectsMalformedConfig(t *testing.T) {
+	for _, value := range []string{"secret-only", "a:", ":secret", "a:one,a:two", "a:one,"} {
+		_, _, err := ParseJWTKeys(value)
+		assert.Error(t, err, value)
+	}
+
+	keys, currentKeyID, err := ParseJWTKeys("new:s2, old:s1")
+	require.NoError(t, err)
+	assert.Equal(t, "new", currentKeyID)
+	assert.Equal(t, []byte("s1"), keys["old"])
+}
+
+func TestNewJWTService_ProductionRequiresStrongSecret(t *testing.T) {
+	strong := strings.Repeat("s", MinJWTSecretBytes)
+
+	for name, secret := range map[string]string{"missing": "", "too short": "short-secret"} {
+		cfg := testSessionConfig("")
+		cfg.JWTSecret = secret
+		_, err := NewJWTService(cfg, true)
+		assert.Error(t, err, name)
+	}
+
+	// Every configured key must be strong, not only the signing one
+	_, err := NewJWTService(testSessionConfig("new:"+strong+",old:short-secret"), true)
+	assert.Error(t, err)
+
+	cfg := testSessionConfig("")
+	cfg.JWTSecret = strong
+	jwtService, err := NewJWTService(cfg, true)
+	require.NoError(t, err)
+	assert.Error(t, jwtService.Rotate("next", "short-secret"))
+}
+
+func TestNewJWTService_EphemeralKeyOutsideProduction(t *testing.T) {
+	cfg := testSessionConfig("")
+
+	jwtService, err := NewJWTService(cfg, false)
+	require.NoError(t, err)
+	token, err := jwtService.GenerateToken(1, "user@example.com", "session-1", false)
+	require.NoError(t, err)
+
+	// The key lasts for the process, so another service and a reload still accept the token
+	other, err := NewJWTService(cfg, false)
+	require.NoError(t, err)
+	_, err = other.ValidateToken(token)
+	assert.NoError(t, err)
+	require.NoError(t, jwtService.ReloadKeys(cfg))
+	_, err = jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+// signTestToken signs claims with key "a" as configured by newTestJWTService(t, "a:secret-a")
+func signTestToken(t *testing.T, claims JWTClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "a"
+	signed, err := token.SignedString([]byte("secret-a"))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTService_RejectsExpiredToken(t *testing.T) {
+	jwtService := newTestJWTService(t, "a:secret-a")
+	now := time.Now()
+
+	// The clock skew leeway does not extend expiry
+	token := signTestToken(t, JWTClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(-time.Second)),
+	}})
+	_, err := jwtService.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	// A token without exp never expires, so it is rejected too
+	token = signTestToken(t, JWTClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now)}})
+	_, err = jwtService.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestJWTService_IssuedAtClockSkew(t *testing.T) {
+	jwtService := newTestJWTService(t, "a:secret-a")
+	now := time.Now()
+
+	issuedIn := func(offset time.Duration) string {
+		return signTestToken(t, JWTClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now.Add(offset)),
+			NotBefore: jwt.NewNumericDate(now.Add(offset)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}})
+	}
+
+	_, err := jwtService.ValidateToken(issuedIn(2 * time.Minute))
+	assert.ErrorIs(t, err, ErrTokenIssuedInFuture)
+
+	// A server whose clock is slightly ahead issues tokens that are still accepted
+	_, err = jwtService.ValidateToken(issuedIn(30 * time.Second))
+	assert.NoError(t, err)
+
+	cfg := testSessionConfig("a:secret-a")
+	cfg.JWTClockSkew = 0
+	strict, err := NewJWTService(cfg, false)
+	require.NoError(t, err)
+	_, err = strict.ValidateToken(issuedIn(30 * time.Second))
+	assert.ErrorIs(t, err, ErrTokenIssuedInFuture)
+}
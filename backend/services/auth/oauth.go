@@ -3,26 +3,49 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 	"domain/auth/entities"
 	"todo-app/internal/dtos"
+	userservice "todo-app/services/user"
 )
 
+// ErrRefreshTokenReused is returned when a session's refresh token was already
+// rotated by another refresh, e.g. when an old refresh flow is replayed
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+// ErrReauthenticationRequired is returned when the provider rejects a session's
+// refresh token (invalid_grant), e.g. because the user revoked access
+var ErrReauthenticationRequired = errors.New("oauth grant is no longer valid; re-authentication required")
+
 // OAuthService handles OAuth flow operations
 type OAuthService struct {
 	db           *gorm.DB
 	googleConfig *GoogleOAuthConfig
+	redirectURIs *entities.RedirectURIValidator
+	sessionTTL   time.Duration
+	autoLink     bool
 }
 
-// NewOAuthService creates a new OAuth service
-func NewOAuthService(db *gorm.DB, googleConfig *GoogleOAuthConfig) *OAuthService {
+// NewOAuthService creates a new OAuth service; redirectURIs decides where the
+// flow may return to and is required, so a misconfigured service fails at
+// startup rather than on the first sign-in. Sessions last sessionTTL, and
+// autoLink links sign-ins to existing accounts with the same verified email.
+func NewOAuthService(db *gorm.DB, googleConfig *GoogleOAuthConfig, redirectURIs *entities.RedirectURIValidator, sessionTTL time.Duration, autoLink bool) (*OAuthService, error) {
+	if redirectURIs == nil {
+		return nil, errors.New("oauth service requires a redirect URI validator")
+	}
+
 	return &OAuthService{
 		db:           db,
 		googleConfig: googleConfig,
-	}
+		redirectURIs: redirectURIs,
+		sessionTTL:   sessionTTL,
+		autoLink:     autoLink,
+	}, nil
 }
 
 // InitiateOAuthFlowResult represents the result of initiating OAuth flow
@@ -34,7 +57,7 @@ type InitiateOAuthFlowResult struct {
 // InitiateOAuthFlow starts the OAuth flow by creating state and generating auth URL
 func (s *OAuthService) InitiateOAuthFlow(ctx context.Context, redirectURI string) (*InitiateOAuthFlowResult, error) {
 	// Create OAuth state with PKCE
-	oauthState, err := entities.CreateAndSave(s.db, redirectURI)
+	oauthState, err := entities.CreateAndSave(s.db, redirectURI, s.redirectURIs)
 	if err != nil {
 		return nil, err
 	}
@@ -103,61 +126,13 @@ func (s *OAuthService) ProcessOAuthCallback(ctx context.Context, code, state str
 	}, nil
 }
 
-// findOrCreateUser finds an existing user or creates a new one from Google user info
+// findOrCreateUser finds an existing user or creates a new one from Google user info.
+// Users matched by email are linked under the same rules as other OAuth sign-ins.
 func (s *OAuthService) findOrCreateUser(userInfo *GoogleUserInfo) (*dtos.User, bool, error) {
-	var user dtos.User
-	isNewUser := false
-
-	// Try to find user by Google ID
-	result := s.db.Where("google_id = ?", userInfo.ID).First(&user)
-	if result.Error == nil {
-		// User exists with this Google ID
-		return &user, false, nil
-	}
-
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, false, result.Error
-	}
-
-	// Try to find user by email (for account linking)
-	result = s.db.Where("email = ?", userInfo.Email).First(&user)
-	if result.Error == nil {
-		// User exists with this email - link Google account
-		now := time.Now()
-		err := user.LinkGoogleAccount(userInfo.ID, now)
-		if err != nil {
-			return nil, false, err
-		}
-
-		// Save the linked account
-		if err := s.db.Save(&user).Error; err != nil {
-			return nil, false, err
-		}
-
-		return &user, false, nil
-	}
-
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, false, result.Error
-	}
-
-	// Create new user
-	now := time.Now()
-	newUser := dtos.User{
-		Email:          userInfo.Email,
-		Name:           userInfo.Name,
-		GoogleID:       userInfo.ID,
-		OAuthProvider:  "google",
-		OAuthCreatedAt: &now,
-		IsActive:       true,
-	}
-
-	if err := s.db.Create(&newUser).Error; err != nil {
-		return nil, false, err
-	}
-
-	isNewUser = true
-	return &newUser, isNewUser, nil
+	userService := userservice.NewUserService(s.db)
+	userService.SetOAuthAutoLink(s.autoLink)
+	return userService.FindOrCreateOAuthUser(
+		dtos.OAuthProviderGoogle, userInfo.ID, userInfo.Email, userInfo.VerifiedEmail, userInfo.Name)
 }
 
 // createOAuthSession creates a new authentication session with OAuth tokens
@@ -166,7 +141,7 @@ func (s *OAuthService) createOAuthSession(userID uint, token *oauth2.Token) (*en
 	sessionToken := generateSessionToken()
 
 	// Calculate expiration times
-	sessionExpiresAt := time.Now().Add(24 * time.Hour)
+	sessionExpiresAt := time.Now().Add(s.sessionTTL)
 	tokenExpiresAt := token.Expiry
 
 	// Create session
@@ -188,7 +163,10 @@ func (s *OAuthService) createOAuthSession(userID uint, token *oauth2.Token) (*en
 	return session, nil
 }
 
-// RefreshOAuthToken refreshes the OAuth access token using refresh token
+// RefreshOAuthToken refreshes the OAuth access token using refresh token and
+// stores the refresh token Google returns in place of the old one. The swap only
+// succeeds if the stored refresh token is still the one used for this refresh,
+// so the old token cannot be replayed once rotated.
 func (s *OAuthService) RefreshOAuthToken(ctx context.Context, sessionID string) (*entities.AuthenticationSession, error) {
 	var session entities.AuthenticationSession
 
@@ -207,11 +185,16 @@ func (s *OAuthService) RefreshOAuthToken(ctx context.Context, sessionID string)
 	if session.RefreshToken == "" {
 		return nil, errors.New("no refresh token available")
 	}
+	previousRefreshToken := session.RefreshToken
 
-	// Refresh the token with Google
-	newToken, err := s.googleConfig.RefreshToken(ctx, session.RefreshToken)
+	// Refresh the token with Google; the old refresh token is carried over
+	// when Google does not issue a new one
+	newToken, err := s.googleConfig.RefreshToken(ctx, previousRefreshToken)
 	if err != nil {
-		return nil, errors.New("failed to refresh token: " + err.Error())
+		if isInvalidGrant(err) {
+			return nil, fmt.Errorf("%w: %v", ErrReauthenticationRequired, err)
+		}
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
 
 	// Update session with new tokens
@@ -220,14 +203,31 @@ func (s *OAuthService) RefreshOAuthToken(ctx context.Context, sessionID string)
 		return nil, err
 	}
 
-	// Save updated session
-	if err := s.db.Save(&session).Error; err != nil {
-		return nil, err
+	// Save updated tokens unless another refresh already rotated them
+	result = s.db.Model(&session).
+		Where("refresh_token = ?", previousRefreshToken).
+		Updates(map[string]interface{}{
+			"access_token":     session.AccessToken,
+			"refresh_token":    session.RefreshToken,
+			"token_expires_at": session.TokenExpiresAt,
+			"last_activity":    session.LastActivity,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrRefreshTokenReused
 	}
 
 	return &session, nil
 }
 
+// isInvalidGrant reports whether the token endpoint rejected the refresh token itself
+func isInvalidGrant(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	return errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant"
+}
+
 // RevokeOAuthAccess revokes OAuth access and terminates sessions
 func (s *OAuthService) RevokeOAuthAccess(ctx context.Context, accessToken string) error {
 	// Revoke token with Google
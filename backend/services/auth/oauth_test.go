@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+	userservice "todo-app/services/user"
+)
+
+// newRotatingTokenServer fakes Google's token endpoint, issuing a new refresh
+// token on every refresh; onRefresh, if set, runs before each response
+func newRotatingTokenServer(t *testing.T, onRefresh func()) *GoogleOAuthConfig {
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+		if onRefresh != nil {
+			onRefresh()
+		}
+
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","token_type":"Bearer","expires_in":3600}`, n, n)
+	}))
+	t.Cleanup(server.Close)
+
+	return &GoogleOAuthConfig{config: &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams},
+	}}
+}
+
+func createOAuthSession(t *testing.T, sessionService *SessionService, userID uint, email string) *entities.AuthenticationSession {
+	expiry := time.Now().Add(time.Hour)
+	session, _, err := sessionService.CreateSession(CreateSessionRequest{
+		UserID:       userID,
+		Email:        email,
+		IsOAuth:      true,
+		AccessToken:  "access-original",
+		RefreshToken: "refresh-original",
+		TokenExpiry:  &expiry,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+// newTestOAuthService creates an OAuth service that may redirect to the local frontend
+func newTestOAuthService(t *testing.T, db *gorm.DB, googleConfig *GoogleOAuthConfig) *OAuthService {
+	redirectURIs, err := entities.NewRedirectURIValidator([]string{"http://localhost:3000/*"})
+	require.NoError(t, err)
+	oauthService, err := NewOAuthService(db, googleConfig, redirectURIs, entities.DefaultSessionTTL, true)
+	require.NoError(t, err)
+	return oauthService
+}
+
+func TestNewOAuthService_RequiresRedirectURIValidator(t *testing.T) {
+	_, err := NewOAuthService(nil, nil, nil, entities.DefaultSessionTTL, true)
+	assert.Error(t, err)
+}
+
+func TestNewGoogleOAuthConfig(t *testing.T) {
+	t.Setenv("GOOGLE_CLIENT_ID", "env-client")
+
+	googleConfig, err := NewGoogleOAuthConfig(config.OAuthConfig{
+		GoogleClientID:     "client",
+		GoogleClientSecret: "secret",
+		GoogleRedirectURI:  "http://localhost:8080/auth/google/callback",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "client", googleConfig.GetClientID())
+	assert.Equal(t, "http://localhost:8080/auth/google/callback", googleConfig.GetRedirectURI())
+	assert.Equal(t, "secret", googleConfig.GetConfig().ClientSecret)
+
+	_, err = NewGoogleOAuthConfig(config.OAuthConfig{GoogleClientID: "client", GoogleClientSecret: "secret"})
+	assert.ErrorContains(t, err, "GOOGLE_REDIRECT_URI")
+
+	_, err = NewGoogleOAuthConfig(config.OAuthConfig{})
+	assert.ErrorContains(t, err, "GOOGLE_CLIENT_ID")
+}
+
+func TestRefreshOAuthToken_StoresRotatedRefreshToken(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	oauthService := newTestOAuthService(t, db, newRotatingTokenServer(t, nil))
+	session := createOAuthSession(t, sessionService, user.ID, user.Email)
+
+	refreshed, err := oauthService.RefreshOAuthToken(context.Background(), session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-1", refreshed.RefreshToken)
+
+	var stored entities.AuthenticationSession
+	require.NoError(t, db.First(&stored, "id = ?", session.ID).Error)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+	assert.NotEqual(t, "refresh-original", stored.RefreshToken)
+	assert.Equal(t, "access-1", stored.AccessToken)
+}
+
+func TestRefreshOAuthToken_RejectsRotatedRefreshToken(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	session := createOAuthSession(t, sessionService, user.ID, user.Email)
+
+	// A concurrent refresh rotates the stored token while this one is in flight
+	oauthService := newTestOAuthService(t, db, newRotatingTokenServer(t, func() {
+		require.NoError(t, db.Exec("UPDATE authentication_sessions SET refresh_token = ? WHERE id = ?", "refresh-concurrent", session.ID).Error)
+	}))
+
+	_, err := oauthService.RefreshOAuthToken(context.Background(), session.ID)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	var stored entities.AuthenticationSession
+	require.NoError(t, db.First(&stored, "id = ?", session.ID).Error)
+	assert.Equal(t, "refresh-concurrent", stored.RefreshToken, "the winning refresh is kept")
+}
+
+func TestRefreshSession_RotatesSessionIDAndToken(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	session, oldToken, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	refreshed, newToken, err := sessionService.RefreshSession(session.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, session.ID, refreshed.ID)
+	assert.NotEqual(t, oldToken, newToken)
+	assert.Equal(t, int64(1), countUserSessions(t, db, user.ID))
+
+	result, err := sessionService.ValidateSession(oldToken)
+	require.NoError(t, err)
+	assert.False(t, result.Valid, "the pre-rotation token is rejected")
+
+	result, err = sessionService.ValidateSession(newToken)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// Replaying the refresh for the old session ID fails
+	_, _, err = sessionService.RefreshSession(session.ID)
+	assert.Error(t, err)
+}
+
+// newInvalidGrantTokenServer fakes Google's token endpoint rejecting a revoked refresh token
+func newInvalidGrantTokenServer(t *testing.T) *GoogleOAuthConfig {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return &GoogleOAuthConfig{config: &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams},
+	}}
+}
+
+// createExpiringOAuthSession creates an OAuth session whose access token is due for refresh
+func createExpiringOAuthSession(t *testing.T, db *gorm.DB, sessionService *SessionService, userID uint, email string) *entities.AuthenticationSession {
+	session := createOAuthSession(t, sessionService, userID, email)
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).
+		Where("id = ?", session.ID).
+		UpdateColumn("token_expires_at", time.Now().Add(time.Minute)).Error)
+	return session
+}
+
+func TestValidateSession_RefreshesExpiringOAuthTokens(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	sessionService.SetOAuthTokenRefresher(newTestOAuthService(t, db, newRotatingTokenServer(t, nil)))
+	session := createExpiringOAuthSession(t, db, sessionService, user.ID, user.Email)
+
+	result, err := sessionService.ValidateSession(session.SessionToken)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+	assert.False(t, result.NeedsRefresh)
+	assert.Equal(t, "access-1", result.Session.AccessToken)
+
+	var stored entities.AuthenticationSession
+	require.NoError(t, db.First(&stored, "id = ?", session.ID).Error)
+	assert.Equal(t, "access-1", stored.AccessToken)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+	require.NotNil(t, stored.TokenExpiresAt)
+	assert.True(t, stored.TokenExpiresAt.After(time.Now().Add(30*time.Minute)))
+}
+
+func TestValidateSession_ConcurrentValidationsRefreshOnce(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	var refreshes int32
+	sessionService.SetOAuthTokenRefresher(newTestOAuthService(t, db, newRotatingTokenServer(t, func() {
+		atomic.AddInt32(&refreshes, 1)
+		// Hold the refresh open so the other validations overlap it
+		time.Sleep(50 * time.Millisecond)
+	})))
+	session := createExpiringOAuthSession(t, db, sessionService, user.ID, user.Email)
+
+	const validations = 10
+	var wg sync.WaitGroup
+	results := make([]*entities.SessionValidationResult, validations)
+	for i := 0; i < validations; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := sessionService.ValidateSession(session.SessionToken)
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&refreshes))
+	for _, result := range results {
+		require.NotNil(t, result)
+		assert.True(t, result.Valid)
+		assert.Equal(t, "access-1", result.Session.AccessToken)
+	}
+
+	var stored entities.AuthenticationSession
+	require.NoError(t, db.First(&stored, "id = ?", session.ID).Error)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+}
+
+func TestValidateSession_InvalidGrantRequiresReauthentication(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+	sessionService.SetOAuthTokenRefresher(newTestOAuthService(t, db, newInvalidGrantTokenServer(t)))
+	session := createExpiringOAuthSession(t, db, sessionService, user.ID, user.Email)
+
+	result, err := sessionService.ValidateSession(session.SessionToken)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorReauthenticationRequired, result.Error)
+	assert.Equal(t, int64(0), countUserSessions(t, db, user.ID))
+}
+
+func TestValidateSession_AutoRefreshCanBeDisabled(t *testing.T) {
+	t.Setenv("OAUTH_TOKEN_AUTO_REFRESH", "false")
+	db, sessionService, user := setupSessionServiceTest(t)
+	sessionService.SetOAuthTokenRefresher(newTestOAuthService(t, db, newInvalidGrantTokenServer(t)))
+	session := createExpiringOAuthSession(t, db, sessionService, user.ID, user.Email)
+
+	result, err := sessionService.ValidateSession(session.SessionToken)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.True(t, result.NeedsRefresh)
+	assert.Equal(t, "access-original", result.Session.AccessToken)
+}
+
+func TestFindOrCreateUser_RejectsEmailLinkedToDifferentGoogleID(t *testing.T) {
+	db, _, user := setupSessionServiceTest(t)
+	oauthService := newTestOAuthService(t, db, nil)
+
+	_, _, err := oauthService.findOrCreateUser(&GoogleUserInfo{ID: "google-456", Email: user.Email, Name: user.Name})
+	assert.ErrorIs(t, err, userservice.ErrAccountAlreadyLinked)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, user.ID).Error)
+	assert.Equal(t, "google-123", reloaded.GoogleID)
+}
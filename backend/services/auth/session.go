@@ -1,28 +1,103 @@
 package auth
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"log"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"domain/auth/entities"
+	"todo-app/internal/config"
 	"todo-app/internal/dtos"
+	"todo-app/utils"
 )
 
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// DefaultSessionIdleTimeout is how long a session may go unused before it is invalidated
+const DefaultSessionIdleTimeout = config.DefaultSessionIdleTimeout
+
+// DefaultOAuthTokenTTL is assumed for OAuth access tokens issued without an expiry
+const DefaultOAuthTokenTTL = config.DefaultOAuthTokenTTL
+
+// oauthTokenRefreshTimeout bounds the provider call made while validating a session
+const oauthTokenRefreshTimeout = 10 * time.Second
+
+// ephemeralCSRFKey keys CSRF tokens when no CSRF secret is configured. It
+// lasts for the life of the process, so CSRF tokens change on restart.
+var ephemeralCSRFKey = sync.OnceValue(func() []byte {
+	key := make([]byte, config.MinCSRFSecretBytes)
+	rand.Read(key)
+	return key
+})
+
+// OAuthTokenRefresher refreshes the OAuth tokens stored on a session
+type OAuthTokenRefresher interface {
+	RefreshOAuthToken(ctx context.Context, sessionID string) (*entities.AuthenticationSession, error)
+}
+
 // SessionService handles session management operations
 type SessionService struct {
-	db         *gorm.DB
-	jwtService *JWTService
+	db                 *gorm.DB
+	jwtService         *JWTService
+	maxSessionsPerUser int
+	idleTimeout        time.Duration
+	sessionTTL         time.Duration
+	oauthTokenTTL      time.Duration
+	csrfKey            []byte
+	cookies            utils.CookieConfig
+	now                func() time.Time
+
+	// tokenRefresher, if set and autoRefresh is on, renews OAuth tokens that
+	// are about to expire during validation; refreshGroup collapses concurrent
+	// refreshes of one session so its refresh token is only spent once
+	tokenRefresher OAuthTokenRefresher
+	autoRefresh    bool
+	refreshGroup   singleflight.Group
 }
 
-// NewSessionService creates a new session service
-func NewSessionService(db *gorm.DB, jwtService *JWTService) *SessionService {
+// NewSessionService creates a new session service from the session
+// configuration. Without a CSRF secret an ephemeral key is used.
+func NewSessionService(db *gorm.DB, jwtService *JWTService, cfg config.SessionConfig) *SessionService {
+	csrfKey := []byte(cfg.CSRFSecret)
+	if cfg.CSRFSecret == "" {
+		log.Printf("Warning: CSRF_SECRET is not set; using an ephemeral key, so CSRF tokens change when the server restarts")
+		csrfKey = ephemeralCSRFKey()
+	}
+
 	return &SessionService{
-		db:         db,
-		jwtService: jwtService,
+		db:                 db,
+		jwtService:         jwtService,
+		maxSessionsPerUser: cfg.MaxSessionsPerUser,
+		idleTimeout:        cfg.IdleTimeout,
+		sessionTTL:         cfg.TTL,
+		oauthTokenTTL:      cfg.OAuthTokenTTL,
+		csrfKey:            csrfKey,
+		cookies:            utils.NewCookieConfig(cfg.CookieSecure, cfg.CookieSameSite),
+		now:                time.Now,
+		autoRefresh:        cfg.OAuthTokenAutoRefresh,
 	}
 }
 
+// SetOAuthTokenRefresher enables refreshing OAuth tokens during session validation
+func (s *SessionService) SetOAuthTokenRefresher(refresher OAuthTokenRefresher) {
+	s.tokenRefresher = refresher
+}
+
+// Cookies returns the configuration of the session and other authentication cookies
+func (s *SessionService) Cookies() utils.CookieConfig {
+	return s.cookies
+}
+
 // CreateSessionRequest represents the data needed to create a session
 type CreateSessionRequest struct {
 	UserID       uint
@@ -33,18 +108,21 @@ type CreateSessionRequest struct {
 	AccessToken  string
 	RefreshToken string
 	TokenExpiry  *time.Time
+
+	// ProviderSessionID is the identity provider's session ID (OIDC "sid"), if known
+	ProviderSessionID string
 }
 
 // CreateSession creates a new authentication session
 func (s *SessionService) CreateSession(req CreateSessionRequest) (*entities.AuthenticationSession, string, error) {
-	// Calculate session expiration (24 hours)
-	sessionExpiresAt := time.Now().Add(24 * time.Hour)
+	// Calculate session expiration
+	sessionExpiresAt := time.Now().Add(s.sessionTTL)
 
 	var session *entities.AuthenticationSession
 
 	if req.IsOAuth && req.AccessToken != "" {
 		// Create OAuth session
-		tokenExpiry := time.Now().Add(1 * time.Hour) // Default 1 hour
+		tokenExpiry := time.Now().Add(s.oauthTokenTTL)
 		if req.TokenExpiry != nil {
 			tokenExpiry = *req.TokenExpiry
 		}
@@ -77,20 +155,82 @@ func (s *SessionService) CreateSession(req CreateSessionRequest) (*entities.Auth
 	}
 
 	session.SessionToken = jwtToken
+	session.ProviderSessionID = req.ProviderSessionID
 
-	// Save session to database
-	if err := s.db.Create(session).Error; err != nil {
+	// Save session and record the login event, evicting the oldest
+	// sessions first if the user is at the session limit
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.evictOldestSessions(tx, req.UserID); err != nil {
+			return err
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(entities.NewLoginEvent(session)).Error
+	})
+	if err != nil {
 		return nil, "", err
 	}
 
 	return session, jwtToken, nil
 }
 
+// evictOldestSessions terminates the user's oldest sessions so that one more
+// session can be created without exceeding maxSessionsPerUser. The user's row
+// is locked until tx ends, so concurrent logins, even in other server
+// processes, count the sessions one at a time.
+func (s *SessionService) evictOldestSessions(tx *gorm.DB, userID uint) error {
+	if s.maxSessionsPerUser <= 0 {
+		return nil
+	}
+
+	// SQLite has no row locks, but only lets one transaction write at a time
+	var locked []uint
+	err := tx.Model(&dtos.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID).Pluck("id", &locked).Error
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&entities.AuthenticationSession{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	excess := int(count) - s.maxSessionsPerUser + 1
+	if excess <= 0 {
+		return nil
+	}
+
+	var oldestIDs []string
+	err = tx.Model(&entities.AuthenticationSession{}).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Limit(excess).
+		Pluck("id", &oldestIDs).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("id IN ?", oldestIDs).Delete(&entities.AuthenticationSession{}).Error
+}
+
 // ValidateSession validates a session token and returns the session
 func (s *SessionService) ValidateSession(tokenString string) (*entities.SessionValidationResult, error) {
 	// Validate JWT token
 	claims, err := s.jwtService.ValidateToken(tokenString)
-	if err != nil {
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		return &entities.SessionValidationResult{
+			Valid: false,
+			Error: entities.SessionErrorTokenExpired,
+		}, nil
+	case errors.Is(err, ErrTokenIssuedInFuture):
+		return &entities.SessionValidationResult{
+			Valid: false,
+			Error: entities.SessionErrorTokenIssuedInFuture,
+		}, nil
+	case err != nil:
 		return &entities.SessionValidationResult{
 			Valid: false,
 			Error: "invalid token: " + err.Error(),
@@ -110,13 +250,24 @@ func (s *SessionService) ValidateSession(tokenString string) (*entities.SessionV
 		return nil, result.Error
 	}
 
+	now := s.now()
+
 	// Check if session is expired
-	if session.IsExpired() {
+	if session.IsExpiredAt(now) {
 		// Delete expired session
 		s.db.Delete(&session)
 		return &entities.SessionValidationResult{
 			Valid: false,
-			Error: "session expired",
+			Error: entities.SessionErrorExpired,
+		}, nil
+	}
+
+	// Check if session has been idle too long; absolute expiry above still caps its lifetime
+	if session.IsIdleAt(s.idleTimeout, now) {
+		s.db.Delete(&session)
+		return &entities.SessionValidationResult{
+			Valid: false,
+			Error: entities.SessionErrorIdleTimeout,
 		}, nil
 	}
 
@@ -129,12 +280,40 @@ func (s *SessionService) ValidateSession(tokenString string) (*entities.SessionV
 		}, nil
 	}
 
-	// Update last activity
-	session.UpdateActivity()
-	s.db.Save(&session)
+	// Accounts pending deletion keep their sessions so they can restore themselves
+	if !user.IsActive && !user.IsPendingDeletion() {
+		s.db.Delete(&session)
+		return &entities.SessionValidationResult{
+			Valid: false,
+			Error: entities.SessionErrorAccountDeactivated,
+		}, nil
+	}
 
-	// Check if OAuth tokens need refresh
+	// Check if OAuth tokens need refresh, refreshing them now when enabled
 	needsRefresh := session.NeedsRefresh()
+	if needsRefresh && s.autoRefresh && s.tokenRefresher != nil {
+		refreshed, err := s.refreshOAuthTokens(session.ID)
+		switch {
+		case errors.Is(err, ErrReauthenticationRequired):
+			s.db.Delete(&session)
+			return &entities.SessionValidationResult{
+				Valid: false,
+				Error: entities.SessionErrorReauthenticationRequired,
+			}, nil
+		case err != nil:
+			// The session stays usable; the client can still refresh explicitly
+			log.Printf("Failed to refresh OAuth tokens for session %s: %v", session.ID, err)
+		default:
+			session.AccessToken = refreshed.AccessToken
+			session.RefreshToken = refreshed.RefreshToken
+			session.TokenExpiresAt = refreshed.TokenExpiresAt
+			needsRefresh = session.NeedsRefresh()
+		}
+	}
+
+	// Update last activity without rewriting the tokens a concurrent refresh may have rotated
+	session.UpdateActivity()
+	s.db.Model(&session).Update("last_activity", session.LastActivity)
 
 	return &entities.SessionValidationResult{
 		Valid:        true,
@@ -144,7 +323,38 @@ func (s *SessionService) ValidateSession(tokenString string) (*entities.SessionV
 	}, nil
 }
 
-// RefreshSession refreshes a session and extends its expiration
+// refreshOAuthTokens refreshes a session's OAuth tokens, sharing the result
+// with concurrent callers for the same session
+func (s *SessionService) refreshOAuthTokens(sessionID string) (*entities.AuthenticationSession, error) {
+	result, err, _ := s.refreshGroup.Do(sessionID, func() (interface{}, error) {
+		// A refresh that finished just before this one may already have renewed the tokens
+		current, err := s.GetSession(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !current.NeedsRefresh() {
+			return current, nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), oauthTokenRefreshTimeout)
+		defer cancel()
+
+		refreshed, err := s.tokenRefresher.RefreshOAuthToken(ctx, sessionID)
+		if errors.Is(err, ErrRefreshTokenReused) {
+			// Another instance rotated the tokens first; use what it stored
+			return s.GetSession(sessionID)
+		}
+		return refreshed, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*entities.AuthenticationSession), nil
+}
+
+// RefreshSession extends a session and rotates it: the session gets a new ID and
+// token, and the old ID is deleted so the previous token can no longer be used
 func (s *SessionService) RefreshSession(sessionID string) (*entities.AuthenticationSession, string, error) {
 	var session entities.AuthenticationSession
 
@@ -154,11 +364,18 @@ func (s *SessionService) RefreshSession(sessionID string) (*entities.Authenticat
 		return nil, "", result.Error
 	}
 
+	now := s.now()
+
 	// Check if session is expired
-	if session.IsExpired() {
+	if session.IsExpiredAt(now) {
 		return nil, "", errors.New("session has expired")
 	}
 
+	// An idle session must not be revived by refreshing it
+	if session.IsIdleAt(s.idleTimeout, now) {
+		return nil, "", errors.New("session has been idle too long")
+	}
+
 	// Get user for JWT generation
 	var user dtos.User
 	if err := s.db.Where("id = ?", session.UserID).First(&user).Error; err != nil {
@@ -166,11 +383,12 @@ func (s *SessionService) RefreshSession(sessionID string) (*entities.Authenticat
 	}
 
 	// Extend session
-	if err := session.ExtendSession(); err != nil {
+	if err := session.ExtendSession(s.sessionTTL); err != nil {
 		return nil, "", err
 	}
 
-	// Generate new JWT token
+	// Issue the rotated session under a new ID
+	session.RotateID()
 	jwtToken, err := s.jwtService.GenerateToken(
 		session.UserID,
 		user.Email,
@@ -183,8 +401,18 @@ func (s *SessionService) RefreshSession(sessionID string) (*entities.Authenticat
 
 	session.SessionToken = jwtToken
 
-	// Save updated session
-	if err := s.db.Save(&session).Error; err != nil {
+	// Replace the old session; if it is already gone another refresh won the race
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Where("id = ?", sessionID).Delete(&entities.AuthenticationSession{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&session).Error
+	})
+	if err != nil {
 		return nil, "", err
 	}
 
@@ -212,10 +440,56 @@ func (s *SessionService) TerminateSession(sessionID string) error {
 	return nil
 }
 
-// TerminateAllUserSessions terminates all sessions for a user
-func (s *SessionService) TerminateAllUserSessions(userID uint) error {
+// TerminateAllUserSessions terminates all sessions for a user and returns how many were removed
+func (s *SessionService) TerminateAllUserSessions(userID uint) (int64, error) {
 	result := s.db.Where("user_id = ?", userID).Delete(&entities.AuthenticationSession{})
-	return result.Error
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// TerminateUserSession terminates one of a user's sessions.
+// Returns ErrSessionNotFound if the session does not exist or belongs to someone else.
+func (s *SessionService) TerminateUserSession(userID uint, sessionID string) error {
+	result := s.db.Where("id = ? AND user_id = ?", sessionID, userID).Delete(&entities.AuthenticationSession{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// TerminateOtherUserSessions terminates all of a user's sessions except keepSessionID
+// and returns how many were removed
+func (s *SessionService) TerminateOtherUserSessions(userID uint, keepSessionID string) (int64, error) {
+	result := s.db.Where("user_id = ? AND id <> ?", userID, keepSessionID).Delete(&entities.AuthenticationSession{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// TerminateProviderSessions terminates sessions created from the given identity provider session.
+// If userID is non-zero, only that user's sessions are affected.
+func (s *SessionService) TerminateProviderSessions(providerSessionID string, userID uint) (int64, error) {
+	query := s.db.Where("provider_session_id = ?", providerSessionID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	result := query.Delete(&entities.AuthenticationSession{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
 }
 
 // GetUserSessions retrieves all active sessions for a user
@@ -233,9 +507,15 @@ func (s *SessionService) GetUserSessions(userID uint) ([]entities.Authentication
 	return sessions, nil
 }
 
-// CleanupExpiredSessions removes expired sessions from the database
+// CleanupExpiredSessions removes expired and idle-timed-out sessions from the database
 func (s *SessionService) CleanupExpiredSessions() (int64, error) {
-	result := s.db.Where("session_expires_at <= ?", time.Now()).Delete(&entities.AuthenticationSession{})
+	now := s.now()
+	query := s.db.Where("session_expires_at <= ?", now)
+	if s.idleTimeout > 0 {
+		query = query.Or("last_activity < ?", now.Add(-s.idleTimeout))
+	}
+
+	result := query.Delete(&entities.AuthenticationSession{})
 	if result.Error != nil {
 		return 0, result.Error
 	}
@@ -247,7 +527,7 @@ func (s *SessionService) CleanupExpiredSessions() (int64, error) {
 func (s *SessionService) GetSession(sessionID string) (*entities.AuthenticationSession, error) {
 	var session entities.AuthenticationSession
 
-	result := s.db.Preload("User").Where("id = ?", sessionID).First(&session)
+	result := s.db.Where("id = ?", sessionID).First(&session)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -273,6 +553,21 @@ func (s *SessionService) GetSessionByToken(tokenString string) (*entities.Authen
 	return s.GetSession(sessionID)
 }
 
+// CSRFToken returns the CSRF token for a session. It is derived from the session
+// ID with the CSRF secret, so every login, and every rotation of the session,
+// gets a new token. Changing the secret changes it too; the auth middleware
+// reissues the CSRF cookie when it goes stale.
+func (s *SessionService) CSRFToken(sessionID string) string {
+	mac := hmac.New(sha256.New, s.csrfKey)
+	mac.Write([]byte("csrf:" + sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GetSessionMaxAge returns the max age in seconds for session cookies, matching the session lifetime
+func (s *SessionService) GetSessionMaxAge() int {
+	return int(s.sessionTTL.Seconds())
+}
+
 // IsSessionValid checks if a session is valid without full validation
 func (s *SessionService) IsSessionValid(sessionID string) (bool, error) {
 	var count int64
@@ -285,4 +580,14 @@ func (s *SessionService) IsSessionValid(sessionID string) (bool, error) {
 	}
 
 	return count > 0, nil
-}
\ No newline at end of file
+}
+
+// GetLoginHistory returns the most recent login events for a user, newest first
+func (s *SessionService) GetLoginHistory(userID uint, limit int) ([]entities.LoginEvent, error) {
+	var events []entities.LoginEvent
+	err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
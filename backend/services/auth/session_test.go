@@ -0,0 +1,339 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"domain/auth/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/config"
+	"todo-app/internal/dtos"
+)
+
+// loadTestSessionConfig loads the session settings from the test's environment
+func loadTestSessionConfig(t *testing.T) config.SessionConfig {
+	t.Setenv("JWT_SECRET", "test-secret")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	return cfg.Session
+}
+
+func setupSessionServiceTest(t *testing.T) (*gorm.DB, *SessionService, *dtos.User) {
+	sessionConfig := loadTestSessionConfig(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &dtos.OAuthIdentity{}, &entities.AuthenticationSession{}, &entities.LoginEvent{}))
+
+	jwtService, err := NewJWTService(sessionConfig, false)
+	require.NoError(t, err)
+
+	user := dtos.User{
+		Email:         "user@example.com",
+		Name:          "Test User",
+		GoogleID:      "google-123",
+		OAuthProvider: "google",
+		IsActive:      true,
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	return db, NewSessionService(db, jwtService, sessionConfig), &user
+}
+
+func countUserSessions(t *testing.T, db *gorm.DB, userID uint) int64 {
+	var count int64
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("user_id = ?", userID).Count(&count).Error)
+	return count
+}
+
+func TestCreateSession_UnlimitedByDefault(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	for i := 0; i < 5; i++ {
+		_, _, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(5), countUserSessions(t, db, user.ID))
+}
+
+func TestCreateSession_EvictsOldestSessionAtLimit(t *testing.T) {
+	t.Setenv("MAX_SESSIONS_PER_USER", "2")
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	var sessionIDs []string
+	for i := 0; i < 3; i++ {
+		session, _, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+		require.NoError(t, err)
+		sessionIDs = append(sessionIDs, session.ID)
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, int64(2), countUserSessions(t, db, user.ID))
+
+	valid, err := sessionService.IsSessionValid(sessionIDs[0])
+	require.NoError(t, err)
+	assert.False(t, valid, "oldest session should have been evicted")
+
+	for _, id := range sessionIDs[1:] {
+		valid, err := sessionService.IsSessionValid(id)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+}
+
+func TestCreateSession_ConcurrentCreationsRespectLimit(t *testing.T) {
+	t.Setenv("MAX_SESSIONS_PER_USER", "2")
+	db, sessionService, user := setupSessionServiceTest(t)
+	// Logins are spread over two services, as over two server processes
+	services := []*SessionService{sessionService, NewSessionService(db, sessionService.jwtService, loadTestSessionConfig(t))}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(service *SessionService) {
+			defer wg.Done()
+			_, _, err := service.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+			assert.NoError(t, err)
+		}(services[i%len(services)])
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(2), countUserSessions(t, db, user.ID))
+}
+
+// setLastActivity backdates a session's last activity
+func setLastActivity(t *testing.T, db *gorm.DB, sessionID string, lastActivity time.Time) {
+	require.NoError(t, db.Model(&entities.AuthenticationSession{}).Where("id = ?", sessionID).UpdateColumn("last_activity", lastActivity).Error)
+}
+
+func TestValidateSession_IdleSessionIsInvalidated(t *testing.T) {
+	t.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	session, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	setLastActivity(t, db, session.ID, time.Now().Add(-31*time.Minute))
+
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorIdleTimeout, result.Error)
+	assert.Equal(t, int64(0), countUserSessions(t, db, user.ID))
+}
+
+func TestValidateSession_DeactivatedUserIsRejected(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	_, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(user).Update("is_active", false).Error)
+
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorAccountDeactivated, result.Error)
+	assert.Equal(t, int64(0), countUserSessions(t, db, user.ID))
+}
+
+func TestValidateSession_PendingDeletionUserKeepsSession(t *testing.T) {
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	_, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	user.ScheduleDeletion(time.Now().Add(24 * time.Hour))
+	require.NoError(t, db.Save(user).Error)
+
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestValidateSession_ActivityKeepsSessionAlive(t *testing.T) {
+	t.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	session, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	setLastActivity(t, db, session.ID, time.Now().Add(-29*time.Minute))
+
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+
+	// Validation records activity, restarting the idle clock
+	stored, err := sessionService.GetSession(session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), stored.LastActivity, time.Minute)
+}
+
+func TestValidateSession_IdleTimeoutWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		idle    time.Duration
+		valid   bool
+		errCode string
+	}{
+		{name: "just inside the window", idle: 30*time.Minute - time.Second, valid: true},
+		{name: "exactly at the timeout", idle: 30 * time.Minute, valid: true},
+		{name: "just outside the window", idle: 30*time.Minute + time.Second, errCode: entities.SessionErrorIdleTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+			db, sessionService, user := setupSessionServiceTest(t)
+
+			session, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+			require.NoError(t, err)
+			lastActivity := time.Now().UTC().Truncate(time.Second)
+			setLastActivity(t, db, session.ID, lastActivity)
+			sessionService.now = func() time.Time { return lastActivity.Add(tt.idle) }
+
+			result, err := sessionService.ValidateSession(token)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, result.Valid)
+			assert.Equal(t, tt.errCode, result.Error)
+
+			// Idle sessions are deleted; live ones are kept
+			expected := int64(0)
+			if tt.valid {
+				expected = 1
+			}
+			assert.Equal(t, expected, countUserSessions(t, db, user.ID))
+		})
+	}
+}
+
+func TestValidateSession_ExpiredSessionReportsExpired(t *testing.T) {
+	_, sessionService, user := setupSessionServiceTest(t)
+
+	session, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	// Past absolute expiry, which wins over the idle check
+	sessionService.now = func() time.Time { return session.SessionExpiresAt.Add(time.Second) }
+
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorExpired, result.Error)
+}
+
+func TestValidateSession_ReportsTokenTimeErrors(t *testing.T) {
+	_, sessionService, user := setupSessionServiceTest(t)
+
+	_, token, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	claims, err := sessionService.jwtService.ValidateToken(token)
+	require.NoError(t, err)
+
+	// This server's clock is past the token's expiry
+	sessionService.jwtService.now = func() time.Time { return claims.ExpiresAt.Add(time.Second) }
+	result, err := sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorTokenExpired, result.Error)
+
+	// The issuing server's clock ran more than the allowed skew ahead
+	sessionService.jwtService.now = func() time.Time { return claims.IssuedAt.Add(-2 * time.Minute) }
+	result, err = sessionService.ValidateSession(token)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entities.SessionErrorTokenIssuedInFuture, result.Error)
+}
+
+func TestCleanupExpiredSessions_RemovesIdleSessions(t *testing.T) {
+	t.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	active, _, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	idle, _, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	setLastActivity(t, db, active.ID, now.Add(-30*time.Minute+time.Second))
+	setLastActivity(t, db, idle.ID, now.Add(-30*time.Minute-time.Second))
+	sessionService.now = func() time.Time { return now }
+
+	removed, err := sessionService.CleanupExpiredSessions()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	_, err = sessionService.GetSession(active.ID)
+	assert.NoError(t, err)
+	_, err = sessionService.GetSession(idle.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRefreshSession_RejectsIdleSession(t *testing.T) {
+	t.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	db, sessionService, user := setupSessionServiceTest(t)
+
+	session, _, err := sessionService.CreateSession(CreateSessionRequest{UserID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+	setLastActivity(t, db, session.ID, time.Now().Add(-time.Hour))
+
+	_, _, err = sessionService.RefreshSession(session.ID)
+	assert.Error(t, err)
+}
+
+func TestCreateSession_UsesConfiguredLifetimes(t *testing.T) {
+	t.Setenv("SESSION_TTL", "168h")
+	t.Setenv("OAUTH_TOKEN_TTL", "30m")
+	_, sessionService, user := setupSessionServiceTest(t)
+
+	session, _, err := sessionService.CreateSession(CreateSessionRequest{
+		UserID:      user.ID,
+		Email:       user.Email,
+		IsOAuth:     true,
+		AccessToken: "access",
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), session.SessionExpiresAt, time.Minute)
+	require.NotNil(t, session.TokenExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), *session.TokenExpiresAt, time.Minute)
+	assert.Equal(t, 7*24*60*60, sessionService.GetSessionMaxAge())
+
+	// Refreshing extends the session by the configured lifetime too
+	refreshed, _, err := sessionService.RefreshSession(session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), refreshed.SessionExpiresAt, time.Minute)
+}
+
+func TestCSRFToken_KeyedWithCSRFSecretRatherThanJWTKey(t *testing.T) {
+	_, sessionService, _ := setupSessionServiceTest(t)
+
+	_, jwtKey := sessionService.jwtService.currentKey()
+	mac := hmac.New(sha256.New, jwtKey)
+	mac.Write([]byte("csrf:session-1"))
+	assert.NotEqual(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), sessionService.CSRFToken("session-1"))
+
+	ephemeral := sessionService.CSRFToken("session-1")
+	sessionConfig := loadTestSessionConfig(t)
+	sessionConfig.CSRFSecret = "csrf-secret"
+	configuredService := NewSessionService(sessionService.db, sessionService.jwtService, sessionConfig)
+	configured := configuredService.CSRFToken("session-1")
+	assert.NotEqual(t, ephemeral, configured)
+	assert.NotEqual(t, configured, configuredService.CSRFToken("session-2"))
+
+	// The same secret gives the same tokens, as on another server process
+	other := NewSessionService(sessionService.db, sessionService.jwtService, sessionConfig)
+	assert.Equal(t, configured, other.CSRFToken("session-1"))
+}
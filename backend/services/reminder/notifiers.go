@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	userrepos "domain/user/repositories"
+	uservo "domain/user/valueobjects"
+)
+
+// ErrNotificationSkipped is returned by a Notifier that chose not to deliver a
+// reminder, such as when the user has opted out of its channel. The
+// notification is marked skipped rather than retried.
+var ErrNotificationSkipped = errors.New("notification skipped")
+
+// Reminder is a due task reminder handed to a Notifier
+type Reminder struct {
+	NotificationID uint
+	UserID         uint
+	TaskID         uint
+	TaskTitle      string
+	DueDate        *time.Time
+	ScheduledFor   time.Time
+}
+
+// Notifier delivers reminders through one channel. Delivery is at least once:
+// a reminder whose notification could not be marked sent is delivered again.
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// LogNotifier writes reminders to the server log
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the reminder
+func (n *LogNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	log.Printf("Reminder for user %d: task %d %q", reminder.UserID, reminder.TaskID, reminder.TaskTitle)
+	return nil
+}
+
+// EmailNotifier emails reminders to users who have email notifications turned
+// on. Until an email provider is configured it writes the email to the server
+// log, so only use it where the log is private.
+type EmailNotifier struct {
+	users userrepos.UserRepository
+}
+
+// NewEmailNotifier creates a new EmailNotifier that looks up recipients in users
+func NewEmailNotifier(users userrepos.UserRepository) *EmailNotifier {
+	return &EmailNotifier{
+		users: users,
+	}
+}
+
+// Notify emails the reminder, or returns ErrNotificationSkipped if the user is
+// gone or has turned email notifications off
+func (n *EmailNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	user, err := n.users.FindByID(ctx, uservo.NewUserID(reminder.UserID))
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.Preferences().EmailNotifications() {
+		return ErrNotificationSkipped
+	}
+
+	log.Printf("Reminder email for %s: task %d %q", user.Email().Value(), reminder.TaskID, reminder.TaskTitle)
+	return nil
+}
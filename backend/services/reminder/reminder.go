@@ -0,0 +1,208 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"todo-app/application/mappers"
+	"todo-app/internal/dtos"
+)
+
+// Dispatch defaults
+const (
+	DefaultBatchSize   = 100 // due notifications handled per DispatchDue call
+	DefaultMaxAttempts = 5   // failed deliveries before a notification is given up
+)
+
+// errNotificationClaimed means another dispatcher already handled the notification
+var errNotificationClaimed = errors.New("notification already claimed")
+
+// DispatchResult counts what DispatchDue did with the due notifications
+type DispatchResult struct {
+	Sent      int
+	Skipped   int
+	Cancelled int
+	Failed    int // failed this time; they are retried until DefaultMaxAttempts
+}
+
+// ReminderService delivers task reminders when they fall due and lists a
+// user's notifications
+type ReminderService struct {
+	db        *gorm.DB
+	notifiers map[string]Notifier
+	mapper    *mappers.TaskMapper
+}
+
+// NewReminderService creates a reminder service delivering each channel's
+// notifications through its notifier
+func NewReminderService(db *gorm.DB, notifiers map[string]Notifier) *ReminderService {
+	return &ReminderService{
+		db:        db,
+		notifiers: notifiers,
+		mapper:    &mappers.TaskMapper{},
+	}
+}
+
+// DispatchDue delivers pending notifications scheduled at or before now. Each
+// is marked sent in the same transaction that delivers it, so a failed
+// delivery leaves it pending for the next call.
+func (s *ReminderService) DispatchDue(ctx context.Context, now time.Time) (DispatchResult, error) {
+	var result DispatchResult
+
+	var due []dtos.Notification
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", dtos.NotificationPending, now.UTC()).
+		Order("scheduled_for ASC, id ASC").
+		Limit(DefaultBatchSize).
+		Find(&due).Error; err != nil {
+		return result, err
+	}
+
+	for i := range due {
+		status, err := s.dispatch(ctx, &due[i], now)
+		switch {
+		case errors.Is(err, errNotificationClaimed):
+			continue
+		case err != nil:
+			result.Failed++
+			if err := s.recordFailure(ctx, &due[i], err); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		switch status {
+		case dtos.NotificationSent:
+			result.Sent++
+		case dtos.NotificationSkipped:
+			result.Skipped++
+		case dtos.NotificationCancelled:
+			result.Cancelled++
+		}
+	}
+
+	return result, nil
+}
+
+// dispatch claims a notification and delivers it, returning the status it
+// was left in. Reminders for tasks that are gone, trashed, no longer pending
+// or rescheduled are cancelled instead.
+func (s *ReminderService) dispatch(ctx context.Context, notification *dtos.Notification, now time.Time) (string, error) {
+	var status string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the dispatcher whose update wins delivers the notification
+		sentAt := now.UTC()
+		claim := tx.Model(&dtos.Notification{}).
+			Where("id = ? AND status = ?", notification.ID, dtos.NotificationPending).
+			Updates(map[string]interface{}{"status": dtos.NotificationSent, "sent_at": sentAt})
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return errNotificationClaimed
+		}
+
+		reminder, ok, err := s.loadReminder(tx, notification)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			status = dtos.NotificationCancelled
+			return setStatus(tx, notification.ID, status)
+		}
+
+		notifier, ok := s.notifiers[notification.Channel]
+		if !ok {
+			status = dtos.NotificationSkipped
+			return setStatus(tx, notification.ID, status)
+		}
+
+		err = notifier.Notify(ctx, reminder)
+		if errors.Is(err, ErrNotificationSkipped) {
+			status = dtos.NotificationSkipped
+			return setStatus(tx, notification.ID, status)
+		}
+		if err != nil {
+			return err
+		}
+
+		status = dtos.NotificationSent
+		return nil
+	})
+	return status, err
+}
+
+// loadReminder builds the reminder for a notification, reporting false if its
+// task should no longer be reminded about at the scheduled time
+func (s *ReminderService) loadReminder(tx *gorm.DB, notification *dtos.Notification) (Reminder, bool, error) {
+	var dto dtos.Task
+	err := tx.Unscoped().First(&dto, notification.TaskID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Reminder{}, false, nil
+	}
+	if err != nil {
+		return Reminder{}, false, err
+	}
+
+	task, err := s.mapper.ToEntity(&dto)
+	if err != nil {
+		return Reminder{}, false, err
+	}
+	remindAt := task.RemindAt()
+	if task.DeletedAt() != nil || !task.Status().IsPending() || remindAt == nil || !remindAt.Equal(notification.ScheduledFor) {
+		return Reminder{}, false, nil
+	}
+
+	return Reminder{
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		TaskID:         notification.TaskID,
+		TaskTitle:      task.Title().Value(),
+		DueDate:        task.DueDate(),
+		ScheduledFor:   notification.ScheduledFor,
+	}, true, nil
+}
+
+// setStatus records that a claimed notification was not sent after all
+func setStatus(tx *gorm.DB, id uint, status string) error {
+	return tx.Model(&dtos.Notification{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "sent_at": nil}).Error
+}
+
+// recordFailure counts a failed delivery, giving the notification up once it
+// has failed DefaultMaxAttempts times
+func (s *ReminderService) recordFailure(ctx context.Context, notification *dtos.Notification, cause error) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": cause.Error(),
+	}
+	if notification.Attempts+1 >= DefaultMaxAttempts {
+		updates["status"] = dtos.NotificationFailed
+	}
+
+	err := s.db.WithContext(ctx).Model(&dtos.Notification{}).
+		Where("id = ? AND status = ?", notification.ID, dtos.NotificationPending).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to record failed notification %d: %w", notification.ID, err)
+	}
+	return nil
+}
+
+// ListRecent returns a user's notifications, most recently scheduled first;
+// a limit of 0 means no limit
+func (s *ReminderService) ListRecent(ctx context.Context, userID uint, limit int) ([]dtos.Notification, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("scheduled_for DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var notifications []dtos.Notification
+	if err := query.Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
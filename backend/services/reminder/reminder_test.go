@@ -0,0 +1,257 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"domain/task/valueobjects"
+	"domain/user/entities"
+	userrepos "domain/user/repositories"
+	uservo "domain/user/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+)
+
+// reminderTestTime is when reminders in these tests are scheduled
+var reminderTestTime = time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+
+// recordingNotifier records the reminders it is given and fails with err
+type recordingNotifier struct {
+	reminders []Reminder
+	err       error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.reminders = append(n.reminders, reminder)
+	return nil
+}
+
+func setupReminderTest(t *testing.T) (*gorm.DB, *recordingNotifier, *recordingNotifier, *ReminderService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.Task{}, &dtos.Tag{}, &dtos.Notification{}))
+
+	logNotifier, emailNotifier := &recordingNotifier{}, &recordingNotifier{}
+	service := NewReminderService(db, map[string]Notifier{
+		dtos.NotificationChannelLog:   logNotifier,
+		dtos.NotificationChannelEmail: emailNotifier,
+	})
+	return db, logNotifier, emailNotifier, service
+}
+
+// createRemindedTask stores a pending task with a reminder at remindAt and a
+// pending notification for each reminder channel
+func createRemindedTask(t *testing.T, db *gorm.DB, remindAt time.Time) dtos.Task {
+	task := dtos.Task{Title: "Call the plumber", Status: "pending", UserID: 1, RemindAt: &remindAt}
+	require.NoError(t, db.Create(&task).Error)
+	for _, channel := range dtos.ReminderChannels {
+		notification := dtos.Notification{UserID: 1, TaskID: task.ID, Channel: channel, ScheduledFor: remindAt, Status: dtos.NotificationPending}
+		require.NoError(t, db.Create(&notification).Error)
+	}
+	return task
+}
+
+func notificationsFor(t *testing.T, db *gorm.DB, taskID uint) map[string]dtos.Notification {
+	var notifications []dtos.Notification
+	require.NoError(t, db.Where("task_id = ?", taskID).Find(&notifications).Error)
+	byChannel := make(map[string]dtos.Notification, len(notifications))
+	for _, notification := range notifications {
+		byChannel[notification.Channel] = notification
+	}
+	return byChannel
+}
+
+func TestDispatchDue_DeliversDueRemindersOnce(t *testing.T) {
+	db, logNotifier, emailNotifier, service := setupReminderTest(t)
+	task := createRemindedTask(t, db, reminderTestTime)
+	ctx := context.Background()
+
+	// Not yet due
+	result, err := service.DispatchDue(ctx, reminderTestTime.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{}, result)
+	assert.Empty(t, logNotifier.reminders)
+
+	sentAt := reminderTestTime.Add(30 * time.Second)
+	result, err = service.DispatchDue(ctx, sentAt)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Sent: 2}, result)
+	require.Len(t, logNotifier.reminders, 1)
+	require.Len(t, emailNotifier.reminders, 1)
+	assert.Equal(t, task.ID, logNotifier.reminders[0].TaskID)
+	assert.Equal(t, uint(1), logNotifier.reminders[0].UserID)
+	assert.Equal(t, "Call the plumber", logNotifier.reminders[0].TaskTitle)
+
+	for channel, notification := range notificationsFor(t, db, task.ID) {
+		assert.Equal(t, dtos.NotificationSent, notification.Status, channel)
+		require.NotNil(t, notification.SentAt, channel)
+		assert.True(t, notification.SentAt.Equal(sentAt), channel)
+	}
+
+	// Later polls do not deliver it again
+	result, err = service.DispatchDue(ctx, sentAt.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{}, result)
+	assert.Len(t, logNotifier.reminders, 1)
+	assert.Len(t, emailNotifier.reminders, 1)
+}
+
+func TestDispatchDue_FailedDeliveryIsRetried(t *testing.T) {
+	db, logNotifier, emailNotifier, service := setupReminderTest(t)
+	task := createRemindedTask(t, db, reminderTestTime)
+	ctx := context.Background()
+
+	emailNotifier.err = errors.New("mail server unavailable")
+	result, err := service.DispatchDue(ctx, reminderTestTime)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Sent: 1, Failed: 1}, result)
+
+	// The failed delivery is rolled back, not marked sent
+	email := notificationsFor(t, db, task.ID)[dtos.NotificationChannelEmail]
+	assert.Equal(t, dtos.NotificationPending, email.Status)
+	assert.Nil(t, email.SentAt)
+	assert.Equal(t, 1, email.Attempts)
+	assert.Equal(t, "mail server unavailable", email.LastError)
+
+	emailNotifier.err = nil
+	result, err = service.DispatchDue(ctx, reminderTestTime.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Sent: 1}, result)
+	assert.Len(t, logNotifier.reminders, 1)
+	assert.Len(t, emailNotifier.reminders, 1)
+	assert.Equal(t, dtos.NotificationSent, notificationsFor(t, db, task.ID)[dtos.NotificationChannelEmail].Status)
+}
+
+func TestDispatchDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	db, _, emailNotifier, service := setupReminderTest(t)
+	task := createRemindedTask(t, db, reminderTestTime)
+	emailNotifier.err = errors.New("mail server unavailable")
+
+	now := reminderTestTime
+	for i := 0; i < DefaultMaxAttempts; i++ {
+		_, err := service.DispatchDue(context.Background(), now)
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+	}
+
+	email := notificationsFor(t, db, task.ID)[dtos.NotificationChannelEmail]
+	assert.Equal(t, dtos.NotificationFailed, email.Status)
+	assert.Equal(t, DefaultMaxAttempts, email.Attempts)
+
+	result, err := service.DispatchDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{}, result)
+}
+
+func TestDispatchDue_SkippedNotification(t *testing.T) {
+	db, _, emailNotifier, service := setupReminderTest(t)
+	task := createRemindedTask(t, db, reminderTestTime)
+	emailNotifier.err = ErrNotificationSkipped
+
+	result, err := service.DispatchDue(context.Background(), reminderTestTime)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Sent: 1, Skipped: 1}, result)
+
+	email := notificationsFor(t, db, task.ID)[dtos.NotificationChannelEmail]
+	assert.Equal(t, dtos.NotificationSkipped, email.Status)
+	assert.Nil(t, email.SentAt)
+}
+
+func TestDispatchDue_CancelsRemindersNoLongerWanted(t *testing.T) {
+	db, logNotifier, emailNotifier, service := setupReminderTest(t)
+
+	completed := createRemindedTask(t, db, reminderTestTime)
+	require.NoError(t, db.Model(&completed).Updates(map[string]interface{}{"status": "completed", "completed": true}).Error)
+	trashed := createRemindedTask(t, db, reminderTestTime)
+	require.NoError(t, db.Delete(&trashed).Error)
+	purged := createRemindedTask(t, db, reminderTestTime)
+	require.NoError(t, db.Unscoped().Delete(&purged).Error)
+	// The task's reminder moved, but this notification was left behind
+	moved := createRemindedTask(t, db, reminderTestTime)
+	later := reminderTestTime.Add(time.Hour)
+	require.NoError(t, db.Model(&moved).Update("remind_at", later).Error)
+
+	result, err := service.DispatchDue(context.Background(), reminderTestTime)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Cancelled: 8}, result)
+	assert.Empty(t, logNotifier.reminders)
+	assert.Empty(t, emailNotifier.reminders)
+
+	for _, taskID := range []uint{completed.ID, trashed.ID, purged.ID, moved.ID} {
+		for channel, notification := range notificationsFor(t, db, taskID) {
+			assert.Equal(t, dtos.NotificationCancelled, notification.Status, "task %d %s", taskID, channel)
+			assert.Nil(t, notification.SentAt)
+		}
+	}
+}
+
+func TestListRecent_ReturnsUsersNotificationsNewestFirst(t *testing.T) {
+	db, _, _, service := setupReminderTest(t)
+	first := createRemindedTask(t, db, reminderTestTime)
+	second := createRemindedTask(t, db, reminderTestTime.Add(time.Hour))
+	other := dtos.Notification{UserID: 2, TaskID: 99, Channel: dtos.NotificationChannelLog, ScheduledFor: reminderTestTime, Status: dtos.NotificationPending}
+	require.NoError(t, db.Create(&other).Error)
+
+	notifications, err := service.ListRecent(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 4)
+	assert.Equal(t, second.ID, notifications[0].TaskID)
+	assert.Equal(t, first.ID, notifications[3].TaskID)
+
+	notifications, err = service.ListRecent(context.Background(), 1, 1)
+	require.NoError(t, err)
+	assert.Len(t, notifications, 1)
+}
+
+// singleUserRepository finds one user by ID
+type singleUserRepository struct {
+	userrepos.UserRepository
+	user *entities.User
+}
+
+func (r *singleUserRepository) FindByID(ctx context.Context, id uservo.UserID) (*entities.User, error) {
+	if r.user == nil || !r.user.ID().Equals(id) {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+func newReminderTestUser(t *testing.T, emailNotifications bool) *entities.User {
+	email, err := uservo.NewEmail("user@example.com")
+	require.NoError(t, err)
+	profile, err := uservo.NewUserProfile("Test", "User", "UTC")
+	require.NoError(t, err)
+	preferences, err := uservo.NewUserPreferences(valueobjects.NewMediumPriority(), emailNotifications, uservo.ThemeAuto)
+	require.NoError(t, err)
+	user, err := entities.NewUser(uservo.NewUserID(1), email, profile, preferences)
+	require.NoError(t, err)
+	return user
+}
+
+func TestEmailNotifier_HonoursEmailNotificationsPreference(t *testing.T) {
+	reminder := Reminder{UserID: 1, TaskID: 7, TaskTitle: "Call the plumber", ScheduledFor: reminderTestTime}
+
+	notifier := NewEmailNotifier(&singleUserRepository{user: newReminderTestUser(t, true)})
+	assert.NoError(t, notifier.Notify(context.Background(), reminder))
+
+	notifier = NewEmailNotifier(&singleUserRepository{user: newReminderTestUser(t, false)})
+	assert.ErrorIs(t, notifier.Notify(context.Background(), reminder), ErrNotificationSkipped)
+
+	// The user is gone
+	notifier = NewEmailNotifier(&singleUserRepository{})
+	assert.ErrorIs(t, notifier.Notify(context.Background(), reminder), ErrNotificationSkipped)
+}
@@ -0,0 +1,214 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	authentities "domain/auth/entities"
+	uservo "domain/user/valueobjects"
+	"gorm.io/gorm"
+	"todo-app/internal/dtos"
+	"todo-app/utils"
+)
+
+// ErrInvalidCredentials is returned when an email and password do not match an account
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// ErrPasswordNotSet is returned for password sign-ins and changes on an account
+// that only signs in with an OAuth provider
+var ErrPasswordNotSet = errors.New("account has no password; sign in with its OAuth provider")
+
+// ErrEmailAlreadyRegistered is returned when registering an email that already has an account
+var ErrEmailAlreadyRegistered = errors.New("email is already registered")
+
+// ErrInvalidResetToken is returned for password reset tokens that are unknown, expired or already used
+var ErrInvalidResetToken = errors.New("password reset token is invalid or has expired")
+
+// ErrWeakPassword is wrapped by the errors returned for passwords that fail
+// the strength rules of uservo.ValidatePassword
+var ErrWeakPassword = uservo.ErrWeakPassword
+
+// dummyPasswordHash is compared against when there is no hash to check, so a
+// sign-in for an unknown email takes as long as one with a wrong password
+var dummyPasswordHash, _ = utils.HashPassword("dummy password for timing")
+
+// RegisterWithPassword creates a password account and issues the token that
+// verifies its email, which the caller delivers
+func (s *UserService) RegisterWithPassword(email, password, name string) (*dtos.User, string, error) {
+	if err := uservo.ValidatePassword(password); err != nil {
+		return nil, "", err
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := uservo.NewVerificationToken(time.Now(), s.verificationTokenTTL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tokenValue, expiresAt := token.Value(), token.ExpiresAt()
+	user := dtos.User{
+		Email:                      email,
+		Name:                       strings.TrimSpace(name),
+		PasswordHash:               passwordHash,
+		AuthMethod:                 "password",
+		IsActive:                   true,
+		VerificationToken:          &tokenValue,
+		VerificationTokenExpiresAt: &expiresAt,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&dtos.User{}).Where("email = ?", uservo.NormalizeEmail(email)).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrEmailAlreadyRegistered
+		}
+		return tx.Create(&user).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &user, tokenValue, nil
+}
+
+// AuthenticatePassword returns the user with the given email and password. The
+// password is hashed even when there is no account to check it against, so the
+// response time does not reveal which emails are registered.
+func (s *UserService) AuthenticatePassword(email, password string) (*dtos.User, error) {
+	user, err := s.GetUserByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hash := dummyPasswordHash
+	if user != nil && user.IsTraditionalUser() {
+		hash = user.PasswordHash
+	}
+	matches := utils.VerifyPassword(password, hash)
+
+	switch {
+	case user == nil:
+		return nil, ErrInvalidCredentials
+	case !user.IsTraditionalUser():
+		return nil, ErrPasswordNotSet
+	case !matches:
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one
+func (s *UserService) ChangePassword(userID uint, currentPassword, newPassword string) (*dtos.User, error) {
+	var user dtos.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if !user.IsTraditionalUser() {
+		return nil, ErrPasswordNotSet
+	}
+	if !utils.VerifyPassword(currentPassword, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+	if err := uservo.ValidatePassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = passwordHash
+	if err := s.db.Model(&user).Update("password_hash", passwordHash).Error; err != nil {
+		return nil, err
+	}
+	s.auditService.Record(authentities.NewAuditLog(authentities.AuditEventPasswordChanged, user.ID, "", "", nil))
+
+	return &user, nil
+}
+
+// RequestPasswordReset issues a password reset token for the account with the
+// given email, which the caller delivers. It returns a nil user, and no error,
+// when there is no password account to reset, so callers can respond the same
+// way whether or not the email is registered.
+func (s *UserService) RequestPasswordReset(email string) (*dtos.User, string, error) {
+	user, err := s.GetUserByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	// Accounts that sign in with an OAuth provider, or were deactivated, get no token
+	if !user.IsTraditionalUser() || (!user.IsActive && !user.IsPendingDeletion()) {
+		return nil, "", nil
+	}
+
+	token, raw := authentities.NewPasswordResetToken(user.ID)
+	if err := s.db.Create(token).Error; err != nil {
+		return nil, "", err
+	}
+	return user, raw, nil
+}
+
+// ResetPassword sets a new password with a token from RequestPasswordReset. The
+// token is spent, along with any others issued to the user, and all of the
+// user's sessions are terminated.
+func (s *UserService) ResetPassword(rawToken, newPassword string) (*dtos.User, error) {
+	if err := uservo.ValidatePassword(newPassword); err != nil {
+		return nil, err
+	}
+	passwordHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	var user dtos.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var token authentities.PasswordResetToken
+		err := tx.Where("token_hash = ?", authentities.HashPasswordResetToken(rawToken)).First(&token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+		if token.IsUsed() || token.IsExpired() {
+			return ErrInvalidResetToken
+		}
+
+		// Claiming the token conditionally keeps two concurrent resets from both using it
+		now := time.Now()
+		result := tx.Model(&authentities.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		if err := tx.First(&user, token.UserID).Error; err != nil {
+			return err
+		}
+		user.PasswordHash = passwordHash
+		if err := tx.Model(&user).Update("password_hash", passwordHash).Error; err != nil {
+			return err
+		}
+
+		// Whoever may have taken over the account is signed out everywhere
+		return tx.Where("user_id = ?", user.ID).Delete(&authentities.AuthenticationSession{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(authentities.NewAuditLog(authentities.AuditEventPasswordChanged, user.ID, "", "",
+		authentities.AuditMetadata{"method": "reset"}))
+
+	return &user, nil
+}
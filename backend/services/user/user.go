@@ -2,24 +2,105 @@ package user
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	authentities "domain/auth/entities"
+	authvo "domain/auth/valueobjects"
+	"domain/task/repositories"
+	uservo "domain/user/valueobjects"
 	"gorm.io/gorm"
+	"todo-app/application/mappers"
+	"todo-app/infrastructure/persistence"
+	"todo-app/internal/config"
 	"todo-app/internal/dtos"
+	"todo-app/services/audit"
 )
 
+// ErrOAuthProviderConflict is returned when an OAuth sign-in matches a user by email
+// who is already linked to a different account at the same provider
+var ErrOAuthProviderConflict = errors.New("user is already linked to another account at this OAuth provider")
+
+// ErrAccountAlreadyLinked is returned when an OAuth sign-in matches a user by email
+// who is already linked to a different Google account
+var ErrAccountAlreadyLinked = errors.New("email is already linked to a different Google account")
+
+// ErrEmailNotVerified is returned when an OAuth sign-in would be linked to an
+// existing account by an email the provider has not verified
+var ErrEmailNotVerified = errors.New("email is not verified by the OAuth provider")
+
+// ErrLinkConfirmationRequired is returned, wrapped in a LinkConfirmationRequiredError,
+// when an OAuth sign-in matches an existing account by email and automatic linking is disabled
+var ErrLinkConfirmationRequired = errors.New("account link must be confirmed by the existing account")
+
+// ErrPendingLinkNotFound is returned when a pending link does not exist, has
+// expired, or belongs to another user
+var ErrPendingLinkNotFound = errors.New("pending account link not found")
+
+// ErrOAuthIdentityInUse is returned when confirming a link to a provider account
+// that has since been linked to another user
+var ErrOAuthIdentityInUse = errors.New("OAuth account is already linked to another user")
+
+// ErrGoogleAccountNotLinked is returned when unlinking a Google account from a
+// user who has none linked
+var ErrGoogleAccountNotLinked = errors.New("user does not have a Google account linked")
+
+// ErrNoAlternativeLoginMethod is returned when unlinking a Google account from a
+// user without a password, which would lock them out
+var ErrNoAlternativeLoginMethod = errors.New("no alternative login method: set a password before unlinking your Google account")
+
+// LinkConfirmationRequiredError carries the pending link the existing account must confirm
+type LinkConfirmationRequiredError struct {
+	Link *authentities.PendingAccountLink
+}
+
+func (e *LinkConfirmationRequiredError) Error() string {
+	return ErrLinkConfirmationRequired.Error()
+}
+
+func (e *LinkConfirmationRequiredError) Unwrap() error {
+	return ErrLinkConfirmationRequired
+}
+
 // UserService handles user-related operations
 type UserService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	auditService *audit.AuditService
+
+	// autoLink links OAuth sign-ins to existing accounts with the same verified
+	// email; when off, the link waits for the account to confirm it
+	autoLink bool
+
+	// verificationTokenTTL is how long email verification tokens stay valid
+	verificationTokenTTL time.Duration
 }
 
-// NewUserService creates a new user service
+// NewUserService creates a new user service. OAuth sign-ins are linked to
+// existing accounts automatically unless SetOAuthAutoLink turns it off.
 func NewUserService(db *gorm.DB) *UserService {
 	return &UserService{
-		db: db,
+		db:                   db,
+		autoLink:             true,
+		verificationTokenTTL: config.DefaultEmailVerificationTokenTTL,
 	}
 }
 
+// SetOAuthAutoLink sets whether OAuth sign-ins are linked automatically to an
+// existing account with the same verified email (config.OAuthConfig.AutoLink)
+func (s *UserService) SetOAuthAutoLink(enabled bool) {
+	s.autoLink = enabled
+}
+
+// SetVerificationTokenTTL sets how long email verification tokens stay valid
+func (s *UserService) SetVerificationTokenTTL(ttl time.Duration) {
+	s.verificationTokenTTL = ttl
+}
+
+// SetAuditService enables audit logging of Google account linking
+func (s *UserService) SetAuditService(auditService *audit.AuditService) {
+	s.auditService = auditService
+}
+
 // GetUserByID retrieves a user by ID
 func (s *UserService) GetUserByID(userID uint) (*dtos.User, error) {
 	var user dtos.User
@@ -36,7 +117,7 @@ func (s *UserService) GetUserByID(userID uint) (*dtos.User, error) {
 func (s *UserService) GetUserByEmail(email string) (*dtos.User, error) {
 	var user dtos.User
 
-	result := s.db.Where("email = ?", email).First(&user)
+	result := s.db.Where("email = ?", uservo.NormalizeEmail(email)).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -57,24 +138,79 @@ func (s *UserService) GetUserByGoogleID(googleID string) (*dtos.User, error) {
 }
 
 // CreateOAuthUser creates a new user from OAuth information
-func (s *UserService) CreateOAuthUser(email, name, googleID string) (*dtos.User, error) {
-	now := time.Now()
+func (s *UserService) CreateOAuthUser(provider, externalID, email, name string) (*dtos.User, error) {
 	user := dtos.User{
-		Email:          email,
-		Name:           name,
-		GoogleID:       googleID,
-		OAuthProvider:  "google",
-		OAuthCreatedAt: &now,
-		IsActive:       true,
+		Email:      email,
+		Name:       name,
+		AuthMethod: provider,
+		IsActive:   true,
+	}
+	if err := user.LinkOAuthAccount(provider, externalID, time.Now()); err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Create(&user).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&dtos.OAuthIdentity{UserID: user.ID, Provider: provider, ExternalID: externalID}).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
 
+// GetUserByOAuthIdentity retrieves a user by OAuth provider and provider account ID.
+// Google users linked before oauth_external_id existed are matched on google_id.
+func (s *UserService) GetUserByOAuthIdentity(provider, externalID string) (*dtos.User, error) {
+	var user dtos.User
+
+	result := oauthIdentityQuery(s.db, provider, externalID).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// oauthIdentityQuery matches the user linked to a provider account, whether the
+// link is an oauth_identities row or, for accounts linked before that table
+// existed, the user's own oauth_provider/oauth_external_id or google_id columns
+func oauthIdentityQuery(db *gorm.DB, provider, externalID string) *gorm.DB {
+	linked := db.Session(&gorm.Session{NewDB: true}).
+		Model(&dtos.OAuthIdentity{}).
+		Select("user_id").
+		Where("provider = ? AND external_id = ?", provider, externalID)
+
+	query := db.Where("id IN (?)", linked).
+		Or("oauth_provider = ? AND oauth_external_id = ?", provider, externalID)
+	if provider == dtos.OAuthProviderGoogle {
+		query = query.Or("google_id = ?", externalID)
+	}
+	return query
+}
+
+// linkedAccountID returns the ID of the account user has linked at provider, or "" if none
+func linkedAccountID(db *gorm.DB, user *dtos.User, provider string) (string, error) {
+	if provider == dtos.OAuthProviderGoogle && user.GoogleID != "" {
+		return user.GoogleID, nil
+	}
+	if user.OAuthProvider == provider && user.OAuthExternalID != "" {
+		return user.OAuthExternalID, nil
+	}
+
+	var identities []dtos.OAuthIdentity
+	if err := db.Where("user_id = ? AND provider = ?", user.ID, provider).Limit(1).Find(&identities).Error; err != nil {
+		return "", err
+	}
+	if len(identities) == 0 {
+		return "", nil
+	}
+	return identities[0].ExternalID, nil
+}
+
 // LinkGoogleAccount links a Google account to an existing user
 func (s *UserService) LinkGoogleAccount(userID uint, googleID string) (*dtos.User, error) {
 	var user dtos.User
@@ -86,13 +222,17 @@ func (s *UserService) LinkGoogleAccount(userID uint, googleID string) (*dtos.Use
 	}
 
 	// Check if user already has Google linked
-	if user.GoogleID != "" {
+	linked, err := linkedAccountID(s.db, &user, dtos.OAuthProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	if linked != "" {
 		return nil, errors.New("user already has a Google account linked")
 	}
 
 	// Check if this Google ID is already used by another user
 	var existingUser dtos.User
-	result = s.db.Where("google_id = ?", googleID).First(&existingUser)
+	result = oauthIdentityQuery(s.db, dtos.OAuthProviderGoogle, googleID).First(&existingUser)
 	if result.Error == nil {
 		return nil, errors.New("this Google account is already linked to another user")
 	}
@@ -101,94 +241,241 @@ func (s *UserService) LinkGoogleAccount(userID uint, googleID string) (*dtos.Use
 	}
 
 	// Link the account
-	now := time.Now()
-	if err := user.LinkGoogleAccount(googleID, now); err != nil {
-		return nil, err
-	}
-
-	// Save changes
-	if err := s.db.Save(&user).Error; err != nil {
+	if err := s.linkOAuthAccount(s.db, &user, dtos.OAuthProviderGoogle, googleID); err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
 
-// UnlinkGoogleAccount removes Google OAuth linking from a user
-func (s *UserService) UnlinkGoogleAccount(userID uint) (*dtos.User, error) {
+// UnlinkGoogleAccount removes the Google account linked to a user and clears the
+// Google tokens stored on the user's sessions, returning them so the caller can
+// revoke them with Google. A user with neither a password nor another linked
+// provider cannot unlink, since they would have no way left to sign in.
+func (s *UserService) UnlinkGoogleAccount(userID uint) (*dtos.User, []string, error) {
 	var user dtos.User
+	var tokens []string
 
-	// Find the user
-	result := s.db.Where("id = ?", userID).First(&user)
-	if result.Error != nil {
-		return nil, result.Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+
+		// Accounts created through the Google sign-in flow are linked by a google_identities row
+		linked, err := linkedAccountID(tx, &user, dtos.OAuthProviderGoogle)
+		if err != nil {
+			return err
+		}
+		var identities int64
+		if err := tx.Model(&authvo.GoogleIdentity{}).Where("user_id = ?", userID).Count(&identities).Error; err != nil {
+			return err
+		}
+		if linked == "" && identities == 0 {
+			return ErrGoogleAccountNotLinked
+		}
+
+		// Another linked provider is as good a way to sign in as a password
+		var others []dtos.OAuthIdentity
+		if err := tx.Where("user_id = ? AND provider <> ?", userID, dtos.OAuthProviderGoogle).
+			Order("created_at, id").Find(&others).Error; err != nil {
+			return err
+		}
+		if !user.IsTraditionalUser() && len(others) == 0 {
+			return ErrNoAlternativeLoginMethod
+		}
+
+		if user.GoogleID != "" || user.OAuthProvider == dtos.OAuthProviderGoogle {
+			// The user row keeps the next linked provider, if any
+			if len(others) > 0 {
+				err = user.LinkOAuthAccount(others[0].Provider, others[0].ExternalID, others[0].CreatedAt)
+			} else {
+				err = user.UnlinkGoogleAccount()
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Save(&user).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&authvo.GoogleIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND provider = ?", userID, dtos.OAuthProviderGoogle).Delete(&dtos.OAuthIdentity{}).Error; err != nil {
+			return err
+		}
+
+		var sessions []authentities.AuthenticationSession
+		if err := tx.Where("user_id = ? AND (access_token <> '' OR refresh_token <> '')", userID).Find(&sessions).Error; err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			// Revoking a refresh token also revokes the access tokens issued from it
+			if session.RefreshToken != "" {
+				tokens = append(tokens, session.RefreshToken)
+			} else {
+				tokens = append(tokens, session.AccessToken)
+			}
+		}
+
+		return tx.Model(&authentities.AuthenticationSession{}).
+			Where("user_id = ?", userID).
+			UpdateColumns(map[string]interface{}{
+				"access_token":     "",
+				"refresh_token":    "",
+				"token_expires_at": nil,
+			}).Error
+	})
+	if err != nil {
+		return nil, nil, err
 	}
+	s.auditService.Record(authentities.NewAuditLog(authentities.AuditEventAccountUnlinked, user.ID, "", "",
+		authentities.AuditMetadata{"provider": dtos.OAuthProviderGoogle}))
 
-	// Check if user has Google linked
-	if user.GoogleID == "" {
-		return nil, errors.New("user does not have a Google account linked")
+	return &user, tokens, nil
+}
+
+// FindOrCreateOAuthUser finds an existing user or creates a new one from OAuth data,
+// keyed on provider and provider account ID. See FindOrLinkOAuthUser for how a
+// user with a matching email is handled.
+func (s *UserService) FindOrCreateOAuthUser(provider, externalID, email string, emailVerified bool, name string) (*dtos.User, bool, error) {
+	user, err := s.FindOrLinkOAuthUser(provider, externalID, email, emailVerified)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
 	}
 
-	// Unlink the account
-	if err := user.UnlinkGoogleAccount(); err != nil {
-		return nil, err
+	// Create new user
+	newUser, err := s.CreateOAuthUser(provider, externalID, email, name)
+	if err != nil {
+		return nil, false, err
 	}
 
-	// Save changes
-	if err := s.db.Save(&user).Error; err != nil {
+	return newUser, true, nil
+}
+
+// FindOrLinkOAuthUser finds the user signing in with an OAuth account, returning
+// gorm.ErrRecordNotFound if there is none. A user with a matching email is linked
+// if the provider verified the email and automatic linking is enabled; otherwise
+// a pending link is recorded and a *LinkConfirmationRequiredError returned.
+func (s *UserService) FindOrLinkOAuthUser(provider, externalID, email string, emailVerified bool) (*dtos.User, error) {
+	// Try to find user by OAuth identity
+	user, err := s.GetUserByOAuthIdentity(provider, externalID)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+
+	// Try to find user by email (for account linking)
+	user, err = s.GetUserByEmail(email)
+	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
-}
+	// Only one account per provider can be linked; accounts at other providers may be added
+	linked, err := linkedAccountID(s.db, user, provider)
+	if err != nil {
+		return nil, err
+	}
+	if linked != "" {
+		if provider == dtos.OAuthProviderGoogle {
+			return nil, ErrAccountAlreadyLinked
+		}
+		return nil, ErrOAuthProviderConflict
+	}
 
-// FindOrCreateOAuthUser finds an existing user or creates a new one from OAuth data
-// This implements automatic account linking based on email
-func (s *UserService) FindOrCreateOAuthUser(email, name, googleID string) (*dtos.User, bool, error) {
-	var user dtos.User
-	isNewUser := false
+	// An unverified email says nothing about who owns the account
+	if !emailVerified {
+		return nil, ErrEmailNotVerified
+	}
 
-	// Try to find user by Google ID
-	result := s.db.Where("google_id = ?", googleID).First(&user)
-	if result.Error == nil {
-		// User exists with this Google ID
-		return &user, false, nil
+	if !s.autoLink {
+		link := authentities.NewPendingAccountLink(user.ID, provider, externalID, user.Email)
+		if err := s.db.Create(link).Error; err != nil {
+			return nil, err
+		}
+		return nil, &LinkConfirmationRequiredError{Link: link}
 	}
 
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, false, result.Error
+	if err := s.linkOAuthAccount(s.db, user, provider, externalID); err != nil {
+		return nil, err
 	}
 
-	// Try to find user by email (for automatic account linking)
-	result = s.db.Where("email = ?", email).First(&user)
-	if result.Error == nil {
-		// User exists with this email - link Google account automatically
-		now := time.Now()
-		err := user.LinkGoogleAccount(googleID, now)
+	return user, nil
+}
+
+// ConfirmPendingLink completes a pending link for the signed-in user, who must
+// own the account the link was recorded for
+func (s *UserService) ConfirmPendingLink(userID uint, provider, token string) (*dtos.User, error) {
+	var user dtos.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var link authentities.PendingAccountLink
+		err := tx.Where("token = ? AND user_id = ? AND provider = ?", token, userID, provider).First(&link).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPendingLinkNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if link.IsExpired() {
+			return ErrPendingLinkNotFound
+		}
+
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		linked, err := linkedAccountID(tx, &user, provider)
 		if err != nil {
-			return nil, false, err
+			return err
+		}
+		if linked != "" {
+			return ErrOAuthProviderConflict
+		}
+
+		// The provider account may have signed up or been linked elsewhere meanwhile
+		var owner dtos.User
+		err = oauthIdentityQuery(tx, provider, link.ExternalID).First(&owner).Error
+		if err == nil {
+			return ErrOAuthIdentityInUse
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
 		}
 
-		// Save the linked account
-		if err := s.db.Save(&user).Error; err != nil {
-			return nil, false, err
+		if err := s.linkOAuthAccount(tx, &user, provider, link.ExternalID); err != nil {
+			return err
 		}
 
-		return &user, false, nil
+		// The link is made; any other pending links for the account are moot
+		return tx.Where("user_id = ?", userID).Delete(&authentities.PendingAccountLink{}).Error
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, false, result.Error
+	return &user, nil
+}
+
+// linkOAuthAccount links the provider account to user and records it in the audit log
+func (s *UserService) linkOAuthAccount(db *gorm.DB, user *dtos.User, provider, externalID string) error {
+	// The first provider linked is also kept on the user row for backward compatibility
+	if !user.IsOAuthUser() {
+		if err := user.LinkOAuthAccount(provider, externalID, time.Now()); err != nil {
+			return err
+		}
+		if err := db.Save(user).Error; err != nil {
+			return err
+		}
 	}
 
-	// Create new user
-	newUser, err := s.CreateOAuthUser(email, name, googleID)
-	if err != nil {
-		return nil, false, err
+	if err := db.Create(&dtos.OAuthIdentity{UserID: user.ID, Provider: provider, ExternalID: externalID}).Error; err != nil {
+		return err
 	}
 
-	isNewUser = true
-	return newUser, isNewUser, nil
+	s.auditService.Record(authentities.NewAuditLog(authentities.AuditEventAccountLinked, user.ID, "", "",
+		authentities.AuditMetadata{"provider": provider}))
+	return nil
 }
 
 // UpdateUserProfile updates a user's profile information
@@ -214,18 +501,97 @@ func (s *UserService) UpdateUserProfile(userID uint, name string) (*dtos.User, e
 	return &user, nil
 }
 
-// DeactivateUser deactivates a user account
+// DeactivateUser deactivates a user account and terminates all of its sessions
 func (s *UserService) DeactivateUser(userID uint) (*dtos.User, error) {
 	var user dtos.User
 
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Find the user
+		if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+
+		// Deactivate
+		user.Deactivate()
+
+		// Save changes
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+
+		// Sign the user out everywhere
+		return tx.Where("user_id = ?", userID).Delete(&authentities.AuthenticationSession{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteUser permanently removes a user account along with its tasks,
+// sessions, login history and linked Google identity
+func (s *UserService) DeleteUser(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var user dtos.User
+		if err := tx.Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+
+		// Delete tasks, including trashed ones, through the repository so domain invariants apply
+		taskRepo := persistence.NewGormTaskRepository(tx, &mappers.TaskMapper{})
+		ctx := tx.Statement.Context
+		tasks, err := taskRepo.FindByUserID(ctx, uservo.NewUserID(userID), repositories.TaskSort{})
+		if err != nil {
+			return err
+		}
+		trashed, err := taskRepo.FindDeletedByUserID(ctx, uservo.NewUserID(userID))
+		if err != nil {
+			return err
+		}
+		for _, task := range append(tasks, trashed...) {
+			if err := taskRepo.DeletePermanently(ctx, task.ID()); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&dtos.TaskActivity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&dtos.Notification{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&authentities.AuthenticationSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&authentities.LoginEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&authvo.GoogleIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&dtos.OAuthIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&authentities.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&user).Error
+	})
+}
+
+// ScheduleDeletion deactivates a user account and schedules its permanent deletion
+func (s *UserService) ScheduleDeletion(userID uint, at time.Time) (*dtos.User, error) {
+	var user dtos.User
+
 	// Find the user
 	result := s.db.Where("id = ?", userID).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
-	// Deactivate
-	user.Deactivate()
+	user.ScheduleDeletion(at)
 
 	// Save changes
 	if err := s.db.Save(&user).Error; err != nil {
@@ -235,6 +601,52 @@ func (s *UserService) DeactivateUser(userID uint) (*dtos.User, error) {
 	return &user, nil
 }
 
+// CancelDeletion restores a user account that is pending deletion
+func (s *UserService) CancelDeletion(userID uint) (*dtos.User, error) {
+	var user dtos.User
+
+	// Find the user
+	result := s.db.Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := user.CancelDeletion(); err != nil {
+		return nil, err
+	}
+
+	// Save changes
+	if err := s.db.Save(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// PurgeScheduledDeletions permanently deletes accounts whose deletion was
+// scheduled at or before now, returning how many were removed
+func (s *UserService) PurgeScheduledDeletions(now time.Time) (int, error) {
+	var userIDs []uint
+	if err := s.db.Model(&dtos.User{}).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Pluck("id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	// Each account is removed in its own transaction so one failure does not block the rest
+	purged := 0
+	var errs []error
+	for _, userID := range userIDs {
+		if err := s.DeleteUser(userID); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
 // ActivateUser activates a user account
 func (s *UserService) ActivateUser(userID uint) (*dtos.User, error) {
 	var user dtos.User
@@ -258,16 +670,27 @@ func (s *UserService) ActivateUser(userID uint) (*dtos.User, error) {
 
 // ListUsers retrieves a list of users with pagination
 func (s *UserService) ListUsers(limit, offset int) ([]dtos.User, int64, error) {
+	return s.SearchUsers("", limit, offset)
+}
+
+// SearchUsers retrieves a page of users whose email contains emailPattern,
+// along with the total number of matches; an empty pattern matches every user
+func (s *UserService) SearchUsers(emailPattern string, limit, offset int) ([]dtos.User, int64, error) {
 	var users []dtos.User
 	var total int64
 
+	query := s.db.Model(&dtos.User{})
+	if emailPattern != "" {
+		query = query.Where("email LIKE ?", "%"+uservo.NormalizeEmail(emailPattern)+"%")
+	}
+
 	// Get total count
-	if err := s.db.Model(&dtos.User{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
-	result := s.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&users)
+	result := query.Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&users)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -279,7 +702,7 @@ func (s *UserService) ListUsers(limit, offset int) ([]dtos.User, int64, error) {
 func (s *UserService) SearchUsersByEmail(emailPattern string) ([]dtos.User, error) {
 	var users []dtos.User
 
-	result := s.db.Where("email LIKE ?", "%"+emailPattern+"%").
+	result := s.db.Where("email LIKE ?", "%"+uservo.NormalizeEmail(emailPattern)+"%").
 		Order("email").
 		Limit(50).
 		Find(&users)
@@ -295,7 +718,7 @@ func (s *UserService) SearchUsersByEmail(emailPattern string) ([]dtos.User, erro
 func (s *UserService) GetOAuthUsers() ([]dtos.User, error) {
 	var users []dtos.User
 
-	result := s.db.Where("google_id IS NOT NULL AND google_id != ''").
+	result := s.db.Where("(google_id IS NOT NULL AND google_id != '') OR (oauth_external_id IS NOT NULL AND oauth_external_id != '')").
 		Order("oauth_created_at DESC").
 		Find(&users)
 
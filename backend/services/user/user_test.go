@@ -0,0 +1,241 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	authentities "domain/auth/entities"
+	authvo "domain/auth/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"todo-app/internal/dtos"
+)
+
+func setupUserServiceTest(t *testing.T) (*gorm.DB, *UserService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&dtos.User{}, &dtos.OAuthIdentity{}, &authentities.PendingAccountLink{}))
+
+	return db, NewUserService(db)
+}
+
+func TestFindOrCreateOAuthUser_CreatesThenFindsByProviderIdentity(t *testing.T) {
+	_, service := setupUserServiceTest(t)
+
+	created, isNew, err := service.FindOrCreateOAuthUser("github", "583231", "octocat@example.com", true, "Octocat")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, "github", created.OAuthProvider)
+	assert.Equal(t, "583231", created.OAuthExternalID)
+	assert.Empty(t, created.GoogleID)
+
+	// The email changed at the provider, but the identity still matches
+	found, isNew, err := service.FindOrCreateOAuthUser("github", "583231", "renamed@example.com", true, "Octocat")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, created.ID, found.ID)
+}
+
+func TestFindOrCreateOAuthUser_SameIDOnDifferentProviderIsDistinct(t *testing.T) {
+	_, service := setupUserServiceTest(t)
+
+	google, _, err := service.FindOrCreateOAuthUser("google", "12345", "google@example.com", true, "Google User")
+	require.NoError(t, err)
+	assert.Equal(t, "12345", google.GoogleID)
+
+	github, isNew, err := service.FindOrCreateOAuthUser("github", "12345", "github@example.com", true, "GitHub User")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.NotEqual(t, google.ID, github.ID)
+}
+
+func TestFindOrCreateOAuthUser_MatchesLegacyGoogleID(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	legacy := dtos.User{Email: "legacy@example.com", Name: "Legacy", GoogleID: "g-1", OAuthProvider: "google", IsActive: true}
+	require.NoError(t, db.Create(&legacy).Error)
+
+	found, isNew, err := service.FindOrCreateOAuthUser("google", "g-1", "legacy@example.com", true, "Legacy")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, legacy.ID, found.ID)
+}
+
+func TestFindOrCreateOAuthUser_LinksPasswordUserByEmail(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	existing := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	linked, isNew, err := service.FindOrCreateOAuthUser("github", "42", "hybrid@example.com", true, "Hybrid")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, existing.ID, linked.ID)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.Equal(t, "github", reloaded.OAuthProvider)
+	assert.Equal(t, "42", reloaded.OAuthExternalID)
+}
+
+func TestFindOrCreateOAuthUser_LinksSecondProviderByEmail(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	existing, _, err := service.FindOrCreateOAuthUser("google", "g-1", "shared@example.com", true, "Shared")
+	require.NoError(t, err)
+
+	linked, isNew, err := service.FindOrCreateOAuthUser("github", "42", "shared@example.com", true, "Shared")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, existing.ID, linked.ID)
+
+	// The user row keeps the first provider; both resolve to the same user
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.Equal(t, "google", reloaded.OAuthProvider)
+	assert.Equal(t, "g-1", reloaded.GoogleID)
+	for provider, externalID := range map[string]string{"google": "g-1", "github": "42"} {
+		found, err := service.GetUserByOAuthIdentity(provider, externalID)
+		require.NoError(t, err, provider)
+		assert.Equal(t, existing.ID, found.ID, provider)
+	}
+
+	// A second account at a provider already linked is still refused
+	_, _, err = service.FindOrCreateOAuthUser("github", "43", "shared@example.com", true, "Shared")
+	assert.ErrorIs(t, err, ErrOAuthProviderConflict)
+}
+
+func TestUnlinkGoogleAccount_KeepsOtherProvider(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+	require.NoError(t, db.AutoMigrate(&authvo.GoogleIdentity{}, &authentities.AuthenticationSession{}))
+
+	existing, _, err := service.FindOrCreateOAuthUser("google", "g-1", "shared@example.com", true, "Shared")
+	require.NoError(t, err)
+	_, _, err = service.FindOrCreateOAuthUser("github", "42", "shared@example.com", true, "Shared")
+	require.NoError(t, err)
+
+	// GitHub is left to sign in with, so no password is needed
+	unlinked, _, err := service.UnlinkGoogleAccount(existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "github", unlinked.OAuthProvider)
+	assert.Equal(t, "42", unlinked.OAuthExternalID)
+	assert.Empty(t, unlinked.GoogleID)
+
+	_, err = service.GetUserByOAuthIdentity("google", "g-1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	found, err := service.GetUserByOAuthIdentity("github", "42")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, found.ID)
+}
+
+func TestFindOrCreateOAuthUser_RejectsEmailLinkedToDifferentGoogleID(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	existing, _, err := service.FindOrCreateOAuthUser("google", "g-1", "linked@example.com", true, "Linked")
+	require.NoError(t, err)
+
+	_, _, err = service.FindOrCreateOAuthUser("google", "g-2", "linked@example.com", true, "Linked")
+	assert.ErrorIs(t, err, ErrAccountAlreadyLinked)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.Equal(t, "g-1", reloaded.GoogleID)
+}
+
+func TestFindOrCreateOAuthUser_MatchesEmailCaseInsensitively(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	existing := dtos.User{Email: " Test@Gmail.com ", Name: "Test User", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+	assert.Equal(t, "test@gmail.com", existing.Email)
+
+	linked, isNew, err := service.FindOrCreateOAuthUser("google", "google_123", "TEST@GMAIL.COM", true, "Test User")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, existing.ID, linked.ID)
+	assert.Equal(t, "google_123", linked.GoogleID)
+
+	found, err := service.GetUserByEmail("  TEST@gmail.COM")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, found.ID)
+}
+
+func TestFindOrCreateOAuthUser_RejectsLinkingUnverifiedEmail(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+
+	existing := dtos.User{Email: "victim@example.com", Name: "Victim", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	_, _, err := service.FindOrCreateOAuthUser("google", "g-1", "victim@example.com", false, "Attacker")
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.False(t, reloaded.IsOAuthUser())
+}
+
+func TestFindOrCreateOAuthUser_RequiresConfirmationWhenAutoLinkDisabled(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+	service.SetOAuthAutoLink(false)
+
+	existing := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	_, _, err := service.FindOrCreateOAuthUser("google", "g-1", "Hybrid@Example.com", true, "Hybrid")
+	var confirmErr *LinkConfirmationRequiredError
+	require.ErrorAs(t, err, &confirmErr)
+	assert.ErrorIs(t, err, ErrLinkConfirmationRequired)
+	assert.Equal(t, existing.ID, confirmErr.Link.UserID)
+
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existing.ID).Error)
+	assert.False(t, reloaded.IsOAuthUser(), "nothing is linked until the account confirms")
+
+	// Only the account the link was recorded for can confirm it, and only for that provider
+	_, err = service.ConfirmPendingLink(existing.ID+1, "google", confirmErr.Link.Token)
+	assert.ErrorIs(t, err, ErrPendingLinkNotFound)
+	_, err = service.ConfirmPendingLink(existing.ID, "github", confirmErr.Link.Token)
+	assert.ErrorIs(t, err, ErrPendingLinkNotFound)
+
+	linked, err := service.ConfirmPendingLink(existing.ID, "google", confirmErr.Link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "g-1", linked.GoogleID)
+
+	// The token is spent, and later sign-ins find the linked account
+	_, err = service.ConfirmPendingLink(existing.ID, "google", confirmErr.Link.Token)
+	assert.ErrorIs(t, err, ErrPendingLinkNotFound)
+	found, isNew, err := service.FindOrCreateOAuthUser("google", "g-1", "hybrid@example.com", true, "Hybrid")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, existing.ID, found.ID)
+}
+
+func TestConfirmPendingLink_RejectsExpiredAndTakenLinks(t *testing.T) {
+	db, service := setupUserServiceTest(t)
+	service.SetOAuthAutoLink(false)
+
+	existing := dtos.User{Email: "hybrid@example.com", Name: "Hybrid", PasswordHash: "hash", IsActive: true}
+	require.NoError(t, db.Create(&existing).Error)
+
+	expired := authentities.NewPendingAccountLink(existing.ID, "github", "42", existing.Email)
+	require.NoError(t, db.Create(expired).Error)
+	require.NoError(t, db.Model(expired).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	_, err := service.ConfirmPendingLink(existing.ID, "github", expired.Token)
+	assert.ErrorIs(t, err, ErrPendingLinkNotFound)
+
+	// The Google account signed up on its own before the link was confirmed
+	taken := authentities.NewPendingAccountLink(existing.ID, "google", "g-43", existing.Email)
+	require.NoError(t, db.Create(taken).Error)
+	_, _, err = service.FindOrCreateOAuthUser("google", "g-43", "other@example.com", true, "Other")
+	require.NoError(t, err)
+	_, err = service.ConfirmPendingLink(existing.ID, "google", taken.Token)
+	assert.ErrorIs(t, err, ErrOAuthIdentityInUse)
+}
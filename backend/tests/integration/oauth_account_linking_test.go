@@ -1,10 +1,10 @@
 package integration
 
 import (
-	"context"
 	"testing"
 	"time"
 
+	"domain/auth/entities"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -18,14 +18,14 @@ func TestOAuthAccountLinking(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&dtos.User{})
 	require.NoError(t, err)
 
 	userService := user.NewUserService(db)
 
 	t.Run("links Google account to existing user by email", func(t *testing.T) {
 		// Create existing user with password auth
-		existingUser := &models.User{
+		existingUser := &dtos.User{
 			Email:        "existing@gmail.com",
 			Name:         "Existing User",
 			PasswordHash: "hashed_password",
@@ -40,8 +40,7 @@ func TestOAuthAccountLinking(t *testing.T) {
 		assert.False(t, existingUser.IsOAuthUser())
 
 		// Simulate OAuth callback with same email
-		ctx := context.Background()
-		linkedUser, isNew, err := userService.FindOrCreateOAuthUser(ctx, "existing@gmail.com", "Existing User", "google_id_123")
+		linkedUser, isNew, err := userService.FindOrCreateOAuthUser("google", "google_id_123", "existing@gmail.com", true, "Existing User")
 		require.NoError(t, err)
 		assert.False(t, isNew, "Should link to existing account, not create new")
 
@@ -60,7 +59,7 @@ func TestOAuthAccountLinking(t *testing.T) {
 
 	t.Run("preserves existing user data when linking", func(t *testing.T) {
 		// Create user with some data
-		existingUser := &models.User{
+		existingUser := &dtos.User{
 			Email:        "preserve@gmail.com",
 			Name:         "Original Name",
 			PasswordHash: "original_password",
@@ -72,8 +71,7 @@ func TestOAuthAccountLinking(t *testing.T) {
 		originalID := existingUser.ID
 
 		// Link OAuth account
-		ctx := context.Background()
-		linkedUser, _, err := userService.FindOrCreateOAuthUser(ctx, "preserve@gmail.com", "OAuth Name", "google_preserve_123")
+		linkedUser, _, err := userService.FindOrCreateOAuthUser("google", "google_preserve_123", "preserve@gmail.com", true, "OAuth Name")
 		require.NoError(t, err)
 
 		// Verify data preservation
@@ -88,8 +86,7 @@ func TestOAuthAccountLinking(t *testing.T) {
 	})
 
 	t.Run("creates new user if email not found", func(t *testing.T) {
-		ctx := context.Background()
-		newUser, isNew, err := userService.FindOrCreateOAuthUser(ctx, "newuser@gmail.com", "New User", "google_new_123")
+		newUser, isNew, err := userService.FindOrCreateOAuthUser("google", "google_new_123", "newuser@gmail.com", true, "New User")
 		require.NoError(t, err)
 		assert.True(t, isNew, "Should create new user")
 
@@ -102,7 +99,7 @@ func TestOAuthAccountLinking(t *testing.T) {
 
 	t.Run("handles account already linked to different Google ID", func(t *testing.T) {
 		// Create user already linked to Google
-		existingUser := &models.User{
+		existingUser := &dtos.User{
 			Email:         "linked@gmail.com",
 			Name:          "Linked User",
 			GoogleID:      "original_google_id",
@@ -116,23 +113,38 @@ func TestOAuthAccountLinking(t *testing.T) {
 
 		// Attempt to link with different Google ID (should not happen in practice)
 		// This tests data integrity
-		ctx := context.Background()
-		user, isNew, err := userService.FindOrCreateOAuthUser(ctx, "linked@gmail.com", "Linked User", "different_google_id")
+		found, isNew, err := userService.FindOrCreateOAuthUser("google", "different_google_id", "linked@gmail.com", true, "Linked User")
 
 		// Expected behavior: Either error or keep original Google ID
 		if err == nil {
 			// If no error, should preserve original Google ID
 			assert.False(t, isNew)
-			assert.Equal(t, "original_google_id", user.GoogleID, "Should not overwrite existing Google ID")
+			assert.Equal(t, "original_google_id", found.GoogleID, "Should not overwrite existing Google ID")
 		} else {
 			// Error is acceptable for this edge case
 			assert.Error(t, err)
 		}
 	})
 
+	t.Run("never links an email the provider has not verified", func(t *testing.T) {
+		existingUser := &dtos.User{
+			Email:        "unverified@gmail.com",
+			Name:         "Unverified User",
+			PasswordHash: "hashed_password",
+			IsActive:     true,
+		}
+		require.NoError(t, db.Create(existingUser).Error)
+
+		_, _, err := userService.FindOrCreateOAuthUser("google", "google_unverified", "unverified@gmail.com", false, "Unverified User")
+		assert.ErrorIs(t, err, user.ErrEmailNotVerified)
+
+		var reloaded dtos.User
+		require.NoError(t, db.First(&reloaded, existingUser.ID).Error)
+		assert.Empty(t, reloaded.GoogleID, "Unverified email must not be linked")
+	})
+
 	t.Run("validates email format during linking", func(t *testing.T) {
-		ctx := context.Background()
-		_, _, err := userService.FindOrCreateOAuthUser(ctx, "invalid-email", "Test User", "google_123")
+		_, _, err := userService.FindOrCreateOAuthUser("google", "google_123", "invalid-email", true, "Test User")
 
 		// Should handle invalid email appropriately
 		// Either return error or sanitize the email
@@ -147,34 +159,33 @@ func TestAccountLinkingEdgeCases(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&dtos.User{})
 	require.NoError(t, err)
 
 	t.Run("case-insensitive email matching", func(t *testing.T) {
 		// Create user with lowercase email
-		user := &models.User{
+		existing := &dtos.User{
 			Email:        "test@gmail.com",
 			Name:         "Test User",
 			PasswordHash: "password",
 			IsActive:     true,
 		}
-		err := db.Create(user).Error
+		err := db.Create(existing).Error
 		require.NoError(t, err)
 
 		// Attempt linking with uppercase email
-		userService := user2.NewUserService(db)
-		ctx := context.Background()
-		linkedUser, isNew, err := userService.FindOrCreateOAuthUser(ctx, "TEST@GMAIL.COM", "Test User", "google_123")
+		userService := user.NewUserService(db)
+		linkedUser, isNew, err := userService.FindOrCreateOAuthUser("google", "google_123", "TEST@GMAIL.COM", true, "Test User")
 
 		// Should find existing user (case-insensitive)
 		require.NoError(t, err)
 		assert.False(t, isNew, "Should link to existing user regardless of case")
-		assert.Equal(t, user.ID, linkedUser.ID)
+		assert.Equal(t, existing.ID, linkedUser.ID)
 	})
 
 	t.Run("linking inactive account", func(t *testing.T) {
 		// Create inactive user
-		inactiveUser := &models.User{
+		inactiveUser := &dtos.User{
 			Email:        "inactive@gmail.com",
 			Name:         "Inactive User",
 			PasswordHash: "password",
@@ -184,9 +195,8 @@ func TestAccountLinkingEdgeCases(t *testing.T) {
 		require.NoError(t, err)
 
 		// Attempt OAuth linking
-		userService := user2.NewUserService(db)
-		ctx := context.Background()
-		_, _, err = userService.FindOrCreateOAuthUser(ctx, "inactive@gmail.com", "Inactive User", "google_inactive")
+		userService := user.NewUserService(db)
+		_, _, err = userService.FindOrCreateOAuthUser("google", "google_inactive", "inactive@gmail.com", true, "Inactive User")
 
 		// Should either reactivate or prevent linking
 		// Implementation choice: document expected behavior
@@ -194,26 +204,25 @@ func TestAccountLinkingEdgeCases(t *testing.T) {
 
 	t.Run("concurrent linking attempts", func(t *testing.T) {
 		// Create user
-		user := &models.User{
+		existing := &dtos.User{
 			Email:        "concurrent@gmail.com",
 			Name:         "Concurrent User",
 			PasswordHash: "password",
 			IsActive:     true,
 		}
-		err := db.Create(user).Error
+		err := db.Create(existing).Error
 		require.NoError(t, err)
 
 		// Simulate concurrent linking attempts
 		// Both should succeed without data corruption
-		userService := user2.NewUserService(db)
-		ctx := context.Background()
+		userService := user.NewUserService(db)
 
 		done := make(chan bool, 2)
 		errors := make(chan error, 2)
 
 		for i := 0; i < 2; i++ {
 			go func() {
-				_, _, err := userService.FindOrCreateOAuthUser(ctx, "concurrent@gmail.com", "Concurrent User", "google_concurrent")
+				_, _, err := userService.FindOrCreateOAuthUser("google", "google_concurrent", "concurrent@gmail.com", true, "Concurrent User")
 				if err != nil {
 					errors <- err
 				}
@@ -232,9 +241,67 @@ func TestAccountLinkingEdgeCases(t *testing.T) {
 		}
 
 		// Verify final state is consistent
-		var finalUser models.User
+		var finalUser dtos.User
 		err = db.Where("email = ?", "concurrent@gmail.com").First(&finalUser).Error
 		require.NoError(t, err)
 		assert.Equal(t, "google_concurrent", finalUser.GoogleID)
 	})
-}
\ No newline at end of file
+}
+
+// TestOAuthAccountLinkingRequiresConfirmation tests linking with OAUTH_AUTO_LINK=false,
+// where the existing account must confirm the link from a signed-in session
+func TestOAuthAccountLinkingRequiresConfirmation(t *testing.T) {
+	t.Setenv("OAUTH_AUTO_LINK", "false")
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&dtos.User{}, &entities.PendingAccountLink{})
+	require.NoError(t, err)
+
+	userService := user.NewUserService(db)
+
+	existingUser := &dtos.User{
+		Email:        "existing@gmail.com",
+		Name:         "Existing User",
+		PasswordHash: "hashed_password",
+		IsActive:     true,
+	}
+	require.NoError(t, db.Create(existingUser).Error)
+
+	// Simulate OAuth callback with same email
+	_, _, err = userService.FindOrCreateOAuthUser("google", "google_id_123", "existing@gmail.com", true, "Existing User")
+	var confirmErr *user.LinkConfirmationRequiredError
+	require.ErrorAs(t, err, &confirmErr)
+	assert.Equal(t, existingUser.ID, confirmErr.Link.UserID)
+
+	// Nothing is linked until the account confirms
+	var reloaded dtos.User
+	require.NoError(t, db.First(&reloaded, existingUser.ID).Error)
+	assert.Empty(t, reloaded.GoogleID)
+
+	t.Run("other accounts cannot confirm the link", func(t *testing.T) {
+		_, err := userService.ConfirmPendingLink(existingUser.ID+1, "google", confirmErr.Link.Token)
+		assert.ErrorIs(t, err, user.ErrPendingLinkNotFound)
+	})
+
+	t.Run("the existing account confirms the link", func(t *testing.T) {
+		linkedUser, err := userService.ConfirmPendingLink(existingUser.ID, "google", confirmErr.Link.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "google_id_123", linkedUser.GoogleID)
+		assert.Equal(t, "hashed_password", linkedUser.PasswordHash, "Password should be preserved")
+
+		// Later sign-ins reach the linked account without confirmation
+		found, isNew, err := userService.FindOrCreateOAuthUser("google", "google_id_123", "existing@gmail.com", true, "Existing User")
+		require.NoError(t, err)
+		assert.False(t, isNew)
+		assert.Equal(t, existingUser.ID, found.ID)
+	})
+
+	t.Run("new users are still created directly", func(t *testing.T) {
+		newUser, isNew, err := userService.FindOrCreateOAuthUser("google", "google_new_123", "newuser@gmail.com", true, "New User")
+		require.NoError(t, err)
+		assert.True(t, isNew)
+		assert.Equal(t, "google_new_123", newUser.GoogleID)
+	})
+}
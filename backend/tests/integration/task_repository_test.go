@@ -112,7 +112,7 @@ func TestGormTaskRepository_FindByUserID_ReturnsEntities(t *testing.T) {
 
 	// Retrieve tasks for user 1
 	userID := uservo.NewUserID(1)
-	userTasks, err := repo.FindByUserID(userID)
+	userTasks, err := repo.FindByUserID(userID, repositories.TaskSort{})
 	require.NoError(t, err)
 	assert.Len(t, userTasks, 2)
 
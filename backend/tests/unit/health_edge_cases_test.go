@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"domain/health/entities"
 	"todo-app/internal/services"
+	"todo-app/internal/version"
 )
 
 // TestHealthServiceDatabaseConnectionTimeout tests database connection timeout scenarios
@@ -146,7 +147,7 @@ func TestHealthServiceStartupConditions(t *testing.T) {
 		healthService := services.NewHealthService()
 		customVersion := "2.1.0-beta"
 
-		healthService.SetVersion(customVersion)
+		healthService.SetVersion(version.Info{Version: customVersion})
 
 		response, err := healthService.GetHealthStatus()
 		assert.NoError(t, err)
@@ -520,7 +521,7 @@ func TestHealthServiceConsistentBehavior(t *testing.T) {
 
 		// Change version
 		newVersion := "test-version-" + time.Now().Format("20060102150405")
-		healthService.SetVersion(newVersion)
+		healthService.SetVersion(version.Info{Version: newVersion})
 
 		// Get updated response
 		updatedResponse, err := healthService.GetHealthStatus()
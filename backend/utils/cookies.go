@@ -2,9 +2,6 @@ package utils
 
 import (
 	"net/http"
-	"os"
-	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,63 +15,54 @@ type CookieConfig struct {
 	Path     string
 }
 
-// GetDefaultCookieConfig returns the default cookie configuration from environment
-func GetDefaultCookieConfig() CookieConfig {
-	secure := os.Getenv("SESSION_COOKIE_SECURE") == "true"
-	httpOnly := os.Getenv("SESSION_COOKIE_HTTPONLY") != "false" // Default true
-	sameSite := os.Getenv("SESSION_COOKIE_SAMESITE")
-	if sameSite == "" {
-		sameSite = "Lax"
-	}
-
+// NewCookieConfig returns the configuration of the authentication cookies:
+// host-only, on every path, sent only over HTTPS when secure is set, with
+// sameSite (Strict, Lax or None) applied to the session cookie
+func NewCookieConfig(secure bool, sameSite string) CookieConfig {
 	return CookieConfig{
 		Secure:   secure,
-		HttpOnly: httpOnly,
+		HttpOnly: true,
 		SameSite: sameSite,
 		Domain:   "",
 		Path:     "/",
 	}
 }
 
-// SetSessionCookie sets a secure session cookie
-func SetSessionCookie(c *gin.Context, name, value string, maxAge int) {
-	config := GetDefaultCookieConfig()
-
-	sameSite := http.SameSiteLaxMode
+// SameSiteMode returns the SameSite mode of the session cookie: Strict, Lax
+// (the default) or None
+func (config CookieConfig) SameSiteMode() http.SameSite {
 	switch config.SameSite {
 	case "Strict":
-		sameSite = http.SameSiteStrictMode
+		return http.SameSiteStrictMode
 	case "None":
-		sameSite = http.SameSiteNoneMode
-	case "Lax":
-		sameSite = http.SameSiteLaxMode
+		return http.SameSiteNoneMode
 	}
+	return http.SameSiteLaxMode
+}
 
-	c.SetSameSite(sameSite)
+// SetSessionCookie sets the session cookie, or clears it when maxAge is
+// negative. It is always HttpOnly, and SameSite=Lax by default so other sites
+// cannot make cross-site subrequests or form posts with it;
+// SameSite=Strict also withholds it from links followed from other sites.
+func (config CookieConfig) SetSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(config.SameSiteMode())
 	c.SetCookie(
-		name,
-		value,
+		"session_token",
+		token,
 		maxAge,
 		config.Path,
 		config.Domain,
 		config.Secure,
-		config.HttpOnly,
+		true, // HttpOnly
 	)
 }
 
-// SetOAuthStateCookie sets a secure cookie for OAuth state
-func SetOAuthStateCookie(c *gin.Context, stateToken string) {
-	config := GetDefaultCookieConfig()
-
-	// OAuth state cookies are short-lived (5 minutes)
-	maxAge := 300
-
-	sameSite := http.SameSiteLaxMode
-	if config.SameSite == "Strict" {
-		sameSite = http.SameSiteStrictMode
-	}
-
-	c.SetSameSite(sameSite)
+// SetOAuthStateCookie sets the OAuth state cookie, or clears it when maxAge is
+// negative. It is always SameSite=Lax, never Strict: the provider's redirect
+// back to the callback is a cross-site navigation, and a Strict cookie would
+// not be sent with it, failing every sign-in with invalid_state.
+func (config CookieConfig) SetOAuthStateCookie(c *gin.Context, stateToken string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
 	c.SetCookie(
 		"oauth_state",
 		stateToken,
@@ -82,14 +70,12 @@ func SetOAuthStateCookie(c *gin.Context, stateToken string) {
 		config.Path,
 		config.Domain,
 		config.Secure,
-		config.HttpOnly,
+		true, // HttpOnly
 	)
 }
 
 // SetCSRFCookie sets a CSRF token cookie
-func SetCSRFCookie(c *gin.Context, csrfToken string) {
-	config := GetDefaultCookieConfig()
-
+func (config CookieConfig) SetCSRFCookie(c *gin.Context, csrfToken string) {
 	// CSRF cookies should not be HttpOnly so JavaScript can read them
 	// but should still be secure
 	sameSite := http.SameSiteStrictMode // CSRF tokens should use Strict
@@ -107,41 +93,19 @@ func SetCSRFCookie(c *gin.Context, csrfToken string) {
 }
 
 // ClearSessionCookie clears the session cookie
-func ClearSessionCookie(c *gin.Context) {
-	config := GetDefaultCookieConfig()
-
-	c.SetCookie(
-		"session_token",
-		"",
-		-1, // Expire immediately
-		config.Path,
-		config.Domain,
-		config.Secure,
-		config.HttpOnly,
-	)
+func (config CookieConfig) ClearSessionCookie(c *gin.Context) {
+	config.SetSessionCookie(c, "", -1)
 }
 
 // ClearOAuthStateCookie clears the OAuth state cookie
-func ClearOAuthStateCookie(c *gin.Context) {
-	config := GetDefaultCookieConfig()
-
-	c.SetCookie(
-		"oauth_state",
-		"",
-		-1,
-		config.Path,
-		config.Domain,
-		config.Secure,
-		config.HttpOnly,
-	)
+func (config CookieConfig) ClearOAuthStateCookie(c *gin.Context) {
+	config.SetOAuthStateCookie(c, "", -1)
 }
 
 // ClearAllAuthCookies clears all authentication-related cookies
-func ClearAllAuthCookies(c *gin.Context) {
-	ClearSessionCookie(c)
-	ClearOAuthStateCookie(c)
-
-	config := GetDefaultCookieConfig()
+func (config CookieConfig) ClearAllAuthCookies(c *gin.Context) {
+	config.ClearSessionCookie(c)
+	config.ClearOAuthStateCookie(c)
 
 	// Clear CSRF cookie
 	c.SetCookie(
@@ -155,36 +119,6 @@ func ClearAllAuthCookies(c *gin.Context) {
 	)
 }
 
-// GetSessionExpiry returns the session expiry duration from environment
-func GetSessionExpiry() time.Duration {
-	hoursStr := os.Getenv("JWT_EXPIRES_HOURS")
-	if hoursStr == "" {
-		return 24 * time.Hour // Default 24 hours
-	}
-
-	hours, err := strconv.Atoi(hoursStr)
-	if err != nil {
-		return 24 * time.Hour
-	}
-
-	return time.Duration(hours) * time.Hour
-}
-
-// GetOAuthStateExpiry returns the OAuth state expiry duration
-func GetOAuthStateExpiry() time.Duration {
-	minutesStr := os.Getenv("OAUTH_STATE_EXPIRES_MINUTES")
-	if minutesStr == "" {
-		return 5 * time.Minute // Default 5 minutes
-	}
-
-	minutes, err := strconv.Atoi(minutesStr)
-	if err != nil {
-		return 5 * time.Minute
-	}
-
-	return time.Duration(minutes) * time.Minute
-}
-
 // ValidateCookieDomain validates a cookie domain
 func ValidateCookieDomain(domain string) bool {
 	// Basic validation - in production, use more sophisticated validation
@@ -196,17 +130,3 @@ func ValidateCookieDomain(domain string) bool {
 	// but can start with dot for subdomains
 	return len(domain) > 0
 }
-
-// GetSecureCookieSettings returns secure cookie settings based on environment
-func GetSecureCookieSettings() map[string]interface{} {
-	config := GetDefaultCookieConfig()
-
-	return map[string]interface{}{
-		"secure":    config.Secure,
-		"http_only": config.HttpOnly,
-		"same_site": config.SameSite,
-		"domain":    config.Domain,
-		"path":      config.Path,
-		"max_age":   int(GetSessionExpiry().Seconds()),
-	}
-}
\ No newline at end of file
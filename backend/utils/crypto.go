@@ -7,7 +7,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"io"
-	"os"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // CryptoService handles encryption and decryption of sensitive data
@@ -15,16 +16,14 @@ type CryptoService struct {
 	key []byte
 }
 
-// NewCryptoService creates a new crypto service from environment
-func NewCryptoService() (*CryptoService, error) {
-	key := os.Getenv("JWT_SECRET")
-	if key == "" {
-		return nil, errors.New("JWT_SECRET environment variable is not set")
+// NewCryptoService creates a new crypto service keyed by secret
+func NewCryptoService(secret string) (*CryptoService, error) {
+	if secret == "" {
+		return nil, errors.New("encryption secret is not set")
 	}
 
-	// Use first 32 bytes of JWT_SECRET as encryption key
-	// In production, use a separate ENCRYPTION_KEY
-	keyBytes := []byte(key)
+	// Use first 32 bytes of the secret as encryption key
+	keyBytes := []byte(secret)
 	if len(keyBytes) < 32 {
 		// Pad key if too short
 		padded := make([]byte, 32)
@@ -111,18 +110,17 @@ func (s *CryptoService) DecryptToken(encryptedToken string) (string, error) {
 }
 
 // HashPassword hashes a password using bcrypt
-// Note: This is a placeholder - actual password hashing should use bcrypt library
 func HashPassword(password string) (string, error) {
-	// In production, use golang.org/x/crypto/bcrypt
-	// For now, this is a placeholder
-	return password, errors.New("password hashing not implemented - use bcrypt")
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
 }
 
 // VerifyPassword verifies a password against a hash
 func VerifyPassword(password, hash string) bool {
-	// In production, use bcrypt.CompareHashAndPassword
-	// For now, this is a placeholder
-	return false
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
 // GenerateSecureToken generates a cryptographically secure random token
@@ -144,18 +142,13 @@ func GenerateSecureRandomBytes(length int) ([]byte, error) {
 }
 
 // EncryptOAuthTokens encrypts OAuth access and refresh tokens
-func EncryptOAuthTokens(accessToken, refreshToken string) (string, string, error) {
-	crypto, err := NewCryptoService()
-	if err != nil {
-		return "", "", err
-	}
-
-	encryptedAccess, err := crypto.EncryptToken(accessToken)
+func (s *CryptoService) EncryptOAuthTokens(accessToken, refreshToken string) (string, string, error) {
+	encryptedAccess, err := s.EncryptToken(accessToken)
 	if err != nil {
 		return "", "", err
 	}
 
-	encryptedRefresh, err := crypto.EncryptToken(refreshToken)
+	encryptedRefresh, err := s.EncryptToken(refreshToken)
 	if err != nil {
 		return "", "", err
 	}
@@ -164,18 +157,13 @@ func EncryptOAuthTokens(accessToken, refreshToken string) (string, string, error
 }
 
 // DecryptOAuthTokens decrypts OAuth access and refresh tokens
-func DecryptOAuthTokens(encryptedAccess, encryptedRefresh string) (string, string, error) {
-	crypto, err := NewCryptoService()
-	if err != nil {
-		return "", "", err
-	}
-
-	accessToken, err := crypto.DecryptToken(encryptedAccess)
+func (s *CryptoService) DecryptOAuthTokens(encryptedAccess, encryptedRefresh string) (string, string, error) {
+	accessToken, err := s.DecryptToken(encryptedAccess)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := crypto.DecryptToken(encryptedRefresh)
+	refreshToken, err := s.DecryptToken(encryptedRefresh)
 	if err != nil {
 		return "", "", err
 	}
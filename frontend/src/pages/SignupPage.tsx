@@ -24,6 +24,12 @@ const SignupPage: React.FC = () => {
             </div>
           )}
 
+          {error === 'account_already_linked' && (
+            <div className="error-message">
+              This email is already linked to a different Google account.
+            </div>
+          )}
+
           {error === 'authentication_failed' && (
             <div className="error-message">
               Authentication failed. Please try again.
@@ -25,6 +25,11 @@ export interface AuthError {
   details?: Record<string, unknown>;
 }
 
+// The server sets this cookie with each session and rejects state-changing
+// requests whose X-CSRF-Token header does not echo it
+const CSRF_COOKIE = 'csrf_token';
+const CSRF_HEADER = 'X-CSRF-Token';
+
 class AuthService {
   private baseUrl: string;
 
@@ -32,6 +37,33 @@ class AuthService {
     this.baseUrl = baseUrl;
   }
 
+  /**
+   * Read the CSRF token the server set for the current session
+   */
+  private getCSRFToken(): string | null {
+    for (const cookie of document.cookie.split(';')) {
+      const [name, ...value] = cookie.trim().split('=');
+      if (name === CSRF_COOKIE) {
+        return decodeURIComponent(value.join('='));
+      }
+    }
+    return null;
+  }
+
+  /**
+   * Headers for a state-changing request, echoing the CSRF token if there is one
+   */
+  private csrfHeaders(): Record<string, string> {
+    const headers: Record<string, string> = {
+      'Accept': 'application/json',
+    };
+    const token = this.getCSRFToken();
+    if (token) {
+      headers[CSRF_HEADER] = token;
+    }
+    return headers;
+  }
+
   /**
    * Initiate Google OAuth login flow
    */
@@ -87,9 +119,7 @@ class AuthService {
     const response = await fetch(`${this.baseUrl}/api/v1/auth/session/refresh`, {
       method: 'POST',
       credentials: 'include',
-      headers: {
-        'Accept': 'application/json',
-      },
+      headers: this.csrfHeaders(),
     });
 
     if (!response.ok) {
@@ -110,9 +140,7 @@ class AuthService {
     const response = await fetch(`${this.baseUrl}/api/v1/auth/logout`, {
       method: 'POST',
       credentials: 'include',
-      headers: {
-        'Accept': 'application/json',
-      },
+      headers: this.csrfHeaders(),
     });
 
     if (!response.ok) {
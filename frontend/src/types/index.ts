@@ -46,6 +46,8 @@ export interface UpdateTaskData {
 export interface TasksResponse {
   tasks: Task[];
   count: number;
+  /** All of the user's tasks outside the trash, regardless of filters */
+  total?: number;
 }
 
 /**